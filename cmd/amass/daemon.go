@@ -0,0 +1,114 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/datasrcs"
+	"github.com/OWASP/Amass/v3/enum"
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/fatih/color"
+)
+
+// runTrackDaemon enumerates the domains every interval and reports the changes found by each
+// enumeration, compared with the enumeration before it in the graph database, until ctx is cancelled.
+func runTrackDaemon(ctx context.Context, args *trackArgs, cfg *config.Config, il *format.IgnoreList, start time.Time, w *trackWriter) {
+	for {
+		began := time.Now()
+		fmt.Fprintf(color.Error, "%s%s\n", yellow("Starting the enumeration at "), yellow(began.Format(timeFormat)))
+
+		if id, err := trackEnumeration(ctx, args); err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+		} else if ctx.Err() == nil {
+			if err := trackChanges(args, cfg, il, start, id, w); err != nil {
+				r.Fprintf(color.Error, "%v\n", err)
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		next := began.Add(args.Interval)
+		fmt.Fprintf(color.Error, "%s%s\n", yellow("The next enumeration starts at "), yellow(next.Format(timeFormat)))
+
+		t := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// trackEnumeration performs an enumeration of the domains using the settings from the configuration
+// file, and stores the findings in the graph databases. The UUID of the enumeration is returned, and an
+// interrupted enumeration is still stored.
+func trackEnumeration(ctx context.Context, args *trackArgs) (string, error) {
+	cfg := config.NewConfig()
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err != nil && args.Filepaths.ConfigFile != "" {
+		return "", fmt.Errorf("Failed to load the configuration file: %v", err)
+	}
+	if args.Filepaths.Directory != "" {
+		cfg.Dir = args.Filepaths.Directory
+	}
+	cfg.AddDomains(args.Domains.Slice())
+	if cfg.Passive {
+		return "", fmt.Errorf("Passive enumerations are not stored in the graph database and cannot be tracked")
+	}
+	createOutputDirectory(cfg)
+
+	logfile, err := os.OpenFile(filepath.Join(config.OutputDirectory(cfg.Dir), "amass.log"),
+		os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return "", fmt.Errorf("Failed to open the log file: %v", err)
+	}
+	defer logfile.Close()
+	cfg.Log = log.New(logfile, "", log.Lmicroseconds)
+
+	sys, err := systems.NewLocalSystem(cfg)
+	if err != nil {
+		return "", err
+	}
+	defer sys.Shutdown()
+
+	sys.SetDataSources(datasrcs.GetAllSources(sys, true))
+	// Expand data source category names into the associated source names
+	cfg.SourceFilter.Sources = expandCategoryNames(cfg.SourceFilter.Sources, generateCategoryMap(sys))
+
+	e := enum.NewEnumeration(cfg, sys)
+	if e == nil {
+		return "", fmt.Errorf("Failed to setup the enumeration")
+	}
+	defer e.Close()
+
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-ctx.Done():
+			e.Done()
+		case <-finished:
+		}
+	}()
+
+	if err := e.Start(); err != nil {
+		return "", err
+	}
+
+	for _, g := range sys.GraphDatabases() {
+		if err := e.Graph.MigrateEventsWithContext(context.Background(), g, cfg.UUID.String()); err != nil {
+			return "", fmt.Errorf("The database migration to %s failed: %v", g.String(), err)
+		}
+	}
+	return cfg.UUID.String(), nil
+}
@@ -4,20 +4,34 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/format"
 	"github.com/OWASP/Amass/v3/graph"
+	"github.com/OWASP/Amass/v3/graphql"
+	amasshttp "github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/OWASP/Amass/v3/stringfilter"
 	"github.com/OWASP/Amass/v3/stringset"
+	"github.com/OWASP/Amass/v3/viz"
 	"github.com/fatih/color"
+	"github.com/google/uuid"
 )
 
 const (
@@ -25,27 +39,69 @@ const (
 )
 
 type dbArgs struct {
-	Domains stringset.Set
-	Enum    int
-	Options struct {
+	Domains    stringset.Set
+	Dump       string
+	Enum       int
+	Enum1      int
+	Enum2      int
+	Exclude    stringset.Set
+	Filter     format.ParseRegexps
+	Match      format.ParseRegexps
+	MaxAge     int
+	MaxEnums   int
+	Merge      string
+	Ownership  string
+	Query      string
+	Reputation stringset.Set
+	Serve      string
+	Since      string
+	Source     stringset.Set
+	Suppress   stringset.Set
+	Token      string
+	Unsuppress stringset.Set
+	Until      string
+	query      *format.Query
+	since      time.Time
+	until      time.Time
+	Options    struct {
+		Addrs            bool
+		Alive            bool
+		Compact          bool
+		Delete           bool
 		DemoMode         bool
+		Diff             bool
+		DNSProviders     bool
+		Emails           bool
 		IPs              bool
 		IPv4             bool
 		IPv6             bool
 		ListEnumerations bool
+		EventStats       bool
+		Force            bool
 		ASNTableSummary  bool
 		DiscoveredNames  bool
 		NoColor          bool
+		Prune            bool
+		Records          bool
 		ShowAll          bool
 		Silent           bool
 		Sources          bool
+		Stream           bool
+		Verify           bool
 	}
 	Filepaths struct {
-		ConfigFile string
-		Directory  string
-		Domains    string
-		JSONOutput string
-		TermOut    string
+		ConfigFile  string
+		CSVOutput   string
+		Cypher      string
+		Directory   string
+		Domains     string
+		GraphML     string
+		ImportInfra string
+		JSONOutput  string
+		Restore     string
+		SummaryCSV  string
+		SummaryJSON string
+		TermOut     string
 	}
 }
 
@@ -58,26 +114,70 @@ func runDBCommand(clArgs []string) {
 	dbCommand.SetOutput(dbBuf)
 
 	args.Domains = stringset.New()
+	args.Exclude = stringset.New()
+	args.Reputation = stringset.New()
+	args.Source = stringset.New()
+	args.Suppress = stringset.New()
+	args.Unsuppress = stringset.New()
 
 	dbCommand.BoolVar(&help1, "h", false, "Show the program usage message")
 	dbCommand.BoolVar(&help2, "help", false, "Show the program usage message")
 	dbCommand.Var(&args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	dbCommand.StringVar(&args.Dump, "dump", "", "Write every node and edge of the database to stdout in the format (ndjson)")
 	dbCommand.IntVar(&args.Enum, "enum", 0, "Identify an enumeration via an index from the listing")
+	dbCommand.IntVar(&args.Enum1, "enum1", 0, "Index from the listing of the first enumeration compared by -diff")
+	dbCommand.IntVar(&args.Enum2, "enum2", 0, "Index from the listing of the second enumeration compared by -diff")
+	dbCommand.IntVar(&args.MaxAge, "max-age", 0, "Number of days that enumerations are kept when pruning")
+	dbCommand.IntVar(&args.MaxEnums, "max-enums", 0, "Number of the most recent enumerations kept for each domain when pruning")
+	dbCommand.Var(&args.Suppress, "suppress", "Names hidden from the reports until the -until date (can be used multiple times)")
+	dbCommand.Var(&args.Unsuppress, "unsuppress", "Names returned to the reports before the suppression expires (can be used multiple times)")
+	dbCommand.StringVar(&args.Since, "since", "", "Print only names first observed by enumerations starting on or after the date (e.g. 2025-01-01)")
+	dbCommand.StringVar(&args.Until, "until", "", "Date that the suppressed names reappear, or the last date of the -since window (e.g. 2025-01-07)")
+	dbCommand.StringVar(&args.Merge, "merge", "", "Indices from the listing of the enumerations merged into a single enumeration (e.g. 1,2,3)")
+	dbCommand.StringVar(&args.Ownership, "ownership", "", "Print only names within root domains of the ownership status (verified or unverified)")
+	dbCommand.StringVar(&args.Token, "token", "", "Ownership verification token published by the root domains for -verify")
+	dbCommand.StringVar(&args.Query, "query", "", "Print only the findings satisfying the expression (e.g. 'name ~ \"^vpn\" AND asn = 13335')")
+	dbCommand.Var(&args.Reputation, "reputation", "Print only names with addresses classified as one of the reputations (e.g. malicious,suspicious)")
+	dbCommand.Var(&args.Match, "match", "Print only names matching the regular expression (can be used multiple times)")
+	dbCommand.Var(&args.Filter, "filter", "Print only names not matching the regular expression (can be used multiple times)")
+	dbCommand.Var(&args.Source, "source", "Print only names discovered by the data sources (e.g. crtsh,certspotter)")
+	dbCommand.Var(&args.Exclude, "exclude-source", "Print only names discovered by data sources other than these (e.g. \"Brute Forcing\")")
+	dbCommand.StringVar(&args.Serve, "serve", "", "Serve GraphQL queries for the findings at the address (e.g. localhost:8080)")
+	dbCommand.BoolVar(&args.Options.Addrs, "addrs", false, "Print just the unique IP addresses of the discovered names, one per line (select with -ipv4 or -ipv6)")
+	dbCommand.BoolVar(&args.Options.Alive, "alive", false, "Print only names with addresses that responded to the most recent liveness probes")
+	dbCommand.BoolVar(&args.Options.Compact, "compact", false, "Rewrite the database to reclaim the space of removed findings and rebuild the indexes")
+	dbCommand.BoolVar(&args.Options.Delete, "delete", false, "Remove the -enum enumeration and the findings no other enumeration references")
 	dbCommand.BoolVar(&args.Options.DemoMode, "demo", false, "Censor output to make it suitable for demonstrations")
+	dbCommand.BoolVar(&args.Options.Diff, "diff", false, "Print the changes between the -enum1 and -enum2 enumerations (the two most recent by default)")
+	dbCommand.BoolVar(&args.Options.DNSProviders, "dns-providers", false, "Print the DNS providers and software of the name servers for each zone")
+	dbCommand.BoolVar(&args.Options.Emails, "emails", false, "Print the email addresses discovered for the domains")
 	dbCommand.BoolVar(&args.Options.IPs, "ip", false, "Show the IP addresses for discovered names")
 	dbCommand.BoolVar(&args.Options.IPv4, "ipv4", false, "Show the IPv4 addresses for discovered names")
 	dbCommand.BoolVar(&args.Options.IPv6, "ipv6", false, "Show the IPv6 addresses for discovered names")
 	dbCommand.BoolVar(&args.Options.ListEnumerations, "list", false, "Numbered list of enums filtered on provided domains")
+	dbCommand.BoolVar(&args.Options.Force, "force", false, "Delete the enumeration without asking for confirmation")
+	dbCommand.BoolVar(&args.Options.EventStats, "stats", false, "Include the finding counts and duration of each enum in the listing")
 	dbCommand.BoolVar(&args.Options.Sources, "src", false, "Print data sources for the discovered names")
 	dbCommand.BoolVar(&args.Options.ASNTableSummary, "summary", false, "Print Just ASN Table Summary")
 	dbCommand.BoolVar(&args.Options.DiscoveredNames, "names", false, "Print Just Discovered Names")
 	dbCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	dbCommand.BoolVar(&args.Options.Prune, "prune", false, "Remove the enumerations outside of the retention policy")
+	dbCommand.BoolVar(&args.Options.Records, "records", false, "Print the CNAME chains and the MX, NS, SRV and PTR records stored for the discovered names")
 	dbCommand.BoolVar(&args.Options.ShowAll, "show", false, "Print the results for the enumeration index + domains provided")
 	dbCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	dbCommand.BoolVar(&args.Options.Verify, "verify", false, "Check the root domains for the -token as a DNS TXT record or well-known file")
+	dbCommand.BoolVar(&args.Options.Stream, "stream", false, "Write the output while reading the persistent graph, without the in-memory copy (use -json - for stdout)")
 	dbCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	dbCommand.StringVar(&args.Filepaths.CSVOutput, "csv", "", "Path to the CSV output file with a row for each address of the names (use - for stdout)")
+	dbCommand.StringVar(&args.Filepaths.Cypher, "cypher", "", "Path to the Neo4j Cypher script that creates the enumeration findings")
 	dbCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
 	dbCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
+	dbCommand.StringVar(&args.Filepaths.GraphML, "graphml", "", "Path to the GraphML file exporting the names, addresses, netblocks, ASNs and sources")
+	dbCommand.StringVar(&args.Filepaths.ImportInfra, "import-infra", "", "Path to a CSV file providing ground truth ASN, netblock and owner mappings")
 	dbCommand.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file")
+	dbCommand.StringVar(&args.Filepaths.SummaryCSV, "summary-csv", "", "Path to the CSV file with a row for each netblock of the ASN summary (use - for stdout)")
+	dbCommand.StringVar(&args.Filepaths.SummaryJSON, "summary-json", "", "Path to the JSON file providing the totals and ASN summary (use - for stdout)")
+	dbCommand.StringVar(&args.Filepaths.Restore, "restore", "", "Path to a database dump to be inserted into the database (use - for stdin)")
 	dbCommand.StringVar(&args.Filepaths.TermOut, "o", "", "Path to the text file containing terminal stdout/stderr")
 
 	if len(clArgs) < 1 {
@@ -102,6 +202,49 @@ func runDBCommand(clArgs []string) {
 		color.Error = ioutil.Discard
 	}
 
+	if args.Query != "" {
+		q, err := format.ParseQuery(args.Query)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to parse the query: %v\n", err)
+			os.Exit(1)
+		}
+		args.query = q
+	}
+
+	if args.Since != "" {
+		since, _, err := parseDateFlag("since", args.Since)
+		if err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+		args.since = since
+	}
+	// The -until flag provides the expiration of the suppressions when names are suppressed
+	if args.Until != "" && len(args.Suppress) == 0 {
+		until, dateOnly, err := parseDateFlag("until", args.Until)
+		if err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+		// A date includes the names observed during the whole day
+		if dateOnly {
+			until = until.Add(24 * time.Hour)
+		}
+		if !args.since.IsZero() && !until.After(args.since) {
+			r.Fprintln(color.Error, "The -until date must be later than the -since date")
+			os.Exit(1)
+		}
+		args.until = until
+	}
+
+	// The data source names are compared ignoring case
+	for _, set := range []stringset.Set{args.Source, args.Exclude} {
+		for _, src := range set.Slice() {
+			set.Remove(src)
+			set.Insert(strings.ToLower(src))
+		}
+	}
+
 	if args.Filepaths.Domains != "" {
 		list, err := config.GetListFromFile(args.Filepaths.Domains)
 		if err != nil {
@@ -125,6 +268,10 @@ func runDBCommand(clArgs []string) {
 		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
 		os.Exit(1)
 	}
+	// The files kept in the output directory, such as the ASN cache, follow the database
+	if args.Filepaths.Directory != "" {
+		cfg.Dir = args.Filepaths.Directory
+	}
 
 	db := openGraphDatabase(args.Filepaths.Directory, cfg)
 	if db == nil {
@@ -133,31 +280,167 @@ func runDBCommand(clArgs []string) {
 	}
 	defer db.Close()
 
-	// Create the in-memory graph database for events that have information in scope
-	memDB, err := memGraphForScope(args.Domains.Slice(), db)
-	if err != nil {
-		r.Fprintln(color.Error, err.Error())
+	if args.Options.Prune {
+		// The command-line options take precedence over the configuration file
+		if args.MaxAge > 0 {
+			cfg.RetentionDays = args.MaxAge
+		}
+		if args.MaxEnums > 0 {
+			cfg.RetentionEvents = args.MaxEnums
+		}
+
+		if err := pruneGraph(db, cfg); err != nil {
+			r.Fprintf(color.Error, "Failed to prune the database: %v\n", err)
+			os.Exit(1)
+		}
+		// The space of the pruned enumerations can be reclaimed right away
+		if !args.Options.Compact {
+			return
+		}
+	}
+
+	if args.Options.Compact {
+		if err := compactGraph(db); err != nil {
+			r.Fprintf(color.Error, "Failed to compact the database: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if args.Dump != "" {
+		if args.Dump != "ndjson" {
+			r.Fprintf(color.Error, "The %s dump format is not supported\n", args.Dump)
+			os.Exit(1)
+		}
+
+		if err := db.Dump(os.Stdout); err != nil {
+			r.Fprintf(color.Error, "Failed to dump the database: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if args.Filepaths.Restore != "" {
+		if err := restoreGraph(args.Filepaths.Restore, db); err != nil {
+			r.Fprintf(color.Error, "Failed to restore the database: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if args.Options.Verify {
+		if err := verifyOwnership(args.Token, args.Domains.Slice(), cfg, db); err != nil {
+			r.Fprintf(color.Error, "Failed to verify the domain ownership: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if args.Ownership != "" && args.Ownership != "verified" && args.Ownership != "unverified" {
+		r.Fprintf(color.Error, "The ownership status must be verified or unverified, not %s\n", args.Ownership)
 		os.Exit(1)
 	}
 
-	// Get all the UUIDs for events that have information in scope
-	uuids := memDB.EventList()
+	if len(args.Suppress) > 0 || len(args.Unsuppress) > 0 {
+		if err := updateSuppressions(&args, db); err != nil {
+			r.Fprintf(color.Error, "Failed to update the suppressions: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Streamed output reads the persistent db directly, instead of first copying the events in scope
+	var err error
+	memDB := db
+	uuids := eventUUIDs(args.Domains.Slice(), db)
+	if !args.Options.Stream {
+		// Create the in-memory graph database for events that have information in scope
+		memDB, err = memGraphForScope(args.Domains.Slice(), db)
+		if err != nil {
+			r.Fprintln(color.Error, err.Error())
+			os.Exit(1)
+		}
+
+		// Get all the UUIDs for events that have information in scope
+		uuids = memDB.EventList()
+	}
 	if len(uuids) == 0 {
 		r.Fprintln(color.Error, "Failed to find the domains of interest in the database")
 		os.Exit(1)
 	}
 
 	if args.Options.ListEnumerations {
-		listEvents(uuids, memDB)
+		var stats *graph.Graph
+		// The statistics are computed by the database holding the complete events
+		if args.Options.EventStats {
+			stats = db
+		}
+
+		listEvents(uuids, memDB, stats)
+		return
+	}
+
+	if args.Filepaths.ImportInfra != "" {
+		if err := importInfrastructure(args.Filepaths.ImportInfra, uuids, db); err != nil {
+			r.Fprintf(color.Error, "Failed to import the infrastructure: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if args.Serve != "" {
+		if err := serveGraphQL(args.Serve, memDB); err != nil {
+			r.Fprintf(color.Error, "Failed to serve the GraphQL endpoint: %v\n", err)
+			os.Exit(1)
+		}
 		return
 	}
 
-	if args.Options.ShowAll || args.Filepaths.JSONOutput != "" {
+	if args.Merge != "" {
+		if err := mergeEvents(args.Merge, uuids, memDB, db); err != nil {
+			r.Fprintf(color.Error, "Failed to merge the enumerations: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if args.Options.Delete {
+		if err := deleteEvent(args.Enum, args.Options.Force, uuids, memDB, db); err != nil {
+			r.Fprintf(color.Error, "Failed to delete the enumeration: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if args.Options.Diff {
+		if err := diffEvents(&args, uuids, suppressNames(nil, db), memDB); err != nil {
+			r.Fprintf(color.Error, "Failed to compare the enumerations: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if args.Options.ShowAll || args.Filepaths.JSONOutput != "" || args.Filepaths.CSVOutput != "" {
 		args.Options.DiscoveredNames = true
 		args.Options.ASNTableSummary = true
 	}
+	if args.Options.Addrs || args.Options.Records {
+		args.Options.DiscoveredNames = true
+	}
+	if err := checkSummaryPaths(args.Filepaths.SummaryCSV, args.Filepaths.SummaryJSON); err != nil {
+		r.Fprintln(color.Error, err.Error())
+		os.Exit(1)
+	}
+	summaryFiles := args.Filepaths.SummaryCSV != "" || args.Filepaths.SummaryJSON != ""
+	// The findings satisfying the query are listed when no other output was requested
+	if (args.query != nil || len(args.Match) > 0 || len(args.Filter) > 0 || args.Source.Len() > 0 ||
+		args.Exclude.Len() > 0 || !args.since.IsZero() || !args.until.IsZero()) && !args.Options.ASNTableSummary && !summaryFiles {
+		args.Options.DiscoveredNames = true
+	}
 
-	if !args.Options.DiscoveredNames && !args.Options.ASNTableSummary {
+	if !args.Options.DiscoveredNames && !args.Options.ASNTableSummary && !summaryFiles &&
+		!args.Options.DNSProviders && !args.Options.Emails &&
+		args.Filepaths.GraphML == "" && args.Filepaths.Cypher == "" {
 		commandUsage(dbUsageMsg, dbCommand, dbBuf)
 		return
 	}
@@ -176,20 +459,358 @@ func runDBCommand(clArgs []string) {
 		uuids = []string{uuids[idx]}
 	}
 
-	var asninfo bool
-	if args.Options.ASNTableSummary {
+	if args.Filepaths.GraphML != "" {
+		if err := writeGraphML(args.Filepaths.GraphML, uuids, memDB); err != nil {
+			r.Fprintf(color.Error, "Failed to write the GraphML file: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if args.Filepaths.Cypher != "" {
+		if err := writeCypher(args.Filepaths.Cypher, uuids, memDB); err != nil {
+			r.Fprintf(color.Error, "Failed to write the Cypher script: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if args.Options.DNSProviders {
+		showDNSProviders(uuids, args.Domains.Slice(), memDB)
+		return
+	}
+
+	if args.Options.Emails {
+		showEmails(uuids, args.Domains.Slice(), memDB)
+		return
+	}
+
+	// The netblocks and autonomous systems are only read from the graph when the query compares them
+	asninfo := args.query.UsesASNInfo()
+	if args.Options.ASNTableSummary || summaryFiles {
 		asninfo = true
 		fgY.Fprintln(color.Error, "Could take a moment while acquiring AS network information")
 		// Migrate the changes back to the persistent db
-		if healASInfo(uuids, memDB) {
-			memDB.MigrateEvents(db, uuids...)
+		if healASInfo(uuids, memDB, cfg) && memDB != db {
+			migrateWithProgress(func(ctx context.Context) error {
+				return memDB.MigrateEventsWithContext(ctx, db, uuids...)
+			})
+		}
+	}
+
+	var cm *format.ContextMap
+	if cfg.ContextFile != "" {
+		if cm, err = format.LoadContextFile(cfg.ContextFile); err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	showEventData(&args, uuids, asninfo, cm, suppressNames(nil, db), ownedDomains(db), memDB)
+}
+
+// importInfrastructure reads ASN, netblock and owner rows from the CSV file and adds them
+// to the events as ground truth. A header row and the owner column are optional.
+func importInfrastructure(path string, uuids []string, db *graph.Graph) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var count int
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if len(record) < 2 {
+			return fmt.Errorf("Line %d: Expected the ASN and netblock columns", line)
+		}
+
+		asn, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(record[0])), "AS"))
+		if err != nil {
+			// Skip the header row
+			if line == 1 {
+				continue
+			}
+			return fmt.Errorf("Line %d: Invalid ASN: %s", line, record[0])
+		}
+
+		var owner string
+		if len(record) > 2 {
+			owner = strings.TrimSpace(record[2])
+		}
+
+		if err := db.InsertGroundTruth(asn, strings.TrimSpace(record[1]), owner, uuids...); err != nil {
+			return fmt.Errorf("Line %d: %v", line, err)
 		}
+		count++
 	}
 
-	showEventData(&args, uuids, asninfo, memDB)
+	g.Fprintf(color.Error, "Imported %d netblocks into %d enumerations\n", count, len(uuids))
+	return nil
 }
 
-func listEvents(uuids []string, db *graph.Graph) {
+// restoreGraph inserts the nodes and edges from the dump file into the graph database.
+func restoreGraph(path string, db *graph.Graph) error {
+	var input io.Reader = os.Stdin
+
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		input = f
+	}
+
+	nodes, edges, err := db.Restore(input)
+	if err != nil {
+		return err
+	}
+
+	g.Fprintf(color.Error, "Restored %d nodes and %d edges into %s\n", nodes, edges, db.String())
+	return nil
+}
+
+// writeGraphML exports the findings of the events, with the data sources as nodes, to the GraphML file.
+func writeGraphML(path string, uuids []string, db *graph.Graph) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	c := &vizCounter{Writer: viz.NewGraphMLWriter(f)}
+	if err := db.StreamVizData(c, uuids...); err != nil {
+		return err
+	}
+	if c.nodes == 0 {
+		return fmt.Errorf("The enumerations did not provide any findings to export")
+	}
+
+	g.Fprintf(color.Error, "Exported %d nodes and %d edges to %s\n", c.nodes, c.edges, path)
+	return f.Sync()
+}
+
+// vizCounter keeps track of the number of nodes and edges streamed to the viz Writer.
+type vizCounter struct {
+	viz.Writer
+	nodes, edges int
+}
+
+func (c *vizCounter) WriteNode(n *viz.Node) error {
+	c.nodes++
+	return c.Writer.WriteNode(n)
+}
+
+func (c *vizCounter) WriteEdge(e *viz.Edge) error {
+	c.edges++
+	return c.Writer.WriteEdge(e)
+}
+
+// writeCypher exports the events to a Cypher script that can be imported into Neo4j using cypher-shell.
+func writeCypher(path string, uuids []string, db *graph.Graph) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := db.WriteCypher(f, uuids...); err != nil {
+		return err
+	}
+
+	g.Fprintf(color.Error, "Exported %d enumerations to %s\n", len(uuids), path)
+	return f.Sync()
+}
+
+// updateSuppressions hides the names from the reports until the date provided by the -until flag,
+// and returns the unsuppressed names to the reports.
+func updateSuppressions(args *dbArgs, db *graph.Graph) error {
+	for _, name := range args.Unsuppress.Slice() {
+		if err := db.DeleteSuppression(name); err != nil {
+			return err
+		}
+		g.Fprintf(color.Error, "%s is no longer suppressed\n", name)
+	}
+
+	if len(args.Suppress) == 0 {
+		return nil
+	}
+	if args.Until == "" {
+		return fmt.Errorf("The -until flag must provide the date that the suppression expires")
+	}
+
+	until, _, err := parseDateFlag("until", args.Until)
+	if err != nil {
+		return err
+	}
+	if !until.After(time.Now()) {
+		return fmt.Errorf("The -until date %s has already passed", args.Until)
+	}
+
+	for _, name := range args.Suppress.Slice() {
+		if err := db.InsertSuppression(name, until); err != nil {
+			return err
+		}
+		g.Fprintf(color.Error, "%s is suppressed until %s\n", name, until.Format(timeFormat))
+	}
+	return nil
+}
+
+// verifyOwnership checks whether the root domains publish the token in a DNS TXT record or serve it from
+// the well-known file, and records the verified domains in the graph database. A domain that no longer
+// provides the token loses the ownership verified previously.
+func verifyOwnership(token string, domains []string, cfg *config.Config, db *graph.Graph) error {
+	if token == "" {
+		return fmt.Errorf("The -token flag must provide the ownership verification token")
+	}
+	if len(domains) == 0 {
+		set := stringset.New()
+		for _, uuid := range db.EventList() {
+			set.InsertMany(db.EventDomains(uuid)...)
+		}
+		domains = set.Slice()
+	}
+	if len(domains) == 0 {
+		return fmt.Errorf("No root domains were provided or found in the database")
+	}
+	sort.Strings(domains)
+
+	sys, err := resolverSystem(cfg)
+	if err != nil {
+		return err
+	}
+	defer sys.Shutdown()
+
+	previous := stringset.New()
+	for _, o := range db.Ownerships() {
+		previous.Insert(o.Domain)
+	}
+
+	ctx := context.Background()
+	for _, domain := range domains {
+		var method string
+
+		if resolvers.OwnershipTokenPublished(ctx, sys.Pool(), domain, token) {
+			method = "dns"
+		} else if amasshttp.OwnershipTokenServed(ctx, domain, token) {
+			method = "http"
+		}
+
+		if method == "" {
+			if previous.Has(domain) {
+				if err := db.DeleteOwnership(domain); err != nil {
+					return err
+				}
+			}
+			r.Fprintf(color.Error, "%s: the ownership could not be verified\n", domain)
+			continue
+		}
+
+		if err := db.InsertOwnership(domain, method, time.Now()); err != nil {
+			return err
+		}
+		g.Fprintf(color.Error, "%s: the ownership was verified using %s\n", domain, method)
+	}
+	return nil
+}
+
+// ownedDomains returns the root domains with ownership verified in the graph database.
+func ownedDomains(db *graph.Graph) []string {
+	var domains []string
+
+	for _, o := range db.Ownerships() {
+		domains = append(domains, o.Domain)
+	}
+	return domains
+}
+
+// pruneGraph removes the enumerations outside of the retention policy from the configuration.
+func pruneGraph(db *graph.Graph, cfg *config.Config) error {
+	policy := &graph.RetentionPolicy{
+		MaxAge:    time.Duration(cfg.RetentionDays) * 24 * time.Hour,
+		MaxEvents: cfg.RetentionEvents,
+	}
+	if !policy.Enabled() {
+		return fmt.Errorf("The retention policy does not include a maximum age or number of enumerations")
+	}
+
+	removed, err := db.PruneEvents(policy, time.Now())
+	if err != nil {
+		return err
+	}
+
+	g.Fprintf(color.Error, "Removed %d enumerations from the %s database\n", len(removed), db.String())
+	return nil
+}
+
+// compactGraph rewrites the graph database without the data removed by earlier operations.
+func compactGraph(db *graph.Graph) error {
+	fgY.Fprintf(color.Error, "Compacting the %s database, which could take a while\n", db.String())
+
+	ctx, cancel := interruptContext(context.Background())
+	defer cancel()
+
+	stats, err := db.Compact(ctx)
+	if err != nil {
+		return err
+	}
+
+	if stats.Before > 0 && stats.After > 0 {
+		g.Fprintf(color.Error, "Compacted the %s database from %s to %s\n",
+			db.String(), byteSize(stats.Before), byteSize(stats.After))
+	} else {
+		g.Fprintf(color.Error, "Compacted the %s database\n", db.String())
+	}
+	return nil
+}
+
+func byteSize(n int64) string {
+	const unit = 1024
+
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func serveGraphQL(addr string, db *graph.Graph) error {
+	mux := http.NewServeMux()
+	mux.Handle("/graphql", graphql.NewHandler(db))
+
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+		// Clients that are slow to send the request or read the response do not hold connections open
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      2 * time.Minute,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	g.Fprintf(color.Error, "Serving GraphQL queries at http://%s/graphql\n", addr)
+	return srv.ListenAndServe()
+}
+
+func listEvents(uuids []string, db, stats *graph.Graph) {
 	events, earliest, latest := orderedEvents(uuids, db)
 	// Check if the user has requested the list of enumerations
 	for pos, idx := 0, len(events)-1; idx >= 0; idx-- {
@@ -205,17 +826,50 @@ func listEvents(uuids []string, db *graph.Graph) {
 			}
 			g.Print(domain)
 		}
+		// Make it clear that sampled enumerations do not contain the complete results
+		if size, err := db.EventMetadata(events[idx], "sample_size"); err == nil {
+			fgY.Printf(" (sample of %s names per data source)", size)
+		}
+		if _, err := db.EventMetadata(events[idx], "preflight"); err == nil {
+			fgY.Print(" (tuned by a preflight)")
+		}
 		g.Println()
+		if stats != nil {
+			printEventStats(events[idx], stats)
+		}
 		pos++
 	}
 }
 
-func showEventData(args *dbArgs, uuids []string, asninfo bool, db *graph.Graph) {
+func printEventStats(uuid string, db *graph.Graph) {
+	stats, err := db.EventStats(uuid)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		return
+	}
+
+	fmt.Fprintf(color.Output, "   %s%s, %s%s, %s%s, %s%s, %s%s, %s%s\n",
+		blue("Names: "), yellow(strconv.Itoa(stats.Names)),
+		blue("Addresses: "), yellow(strconv.Itoa(stats.Addresses)),
+		blue("Netblocks: "), yellow(strconv.Itoa(stats.Netblocks)),
+		blue("ASNs: "), yellow(strconv.Itoa(stats.ASNs)),
+		blue("Sources: "), yellow(strconv.Itoa(stats.Sources)),
+		blue("Duration: "), yellow(stats.Duration.String()))
+}
+
+func showEventData(args *dbArgs, uuids []string, asninfo bool, cm *format.ContextMap, il *format.IgnoreList, owned []string, db *graph.Graph) {
 	var total int
 	var err error
 	var outfile *os.File
 	var discovered []*requests.Output
-	domains := args.Domains.Slice()
+
+	if args.Options.Stream && args.Filepaths.JSONOutput != "" {
+		if err := streamJSON(args, uuids, asninfo, cm, il, owned, db); err != nil {
+			r.Fprintf(color.Error, "Failed to write the JSON output: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	if args.Filepaths.TermOut != "" {
 		outfile, err = os.OpenFile(args.Filepaths.TermOut, os.O_WRONLY|os.O_CREATE, 0644)
@@ -231,17 +885,51 @@ func showEventData(args *dbArgs, uuids []string, asninfo bool, db *graph.Graph)
 		outfile.Seek(0, 0)
 	}
 
+	var csvwriter *format.CSVWriter
+	if path := args.Filepaths.CSVOutput; path != "" {
+		var csvout io.Writer = os.Stdout
+
+		if path != "-" {
+			csvfile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+			if err != nil {
+				r.Fprintf(color.Error, "Failed to open the CSV output file: %v\n", err)
+				os.Exit(1)
+			}
+			defer func() {
+				csvfile.Sync()
+				csvfile.Close()
+			}()
+
+			csvout = csvfile
+		}
+
+		csvwriter, err = format.NewCSVWriter(csvout)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to write the CSV output: %v\n", err)
+			os.Exit(1)
+		}
+		defer csvwriter.Flush()
+	}
+
+	// The address list replaces the lines of the names in the terminal and text file output
+	var addrwriter *format.AddressWriter
+	if args.Options.Addrs {
+		if outfile != nil {
+			addrwriter = format.NewAddressWriter(outfile)
+		} else {
+			addrwriter = format.NewAddressWriter(color.Output)
+		}
+	}
+
 	tags := make(map[string]int)
 	asns := make(map[int]*format.ASNSummaryData)
-	for _, out := range getEventOutput(uuids, asninfo, db) {
-		if len(domains) > 0 && !domainNameInScope(out.Name, domains) {
-			continue
+	// The names are printed while the findings are read, so large events do not delay the output
+	_ = eachEventOutput(uuids, asninfo, db, func(out *requests.Output) error {
+		if !selectEventOutput(args, out, cm, il, owned, db) {
+			return nil
 		}
 
-		out.Addresses = format.DesiredAddrTypes(out.Addresses, args.Options.IPv4, args.Options.IPv6)
-		if l := len(out.Addresses); (args.Options.IPs || args.Options.IPv4 || args.Options.IPv6) && l == 0 {
-			continue
-		} else if l > 0 {
+		if l := len(out.Addresses); l > 0 {
 			total++
 			format.UpdateSummaryData(out, tags, asns)
 		}
@@ -251,30 +939,62 @@ func showEventData(args *dbArgs, uuids []string, asninfo bool, db *graph.Graph)
 		if ips != "" {
 			ips = " " + ips
 		}
+		if c := format.ContextLine(out.Context); c != "" {
+			ips += " " + c
+		}
 
 		if args.Options.DiscoveredNames {
 			var written bool
-			if outfile != nil {
+			if addrwriter != nil {
+				if err := addrwriter.Write(out); err != nil {
+					r.Fprintf(color.Error, "Failed to write the addresses: %v\n", err)
+					os.Exit(1)
+				}
+				written = true
+			} else if outfile != nil {
 				fmt.Fprintf(outfile, "%s%s%s\n", source, name, ips)
+				if args.Options.Records {
+					printRecords(outfile, out.Name, db, false)
+				}
 				written = true
 			}
 			if args.Filepaths.JSONOutput != "" {
 				discovered = append(discovered, out)
 				written = true
 			}
+			if csvwriter != nil {
+				if err := csvwriter.Write(out); err != nil {
+					r.Fprintf(color.Error, "Failed to write the CSV output: %v\n", err)
+					os.Exit(1)
+				}
+				written = true
+			}
 			if !written {
 				fmt.Fprintf(color.Output, "%s%s%s\n", blue(source), green(name), yellow(ips))
+				if args.Options.Records {
+					printRecords(color.Output, out.Name, db, true)
+				}
 			}
 		}
+		return nil
+	})
+
+	if err := writeSummaryFiles(args.Filepaths.SummaryCSV, args.Filepaths.SummaryJSON, total, tags, asns); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
 	}
+	// The text summary is not mixed with the machine-readable summary written to stdout
+	summaryStdout := args.Filepaths.SummaryCSV == "-" || args.Filepaths.SummaryJSON == "-"
 
 	if total == 0 {
-		r.Println("No names were discovered")
+		if addrwriter == nil && !summaryStdout {
+			r.Println("No names were discovered")
+		}
 		return
 	}
 	if args.Filepaths.JSONOutput != "" {
 		writeJSON(args, uuids, discovered, db)
-	} else if args.Options.ASNTableSummary {
+	} else if args.Options.ASNTableSummary && csvwriter == nil && addrwriter == nil && !summaryStdout {
 		var out io.Writer
 		status := color.NoColor
 
@@ -292,6 +1012,209 @@ func showEventData(args *dbArgs, uuids []string, asninfo bool, db *graph.Graph)
 	}
 }
 
+// printRecords writes the DNS records stored for the name below its line of the output.
+func printRecords(w io.Writer, name string, db *graph.Graph, colored bool) {
+	rtype, from, to := fmt.Sprint, fmt.Sprint, fmt.Sprint
+	if colored {
+		rtype, from, to = blue, green, yellow
+	}
+
+	for _, rec := range db.NameRecords(name) {
+		fmt.Fprintf(w, "    %s %s --> %s\n", rtype(fmt.Sprintf("%-5s", rec.Type)), from(rec.Name), to(rec.Target))
+	}
+}
+
+// parseDateFlag accepts a date, which is reported by dateOnly, or a time in the RFC 3339 format.
+func parseDateFlag(flag, value string) (t time.Time, dateOnly bool, err error) {
+	if t, err = time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	if t, err = time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	return t, false, fmt.Errorf("The -%s date must be in the form 2006-01-02 or RFC 3339: %s", flag, value)
+}
+
+// selectEventOutput annotates the finding and applies the scope, suppressions, name patterns, ownership, data source,
+// date, query, reputation and address options provided on the command-line. False is returned when the finding should not be included.
+func selectEventOutput(args *dbArgs, out *requests.Output, cm *format.ContextMap,
+	il *format.IgnoreList, owned []string, db *graph.Graph) bool {
+	if domains := args.Domains.Slice(); len(domains) > 0 && !domainNameInScope(out.Name, domains) {
+		return false
+	}
+	if il.Ignored(out) {
+		return false
+	}
+	if (len(args.Match) > 0 && !args.Match.AnyMatch(out.Name)) || args.Filter.AnyMatch(out.Name) {
+		return false
+	}
+	if !sourcesSelected(out.Sources, args.Source, args.Exclude) {
+		return false
+	}
+	if !args.since.IsZero() || !args.until.IsZero() {
+		first := db.NameFirstSeen(out.Name)
+
+		if first.IsZero() || first.Before(args.since) || (!args.until.IsZero() && !first.Before(args.until)) {
+			return false
+		}
+	}
+
+	out.Verified = len(owned) > 0 && domainNameInScope(out.Name, owned)
+	if (args.Ownership == "verified" && !out.Verified) || (args.Ownership == "unverified" && out.Verified) {
+		return false
+	}
+	for i := range out.Addresses {
+		out.Addresses[i].Reputation = db.AddressReputation(out.Addresses[i].Address.String())
+	}
+	if !args.query.Match(out) {
+		return false
+	}
+
+	cm.Annotate(out)
+	out.Addresses = format.DesiredAddrTypes(out.Addresses, args.Options.IPv4, args.Options.IPv6)
+	if args.Options.Alive {
+		if out.Addresses = aliveAddresses(out.Addresses, db); len(out.Addresses) == 0 {
+			return false
+		}
+	}
+	if args.Reputation.Len() > 0 {
+		if out.Addresses = reputationAddresses(out.Addresses, args.Reputation); len(out.Addresses) == 0 {
+			return false
+		}
+	}
+	if (args.Options.Addrs || args.Options.IPs || args.Options.IPv4 || args.Options.IPv6) && len(out.Addresses) == 0 {
+		return false
+	}
+	return true
+}
+
+func showDNSProviders(uuids, domains []string, db *graph.Graph) {
+	providers := make(map[string]map[string][]*graph.NameserverInfo)
+
+	for zone, servers := range db.ZoneNameservers(uuids...) {
+		if len(domains) > 0 && !domainNameInScope(zone, domains) {
+			continue
+		}
+
+		for _, ns := range servers {
+			provider := ns.Provider
+			if provider == "" {
+				provider = "Unknown"
+			}
+
+			if _, found := providers[provider]; !found {
+				providers[provider] = make(map[string][]*graph.NameserverInfo)
+			}
+			providers[provider][zone] = append(providers[provider][zone], ns)
+		}
+	}
+
+	if len(providers) == 0 {
+		r.Println("No name servers were discovered")
+		return
+	}
+
+	var names []string
+	for provider := range providers {
+		names = append(names, provider)
+	}
+	sort.Strings(names)
+
+	for _, provider := range names {
+		zones := providers[provider]
+
+		var zonenames []string
+		for zone := range zones {
+			zonenames = append(zonenames, zone)
+		}
+		sort.Strings(zonenames)
+
+		fmt.Fprintf(color.Output, "%s (%s)\n", blue(provider), yellow(fmt.Sprintf("%d zones", len(zonenames))))
+		for _, zone := range zonenames {
+			fmt.Fprintf(color.Output, "  %s\n", green(zone))
+
+			for _, ns := range zones[zone] {
+				var software string
+				// The version string disclosed by the server often includes the software name
+				if v := ns.Version; v != "" {
+					software = " " + v
+					if ns.Software != "" && !strings.Contains(strings.ToLower(v), strings.ToLower(ns.Software)) {
+						software = " " + ns.Software + " " + v
+					}
+				} else if ns.Software != "" {
+					software = " " + ns.Software
+				}
+				fmt.Fprintf(color.Output, "    %s%s\n", ns.Name, yellow(software))
+			}
+		}
+	}
+}
+
+func showEmails(uuids, domains []string, db *graph.Graph) {
+	var count int
+	var last string
+
+	for _, e := range db.EventEmails(uuids...) {
+		if len(domains) > 0 && !domainNameInScope(e.Domain, domains) {
+			continue
+		}
+
+		if e.Domain != last {
+			fmt.Fprintf(color.Output, "%s\n", green(e.Domain))
+			last = e.Domain
+		}
+		fmt.Fprintf(color.Output, "  %s %s\n", e.Address, blue(fmt.Sprintf("(%s)", strings.Join(e.Sources, ", "))))
+		count++
+	}
+
+	if count == 0 {
+		r.Println("No email addresses were discovered")
+	}
+}
+
+func aliveAddresses(addrs []requests.AddressInfo, db *graph.Graph) []requests.AddressInfo {
+	var alive []requests.AddressInfo
+
+	for _, addr := range addrs {
+		if yes, _ := db.AddressLiveness(addr.Address.String()); yes {
+			alive = append(alive, addr)
+		}
+	}
+	return alive
+}
+
+// reputationAddresses returns the addresses classified with one of the reputations by any provider.
+// sourcesSelected returns true when one of the data sources that discovered the name is in the include
+// set, or any set when it is empty, and is not in the exclude set. The names are compared ignoring case.
+func sourcesSelected(sources []string, include, exclude stringset.Set) bool {
+	if include.Len() == 0 && exclude.Len() == 0 {
+		return true
+	}
+
+	for _, src := range sources {
+		src = strings.ToLower(src)
+
+		if (include.Len() == 0 || include.Has(src)) && !exclude.Has(src) {
+			return true
+		}
+	}
+	return false
+}
+
+func reputationAddresses(addrs []requests.AddressInfo, reputations stringset.Set) []requests.AddressInfo {
+	var selected []requests.AddressInfo
+
+	for _, addr := range addrs {
+		for _, r := range addr.Reputation {
+			if reputations.Has(r.Classification) {
+				selected = append(selected, addr)
+				break
+			}
+		}
+	}
+	return selected
+}
+
 type jsonEvent struct {
 	UUID   string `json:"uuid"`
 	Start  string `json:"start"`
@@ -309,6 +1232,249 @@ type jsonOutput struct {
 	Domains []*jsonDomain `json:"domains"`
 }
 
+// mergeEvents consolidates the enumerations selected by the comma-separated indices from the listing
+// into a single enumeration with a new identifier.
+func mergeEvents(indices string, uuids []string, scoped, db *graph.Graph) error {
+	events, _, _ := orderedEvents(uuids, scoped)
+
+	selected := stringset.New()
+	for _, field := range strings.Split(indices, ",") {
+		idx, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || idx < 1 || idx > len(events) {
+			return fmt.Errorf("The enumeration indices must be between 1 and %d", len(events))
+		}
+		// The listing numbers the enumerations starting with the most recent
+		selected.Insert(events[len(events)-idx])
+	}
+	if selected.Len() < 2 {
+		return fmt.Errorf("At least two enumerations must be selected")
+	}
+
+	into := uuid.New().String()
+	if err := db.MergeEvents(into, selected.Slice()...); err != nil {
+		return err
+	}
+
+	g.Fprintf(color.Error, "Merged %d enumerations into %s\n", selected.Len(), into)
+	return nil
+}
+
+// deleteEvent removes the enumeration selected from the listing, along with the findings that no other
+// enumeration references, after the user confirms the deletion or when the force parameter is true.
+func deleteEvent(idx int, force bool, uuids []string, scoped, db *graph.Graph) error {
+	events, _, _ := orderedEvents(uuids, scoped)
+	if idx < 1 || idx > len(events) {
+		return fmt.Errorf("The -enum index must be between 1 and %d", len(events))
+	}
+	// The listing numbers the enumerations starting with the most recent
+	event := events[len(events)-idx]
+
+	if !force {
+		start, finish := scoped.EventDateRange(event)
+
+		fmt.Fprintf(color.Error, "Delete the enumeration of %s performed %s -> %s? %s ",
+			strings.Join(scoped.EventDomains(event), ", "), start.Format(timeFormat),
+			finish.Format(timeFormat), yellow("[y/N]"))
+
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			return fmt.Errorf("The deletion was not confirmed")
+		}
+	}
+
+	removed, err := db.RemoveEvent(event)
+	if err != nil {
+		return err
+	}
+
+	g.Fprintf(color.Error, "Removed the enumeration %s and %d findings from the %s database\n", event, removed, db.String())
+	return nil
+}
+
+// jsonEventDiff is the object written by -diff when JSON output is requested.
+type jsonEventDiff struct {
+	Older *jsonEvent `json:"older"`
+	Newer *jsonEvent `json:"newer"`
+	*format.OutputDiff
+}
+
+// diffEvents compares the enumerations selected from the listing by the -enum1 and -enum2 flags, and
+// prints or writes as JSON the names added and removed, the address changes and the new netblocks.
+// The older enumeration is always the baseline, regardless of the order the indices were provided.
+func diffEvents(args *dbArgs, uuids []string, il *format.IgnoreList, db *graph.Graph) error {
+	events, earliest, latest := orderedEvents(uuids, db)
+
+	first, second := args.Enum1, args.Enum2
+	if first == 0 && second == 0 {
+		first, second = 2, 1
+	}
+	if first < 1 || second < 1 || first > len(events) || second > len(events) {
+		return fmt.Errorf("The enumeration indices must be between 1 and %d", len(events))
+	}
+	if first == second {
+		return fmt.Errorf("Two different enumerations must be selected")
+	}
+
+	// The listing numbers the enumerations starting with the most recent
+	oidx, nidx := len(events)-first, len(events)-second
+	if oidx > nidx {
+		oidx, nidx = nidx, oidx
+	}
+
+	scoped := func(uuid string) []*requests.Output {
+		var output []*requests.Output
+
+		for _, out := range getEventOutput([]string{uuid}, true, db) {
+			if domains := args.Domains.Slice(); len(domains) > 0 && !domainNameInScope(out.Name, domains) {
+				continue
+			}
+			if !il.Ignored(out) {
+				output = append(output, out)
+			}
+		}
+		return output
+	}
+	diff := format.DiffOutput(scoped(events[oidx]), scoped(events[nidx]))
+
+	if path := args.Filepaths.JSONOutput; path != "" {
+		var output io.Writer = os.Stdout
+
+		if path != "-" {
+			f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				f.Sync()
+				f.Close()
+			}()
+
+			output = f
+		}
+
+		enc := json.NewEncoder(output)
+		enc.SetIndent("", "  ")
+		return enc.Encode(&jsonEventDiff{
+			Older: &jsonEvent{
+				UUID:   events[oidx],
+				Start:  earliest[oidx].UTC().Format(time.RFC3339),
+				Finish: latest[oidx].UTC().Format(time.RFC3339),
+			},
+			Newer: &jsonEvent{
+				UUID:   events[nidx],
+				Start:  earliest[nidx].UTC().Format(time.RFC3339),
+				Finish: latest[nidx].UTC().Format(time.RFC3339),
+			},
+			OutputDiff: diff,
+		})
+	}
+
+	blueLine()
+	fmt.Fprintf(color.Output, "%s\t%s%s%s\n%s\t%s%s%s\n", blue("Between"),
+		yellow(earliest[oidx].Format(timeFormat)), blue(" -> "), yellow(latest[oidx].Format(timeFormat)),
+		blue("and"), yellow(earliest[nidx].Format(timeFormat)), blue(" -> "), yellow(latest[nidx].Format(timeFormat)))
+	blueLine()
+
+	if diff.Empty() {
+		g.Println("No differences discovered")
+		return nil
+	}
+	for _, o := range diff.Added {
+		fmt.Fprintf(color.Output, "%s%s %s\n", blue("Found: "), green(o.Name), yellow(lineOfAddresses(o.Addresses)))
+	}
+	for _, o := range diff.Removed {
+		fmt.Fprintf(color.Output, "%s%s %s\n", blue("Removed: "), green(o.Name), yellow(lineOfAddresses(o.Addresses)))
+	}
+	for _, c := range diff.Changed {
+		fmt.Fprintf(color.Output, "%s%s\n\t%s\t%s\n\t%s\t%s\n", blue("Moved: "), green(c.Name),
+			blue(" from "), yellow(strings.Join(c.Older, ",")), blue(" to "), yellow(strings.Join(c.Newer, ",")))
+	}
+	for _, cidr := range diff.Netblocks {
+		fmt.Fprintf(color.Output, "%s%s\n", blue("New netblock: "), yellow(cidr))
+	}
+	return nil
+}
+
+// jsonAsset is the object written for each discovered name when the JSON output is streamed.
+type jsonAsset struct {
+	*requests.Output
+	ASNs   []int  `json:"asns"`
+	Event  string `json:"event"`
+	Start  string `json:"start"`
+	Finish string `json:"finish"`
+}
+
+// streamJSON writes the findings as newline-delimited JSON while the events are read from the graph,
+// starting with the most recent enumeration. Each name is written once, along with the dates of the
+// most recent enumeration that discovered it in RFC 3339 format.
+func streamJSON(args *dbArgs, uuids []string, asninfo bool, cm *format.ContextMap,
+	il *format.IgnoreList, owned []string, db *graph.Graph) error {
+	var output io.Writer = os.Stdout
+
+	if path := args.Filepaths.JSONOutput; path != "-" {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			f.Sync()
+			f.Close()
+		}()
+
+		output = f
+	}
+
+	var total int
+	bufwr := bufio.NewWriter(output)
+	enc := json.NewEncoder(bufwr)
+	cache := eventOutputCache(uuids, db)
+	filter := stringfilter.NewStringFilter()
+	for i := len(uuids) - 1; i >= 0; i-- {
+		uuid := uuids[i]
+		earliest, latest := db.EventDateRange(uuid)
+
+		err := db.StreamEventOutput(context.Background(), uuid, filter, asninfo, cache, func(out *requests.Output) error {
+			if !selectEventOutput(args, out, cm, il, owned, db) {
+				return nil
+			}
+
+			asset := &jsonAsset{
+				Output: out,
+				ASNs:   []int{},
+				Event:  uuid,
+				Start:  earliest.UTC().Format(time.RFC3339),
+				Finish: latest.UTC().Format(time.RFC3339),
+			}
+
+			asns := make(map[int]struct{})
+			for _, addr := range out.Addresses {
+				if _, found := asns[addr.ASN]; !found && addr.ASN != 0 {
+					asns[addr.ASN] = struct{}{}
+					asset.ASNs = append(asset.ASNs, addr.ASN)
+				}
+			}
+
+			if err := enc.Encode(asset); err != nil {
+				return err
+			}
+			total++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// Provide the findings of each enumeration to the reader without delay
+		if err := bufwr.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if total == 0 {
+		r.Fprintln(color.Error, "No names were discovered")
+	}
+	return nil
+}
+
 func writeJSON(args *dbArgs, uuids []string, assets []*requests.Output, db *graph.Graph) {
 	var output jsonOutput
 
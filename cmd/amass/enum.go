@@ -6,7 +6,8 @@ package main
 import (
 	"bufio"
 	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -18,6 +19,8 @@ import (
 	"os/signal"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -27,11 +30,14 @@ import (
 	"github.com/OWASP/Amass/v3/datasrcs"
 	"github.com/OWASP/Amass/v3/enum"
 	"github.com/OWASP/Amass/v3/format"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/notify"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringfilter"
 	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/fatih/color"
+	"github.com/google/uuid"
 )
 
 const enumUsageMsg = "enum [options] -d DOMAIN"
@@ -42,36 +48,54 @@ type enumArgs struct {
 	CIDRs             format.ParseCIDRs
 	AltWordList       stringset.Set
 	AltWordListMask   stringset.Set
+	AuthoritativeRate int
 	BruteWordList     stringset.Set
 	BruteWordListMask stringset.Set
 	Blacklist         stringset.Set
+	BlacklistRegexps  format.ParseTerms
 	Domains           stringset.Set
+	Categories        stringset.Set
+	ExcludedCats      stringset.Set
 	Excluded          stringset.Set
 	Included          stringset.Set
 	Interface         string
+	MaxDepth          int
 	MaxDNSQueries     int
+	MinForDepth       format.ParseStrings
 	MinForRecursive   int
 	Names             stringset.Set
 	Ports             format.ParseInts
 	Resolvers         stringset.Set
+	Resume            string
+	SampleSize        int
 	Timeout           int
 	Options           struct {
 		Active              bool
+		Addrs               bool
+		Authoritative       bool
 		BruteForcing        bool
 		DemoMode            bool
+		EgressReport        bool
 		IPs                 bool
 		IPv4                bool
 		IPv6                bool
 		ListSources         bool
+		Liveness            bool
+		Monitor             bool
 		MonitorResolverRate bool
+		NDJSON              bool
 		NoAlts              bool
 		NoColor             bool
 		NoLocalDatabase     bool
 		NoRecursive         bool
 		Passive             bool
+		Preflight           bool
+		Progress            bool
 		Silent              bool
 		Sources             bool
+		SourceStats         bool
 		Verbose             bool
+		VHosts              bool
 	}
 	Filepaths struct {
 		AllFilePrefix    string
@@ -81,13 +105,17 @@ type enumArgs struct {
 		ConfigFile       string
 		Directory        string
 		Domains          format.ParseStrings
+		EgressAllow      string
 		ExcludedSrcs     string
+		Ignore           string
 		IncludedSrcs     string
 		JSONOutput       string
 		LogFile          string
 		Names            format.ParseStrings
 		Resolvers        format.ParseStrings
 		ScriptsDirectory string
+		SummaryCSV       string
+		SummaryJSON      string
 		TermOut          string
 	}
 }
@@ -96,37 +124,55 @@ func defineEnumArgumentFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	enumFlags.Var(&args.Addresses, "addr", "IPs and ranges (192.168.1.1-254) separated by commas")
 	enumFlags.Var(&args.AltWordListMask, "awm", "\"hashcat-style\" wordlist masks for name alterations")
 	enumFlags.Var(&args.ASNs, "asn", "ASNs separated by commas (can be used multiple times)")
+	enumFlags.IntVar(&args.AuthoritativeRate, "auth-rate", 0, "Maximum queries per second sent to each authoritative server")
 	enumFlags.Var(&args.CIDRs, "cidr", "CIDRs separated by commas (can be used multiple times)")
 	enumFlags.Var(&args.Blacklist, "bl", "Blacklist of subdomain names that will not be investigated")
+	enumFlags.Var(&args.BlacklistRegexps, "blr", "Regular expression matching entire names that will not be resolved or stored (can be used multiple times)")
 	enumFlags.Var(&args.BruteWordListMask, "wm", "\"hashcat-style\" wordlist masks for DNS brute forcing")
 	enumFlags.Var(&args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	enumFlags.Var(&args.Categories, "categories", "Data source categories separated by commas to be exclusively used")
+	enumFlags.Var(&args.ExcludedCats, "exclude-categories", "Data source categories separated by commas to be excluded")
 	enumFlags.Var(&args.Excluded, "exclude", "Data source names separated by commas to be excluded")
 	enumFlags.Var(&args.Included, "include", "Data source names separated by commas to be included")
 	enumFlags.StringVar(&args.Interface, "iface", "", "Provide the network interface to send traffic through")
+	enumFlags.IntVar(&args.MaxDepth, "max-depth", 0, "Maximum number of labels below the root domain reached by recursive brute forcing")
 	enumFlags.IntVar(&args.MaxDNSQueries, "max-dns-queries", 0, "Maximum number of concurrent DNS queries")
+	enumFlags.Var(&args.MinForDepth, "min-for-depth", "DEPTH:COUNT pairs separated by commas for the subdomain labels seen before recursive brute forcing at each depth")
 	enumFlags.IntVar(&args.MinForRecursive, "min-for-recursive", 1, "Subdomain labels seen before recursive brute forcing")
 	enumFlags.Var(&args.Ports, "p", "Ports separated by commas (default: 443)")
 	enumFlags.Var(&args.Resolvers, "r", "IP addresses of preferred DNS resolvers (can be used multiple times)")
+	enumFlags.StringVar(&args.Resume, "resume", "", "UUID of an interrupted enumeration to continue from its checkpoint")
+	enumFlags.IntVar(&args.SampleSize, "sample", 0, "Accept at most N names per domain from each data source, without brute forcing")
 	enumFlags.IntVar(&args.Timeout, "timeout", 0, "Number of minutes to let enumeration run before quitting")
 }
 
 func defineEnumOptionFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	enumFlags.BoolVar(&args.Options.Active, "active", false, "Attempt zone transfers and certificate name grabs")
+	enumFlags.BoolVar(&args.Options.Addrs, "addrs", false, "Print just the unique IP addresses of the discovered names, one per line (select with -ipv4 or -ipv6)")
+	enumFlags.BoolVar(&args.Options.Authoritative, "authoritative", false, "Resolve names using the authoritative servers of each zone (requires -active)")
 	enumFlags.BoolVar(&args.Options.BruteForcing, "brute", false, "Execute brute forcing after searches")
 	enumFlags.BoolVar(&args.Options.DemoMode, "demo", false, "Censor output to make it suitable for demonstrations")
+	enumFlags.BoolVar(&args.Options.EgressReport, "egress-report", false, "Record the remote endpoints contacted in the output directory")
 	enumFlags.BoolVar(&args.Options.IPs, "ip", false, "Show the IP addresses for discovered names")
 	enumFlags.BoolVar(&args.Options.IPv4, "ipv4", false, "Show the IPv4 addresses for discovered names")
 	enumFlags.BoolVar(&args.Options.IPv6, "ipv6", false, "Show the IPv6 addresses for discovered names")
 	enumFlags.BoolVar(&args.Options.ListSources, "list", false, "Print the names of all available data sources")
+	enumFlags.BoolVar(&args.Options.Liveness, "liveness", false, "Probe resolved addresses with ICMP echo and TCP connections to learn which respond")
+	enumFlags.BoolVar(&args.Options.Monitor, "monitor", false, "Only request results issued since the previous execution from supporting sources")
+	enumFlags.BoolVar(&args.Options.NDJSON, "ndjson", false, "Stream each finding to stdout as a line of JSON (same as -json -)")
 	enumFlags.BoolVar(&args.Options.MonitorResolverRate, "noresolvrate", true, "Disable resolver rate monitoring")
 	enumFlags.BoolVar(&args.Options.NoAlts, "noalts", false, "Disable generation of altered names")
 	enumFlags.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
 	enumFlags.BoolVar(&args.Options.NoLocalDatabase, "nolocaldb", false, "Disable saving data into a local database")
 	enumFlags.BoolVar(&args.Options.NoRecursive, "norecursive", false, "Turn off recursive brute forcing")
 	enumFlags.BoolVar(&args.Options.Passive, "passive", false, "Disable DNS resolution of names and dependent features")
+	enumFlags.BoolVar(&args.Options.Preflight, "preflight", false, "Tune brute forcing, alterations and concurrency using a quick passive pass first")
+	enumFlags.BoolVar(&args.Options.Progress, "progress", false, "Show the names found, DNS queries, active sources, queues and elapsed time on stderr")
 	enumFlags.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
 	enumFlags.BoolVar(&args.Options.Sources, "src", false, "Print data sources for the discovered names")
+	enumFlags.BoolVar(&args.Options.SourceStats, "src-stats", false, "Print a summary of the activity of each data source")
 	enumFlags.BoolVar(&args.Options.Verbose, "v", false, "Output status / debug / troubleshooting info")
+	enumFlags.BoolVar(&args.Options.VHosts, "vhosts", false, "Probe the web servers at resolved addresses for virtual hosts lacking DNS records (requires -active)")
 }
 
 func defineEnumFilepathFlags(enumFlags *flag.FlagSet, args *enumArgs) {
@@ -137,13 +183,17 @@ func defineEnumFilepathFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	enumFlags.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
 	enumFlags.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
 	enumFlags.Var(&args.Filepaths.Domains, "df", "Path to a file providing root domain names")
+	enumFlags.StringVar(&args.Filepaths.EgressAllow, "egress-allow", "", "Path to a file providing the only hosts, addresses and CIDRs that can be contacted")
+	enumFlags.StringVar(&args.Filepaths.Ignore, "ignore", "", "Path to a file listing names, regexes and CIDRs that are not reported as new in monitor mode")
 	enumFlags.StringVar(&args.Filepaths.ExcludedSrcs, "ef", "", "Path to a file providing data sources to exclude")
 	enumFlags.StringVar(&args.Filepaths.IncludedSrcs, "if", "", "Path to a file providing data sources to include")
-	enumFlags.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file")
+	enumFlags.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file (one object per line), or - to stream the findings to stdout")
 	enumFlags.StringVar(&args.Filepaths.LogFile, "log", "", "Path to the log file where errors will be written")
 	enumFlags.Var(&args.Filepaths.Names, "nf", "Path to a file providing already known subdomain names (from other tools/sources)")
 	enumFlags.Var(&args.Filepaths.Resolvers, "rf", "Path to a file providing preferred DNS resolvers")
 	enumFlags.StringVar(&args.Filepaths.ScriptsDirectory, "scripts", "", "Path to a directory containing ADS scripts")
+	enumFlags.StringVar(&args.Filepaths.SummaryCSV, "summary-csv", "", "Path to the CSV file with a row for each netblock of the ASN summary (use - for stdout)")
+	enumFlags.StringVar(&args.Filepaths.SummaryJSON, "summary-json", "", "Path to the JSON file providing the totals and ASN summary (use - for stdout)")
 	enumFlags.StringVar(&args.Filepaths.TermOut, "o", "", "Path to the text file containing terminal stdout/stderr")
 }
 
@@ -152,12 +202,34 @@ func runEnumCommand(clArgs []string) {
 	rand.Seed(time.Now().UTC().UnixNano())
 
 	// Extract the correct config from the user provided arguments and/or configuration file
-	cfg, args := argsAndConfig(clArgs)
+	cfg, args, checkpoint := argsAndConfig(clArgs)
 	if cfg == nil {
 		return
 	}
 	createOutputDirectory(cfg)
 
+	var progress *progressLine
+	// The progress line stays below the other output written to the terminal
+	if args.Options.Progress && !args.Options.Silent {
+		progress = newProgressLine(color.Error)
+		color.Output = progress.Writer(color.Output)
+		color.Error = progress.Writer(color.Error)
+	}
+
+	il, err := loadIgnoreList(args.Filepaths.Ignore)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+
+	var cm *format.ContextMap
+	if cfg.ContextFile != "" {
+		if cm, err = format.LoadContextFile(cfg.ContextFile); err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	rLog, wLog := io.Pipe()
 	// Setup logging so that messages can be written to the file and used by the program
 	cfg.Log = log.New(wLog, "", log.Lmicroseconds)
@@ -176,10 +248,36 @@ func runEnumCommand(clArgs []string) {
 		os.Exit(1)
 	}
 	defer sys.Shutdown()
+
+	if cfg.PruneOnStart {
+		for _, g := range sys.GraphDatabases() {
+			if err := pruneGraph(g, cfg); err != nil {
+				r.Fprintf(color.Error, "Failed to prune the %s database: %v\n", g.String(), err)
+			}
+		}
+	}
+	for _, g := range sys.GraphDatabases() {
+		il = suppressNames(il, g)
+	}
 	sys.SetDataSources(datasrcs.GetAllSources(sys, true))
 	// Expand data source category names into the associated source names
 	cfg.SourceFilter.Sources = expandCategoryNames(cfg.SourceFilter.Sources, generateCategoryMap(sys))
 
+	var tuning *enum.PreflightTuning
+	if cfg.Preflight {
+		fmt.Fprintf(color.Error, "%s\n", yellow("Performing the passive preflight to tune the enumeration"))
+
+		tuning, err = enum.RunPreflight(cfg, sys)
+		if err == nil {
+			err = tuning.Apply(cfg)
+		}
+		if err != nil {
+			r.Fprintf(color.Error, "The preflight failed: %v\n", err)
+			os.Exit(1)
+		}
+		printPreflightTuning(tuning)
+	}
+
 	// Setup the new enumeration
 	e := enum.NewEnumeration(cfg, sys)
 	if e == nil {
@@ -187,6 +285,14 @@ func runEnumCommand(clArgs []string) {
 		os.Exit(1)
 	}
 	defer e.Close()
+	if tuning != nil {
+		e.SetPreflight(tuning)
+	}
+	if checkpoint != nil {
+		fmt.Fprintf(color.Error, "%s%s%s%s\n", yellow("Resuming enumeration "), yellow(checkpoint.UUID),
+			yellow(" from the checkpoint saved "), yellow(checkpoint.Updated.Format(time.RFC1123)))
+		e.Resume(checkpoint)
+	}
 
 	var wg sync.WaitGroup
 	var outChans []chan *requests.Output
@@ -212,18 +318,39 @@ func runEnumCommand(clArgs []string) {
 	outChans = append(outChans, jsonOutChan)
 
 	wg.Add(1)
-	go processOutput(e, outChans, done, &wg)
+	go processOutput(e, il, cm, outChans, done, &wg)
 
 	go signalHandler(e)
+	go showOpenBreakers(e, done)
+	if progress != nil {
+		e.Bus.Subscribe(requests.EnumProgressTopic, progress.Update)
+	}
 	// Start the enumeration process
 	if err := e.Start(); err != nil {
 		r.Println(err)
 		os.Exit(1)
 	}
+	if progress != nil {
+		progress.Stop()
+	}
 	// Let all the goroutines know that the enumeration has finished
 	close(done)
 	wg.Wait()
 
+	if args.Options.SourceStats {
+		printSourceStats(e)
+	}
+	printTrippedBreakers(e)
+	if path := enum.CheckpointPath(cfg, cfg.UUID.String()); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			fmt.Fprintf(color.Error, "%s%s\n",
+				yellow("The enumeration was interrupted and can be continued using -resume "), yellow(cfg.UUID.String()))
+		}
+	}
+	if amassnet.Egress != nil {
+		writeEgressReport(e, args)
+	}
+
 	//e.Graph.DumpGraph()
 	// If necessary, handle graph database migration
 	if !cfg.Passive && len(e.Sys.GraphDatabases()) > 0 {
@@ -234,15 +361,97 @@ func runEnumCommand(clArgs []string) {
 			fmt.Fprintf(color.Error, "%s%s%s\n",
 				yellow("Discoveries are being migrated into the "), yellow(g.String()), yellow(" database"))
 
-			if err := e.Graph.MigrateEvents(g, e.Config.UUID.String()); err != nil {
+			err := migrateWithProgress(func(ctx context.Context) error {
+				return e.Graph.MigrateEventsWithContext(ctx, g, e.Config.UUID.String())
+			})
+			if err != nil {
 				fmt.Fprintf(color.Error, "%s%s%s%s\n",
 					red("The database migration to "), red(g.String()), red(" failed: "), red(err.Error()))
 			}
 		}
+
+		if cfg.EmailReport != nil {
+			sendEmailReport(e, il)
+		}
+	}
+}
+
+// printPreflightTuning shows the observations of the preflight and the settings selected from them.
+func printPreflightTuning(t *enum.PreflightTuning) {
+	fmt.Fprintf(color.Error, "%s%s, %s%s, %s%s, %s%s\n",
+		blue("Names: "), yellow(strconv.Itoa(t.Names)),
+		blue("Subdomains: "), yellow(strconv.Itoa(t.Subdomains)),
+		blue("Largest zone: "), yellow(strconv.Itoa(t.LargestZone)),
+		blue("Wildcards: "), yellow(strconv.Itoa(len(t.Wildcards))))
+	fmt.Fprintf(color.Error, "%s%s, %s%s, %s%s, %s%s, %s%s\n",
+		blue("Brute forcing: "), yellow(strconv.FormatBool(t.BruteForcing)),
+		blue("Recursive after: "), yellow(strconv.Itoa(t.MinForRecursive)),
+		blue("Observed words: "), yellow(strconv.Itoa(len(t.Labels))),
+		blue("Edit distance: "), yellow(strconv.Itoa(t.EditDistance)),
+		blue("DNS queries: "), yellow(strconv.Itoa(t.MaxDNSQueries)))
+}
+
+// sendEmailReport emails the changes discovered since the previous report once the
+// schedule from the configuration has elapsed. The enumerations that were active
+// after the previous report are compared with the earlier enumerations.
+func sendEmailReport(e *enum.Enumeration, il *format.IgnoreList) {
+	rcfg := e.Config.EmailReport
+	dir := config.OutputDirectory(e.Config.Dir)
+	graphs := e.Sys.GraphDatabases()
+	if dir == "" || len(graphs) == 0 {
+		return
+	}
+
+	now := time.Now()
+	last := notify.LastReport(dir)
+	if !notify.ReportDue(last, rcfg.Interval, now) {
+		return
+	}
+
+	domains := e.Config.Domains()
+	memDB, err := memGraphForScope(domains, graphs[0])
+	if err != nil {
+		fmt.Fprintf(color.Error, "%s%s\n", red("Failed to build the email report: "), red(err.Error()))
+		return
+	}
+	defer memDB.Close()
+
+	var older, newer []string
+	current := e.Config.UUID.String()
+	uuids, earliest, latest := orderedEvents(eventUUIDs(domains, memDB), memDB)
+	since := last
+	for i, id := range uuids {
+		if id == current || (!last.IsZero() && latest[i].After(last)) {
+			if since.IsZero() {
+				since = earliest[i]
+			}
+			newer = append(newer, id)
+		} else {
+			older = append(older, id)
+		}
+	}
+
+	var oldOut []*requests.Output
+	if len(older) > 0 {
+		oldOut = getScopedOutput(older, domains, memDB)
 	}
+	report := notify.NewReport(domains, oldOut, getScopedOutput(newer, domains, memDB), il)
+	report.Since = since
+	report.Until = now
+	report.Sources = sourceStats(e)
+	report.Breakers = circuitBreakers(e).Tripped()
+
+	if err := notify.SendEmail(rcfg, rcfg.Subject, report.String()); err != nil {
+		fmt.Fprintf(color.Error, "%s%s\n", red("Failed to send the email report: "), red(err.Error()))
+		return
+	}
+	if err := notify.SaveLastReport(dir, now); err != nil {
+		fmt.Fprintf(color.Error, "%s%s\n", red("Failed to save the time of the email report: "), red(err.Error()))
+	}
+	fmt.Fprintf(color.Error, "%s%s\n", green("The report was emailed to "), green(strings.Join(rcfg.To, ", ")))
 }
 
-func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
+func argsAndConfig(clArgs []string) (*config.Config, *enumArgs, *enum.Checkpoint) {
 	args := enumArgs{
 		AltWordList:       stringset.New(),
 		AltWordListMask:   stringset.New(),
@@ -250,6 +459,8 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 		BruteWordListMask: stringset.New(),
 		Blacklist:         stringset.New(),
 		Domains:           stringset.New(),
+		Categories:        stringset.New(),
+		ExcludedCats:      stringset.New(),
 		Excluded:          stringset.New(),
 		Included:          stringset.New(),
 		Names:             stringset.New(),
@@ -269,7 +480,7 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 
 	if len(clArgs) < 1 {
 		commandUsage(enumUsageMsg, enumCommand, enumBuf)
-		return nil, &args
+		return nil, &args, nil
 	}
 
 	if err := enumCommand.Parse(clArgs); err != nil {
@@ -278,7 +489,7 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 	}
 	if help1 || help2 {
 		commandUsage(enumUsageMsg, enumCommand, enumBuf)
-		return nil, &args
+		return nil, &args, nil
 	}
 
 	if args.Interface != "" {
@@ -295,6 +506,13 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 	if args.Options.NoColor {
 		color.NoColor = true
 	}
+	if args.Options.NDJSON {
+		args.Filepaths.JSONOutput = "-"
+	}
+	// The findings streamed to stdout are not mixed with the output intended for the terminal
+	if args.Filepaths.JSONOutput == "-" || args.Filepaths.SummaryCSV == "-" || args.Filepaths.SummaryJSON == "-" {
+		color.Output = color.Error
+	}
 	if args.Options.Silent {
 		color.Output = ioutil.Discard
 		color.Error = ioutil.Discard
@@ -315,6 +533,10 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 		fmt.Fprintf(color.Error, "%v\n", err)
 		os.Exit(1)
 	}
+	if err := setupEgressMonitor(&args); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
 
 	cfg := config.NewConfig()
 	// Check if a configuration file was provided, and if so, load the settings
@@ -339,20 +561,44 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 		for _, line := range GetAllSourceInfo(cfg) {
 			fmt.Fprintln(color.Output, line)
 		}
-		return nil, &args
+		return nil, &args, nil
+	}
+
+	// The interrupted enumeration continues with the same UUID and root domain names
+	var checkpoint *enum.Checkpoint
+	if args.Resume != "" {
+		var err error
+
+		checkpoint, err = enum.LoadCheckpoint(cfg, args.Resume)
+		if err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+		cfg.UUID = uuid.MustParse(checkpoint.UUID)
+		cfg.AddDomains(checkpoint.Domains)
 	}
 
 	// Some input validation
-	if cfg.Passive && (args.Options.IPs || args.Options.IPv4 || args.Options.IPv6) {
+	if cfg.Passive && (args.Options.Addrs || args.Options.IPs || args.Options.IPv4 || args.Options.IPv6) {
 		r.Fprintln(color.Error, "IP addresses cannot be provided without DNS resolution")
 		os.Exit(1)
 	}
+	if err := checkSummaryPaths(args.Filepaths.SummaryCSV, args.Filepaths.SummaryJSON); err != nil {
+		r.Fprintln(color.Error, err.Error())
+		os.Exit(1)
+	}
+	// The address list is written to stdout as well
+	if args.Options.Addrs && (args.Filepaths.JSONOutput == "-" ||
+		args.Filepaths.SummaryCSV == "-" || args.Filepaths.SummaryJSON == "-") {
+		r.Fprintln(color.Error, "The -addrs flag cannot be used with other output written to stdout")
+		os.Exit(1)
+	}
 	if len(cfg.Domains()) == 0 {
 		r.Fprintln(color.Error, "Configuration error: No root domain names were provided")
 		os.Exit(1)
 	}
 
-	return cfg, &args
+	return cfg, &args, checkpoint
 }
 
 func printOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Output, wg *sync.WaitGroup) {
@@ -361,6 +607,12 @@ func printOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Outp
 	var total int
 	tags := make(map[string]int)
 	asns := make(map[int]*format.ASNSummaryData)
+	// The address list is printed without the names, colors or the summary table for piping into port scanners
+	var addrwriter *format.AddressWriter
+	if args.Options.Addrs {
+		addrwriter = format.NewAddressWriter(color.Output)
+	}
+	summaryFiles := args.Filepaths.SummaryCSV != "" || args.Filepaths.SummaryJSON != ""
 	// Print all the output returned by the enumeration
 	for out := range output {
 		out.Addresses = format.DesiredAddrTypes(out.Addresses, args.Options.IPv4, args.Options.IPv6)
@@ -369,19 +621,42 @@ func printOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Outp
 		}
 
 		total++
-		if !args.Options.Passive {
+		if !args.Options.Passive || summaryFiles {
 			format.UpdateSummaryData(out, tags, asns)
 		}
 
+		if addrwriter != nil {
+			if err := addrwriter.Write(out); err != nil {
+				r.Fprintf(color.Error, "Failed to print the addresses: %v\n", err)
+			}
+			continue
+		}
+
 		source, name, ips := format.OutputLineParts(out, args.Options.Sources,
 			args.Options.IPs || args.Options.IPv4 || args.Options.IPv6, args.Options.DemoMode)
 		if ips != "" {
 			ips = " " + ips
 		}
 
-		fmt.Fprintf(color.Output, "%s%s%s\n", blue(source), green(name), yellow(ips))
+		var marker string
+		// Highlight the names that no previous enumeration discovered
+		if out.New {
+			marker = " " + red("[new]")
+		}
+
+		if c := format.ContextLine(out.Context); c != "" {
+			marker += " " + blue(c)
+		}
+
+		fmt.Fprintf(color.Output, "%s%s%s%s\n", blue(source), green(name), yellow(ips), marker)
 	}
 
+	if err := writeSummaryFiles(args.Filepaths.SummaryCSV, args.Filepaths.SummaryJSON, total, tags, asns); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+	}
+	if addrwriter != nil {
+		return
+	}
 	if total == 0 {
 		r.Println("No names were discovered")
 	} else if !args.Options.Passive {
@@ -389,6 +664,121 @@ func printOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Outp
 	}
 }
 
+func printSourceStats(e *enum.Enumeration) {
+	stats := sourceStats(e)
+	if stats == nil {
+		return
+	}
+
+	breakers := make(map[string]systems.BreakerStatus)
+	for _, b := range circuitBreakers(e).Status() {
+		breakers[b.Source] = b
+	}
+
+	fmt.Fprintf(color.Error, "\n%-24s %10s %10s %10s %10s %12s %10s\n",
+		"Data Source", "Requests", "Names", "Unique", "Errors", "Time", "Breaker")
+	fmt.Fprintln(color.Error, strings.Repeat("-", 92))
+	for _, s := range stats {
+		state := systems.BreakerClosed.String()
+		if b, found := breakers[s.Source]; found {
+			state = b.State.String()
+		}
+
+		fmt.Fprintf(color.Error, "%s %s %s %s %s %s %s\n", blue(fmt.Sprintf("%-24s", s.Source)),
+			yellow(fmt.Sprintf("%10d", s.Requests)), green(fmt.Sprintf("%10d", s.Names)),
+			green(fmt.Sprintf("%10d", s.Unique)), red(fmt.Sprintf("%10d", s.Errors)),
+			yellow(fmt.Sprintf("%12s", s.Elapsed.Round(time.Millisecond))), red(fmt.Sprintf("%10s", state)))
+	}
+}
+
+// showOpenBreakers periodically prints the circuit breakers that are refusing requests until done is closed.
+func showOpenBreakers(e *enum.Enumeration, done chan struct{}) {
+	t := time.NewTicker(time.Minute)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+			var open []string
+			for _, b := range circuitBreakers(e).Status() {
+				if b.State != systems.BreakerClosed {
+					open = append(open, b.String())
+				}
+			}
+
+			if len(open) > 0 {
+				fmt.Fprintf(color.Error, "%s%s\n", yellow("Circuit breakers: "), red(strings.Join(open, ", ")))
+			}
+		}
+	}
+}
+
+// printTrippedBreakers prints the circuit breakers that opened during the enumeration.
+func printTrippedBreakers(e *enum.Enumeration) {
+	tripped := circuitBreakers(e).Tripped()
+	if len(tripped) == 0 {
+		return
+	}
+
+	fmt.Fprintf(color.Error, "\n%s\n", yellow("The circuit breakers of these data sources opened during the enumeration:"))
+	for _, b := range tripped {
+		fmt.Fprintf(color.Error, "  %s\n", red(b.String()))
+	}
+}
+
+// circuitBreakers returns the breakers of the System, or nil when the System does not have them.
+func circuitBreakers(e *enum.Enumeration) *systems.CircuitBreakers {
+	if sys, ok := e.Sys.(systems.BreakerSystem); ok {
+		return sys.CircuitBreakers()
+	}
+	return nil
+}
+
+// sourceStats returns the activity of each data source, including the sources without any activity.
+func sourceStats(e *enum.Enumeration) []systems.SourceStats {
+	sys, ok := e.Sys.(systems.SourceStatsSystem)
+	if !ok || sys.SourceStats() == nil {
+		return nil
+	}
+	tracker := sys.SourceStats()
+
+	// Count the names that were provided by a single data source
+	unique := make(map[string]int)
+	for _, out := range e.Graph.EventNames(e.Config.UUID.String(), nil) {
+		if len(out.Sources) == 1 {
+			unique[out.Sources[0]]++
+		}
+	}
+	for src, num := range unique {
+		tracker.SetUnique(src, num)
+	}
+
+	stats := tracker.Stats()
+	// Data sources without any activity are also included in the summary
+	seen := stringset.New()
+	for _, s := range stats {
+		seen.Insert(s.Source)
+	}
+	for _, src := range e.Sys.DataSources() {
+		if !seen.Has(src.String()) {
+			stats = append(stats, systems.SourceStats{Source: src.String()})
+		}
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].Unique != stats[j].Unique {
+			return stats[i].Unique > stats[j].Unique
+		}
+		if stats[i].Names != stats[j].Names {
+			return stats[i].Names > stats[j].Names
+		}
+		return stats[i].Source < stats[j].Source
+	})
+	return stats
+}
+
 func saveTextOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Output, wg *sync.WaitGroup) {
 	defer wg.Done()
 
@@ -442,7 +832,7 @@ func saveJSONOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.O
 	if args.Filepaths.JSONOutput != "" {
 		jsonfile = args.Filepaths.JSONOutput
 	}
-	if args.Filepaths.AllFilePrefix != "" {
+	if args.Filepaths.AllFilePrefix != "" && jsonfile != "-" {
 		jsonfile = args.Filepaths.AllFilePrefix + ".json"
 	}
 
@@ -450,28 +840,82 @@ func saveJSONOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.O
 		return
 	}
 
-	jsonptr, err := os.OpenFile(jsonfile, os.O_WRONLY|os.O_CREATE, 0644)
+	var writer *format.JSONLinesWriter
+	if jsonfile == "-" {
+		writer = format.NewJSONLinesStream(os.Stdout)
+	} else {
+		var err error
+
+		writer, err = format.NewJSONLinesWriter(jsonfile)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to open the JSON output file: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	defer writer.Close()
+
+	// Save all the output returned by the enumeration as it arrives
+	for out := range output {
+		if err := writer.Write(out); err != nil {
+			e.Config.Log.Printf("Failed to write to the JSON output file: %v", err)
+		}
+	}
+}
+
+func setupEgressMonitor(args *enumArgs) error {
+	if !args.Options.EgressReport && args.Filepaths.EgressAllow == "" {
+		return nil
+	}
+
+	monitor := amassnet.NewEgressMonitor()
+	if args.Filepaths.EgressAllow != "" {
+		list, err := config.GetListFromFile(args.Filepaths.EgressAllow)
+		if err != nil {
+			return fmt.Errorf("Failed to parse the egress allow-list file: %v", err)
+		}
+		if len(list) == 0 {
+			return fmt.Errorf("The egress allow-list file %s is empty", args.Filepaths.EgressAllow)
+		}
+		if err := monitor.Allow(list...); err != nil {
+			return err
+		}
+	}
+
+	amassnet.Egress = monitor
+	return nil
+}
+
+func writeEgressReport(e *enum.Enumeration, args *enumArgs) {
+	dir := config.OutputDirectory(e.Config.Dir)
+	path := filepath.Join(dir, "amass_egress.csv")
+	if args.Filepaths.AllFilePrefix != "" {
+		path = args.Filepaths.AllFilePrefix + "_egress.csv"
+	}
+
+	f, err := os.Create(path)
 	if err != nil {
-		r.Fprintf(color.Error, "Failed to open the JSON output file: %v\n", err)
-		os.Exit(1)
+		r.Fprintf(color.Error, "Failed to open the egress report file: %v\n", err)
+		return
 	}
-	defer func() {
-		jsonptr.Sync()
-		jsonptr.Close()
-	}()
+	defer f.Close()
 
-	jsonptr.Truncate(0)
-	jsonptr.Seek(0, 0)
+	if err := amassnet.Egress.WriteReport(f); err != nil {
+		r.Fprintf(color.Error, "Failed to write the egress report: %v\n", err)
+		return
+	}
 
-	enc := json.NewEncoder(jsonptr)
-	// Save all the output returned by the enumeration
-	for out := range output {
-		// Handle encoding the result as JSON
-		enc.Encode(out)
+	var denied int
+	for _, ep := range amassnet.Egress.Endpoints() {
+		denied += ep.Denied
+	}
+	if denied > 0 {
+		fmt.Fprintf(color.Error, "%s\n", yellow(fmt.Sprintf("%d connections were refused by the egress allow-list", denied)))
 	}
+	fmt.Fprintf(color.Error, "%s%s\n", green("The remote endpoints contacted were written to "), green(path))
 }
 
-func processOutput(e *enum.Enumeration, outputs []chan *requests.Output, done chan struct{}, wg *sync.WaitGroup) {
+func processOutput(e *enum.Enumeration, il *format.IgnoreList, cm *format.ContextMap,
+	outputs []chan *requests.Output, done chan struct{}, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	// This filter ensures that we only get new names
@@ -482,6 +926,11 @@ func processOutput(e *enum.Enumeration, outputs []chan *requests.Output, done ch
 			if !e.Config.IsDomainInScope(o.Name) {
 				continue
 			}
+			// Churning assets on the ignore list are still stored, but never announced as new
+			if o.New && il.Ignored(o) {
+				o.New = false
+			}
+			cm.Annotate(o)
 
 			for _, ch := range outputs {
 				ch <- o
@@ -717,8 +1166,16 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 		conf.AltWordlist = e.AltWordList.Slice()
 	}
 	if e.Options.BruteForcing {
+		if e.SampleSize > 0 {
+			return errors.New("The brute flag cannot be used with the sample flag")
+		}
 		conf.BruteForcing = true
 	}
+	if e.SampleSize > 0 {
+		conf.SampleSize = e.SampleSize
+		// Sampling provides a quick look, so brute forcing from the config file is disabled as well
+		conf.BruteForcing = false
+	}
 	if e.Options.NoAlts {
 		conf.Alterations = false
 	}
@@ -731,15 +1188,62 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 	if e.MinForRecursive != 1 {
 		conf.MinForRecursive = e.MinForRecursive
 	}
+	if e.MaxDepth < 0 {
+		return fmt.Errorf("The maximum depth must not be negative: %d", e.MaxDepth)
+	} else if e.MaxDepth > 0 {
+		conf.MaxDepth = e.MaxDepth
+	}
+	if len(e.MinForDepth) > 0 {
+		depths := make(map[int]int)
+
+		for _, v := range e.MinForDepth {
+			depth, min, err := config.ParseMinForDepth(v)
+			if err != nil {
+				return err
+			}
+			depths[depth] = min
+		}
+		conf.MinForDepth = depths
+	}
 	if e.Options.Active {
 		conf.Active = true
 	}
+	if e.Options.Liveness {
+		conf.Liveness = true
+	}
+	if e.Options.VHosts {
+		conf.VHosts = true
+	}
+	if e.Options.Authoritative {
+		conf.Authoritative = true
+	}
+	if e.AuthoritativeRate > 0 {
+		conf.AuthoritativeRate = e.AuthoritativeRate
+	}
 	if e.Options.Passive {
 		conf.Passive = true
 	}
+	if e.Options.Monitor {
+		conf.Monitor = true
+	}
+	if e.Options.Preflight {
+		conf.Preflight = true
+	}
 	if e.Blacklist.Len() > 0 {
 		conf.Blacklist = e.Blacklist.Slice()
 	}
+	if len(e.BlacklistRegexps) > 0 {
+		var excluded []*regexp.Regexp
+
+		for _, p := range e.BlacklistRegexps {
+			re, err := config.ParseExcludedName(p)
+			if err != nil {
+				return fmt.Errorf("The blacklist regex %s is invalid: %v", p, err)
+			}
+			excluded = append(excluded, re)
+		}
+		conf.ExcludedNames = excluded
+	}
 	if e.Timeout > 0 {
 		conf.Timeout = e.Timeout
 	}
@@ -753,6 +1257,18 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 		conf.MonitorResolverRate = false
 	}
 
+	if len(e.Categories) > 0 {
+		conf.SourceCategories.Enabled = nil
+		if err := conf.EnableSourceCategories(e.Categories.Slice()...); err != nil {
+			return err
+		}
+	}
+	if len(e.ExcludedCats) > 0 {
+		if err := conf.DisableSourceCategories(e.ExcludedCats.Slice()...); err != nil {
+			return err
+		}
+	}
+
 	if len(e.Included) > 0 {
 		conf.SourceFilter.Include = true
 		// Check if brute forcing and alterations should be added
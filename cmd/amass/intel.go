@@ -5,6 +5,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -13,6 +14,9 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -20,13 +24,15 @@ import (
 	"github.com/OWASP/Amass/v3/datasrcs"
 	"github.com/OWASP/Amass/v3/format"
 	"github.com/OWASP/Amass/v3/intel"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/fatih/color"
 )
 
 const (
-	intelUsageMsg = "intel [options] [-whois -d DOMAIN] [-addr ADDR -asn ASN -cidr CIDR]"
+	intelUsageMsg = "intel [options] [-whois -d DOMAIN] [-whois-org ORG] [-whois-email EMAIL] [-lookalike -d DOMAIN] [-addr ADDR -asn ASN -cidr CIDR]"
 )
 
 type intelArgs struct {
@@ -34,20 +40,28 @@ type intelArgs struct {
 	ASNs             format.ParseInts
 	CIDRs            format.ParseCIDRs
 	OrganizationName string
+	Regions          format.ParseStrings
 	Domains          stringset.Set
+	Categories       stringset.Set
+	ExcludedCats     stringset.Set
 	Excluded         stringset.Set
 	Included         stringset.Set
 	MaxDNSQueries    int
+	MinObservations  int
 	Ports            format.ParseInts
 	Resolvers        stringset.Set
 	Timeout          int
+	WhoisEmails      format.ParseStrings
+	WhoisOrgs        format.ParseTerms
 	Options          struct {
 		Active              bool
 		DemoMode            bool
+		ExcludeGenericRDNS  bool
 		IPs                 bool
 		IPv4                bool
 		IPv6                bool
 		ListSources         bool
+		Lookalikes          bool
 		ReverseWhois        bool
 		Sources             bool
 		MonitorResolverRate bool
@@ -59,6 +73,7 @@ type intelArgs struct {
 		Domains      format.ParseStrings
 		ExcludedSrcs string
 		IncludedSrcs string
+		JSONOutput   string
 		LogFile      string
 		Resolvers    format.ParseStrings
 		TermOut      string
@@ -69,23 +84,31 @@ func defineIntelArgumentFlags(intelFlags *flag.FlagSet, args *intelArgs) {
 	intelFlags.Var(&args.Addresses, "addr", "IPs and ranges (192.168.1.1-254) separated by commas")
 	intelFlags.Var(&args.ASNs, "asn", "ASNs separated by commas (can be used multiple times)")
 	intelFlags.Var(&args.CIDRs, "cidr", "CIDRs separated by commas (can be used multiple times)")
-	intelFlags.StringVar(&args.OrganizationName, "org", "", "Search string provided against AS description information")
+	intelFlags.Var(&args.Regions, "cc", "Country codes or registries (e.g. DE,ripencc) that restrict ASN and netblock discovery")
+	intelFlags.StringVar(&args.OrganizationName, "org", "", "Organization name matched against the AS descriptions provided by the registries")
 	intelFlags.Var(&args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	intelFlags.Var(&args.Categories, "categories", "Data source categories separated by commas to be exclusively used")
+	intelFlags.Var(&args.ExcludedCats, "exclude-categories", "Data source categories separated by commas to be excluded")
 	intelFlags.Var(&args.Excluded, "exclude", "Data source names separated by commas to be excluded")
 	intelFlags.Var(&args.Included, "include", "Data source names separated by commas to be included")
 	intelFlags.IntVar(&args.MaxDNSQueries, "max-dns-queries", 0, "Maximum number of concurrent DNS queries")
+	intelFlags.IntVar(&args.MinObservations, "min-observations", 0, "Number of addresses that must reference a root domain before it is returned")
 	intelFlags.Var(&args.Ports, "p", "Ports separated by commas (default: 443)")
 	intelFlags.Var(&args.Resolvers, "r", "IP addresses of preferred DNS resolvers (can be used multiple times)")
 	intelFlags.IntVar(&args.Timeout, "timeout", 0, "Number of minutes to let enumeration run before quitting")
+	intelFlags.Var(&args.WhoisEmails, "whois-email", "Registrant email addresses separated by commas run through reverse whois")
+	intelFlags.Var(&args.WhoisOrgs, "whois-org", "Registrant organization run through reverse whois (can be used multiple times)")
 }
 
 func defineIntelOptionFlags(intelFlags *flag.FlagSet, args *intelArgs) {
 	intelFlags.BoolVar(&args.Options.Active, "active", false, "Attempt certificate name grabs")
 	intelFlags.BoolVar(&args.Options.DemoMode, "demo", false, "Censor output to make it suitable for demonstrations")
+	intelFlags.BoolVar(&args.Options.ExcludeGenericRDNS, "exclude-generic-rdns", false, "Ignore reverse DNS names generated by providers for address ranges")
 	intelFlags.BoolVar(&args.Options.IPs, "ip", false, "Show the IP addresses for discovered names")
 	intelFlags.BoolVar(&args.Options.IPv4, "ipv4", false, "Show the IPv4 addresses for discovered names")
 	intelFlags.BoolVar(&args.Options.IPv6, "ipv6", false, "Show the IPv6 addresses for discovered names")
 	intelFlags.BoolVar(&args.Options.ListSources, "list", false, "Print the names of all available data sources")
+	intelFlags.BoolVar(&args.Options.Lookalikes, "lookalike", false, "Discover registered typo, homoglyph and bitflip permutations of the provided domains")
 	intelFlags.BoolVar(&args.Options.MonitorResolverRate, "noresolvrate", true, "Disable resolver rate monitoring")
 	intelFlags.BoolVar(&args.Options.ReverseWhois, "whois", false, "All provided domains are run through reverse whois")
 	intelFlags.BoolVar(&args.Options.Sources, "src", false, "Print data sources for the discovered names")
@@ -98,6 +121,7 @@ func defineIntelFilepathFlags(intelFlags *flag.FlagSet, args *intelArgs) {
 	intelFlags.Var(&args.Filepaths.Domains, "df", "Path to a file providing root domain names")
 	intelFlags.StringVar(&args.Filepaths.ExcludedSrcs, "ef", "", "Path to a file providing data sources to exclude")
 	intelFlags.StringVar(&args.Filepaths.IncludedSrcs, "if", "", "Path to a file providing data sources to include")
+	intelFlags.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file (one record per line), or - to stream the records to stdout")
 	intelFlags.StringVar(&args.Filepaths.LogFile, "log", "", "Path to the log file where errors will be written")
 	intelFlags.Var(&args.Filepaths.Resolvers, "rf", "Path to a file providing preferred DNS resolvers")
 	intelFlags.StringVar(&args.Filepaths.TermOut, "o", "", "Path to the text file containing terminal stdout/stderr")
@@ -105,10 +129,12 @@ func defineIntelFilepathFlags(intelFlags *flag.FlagSet, args *intelArgs) {
 
 func runIntelCommand(clArgs []string) {
 	args := intelArgs{
-		Domains:   stringset.New(),
-		Excluded:  stringset.New(),
-		Included:  stringset.New(),
-		Resolvers: stringset.New(),
+		Domains:      stringset.New(),
+		Categories:   stringset.New(),
+		ExcludedCats: stringset.New(),
+		Excluded:     stringset.New(),
+		Included:     stringset.New(),
+		Resolvers:    stringset.New(),
 	}
 	var help1, help2 bool
 	intelCommand := flag.NewFlagSet("intel", flag.ContinueOnError)
@@ -142,21 +168,14 @@ func runIntelCommand(clArgs []string) {
 		os.Exit(1)
 	}
 
+	// The records streamed to stdout are not mixed with the output intended for the terminal
+	if args.Filepaths.JSONOutput == "-" {
+		color.Output = color.Error
+	}
+
 	// Seed the default pseudo-random number generator
 	rand.Seed(time.Now().UTC().UnixNano())
 
-	if args.OrganizationName != "" {
-		asns, descs, err := config.LookupASNsByName(args.OrganizationName)
-		if err == nil {
-			for i, a := range asns {
-				fmt.Printf("%d, %s\n", a, descs[i])
-			}
-		} else {
-			fmt.Printf("%v\n", err)
-		}
-		return
-	}
-
 	if err := processIntelInputFiles(&args); err != nil {
 		fmt.Fprintf(color.Error, "%v\n", err)
 		os.Exit(1)
@@ -188,8 +207,18 @@ func runIntelCommand(clArgs []string) {
 		return
 	}
 
+	if args.OrganizationName != "" {
+		printOrganizationASNs(args.OrganizationName, cfg, &args)
+		return
+	}
+
+	// Searching for the registrants implies reverse whois
+	if len(args.WhoisOrgs) > 0 || len(args.WhoisEmails) > 0 {
+		args.Options.ReverseWhois = true
+	}
+
 	// Some input validation
-	if !args.Options.ReverseWhois && args.OrganizationName == "" &&
+	if !args.Options.ReverseWhois && !args.Options.Lookalikes && args.OrganizationName == "" &&
 		len(args.Addresses) == 0 && len(args.CIDRs) == 0 && len(args.ASNs) == 0 {
 		commandUsage(intelUsageMsg, intelCommand, intelBuf)
 		os.Exit(1)
@@ -218,17 +247,27 @@ func runIntelCommand(clArgs []string) {
 	}
 	ic.Config = cfg
 
-	if args.Options.ReverseWhois {
-		if len(ic.Config.Domains()) == 0 {
-			r.Fprintln(color.Error, "No root domain names were provided")
-			os.Exit(1)
-		}
+	if args.Options.Lookalikes && len(ic.Config.Domains()) == 0 {
+		r.Fprintln(color.Error, "No root domain names were provided")
+		os.Exit(1)
+	}
+	if args.Options.ReverseWhois && len(ic.Config.Domains()) == 0 &&
+		len(ic.Config.WhoisOrgs) == 0 && len(ic.Config.WhoisEmails) == 0 {
+		r.Fprintln(color.Error, "No root domain names, registrant organizations or email addresses were provided")
+		os.Exit(1)
+	}
 
+	if args.Options.ReverseWhois {
 		args.Options.IPs = false
 		args.Options.IPv4 = false
 		args.Options.IPv6 = false
 		go ic.ReverseWhois()
+	} else if args.Options.Lookalikes {
+		go ic.Lookalikes()
 	} else {
+		if args.Filepaths.JSONOutput != "" {
+			ic.ASNOutput = make(chan *requests.ASNRequest, 10)
+		}
 		go ic.HostedDomains()
 	}
 
@@ -236,6 +275,108 @@ func runIntelCommand(clArgs []string) {
 	processIntelOutput(ic, &args)
 }
 
+// intelRecord is the line written to the JSON output file for each ASN and root domain discovered.
+type intelRecord struct {
+	Type        string                  `json:"type"`
+	ASN         int                     `json:"asn,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	CIDRs       []string                `json:"cidrs,omitempty"`
+	Score       float64                 `json:"score,omitempty"`
+	Name        string                  `json:"name,omitempty"`
+	Domain      string                  `json:"domain,omitempty"`
+	Addresses   []string                `json:"addresses,omitempty"`
+	Tag         string                  `json:"tag,omitempty"`
+	Sources     []string                `json:"sources,omitempty"`
+	Lookalike   *requests.LookalikeInfo `json:"lookalike,omitempty"`
+}
+
+func asnIntelRecord(req *requests.ASNRequest) *intelRecord {
+	var cidrs []string
+	if req.Netblocks != nil {
+		cidrs = req.Netblocks.Slice()
+		sort.Strings(cidrs)
+	}
+
+	return &intelRecord{
+		Type:        "asn",
+		ASN:         req.ASN,
+		Description: req.Description,
+		CIDRs:       cidrs,
+	}
+}
+
+func domainIntelRecord(out *requests.Output) *intelRecord {
+	var addrs []string
+	for _, a := range out.Addresses {
+		addrs = append(addrs, a.Address.String())
+	}
+
+	return &intelRecord{
+		Type:      "domain",
+		Name:      out.Name,
+		Domain:    out.Domain,
+		Addresses: addrs,
+		Tag:       out.Tag,
+		Sources:   out.Sources,
+		Lookalike: out.Lookalike,
+	}
+}
+
+func openIntelJSONOutput(path string) *format.JSONLinesWriter {
+	if path == "" {
+		return nil
+	}
+	if path == "-" {
+		return format.NewJSONLinesStream(os.Stdout)
+	}
+
+	writer, err := format.NewJSONLinesWriter(path)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to open the JSON output file: %v\n", err)
+		os.Exit(1)
+	}
+	return writer
+}
+
+func printOrganizationASNs(org string, cfg *config.Config, args *intelArgs) {
+	candidates, err := intel.OrganizationASNs(context.Background(), org)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+	}
+
+	writer := openIntelJSONOutput(args.Filepaths.JSONOutput)
+	if writer != nil {
+		defer writer.Close()
+	}
+
+	var d *amassnet.Delegations
+	if len(cfg.Regions) > 0 {
+		if d, err = intel.LoadDelegations(cfg); err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	for _, c := range candidates {
+		if d != nil && !d.ASNInRegion(c.ASN, cfg.Regions) {
+			continue
+		}
+
+		fmt.Fprintf(color.Output, "%d, %s [%.0f%% match: %s]\n", c.ASN, c.Description, c.Score*100, strings.Join(c.Sources, ", "))
+		if writer != nil {
+			if err := writer.WriteRecord(&intelRecord{
+				Type:        "asn",
+				ASN:         c.ASN,
+				Description: c.Description,
+				Score:       c.Score,
+				Sources:     c.Sources,
+			}); err != nil {
+				r.Fprintf(color.Error, "Failed to write to the JSON output file: %v\n", err)
+			}
+		}
+	}
+}
+
 func processIntelOutput(ic *intel.Collection, args *intelArgs) {
 	var err error
 	dir := config.OutputDirectory(ic.Config.Dir)
@@ -260,8 +401,36 @@ func processIntelOutput(ic *intel.Collection, args *intelArgs) {
 		outptr.Seek(0, 0)
 	}
 
+	var wg sync.WaitGroup
+	writer := openIntelJSONOutput(args.Filepaths.JSONOutput)
+	if writer != nil {
+		defer func() {
+			wg.Wait()
+			writer.Close()
+		}()
+	}
+	// The ASNs and their netblocks arrive while the root domains are still being discovered
+	if writer != nil && ic.ASNOutput != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for req := range ic.ASNOutput {
+				if err := writer.WriteRecord(asnIntelRecord(req)); err != nil {
+					ic.Config.Log.Printf("Failed to write to the JSON output file: %v", err)
+				}
+			}
+		}()
+	}
+
 	// Collect all the names returned by the intelligence collection
 	for out := range ic.Output {
+		if writer != nil {
+			if err := writer.WriteRecord(domainIntelRecord(out)); err != nil {
+				ic.Config.Log.Printf("Failed to write to the JSON output file: %v", err)
+			}
+		}
+
 		source, name, ips := format.OutputLineParts(out, args.Options.Sources,
 			args.Options.IPs || args.Options.IPv4 || args.Options.IPv6, args.Options.DemoMode)
 
@@ -269,14 +438,31 @@ func processIntelOutput(ic *intel.Collection, args *intelArgs) {
 			ips = " " + ips
 		}
 
-		fmt.Fprintf(color.Output, "%s%s%s\n", blue(source), green(name), yellow(ips))
+		var reg string
+		if out.Lookalike != nil {
+			reg = " " + lookalikeLine(out.Lookalike)
+		}
+
+		fmt.Fprintf(color.Output, "%s%s%s%s\n", blue(source), green(name), yellow(ips), reg)
 		// Handle writing the line to a specified output file
 		if outptr != nil {
-			fmt.Fprintf(outptr, "%s%s%s\n", source, name, ips)
+			fmt.Fprintf(outptr, "%s%s%s%s\n", source, name, ips, reg)
 		}
 	}
 }
 
+func lookalikeLine(info *requests.LookalikeInfo) string {
+	parts := []string{"lookalike of " + info.Of}
+
+	if info.Registrar != "" {
+		parts = append(parts, "registrar: "+info.Registrar)
+	}
+	if !info.Created.IsZero() {
+		parts = append(parts, "created: "+info.Created.Format("2006-01-02"))
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
 // If the user interrupts the program, print the summary information
 func intelSignalHandler(ic *intel.Collection) {
 	quit := make(chan os.Signal, 1)
@@ -339,6 +525,9 @@ func (i intelArgs) OverrideConfig(conf *config.Config) error {
 	if len(i.CIDRs) > 0 {
 		conf.CIDRs = i.CIDRs
 	}
+	if len(i.Regions) > 0 {
+		conf.Regions = i.Regions
+	}
 	if len(i.Ports) > 0 {
 		conf.Ports = i.Ports
 	}
@@ -348,9 +537,26 @@ func (i intelArgs) OverrideConfig(conf *config.Config) error {
 	if i.MaxDNSQueries > 0 {
 		conf.MaxDNSQueries = i.MaxDNSQueries
 	}
+	if i.MinObservations > 0 {
+		conf.MinDomainObservations = i.MinObservations
+	}
+	if i.Options.ExcludeGenericRDNS {
+		conf.ExcludeGenericRDNS = true
+	}
 	if i.Timeout > 0 {
 		conf.Timeout = i.Timeout
 	}
+	if len(i.WhoisOrgs) > 0 {
+		conf.WhoisOrgs = i.WhoisOrgs
+	}
+	if len(i.WhoisEmails) > 0 {
+		for _, email := range i.WhoisEmails {
+			if !strings.Contains(email, "@") {
+				return fmt.Errorf("%s is not an email address", email)
+			}
+		}
+		conf.WhoisEmails = i.WhoisEmails
+	}
 	if i.Options.Verbose {
 		conf.Verbose = true
 	}
@@ -361,6 +567,18 @@ func (i intelArgs) OverrideConfig(conf *config.Config) error {
 		conf.MonitorResolverRate = false
 	}
 
+	if len(i.Categories) > 0 {
+		conf.SourceCategories.Enabled = nil
+		if err := conf.EnableSourceCategories(i.Categories.Slice()...); err != nil {
+			return err
+		}
+	}
+	if len(i.ExcludedCats) > 0 {
+		if err := conf.DisableSourceCategories(i.ExcludedCats.Slice()...); err != nil {
+			return err
+		}
+	}
+
 	if len(i.Included) > 0 {
 		conf.SourceFilter.Include = true
 		conf.SourceFilter.Sources = i.Included.Slice()
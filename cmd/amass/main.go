@@ -27,11 +27,15 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"net"
 	"os"
+	"os/signal"
 	"path"
 	"sort"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/OWASP/Amass/v3/config"
@@ -40,6 +44,7 @@ import (
 	"github.com/OWASP/Amass/v3/format"
 	"github.com/OWASP/Amass/v3/graph"
 	amassnet "github.com/OWASP/Amass/v3/net"
+	amasshttp "github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringfilter"
 	"github.com/OWASP/Amass/v3/systems"
@@ -47,12 +52,18 @@ import (
 )
 
 const (
-	mainUsageMsg         = "intel|enum|viz|track|db|dns [options]"
+	mainUsageMsg         = "intel|enum|viz|track|report|db|dns [options]"
 	exampleConfigFileURL = "https://github.com/OWASP/Amass/blob/master/examples/config.ini"
 	userGuideURL         = "https://github.com/OWASP/Amass/blob/master/doc/user_guide.md"
 	tutorialURL          = "https://github.com/OWASP/Amass/blob/master/doc/tutorial.md"
 )
 
+// The environment variables that select an HTTP cassette, which take precedence over the configuration.
+const (
+	cassetteEnv     = "AMASS_HTTP_CASSETTE"
+	cassetteModeEnv = "AMASS_HTTP_CASSETTE_MODE"
+)
+
 var (
 	// Colors used to ease the reading of program output
 	g      = color.New(color.FgHiGreen)
@@ -74,12 +85,13 @@ func commandUsage(msg string, cmdFlagSet *flag.FlagSet, errBuf *bytes.Buffer) {
 
 	if msg == mainUsageMsg {
 		g.Fprintf(color.Error, "\nSubcommands: \n\n")
-		g.Fprintf(color.Error, "\t%-11s - Discover targets for enumerations\n", "amass intel")
-		g.Fprintf(color.Error, "\t%-11s - Perform enumerations and network mapping\n", "amass enum")
-		g.Fprintf(color.Error, "\t%-11s - Visualize enumeration results\n", "amass viz")
-		g.Fprintf(color.Error, "\t%-11s - Track differences between enumerations\n", "amass track")
-		g.Fprintf(color.Error, "\t%-11s - Manipulate the Amass graph database\n", "amass db")
-		g.Fprintf(color.Error, "\t%-11s - Resolve DNS names at high performance\n\n", "amass dns")
+		g.Fprintf(color.Error, "\t%-12s - Discover targets for enumerations\n", "amass intel")
+		g.Fprintf(color.Error, "\t%-12s - Perform enumerations and network mapping\n", "amass enum")
+		g.Fprintf(color.Error, "\t%-12s - Visualize enumeration results\n", "amass viz")
+		g.Fprintf(color.Error, "\t%-12s - Track differences between enumerations\n", "amass track")
+		g.Fprintf(color.Error, "\t%-12s - Generate an HTML report of the findings\n", "amass report")
+		g.Fprintf(color.Error, "\t%-12s - Manipulate the Amass graph database\n", "amass db")
+		g.Fprintf(color.Error, "\t%-12s - Resolve DNS names at high performance\n\n", "amass dns")
 	}
 
 	g.Fprintf(color.Error, "The user's guide can be found here: \n%s\n\n", userGuideURL)
@@ -88,7 +100,7 @@ func commandUsage(msg string, cmdFlagSet *flag.FlagSet, errBuf *bytes.Buffer) {
 }
 
 func main() {
-	var version, help1, help2 bool
+	var version, capabilities, help1, help2 bool
 	mainFlagSet := flag.NewFlagSet("amass", flag.ContinueOnError)
 
 	defaultBuf := new(bytes.Buffer)
@@ -97,6 +109,7 @@ func main() {
 	mainFlagSet.BoolVar(&help1, "h", false, "Show the program usage message")
 	mainFlagSet.BoolVar(&help2, "help", false, "Show the program usage message")
 	mainFlagSet.BoolVar(&version, "version", false, "Print the version number of this Amass binary")
+	mainFlagSet.BoolVar(&capabilities, "capabilities", false, "Print the graph database systems supported by this binary")
 
 	if len(os.Args) < 2 {
 		commandUsage(mainUsageMsg, mainFlagSet, defaultBuf)
@@ -115,6 +128,20 @@ func main() {
 		fmt.Fprintf(color.Error, "%s\n", format.Version)
 		return
 	}
+	if capabilities {
+		for _, c := range graph.Capabilities() {
+			if c.Available {
+				g.Fprintf(color.Output, "%-12s available\n", c.System)
+			} else {
+				r.Fprintf(color.Output, "%-12s unavailable: %s\n", c.System, c.Reason)
+			}
+		}
+		return
+	}
+	if err := setupCassetteFromEnv(); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
 
 	switch os.Args[1] {
 	case "db":
@@ -125,6 +152,8 @@ func main() {
 		runEnumCommand(os.Args[2:])
 	case "intel":
 		runIntelCommand(os.Args[2:])
+	case "report":
+		runReportCommand(os.Args[2:])
 	case "track":
 		runTrackCommand(os.Args[2:])
 	case "viz":
@@ -135,6 +164,23 @@ func main() {
 	}
 }
 
+// setupCassetteFromEnv installs the HTTP cassette selected by the environment variables, so the
+// cassette from the configuration file is not used.
+func setupCassetteFromEnv() error {
+	path := os.Getenv(cassetteEnv)
+	if path == "" {
+		return nil
+	}
+
+	cassette, err := amasshttp.NewCassette(path, os.Getenv(cassetteModeEnv))
+	if err != nil {
+		return err
+	}
+
+	amasshttp.UseCassette(cassette)
+	return nil
+}
+
 // GetAllSourceInfo returns the output for the 'list' flag.
 func GetAllSourceInfo(cfg *config.Config) []string {
 	var names []string
@@ -209,18 +255,30 @@ func expandCategoryNames(names []string, categories map[string][]string) []strin
 	return newnames
 }
 
+// resolverSystem returns a system providing the resolvers from the configuration, or the default
+// resolvers, for the subcommands that send DNS queries outside of an enumeration.
+func resolverSystem(cfg *config.Config) (*systems.LocalSystem, error) {
+	rcfg := config.NewConfig()
+	rcfg.LocalDatabase = false
+	if len(cfg.Resolvers) > 0 {
+		rcfg.Resolvers = cfg.Resolvers
+	}
+
+	return systems.NewLocalSystem(rcfg)
+}
+
 func openGraphDatabase(dir string, cfg *config.Config) *graph.Graph {
 	for _, db := range cfg.GraphDBs {
 		if !db.Primary {
 			continue
 		}
 
-		cayley := graph.NewCayleyGraph(db.System, db.URL, db.Options)
-		if cayley == nil {
+		gdb, err := graph.NewGraphDatabase(db.System, db.URL, db.Username, db.Password, db.DBName, db.Options)
+		if err != nil {
 			return nil
 		}
 
-		g := graph.NewGraph(cayley)
+		g := graph.NewGraph(gdb)
 		if g == nil {
 			return nil
 		}
@@ -231,12 +289,12 @@ func openGraphDatabase(dir string, cfg *config.Config) *graph.Graph {
 	if db := cfg.LocalDatabaseSettings(cfg.GraphDBs); db != nil {
 		db.Options = ""
 
-		cayley := graph.NewCayleyGraph(db.System, config.OutputDirectory(dir), db.Options)
-		if cayley == nil {
+		gdb, err := graph.NewGraphDatabase(db.System, config.OutputDirectory(dir), "", "", "", db.Options)
+		if err != nil {
 			return nil
 		}
 
-		if g := graph.NewGraph(cayley); g != nil {
+		if g := graph.NewGraph(gdb); g != nil {
 			return g
 		}
 	}
@@ -301,13 +359,13 @@ func memGraphForScope(domains []string, from *graph.Graph) (*graph.Graph, error)
 		return nil, errors.New("Failed to create the in-memory graph database")
 	}
 
-	var err error
 	// Migrate the event data into the in-memory graph database
-	if len(domains) == 0 {
-		err = from.MigrateEvents(db)
-	} else {
-		err = from.MigrateEventsInScope(db, domains)
-	}
+	err := migrateWithProgress(func(ctx context.Context) error {
+		if len(domains) == 0 {
+			return from.MigrateEventsWithContext(ctx, db)
+		}
+		return from.MigrateEventsInScopeWithContext(ctx, db, domains)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("Failed to move the data into the in-memory graph database: %v", err)
 	}
@@ -315,16 +373,135 @@ func memGraphForScope(domains []string, from *graph.Graph) (*graph.Graph, error)
 	return db, nil
 }
 
+// migrateWithProgress executes the migration while showing the progress of migrations larger than a
+// single batch, and cancels the migration when the user interrupts the program.
+func migrateWithProgress(migrate func(ctx context.Context) error) error {
+	bus := eventbus.NewEventBus()
+	defer bus.Stop()
+
+	var lock sync.Mutex
+	var shown, finished bool
+	bus.Subscribe(requests.MigrateProgressTopic, func(p *graph.MigrateProgress) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if finished || p.Total <= graph.MigrateBatchSize {
+			return
+		}
+		shown = true
+		fgY.Fprintf(color.Error, "\rMigrating the data into the %s database: %d%%", p.Graph, p.Written*100/p.Total)
+	})
+
+	ctx, cancel := interruptContext(context.WithValue(context.Background(), requests.ContextEventBus, bus))
+	defer cancel()
+
+	err := migrate(ctx)
+
+	lock.Lock()
+	finished = true
+	if shown {
+		fmt.Fprintln(color.Error)
+	}
+	lock.Unlock()
+	return err
+}
+
+// interruptContext returns a context that is cancelled when the user interrupts the program, which permits
+// long database operations to stop cleanly. The cancel function must be called once the operation completes.
+func interruptContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-quit:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(quit)
+	}()
+	return ctx, cancel
+}
+
 func getEventOutput(uuids []string, asninfo bool, db *graph.Graph) []*requests.Output {
 	var output []*requests.Output
-	cache := amassnet.NewASNCache()
+
+	_ = eachEventOutput(uuids, asninfo, db, func(out *requests.Output) error {
+		output = append(output, out)
+		return nil
+	})
+	return output
+}
+
+// eachEventOutput provides the findings of the events, starting with the most recent, to the callback
+// as they are read from the graph database. The names already provided are not repeated.
+func eachEventOutput(uuids []string, asninfo bool, db *graph.Graph, callback func(*requests.Output) error) error {
+	cache := eventOutputCache(uuids, db)
 	filter := stringfilter.NewStringFilter()
 
 	for i := len(uuids) - 1; i >= 0; i-- {
-		output = append(output, db.EventOutput(uuids[i], filter, asninfo, cache)...)
+		if err := db.StreamEventOutput(context.Background(), uuids[i], filter, asninfo, cache, callback); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	return output
+// eventOutputCache returns the ASN cache used while obtaining the findings of the events.
+func eventOutputCache(uuids []string, db *graph.Graph) *amassnet.ASNCache {
+	cache := amassnet.NewASNCache()
+	// The infrastructure provided by the user takes precedence over the data sources
+	for _, r := range db.GroundTruth(uuids...) {
+		cache.Override(r)
+	}
+
+	return cache
+}
+
+// checkSummaryPaths returns an error when both variants of the enumeration summary would be
+// written to stdout, since the CSV rows and the JSON object cannot be told apart.
+func checkSummaryPaths(csvPath, jsonPath string) error {
+	if csvPath == "-" && jsonPath == "-" {
+		return errors.New("The -summary-csv and -summary-json flags cannot both write to stdout")
+	}
+	return nil
+}
+
+// writeSummaryFiles writes the machine-readable variants of the enumeration summary to the
+// paths provided, where - selects stdout.
+func writeSummaryFiles(csvPath, jsonPath string, total int, tags map[string]int, asns map[int]*format.ASNSummaryData) error {
+	write := func(path string, fn func(w io.Writer) error) error {
+		if path == "" {
+			return nil
+		} else if path == "-" {
+			return fn(os.Stdout)
+		}
+
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if err := fn(f); err != nil {
+			return err
+		}
+		return f.Sync()
+	}
+
+	if err := write(csvPath, func(w io.Writer) error {
+		return format.FprintEnumerationSummaryCSV(w, asns)
+	}); err != nil {
+		return fmt.Errorf("Failed to write the CSV summary: %v", err)
+	}
+
+	if err := write(jsonPath, func(w io.Writer) error {
+		return format.FprintEnumerationSummaryJSON(w, total, tags, asns)
+	}); err != nil {
+		return fmt.Errorf("Failed to write the JSON summary: %v", err)
+	}
+	return nil
 }
 
 func domainNameInScope(name string, scope []string) bool {
@@ -343,9 +520,19 @@ func domainNameInScope(name string, scope []string) bool {
 	return discovered
 }
 
-func healASInfo(uuids []string, db *graph.Graph) bool {
+func healASInfo(uuids []string, db *graph.Graph, c *config.Config) bool {
 	cache := amassnet.NewASNCache()
+	// The prefixes learned by previous executions are not requested from the data sources again
+	path := c.ASNCachePath()
+	if path != "" {
+		if _, err := cache.Load(path, time.Duration(c.ASNCacheTTL)*time.Minute); err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+		}
+	}
 	db.ASNCacheFill(cache)
+	for _, r := range db.GroundTruth(uuids...) {
+		cache.Override(r)
+	}
 
 	cfg := config.NewConfig()
 	cfg.LocalDatabase = false
@@ -388,32 +575,20 @@ func healASInfo(uuids []string, db *graph.Graph) bool {
 		}
 	}
 
+	if updated && path != "" {
+		if err := cache.Save(path); err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+		}
+	}
 	return updated
 }
 
 func assignNetInterface(iface *net.Interface) error {
-	addrs, err := iface.Addrs()
+	addr, err := amassnet.InterfaceAddr(iface)
 	if err != nil {
-		return fmt.Errorf("Network interface '%s' has no assigned addresses", iface.Name)
-	}
-
-	var best net.Addr
-	for _, addr := range addrs {
-		if a, ok := addr.(*net.IPNet); ok {
-			if best == nil {
-				best = a
-			}
-			if amassnet.IsIPv4(a.IP) {
-				best = a
-				break
-			}
-		}
-	}
-
-	if best == nil {
-		return fmt.Errorf("Network interface '%s' does not have assigned IP addresses", iface.Name)
+		return err
 	}
 
-	amassnet.LocalAddr = best
+	amassnet.LocalAddr = addr
 	return nil
 }
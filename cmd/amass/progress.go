@@ -0,0 +1,107 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/enum"
+)
+
+// progressLine keeps the progress of the enumeration on the last line of the terminal. The line
+// is erased before anything else is written to the terminal, and drawn again afterwards.
+type progressLine struct {
+	sync.Mutex
+	out     io.Writer
+	line    string
+	midline bool
+	stopped bool
+}
+
+func newProgressLine(out io.Writer) *progressLine {
+	return &progressLine{out: out}
+}
+
+// Writer returns a writer for w that keeps the progress line below the output.
+func (p *progressLine) Writer(w io.Writer) io.Writer {
+	return &progressWriter{
+		progress: p,
+		w:        w,
+	}
+}
+
+// Update replaces the progress line with the latest progress of the enumeration.
+func (p *progressLine) Update(prog *enum.Progress) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.stopped {
+		return
+	}
+	p.erase()
+	p.line = formatProgress(prog)
+	p.draw()
+}
+
+// Stop erases the progress line, which is not drawn again.
+func (p *progressLine) Stop() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.erase()
+	p.line = ""
+	p.stopped = true
+}
+
+func (p *progressLine) erase() {
+	if p.line != "" && !p.midline {
+		fmt.Fprint(p.out, "\r\033[K")
+	}
+}
+
+// The line is not drawn while other output has not finished the current line.
+func (p *progressLine) draw() {
+	if p.line != "" && !p.midline {
+		fmt.Fprint(p.out, p.line)
+	}
+}
+
+type progressWriter struct {
+	progress *progressLine
+	w        io.Writer
+}
+
+// Write implements the io.Writer interface.
+func (pw *progressWriter) Write(b []byte) (int, error) {
+	p := pw.progress
+
+	p.Lock()
+	defer p.Unlock()
+
+	p.erase()
+	n, err := pw.w.Write(b)
+	if len(b) > 0 {
+		p.midline = b[len(b)-1] != '\n'
+	}
+	p.draw()
+	return n, err
+}
+
+func formatProgress(p *enum.Progress) string {
+	eta := "unknown"
+	if p.ETA > 0 {
+		eta = "~" + p.ETA.Round(time.Second).String()
+	}
+
+	return fmt.Sprintf("%s%s %s%s %s%s %s%s %s%s",
+		blue("Names: "), yellow(strconv.Itoa(p.Names)),
+		blue("DNS: "), yellow(strconv.FormatInt(p.QueriesPerSec, 10)+"/sec"),
+		blue("Active sources: "), yellow(strconv.Itoa(p.ActiveSources)),
+		blue("Queues: "), yellow(fmt.Sprintf("%d names, %d DNS, %d data", p.NameQueue, p.DNSQueue, p.DataQueue)),
+		blue("Elapsed: "), yellow(p.Elapsed.Round(time.Second).String()+" (ETA "+eta+")"))
+}
@@ -0,0 +1,232 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/graph"
+	"github.com/OWASP/Amass/v3/notify"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/stringset"
+	"github.com/fatih/color"
+)
+
+const reportUsageMsg = "report [options] -d domain"
+
+type reportArgs struct {
+	Domains stringset.Set
+	Title   string
+	Options struct {
+		NoColor bool
+		Silent  bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+		Domains    string
+		Ignore     string
+		Output     string
+	}
+}
+
+func runReportCommand(clArgs []string) {
+	var args reportArgs
+	var help1, help2 bool
+	reportCommand := flag.NewFlagSet("report", flag.ContinueOnError)
+
+	args.Domains = stringset.New()
+
+	reportBuf := new(bytes.Buffer)
+	reportCommand.SetOutput(reportBuf)
+
+	reportCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	reportCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	reportCommand.Var(&args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	reportCommand.StringVar(&args.Title, "title", "", "The title shown at the top of the report")
+	reportCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	reportCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	reportCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	reportCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
+	reportCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
+	reportCommand.StringVar(&args.Filepaths.Ignore, "ignore", "", "Path to a file listing names, regexes and CIDRs excluded from the report")
+	reportCommand.StringVar(&args.Filepaths.Output, "o", "amass_report.html", "Path to the HTML file receiving the report")
+
+	if len(clArgs) < 1 {
+		commandUsage(reportUsageMsg, reportCommand, reportBuf)
+		return
+	}
+
+	if err := reportCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(reportUsageMsg, reportCommand, reportBuf)
+		return
+	}
+
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Output = ioutil.Discard
+		color.Error = ioutil.Discard
+	}
+
+	if args.Filepaths.Domains != "" {
+		list, err := config.GetListFromFile(args.Filepaths.Domains)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to parse the domain names file: %v\n", err)
+			os.Exit(1)
+		}
+		args.Domains.InsertMany(list...)
+	}
+	if args.Filepaths.Output == "" {
+		r.Fprintln(color.Error, "No path was provided for the report")
+		os.Exit(1)
+	}
+
+	il, err := loadIgnoreList(args.Filepaths.Ignore)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+
+	cfg := new(config.Config)
+	cfg.LocalDatabase = true
+	// Check if a configuration file was provided, and if so, load the settings
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
+		if args.Filepaths.Directory == "" {
+			args.Filepaths.Directory = cfg.Dir
+		}
+		if len(args.Domains) == 0 {
+			args.Domains.InsertMany(cfg.Domains()...)
+		}
+	} else if args.Filepaths.ConfigFile != "" {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+	if len(args.Domains) == 0 {
+		r.Fprintln(color.Error, "No root domain names were provided")
+		os.Exit(1)
+	}
+
+	report, err := engagementReport(&args, cfg, il)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+
+	if err := writeEngagementReport(args.Filepaths.Output, report); err != nil {
+		r.Fprintf(color.Error, "Failed to write the report: %v\n", err)
+		os.Exit(1)
+	}
+	g.Fprintf(color.Error, "The report for %d names was written to %s\n", len(report.Assets), args.Filepaths.Output)
+}
+
+// engagementReport builds the report for the most recent enumeration of the domains, which is
+// compared with the enumeration that preceded it when one is available.
+func engagementReport(args *reportArgs, cfg *config.Config, il *format.IgnoreList) (*notify.EngagementReport, error) {
+	// Connect with the graph database containing the enumeration data
+	db := openGraphDatabase(args.Filepaths.Directory, cfg)
+	if db == nil {
+		return nil, fmt.Errorf("Failed to connect with the database")
+	}
+	defer db.Close()
+	// The suppressed names are left out of the report until the suppressions expire
+	il = suppressNames(il, db)
+
+	// Create the in-memory graph database
+	memDB, err := memGraphForScope(args.Domains.Slice(), db)
+	if err != nil {
+		return nil, err
+	}
+	defer memDB.Close()
+
+	// Get all the UUIDs for events that have information in scope
+	uuids := eventUUIDs(args.Domains.Slice(), memDB)
+	if len(uuids) == 0 {
+		return nil, fmt.Errorf("Failed to find the domains of interest in the database")
+	}
+
+	var earliest, latest []time.Time
+	// Put the events in chronological order
+	uuids, earliest, latest = orderedEvents(uuids, memDB)
+	if len(uuids) == 0 {
+		return nil, fmt.Errorf("Failed to sort the events")
+	}
+
+	domains := args.Domains.Slice()
+	idx := len(uuids) - 1
+	current := reportOutput(uuids[idx], domains, memDB)
+
+	var previous []*requests.Output
+	if idx > 0 {
+		previous = reportOutput(uuids[idx-1], domains, memDB)
+		// The earlier enumeration is still compared when it found no names within the domains
+		if previous == nil {
+			previous = []*requests.Output{}
+		}
+	}
+
+	report := notify.NewEngagementReport(domains, current, previous, il)
+	report.Title = args.Title
+	report.Start, report.Finish = earliest[idx], latest[idx]
+	if idx > 0 {
+		report.PreviousStart, report.PreviousFinish = earliest[idx-1], latest[idx-1]
+	}
+	return report, nil
+}
+
+// reportOutput returns the findings of the enumeration within the domains. The infrastructure of the
+// addresses is included when the graph database provides it, without leaving out the other addresses.
+func reportOutput(uuid string, domains []string, db *graph.Graph) []*requests.Output {
+	infra := make(map[string]requests.AddressInfo)
+	for _, out := range getEventOutput([]string{uuid}, true, db) {
+		for _, a := range out.Addresses {
+			infra[a.Address.String()] = a
+		}
+	}
+
+	var output []*requests.Output
+	for _, out := range getEventOutput([]string{uuid}, false, db) {
+		if !domainNameInScope(out.Name, domains) {
+			continue
+		}
+
+		for i, a := range out.Addresses {
+			if info, found := infra[a.Address.String()]; found {
+				out.Addresses[i] = info
+			}
+		}
+		output = append(output, out)
+	}
+	return output
+}
+
+func writeEngagementReport(path string, report *notify.EngagementReport) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	if err := report.WriteHTML(w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
@@ -5,18 +5,28 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"os"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/format"
 	"github.com/OWASP/Amass/v3/graph"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/notify"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/fatih/color"
+	"golang.org/x/net/publicsuffix"
 )
 
 const (
@@ -24,19 +34,151 @@ const (
 	trackUsageMsg = "track [options] -d domain"
 )
 
+// The kinds of events written to the -json file for the changes to the assets
+const (
+	assetFound          = "asset-found"
+	assetMoved          = "asset-moved"
+	assetRemoved        = "asset-removed"
+	assetDecommissioned = "asset-decommissioned"
+)
+
+const (
+	// The number of names verified at the same time by -decommission
+	decommissionWorkers = 25
+	// How long the liveness probes wait for the addresses of a name to respond
+	decommissionTimeout = 3 * time.Second
+	// The number of events posted to the webhook URLs at the same time
+	webhookWorkers = 4
+)
+
+// trackEvent describes a change to an asset, and is written as one JSON object per line.
+type trackEvent struct {
+	Event     string   `json:"event"`
+	Name      string   `json:"name"`
+	Domain    string   `json:"domain"`
+	Addresses []string `json:"addresses,omitempty"`
+	Previous  []string `json:"previous_addresses,omitempty"`
+	Reason    string   `json:"reason,omitempty"`
+	Timestamp string   `json:"timestamp"`
+}
+
+// trackWriter prints the events on the terminal and to the -json file when one was provided,
+// and posts the events to the webhook URLs when they were configured.
+type trackWriter struct {
+	ctx   context.Context
+	enc   *json.Encoder
+	hooks *config.WebhookConfig
+	queue chan *trackEvent
+	wg    sync.WaitGroup
+}
+
+// newTrackWriter returns a trackWriter that posts the events from a bounded number of goroutines,
+// so the changes are reported without waiting for each delivery. The pending deliveries are
+// cancelled along with ctx.
+func newTrackWriter(ctx context.Context, hooks *config.WebhookConfig) *trackWriter {
+	w := &trackWriter{ctx: ctx, hooks: hooks}
+
+	if hooks != nil {
+		w.queue = make(chan *trackEvent, webhookWorkers)
+		for i := 0; i < webhookWorkers; i++ {
+			w.wg.Add(1)
+			go w.deliver()
+		}
+	}
+	return w
+}
+
+func (w *trackWriter) write(ev *trackEvent) {
+	fmt.Fprintln(color.Output, ev.line())
+
+	if w.enc != nil {
+		if err := w.enc.Encode(ev); err != nil {
+			r.Fprintf(color.Error, "Failed to write the JSON output: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if w.queue != nil {
+		select {
+		case w.queue <- ev:
+		case <-w.ctx.Done():
+		}
+	}
+}
+
+func (w *trackWriter) deliver() {
+	defer w.wg.Done()
+
+	for ev := range w.queue {
+		// The events still queued are dropped after an interruption
+		if w.ctx.Err() != nil {
+			continue
+		}
+
+		if err := notify.PostWebhooks(w.ctx, w.hooks, ev); err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+		}
+	}
+}
+
+// Close waits for the events already written to be posted to the webhook URLs.
+func (w *trackWriter) Close() {
+	if w.queue != nil {
+		close(w.queue)
+		w.wg.Wait()
+		w.queue = nil
+	}
+}
+
+func (ev *trackEvent) line() string {
+	addrs := strings.Join(ev.Addresses, ",")
+
+	switch ev.Event {
+	case assetFound:
+		return fmt.Sprintf("%s%s %s", blue("Found: "), green(ev.Name), yellow(addrs))
+	case assetMoved:
+		return fmt.Sprintf("%s%s\n\t%s\t%s\n\t%s\t%s", blue("Moved: "), green(ev.Name),
+			blue(" from "), yellow(strings.Join(ev.Previous, ",")), blue(" to "), yellow(addrs))
+	case assetRemoved:
+		return fmt.Sprintf("%s%s %s", blue("Removed: "), green(ev.Name), yellow(addrs))
+	}
+	return fmt.Sprintf("%s%s %s %s", blue("Decommissioned: "), green(ev.Name),
+		yellow(strings.Join(ev.Previous, ",")), red("("+ev.Reason+")"))
+}
+
+func newTrackEvent(kind string, o *requests.Output, at time.Time) *trackEvent {
+	domain := o.Domain
+	if domain == "" {
+		domain, _ = publicsuffix.EffectiveTLDPlusOne(o.Name)
+	}
+
+	return &trackEvent{
+		Event:     kind,
+		Name:      o.Name,
+		Domain:    domain,
+		Addresses: addressStrings(o.Addresses),
+		Timestamp: at.UTC().Format(time.RFC3339),
+	}
+}
+
 type trackArgs struct {
-	Domains stringset.Set
-	Last    int
-	Since   string
-	Options struct {
-		History bool
-		NoColor bool
-		Silent  bool
+	Domains  stringset.Set
+	Interval time.Duration
+	Last     int
+	Since    string
+	Webhooks format.ParseStrings
+	Options  struct {
+		Daemon       bool
+		Decommission bool
+		History      bool
+		NoColor      bool
+		Silent       bool
 	}
 	Filepaths struct {
 		ConfigFile string
 		Directory  string
 		Domains    string
+		Ignore     string
+		JSONOutput string
 	}
 }
 
@@ -53,14 +195,20 @@ func runTrackCommand(clArgs []string) {
 	trackCommand.BoolVar(&help1, "h", false, "Show the program usage message")
 	trackCommand.BoolVar(&help2, "help", false, "Show the program usage message")
 	trackCommand.Var(&args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
+	trackCommand.DurationVar(&args.Interval, "interval", 24*time.Hour, "Time between the start of the enumerations in daemon mode")
 	trackCommand.IntVar(&args.Last, "last", 0, "The number of recent enumerations to include in the tracking")
 	trackCommand.StringVar(&args.Since, "since", "", "Exclude all enumerations before (format: "+timeFormat+")")
+	trackCommand.Var(&args.Webhooks, "webhook", "URL receiving each change as a JSON POST request (can be used multiple times)")
+	trackCommand.BoolVar(&args.Options.Daemon, "daemon", false, "Repeatedly enumerate the domains and report only the changes found by each enumeration")
+	trackCommand.BoolVar(&args.Options.Decommission, "decommission", false, "Resolve the previously confirmed names and report those no longer resolving or responding")
 	trackCommand.BoolVar(&args.Options.History, "history", false, "Show the difference between all enumeration pairs")
 	trackCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
 	trackCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
 	trackCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
 	trackCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
 	trackCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
+	trackCommand.StringVar(&args.Filepaths.Ignore, "ignore", "", "Path to a file listing names, regexes and CIDRs excluded from the changes")
+	trackCommand.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the file receiving the changes as one JSON event per line")
 
 	if len(clArgs) < 1 {
 		commandUsage(trackUsageMsg, trackCommand, trackBuf)
@@ -93,6 +241,18 @@ func runTrackCommand(clArgs []string) {
 		r.Fprintln(color.Error, "Tracking requires more than one enumeration")
 		os.Exit(1)
 	}
+	if args.Options.Daemon && args.Options.History {
+		r.Fprintln(color.Error, "The history flag cannot be used with the daemon flag")
+		os.Exit(1)
+	}
+	if args.Options.Daemon && (args.Since != "" || args.Last != 0) {
+		r.Fprintln(color.Error, "The since and last flags cannot be used with the daemon flag")
+		os.Exit(1)
+	}
+	if args.Options.Daemon && args.Interval <= 0 {
+		r.Fprintln(color.Error, "The interval must be a positive duration, such as 24h")
+		os.Exit(1)
+	}
 	if args.Filepaths.Domains != "" {
 		list, err := config.GetListFromFile(args.Filepaths.Domains)
 		if err != nil {
@@ -106,7 +266,12 @@ func runTrackCommand(clArgs []string) {
 		os.Exit(1)
 	}
 
-	var err error
+	il, err := loadIgnoreList(args.Filepaths.Ignore)
+	if err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+
 	var start time.Time
 	if args.Since != "" {
 		start, err = time.Parse(timeFormat, args.Since)
@@ -133,44 +298,93 @@ func runTrackCommand(clArgs []string) {
 		os.Exit(1)
 	}
 
+	for _, u := range args.Webhooks {
+		if err := cfg.AddWebhookURL(u); err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	ctx, cancel := interruptContext(context.Background())
+	defer cancel()
+
+	w := newTrackWriter(ctx, cfg.Webhooks)
+	defer w.Close()
+	if path := args.Filepaths.JSONOutput; path != "" {
+		jsonfile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to open the JSON output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer func() {
+			jsonfile.Sync()
+			jsonfile.Close()
+		}()
+
+		w.enc = json.NewEncoder(jsonfile)
+	}
+
+	if args.Options.Daemon {
+		runTrackDaemon(ctx, &args, cfg, il, start, w)
+		return
+	}
+	if err := trackChanges(&args, cfg, il, start, "", w); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		w.Close()
+		os.Exit(1)
+	}
+}
+
+// trackChanges reports the differences between the enumerations selected by the arguments. When current
+// identifies an enumeration, nothing is reported unless it is the most recent enumeration in scope, and
+// it is only compared with the enumeration before it.
+func trackChanges(args *trackArgs, cfg *config.Config, il *format.IgnoreList, start time.Time, current string, w *trackWriter) error {
 	// Connect with the graph database containing the enumeration data
 	db := openGraphDatabase(args.Filepaths.Directory, cfg)
 	if db == nil {
-		r.Fprintln(color.Error, "Failed to connect with the database")
-		os.Exit(1)
+		return fmt.Errorf("Failed to connect with the database")
 	}
 	defer db.Close()
 
+	return trackGraphChanges(args, cfg, il, start, current, db, w)
+}
+
+func trackGraphChanges(args *trackArgs, cfg *config.Config, il *format.IgnoreList,
+	start time.Time, current string, db *graph.Graph, w *trackWriter) error {
+	// The suppressed names are not reported until the suppressions expire
+	il = suppressNames(il, db)
+
 	// Create the in-memory graph database
 	memDB, err := memGraphForScope(args.Domains.Slice(), db)
 	if err != nil {
-		r.Fprintln(color.Error, err.Error())
-		os.Exit(1)
+		return err
 	}
+	defer memDB.Close()
 
 	// Get all the UUIDs for events that have information in scope
 	uuids := eventUUIDs(args.Domains.Slice(), memDB)
 	if len(uuids) == 0 {
-		r.Fprintln(color.Error, "Failed to find the domains of interest in the database")
-		os.Exit(1)
+		return fmt.Errorf("Failed to find the domains of interest in the database")
 	}
 
 	var earliest, latest []time.Time
 	// Put the events in chronological order
 	uuids, earliest, latest = orderedEvents(uuids, memDB)
 	if len(uuids) == 0 {
-		r.Fprintln(color.Error, "Failed to sort the events")
-		os.Exit(1)
+		return fmt.Errorf("Failed to sort the events")
 	}
-
-	// The default is to use all the enumerations available
-	if args.Last == 0 {
-		args.Last = len(uuids)
+	if current != "" && uuids[len(uuids)-1] != current {
+		return fmt.Errorf("The enumeration did not discover any names within the domains of interest")
 	}
 
 	var begin int
-	// Filter out enumerations that begin before the start date/time
-	if args.Since != "" {
+	if current != "" {
+		// The enumeration is only compared with the one before it, so each change is reported once
+		begin = 2
+		if begin > len(uuids) {
+			begin = len(uuids)
+		}
+	} else if args.Since != "" { // Filter out enumerations that begin before the start date/time
 		for i := len(uuids) - 1; i >= 0; i-- {
 			if earliest[i].Before(start) {
 				break
@@ -178,12 +392,16 @@ func runTrackCommand(clArgs []string) {
 			begin++
 		}
 	} else { // Or the number of enumerations from the end of the timeline
-		if args.Last > len(uuids) {
-			r.Fprintf(color.Error, "%d enumerations are not available\n", args.Last)
-			os.Exit(1)
+		// The default is to use all the enumerations available
+		last := args.Last
+		if last == 0 {
+			last = len(uuids)
+		}
+		if last > len(uuids) {
+			return fmt.Errorf("%d enumerations are not available", last)
 		}
 
-		begin = args.Last
+		begin = last
 	}
 	begin = len(uuids) - begin
 	uuids = uuids[begin:]
@@ -191,16 +409,24 @@ func runTrackCommand(clArgs []string) {
 	latest = latest[begin:]
 
 	if len(uuids) == 1 {
-		printOneEvent(uuids, args.Domains.Slice(), earliest[0], latest[0], memDB)
-		return
+		printOneEvent(uuids, args.Domains.Slice(), earliest[0], latest[0], il, memDB, w)
 	} else if args.Options.History {
-		completeHistoryOutput(uuids, args.Domains.Slice(), earliest, latest, memDB)
-		return
+		completeHistoryOutput(uuids, args.Domains.Slice(), earliest, latest, il, memDB, w)
+	} else {
+		cumulativeOutput(uuids, args.Domains.Slice(), earliest, latest, il, memDB, w)
+	}
+
+	if args.Options.Decommission {
+		confirmed := getScopedOutput(uuids, args.Domains.Slice(), memDB)
+
+		if err := decommissionOutput(confirmed, il, cfg, db, w); err != nil {
+			return err
+		}
 	}
-	cumulativeOutput(uuids, args.Domains.Slice(), earliest, latest, memDB)
+	return nil
 }
 
-func printOneEvent(uuid, domains []string, earliest, latest time.Time, db *graph.Graph) {
+func printOneEvent(uuid, domains []string, earliest, latest time.Time, il *format.IgnoreList, db *graph.Graph, w *trackWriter) {
 	one := getScopedOutput(uuid, domains, db)
 
 	blueLine()
@@ -209,12 +435,12 @@ func printOneEvent(uuid, domains []string, earliest, latest time.Time, db *graph
 		blue("and"), yellow(earliest.Format(timeFormat)), blue(" -> "), yellow(latest.Format(timeFormat)))
 	blueLine()
 
-	for _, d := range diffEnumOutput([]*requests.Output{}, one) {
-		fmt.Fprintln(color.Output, d)
+	for _, ev := range diffEnumOutput([]*requests.Output{}, one, il, latest) {
+		w.write(ev)
 	}
 }
 
-func cumulativeOutput(uuids, domains []string, ea, la []time.Time, db *graph.Graph) {
+func cumulativeOutput(uuids, domains []string, ea, la []time.Time, il *format.IgnoreList, db *graph.Graph, w *trackWriter) {
 	idx := len(uuids) - 1
 	cum := getScopedOutput(uuids[:idx], domains, db)
 
@@ -226,9 +452,9 @@ func cumulativeOutput(uuids, domains []string, ea, la []time.Time, db *graph.Gra
 
 	var updates bool
 	out := getScopedOutput([]string{uuids[idx]}, domains, db)
-	for _, d := range diffEnumOutput(cum, out) {
+	for _, ev := range diffEnumOutput(cum, out, il, la[idx]) {
 		updates = true
-		fmt.Fprintln(color.Output, d)
+		w.write(ev)
 	}
 	if !updates {
 		g.Println("No differences discovered")
@@ -249,7 +475,7 @@ func getScopedOutput(uuids, domains []string, db *graph.Graph) []*requests.Outpu
 	return output
 }
 
-func completeHistoryOutput(uuids, domains []string, ea, la []time.Time, db *graph.Graph) {
+func completeHistoryOutput(uuids, domains []string, ea, la []time.Time, il *format.IgnoreList, db *graph.Graph, w *trackWriter) {
 	var prev string
 
 	for i, uuid := range uuids {
@@ -270,9 +496,9 @@ func completeHistoryOutput(uuids, domains []string, ea, la []time.Time, db *grap
 		var updates bool
 		out1 := getScopedOutput([]string{prev}, domains, db)
 		out2 := getScopedOutput([]string{uuid}, domains, db)
-		for _, d := range diffEnumOutput(out1, out2) {
+		for _, ev := range diffEnumOutput(out1, out2, il, la[i]) {
 			updates = true
-			fmt.Fprintln(color.Output, d)
+			w.write(ev)
 		}
 		if !updates {
 			g.Println("No differences discovered")
@@ -288,7 +514,8 @@ func blueLine() {
 	fmt.Println()
 }
 
-func diffEnumOutput(older, newer []*requests.Output) []string {
+// The changes to assets on the ignore list are not reported, since they are expected every time.
+func diffEnumOutput(older, newer []*requests.Output, il *format.IgnoreList, at time.Time) []*trackEvent {
 	oldmap := make(map[string]*requests.Output)
 	newmap := make(map[string]*requests.Output)
 
@@ -299,32 +526,188 @@ func diffEnumOutput(older, newer []*requests.Output) []string {
 		newmap[o.Name] = o
 	}
 
-	var diff []string
+	var diff []*trackEvent
 	for name, o := range newmap {
+		if il.Ignored(o) {
+			continue
+		}
+
 		o2, found := oldmap[name]
 		if !found {
-			diff = append(diff, fmt.Sprintf("%s%s %s", blue("Found: "),
-				green(name), yellow(lineOfAddresses(o.Addresses))))
+			diff = append(diff, newTrackEvent(assetFound, o, at))
 			continue
 		}
 
-		if !compareAddresses(o.Addresses, o2.Addresses) {
-			diff = append(diff, fmt.Sprintf("%s%s\n\t%s\t%s\n\t%s\t%s", blue("Moved: "),
-				green(name), blue(" from "), yellow(lineOfAddresses(o2.Addresses)),
-				blue(" to "), yellow(lineOfAddresses(o.Addresses))))
+		if !compareAddresses(o.Addresses, o2.Addresses) && !il.Ignored(o2) {
+			ev := newTrackEvent(assetMoved, o, at)
+			ev.Previous = addressStrings(o2.Addresses)
+			diff = append(diff, ev)
 		}
 	}
 
 	for name, o := range oldmap {
-		if _, found := newmap[name]; !found {
-			diff = append(diff, fmt.Sprintf("%s%s %s", blue("Removed: "),
-				green(name), yellow(lineOfAddresses(o.Addresses))))
+		if _, found := newmap[name]; !found && !il.Ignored(o) {
+			diff = append(diff, newTrackEvent(assetRemoved, o, at))
 		}
 	}
 
 	return diff
 }
 
+// decommissionOutput resolves each of the previously confirmed names again and reports the assets that
+// stopped resolving, along with those that no longer respond on any address after responding to the
+// liveness probes of earlier enumerations. Names that could not be resolved conclusively, such as when
+// the queries timed out, are not reported, so inventory systems can retire the assets from the events.
+func decommissionOutput(confirmed []*requests.Output, il *format.IgnoreList, cfg *config.Config, db *graph.Graph, w *trackWriter) error {
+	sys, err := resolverSystem(cfg)
+	if err != nil {
+		return err
+	}
+	defer sys.Shutdown()
+
+	ports := cfg.LivenessPorts
+	if len(ports) == 0 {
+		ports = config.NewConfig().LivenessPorts
+	}
+
+	ctx, cancel := interruptContext(context.Background())
+	defer cancel()
+
+	var names []*requests.Output
+	for _, o := range confirmed {
+		if len(o.Addresses) > 0 && !il.Ignored(o) {
+			names = append(names, o)
+		}
+	}
+
+	fmt.Println()
+	blueLine()
+	fmt.Fprintf(color.Output, "%s\t%s\n", blue("Verified"), yellow(fmt.Sprintf("%d previously confirmed names", len(names))))
+	blueLine()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var unverified int
+	var events []*trackEvent
+	sem := make(chan struct{}, decommissionWorkers)
+	for _, o := range names {
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func(o *requests.Output) {
+			defer func() { <-sem; wg.Done() }()
+
+			reason, verified := decommissionReason(ctx, o, sys.Pool(), ports, db)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !verified {
+				unverified++
+			} else if reason != "" {
+				ev := newTrackEvent(assetDecommissioned, o, time.Now())
+				ev.Previous, ev.Addresses = ev.Addresses, nil
+				ev.Reason = reason
+				events = append(events, ev)
+			}
+		}(o)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("The verification of the names was interrupted")
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Name < events[j].Name })
+	for _, ev := range events {
+		w.write(ev)
+	}
+	if len(events) == 0 {
+		g.Println("No decommissioned assets discovered")
+	}
+	if unverified > 0 {
+		fgY.Fprintf(color.Error, "%d names could not be verified due to DNS failures\n", unverified)
+	}
+	return nil
+}
+
+// decommissionReason returns why the asset has been decommissioned, or the empty string when it remains in
+// service. False is returned when the DNS queries failed, since the name could still be resolving.
+func decommissionReason(ctx context.Context, o *requests.Output, pool resolvers.Resolver, ports []int, db *graph.Graph) (string, bool) {
+	addrs, confirmed := resolvers.NameAddresses(ctx, pool, o.Name)
+	if !confirmed {
+		return "", false
+	}
+	if len(addrs) == 0 {
+		return "stopped resolving", true
+	}
+
+	// Only the names that responded before can show that their services went unreachable
+	var responded bool
+	for _, addr := range o.Addresses {
+		if alive, _ := db.AddressLiveness(addr.Address.String()); alive {
+			responded = true
+			break
+		}
+	}
+	if !responded {
+		return "", true
+	}
+
+	var probed bool
+	for _, addr := range addrs {
+		if yes, _ := amassnet.IsReservedAddress(addr); yes {
+			continue
+		}
+
+		probed = true
+		if amassnet.ProbeLiveness(ctx, addr, ports, decommissionTimeout).Alive() {
+			return "", true
+		}
+	}
+	if !probed || ctx.Err() != nil {
+		return "", true
+	}
+	return "unreachable", true
+}
+
+// suppressNames adds the names suppressed in the graph database to the ignore list.
+func suppressNames(il *format.IgnoreList, db *graph.Graph) *format.IgnoreList {
+	var names []string
+	for _, s := range db.Suppressions(time.Now()) {
+		names = append(names, s.Name)
+	}
+
+	if len(names) == 0 {
+		return il
+	}
+	return il.AddNames(names...)
+}
+
+func loadIgnoreList(path string) (*format.IgnoreList, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open the ignore list file: %v", err)
+	}
+	defer f.Close()
+
+	return format.ReadIgnoreList(f)
+}
+
+func addressStrings(addrs []requests.AddressInfo) []string {
+	var list []string
+
+	for _, addr := range addrs {
+		if addr.Address != nil {
+			list = append(list, addr.Address.String())
+		}
+	}
+	return list
+}
+
 func lineOfAddresses(addrs []requests.AddressInfo) string {
 	var line string
 
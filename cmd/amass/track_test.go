@@ -0,0 +1,122 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/graph"
+	"github.com/OWASP/Amass/v3/stringset"
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+)
+
+func TestTrackChangesDaemonCycles(t *testing.T) {
+	color.Output = ioutil.Discard
+
+	db := graph.NewGraph(graph.NewCayleyGraphMemory())
+	defer db.Close()
+
+	args := &trackArgs{Domains: stringset.New("example.com")}
+	args.Options.Daemon = true
+
+	cycles := [][]string{
+		{"www.example.com", "mail.example.com"},
+		{"www.example.com"},
+		{"www.example.com"},
+	}
+	var removed []int
+	for i, names := range cycles {
+		// The events are ordered by their start times, which have a resolution of one second
+		if i > 0 {
+			time.Sleep(1100 * time.Millisecond)
+		}
+
+		id := uuid.New().String()
+		if _, err := db.InsertEvent(id); err != nil {
+			t.Fatalf("Failed to insert the event: %v", err)
+		}
+		for _, name := range names {
+			if err := db.InsertA(name, "192.0.2.1", "DNS", "dns", id); err != nil {
+				t.Fatalf("Failed to insert %s: %v", name, err)
+			}
+		}
+
+		var buf bytes.Buffer
+		w := &trackWriter{enc: json.NewEncoder(&buf)}
+		if err := trackGraphChanges(args, new(config.Config), nil, time.Time{}, id, db, w); err != nil {
+			t.Fatalf("Failed to track the changes of enumeration %d: %v", i+1, err)
+		}
+
+		dec := json.NewDecoder(&buf)
+		for dec.More() {
+			var ev trackEvent
+
+			if err := dec.Decode(&ev); err != nil {
+				t.Fatalf("Failed to decode the event: %v", err)
+			}
+			if ev.Event == assetRemoved {
+				if ev.Name != "mail.example.com" {
+					t.Errorf("%s was reported as removed", ev.Name)
+				}
+				removed = append(removed, i+1)
+			}
+		}
+	}
+
+	if len(removed) != 1 || removed[0] != 2 {
+		t.Errorf("The removal was reported after the enumerations %v", removed)
+	}
+}
+
+func TestTrackWriterWebhooks(t *testing.T) {
+	color.Output = ioutil.Discard
+
+	var lock sync.Mutex
+	received := make(map[string]bool)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev trackEvent
+
+		if err := json.NewDecoder(r.Body).Decode(&ev); err == nil {
+			lock.Lock()
+			received[ev.Name] = true
+			lock.Unlock()
+		}
+	}))
+	defer ts.Close()
+
+	hooks := &config.WebhookConfig{URLs: []string{ts.URL}}
+	names := []string{"a.example.com", "b.example.com", "c.example.com", "d.example.com", "e.example.com", "f.example.com"}
+
+	w := newTrackWriter(context.Background(), hooks)
+	for _, name := range names {
+		w.write(&trackEvent{Event: assetFound, Name: name})
+	}
+	w.Close()
+	if len(received) != len(names) {
+		t.Errorf("%d of the %d events were posted before the writer was closed", len(received), len(names))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	received = make(map[string]bool)
+	w = newTrackWriter(ctx, hooks)
+	for _, name := range names {
+		w.write(&trackEvent{Event: assetFound, Name: name})
+	}
+	w.Close()
+	if len(received) != 0 {
+		t.Errorf("%d events were posted after the cancellation", len(received))
+	}
+}
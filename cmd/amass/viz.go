@@ -5,7 +5,10 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"flag"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"math/rand"
 	"os"
@@ -13,23 +16,26 @@ import (
 	"time"
 
 	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/format"
 	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/OWASP/Amass/v3/viz"
 	"github.com/fatih/color"
 )
 
 const (
-	vizUsageMsg = "viz -d3|-dot||-gexf|-graphistry|-maltego [options]"
+	vizUsageMsg = "viz -d3|-dot||-gexf|-graphistry|-html|-maltego [options]"
 )
 
 type vizArgs struct {
 	Domains stringset.Set
-	Enum    int
+	Enums   format.ParseInts
 	Options struct {
+		AllEnums   bool
 		D3         bool
 		DOT        bool
 		GEXF       bool
 		Graphistry bool
+		HTML       bool
 		Maltego    bool
 		NoColor    bool
 		Silent     bool
@@ -56,7 +62,8 @@ func runVizCommand(clArgs []string) {
 	vizCommand.BoolVar(&help1, "h", false, "Show the program usage message")
 	vizCommand.BoolVar(&help2, "help", false, "Show the program usage message")
 	vizCommand.Var(&args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
-	vizCommand.IntVar(&args.Enum, "enum", 0, "Identify an enumeration via an index from the listing")
+	vizCommand.Var(&args.Enums, "enum", "Enumeration indexes from the listing separated by commas (can be used multiple times)")
+	vizCommand.BoolVar(&args.Options.AllEnums, "all", false, "Include all the enumerations in a merged visualization")
 	vizCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
 	vizCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
 	vizCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
@@ -66,7 +73,8 @@ func runVizCommand(clArgs []string) {
 	vizCommand.BoolVar(&args.Options.DOT, "dot", false, "Generate the DOT output file")
 	vizCommand.BoolVar(&args.Options.GEXF, "gexf", false, "Generate the Gephi Graph Exchange XML Format (GEXF) file")
 	vizCommand.BoolVar(&args.Options.Graphistry, "graphistry", false, "Generate the Graphistry JSON file")
-	vizCommand.BoolVar(&args.Options.Maltego, "maltego", false, "Generate the Maltego csv file")
+	vizCommand.BoolVar(&args.Options.HTML, "html", false, "Generate the interactive HTML file with search, node type filtering and zoom")
+	vizCommand.BoolVar(&args.Options.Maltego, "maltego", false, "Generate the Maltego graph (MTGX) file")
 	vizCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
 	vizCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
 
@@ -94,11 +102,16 @@ func runVizCommand(clArgs []string) {
 
 	// Make sure at least one graph file format has been identified on the command-line
 	if !args.Options.D3 && !args.Options.DOT &&
-		!args.Options.GEXF && !args.Options.Graphistry && !args.Options.Maltego {
+		!args.Options.GEXF && !args.Options.Graphistry && !args.Options.HTML && !args.Options.Maltego {
 		r.Fprintln(color.Error, "At least one file format must be selected")
 		os.Exit(1)
 	}
 
+	if args.Options.AllEnums && len(args.Enums) > 0 {
+		r.Fprintln(color.Error, "The enum flag cannot be used with the all flag")
+		os.Exit(1)
+	}
+
 	if args.Filepaths.Domains != "" {
 		list, err := config.GetListFromFile(args.Filepaths.Domains)
 		if err != nil {
@@ -124,6 +137,10 @@ func runVizCommand(clArgs []string) {
 		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
 		os.Exit(1)
 	}
+	// The files kept in the output directory, such as the ASN cache, follow the database
+	if args.Filepaths.Directory != "" {
+		cfg.Dir = args.Filepaths.Directory
+	}
 
 	db := openGraphDatabase(args.Filepaths.Directory, cfg)
 	if db == nil {
@@ -153,23 +170,22 @@ func runVizCommand(clArgs []string) {
 		os.Exit(1)
 	}
 
-	selected := len(uuids) - 1
-	// Select the enumeration that the user specified
-	if args.Enum > 0 && len(uuids) > args.Enum {
-		selected = args.Enum
+	// Select the enumerations that the user specified
+	uuids, err = selectedEvents(uuids, args.Enums, args.Options.AllEnums)
+	if err != nil {
+		r.Fprintln(color.Error, err.Error())
+		os.Exit(1)
 	}
-	uuids = []string{uuids[selected]}
 
 	// Need to check if all the network infrastructure information is available
 	fgY.Fprintln(color.Error, "Could take a moment while acquiring AS network information")
 	// Migrate the changes back to the persistent db
-	if healASInfo(uuids, memDB) {
-		memDB.MigrateEvents(db, uuids...)
+	if healASInfo(uuids, memDB, cfg) {
+		migrateWithProgress(func(ctx context.Context) error {
+			return memDB.MigrateEventsWithContext(ctx, db, uuids...)
+		})
 	}
 
-	// Obtain the visualization nodes & edges from the graph
-	nodes, edges := memDB.VizData(uuids[0])
-
 	// Get the directory to save the files into
 	dir := args.Filepaths.Directory
 	if args.Filepaths.Output != "" {
@@ -181,89 +197,71 @@ func runVizCommand(clArgs []string) {
 		dir = args.Filepaths.Output
 	}
 
-	if args.Options.D3 {
-		path := filepath.Join(dir, "amass_d3.html")
-		writeD3File(path, nodes, edges)
-	}
-	if args.Options.DOT {
-		path := filepath.Join(dir, "amass.dot")
-		writeDOTData(path, nodes, edges)
-	}
-	if args.Options.GEXF {
-		path := filepath.Join(dir, "amass.gexf")
-		writeGEXFFile(path, nodes, edges)
-	}
-	if args.Options.Graphistry {
-		path := filepath.Join(dir, "amass_graphistry.json")
-		writeGraphistryFile(path, nodes, edges)
-	}
-	if args.Options.Maltego {
-		path := filepath.Join(dir, "amass_maltego.csv")
-		writeMaltegoFile(path, nodes, edges)
-	}
-}
-
-func writeMaltegoFile(path string, nodes []viz.Node, edges []viz.Edge) {
-	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
-	if err != nil {
-		return
-	}
-	defer f.Close()
+	var files []*os.File
+	var writers []viz.Writer
+	for _, format := range []struct {
+		selected bool
+		filename string
+		writer   func(io.Writer) viz.Writer
+	}{
+		{args.Options.D3, "amass_d3.html", func(w io.Writer) viz.Writer { return viz.NewD3Writer(w, uuids) }},
+		{args.Options.DOT, "amass.dot", func(w io.Writer) viz.Writer { return viz.NewDOTWriter(w, uuids) }},
+		{args.Options.GEXF, "amass.gexf", func(w io.Writer) viz.Writer { return viz.NewGEXFWriter(w, uuids) }},
+		{args.Options.Graphistry, "amass_graphistry.json", func(w io.Writer) viz.Writer { return viz.NewGraphistryWriter(w, uuids) }},
+		{args.Options.HTML, "amass.html", func(w io.Writer) viz.Writer { return viz.NewHTMLWriter(w, uuids) }},
+		{args.Options.Maltego, "amass.mtgx", viz.NewMaltegoWriter},
+	} {
+		if !format.selected {
+			continue
+		}
 
-	f.Truncate(0)
-	f.Seek(0, 0)
-	viz.WriteMaltegoData(f, nodes, edges)
-	f.Sync()
-}
+		path := filepath.Join(dir, format.filename)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to open the output file %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		defer f.Close()
 
-func writeGraphistryFile(path string, nodes []viz.Node, edges []viz.Edge) {
-	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
-	if err != nil {
-		return
+		files = append(files, f)
+		writers = append(writers, format.writer(f))
 	}
-	defer f.Close()
-
-	f.Truncate(0)
-	f.Seek(0, 0)
-	viz.WriteGraphistryData(f, nodes, edges)
-	f.Sync()
-}
 
-func writeGEXFFile(path string, nodes []viz.Node, edges []viz.Edge) {
-	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
-	if err != nil {
-		return
+	// Stream the visualization nodes & edges from the graph into the files
+	if err := memDB.StreamVizData(viz.NewMultiWriter(writers...), uuids...); err != nil {
+		r.Fprintf(color.Error, "Failed to write the visualizations: %v\n", err)
+		os.Exit(1)
 	}
-	defer f.Close()
 
-	f.Truncate(0)
-	f.Seek(0, 0)
-	viz.WriteGEXFData(f, nodes, edges)
-	f.Sync()
+	for _, f := range files {
+		f.Sync()
+	}
 }
 
-func writeD3File(path string, nodes []viz.Node, edges []viz.Edge) {
-	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
-	if err != nil {
-		return
+// selectedEvents returns the chronologically ordered events identified by the listing indexes.
+// The most recent enumeration is selected when no indexes are provided.
+func selectedEvents(uuids []string, indexes []int, all bool) ([]string, error) {
+	if all {
+		return uuids, nil
+	}
+	if len(indexes) == 0 {
+		return []string{uuids[len(uuids)-1]}, nil
 	}
-	defer f.Close()
-
-	f.Truncate(0)
-	f.Seek(0, 0)
-	viz.WriteD3Data(f, nodes, edges)
-	f.Sync()
-}
 
-func writeDOTData(path string, nodes []viz.Node, edges []viz.Edge) {
-	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
-	if err != nil {
-		return
+	positions := make(map[int]struct{})
+	for _, idx := range indexes {
+		if idx < 1 || idx > len(uuids) {
+			return nil, fmt.Errorf("Enumeration %d is not available in the listing", idx)
+		}
+		// The listing numbers the enumerations starting with the most recent
+		positions[len(uuids)-idx] = struct{}{}
 	}
-	defer f.Close()
 
-	f.Truncate(0)
-	f.Seek(0, 0)
-	viz.WriteDOTData(f, nodes, edges)
-	f.Sync()
+	var selected []string
+	for pos, uuid := range uuids {
+		if _, found := positions[pos]; found {
+			selected = append(selected, uuid)
+		}
+	}
+	return selected, nil
 }
@@ -0,0 +1,35 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/go-ini/ini"
+)
+
+// The queries per second sent to each authoritative server when a rate has not been provided.
+const defaultAuthoritativeRate = 10
+
+func (c *Config) loadAuthoritativeSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("authoritative")
+	if err != nil {
+		return nil
+	}
+
+	c.Authoritative = sec.Key("enabled").MustBool(true)
+	if !c.Authoritative {
+		return nil
+	}
+
+	if sec.HasKey("rate") {
+		rate, err := sec.Key("rate").Int()
+		if err != nil || rate <= 0 {
+			return fmt.Errorf("The authoritative rate %s is not a positive number of queries per second",
+				sec.Key("rate").String())
+		}
+		c.AuthoritativeRate = rate
+	}
+	return nil
+}
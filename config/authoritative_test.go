@@ -0,0 +1,59 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestAuthoritativeSettings(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[authoritative]
+		rate = 25
+		`),
+	)
+
+	if err := c.loadAuthoritativeSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the authoritative settings: %v", err)
+	}
+	if !c.Authoritative || c.AuthoritativeRate != 25 {
+		t.Errorf("The authoritative settings were loaded as %t and %d", c.Authoritative, c.AuthoritativeRate)
+	}
+
+	if err := c.CheckSettings(); err == nil {
+		t.Errorf("Authoritative queries were accepted without active enumeration")
+	}
+	c.Active = true
+	if err := c.CheckSettings(); err != nil {
+		t.Errorf("Authoritative queries were not accepted during active enumeration: %v", err)
+	}
+
+	for _, rate := range []string{"0", "-5", "fast"} {
+		cfg, _ = ini.LoadSources(
+			ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			},
+			[]byte("[authoritative]\nrate = "+rate),
+		)
+
+		if err := c.loadAuthoritativeSettings(cfg); err == nil {
+			t.Errorf("Failed to report an error for the authoritative rate %s", rate)
+		}
+	}
+
+	c = NewConfig()
+	if c.Authoritative || c.AuthoritativeRate != defaultAuthoritativeRate {
+		t.Errorf("The default authoritative settings were %t and %d", c.Authoritative, c.AuthoritativeRate)
+	}
+}
@@ -5,11 +5,53 @@ package config
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/OWASP/Amass/v3/stringset"
+	"github.com/OWASP/Amass/v3/wordlist"
 	"github.com/go-ini/ini"
 )
 
+// The largest range of years accepted by the year_suffix setting
+const maxYearSuffixes = 50
+
+// BruteWordlist returns the brute forcing wordlist after applying the transforms selected for
+// the domain containing the name, or the transforms of the bruteforce section otherwise.
+func (c *Config) BruteWordlist(name string) []string {
+	name = strings.ToLower(strings.Trim(name, "."))
+
+	t := c.BruteTransforms
+	var longest int
+	for domain, dt := range c.DomainBruteTransforms {
+		if (name == domain || strings.HasSuffix(name, "."+domain)) && len(domain) > longest {
+			t, longest = dt, len(domain)
+		}
+	}
+	return t.Apply(c.Wordlist)
+}
+
+// ParseMinForDepth returns the depth and the number of discoveries from a value such as 2:5, which
+// requires five names discovered within a subdomain two labels below the root domain before it is
+// brute forced.
+func ParseMinForDepth(value string) (int, int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("%s is not in the DEPTH:COUNT format", value)
+	}
+
+	depth, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || depth < 1 {
+		return 0, 0, fmt.Errorf("%s does not provide a depth of at least one", value)
+	}
+
+	min, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil || min < 0 {
+		return 0, 0, fmt.Errorf("%s does not provide a valid number of discoveries", value)
+	}
+	return depth, min, nil
+}
+
 func (c *Config) loadBruteForceSettings(cfg *ini.File) error {
 	bruteforce, err := cfg.GetSection("bruteforce")
 	if err != nil {
@@ -23,6 +65,23 @@ func (c *Config) loadBruteForceSettings(cfg *ini.File) error {
 
 	c.Recursive = bruteforce.Key("recursive").MustBool(true)
 	c.MinForRecursive = bruteforce.Key("minimum_for_recursive").MustInt(0)
+	c.MaxDepth = bruteforce.Key("max_depth").MustInt(0)
+	if c.MaxDepth < 0 {
+		return fmt.Errorf("The bruteforce max_depth setting must not be negative: %d", c.MaxDepth)
+	}
+
+	if bruteforce.HasKey("minimum_for_depth") {
+		for _, v := range bruteforce.Key("minimum_for_depth").ValueWithShadows() {
+			depth, min, err := ParseMinForDepth(v)
+			if err != nil {
+				return fmt.Errorf("The bruteforce minimum_for_depth setting: %v", err)
+			}
+			if c.MinForDepth == nil {
+				c.MinForDepth = make(map[int]int)
+			}
+			c.MinForDepth[depth] = min
+		}
+	}
 
 	if bruteforce.HasKey("wordlist_file") {
 		for _, wordlist := range bruteforce.Key("wordlist_file").ValueWithShadows() {
@@ -35,9 +94,102 @@ func (c *Config) loadBruteForceSettings(cfg *ini.File) error {
 	}
 
 	c.Wordlist = stringset.Deduplicate(c.Wordlist)
+
+	t, err := loadBruteTransforms(bruteforce)
+	if err != nil {
+		return fmt.Errorf("The bruteforce section: %v", err)
+	}
+	c.BruteTransforms = t
+
+	for _, child := range bruteforce.ChildSections() {
+		domain := strings.ToLower(strings.Trim(strings.TrimPrefix(child.Name(), "bruteforce."), "."))
+		if domain == "" {
+			continue
+		}
+
+		t, err := loadBruteTransforms(child)
+		if err != nil {
+			return fmt.Errorf("The bruteforce transforms for %s: %v", domain, err)
+		}
+		if c.DomainBruteTransforms == nil {
+			c.DomainBruteTransforms = make(map[string]*wordlist.Transforms)
+		}
+		c.DomainBruteTransforms[domain] = t
+	}
 	return nil
 }
 
+func loadBruteTransforms(sec *ini.Section) (*wordlist.Transforms, error) {
+	t := &wordlist.Transforms{
+		SplitCamelCase: sec.Key("split_camel_case").MustBool(false),
+	}
+
+	if sec.HasKey("separator") {
+		seen := stringset.New()
+
+		for _, value := range sec.Key("separator").ValueWithShadows() {
+			var sep string
+
+			switch strings.ToLower(strings.TrimSpace(value)) {
+			case "dash", "-":
+				sep = "-"
+			case "underscore", "_":
+				sep = "_"
+			case "none", "":
+				sep = ""
+			default:
+				return nil, fmt.Errorf("The separator %s is not dash, underscore or none", value)
+			}
+
+			// The order of the separators is kept, so the preferred style is tried first
+			if !seen.Has(sep) {
+				seen.Insert(sep)
+				t.Separators = append(t.Separators, sep)
+			}
+		}
+	}
+
+	if sec.HasKey("year_suffix") {
+		for _, value := range sec.Key("year_suffix").ValueWithShadows() {
+			years, err := parseYearRange(value)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, year := range years {
+				t.Years = uniqueIntAppend(t.Years, strconv.Itoa(year))
+			}
+		}
+	}
+	return t, nil
+}
+
+// parseYearRange accepts a single year or an inclusive range, such as 2019-2023.
+func parseYearRange(value string) ([]int, error) {
+	parts := strings.SplitN(strings.TrimSpace(value), "-", 2)
+
+	min, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || min <= 0 {
+		return nil, fmt.Errorf("The year suffix %s is not a year or range of years", value)
+	}
+	max := min
+	if len(parts) == 2 {
+		max, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || max < min {
+			return nil, fmt.Errorf("The year suffix %s is not a year or range of years", value)
+		}
+	}
+	if max-min >= maxYearSuffixes {
+		return nil, fmt.Errorf("The year suffix %s exceeds %d years", value, maxYearSuffixes)
+	}
+
+	var years []int
+	for y := min; y <= max; y++ {
+		years = append(years, y)
+	}
+	return years, nil
+}
+
 func (c *Config) loadAlterationSettings(cfg *ini.File) error {
 	alterations, err := cfg.GetSection("alterations")
 	if err != nil {
@@ -0,0 +1,107 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestBruteTransformSettings(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[bruteforce]
+		separator = dash
+		separator = none
+		year_suffix = 2020-2021
+
+		[bruteforce.example.com]
+		separator = underscore
+		split_camel_case = true
+		year_suffix = 2022
+		`),
+	)
+
+	if err := c.loadBruteForceSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the bruteforce settings: %v", err)
+	}
+	if !reflect.DeepEqual(c.BruteTransforms.Separators, []string{"-", ""}) {
+		t.Errorf("The separators were loaded as %v", c.BruteTransforms.Separators)
+	}
+	if !reflect.DeepEqual(c.BruteTransforms.Years, []int{2020, 2021}) {
+		t.Errorf("The year suffixes were loaded as %v", c.BruteTransforms.Years)
+	}
+
+	c.Wordlist = []string{"devApi"}
+	if got := c.BruteWordlist("www.example.com"); !reflect.DeepEqual(got, []string{"dev_api", "dev_api2022", "dev_api_2022"}) {
+		t.Errorf("The domain transforms produced %v", got)
+	}
+	if got := c.BruteWordlist("owasp.org"); !reflect.DeepEqual(got, []string{"devapi", "devapi2020", "devapi-2020", "devapi2021", "devapi-2021"}) {
+		t.Errorf("The bruteforce transforms produced %v", got)
+	}
+
+	for _, setting := range []string{"separator = dot", "year_suffix = 2021-2019", "year_suffix = 1900-2000", "year_suffix = soon"} {
+		cfg, _ = ini.LoadSources(
+			ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			},
+			[]byte("[bruteforce]\n"+setting),
+		)
+
+		if err := NewConfig().loadBruteForceSettings(cfg); err == nil {
+			t.Errorf("Failed to report an error for the setting %s", setting)
+		}
+	}
+}
+
+func TestBruteDepthSettings(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[bruteforce]
+		minimum_for_recursive = 1
+		max_depth = 3
+		minimum_for_depth = 2:5
+		minimum_for_depth = 3 : 10
+		`),
+	)
+
+	if err := c.loadBruteForceSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the bruteforce settings: %v", err)
+	}
+	if c.MaxDepth != 3 {
+		t.Errorf("The maximum depth was loaded as %d", c.MaxDepth)
+	}
+	if !reflect.DeepEqual(c.MinForDepth, map[int]int{2: 5, 3: 10}) {
+		t.Errorf("The minimums for each depth were loaded as %v", c.MinForDepth)
+	}
+
+	for _, setting := range []string{"max_depth = -1", "minimum_for_depth = 2", "minimum_for_depth = 0:5", "minimum_for_depth = 2:-1"} {
+		cfg, _ = ini.LoadSources(
+			ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			},
+			[]byte("[bruteforce]\n"+setting),
+		)
+
+		if err := NewConfig().loadBruteForceSettings(cfg); err == nil {
+			t.Errorf("Failed to report an error for the setting %s", setting)
+		}
+	}
+}
@@ -64,12 +64,27 @@ type Config struct {
 	// Alternative directory for scripts provided by the user
 	ScriptsDirectory string `ini:"scripts_directory"`
 
+	// Alternative directory for Go plugins that provide additional data sources
+	PluginsDirectory string `ini:"plugins_directory"`
+
+	// A YAML or CSV file mapping names and CIDRs to the owner, environment and criticality of the assets
+	ContextFile string `ini:"context_file"`
+
 	// Use a local graph database
 	LocalDatabase bool
 
 	// The graph databases used by the system / enumerations
 	GraphDBs []*Database
 
+	// The number of days that enumerations are kept in the graph databases (zero keeps them all)
+	RetentionDays int
+
+	// The number of the most recent enumerations kept for each root domain (zero keeps them all)
+	RetentionEvents int
+
+	// Will the retention policy be enforced before each enumeration starts?
+	PruneOnStart bool
+
 	// The maximum number of concurrent DNS queries
 	MaxDNSQueries int `ini:"maximum_dns_queries"`
 
@@ -100,6 +115,16 @@ type Config struct {
 	// Minimum number of subdomain discoveries before performing recursive brute forcing
 	MinForRecursive int
 
+	// The most labels below the root domain that recursive brute forcing reaches, or zero for no limit
+	MaxDepth int
+
+	// The subdomain discoveries required at specific depths, which take precedence over MinForRecursive
+	MinForDepth map[int]int
+
+	// The variations of the wordlist entries tried when brute forcing, and those selected for specific domains
+	BruteTransforms       *wordlist.Transforms
+	DomainBruteTransforms map[string]*wordlist.Transforms
+
 	// Will discovered subdomain name alterations be generated?
 	Alterations    bool
 	FlipWords      bool
@@ -110,31 +135,131 @@ type Config struct {
 	EditDistance   int
 	AltWordlist    []string
 
+	// The maximum number of names accepted from each data source per root domain (zero is no limit)
+	SampleSize int
+
 	// Only access the data sources for names and return results?
 	Passive bool
 
+	// Will a sampled passive pass tune the brute forcing, alterations and concurrency of the active phase?
+	Preflight bool
+
+	// Will data sources that support it only request results since the previous execution?
+	Monitor bool
+
 	// Determines if zone transfers will be attempted
 	Active bool
 
+	// Country codes or regional Internet registries that restrict the ASN and netblock discovery
+	Regions []string
+
+	// Will resolved addresses be probed to learn which ones are responding?
+	Liveness bool
+
+	// The TCP ports used when probing addresses for liveness
+	LivenessPorts []int
+
+	// Will the web servers at resolved addresses be probed for virtual hosts lacking DNS records?
+	VHosts bool
+
+	// The TCP ports of the web servers probed for virtual hosts
+	VHostPorts []int
+
+	// The labels combined with the domain names when probing for virtual hosts
+	VHostWordlist []string
+
+	// The number of addresses in the netblocks that must reference a root domain before intel returns it
+	MinDomainObservations int
+
+	// Will intel ignore the reverse DNS names generated by providers for whole address ranges?
+	ExcludeGenericRDNS bool
+
+	// Additional expressions matching the reverse DNS names ignored by intel
+	GenericRDNSPatterns []*regexp.Regexp
+
+	// The registrant organizations and email addresses that intel searches for using reverse whois
+	WhoisOrgs   []string
+	WhoisEmails []string
+
 	// A blacklist of subdomain names that will not be investigated
 	Blacklist []string
 
+	// Expressions matching the discovered names that will not be resolved or entered into the graph
+	ExcludedNames []*regexp.Regexp
+
 	// A list of data sources that should not be utilized
 	SourceFilter struct {
 		Include bool // true = include, false = exclude
 		Sources []string
 	}
 
+	// The data source categories exclusively used, and the categories that should not be utilized
+	SourceCategories struct {
+		Enabled  []string
+		Disabled []string
+	}
+
 	// The minimum number of minutes that data source responses will be reused
 	MinimumTTL int
 
+	// The default HTTP/HTTPS proxy used by data sources lacking their own proxy setting
+	Proxy string
+
+	// The number of minutes that data source HTTP responses are cached on disk (zero disables the cache)
+	HTTPCacheTTL int
+
+	// The number of minutes that the ASN and netblock information is cached on disk (zero disables the cache)
+	ASNCacheTTL int
+
+	// The file where data source HTTP interactions are recorded or replayed from, and the mode used
+	HTTPCassette     string
+	HTTPCassetteMode string
+
+	// The number of seconds before a data source HTTP request is abandoned
+	SourceTimeout int
+
+	// The number of times a data source HTTP request is sent again after a transient failure
+	SourceRetries int
+
+	// The number of milliseconds waited before the first retry, doubled for each following retry
+	SourceRetryBackoff int
+
+	// The fraction of each retry wait that is randomly added or removed
+	SourceRetryJitter float64
+
+	// The HTTP status codes that cause data source requests to be retried
+	SourceRetryCodes []int
+
+	// The maximum number of names accepted from each data source per root domain (zero disables the cap)
+	MaxSourceResults int
+
+	// Names are quarantined once a data source provides this many times its usual volume for a domain
+	SourceAnomalyFactor int
+
 	// Type of DNS records to query for
 	RecordTypes []string
 
+	// The DNS wildcard answers declared for zones, which are filtered without probing
+	Wildcards []*WildcardConfig
+
 	// Resolver settings
 	Resolvers           []string
 	MonitorResolverRate bool
 
+	// Will the names within each zone be resolved by the authoritative servers of the zone?
+	Authoritative bool
+
+	// The maximum number of queries per second sent to each authoritative server
+	AuthoritativeRate int
+
+	// The network interface or source IP address that the DNS and HTTP traffic is sent from
+	Interface     string
+	SourceAddress net.IP
+
+	// The range of local ports used for DNS queries (zero lets the operating system select the ports)
+	DNSPortMin int
+	DNSPortMax int
+
 	// Enumeration Timeout
 	Timeout int
 
@@ -147,6 +272,15 @@ type Config struct {
 	// The regular expressions for the root domains added to the enumeration
 	regexps map[string]*regexp.Regexp
 
+	// The executables that act as data sources
+	ExternalSources []*ExternalSourceConfig
+
+	// The scheduled summary emailed after enumerations, or nil when not configured
+	EmailReport *EmailReportConfig
+
+	// The URLs receiving the changes reported by the track subcommand, or nil when not configured
+	Webhooks *WebhookConfig
+
 	// The data source configurations
 	datasrcConfigs map[string]*DataSourceConfig
 }
@@ -157,11 +291,20 @@ func NewConfig() *Config {
 		UUID:                uuid.New(),
 		Log:                 log.New(ioutil.Discard, "", 0),
 		Ports:               []int{443},
+		LivenessPorts:       []int{80, 443},
+		VHostPorts:          []int{80, 443},
 		MaxDNSQueries:       defaultConcurrentDNSQueries,
 		MinForRecursive:     1,
 		Resolvers:           defaultPublicResolvers,
 		MonitorResolverRate: true,
+		AuthoritativeRate:   defaultAuthoritativeRate,
 		LocalDatabase:       true,
+		SourceAnomalyFactor: 100,
+		SourceTimeout:       defaultSourceTimeout,
+		SourceRetries:       defaultSourceRetries,
+		SourceRetryBackoff:  defaultSourceRetryBackoff,
+		SourceRetryJitter:   defaultSourceRetryJitter,
+		SourceRetryCodes:    append([]int(nil), amasshttp.DefaultRetryStatusCodes...),
 		// The following is enum-only, but intel will just ignore them anyway
 		Alterations:    true,
 		FlipWords:      true,
@@ -171,6 +314,9 @@ func NewConfig() *Config {
 		MinForWordFlip: 2,
 		EditDistance:   1,
 		Recursive:      true,
+
+		// Intel returns the root domains referenced by a single address
+		MinDomainObservations: 1,
 	}
 
 	return c
@@ -188,16 +334,42 @@ func (c *Config) CheckSettings() error {
 	if c.BruteForcing {
 		if c.Passive {
 			return errors.New("Brute forcing cannot be performed without DNS resolution")
+		} else if c.SampleSize > 0 {
+			return errors.New("Brute forcing cannot be performed while sampling the data sources")
 		} else if len(c.Wordlist) == 0 {
-			c.Wordlist, err = getWordlistByFS("/namelist.txt")
+			c.Wordlist, err = DefaultWordlist()
 			if err != nil {
 				return err
 			}
 		}
 	}
+	if c.Preflight {
+		if c.Passive {
+			return errors.New("The preflight tunes DNS resolution that passive enumerations do not perform")
+		} else if c.SampleSize > 0 {
+			return errors.New("The preflight cannot be used while sampling the data sources")
+		}
+	}
 	if c.Passive && c.Active {
 		return errors.New("Active enumeration cannot be performed without DNS resolution")
 	}
+	if c.Passive && c.Liveness {
+		return errors.New("Liveness probes cannot be performed without DNS resolution")
+	}
+	if c.VHosts {
+		if !c.Active {
+			return errors.New("Virtual host probing can only be performed during active enumerations")
+		} else if len(c.VHostWordlist) == 0 {
+			c.VHostWordlist = append([]string(nil), defaultVHostWordlist...)
+		}
+	}
+	if c.Authoritative {
+		if !c.Active {
+			return errors.New("Queries can only be sent to the authoritative servers during active enumerations")
+		} else if c.AuthoritativeRate <= 0 {
+			c.AuthoritativeRate = defaultAuthoritativeRate
+		}
+	}
 	if c.Alterations {
 		if len(c.AltWordlist) == 0 {
 			c.AltWordlist, err = getWordlistByFS("/alterations.txt")
@@ -245,11 +417,21 @@ func (c *Config) LoadSettings(path string) error {
 
 	loads := []func(cfg *ini.File) error{
 		c.loadResolverSettings,
+		c.loadAuthoritativeSettings,
+		c.loadNetworkSettings,
 		c.loadScopeSettings,
 		c.loadAlterationSettings,
 		c.loadBruteForceSettings,
+		c.loadLivenessSettings,
+		c.loadVHostSettings,
 		c.loadDatabaseSettings,
 		c.loadDataSourceSettings,
+		c.loadExternalSourceSettings,
+		c.loadEmailReportSettings,
+		c.loadWebhookSettings,
+		c.loadWildcardSettings,
+		c.loadIntelSettings,
+		c.loadRetentionSettings,
 	}
 	for _, load := range loads {
 		if err := load(cfg); err != nil {
@@ -342,6 +524,11 @@ func getWordlistByURL(url string) ([]string, error) {
 	return getWordList(strings.NewReader(page))
 }
 
+// DefaultWordlist returns the embedded wordlist used for brute forcing when none was provided.
+func DefaultWordlist() ([]string, error) {
+	return getWordlistByFS("/namelist.txt")
+}
+
 func getWordlistByFS(path string) ([]string, error) {
 	fsOnce.Do(openTheFS)
 
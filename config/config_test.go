@@ -8,6 +8,8 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+
+	"github.com/go-ini/ini"
 )
 
 func TestCheckSettings(t *testing.T) {
@@ -103,6 +105,48 @@ func TestBlacklist(t *testing.T) {
 	}
 }
 
+func TestExcludedNames(t *testing.T) {
+	c := NewConfig()
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[scope]
+		port = 443
+		[scope.blacklisted]
+		subdomain = dev.example.com
+		regex = .*\.cdn\.example\.com
+		regex = edge-[0-9]+\.example\.com
+		`),
+	)
+
+	if err := c.loadScopeSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the scope settings: %v", err)
+	}
+	if len(c.ExcludedNames) != 2 {
+		t.Fatalf("%d excluded name expressions were loaded instead of 2", len(c.ExcludedNames))
+	}
+
+	for _, name := range []string{"a1.cdn.example.com", "X.CDN.example.com", "edge-42.example.com", "www.dev.example.com"} {
+		if !c.Blacklisted(name) {
+			t.Errorf("%s was not excluded", name)
+		}
+	}
+	// The expressions must match the entire name
+	for _, name := range []string{"cdn.example.com", "a1.cdn.example.com.evil.net", "www.edge-42.example.com"} {
+		if c.Excluded(name) {
+			t.Errorf("%s was excluded", name)
+		}
+	}
+
+	bad, _ := ini.LoadSources(ini.LoadOptions{Insensitive: true, AllowShadows: true}, []byte("[scope]\n[scope.blacklisted]\nregex = ([a-z]"))
+	if err := NewConfig().loadScopeSettings(bad); err == nil {
+		t.Errorf("The invalid excluded name expression was accepted")
+	}
+}
+
 func TestLoadSettings(t *testing.T) {
 	c := NewConfig()
 	path := "../examples/config.ini"
@@ -6,17 +6,65 @@ package config
 import (
 	"fmt"
 	"math/rand"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	amasshttp "github.com/OWASP/Amass/v3/net/http"
+	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/go-ini/ini"
 )
 
+const (
+	// The number of seconds that data source HTTP requests are given when not configured
+	defaultSourceTimeout = 60
+
+	// The retry settings applied to data source HTTP requests when not configured
+	defaultSourceRetries      = 2
+	defaultSourceRetryBackoff = 1000
+	defaultSourceRetryJitter  = 0.2
+
+	// The longest wait between two attempts of a data source HTTP request
+	maxSourceRetryBackoff = 30 * time.Second
+
+	// The file in the output directory that persists the ASN and netblock information
+	asnCacheFileName = "asn_cache.json"
+)
+
+// ASNCachePath returns the file that persists the ASN and netblock information between
+// executions, or an empty string when the cache is disabled.
+func (c *Config) ASNCachePath() string {
+	if c.ASNCacheTTL <= 0 {
+		return ""
+	}
+
+	dir := OutputDirectory(c.Dir)
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, asnCacheFileName)
+}
+
 // DataSourceConfig contains the configurations specific to a data source.
 type DataSourceConfig struct {
 	Name  string
-	TTL   int `ini:"ttl"`
-	creds map[string]*Credentials
+	TTL   int    `ini:"ttl"`
+	Proxy string `ini:"proxy"`
+	// The base URL of the service, such as for self-hosted instances of the data source
+	URL string `ini:"url"`
+	// Overrides the max_results value of the data_sources section when set
+	MaxResults int `ini:"max_results"`
+	// Overrides the timeout value of the data_sources section when set
+	Timeout int `ini:"timeout"`
+	// Overrides the retries value of the data_sources section when set, and -1 disables retries
+	Retries int `ini:"retries"`
+	creds   map[string]*Credentials
+	// All the keys in the section, which includes the settings specific to the data source
+	settings map[string]string
 }
 
 // Credentials contains values required for authenticating with web APIs.
@@ -26,6 +74,28 @@ type Credentials struct {
 	Password string `ini:"password"`
 	Key      string `ini:"apikey"`
 	Secret   string `ini:"secret"`
+	// The OAuth2 client credentials, used by APIs that issue access tokens
+	TokenURL     string `ini:"token_url"`
+	ClientID     string `ini:"client_id"`
+	ClientSecret string `ini:"client_secret"`
+	// The OAuth2 scopes requested, separated by spaces or commas
+	Scope string `ini:"scope"`
+}
+
+// OAuth2 returns the OAuth2 client credentials, or nil when the token URL was not provided.
+func (cr *Credentials) OAuth2() *amasshttp.ClientCredentials {
+	if cr == nil || cr.TokenURL == "" {
+		return nil
+	}
+
+	return &amasshttp.ClientCredentials{
+		TokenURL:     cr.TokenURL,
+		ClientID:     cr.ClientID,
+		ClientSecret: cr.ClientSecret,
+		Scopes: strings.FieldsFunc(cr.Scope, func(r rune) bool {
+			return r == ' ' || r == ','
+		}),
+	}
 }
 
 // GetDataSourceConfig returns the DataSourceConfig associated with the data source name argument.
@@ -49,6 +119,90 @@ func (c *Config) GetDataSourceConfig(source string) *DataSourceConfig {
 	return c.datasrcConfigs[key]
 }
 
+// DataSourceMaxResults returns the maximum number of names accepted from the named data
+// source for each root domain. Zero is returned when the number is not limited.
+func (c *Config) DataSourceMaxResults(source string) int {
+	if dsc := c.GetDataSourceConfig(source); dsc != nil && dsc.MaxResults > 0 {
+		return dsc.MaxResults
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	return c.MaxSourceResults
+}
+
+// DataSourceTimeout returns the amount of time that HTTP requests sent by the named data source are given.
+func (c *Config) DataSourceTimeout(source string) time.Duration {
+	if dsc := c.GetDataSourceConfig(source); dsc != nil && dsc.Timeout > 0 {
+		return time.Duration(dsc.Timeout) * time.Second
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.SourceTimeout <= 0 {
+		return defaultSourceTimeout * time.Second
+	}
+	return time.Duration(c.SourceTimeout) * time.Second
+}
+
+// DataSourceRetryPolicy returns the policy for sending the HTTP requests of the named
+// data source again after transient failures, or nil when the requests are not retried.
+func (c *Config) DataSourceRetryPolicy(source string) *amasshttp.RetryPolicy {
+	var retries int
+	if dsc := c.GetDataSourceConfig(source); dsc != nil && dsc.Retries != 0 {
+		retries = dsc.Retries
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if retries == 0 {
+		retries = c.SourceRetries
+	}
+	if retries <= 0 {
+		return nil
+	}
+
+	return &amasshttp.RetryPolicy{
+		Retries:     retries,
+		Backoff:     time.Duration(c.SourceRetryBackoff) * time.Millisecond,
+		MaxBackoff:  maxSourceRetryBackoff,
+		Jitter:      c.SourceRetryJitter,
+		StatusCodes: append([]int(nil), c.SourceRetryCodes...),
+	}
+}
+
+// DataSourceProxy returns the HTTP/HTTPS proxy URL that should be used by the named data source.
+// An empty string is returned when the requests should be sent without a proxy.
+func (c *Config) DataSourceProxy(source string) string {
+	if dsc := c.GetDataSourceConfig(source); dsc != nil && dsc.Proxy != "" {
+		return dsc.Proxy
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	return c.Proxy
+}
+
+// Setting returns the value of the key from the configuration section of the data source,
+// or an empty string when the key was not provided.
+func (dsc *DataSourceConfig) Setting(key string) string {
+	return dsc.settings[strings.ToLower(key)]
+}
+
+// Settings returns a copy of all the keys from the configuration section of the data source.
+func (dsc *DataSourceConfig) Settings() map[string]string {
+	settings := make(map[string]string, len(dsc.settings))
+
+	for k, v := range dsc.settings {
+		settings[k] = v
+	}
+	return settings
+}
+
 // AddCredentials adds the Credentials provided to the configuration.
 func (dsc *DataSourceConfig) AddCredentials(cred *Credentials) error {
 	if cred == nil || cred.Name == "" {
@@ -87,31 +241,234 @@ func (c *Config) loadDataSourceSettings(cfg *ini.File) error {
 		}
 	}
 
+	if sec.HasKey("http_cache_ttl") {
+		if ttl, err := sec.Key("http_cache_ttl").Int(); err == nil {
+			c.HTTPCacheTTL = ttl
+		}
+	}
+
+	if sec.HasKey("asn_cache_ttl") {
+		if ttl, err := sec.Key("asn_cache_ttl").Int(); err == nil {
+			c.ASNCacheTTL = ttl
+		}
+	}
+
+	if sec.HasKey("max_results") {
+		if max, err := sec.Key("max_results").Int(); err == nil {
+			c.MaxSourceResults = max
+		}
+	}
+
+	if sec.HasKey("timeout") {
+		if timeout, err := sec.Key("timeout").Int(); err == nil && timeout > 0 {
+			c.SourceTimeout = timeout
+		}
+	}
+
+	if sec.HasKey("retries") {
+		if retries, err := sec.Key("retries").Int(); err == nil && retries >= 0 {
+			c.SourceRetries = retries
+		}
+	}
+
+	if sec.HasKey("retry_backoff") {
+		if backoff, err := sec.Key("retry_backoff").Int(); err == nil && backoff >= 0 {
+			c.SourceRetryBackoff = backoff
+		}
+	}
+
+	if sec.HasKey("retry_jitter") {
+		if jitter, err := sec.Key("retry_jitter").Float64(); err == nil && jitter >= 0 && jitter <= 1 {
+			c.SourceRetryJitter = jitter
+		}
+	}
+
+	if sec.HasKey("retry_status_codes") {
+		var codes []int
+
+		for _, v := range strings.Split(sec.Key("retry_status_codes").String(), ",") {
+			if v = strings.TrimSpace(v); v == "" {
+				continue
+			}
+
+			code, err := strconv.Atoi(v)
+			if err != nil || code < 100 || code > 599 {
+				return fmt.Errorf("The retry_status_codes value %s is not a valid HTTP status code", v)
+			}
+			codes = append(codes, code)
+		}
+		c.SourceRetryCodes = codes
+	}
+
+	if sec.HasKey("anomaly_factor") {
+		if factor, err := sec.Key("anomaly_factor").Int(); err == nil {
+			c.SourceAnomalyFactor = factor
+		}
+	}
+
+	if sec.HasKey("cassette") {
+		c.HTTPCassette = sec.Key("cassette").String()
+	}
+
+	if sec.HasKey("cassette_mode") {
+		mode := strings.ToLower(strings.TrimSpace(sec.Key("cassette_mode").String()))
+		if mode != amasshttp.CassetteRecord && mode != amasshttp.CassetteReplay {
+			return fmt.Errorf("The cassette_mode value must be %s or %s", amasshttp.CassetteRecord, amasshttp.CassetteReplay)
+		}
+		c.HTTPCassetteMode = mode
+	}
+
+	if sec.HasKey("proxy") {
+		proxy := sec.Key("proxy").String()
+		if err := checkProxyURL(proxy); err != nil {
+			return err
+		}
+		c.Proxy = proxy
+	}
+
 	for _, child := range sec.ChildSections() {
 		name := strings.Split(child.Name(), ".")[1]
 
 		if name == "disabled" {
 			// Load up all the disabled data source names
-			c.SourceFilter.Sources = stringset.Deduplicate(child.Key("data_source").ValueWithShadows())
-			c.SourceFilter.Include = false
+			if child.HasKey("data_source") {
+				c.SourceFilter.Sources = stringset.Deduplicate(child.Key("data_source").ValueWithShadows())
+				c.SourceFilter.Include = false
+			}
+			if child.HasKey("category") {
+				if err := c.DisableSourceCategories(child.Key("category").ValueWithShadows()...); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if name == "enabled" {
+			if err := c.EnableSourceCategories(child.Key("category").ValueWithShadows()...); err != nil {
+				return err
+			}
 			continue
 		}
 
 		dsc := c.GetDataSourceConfig(name)
 		// Parse the Database information and assign to the Config
 		child.MapTo(dsc)
+		dsc.settings = make(map[string]string)
+		for _, key := range child.Keys() {
+			dsc.settings[strings.ToLower(key.Name())] = key.String()
+		}
 		if c.MinimumTTL > dsc.TTL {
 			dsc.TTL = c.MinimumTTL
 		}
+		if err := checkProxyURL(dsc.Proxy); dsc.Proxy != "" && err != nil {
+			return fmt.Errorf("The %s data source: %v", name, err)
+		}
+		if err := checkServiceURL(dsc.URL); dsc.URL != "" && err != nil {
+			return fmt.Errorf("The %s data source: %v", name, err)
+		}
 		// Check for data source credentials
 		for _, cr := range child.ChildSections() {
 			setName := strings.Split(cr.Name(), ".")[2]
 
 			creds := &Credentials{Name: setName}
 			cr.MapTo(creds)
+			if creds.TokenURL != "" {
+				if err := checkServiceURL(creds.TokenURL); err != nil {
+					return fmt.Errorf("The %s data source token_url: %v", name, err)
+				}
+				if creds.ClientID == "" {
+					return fmt.Errorf("The %s data source provided the token_url without a client_id", name)
+				}
+			}
 			dsc.AddCredentials(creds)
 		}
 	}
 
 	return nil
 }
+
+// EnableSourceCategories restricts the data sources to the categories provided.
+func (c *Config) EnableSourceCategories(categories ...string) error {
+	list, err := checkSourceCategories(categories)
+	if err != nil {
+		return err
+	}
+
+	c.SourceCategories.Enabled = stringset.New(append(c.SourceCategories.Enabled, list...)...).Slice()
+	return nil
+}
+
+// DisableSourceCategories prevents the data sources of the categories provided from being used.
+func (c *Config) DisableSourceCategories(categories ...string) error {
+	list, err := checkSourceCategories(categories)
+	if err != nil {
+		return err
+	}
+
+	c.SourceCategories.Disabled = stringset.New(append(c.SourceCategories.Disabled, list...)...).Slice()
+	return nil
+}
+
+// SourceCategoryEnabled returns true when data sources of the category can be used.
+func (c *Config) SourceCategoryEnabled(category string) bool {
+	category = strings.ToLower(category)
+
+	for _, d := range c.SourceCategories.Disabled {
+		if d == category {
+			return false
+		}
+	}
+	if len(c.SourceCategories.Enabled) == 0 {
+		return true
+	}
+
+	for _, e := range c.SourceCategories.Enabled {
+		if e == category {
+			return true
+		}
+	}
+	// Brute forcing and name alterations are controlled by their own settings
+	return category == requests.BRUTE || category == requests.ALT
+}
+
+func checkSourceCategories(categories []string) ([]string, error) {
+	valid := stringset.New(requests.API, requests.ALT, requests.ARCHIVE, requests.BRUTE,
+		requests.CERT, requests.EXTERNAL, requests.RIR, requests.SCRAPE)
+
+	var list []string
+	for _, category := range categories {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if category == "" {
+			continue
+		}
+
+		if !valid.Has(category) {
+			names := valid.Slice()
+			sort.Strings(names)
+			return nil, fmt.Errorf("%s is not a data source category: %s", category, strings.Join(names, ", "))
+		}
+		list = append(list, category)
+	}
+	return list, nil
+}
+
+func checkProxyURL(proxy string) error {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return fmt.Errorf("Failed to parse the proxy URL %s: %v", proxy, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("The proxy URL %s must include the scheme and host", proxy)
+	}
+	return nil
+}
+
+func checkServiceURL(service string) error {
+	u, err := url.Parse(service)
+	if err != nil {
+		return fmt.Errorf("Failed to parse the URL %s: %v", service, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("The URL %s must include the HTTP or HTTPS scheme and host", service)
+	}
+	return nil
+}
@@ -4,8 +4,11 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
+	"time"
 
+	amasshttp "github.com/OWASP/Amass/v3/net/http"
 	"github.com/go-ini/ini"
 )
 
@@ -78,6 +81,8 @@ func TestLoadDataSourceSettings(t *testing.T) {
 		[]byte(`
 		[data_sources]
 		minimum_ttl = 1440
+		http_cache_ttl = 720
+		asn_cache_ttl = 10080
 
 		[data_sources.disabled]
 		data_source = CommonCrawl
@@ -96,9 +101,12 @@ func TestLoadDataSourceSettings(t *testing.T) {
 	if err := c.loadDataSourceSettings(cfg); err != nil {
 		t.Errorf("Failed to parse the data source settings: %v", err)
 	}
-	if c.MinimumTTL != 1440 {
+	if c.MinimumTTL != 1440 || c.HTTPCacheTTL != 720 || c.ASNCacheTTL != 10080 {
 		t.Errorf("Failed to load global data source settings")
 	}
+	if c.Dir = "/tmp/amass"; c.ASNCachePath() != filepath.Join("/tmp/amass", asnCacheFileName) {
+		t.Errorf("The ASN cache file was %s", c.ASNCachePath())
+	}
 
 	dsc := c.GetDataSourceConfig("AlienVault")
 	if dsc != nil {
@@ -109,3 +117,330 @@ func TestLoadDataSourceSettings(t *testing.T) {
 		t.Errorf("Failed to load data source settings")
 	}
 }
+
+func TestDataSourceProxy(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[data_sources]
+		proxy = http://127.0.0.1:8080
+
+		[data_sources.NetworksDB]
+		proxy = socks5://127.0.0.1:9050
+		`),
+	)
+
+	if err := c.loadDataSourceSettings(cfg); err != nil {
+		t.Errorf("Failed to parse the data source settings: %v", err)
+	}
+	if p := c.DataSourceProxy("NetworksDB"); p != "socks5://127.0.0.1:9050" {
+		t.Errorf("DataSourceProxy returned %s instead of the data source proxy", p)
+	}
+	if p := c.DataSourceProxy("AlienVault"); p != "http://127.0.0.1:8080" {
+		t.Errorf("DataSourceProxy returned %s instead of the default proxy", p)
+	}
+
+	cfg, _ = ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[data_sources]
+		[data_sources.NetworksDB]
+		proxy = 127.0.0.1
+		`),
+	)
+
+	if err := c.loadDataSourceSettings(cfg); err == nil {
+		t.Errorf("Failed to report an error when provided an invalid proxy URL")
+	}
+}
+
+func TestDataSourceURL(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[data_sources]
+		[data_sources.GitLab]
+		url = https://gitlab.example.com
+		`),
+	)
+
+	if err := c.loadDataSourceSettings(cfg); err != nil {
+		t.Errorf("Failed to parse the data source settings: %v", err)
+	}
+	if u := c.GetDataSourceConfig("GitLab").URL; u != "https://gitlab.example.com" {
+		t.Errorf("The data source URL was loaded as %s", u)
+	}
+
+	cfg, _ = ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[data_sources]
+		[data_sources.GitLab]
+		url = gitlab.example.com
+		`),
+	)
+
+	if err := c.loadDataSourceSettings(cfg); err == nil {
+		t.Errorf("Failed to report an error when provided an invalid data source URL")
+	}
+}
+
+func TestDataSourceSettings(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[data_sources]
+		[data_sources.Wayback]
+		ttl = 1440
+		from = 2015
+		`),
+	)
+
+	if err := c.loadDataSourceSettings(cfg); err != nil {
+		t.Errorf("Failed to parse the data source settings: %v", err)
+	}
+
+	dsc := c.GetDataSourceConfig("Wayback")
+	if v := dsc.Setting("FROM"); v != "2015" {
+		t.Errorf("The from setting was loaded as %q", v)
+	}
+	if v := dsc.Setting("to"); v != "" {
+		t.Errorf("A missing setting returned %q", v)
+	}
+	if settings := dsc.Settings(); len(settings) != 2 || settings["ttl"] != "1440" {
+		t.Errorf("Unexpected settings were returned: %v", settings)
+	}
+}
+
+func TestSourceCategories(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[data_sources]
+		[data_sources.enabled]
+		category = cert
+		category = API
+		[data_sources.disabled]
+		data_source = Ask
+		category = api
+		`),
+	)
+
+	if err := c.loadDataSourceSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the data source categories: %v", err)
+	}
+	if len(c.SourceFilter.Sources) != 1 {
+		t.Errorf("The disabled data source names were not loaded: %v", c.SourceFilter.Sources)
+	}
+
+	tests := []struct {
+		Category string
+		Expected bool
+	}{
+		{"cert", true},
+		{"api", false},
+		{"scrape", false},
+		{"brute", true},
+	}
+	for _, test := range tests {
+		if got := c.SourceCategoryEnabled(test.Category); got != test.Expected {
+			t.Errorf("The %s category enabled: %t, expected: %t", test.Category, got, test.Expected)
+		}
+	}
+
+	if err := c.DisableSourceCategories("web"); err == nil {
+		t.Errorf("DisableSourceCategories accepted an unknown category")
+	}
+}
+
+func TestDataSourceTimeout(t *testing.T) {
+	c := NewConfig()
+
+	if d := c.DataSourceTimeout("Crtsh"); d != defaultSourceTimeout*time.Second {
+		t.Errorf("The default timeout was %v", d)
+	}
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[data_sources]
+		timeout = 20
+		[data_sources.Baidu]
+		timeout = 90
+		`),
+	)
+
+	if err := c.loadDataSourceSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the data source settings: %v", err)
+	}
+	if d := c.DataSourceTimeout("Baidu"); d != 90*time.Second {
+		t.Errorf("The timeout of the data source was %v", d)
+	}
+	if d := c.DataSourceTimeout("Crtsh"); d != 20*time.Second {
+		t.Errorf("The timeout of the data_sources section was %v", d)
+	}
+}
+
+func TestCassetteSettings(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[data_sources]
+		cassette = testdata/sources.json
+		cassette_mode = Record
+		`),
+	)
+
+	if err := c.loadDataSourceSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the data source settings: %v", err)
+	}
+	if c.HTTPCassette != "testdata/sources.json" || c.HTTPCassetteMode != amasshttp.CassetteRecord {
+		t.Errorf("Failed to load the cassette settings: %s, %s", c.HTTPCassette, c.HTTPCassetteMode)
+	}
+
+	cfg, _ = ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[data_sources]
+		cassette_mode = rewind
+		`),
+	)
+
+	if err := c.loadDataSourceSettings(cfg); err == nil {
+		t.Errorf("Failed to report an error for the invalid cassette mode")
+	}
+}
+
+func TestDataSourceRetryPolicy(t *testing.T) {
+	c := NewConfig()
+
+	if p := c.DataSourceRetryPolicy("Crtsh"); p == nil || p.Retries != defaultSourceRetries {
+		t.Errorf("The default retry policy was %+v", p)
+	}
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[data_sources]
+		retries = 4
+		retry_backoff = 250
+		retry_jitter = 0.5
+		retry_status_codes = 500, 502
+		[data_sources.Baidu]
+		retries = -1
+		[data_sources.Crtsh]
+		retries = 1
+		`),
+	)
+
+	if err := c.loadDataSourceSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the data source settings: %v", err)
+	}
+	if p := c.DataSourceRetryPolicy("Baidu"); p != nil {
+		t.Errorf("The retries of the data source were not disabled: %+v", p)
+	}
+	if p := c.DataSourceRetryPolicy("Crtsh"); p == nil || p.Retries != 1 {
+		t.Errorf("The retries of the data source were not applied: %+v", p)
+	}
+
+	p := c.DataSourceRetryPolicy("AlienVault")
+	if p == nil || p.Retries != 4 || p.Backoff != 250*time.Millisecond || p.Jitter != 0.5 {
+		t.Fatalf("The retry settings of the data_sources section were not applied: %+v", p)
+	}
+	if len(p.StatusCodes) != 2 || p.StatusCodes[0] != 500 || p.StatusCodes[1] != 502 {
+		t.Errorf("The retry status codes were %v", p.StatusCodes)
+	}
+
+	bad, _ := ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(`
+		[data_sources]
+		retry_status_codes = 500, bad
+		`))
+	if err := c.loadDataSourceSettings(bad); err == nil {
+		t.Errorf("An invalid retry status code was accepted")
+	}
+}
+
+func TestOAuth2Credentials(t *testing.T) {
+	c := NewConfig()
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[data_sources]
+		[data_sources.Feed]
+		[data_sources.Feed.Credentials]
+		token_url = https://auth.example.com/oauth2/token
+		client_id = amass
+		client_secret = secret
+		scope = read, dns
+		`),
+	)
+
+	if err := c.loadDataSourceSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the data source settings: %v", err)
+	}
+
+	cc := c.GetDataSourceConfig("Feed").GetCredentials().OAuth2()
+	if cc == nil {
+		t.Fatalf("The OAuth2 client credentials were not loaded")
+	}
+	if cc.TokenURL != "https://auth.example.com/oauth2/token" || cc.ClientID != "amass" ||
+		cc.ClientSecret != "secret" || len(cc.Scopes) != 2 || cc.Scopes[1] != "dns" {
+		t.Errorf("Unexpected OAuth2 client credentials: %+v", cc)
+	}
+	if (&Credentials{Key: "key"}).OAuth2() != nil {
+		t.Errorf("OAuth2 client credentials were returned without a token URL")
+	}
+
+	bad, _ := ini.LoadSources(ini.LoadOptions{Insensitive: true}, []byte(`
+		[data_sources]
+		[data_sources.Feed]
+		[data_sources.Feed.Credentials]
+		token_url = https://auth.example.com/oauth2/token
+		`))
+	if err := NewConfig().loadDataSourceSettings(bad); err == nil {
+		t.Errorf("The token_url was accepted without a client_id")
+	}
+}
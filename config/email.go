@@ -0,0 +1,90 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-ini/ini"
+)
+
+// The ways that the connection with the SMTP server can be secured.
+const (
+	EmailTLSNone     = "none"
+	EmailTLSStartTLS = "starttls"
+	EmailTLSImplicit = "tls"
+)
+
+// EmailReportConfig describes the summary emailed once the schedule has elapsed since the previous report.
+type EmailReportConfig struct {
+	// The amount of time between two reports
+	Interval time.Duration
+	// The host and port of the SMTP server
+	Server string
+	// How the connection with the server is secured: none, starttls or tls
+	TLS string
+	// Set when the certificate of the server is not verified
+	SkipVerify bool
+	Username   string
+	Password   string
+	From       string
+	To         []string
+	Subject    string
+}
+
+// The default subject line of the report emails
+const defaultEmailSubject = "Amass report"
+
+func (c *Config) loadEmailReportSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("email_report")
+	if err != nil || !sec.Key("enabled").MustBool(true) {
+		return nil
+	}
+
+	report := &EmailReportConfig{
+		Server:     sec.Key("server").String(),
+		TLS:        strings.ToLower(sec.Key("tls").MustString(EmailTLSStartTLS)),
+		SkipVerify: sec.Key("skip_verify").MustBool(false),
+		Username:   sec.Key("username").String(),
+		Password:   sec.Key("password").String(),
+		From:       sec.Key("from").String(),
+		Subject:    sec.Key("subject").MustString(defaultEmailSubject),
+	}
+
+	switch schedule := strings.ToLower(sec.Key("schedule").MustString("daily")); schedule {
+	case "daily":
+		report.Interval = 24 * time.Hour
+	case "weekly":
+		report.Interval = 7 * 24 * time.Hour
+	default:
+		return fmt.Errorf("The email_report schedule %s is not daily or weekly", schedule)
+	}
+
+	if _, _, err := net.SplitHostPort(report.Server); err != nil {
+		return fmt.Errorf("The email_report server must be provided as host:port: %v", err)
+	}
+	switch report.TLS {
+	case EmailTLSNone, EmailTLSStartTLS, EmailTLSImplicit:
+	default:
+		return fmt.Errorf("The email_report tls setting %s is not none, starttls or tls", report.TLS)
+	}
+
+	if report.From == "" {
+		return fmt.Errorf("The email_report section did not provide the from address")
+	}
+	for _, addr := range sec.Key("to").ValueWithShadows() {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			report.To = append(report.To, addr)
+		}
+	}
+	if len(report.To) == 0 {
+		return fmt.Errorf("The email_report section did not provide a to address")
+	}
+
+	c.EmailReport = report
+	return nil
+}
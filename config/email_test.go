@@ -0,0 +1,65 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-ini/ini"
+)
+
+func TestEmailReportSettings(t *testing.T) {
+	load := func(data string) (*Config, error) {
+		c := NewConfig()
+		cfg, _ := ini.LoadSources(
+			ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			},
+			[]byte(data),
+		)
+		return c, c.loadEmailReportSettings(cfg)
+	}
+
+	c, err := load(`
+		[email_report]
+		schedule = weekly
+		server = smtp.example.com:465
+		tls = tls
+		username = amass
+		password = secret
+		from = amass@example.com
+		to = team@example.com
+		to = oncall@example.com
+		`)
+	if err != nil {
+		t.Fatalf("Failed to parse the email report settings: %v", err)
+	}
+
+	r := c.EmailReport
+	if r == nil {
+		t.Fatalf("The email report settings were not loaded")
+	}
+	if r.Interval != 7*24*time.Hour || r.TLS != EmailTLSImplicit || r.Subject != defaultEmailSubject {
+		t.Errorf("Unexpected email report settings: %+v", r)
+	}
+	if len(r.To) != 2 || r.To[1] != "oncall@example.com" {
+		t.Errorf("The recipients were %v", r.To)
+	}
+
+	if c, err := load(""); err != nil || c.EmailReport != nil {
+		t.Errorf("The email report was configured without the section")
+	}
+	for _, bad := range []string{
+		"[email_report]\nserver = smtp.example.com:25\nfrom = a@example.com\nto = b@example.com\nschedule = hourly",
+		"[email_report]\nserver = smtp.example.com\nfrom = a@example.com\nto = b@example.com",
+		"[email_report]\nserver = smtp.example.com:25\nfrom = a@example.com",
+		"[email_report]\nserver = smtp.example.com:25\nfrom = a@example.com\nto = b@example.com\ntls = ssl",
+	} {
+		if _, err := load(bad); err == nil {
+			t.Errorf("The invalid settings were accepted:\n%s", bad)
+		}
+	}
+}
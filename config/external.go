@@ -0,0 +1,66 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+// ExternalSourceConfig describes an executable that acts as a data source by exchanging
+// line-delimited JSON messages with Amass over its standard input and output.
+type ExternalSourceConfig struct {
+	Name string
+	// The path to the executable
+	Path string
+	// The command-line arguments provided to the executable
+	Args []string
+	// The data source type, such as api or scrape (Default: api)
+	Type string
+	// The number of seconds allowed for the executable to complete each query
+	Timeout int
+}
+
+// The default number of seconds allowed for an external data source to complete a query
+const defaultExternalSourceTimeout = 300
+
+func (c *Config) loadExternalSourceSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("external_sources")
+	if err != nil {
+		return nil
+	}
+
+	for _, child := range sec.ChildSections() {
+		// Section names are case insensitive, so the name setting can provide the preferred form
+		name := child.Key("name").MustString(strings.TrimPrefix(child.Name(), "external_sources."))
+
+		path := child.Key("path").String()
+		if path == "" {
+			return fmt.Errorf("The %s external data source did not provide the path setting", name)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("The %s external data source: %v", name, err)
+		}
+
+		ext := &ExternalSourceConfig{
+			Name:    name,
+			Path:    path,
+			Type:    child.Key("type").MustString("api"),
+			Timeout: child.Key("timeout").MustInt(defaultExternalSourceTimeout),
+		}
+		if child.HasKey("argument") {
+			ext.Args = child.Key("argument").ValueWithShadows()
+		}
+		if ext.Timeout <= 0 {
+			ext.Timeout = defaultExternalSourceTimeout
+		}
+
+		c.ExternalSources = append(c.ExternalSources, ext)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestExternalSourceSettings(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("Failed to obtain the path of an executable: %v", err)
+	}
+
+	c := NewConfig()
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[external_sources]
+		[external_sources.internal]
+		name = Internal
+		path = `+exe+`
+		argument = -mode
+		argument = amass
+		timeout = 60
+		`),
+	)
+
+	if err := c.loadExternalSourceSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the external data source settings: %v", err)
+	}
+	if len(c.ExternalSources) != 1 {
+		t.Fatalf("%d external data sources were loaded instead of one", len(c.ExternalSources))
+	}
+
+	ext := c.ExternalSources[0]
+	if ext.Name != "Internal" || ext.Path != exe || ext.Type != "api" || ext.Timeout != 60 {
+		t.Errorf("The external data source settings were not loaded correctly: %+v", ext)
+	}
+	if len(ext.Args) != 2 || ext.Args[0] != "-mode" || ext.Args[1] != "amass" {
+		t.Errorf("The external data source arguments were not loaded correctly: %v", ext.Args)
+	}
+
+	c = NewConfig()
+	cfg, _ = ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[external_sources]
+		[external_sources.missing]
+		path = /does/not/exist
+		`),
+	)
+
+	if err := c.loadExternalSourceSettings(cfg); err == nil {
+		t.Errorf("Failed to report an error when provided a path that does not exist")
+	}
+}
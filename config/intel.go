@@ -0,0 +1,52 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+func (c *Config) loadIntelSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("intel")
+	if err != nil {
+		return nil
+	}
+
+	c.MinDomainObservations = sec.Key("minimum_observations").MustInt(c.MinDomainObservations)
+	if c.MinDomainObservations < 1 {
+		return fmt.Errorf("The intel minimum_observations must be one or more")
+	}
+
+	c.ExcludeGenericRDNS = sec.Key("exclude_generic_rdns").MustBool(c.ExcludeGenericRDNS)
+	if sec.HasKey("generic_rdns_pattern") {
+		for _, p := range sec.Key("generic_rdns_pattern").ValueWithShadows() {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return fmt.Errorf("The intel generic_rdns_pattern %s is invalid: %v", p, err)
+			}
+
+			c.GenericRDNSPatterns = append(c.GenericRDNSPatterns, re)
+		}
+		// Providing the patterns implies that the generic names are excluded
+		c.ExcludeGenericRDNS = true
+	}
+
+	if sec.HasKey("whois_org") {
+		c.WhoisOrgs = append(c.WhoisOrgs, sec.Key("whois_org").ValueWithShadows()...)
+	}
+	if sec.HasKey("whois_email") {
+		for _, email := range sec.Key("whois_email").ValueWithShadows() {
+			if !strings.Contains(email, "@") {
+				return fmt.Errorf("The intel whois_email %s is not an email address", email)
+			}
+
+			c.WhoisEmails = append(c.WhoisEmails, email)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestIntelSettings(t *testing.T) {
+	c := NewConfig()
+	if c.MinDomainObservations != 1 || c.ExcludeGenericRDNS {
+		t.Errorf("The default intel settings changed the behavior: %d, %t", c.MinDomainObservations, c.ExcludeGenericRDNS)
+	}
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[intel]
+		minimum_observations = 3
+		generic_rdns_pattern = \.hosted-by\.example\.net$
+		whois_org = Example, Inc.
+		whois_org = Example Holdings
+		whois_email = hostmaster@example.com
+		`),
+	)
+
+	if err := c.loadIntelSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the intel settings: %v", err)
+	}
+	if c.MinDomainObservations != 3 {
+		t.Errorf("The minimum observations was %d instead of 3", c.MinDomainObservations)
+	}
+	if !c.ExcludeGenericRDNS || len(c.GenericRDNSPatterns) != 1 ||
+		!c.GenericRDNSPatterns[0].MatchString("vm42.hosted-by.example.net") {
+		t.Errorf("The generic reverse DNS pattern was not loaded correctly")
+	}
+	if len(c.WhoisOrgs) != 2 || c.WhoisOrgs[0] != "Example, Inc." || c.WhoisOrgs[1] != "Example Holdings" {
+		t.Errorf("The reverse whois organizations were not loaded correctly: %v", c.WhoisOrgs)
+	}
+	if len(c.WhoisEmails) != 1 || c.WhoisEmails[0] != "hostmaster@example.com" {
+		t.Errorf("The reverse whois email addresses were not loaded correctly: %v", c.WhoisEmails)
+	}
+
+	for _, bad := range []string{"minimum_observations = 0", "generic_rdns_pattern = ([a-z]", "whois_email = example.com"} {
+		c := NewConfig()
+		cfg, _ := ini.LoadSources(ini.LoadOptions{Insensitive: true, AllowShadows: true}, []byte("[intel]\n"+bad))
+
+		if err := c.loadIntelSettings(cfg); err == nil {
+			t.Errorf("The invalid intel setting was accepted: %s", bad)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"github.com/go-ini/ini"
+)
+
+func (c *Config) loadLivenessSettings(cfg *ini.File) error {
+	liveness, err := cfg.GetSection("liveness")
+	if err != nil {
+		return nil
+	}
+
+	c.Liveness = liveness.Key("enabled").MustBool(true)
+	if !c.Liveness {
+		return nil
+	}
+
+	if liveness.HasKey("port") {
+		var ports []int
+
+		for _, port := range liveness.Key("port").ValueWithShadows() {
+			ports = uniqueIntAppend(ports, port)
+		}
+		if len(ports) > 0 {
+			c.LivenessPorts = ports
+		}
+	}
+
+	return nil
+}
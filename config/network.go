@@ -6,8 +6,11 @@ package config
 import (
 	"bufio"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
+
+	"github.com/go-ini/ini"
 )
 
 // LookupASNsByName returns requests.ASNRequest objects for autonomous systems with
@@ -16,30 +19,100 @@ func LookupASNsByName(s string) ([]int, []string, error) {
 	var asns []int
 	var descs []string
 
+	s = strings.ToLower(s)
+	err := eachEmbeddedASN(func(asn int, desc string) {
+		if strings.Contains(strings.ToLower(desc), s) {
+			asns = append(asns, asn)
+			descs = append(descs, desc)
+		}
+	})
+	return asns, descs, err
+}
+
+// EmbeddedASNs returns all the autonomous systems and descriptions from the ASN list embedded into the binary.
+func EmbeddedASNs() ([]int, []string, error) {
+	var asns []int
+	var descs []string
+
+	err := eachEmbeddedASN(func(asn int, desc string) {
+		asns = append(asns, asn)
+		descs = append(descs, desc)
+	})
+	return asns, descs, err
+}
+
+func eachEmbeddedASN(callback func(asn int, desc string)) error {
 	fsOnce.Do(openTheFS)
 
 	content, err := StatikFS.Open("/asnlist.txt")
 	if err != nil {
-		return asns, descs, fmt.Errorf("Failed to obtain the embedded ASN information: asnlist.txt: %v", err)
+		return fmt.Errorf("Failed to obtain the embedded ASN information: asnlist.txt: %v", err)
 	}
+	defer content.Close()
 
-	s = strings.ToLower(s)
 	scanner := bufio.NewScanner(content)
 	for scanner.Scan() {
-		line := scanner.Text()
-
-		if err := scanner.Err(); err == nil {
-			parts := strings.Split(strings.TrimSpace(line), ",")
-
-			if strings.Contains(strings.ToLower(parts[1]), s) {
-				a, err := strconv.Atoi(parts[0])
-				if err == nil {
-					asns = append(asns, a)
-					descs = append(descs, parts[1])
-				}
-			}
+		// The descriptions can contain commas, such as "FACEBOOK - Facebook, Inc., US"
+		parts := strings.SplitN(strings.TrimSpace(scanner.Text()), ",", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		if a, err := strconv.Atoi(parts[0]); err == nil {
+			callback(a, parts[1])
 		}
 	}
+	return scanner.Err()
+}
 
-	return asns, descs, nil
+func (c *Config) loadNetworkSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("network")
+	if err != nil {
+		return nil
+	}
+
+	if sec.HasKey("interface") {
+		c.Interface = strings.TrimSpace(sec.Key("interface").String())
+	}
+
+	if sec.HasKey("source_address") {
+		addr := strings.TrimSpace(sec.Key("source_address").String())
+
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return fmt.Errorf("The network source_address %s is not a valid IP address", addr)
+		}
+		c.SourceAddress = ip
+	}
+
+	if sec.HasKey("dns_ports") {
+		min, max, err := parsePortRange(sec.Key("dns_ports").String())
+		if err != nil {
+			return fmt.Errorf("The network dns_ports setting: %v", err)
+		}
+		c.DNSPortMin, c.DNSPortMax = min, max
+	}
+	return nil
+}
+
+// parsePortRange accepts a single port or an inclusive range, such as 20000-29999.
+func parsePortRange(s string) (int, int, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "-", 2)
+	if len(parts) == 1 {
+		parts = append(parts, parts[0])
+	}
+
+	min, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid port range %s", s)
+	}
+	max, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid port range %s", s)
+	}
+
+	if min < 1 || max > 65535 || min > max {
+		return 0, 0, fmt.Errorf("Invalid port range %s", s)
+	}
+	return min, max, nil
 }
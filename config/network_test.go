@@ -0,0 +1,71 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestLoadNetworkSettings(t *testing.T) {
+	c := NewConfig()
+
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[network]
+		interface = eth1
+		source_address = 192.0.2.10
+		dns_ports = 20000-29999
+		`),
+	)
+
+	if err := c.loadNetworkSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the network settings: %v", err)
+	}
+	if c.Interface != "eth1" || c.SourceAddress.String() != "192.0.2.10" {
+		t.Errorf("The interface and source address were loaded as %s and %s", c.Interface, c.SourceAddress)
+	}
+	if c.DNSPortMin != 20000 || c.DNSPortMax != 29999 {
+		t.Errorf("The DNS ports were loaded as %d-%d", c.DNSPortMin, c.DNSPortMax)
+	}
+
+	for _, ports := range []string{"29999-20000", "0-100", "20000-70000", "high"} {
+		cfg, _ = ini.LoadSources(
+			ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			},
+			[]byte("[network]\ndns_ports = "+ports),
+		)
+
+		if err := c.loadNetworkSettings(cfg); err == nil {
+			t.Errorf("Failed to report an error for the DNS port range %s", ports)
+		}
+	}
+}
+
+func TestLookupASNsByName(t *testing.T) {
+	asns, descs, err := LookupASNsByName("facebook, inc")
+	if err != nil {
+		t.Fatalf("Failed to search the embedded ASN information: %v", err)
+	}
+
+	var found bool
+	for i, asn := range asns {
+		if asn == 32934 {
+			found = true
+			if descs[i] != "FACEBOOK - Facebook, Inc., US" {
+				t.Errorf("AS32934 had the description %q", descs[i])
+			}
+		}
+	}
+	if !found {
+		t.Errorf("The search did not match the description containing commas")
+	}
+}
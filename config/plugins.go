@@ -0,0 +1,52 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// AcquirePlugins returns the paths of all the Go plugin files provided by the user for data sources.
+// An error is returned when the configured plugins directory does not exist.
+func (c *Config) AcquirePlugins() ([]string, error) {
+	var paths []string
+
+	// The plugins folder of the output directory is optional
+	if dir := OutputDirectory(c.Dir); dir != "" {
+		if finfo, err := os.Stat(filepath.Join(dir, "plugins")); err == nil && finfo.IsDir() {
+			paths = append(paths, filepath.Join(dir, "plugins"))
+		}
+	}
+	if c.PluginsDirectory != "" {
+		dir, err := filepath.Abs(c.PluginsDirectory)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to resolve the plugins directory %s: %v", c.PluginsDirectory, err)
+		}
+
+		if finfo, err := os.Stat(dir); err != nil || !finfo.IsDir() {
+			return nil, fmt.Errorf("The plugins directory %s does not exist or is not a directory", dir)
+		}
+		paths = append(paths, dir)
+	}
+
+	var plugins []string
+	for _, path := range paths {
+		filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			// Is this file not a plugin?
+			if info.IsDir() || filepath.Ext(info.Name()) != ".so" {
+				return nil
+			}
+
+			plugins = append(plugins, path)
+			return nil
+		})
+	}
+
+	return plugins, nil
+}
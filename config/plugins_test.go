@@ -0,0 +1,81 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAcquirePlugins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "amass")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	extra, err := ioutil.TempDir("", "plugins")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(extra)
+
+	if err := os.Mkdir(filepath.Join(dir, "plugins"), 0755); err != nil {
+		t.Fatalf("Failed to create the plugins directory: %v", err)
+	}
+	for _, path := range []string{
+		filepath.Join(dir, "plugins", "first.so"),
+		filepath.Join(dir, "plugins", "readme.txt"),
+		filepath.Join(extra, "second.so"),
+	} {
+		if err := ioutil.WriteFile(path, []byte{}, 0644); err != nil {
+			t.Fatalf("Failed to create the file %s: %v", path, err)
+		}
+	}
+
+	c := NewConfig()
+	c.Dir = dir
+	c.PluginsDirectory = extra
+
+	plugins, err := c.AcquirePlugins()
+	if err != nil {
+		t.Fatalf("AcquirePlugins returned an error: %v", err)
+	}
+	if len(plugins) != 2 {
+		t.Errorf("AcquirePlugins returned %d plugins instead of 2: %v", len(plugins), plugins)
+	}
+	for _, path := range plugins {
+		if filepath.Ext(path) != ".so" {
+			t.Errorf("AcquirePlugins returned the file %s that is not a plugin", path)
+		}
+	}
+}
+
+func TestAcquirePluginsDirectory(t *testing.T) {
+	extra, err := ioutil.TempDir("", "plugins")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(extra)
+
+	if err := ioutil.WriteFile(filepath.Join(extra, "only.so"), []byte{}, 0644); err != nil {
+		t.Fatalf("Failed to create the plugin file: %v", err)
+	}
+
+	c := NewConfig()
+	// The output directory does not need to exist for the plugins directory to be used
+	c.Dir = filepath.Join(extra, "missing")
+	c.PluginsDirectory = extra
+
+	if plugins, err := c.AcquirePlugins(); err != nil || len(plugins) != 1 {
+		t.Errorf("AcquirePlugins returned %v with error: %v", plugins, err)
+	}
+
+	c.PluginsDirectory = filepath.Join(extra, "missing")
+	if _, err := c.AcquirePlugins(); err == nil {
+		t.Errorf("AcquirePlugins did not return an error for a missing plugins directory")
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/go-ini/ini"
+)
+
+func (c *Config) loadRetentionSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("retention")
+	if err != nil {
+		return nil
+	}
+
+	c.RetentionDays = sec.Key("maximum_age").MustInt(c.RetentionDays)
+	if c.RetentionDays < 0 {
+		return fmt.Errorf("The retention maximum_age cannot be negative")
+	}
+
+	c.RetentionEvents = sec.Key("maximum_enumerations").MustInt(c.RetentionEvents)
+	if c.RetentionEvents < 0 {
+		return fmt.Errorf("The retention maximum_enumerations cannot be negative")
+	}
+
+	c.PruneOnStart = sec.Key("prune_on_start").MustBool(c.PruneOnStart)
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestRetentionSettings(t *testing.T) {
+	c := NewConfig()
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[retention]
+		maximum_age = 90
+		maximum_enumerations = 5
+		prune_on_start = true
+		`),
+	)
+
+	if err := c.loadRetentionSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the retention settings: %v", err)
+	}
+	if c.RetentionDays != 90 || c.RetentionEvents != 5 || !c.PruneOnStart {
+		t.Errorf("The retention settings were not loaded correctly: %d, %d, %t",
+			c.RetentionDays, c.RetentionEvents, c.PruneOnStart)
+	}
+
+	for _, bad := range []string{"maximum_age = -1", "maximum_enumerations = -2"} {
+		c := NewConfig()
+		cfg, _ := ini.LoadSources(ini.LoadOptions{Insensitive: true, AllowShadows: true}, []byte("[retention]\n"+bad))
+
+		if err := c.loadRetentionSettings(cfg); err == nil {
+			t.Errorf("The invalid retention setting was accepted: %s", bad)
+		}
+	}
+}
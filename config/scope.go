@@ -4,6 +4,7 @@
 package config
 
 import (
+	"fmt"
 	"net"
 	"regexp"
 	"strings"
@@ -135,7 +136,8 @@ func (c *Config) IsAddressInScope(addr string) bool {
 	return false
 }
 
-// Blacklisted returns true is the name in the parameter ends with a subdomain name in the config blacklist.
+// Blacklisted returns true is the name in the parameter ends with a subdomain name in the config blacklist,
+// or is matched by one of the excluded name expressions.
 func (c *Config) Blacklisted(name string) bool {
 	var resp bool
 
@@ -146,7 +148,25 @@ func (c *Config) Blacklisted(name string) bool {
 			break
 		}
 	}
-	return resp
+	return resp || c.Excluded(n)
+}
+
+// Excluded returns true when the entire name is matched by one of the excluded name expressions.
+func (c *Config) Excluded(name string) bool {
+	n := strings.TrimSpace(name)
+
+	for _, re := range c.ExcludedNames {
+		if re.MatchString(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseExcludedName compiles the expression, such as .*\.cdn\.example\.com, so that it matches
+// entire names without regard to case.
+func ParseExcludedName(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)^(?:" + strings.TrimSpace(pattern) + ")$")
 }
 
 func (c *Config) loadScopeSettings(cfg *ini.File) error {
@@ -199,6 +219,17 @@ func (c *Config) loadScopeSettings(cfg *ini.File) error {
 	// Load up all the blacklisted subdomain names
 	if blacklisted, err := cfg.GetSection("scope.blacklisted"); err == nil {
 		c.Blacklist = stringset.Deduplicate(blacklisted.Key("subdomain").ValueWithShadows())
+
+		if blacklisted.HasKey("regex") {
+			for _, p := range blacklisted.Key("regex").ValueWithShadows() {
+				re, err := ParseExcludedName(p)
+				if err != nil {
+					return fmt.Errorf("The blacklisted regex %s is invalid: %v", p, err)
+				}
+
+				c.ExcludedNames = append(c.ExcludedNames, re)
+			}
+		}
 	}
 
 	return nil
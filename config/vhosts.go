@@ -0,0 +1,54 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/OWASP/Amass/v3/stringset"
+	"github.com/go-ini/ini"
+)
+
+// The labels tried as virtual hosts when a wordlist has not been provided.
+var defaultVHostWordlist = []string{
+	"admin", "api", "app", "beta", "corp", "dev", "development", "git", "intranet", "internal",
+	"jenkins", "jira", "login", "portal", "preprod", "prod", "qa", "stage", "staging", "test",
+	"uat", "vpn", "webmail", "wiki",
+}
+
+func (c *Config) loadVHostSettings(cfg *ini.File) error {
+	vhosts, err := cfg.GetSection("vhosts")
+	if err != nil {
+		return nil
+	}
+
+	c.VHosts = vhosts.Key("enabled").MustBool(true)
+	if !c.VHosts {
+		return nil
+	}
+
+	if vhosts.HasKey("port") {
+		var ports []int
+
+		for _, port := range vhosts.Key("port").ValueWithShadows() {
+			ports = uniqueIntAppend(ports, port)
+		}
+		if len(ports) > 0 {
+			c.VHostPorts = ports
+		}
+	}
+
+	if vhosts.HasKey("wordlist_file") {
+		for _, wordlist := range vhosts.Key("wordlist_file").ValueWithShadows() {
+			list, err := GetListFromFile(wordlist)
+			if err != nil {
+				return fmt.Errorf("Unable to load the file in the vhosts wordlist_file setting: %s: %v", wordlist, err)
+			}
+			c.VHostWordlist = append(c.VHostWordlist, list...)
+		}
+	}
+
+	c.VHostWordlist = stringset.Deduplicate(c.VHostWordlist)
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestVHostSettings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vhosts")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "vhosts.txt")
+	if err := ioutil.WriteFile(path, []byte("admin\nstaging\nadmin\n"), 0644); err != nil {
+		t.Fatalf("Failed to write the wordlist: %v", err)
+	}
+
+	c := NewConfig()
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[vhosts]
+		port = 80
+		port = 8443
+		wordlist_file = `+path+`
+		`),
+	)
+
+	if err := c.loadVHostSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the vhosts settings: %v", err)
+	}
+	if !c.VHosts || len(c.VHostPorts) != 2 || c.VHostPorts[1] != 8443 {
+		t.Errorf("The vhosts settings were not loaded correctly: %t, %v", c.VHosts, c.VHostPorts)
+	}
+	if len(c.VHostWordlist) != 2 {
+		t.Errorf("The vhosts wordlist was not loaded correctly: %v", c.VHostWordlist)
+	}
+
+	if err := c.CheckSettings(); err == nil {
+		t.Errorf("Virtual host probing was accepted without active enumeration")
+	}
+
+	c = NewConfig()
+	c.VHosts = true
+	c.Active = true
+	if err := c.CheckSettings(); err != nil || len(c.VHostWordlist) == 0 {
+		t.Errorf("The default vhosts wordlist was not used: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ini/ini"
+)
+
+// The default number of times a failed webhook notification is sent again
+const defaultWebhookRetries = 3
+
+// The default wait in milliseconds before the first retry of a failed webhook notification
+const defaultWebhookBackoff = 1000
+
+// WebhookConfig describes the URLs that receive the changes reported by the track subcommand.
+type WebhookConfig struct {
+	URLs []string
+	// The number of times a failed notification is sent again
+	Retries int
+	// The wait before the first retry, which is doubled for each of the following retries
+	Backoff time.Duration
+	// Additional headers sent with each notification, such as authorization tokens
+	Headers map[string]string
+}
+
+// AddWebhookURL validates the URL and adds it to the webhook configuration,
+// which is created with the default settings when not already provided.
+func (c *Config) AddWebhookURL(u string) error {
+	if err := checkWebhookURL(u); err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.Webhooks == nil {
+		c.Webhooks = &WebhookConfig{
+			Retries: defaultWebhookRetries,
+			Backoff: defaultWebhookBackoff * time.Millisecond,
+			Headers: make(map[string]string),
+		}
+	}
+	for _, existing := range c.Webhooks.URLs {
+		if existing == u {
+			return nil
+		}
+	}
+	c.Webhooks.URLs = append(c.Webhooks.URLs, u)
+	return nil
+}
+
+func checkWebhookURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("The webhook URL %s could not be parsed: %v", u, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("The webhook URL %s is not an HTTP or HTTPS URL", u)
+	}
+	return nil
+}
+
+func (c *Config) loadWebhookSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("webhooks")
+	if err != nil || !sec.Key("enabled").MustBool(true) {
+		return nil
+	}
+
+	hooks := &WebhookConfig{
+		Retries: sec.Key("retries").MustInt(defaultWebhookRetries),
+		Backoff: time.Duration(sec.Key("backoff").MustInt(defaultWebhookBackoff)) * time.Millisecond,
+		Headers: make(map[string]string),
+	}
+	if hooks.Retries < 0 {
+		return fmt.Errorf("The webhooks retries setting must not be negative")
+	}
+	if hooks.Backoff < 0 {
+		return fmt.Errorf("The webhooks backoff setting must not be negative")
+	}
+
+	for _, u := range sec.Key("url").ValueWithShadows() {
+		if u = strings.TrimSpace(u); u == "" {
+			continue
+		}
+		if err := checkWebhookURL(u); err != nil {
+			return err
+		}
+		hooks.URLs = append(hooks.URLs, u)
+	}
+	if len(hooks.URLs) == 0 {
+		return fmt.Errorf("The webhooks section did not provide a url")
+	}
+
+	for _, h := range sec.Key("header").ValueWithShadows() {
+		if h = strings.TrimSpace(h); h == "" {
+			continue
+		}
+
+		parts := strings.SplitN(h, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return fmt.Errorf("The webhooks header %s is not provided as name: value", h)
+		}
+		hooks.Headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	c.Webhooks = hooks
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-ini/ini"
+)
+
+func TestWebhookSettings(t *testing.T) {
+	load := func(data string) (*Config, error) {
+		c := NewConfig()
+		cfg, _ := ini.LoadSources(
+			ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			},
+			[]byte(data),
+		)
+		return c, c.loadWebhookSettings(cfg)
+	}
+
+	c, err := load(`
+		[webhooks]
+		url = https://hooks.example.com/amass
+		url = http://alerts.example.com:8080/events
+		retries = 5
+		backoff = 250
+		header = Authorization: Bearer Secret
+		`)
+	if err != nil {
+		t.Fatalf("Failed to parse the webhook settings: %v", err)
+	}
+
+	w := c.Webhooks
+	if w == nil {
+		t.Fatalf("The webhook settings were not loaded")
+	}
+	if len(w.URLs) != 2 || w.URLs[1] != "http://alerts.example.com:8080/events" {
+		t.Errorf("The webhook URLs were %v", w.URLs)
+	}
+	if w.Retries != 5 || w.Backoff != 250*time.Millisecond {
+		t.Errorf("Unexpected webhook settings: %+v", w)
+	}
+	if w.Headers["Authorization"] != "Bearer Secret" {
+		t.Errorf("The webhook headers were %v", w.Headers)
+	}
+
+	if c, err := load(""); err != nil || c.Webhooks != nil {
+		t.Errorf("The webhooks were configured without the section")
+	}
+	for _, bad := range []string{
+		"[webhooks]\nretries = 2",
+		"[webhooks]\nurl = ftp://hooks.example.com",
+		"[webhooks]\nurl = https://hooks.example.com\nretries = -1",
+		"[webhooks]\nurl = https://hooks.example.com\nheader = Authorization",
+	} {
+		if _, err := load(bad); err == nil {
+			t.Errorf("The invalid settings were accepted:\n%s", bad)
+		}
+	}
+
+	c = NewConfig()
+	if err := c.AddWebhookURL("hooks.example.com"); err == nil {
+		t.Errorf("The webhook URL without a scheme was accepted")
+	}
+	if err := c.AddWebhookURL("https://hooks.example.com"); err != nil || c.Webhooks == nil {
+		t.Fatalf("Failed to add the webhook URL: %v", err)
+	}
+	c.AddWebhookURL("https://hooks.example.com")
+	if len(c.Webhooks.URLs) != 1 || c.Webhooks.Retries != defaultWebhookRetries {
+		t.Errorf("Unexpected webhook settings after adding the URL: %+v", c.Webhooks)
+	}
+}
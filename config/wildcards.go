@@ -0,0 +1,89 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+// WildcardConfig declares the answers known to be returned by the DNS wildcard of a zone,
+// so the names within the zone can be filtered without probing for the wildcard.
+type WildcardConfig struct {
+	Zone string
+	// The addresses and netblocks returned by the wildcard
+	Netblocks []*net.IPNet
+	// The regular expressions matching other record data returned by the wildcard
+	Patterns []*regexp.Regexp
+}
+
+func (c *Config) loadWildcardSettings(cfg *ini.File) error {
+	sec, err := cfg.GetSection("wildcards")
+	if err != nil {
+		return nil
+	}
+
+	for _, child := range sec.ChildSections() {
+		zone := strings.Trim(strings.TrimPrefix(child.Name(), "wildcards."), ".")
+		if zone == "" {
+			continue
+		}
+
+		w := &WildcardConfig{Zone: zone}
+		if child.HasKey("address") {
+			for _, addr := range child.Key("address").ValueWithShadows() {
+				ipnet, err := parseWildcardAddress(addr)
+				if err != nil {
+					return fmt.Errorf("The %s wildcard declaration: %v", zone, err)
+				}
+
+				w.Netblocks = append(w.Netblocks, ipnet)
+			}
+		}
+		if child.HasKey("pattern") {
+			for _, p := range child.Key("pattern").ValueWithShadows() {
+				re, err := regexp.Compile(p)
+				if err != nil {
+					return fmt.Errorf("The %s wildcard declaration has an invalid pattern %s: %v", zone, p, err)
+				}
+
+				w.Patterns = append(w.Patterns, re)
+			}
+		}
+
+		if len(w.Netblocks) == 0 && len(w.Patterns) == 0 {
+			return fmt.Errorf("The %s wildcard declaration did not provide an address or pattern", zone)
+		}
+		c.Wildcards = append(c.Wildcards, w)
+	}
+
+	return nil
+}
+
+func parseWildcardAddress(addr string) (*net.IPNet, error) {
+	addr = strings.TrimSpace(addr)
+
+	if _, ipnet, err := net.ParseCIDR(addr); err == nil {
+		return ipnet, nil
+	}
+
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil, fmt.Errorf("%s is not a valid IP address or netblock", addr)
+	}
+
+	bits := 128
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 32
+	}
+	return &net.IPNet{
+		IP:   ip,
+		Mask: net.CIDRMask(bits, bits),
+	}, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package config
+
+import (
+	"net"
+	"testing"
+
+	"github.com/go-ini/ini"
+)
+
+func TestWildcardSettings(t *testing.T) {
+	c := NewConfig()
+	cfg, _ := ini.LoadSources(
+		ini.LoadOptions{
+			Insensitive:  true,
+			AllowShadows: true,
+		},
+		[]byte(`
+		[wildcards]
+		[wildcards.apps.owasp.org]
+		address = 192.0.2.10
+		address = 2001:db8::/64
+		pattern = \.cdn\.example\.net$
+		`),
+	)
+
+	if err := c.loadWildcardSettings(cfg); err != nil {
+		t.Fatalf("Failed to parse the wildcard settings: %v", err)
+	}
+	if len(c.Wildcards) != 1 {
+		t.Fatalf("%d wildcard declarations were loaded instead of one", len(c.Wildcards))
+	}
+
+	w := c.Wildcards[0]
+	if w.Zone != "apps.owasp.org" || len(w.Netblocks) != 2 || len(w.Patterns) != 1 {
+		t.Fatalf("The wildcard declaration was not loaded correctly: %+v", w)
+	}
+	if !w.Netblocks[0].Contains(net.ParseIP("192.0.2.10")) || w.Netblocks[0].Contains(net.ParseIP("192.0.2.11")) {
+		t.Errorf("The single address was not converted to a host netblock: %s", w.Netblocks[0])
+	}
+	if !w.Netblocks[1].Contains(net.ParseIP("2001:db8::1")) {
+		t.Errorf("The IPv6 netblock was not loaded correctly: %s", w.Netblocks[1])
+	}
+	if !w.Patterns[0].MatchString("abc.cdn.example.net") {
+		t.Errorf("The pattern was not loaded correctly: %s", w.Patterns[0])
+	}
+
+	for _, bad := range []string{
+		"[wildcards.owasp.org]\naddress = 192.0.2.300",
+		"[wildcards.owasp.org]\npattern = ([a-z]",
+		"[wildcards.owasp.org]\nenabled = true",
+	} {
+		c := NewConfig()
+		cfg, _ := ini.LoadSources(ini.LoadOptions{Insensitive: true, AllowShadows: true}, []byte("[wildcards]\n"+bad))
+
+		if err := c.loadWildcardSettings(cfg); err == nil {
+			t.Errorf("The invalid wildcard declaration was accepted: %s", bad)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+)
+
+// The number of days of abuse reports considered by AbuseIPDB
+const abuseIPDBMaxAge = 90
+
+// AbuseIPDB is the Service that handles access to the AbuseIPDB IP reputation data source.
+type AbuseIPDB struct {
+	requests.BaseService
+
+	SourceType string
+	sys        systems.System
+	creds      *config.Credentials
+}
+
+type abuseIPDBResponse struct {
+	Data struct {
+		IPAddress     string `json:"ipAddress"`
+		IsWhitelisted bool   `json:"isWhitelisted"`
+		Score         int    `json:"abuseConfidenceScore"`
+		UsageType     string `json:"usageType"`
+		TotalReports  int    `json:"totalReports"`
+	} `json:"data"`
+	Errors []struct {
+		Detail string `json:"detail"`
+	} `json:"errors"`
+}
+
+// NewAbuseIPDB returns the object initialized, but not yet started.
+func NewAbuseIPDB(sys systems.System) *AbuseIPDB {
+	a := &AbuseIPDB{
+		SourceType: requests.API,
+		sys:        sys,
+	}
+
+	a.BaseService = *requests.NewBaseService(a, "AbuseIPDB")
+	return a
+}
+
+// Type implements the Service interface.
+func (a *AbuseIPDB) Type() string {
+	return a.SourceType
+}
+
+// OnStart implements the Service interface.
+func (a *AbuseIPDB) OnStart() error {
+	a.BaseService.OnStart()
+
+	a.creds = a.sys.Config().GetDataSourceConfig(a.String()).GetCredentials()
+	if a.creds == nil || a.creds.Key == "" {
+		a.sys.Config().Log.Printf("%s: API key data was not provided", a.String())
+	}
+
+	a.SetRateLimit(time.Second)
+	return nil
+}
+
+// CheckConfig implements the Service interface.
+func (a *AbuseIPDB) CheckConfig() error {
+	creds := a.sys.Config().GetDataSourceConfig(a.String()).GetCredentials()
+
+	if creds == nil || creds.Key == "" {
+		estr := fmt.Sprintf("%s: check callback failed for the configuration", a.String())
+		a.sys.Config().Log.Print(estr)
+		return errors.New(estr)
+	}
+
+	return nil
+}
+
+// OnAddrRequest implements the Service interface.
+func (a *AbuseIPDB) OnAddrRequest(ctx context.Context, req *requests.AddrRequest) {
+	if req != nil {
+		publishReputation(ctx, a, req.Address)
+	}
+}
+
+// AddressReputation implements the ReputationProvider interface.
+func (a *AbuseIPDB) AddressReputation(ctx context.Context, addr string) (*requests.ReputationRequest, error) {
+	_, bus, err := ContextConfigBus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.creds == nil || a.creds.Key == "" {
+		return nil, nil
+	}
+
+	a.CheckRateLimit()
+	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, a.String())
+
+	headers := map[string]string{
+		"Accept": "application/json",
+		"Key":    a.creds.Key,
+	}
+	page, err := requestWebPage(a.sys, a, a.getURL(addr), nil, headers, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return a.parseResponse(addr, page)
+}
+
+func (a *AbuseIPDB) parseResponse(addr, page string) (*requests.ReputationRequest, error) {
+	var resp abuseIPDBResponse
+	if err := json.Unmarshal([]byte(page), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, errors.New(resp.Errors[0].Detail)
+	}
+
+	rep := &requests.ReputationRequest{
+		Address:        addr,
+		Classification: scoreClassification(resp.Data.Score),
+		Score:          resp.Data.Score,
+		Tag:            a.SourceType,
+		Source:         a.String(),
+	}
+	if resp.Data.IsWhitelisted {
+		rep.Classification = requests.ReputationBenign
+	}
+	if resp.Data.UsageType != "" {
+		rep.Categories = append(rep.Categories, resp.Data.UsageType)
+	}
+	return rep, nil
+}
+
+func (a *AbuseIPDB) getURL(addr string) string {
+	params := url.Values{}
+	params.Set("ipAddress", addr)
+	params.Set("maxAgeInDays", strconv.Itoa(abuseIPDBMaxAge))
+
+	return "https://api.abuseipdb.com/api/v2/check?" + params.Encode()
+}
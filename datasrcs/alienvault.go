@@ -15,7 +15,6 @@ import (
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/eventbus"
-	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/OWASP/Amass/v3/systems"
@@ -103,7 +102,7 @@ func (a *AlienVault) executeDNSQuery(ctx context.Context, req *requests.DNSReque
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, a.String())
 
 	u := a.getURL(req.Domain) + "passive_dns"
-	page, err := http.RequestWebPage(u, nil, a.getHeaders(), "", "")
+	page, err := requestWebPage(a.sys, a, u, nil, a.getHeaders(), "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", a.String(), u, err))
 		return
@@ -174,7 +173,7 @@ func (a *AlienVault) executeURLQuery(ctx context.Context, req *requests.DNSReque
 
 	headers := a.getHeaders()
 	u := a.getURL(req.Domain) + "url_list"
-	page, err := http.RequestWebPage(u, nil, headers, "", "")
+	page, err := requestWebPage(a.sys, a, u, nil, headers, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", a.String(), u, err))
 		return
@@ -205,7 +204,7 @@ func (a *AlienVault) executeURLQuery(ctx context.Context, req *requests.DNSReque
 		for cur := m.PageNum + 1; cur <= pages; cur++ {
 			a.CheckRateLimit()
 			pageURL := u + "?page=" + strconv.Itoa(cur)
-			page, err = http.RequestWebPage(pageURL, nil, headers, "", "")
+			page, err = requestWebPage(a.sys, a, pageURL, nil, headers, "", "")
 			if err != nil {
 				bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
 					fmt.Sprintf("%s: %s: %v", a.String(), pageURL, err))
@@ -269,7 +268,7 @@ func (a *AlienVault) executeWhoisQuery(ctx context.Context, req *requests.WhoisR
 		bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, a.String())
 
 		pageURL := a.getReverseWhoisURL(email)
-		page, err := http.RequestWebPage(pageURL, nil, headers, "", "")
+		page, err := requestWebPage(a.sys, a, pageURL, nil, headers, "", "")
 		if err != nil {
 			bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
 				fmt.Sprintf("%s: %s: %v", a.String(), pageURL, err))
@@ -319,7 +318,7 @@ func (a *AlienVault) queryWhoisForEmails(ctx context.Context, req *requests.Whoi
 
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, a.String())
 
-	page, err := http.RequestWebPage(u, nil, a.getHeaders(), "", "")
+	page, err := requestWebPage(a.sys, a, u, nil, a.getHeaders(), "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", a.String(), u, err))
 		return emails.Slice()
@@ -0,0 +1,255 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/eventbus"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/net/dns"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+)
+
+const (
+	certstreamURL         = "wss://certstream.calidog.io/"
+	certstreamDialTimeout = 20 * time.Second
+	// The delays between attempts to reestablish the stream
+	certstreamMinBackoff = 5 * time.Second
+	certstreamMaxBackoff = time.Minute
+)
+
+// Certstream is the Service that handles access to the Certstream data source. Unlike the
+// other data sources, it streams the names from newly issued certificates for the duration
+// of the enumerations, which catches names that static CT log queries have not yet seen.
+type Certstream struct {
+	requests.BaseService
+
+	SourceType string
+	sys        systems.System
+	lock       sync.Mutex
+	started    bool
+	// The contexts of the enumerations that will receive names from the stream
+	ctxs []context.Context
+}
+
+// NewCertstream returns he object initialized, but not yet started.
+func NewCertstream(sys systems.System) *Certstream {
+	c := &Certstream{
+		SourceType: requests.CERT,
+		sys:        sys,
+	}
+
+	c.BaseService = *requests.NewBaseService(c, "Certstream")
+	return c
+}
+
+// Type implements the Service interface.
+func (c *Certstream) Type() string {
+	return c.SourceType
+}
+
+// OnDNSRequest implements the Service interface.
+func (c *Certstream) OnDNSRequest(ctx context.Context, req *requests.DNSRequest) {
+	_, bus, err := ContextConfigBus(ctx)
+	if err != nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, registered := range c.ctxs {
+		if registered == ctx {
+			return
+		}
+	}
+	c.ctxs = append(c.ctxs, ctx)
+
+	bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
+		fmt.Sprintf("Streaming %s certificates for the duration of the enumeration", c.String()))
+
+	// A single stream serves all the enumerations
+	if !c.started {
+		c.started = true
+		go c.stream()
+	}
+}
+
+// activeContexts returns the contexts of enumerations that have not completed.
+func (c *Certstream) activeContexts() []context.Context {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var active []context.Context
+	for _, ctx := range c.ctxs {
+		select {
+		case <-ctx.Done():
+		default:
+			active = append(active, ctx)
+		}
+	}
+
+	c.ctxs = active
+	return active
+}
+
+func (c *Certstream) stream() {
+	backoff := certstreamMinBackoff
+
+	for {
+		start := time.Now()
+		err := c.readStream()
+
+		select {
+		case <-c.Quit():
+			return
+		default:
+		}
+		if c.finished() {
+			return
+		}
+
+		if err != nil {
+			c.sys.Config().Log.Printf("%s: %v", c.String(), err)
+		}
+
+		var wait time.Duration
+		wait, backoff = certstreamBackoff(backoff, time.Since(start))
+
+		t := time.NewTimer(wait)
+		select {
+		case <-c.Quit():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// certstreamBackoff returns the delay before the stream is reestablished and the delay for the
+// attempt after it. Streams that were established for a while reset the delay.
+func certstreamBackoff(backoff, established time.Duration) (time.Duration, time.Duration) {
+	if established > certstreamMaxBackoff {
+		backoff = certstreamMinBackoff
+	}
+
+	next := backoff * 2
+	if next > certstreamMaxBackoff {
+		next = certstreamMaxBackoff
+	}
+	return backoff, next
+}
+
+// finished returns true and allows the stream to be started again when all the
+// enumerations have completed.
+func (c *Certstream) finished() bool {
+	if len(c.activeContexts()) > 0 {
+		return false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Another enumeration could have been registered in the meantime
+	if len(c.ctxs) > 0 {
+		return false
+	}
+	c.started = false
+	return true
+}
+
+func (c *Certstream) readStream() error {
+	ctx, cancel := context.WithTimeout(context.Background(), certstreamDialTimeout)
+	start := time.Now()
+	d := ws.Dialer{NetDial: amassnet.DialContext}
+	conn, br, _, err := d.Dial(ctx, certstreamURL)
+	cancel()
+	if sys, ok := c.sys.(systems.SourceStatsSystem); ok && sys.SourceStats() != nil {
+		sys.SourceStats().AddRequest(c.String(), time.Since(start), err)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to connect to %s: %v", certstreamURL, err)
+	}
+	defer conn.Close()
+
+	// Closing the connection interrupts the reads when the service is stopped
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-c.Quit():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	rw := struct {
+		io.Reader
+		io.Writer
+	}{Reader: conn, Writer: conn}
+	// The dialer can return data read beyond the handshake
+	if br != nil {
+		rw.Reader = io.MultiReader(br, conn)
+		defer ws.PutReader(br)
+	}
+
+	for {
+		msg, _, err := wsutil.ReadServerData(rw)
+		if err != nil {
+			return fmt.Errorf("The stream was interrupted: %v", err)
+		}
+
+		if !c.processMessage(msg) {
+			return nil
+		}
+	}
+}
+
+// processMessage returns false when no enumerations remain to receive the names.
+func (c *Certstream) processMessage(msg []byte) bool {
+	var m struct {
+		Type string `json:"message_type"`
+		Data struct {
+			LeafCert struct {
+				AllDomains []string `json:"all_domains"`
+			} `json:"leaf_cert"`
+		} `json:"data"`
+	}
+	ctxs := c.activeContexts()
+	if len(ctxs) == 0 {
+		return false
+	}
+
+	if err := json.Unmarshal(msg, &m); err != nil || m.Type != "certificate_update" {
+		return true
+	}
+
+	for _, d := range m.Data.LeafCert.AllDomains {
+		name := strings.ToLower(dns.RemoveAsteriskLabel(strings.TrimSpace(d)))
+		if name == "" {
+			continue
+		}
+
+		for _, ctx := range ctxs {
+			cfg, bus, err := ContextConfigBus(ctx)
+			if err != nil || cfg.WhichDomain(name) == "" {
+				continue
+			}
+
+			bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, c.String())
+			genNewNameEvent(ctx, c.sys, c, name)
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestCertstreamProcessMessage(t *testing.T) {
+	c := NewCertstream(nil)
+
+	cfg := config.NewConfig()
+	cfg.AddDomain("example.com")
+
+	bus := eventbus.NewEventBus()
+	defer bus.Stop()
+	rec := eventbus.NewRecorder(bus, requests.NewNameTopic)
+	defer rec.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = context.WithValue(ctx, requests.ContextConfig, cfg)
+	ctx = context.WithValue(ctx, requests.ContextEventBus, bus)
+	c.ctxs = append(c.ctxs, ctx)
+
+	tests := []struct {
+		msg   string
+		names []string
+	}{
+		{`{"message_type":"certificate_update","data":{"leaf_cert":{"all_domains":["*.Example.com"," www.example.com ","www.owasp.org"]}}}`,
+			[]string{"example.com", "www.example.com"}},
+		{`{"message_type":"certificate_update","data":{"leaf_cert":{"all_domains":["mail.example.com.evil.net",""]}}}`, nil},
+		{`{"message_type":"heartbeat","data":{"leaf_cert":{"all_domains":["api.example.com"]}}}`, nil},
+		{`{"message_type":`, nil},
+	}
+
+	var total int
+	for _, test := range tests {
+		if !c.processMessage([]byte(test.msg)) {
+			t.Errorf("processMessage stopped the stream while the enumeration was active: %s", test.msg)
+		}
+
+		total += len(test.names)
+		if !rec.Wait(requests.NewNameTopic, total, 5*time.Second) {
+			t.Fatalf("The message %s provided %d names instead of %d", test.msg, rec.Len(requests.NewNameTopic), total)
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	var names []string
+	for _, msg := range rec.Messages(requests.NewNameTopic) {
+		req := msg[0].(*requests.DNSRequest)
+
+		if req.Domain != "example.com" || req.Source != "Certstream" || req.Tag != requests.CERT {
+			t.Errorf("Unexpected request published for the certificate: %+v", req)
+		}
+		names = append(names, req.Name)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "example.com" || names[1] != "www.example.com" {
+		t.Errorf("The names %v were published instead of the names in scope", names)
+	}
+
+	// The stream is no longer needed once the enumeration completes
+	cancel()
+	if c.processMessage([]byte(tests[0].msg)) {
+		t.Errorf("processMessage continued the stream after the enumeration completed")
+	}
+}
+
+func TestCertstreamBackoff(t *testing.T) {
+	tests := []struct {
+		backoff     time.Duration
+		established time.Duration
+		wait        time.Duration
+		next        time.Duration
+	}{
+		{certstreamMinBackoff, 0, certstreamMinBackoff, 2 * certstreamMinBackoff},
+		{2 * certstreamMinBackoff, time.Second, 2 * certstreamMinBackoff, 4 * certstreamMinBackoff},
+		{certstreamMaxBackoff, time.Second, certstreamMaxBackoff, certstreamMaxBackoff},
+		{40 * time.Second, time.Second, 40 * time.Second, certstreamMaxBackoff},
+		// Streams that were established for a while start over with the shortest delay
+		{certstreamMaxBackoff, time.Hour, certstreamMinBackoff, 2 * certstreamMinBackoff},
+	}
+
+	for _, test := range tests {
+		wait, next := certstreamBackoff(test.backoff, test.established)
+
+		if wait != test.wait || next != test.next {
+			t.Errorf("certstreamBackoff(%v, %v) returned %v, %v instead of %v, %v",
+				test.backoff, test.established, wait, next, test.wait, test.next)
+		}
+	}
+}
@@ -13,7 +13,6 @@ import (
 	"time"
 
 	"github.com/OWASP/Amass/v3/eventbus"
-	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringfilter"
 	"github.com/OWASP/Amass/v3/systems"
@@ -51,7 +50,7 @@ func (c *CommonCrawl) OnStart() error {
 	c.BaseService.OnStart()
 
 	// Get all of the index API URLs
-	page, err := http.RequestWebPage(commonCrawlIndexListURL, nil, nil, "", "")
+	page, err := requestWebPage(c.sys, c, commonCrawlIndexListURL, nil, nil, "", "")
 	if err != nil {
 		c.sys.Config().Log.Printf("%s: Failed to obtain the index list: %v", c.String(), err)
 		return fmt.Errorf("%s: Failed to obtain the index list: %v", c.String(), err)
@@ -104,7 +103,7 @@ func (c *CommonCrawl) OnDNSRequest(ctx context.Context, req *requests.DNSRequest
 			bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, c.String())
 
 			u := c.getURL(req.Domain, index)
-			page, err := http.RequestWebPage(u, nil, nil, "", "")
+			page, err := requestWebPage(c.sys, c, u, nil, nil, "", "")
 			if err != nil {
 				bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", c.String(), u, err))
 				continue
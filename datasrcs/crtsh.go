@@ -7,11 +7,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/eventbus"
 	"github.com/OWASP/Amass/v3/net/dns"
-	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/OWASP/Amass/v3/systems"
@@ -19,6 +20,11 @@ import (
 	_ "github.com/lib/pq" // Need the postgres driver
 )
 
+const (
+	crtshMaxAttempts = 3
+	crtshBackoff     = 2 * time.Second
+)
+
 // Crtsh is the Service that handles access to the Crtsh data source.
 type Crtsh struct {
 	requests.BaseService
@@ -82,21 +88,43 @@ func (c *Crtsh) OnDNSRequest(ctx context.Context, req *requests.DNSRequest) {
 }
 
 func (c *Crtsh) executeQuery(ctx context.Context, domain string) {
-	_, bus, err := ContextConfigBus(ctx)
+	cfg, bus, err := ContextConfigBus(ctx)
 	if err != nil {
 		return
 	}
 
 	var results []struct {
+		ID     int64  `db:"id"`
 		Domain string `db:"domain"`
 	}
 
 	pattern := "%." + domain
-	err = c.db.Select(&results,
-		`SELECT DISTINCT ci.NAME_VALUE as domain
+	query := `SELECT DISTINCT ci.CERTIFICATE_ID as id, ci.NAME_VALUE as domain
 		FROM certificate_identity ci
-		WHERE reverse(lower(ci.NAME_VALUE)) LIKE reverse(lower($1))
-		ORDER BY ci.NAME_VALUE`, pattern)
+		WHERE reverse(lower(ci.NAME_VALUE)) LIKE reverse(lower($1))`
+	args := []interface{}{pattern}
+	// Only request the certificates issued since the previous execution
+	if cursor, err := strconv.ParseInt(getSourceCursor(cfg, c.String(), domain), 10, 64); err == nil {
+		query += " AND ci.CERTIFICATE_ID > $2"
+		args = append(args, cursor)
+	}
+	query += " ORDER BY ci.NAME_VALUE"
+
+	for attempt := 0; ; attempt++ {
+		err = c.db.SelectContext(ctx, &results, query, args...)
+		if err == nil || attempt >= crtshMaxAttempts-1 {
+			break
+		}
+
+		// The public database often cancels statements under load, so back off before trying again
+		t := time.NewTimer(time.Duration(1<<uint(attempt)) * crtshBackoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
 			fmt.Sprintf("%s: Query pattern %s: %v", c.String(), pattern, err))
@@ -105,25 +133,30 @@ func (c *Crtsh) executeQuery(ctx context.Context, domain string) {
 
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, c.String())
 
+	var max int64
 	// Extract the subdomain names from the results
 	names := stringset.New()
 	for _, result := range results {
 		names.Insert(dns.RemoveAsteriskLabel(result.Domain))
+		if result.ID > max {
+			max = result.ID
+		}
 	}
 
 	for name := range names {
 		genNewNameEvent(ctx, c.sys, c, name)
 	}
+	c.saveCursor(cfg, domain, max)
 }
 
 func (c *Crtsh) scrape(ctx context.Context, domain string) {
-	_, bus, err := ContextConfigBus(ctx)
+	cfg, bus, err := ContextConfigBus(ctx)
 	if err != nil {
 		return
 	}
 
 	url := c.getURL(domain)
-	page, err := http.RequestWebPage(url, nil, nil, "", "")
+	page, err := requestWebPage(c.sys, c, url, nil, nil, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", c.String(), url, err))
 		return
@@ -133,14 +166,37 @@ func (c *Crtsh) scrape(ctx context.Context, domain string) {
 
 	// Extract the subdomain names from the results
 	var results []struct {
+		ID   int64  `json:"id"`
 		Name string `json:"name_value"`
 	}
 	if err := json.Unmarshal([]byte(page), &results); err != nil {
 		return
 	}
+
+	// The web interface has no time filter, so previously seen certificates are skipped here
+	cursor, _ := strconv.ParseInt(getSourceCursor(cfg, c.String(), domain), 10, 64)
+
+	max := cursor
 	for _, line := range results {
+		if line.ID <= cursor {
+			continue
+		}
+		if line.ID > max {
+			max = line.ID
+		}
 		genNewNameEvent(ctx, c.sys, c, line.Name)
 	}
+	c.saveCursor(cfg, domain, max)
+}
+
+func (c *Crtsh) saveCursor(cfg *config.Config, domain string, id int64) {
+	if id <= 0 {
+		return
+	}
+
+	if err := setSourceCursor(cfg, c.String(), domain, strconv.FormatInt(id, 10)); err != nil {
+		cfg.Log.Printf("%s: Failed to save the cursor for %s: %v", c.String(), domain, err)
+	}
 }
 
 func (c *Crtsh) getURL(domain string) string {
@@ -0,0 +1,115 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/OWASP/Amass/v3/config"
+)
+
+// The name of the file used for incremental data source cursors within the output directory
+const sourceCursorsFileName = "source_cursors.json"
+
+// Serializes access to the cursors file shared by all the data sources
+var sourceCursorsLock sync.Mutex
+
+// The cursors are stored per data source and root domain
+type sourceCursors map[string]map[string]string
+
+// getSourceCursor returns the position reached by the previous execution of the data source
+// for the domain. An empty string is returned when the enumeration is not in monitor mode.
+func getSourceCursor(cfg *config.Config, source, domain string) string {
+	if !cfg.Monitor {
+		return ""
+	}
+
+	sourceCursorsLock.Lock()
+	defer sourceCursorsLock.Unlock()
+
+	cursors, err := loadSourceCursors(cfg)
+	if err != nil {
+		return ""
+	}
+	if domains, found := cursors[source]; found {
+		return domains[domain]
+	}
+	return ""
+}
+
+// setSourceCursor persists the position reached by the data source for the domain, so
+// the next execution in monitor mode can request only the results issued afterwards.
+func setSourceCursor(cfg *config.Config, source, domain, cursor string) error {
+	if cursor == "" {
+		return nil
+	}
+
+	sourceCursorsLock.Lock()
+	defer sourceCursorsLock.Unlock()
+
+	cursors, err := loadSourceCursors(cfg)
+	if err != nil {
+		cursors = make(sourceCursors)
+	}
+	if _, found := cursors[source]; !found {
+		cursors[source] = make(map[string]string)
+	}
+	cursors[source][domain] = cursor
+
+	return saveSourceCursors(cfg, cursors)
+}
+
+func sourceCursorsPath(cfg *config.Config) string {
+	dir := config.OutputDirectory(cfg.Dir)
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, sourceCursorsFileName)
+}
+
+func loadSourceCursors(cfg *config.Config) (sourceCursors, error) {
+	path := sourceCursorsPath(cfg)
+	if path == "" {
+		return nil, fmt.Errorf("Failed to obtain the output directory")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cursors := make(sourceCursors)
+	if err := json.Unmarshal(data, &cursors); err != nil {
+		return nil, fmt.Errorf("Failed to parse the data source cursors: %v", err)
+	}
+	return cursors, nil
+}
+
+func saveSourceCursors(cfg *config.Config, cursors sourceCursors) error {
+	path := sourceCursorsPath(cfg)
+	if path == "" {
+		return fmt.Errorf("Failed to obtain the output directory")
+	}
+
+	data, err := json.MarshalIndent(cursors, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("Failed to write the data source cursors: %v", err)
+	}
+	// The rename prevents a partially written file from replacing the previous cursors
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -5,8 +5,8 @@ package datasrcs
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -16,9 +16,14 @@ import (
 	"github.com/OWASP/Amass/v3/eventbus"
 	amasshttp "github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/OWASP/Amass/v3/systems"
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
 )
 
+const dnsDumpsterURL = "https://dnsdumpster.com/"
+
 // DNSDumpster is the Service that handles access to the DNSDumpster data source.
 type DNSDumpster struct {
 	requests.BaseService
@@ -68,89 +73,137 @@ func (d *DNSDumpster) OnDNSRequest(ctx context.Context, req *requests.DNSRequest
 	bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
 		fmt.Sprintf("Querying %s for %s subdomains", d.String(), req.Domain))
 
-	u := "https://dnsdumpster.com/"
-	page, err := amasshttp.RequestWebPage(u, nil, nil, "", "")
+	client, err := amasshttp.ProxyClient(cfg.DataSourceProxy(d.String()))
 	if err != nil {
-		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", d.String(), u, err))
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %v", d.String(), err))
+		return
+	}
+
+	// The cache is not used, since the CSRF token and cookie must be fresh
+	tctx, cancel := context.WithTimeout(ctx, cfg.DataSourceTimeout(d.String()))
+	page, err := withBreaker(d.sys, d, func() (string, error) {
+		return amasshttp.RequestWebPageWithContext(tctx, client, dnsDumpsterURL, nil, nil, "", "")
+	})
+	cancel()
+	if err != nil {
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", d.String(), dnsDumpsterURL, err))
 		return
 	}
 
 	token := d.getCSRFToken(page)
 	if token == "" {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
-			fmt.Sprintf("%s: %s: Failed to obtain the CSRF token", d.String(), u))
+			fmt.Sprintf("%s: %s: Failed to obtain the CSRF token", d.String(), dnsDumpsterURL))
 		return
 	}
 
 	d.CheckRateLimit()
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, d.String())
 
-	page, err = d.postForm(ctx, token, req.Domain)
+	tctx, cancel = context.WithTimeout(ctx, cfg.DataSourceTimeout(d.String()))
+	page, err = withBreaker(d.sys, d, func() (string, error) {
+		return d.postForm(tctx, client, token, req.Domain)
+	})
+	cancel()
 	if err != nil {
-		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", d.String(), u, err))
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", d.String(), dnsDumpsterURL, err))
 		return
 	}
 
-	for _, sd := range re.FindAllString(page, -1) {
-		genNewNameEvent(ctx, d.sys, d, amasshttp.CleanName(sd))
+	names, err := d.parseResults(page, re)
+	if err != nil {
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", d.String(), req.Domain, err))
+		return
+	}
+
+	for _, name := range names {
+		genNewNameEvent(ctx, d.sys, d, name)
 	}
 }
 
 func (d *DNSDumpster) getCSRFToken(page string) string {
-	re := regexp.MustCompile(`<input type="hidden" name="csrfmiddlewaretoken" value="([a-zA-Z0-9]*)">`)
-
-	if subs := re.FindStringSubmatch(page); len(subs) == 2 {
-		return strings.TrimSpace(subs[1])
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		return ""
 	}
-	return ""
+
+	token, _ := doc.Find(`input[name="csrfmiddlewaretoken"]`).First().Attr("value")
+	return strings.TrimSpace(token)
 }
 
-func (d *DNSDumpster) postForm(ctx context.Context, token, domain string) (string, error) {
-	_, bus, err := ContextConfigBus(ctx)
-	if err != nil {
-		return "", fmt.Errorf("%s failed to obtain the EventBus from Context", d.String())
+func (d *DNSDumpster) postForm(ctx context.Context, client *http.Client, token, domain string) (string, error) {
+	u, _ := url.Parse(dnsDumpsterURL)
+
+	// The cookie issued with the form is sent back by the client, so it is only
+	// provided here when the server did not set one
+	var found bool
+	if client.Jar != nil {
+		for _, c := range client.Jar.Cookies(u) {
+			if c.Name == "csrftoken" && c.Value != "" {
+				found = true
+				break
+			}
+		}
+	}
+
+	headers := map[string]string{
+		"Content-Type": "application/x-www-form-urlencoded",
+		"Referer":      dnsDumpsterURL,
+		"X-CSRF-Token": token,
+	}
+	if !found {
+		headers["Cookie"] = (&http.Cookie{Name: "csrftoken", Value: token}).String()
 	}
 
 	params := url.Values{
 		"csrfmiddlewaretoken": {token},
 		"targetip":            {domain},
+		"user":                {"free"},
 	}
 
-	req, err := http.NewRequest("POST", "https://dnsdumpster.com/", strings.NewReader(params.Encode()))
+	return amasshttp.RequestWebPageWithContext(ctx, client, dnsDumpsterURL,
+		strings.NewReader(params.Encode()), headers, "", "")
+}
+
+// parseResults extracts the names in scope from the cells of the result tables.
+func (d *DNSDumpster) parseResults(page string, re *regexp.Regexp) ([]string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
 	if err != nil {
-		bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
-			fmt.Sprintf("%s: Failed to setup the POST request: %v", d.String(), err))
-		return "", err
+		return nil, err
 	}
-	// The CSRF token needs to be sent as a cookie
-	cookie := &http.Cookie{
-		Name:   "csrftoken",
-		Domain: "dnsdumpster.com",
-		Value:  token,
+
+	tables := doc.Find("table")
+	if tables.Length() == 0 {
+		// The form is returned again when the search was refused, such as by the daily limit
+		if strings.Contains(strings.ToLower(page), "too many") {
+			return nil, errors.New("The search limit has been reached")
+		}
+		return nil, errors.New("No results were returned")
 	}
-	req.AddCookie(cookie)
 
-	req.Header.Set("User-Agent", amasshttp.UserAgent)
-	req.Header.Set("Accept", amasshttp.Accept)
-	req.Header.Set("Accept-Language", amasshttp.AcceptLang)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Referer", "https://dnsdumpster.com")
-	req.Header.Set("X-CSRF-Token", token)
+	names := stringset.New()
+	tables.Find("td").Each(func(i int, cell *goquery.Selection) {
+		// The values of a cell, such as the host name and the IP address, are separate text nodes
+		for _, n := range cell.Nodes {
+			for _, text := range textNodes(n) {
+				for _, name := range re.FindAllString(text, -1) {
+					names.Insert(amasshttp.CleanName(name))
+				}
+			}
+		}
+	})
+
+	return names.Slice(), nil
+}
 
-	resp, err := amasshttp.DefaultClient.Do(req)
-	if err != nil {
-		bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
-			fmt.Sprintf("%s: The POST request failed: %v", d.String(), err))
-		return "", err
+func textNodes(n *html.Node) []string {
+	if n.Type == html.TextNode {
+		return []string{n.Data}
 	}
-	defer resp.Body.Close()
 
-	// Now, grab the entire page
-	in, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
-			fmt.Sprintf("Failed to read response body: %v", err))
-		return "", err
+	var texts []string
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		texts = append(texts, textNodes(c)...)
 	}
-	return string(in), nil
+	return texts
 }
@@ -0,0 +1,163 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/stringset"
+	"github.com/OWASP/Amass/v3/systems"
+)
+
+// DomainTools is the Service that handles access to the DomainTools Reverse Whois API.
+type DomainTools struct {
+	requests.BaseService
+
+	SourceType string
+	sys        systems.System
+	creds      *config.Credentials
+}
+
+type domainToolsResponse struct {
+	Response struct {
+		Domains []string `json:"domains"`
+	} `json:"response"`
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewDomainTools returns the object initialized, but not yet started.
+func NewDomainTools(sys systems.System) *DomainTools {
+	d := &DomainTools{
+		SourceType: requests.API,
+		sys:        sys,
+	}
+
+	d.BaseService = *requests.NewBaseService(d, "DomainTools")
+	return d
+}
+
+// Type implements the Service interface.
+func (d *DomainTools) Type() string {
+	return d.SourceType
+}
+
+// OnStart implements the Service interface.
+func (d *DomainTools) OnStart() error {
+	d.BaseService.OnStart()
+
+	d.creds = d.sys.Config().GetDataSourceConfig(d.String()).GetCredentials()
+	if d.creds == nil || d.creds.Username == "" || d.creds.Key == "" {
+		d.sys.Config().Log.Printf("%s: API username and key data was not provided", d.String())
+	}
+
+	d.SetRateLimit(time.Second)
+	return nil
+}
+
+// CheckConfig implements the Service interface.
+func (d *DomainTools) CheckConfig() error {
+	creds := d.sys.Config().GetDataSourceConfig(d.String()).GetCredentials()
+
+	if creds == nil || creds.Username == "" || creds.Key == "" {
+		estr := fmt.Sprintf("%s: check callback failed for the configuration", d.String())
+		d.sys.Config().Log.Print(estr)
+		return errors.New(estr)
+	}
+
+	return nil
+}
+
+// OnWhoisRequest implements the Service interface. The Reverse Whois API searches the whois
+// records for terms, so the registrant organizations and email addresses are looked up.
+func (d *DomainTools) OnWhoisRequest(ctx context.Context, req *requests.WhoisRequest) {
+	cfg, bus, err := ContextConfigBus(ctx)
+	if err != nil {
+		return
+	}
+
+	if d.creds == nil || d.creds.Username == "" || d.creds.Key == "" {
+		return
+	}
+
+	if req.Domain != "" || (req.Company == "" && req.Email == "") {
+		return
+	}
+	term := whoisSearchTerm(req)
+	bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
+		fmt.Sprintf("Querying %s for domains registered by %s", d.String(), term))
+
+	d.CheckRateLimit()
+	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, d.String())
+
+	page, err := requestWebPage(d.sys, d, d.getReverseWhoisURL(term), nil, nil, "", "")
+	if err != nil {
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", d.String(), term, err))
+		return
+	}
+
+	domains, err := parseDomainToolsReverseWhois(page)
+	if err != nil {
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", d.String(), term, err))
+		return
+	}
+
+	found := stringset.New()
+	for _, name := range domains {
+		if !cfg.IsDomainInScope(name) {
+			found.Insert(name)
+		}
+	}
+
+	if len(found) > 0 {
+		bus.Publish(requests.NewWhoisTopic, eventbus.PriorityHigh, &requests.WhoisRequest{
+			Company:    req.Company,
+			Email:      req.Email,
+			NewDomains: found.Slice(),
+			Tag:        d.SourceType,
+			Source:     d.String(),
+		})
+	}
+}
+
+// parseDomainToolsReverseWhois returns the domain names from the Reverse Whois API response.
+func parseDomainToolsReverseWhois(page string) ([]string, error) {
+	var resp domainToolsResponse
+
+	if err := json.NewDecoder(strings.NewReader(page)).Decode(&resp); err != nil {
+		return nil, err
+	}
+	if resp.Error.Message != "" {
+		return nil, fmt.Errorf("error %d: %s", resp.Error.Code, resp.Error.Message)
+	}
+
+	var domains []string
+	for _, name := range resp.Response.Domains {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			domains = append(domains, name)
+		}
+	}
+	return domains, nil
+}
+
+func (d *DomainTools) getReverseWhoisURL(term string) string {
+	v := url.Values{}
+
+	v.Set("terms", term)
+	v.Set("mode", "purchase")
+	v.Set("api_username", d.creds.Username)
+	v.Set("api_key", d.creds.Key)
+	return "https://api.domaintools.com/v1/reverse-whois/?" + v.Encode()
+}
@@ -0,0 +1,30 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDomainToolsReverseWhois(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "domaintools_reverse_whois.json"))
+	if err != nil {
+		t.Fatalf("Failed to read the fixture: %v", err)
+	}
+
+	domains, err := parseDomainToolsReverseWhois(string(data))
+	if err != nil {
+		t.Fatalf("Failed to parse the reverse whois response: %v", err)
+	}
+	if strings.Join(domains, ",") != "example.com,example-holdings.net,example.org" {
+		t.Errorf("The response provided the domains %v", domains)
+	}
+
+	if _, err := parseDomainToolsReverseWhois(`{"error":{"code":403,"message":"Not authorized"}}`); err == nil {
+		t.Errorf("The error response was not reported")
+	}
+}
@@ -0,0 +1,335 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/eventbus"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+)
+
+// ExternalQuery is the line-delimited JSON message written to an external data source for each query.
+type ExternalQuery struct {
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Domain  string `json:"domain,omitempty"`
+	Address string `json:"address,omitempty"`
+}
+
+// ExternalResult is the line-delimited JSON message read from an external data source.
+// Each query is answered by any number of results followed by a result of type "done".
+type ExternalResult struct {
+	ID      int    `json:"id"`
+	Type    string `json:"type"`
+	Name    string `json:"name,omitempty"`
+	Address string `json:"address,omitempty"`
+	Domain  string `json:"domain,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// The methods of the queries sent to external data sources
+const (
+	externalVertical   = "vertical"
+	externalHorizontal = "horizontal"
+	externalAddress    = "address"
+)
+
+// External is the Service that handles access to an executable acting as a data source.
+type External struct {
+	requests.BaseService
+
+	SourceType string
+	sys        systems.System
+	cfg        *config.ExternalSourceConfig
+
+	sync.Mutex
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	results chan *ExternalResult
+	stopped chan struct{}
+	queryID int
+}
+
+// NewExternal returns he object initialized, but not yet started.
+func NewExternal(sys systems.System, cfg *config.ExternalSourceConfig) *External {
+	e := &External{
+		SourceType: cfg.Type,
+		sys:        sys,
+		cfg:        cfg,
+	}
+
+	e.BaseService = *requests.NewBaseService(e, cfg.Name)
+	return e
+}
+
+// Type implements the Service interface.
+func (e *External) Type() string {
+	return e.SourceType
+}
+
+// CheckConfig implements the Service interface.
+func (e *External) CheckConfig() error {
+	if _, err := exec.LookPath(e.cfg.Path); err != nil {
+		estr := fmt.Sprintf("%s: The executable %s is not available: %v", e.String(), e.cfg.Path, err)
+
+		e.sys.Config().Log.Print(estr)
+		return errors.New(estr)
+	}
+	return nil
+}
+
+// OnStart implements the Service interface.
+func (e *External) OnStart() error {
+	e.BaseService.OnStart()
+
+	e.Lock()
+	defer e.Unlock()
+
+	if err := e.startProcess(); err != nil {
+		e.sys.Config().Log.Printf("%s: %v", e.String(), err)
+	}
+	return nil
+}
+
+// OnStop implements the Service interface.
+func (e *External) OnStop() error {
+	e.Lock()
+	defer e.Unlock()
+
+	e.stopProcess()
+	return nil
+}
+
+// OnDNSRequest implements the Service interface.
+func (e *External) OnDNSRequest(ctx context.Context, req *requests.DNSRequest) {
+	if req == nil || req.Domain == "" {
+		return
+	}
+
+	e.query(ctx, &ExternalQuery{
+		Method: externalVertical,
+		Domain: req.Domain,
+	}, fmt.Sprintf("Querying %s for %s subdomains", e.String(), req.Domain))
+}
+
+// OnAddrRequest implements the Service interface.
+func (e *External) OnAddrRequest(ctx context.Context, req *requests.AddrRequest) {
+	if req == nil || req.Address == "" {
+		return
+	}
+
+	e.query(ctx, &ExternalQuery{
+		Method:  externalAddress,
+		Address: req.Address,
+	}, fmt.Sprintf("Querying %s for %s", e.String(), req.Address))
+}
+
+// OnWhoisRequest implements the Service interface.
+func (e *External) OnWhoisRequest(ctx context.Context, req *requests.WhoisRequest) {
+	if req == nil || req.Domain == "" {
+		return
+	}
+
+	e.query(ctx, &ExternalQuery{
+		Method: externalHorizontal,
+		Domain: req.Domain,
+	}, fmt.Sprintf("Querying %s for %s associated domains", e.String(), req.Domain))
+}
+
+func (e *External) query(ctx context.Context, q *ExternalQuery, msg string) {
+	_, bus, err := ContextConfigBus(ctx)
+	if err != nil {
+		return
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	// Restart the executable if it has exited since the previous query
+	if e.cmd == nil {
+		if err := e.startProcess(); err != nil {
+			bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %v", e.String(), err))
+			return
+		}
+	}
+
+	e.CheckRateLimit()
+	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, e.String())
+	bus.Publish(requests.LogTopic, eventbus.PriorityHigh, msg)
+
+	e.queryID++
+	q.ID = e.queryID
+	data, err := json.Marshal(q)
+	if err != nil {
+		return
+	}
+	if _, err := e.stdin.Write(append(data, '\n')); err != nil {
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: Failed to send the query: %v", e.String(), err))
+		e.stopProcess()
+		return
+	}
+
+	t := time.NewTimer(time.Duration(e.cfg.Timeout) * time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
+				fmt.Sprintf("%s: The query %d timed out and the executable will be restarted", e.String(), q.ID))
+			e.stopProcess()
+			return
+		case r, ok := <-e.results:
+			if !ok {
+				bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
+					fmt.Sprintf("%s: The executable exited during query %d", e.String(), q.ID))
+				e.stopProcess()
+				return
+			}
+			// Ignore results from previous queries that timed out
+			if r.ID != q.ID {
+				continue
+			}
+			if r.Type == "done" {
+				return
+			}
+
+			bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, e.String())
+			e.processResult(ctx, q, r)
+		}
+	}
+}
+
+func (e *External) processResult(ctx context.Context, q *ExternalQuery, r *ExternalResult) {
+	cfg, bus, err := ContextConfigBus(ctx)
+	if err != nil {
+		return
+	}
+
+	switch r.Type {
+	case "name":
+		if name := subRE.FindString(r.Name); name != "" {
+			genNewNameEvent(ctx, e.sys, e, name)
+		}
+	case "address":
+		if ip := net.ParseIP(r.Address); ip == nil {
+			return
+		}
+		if reserved, _ := amassnet.IsReservedAddress(r.Address); reserved {
+			return
+		}
+
+		name := r.Name
+		if name == "" {
+			name = q.Domain
+		}
+		if domain := cfg.WhichDomain(name); domain != "" {
+			bus.Publish(requests.NewAddrTopic, eventbus.PriorityHigh, &requests.AddrRequest{
+				Address: r.Address,
+				Domain:  domain,
+				Tag:     e.SourceType,
+				Source:  e.String(),
+			})
+		}
+	case "associated":
+		if q.Domain != "" && r.Domain != "" {
+			bus.Publish(requests.NewWhoisTopic, eventbus.PriorityHigh, &requests.WhoisRequest{
+				Domain:     q.Domain,
+				NewDomains: []string{r.Domain},
+				Tag:        e.SourceType,
+				Source:     e.String(),
+			})
+		}
+	case "log", "error":
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s", e.String(), r.Message))
+	}
+}
+
+// startProcess must be called while holding the lock.
+func (e *External) startProcess() error {
+	cmd := exec.Command(e.cfg.Path, e.cfg.Args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("Failed to start the executable %s: %v", e.cfg.Path, err)
+	}
+
+	results := make(chan *ExternalResult, 100)
+	stopped := make(chan struct{})
+	go e.readResults(stdout, results, stopped)
+
+	e.cmd = cmd
+	e.stdin = stdin
+	e.results = results
+	e.stopped = stopped
+	return nil
+}
+
+// stopProcess must be called while holding the lock.
+func (e *External) stopProcess() {
+	if e.cmd == nil {
+		return
+	}
+
+	// Closing the standard input informs the executable that no more queries will be sent
+	e.stdin.Close()
+	close(e.stopped)
+	done := make(chan struct{})
+	go func(cmd *exec.Cmd) {
+		cmd.Wait()
+		close(done)
+	}(e.cmd)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		e.cmd.Process.Kill()
+		<-done
+	}
+
+	e.cmd = nil
+	e.stdin = nil
+}
+
+func (e *External) readResults(stdout io.Reader, results chan *ExternalResult, stopped chan struct{}) {
+	defer close(results)
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r ExternalResult
+
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			e.sys.Config().Log.Printf("%s: Failed to parse the result: %v", e.String(), err)
+			continue
+		}
+		select {
+		case <-stopped:
+			return
+		case results <- &r:
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/net/http"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+)
+
+// GreyNoise is the Service that handles access to the GreyNoise Community API, which classifies
+// the addresses observed scanning the Internet and those of common business services.
+type GreyNoise struct {
+	requests.BaseService
+
+	SourceType string
+	sys        systems.System
+	creds      *config.Credentials
+}
+
+type greyNoiseResponse struct {
+	IP             string `json:"ip"`
+	Noise          bool   `json:"noise"`
+	RIOT           bool   `json:"riot"`
+	Classification string `json:"classification"`
+	Name           string `json:"name"`
+	Message        string `json:"message"`
+}
+
+// NewGreyNoise returns the object initialized, but not yet started.
+func NewGreyNoise(sys systems.System) *GreyNoise {
+	g := &GreyNoise{
+		SourceType: requests.API,
+		sys:        sys,
+	}
+
+	g.BaseService = *requests.NewBaseService(g, "GreyNoise")
+	return g
+}
+
+// Type implements the Service interface.
+func (g *GreyNoise) Type() string {
+	return g.SourceType
+}
+
+// OnStart implements the Service interface.
+func (g *GreyNoise) OnStart() error {
+	g.BaseService.OnStart()
+
+	g.creds = g.sys.Config().GetDataSourceConfig(g.String()).GetCredentials()
+	if g.creds == nil || g.creds.Key == "" {
+		g.sys.Config().Log.Printf("%s: API key data was not provided", g.String())
+	}
+
+	g.SetRateLimit(2 * time.Second)
+	return nil
+}
+
+// CheckConfig implements the Service interface.
+func (g *GreyNoise) CheckConfig() error {
+	creds := g.sys.Config().GetDataSourceConfig(g.String()).GetCredentials()
+
+	if creds == nil || creds.Key == "" {
+		estr := fmt.Sprintf("%s: check callback failed for the configuration", g.String())
+		g.sys.Config().Log.Print(estr)
+		return errors.New(estr)
+	}
+
+	return nil
+}
+
+// OnAddrRequest implements the Service interface.
+func (g *GreyNoise) OnAddrRequest(ctx context.Context, req *requests.AddrRequest) {
+	if req != nil {
+		publishReputation(ctx, g, req.Address)
+	}
+}
+
+// AddressReputation implements the ReputationProvider interface.
+func (g *GreyNoise) AddressReputation(ctx context.Context, addr string) (*requests.ReputationRequest, error) {
+	_, bus, err := ContextConfigBus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.creds == nil || g.creds.Key == "" {
+		return nil, nil
+	}
+
+	g.CheckRateLimit()
+	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, g.String())
+
+	headers := map[string]string{
+		"Accept": "application/json",
+		"key":    g.creds.Key,
+	}
+	page, err := requestWebPage(g.sys, g, "https://api.greynoise.io/v3/community/"+addr, nil, headers, "", "")
+	if err != nil {
+		// Addresses that have not been observed are not found
+		var se *http.StatusError
+		if errors.As(err, &se) && se.StatusCode == nethttp.StatusNotFound {
+			return g.newRequest(addr, &greyNoiseResponse{}), nil
+		}
+		return nil, err
+	}
+
+	var resp greyNoiseResponse
+	if err := json.Unmarshal([]byte(page), &resp); err != nil {
+		return nil, err
+	}
+	return g.newRequest(addr, &resp), nil
+}
+
+func (g *GreyNoise) newRequest(addr string, resp *greyNoiseResponse) *requests.ReputationRequest {
+	rep := &requests.ReputationRequest{
+		Address:        addr,
+		Classification: requests.ReputationUnknown,
+		Tag:            g.SourceType,
+		Source:         g.String(),
+	}
+
+	switch c := strings.ToLower(resp.Classification); c {
+	case requests.ReputationMalicious, requests.ReputationBenign:
+		rep.Classification = c
+	}
+	// The address is scanning the Internet, or belongs to a common business service
+	if resp.Noise {
+		rep.Categories = append(rep.Categories, "noise")
+	}
+	if resp.RIOT {
+		rep.Categories = append(rep.Categories, "riot")
+	}
+	if resp.Name != "" && resp.Name != "unknown" {
+		rep.Categories = append(rep.Categories, resp.Name)
+	}
+	return rep
+}
@@ -0,0 +1,176 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+)
+
+// The number of email addresses requested for each page of the domain search results
+const hunterPageSize = 100
+
+// Hunter is the Service that handles access to the Hunter.io data source.
+type Hunter struct {
+	requests.BaseService
+
+	SourceType string
+	sys        systems.System
+	creds      *config.Credentials
+}
+
+type hunterResponse struct {
+	Data struct {
+		Emails []struct {
+			Value   string `json:"value"`
+			Sources []struct {
+				Domain string `json:"domain"`
+				URI    string `json:"uri"`
+			} `json:"sources"`
+		} `json:"emails"`
+	} `json:"data"`
+	Meta struct {
+		Results int `json:"results"`
+	} `json:"meta"`
+	Errors []struct {
+		Details string `json:"details"`
+	} `json:"errors"`
+}
+
+// NewHunter returns the object initialized, but not yet started.
+func NewHunter(sys systems.System) *Hunter {
+	h := &Hunter{
+		SourceType: requests.API,
+		sys:        sys,
+	}
+
+	h.BaseService = *requests.NewBaseService(h, "Hunter")
+	return h
+}
+
+// Type implements the Service interface.
+func (h *Hunter) Type() string {
+	return h.SourceType
+}
+
+// OnStart implements the Service interface.
+func (h *Hunter) OnStart() error {
+	h.BaseService.OnStart()
+
+	h.creds = h.sys.Config().GetDataSourceConfig(h.String()).GetCredentials()
+	if h.creds == nil || h.creds.Key == "" {
+		h.sys.Config().Log.Printf("%s: API key data was not provided", h.String())
+	}
+
+	h.SetRateLimit(time.Second)
+	return nil
+}
+
+// CheckConfig implements the Service interface.
+func (h *Hunter) CheckConfig() error {
+	creds := h.sys.Config().GetDataSourceConfig(h.String()).GetCredentials()
+
+	if creds == nil || creds.Key == "" {
+		estr := fmt.Sprintf("%s: check callback failed for the configuration", h.String())
+		h.sys.Config().Log.Print(estr)
+		return errors.New(estr)
+	}
+
+	return nil
+}
+
+// OnDNSRequest implements the Service interface.
+func (h *Hunter) OnDNSRequest(ctx context.Context, req *requests.DNSRequest) {
+	cfg, bus, err := ContextConfigBus(ctx)
+	if err != nil {
+		return
+	}
+
+	if h.creds == nil || h.creds.Key == "" {
+		return
+	}
+
+	if !cfg.IsDomainInScope(req.Domain) {
+		return
+	}
+	bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
+		fmt.Sprintf("Querying %s for %s subdomains", h.String(), req.Domain))
+
+	for offset := 0; ; offset += hunterPageSize {
+		h.CheckRateLimit()
+		bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, h.String())
+
+		u := h.getURL(req.Domain, offset)
+		page, err := requestWebPage(h.sys, h, u, nil, nil, "", "")
+		if err != nil {
+			bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", h.String(), req.Domain, err))
+			return
+		}
+
+		var resp hunterResponse
+		if err := json.Unmarshal([]byte(page), &resp); err != nil {
+			bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", h.String(), req.Domain, err))
+			return
+		}
+		if len(resp.Errors) > 0 {
+			bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
+				fmt.Sprintf("%s: %s: %s", h.String(), req.Domain, resp.Errors[0].Details))
+			return
+		}
+
+		for _, e := range resp.Data.Emails {
+			h.processEmail(ctx, req.Domain, e.Value)
+			// The web pages where the email address was found provide additional host names
+			for _, src := range e.Sources {
+				genNewNameEvent(ctx, h.sys, h, src.Domain)
+			}
+		}
+
+		if len(resp.Data.Emails) < hunterPageSize || offset+hunterPageSize >= resp.Meta.Results {
+			break
+		}
+	}
+}
+
+func (h *Hunter) processEmail(ctx context.Context, domain, email string) {
+	_, bus, err := ContextConfigBus(ctx)
+	if err != nil {
+		return
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	parts := strings.Split(email, "@")
+	if len(parts) != 2 || parts[0] == "" {
+		return
+	}
+
+	genNewNameEvent(ctx, h.sys, h, parts[1])
+	bus.Publish(requests.NewEmailTopic, eventbus.PriorityHigh, &requests.EmailRequest{
+		Email:  email,
+		Domain: domain,
+		Tag:    h.SourceType,
+		Source: h.String(),
+	})
+}
+
+func (h *Hunter) getURL(domain string, offset int) string {
+	params := url.Values{}
+	params.Set("domain", domain)
+	params.Set("limit", strconv.Itoa(hunterPageSize))
+	params.Set("offset", strconv.Itoa(offset))
+	params.Set("api_key", h.creds.Key)
+
+	return "https://api.hunter.io/v2/domain-search?" + params.Encode()
+}
@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"github.com/OWASP/Amass/v3/eventbus"
-	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/systems"
 )
@@ -20,11 +19,15 @@ type IPAPI struct {
 	requests.BaseService
 
 	SourceType string
+	sys        systems.System
 }
 
 // NewIPAPI returns he object initialized, but not yet started.
 func NewIPAPI(sys systems.System) *IPAPI {
-	i := &IPAPI{SourceType: requests.API}
+	i := &IPAPI{
+		SourceType: requests.API,
+		sys:        sys,
+	}
 
 	i.BaseService = *requests.NewBaseService(i, "ipapi")
 	return i
@@ -59,7 +62,7 @@ func (i *IPAPI) OnAddrRequest(ctx context.Context, req *requests.AddrRequest) {
 
 	url := i.restAddrURL(req.Address)
 	headers := map[string]string{"Content-Type": "application/json"}
-	page, err := http.RequestWebPage(url, nil, headers, "", "")
+	page, err := requestWebPage(i.sys, i, url, nil, headers, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", i.String(), url, err))
 		return
@@ -12,7 +12,6 @@ import (
 
 	"github.com/OWASP/Amass/v3/eventbus"
 	amassnet "github.com/OWASP/Amass/v3/net"
-	amasshttp "github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/OWASP/Amass/v3/systems"
@@ -23,11 +22,15 @@ type IPToASN struct {
 	requests.BaseService
 
 	SourceType string
+	sys        systems.System
 }
 
 // NewIPToASN returns he object initialized, but not yet started.
 func NewIPToASN(sys systems.System) *IPToASN {
-	i := &IPToASN{SourceType: requests.API}
+	i := &IPToASN{
+		SourceType: requests.API,
+		sys:        sys,
+	}
 
 	i.BaseService = *requests.NewBaseService(i, "IPToASN")
 	return i
@@ -73,7 +76,7 @@ func (i *IPToASN) getASInfo(addr string) (*requests.ASNRequest, error) {
 	u := i.getURL(addr)
 
 	headers := map[string]string{"Accept": "application/json"}
-	page, err := amasshttp.RequestWebPage(u, nil, headers, "", "")
+	page, err := requestWebPage(i.sys, i, u, nil, headers, "", "")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %s: %v", i.String(), u, err)
 	}
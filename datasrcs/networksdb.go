@@ -6,6 +6,7 @@ package datasrcs
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
@@ -18,10 +19,11 @@ import (
 	"github.com/OWASP/Amass/v3/eventbus"
 	amassnet "github.com/OWASP/Amass/v3/net"
 	"github.com/OWASP/Amass/v3/net/dns"
-	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/OWASP/Amass/v3/systems"
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
 )
 
 const (
@@ -29,18 +31,6 @@ const (
 	networksdbAPIPATH = "/api/v1"
 )
 
-var (
-	networksdbASNLinkRE    = regexp.MustCompile(`Announcing ASN:<\/b> <a class="link_sm" href="(.*)"`)
-	networksdbIPLinkRE     = regexp.MustCompile(`<a class="link_sm" href="(\/ip\/[.:a-zA-Z0-9]+)">`)
-	networksdbASNRE        = regexp.MustCompile(`AS Number:<\/b> ([0-9]*)<br>`)
-	networksdbCIDRRE       = regexp.MustCompile(`CIDR:<\/b>(.*)<br>`)
-	networksdbIPPageCIDRRE = regexp.MustCompile(`<b>Network:.* href=".*".*href=".*">(.*)<\/a>`)
-	networksdbASNameRE     = regexp.MustCompile(`AS Name:<\/b>(.*)<br>`)
-	networksdbCCRE         = regexp.MustCompile(`Location:<\/b>.*href="/country/(.*)">`)
-	networksdbDomainsRE    = regexp.MustCompile(`Domains in network`)
-	networksdbTableRE      = regexp.MustCompile(`<table class`)
-)
-
 // NetworksDB is the Service that handles access to the NetworksDB.io data source.
 type NetworksDB struct {
 	requests.BaseService
@@ -113,54 +103,47 @@ func (n *NetworksDB) executeASNAddrQuery(ctx context.Context, addr string) {
 	}
 
 	u := n.getIPURL(addr)
-	page, err := http.RequestWebPage(u, nil, nil, "", "")
+	page, err := requestWebPage(n.sys, n, u, nil, nil, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 		return
 	}
 
-	matches := networksdbASNLinkRE.FindStringSubmatch(page)
-	if matches == nil || len(matches) < 2 {
-		bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
-			fmt.Sprintf("%s: %s: Failed to extract the autonomous system href", n.String(), u),
-		)
+	href, err := parseNetworksDBIPPageASN(page)
+	if err != nil {
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 		return
 	}
 
 	n.CheckRateLimit()
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, n.String())
 
-	u = networksdbBaseURL + matches[1]
-	page, err = http.RequestWebPage(u, nil, nil, "", "")
+	u = networksdbBaseURL + href
+	page, err = requestWebPage(n.sys, n, u, nil, nil, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 		return
 	}
 
-	netblocks := stringset.New()
-	for _, match := range networksdbCIDRRE.FindAllStringSubmatch(page, -1) {
-		if len(match) >= 2 {
-			netblocks.Insert(strings.TrimSpace(match[1]))
-		}
-	}
-
-	matches = networksdbASNRE.FindStringSubmatch(page)
-	if matches == nil || len(matches) < 2 {
-		bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
-			fmt.Sprintf("%s: %s: The regular expression failed to extract the ASN", n.String(), u),
-		)
+	info, err := parseNetworksDBASNPage(page)
+	if err != nil {
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 		return
 	}
 
-	asn, err := strconv.Atoi(strings.TrimSpace(matches[1]))
-	if err != nil {
+	asn := info.ASN
+	if asn == 0 {
+		// The path of the autonomous system page also provides the number
+		asn = networksdbASNFromPath(href)
+	}
+	if asn == 0 {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
 			fmt.Sprintf("%s: %s: Failed to extract a valid ASN", n.String(), u),
 		)
 		return
 	}
 
-	n.executeASNQuery(ctx, asn, addr, netblocks)
+	n.executeASNQuery(ctx, asn, addr, info.Netblocks)
 }
 
 func (n *NetworksDB) getIPURL(addr string) string {
@@ -177,34 +160,29 @@ func (n *NetworksDB) executeASNQuery(ctx context.Context, asn int, addr string,
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, n.String())
 
 	u := n.getASNURL(asn)
-	page, err := http.RequestWebPage(u, nil, nil, "", "")
+	page, err := requestWebPage(n.sys, n, u, nil, nil, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 		return
 	}
 
-	matches := networksdbASNameRE.FindStringSubmatch(page)
-	if matches == nil || len(matches) < 2 {
-		bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
-			fmt.Sprintf("%s: The regular expression failed to extract the AS name", n.String()),
-		)
+	info, err := parseNetworksDBASNPage(page)
+	if err != nil {
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 		return
 	}
-	name := strings.TrimSpace(matches[1])
-
-	matches = networksdbCCRE.FindStringSubmatch(page)
-	if matches == nil || len(matches) < 2 {
+	if info.Name == "" {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
-			fmt.Sprintf("%s: The regular expression failed to extract the country code", n.String()),
+			fmt.Sprintf("%s: %s: Failed to extract the AS name", n.String(), u),
 		)
 		return
 	}
-	cc := strings.TrimSpace(matches[1])
+	netblocks.Union(info.Netblocks)
 
-	for _, match := range networksdbCIDRRE.FindAllStringSubmatch(page, -1) {
-		if len(match) >= 2 {
-			netblocks.Insert(strings.TrimSpace(match[1]))
-		}
+	desc := info.Name
+	// The country code is helpful, but the remaining information is still used when it is missing
+	if info.CC != "" {
+		desc += ", " + info.CC
 	}
 
 	var prefix string
@@ -226,8 +204,8 @@ func (n *NetworksDB) executeASNQuery(ctx context.Context, asn int, addr string,
 		Address:     addr,
 		ASN:         asn,
 		Prefix:      prefix,
-		CC:          cc,
-		Description: name + ", " + cc,
+		CC:          info.CC,
+		Description: desc,
 		Netblocks:   netblocks,
 		Tag:         n.SourceType,
 		Source:      n.String(),
@@ -360,7 +338,7 @@ func (n *NetworksDB) apiIPQuery(ctx context.Context, addr string) (string, strin
 	u := n.getAPIIPURL()
 	params := url.Values{"ip": {addr}}
 	body := strings.NewReader(params.Encode())
-	page, err := http.RequestWebPage(u, body, n.getHeaders(), "", "")
+	page, err := requestWebPage(n.sys, n, u, body, n.getHeaders(), "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 		return "", ""
@@ -410,7 +388,7 @@ func (n *NetworksDB) apiOrgInfoQuery(ctx context.Context, id string) []int {
 	u := n.getAPIOrgInfoURL()
 	params := url.Values{"id": {id}}
 	body := strings.NewReader(params.Encode())
-	page, err := http.RequestWebPage(u, body, n.getHeaders(), "", "")
+	page, err := requestWebPage(n.sys, n, u, body, n.getHeaders(), "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 		return []int{}
@@ -455,7 +433,7 @@ func (n *NetworksDB) apiASNInfoQuery(ctx context.Context, asn int) *requests.ASN
 	u := n.getAPIASNInfoURL()
 	params := url.Values{"asn": {strconv.Itoa(asn)}}
 	body := strings.NewReader(params.Encode())
-	page, err := http.RequestWebPage(u, body, n.getHeaders(), "", "")
+	page, err := requestWebPage(n.sys, n, u, body, n.getHeaders(), "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 		return nil
@@ -512,7 +490,7 @@ func (n *NetworksDB) apiNetblocksQuery(ctx context.Context, asn int) stringset.S
 	u := n.getAPINetblocksURL()
 	params := url.Values{"asn": {strconv.Itoa(asn)}}
 	body := strings.NewReader(params.Encode())
-	page, err := http.RequestWebPage(u, body, n.getHeaders(), "", "")
+	page, err := requestWebPage(n.sys, n, u, body, n.getHeaders(), "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 		return netblocks
@@ -574,47 +552,34 @@ func (n *NetworksDB) OnWhoisRequest(ctx context.Context, req *requests.WhoisRequ
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, n.String())
 
 	u := n.getDomainToIPURL(req.Domain)
-	page, err := http.RequestWebPage(u, nil, nil, "", "")
+	page, err := requestWebPage(n.sys, n, u, nil, nil, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 		return
 	}
 
-	matches := networksdbIPLinkRE.FindAllStringSubmatch(page, -1)
-	if matches == nil {
-		bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
-			fmt.Sprintf("%s: %s: Failed to extract the IP page href", n.String(), u),
-		)
+	hrefs, err := parseNetworksDBIPLinks(page)
+	if err != nil {
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 		return
 	}
 
 	newdomains := stringset.New()
 	re := dns.AnySubdomainRegex()
-	for _, match := range matches {
-		if len(match) < 2 {
-			continue
-		}
-
+	for _, href := range hrefs {
 		n.CheckRateLimit()
 		bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, n.String())
 
-		u = networksdbBaseURL + match[1]
-		page, err = http.RequestWebPage(u, nil, nil, "", "")
+		u = networksdbBaseURL + href
+		page, err = requestWebPage(n.sys, n, u, nil, nil, "", "")
 		if err != nil {
 			bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 			continue
 		}
 
-		cidrMatch := networksdbIPPageCIDRRE.FindStringSubmatch(page)
-		if cidrMatch == nil || len(cidrMatch) < 2 {
-			bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
-				fmt.Sprintf("%s: %s: Failed to extract the CIDR", n.String(), u),
-			)
-			continue
-		}
-
-		_, cidr, err := net.ParseCIDR(cidrMatch[1])
+		cidr, err := parseNetworksDBIPPageNetwork(page)
 		if err != nil {
+			bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 			continue
 		}
 
@@ -624,26 +589,18 @@ func (n *NetworksDB) OnWhoisRequest(ctx context.Context, req *requests.WhoisRequ
 		first, last := amassnet.FirstLast(cidr)
 		u := n.getDomainsInNetworkURL(first.String(), last.String())
 
-		page, err = http.RequestWebPage(u, nil, nil, "", "")
+		page, err = requestWebPage(n.sys, n, u, nil, nil, "", "")
 		if err != nil {
 			bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 			continue
 		}
 
-		domainsPos := networksdbDomainsRE.FindStringIndex(page)
-		tablePos := networksdbTableRE.FindStringIndex(page)
-		if domainsPos == nil || tablePos == nil || len(domainsPos) < 2 || len(tablePos) < 2 {
-			bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
-				fmt.Sprintf("%s: %s: Failed to extract the domain section of the page", n.String(), u),
-			)
+		domains, err := parseNetworksDBDomains(page, re)
+		if err != nil {
+			bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", n.String(), u, err))
 			continue
 		}
-
-		start := domainsPos[1]
-		end := tablePos[1]
-		for _, d := range re.FindAllString(page[start:end], -1) {
-			newdomains.Insert(strings.TrimSpace(d))
-		}
+		newdomains.InsertMany(domains...)
 	}
 
 	if len(newdomains.Slice()) > 0 {
@@ -663,3 +620,312 @@ func (n *NetworksDB) getDomainToIPURL(domain string) string {
 func (n *NetworksDB) getDomainsInNetworkURL(first, last string) string {
 	return networksdbBaseURL + "/domains-in-network/" + first + "/" + last
 }
+
+// networksdbLink is an anchor found within the value of a labeled field on the NetworksDB pages.
+type networksdbLink struct {
+	Href string
+	Text string
+}
+
+// networksdbField is the value following a label on the NetworksDB pages, such as <b>AS Number:</b> 15169<br>.
+type networksdbField struct {
+	Text  string
+	Links []*networksdbLink
+}
+
+// networksdbFields returns the values of every field with the label. The labels are matched regardless of
+// case, spacing and the trailing colon, and can be emphasized or the terms of definition lists and tables.
+func networksdbFields(doc *goquery.Document, label string) []*networksdbField {
+	var fields []*networksdbField
+
+	label = networksdbLabel(label)
+	doc.Find("b, strong, dt, th, label").Each(func(i int, s *goquery.Selection) {
+		if networksdbLabel(s.Text()) != label {
+			return
+		}
+
+		var nodes []*html.Node
+		switch goquery.NodeName(s) {
+		case "dt", "th":
+			// The value is held by the following dd or td element
+			nodes = s.Next().Nodes
+		default:
+			// The value continues until the end of the line or the next label
+			for n := s.Nodes[0].NextSibling; n != nil; n = n.NextSibling {
+				if n.Type == html.ElementNode && (n.Data == "br" || n.Data == "b" || n.Data == "strong") {
+					break
+				}
+				nodes = append(nodes, n)
+			}
+		}
+
+		field := new(networksdbField)
+		var texts []string
+		for _, n := range nodes {
+			texts = append(texts, textNodes(n)...)
+			field.Links = append(field.Links, networksdbLinks(n)...)
+		}
+
+		field.Text = strings.TrimLeft(strings.Join(strings.Fields(strings.Join(texts, " ")), " "), ": ")
+		fields = append(fields, field)
+	})
+
+	return fields
+}
+
+func networksdbLabel(text string) string {
+	text = strings.TrimSuffix(strings.TrimSpace(text), ":")
+
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+func networksdbLinks(n *html.Node) []*networksdbLink {
+	if n.Type != html.ElementNode {
+		return nil
+	}
+
+	if n.Data == "a" {
+		for _, attr := range n.Attr {
+			if attr.Key == "href" {
+				return []*networksdbLink{{
+					Href: strings.TrimSpace(attr.Val),
+					Text: strings.TrimSpace(strings.Join(textNodes(n), "")),
+				}}
+			}
+		}
+		return nil
+	}
+
+	var links []*networksdbLink
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		links = append(links, networksdbLinks(c)...)
+	}
+	return links
+}
+
+// networksdbPath returns the path of links to NetworksDB pages that begin with the prefix.
+func networksdbPath(href, prefix string) string {
+	u, err := url.Parse(href)
+	if err != nil || (u.Host != "" && !strings.HasSuffix(strings.ToLower(u.Host), "networksdb.io")) {
+		return ""
+	}
+
+	if !strings.HasPrefix(u.Path, prefix) || len(u.Path) == len(prefix) {
+		return ""
+	}
+	return u.Path
+}
+
+func networksdbASNFromPath(path string) int {
+	last := path[strings.LastIndex(path, "/")+1:]
+
+	asn, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(last), "AS"))
+	if err != nil {
+		return 0
+	}
+	return asn
+}
+
+// networksdbCIDR returns the first netblock within the text.
+func networksdbCIDR(text string) string {
+	for _, token := range strings.Fields(text) {
+		if _, ipnet, err := net.ParseCIDR(strings.Trim(token, ",;()[]")); err == nil {
+			return ipnet.String()
+		}
+	}
+	return ""
+}
+
+// parseNetworksDBIPPageASN returns the path to the page of the autonomous system announcing the address.
+func parseNetworksDBIPPageASN(page string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		return "", err
+	}
+
+	for _, field := range networksdbFields(doc, "Announcing ASN") {
+		for _, link := range field.Links {
+			if path := networksdbPath(link.Href, "/autonomous-system/"); path != "" {
+				return path, nil
+			}
+		}
+	}
+
+	// Any link to an autonomous system serves when the label has changed
+	var path string
+	doc.Find(`a[href*="/autonomous-system/"]`).EachWithBreak(func(i int, s *goquery.Selection) bool {
+		href, _ := s.Attr("href")
+
+		path = networksdbPath(href, "/autonomous-system/")
+		return path == ""
+	})
+	if path == "" {
+		return "", errors.New("Failed to extract the autonomous system href")
+	}
+	return path, nil
+}
+
+// networksdbASInfo contains the information extracted from the page of an autonomous system.
+type networksdbASInfo struct {
+	ASN       int
+	Name      string
+	CC        string
+	Netblocks stringset.Set
+}
+
+// parseNetworksDBASNPage extracts the number, name, country code and netblocks of the autonomous system.
+// The fields missing from the page are left empty.
+func parseNetworksDBASNPage(page string) (*networksdbASInfo, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+
+	info := &networksdbASInfo{Netblocks: stringset.New()}
+	for _, field := range networksdbFields(doc, "AS Number") {
+		if tokens := strings.Fields(field.Text); len(tokens) > 0 {
+			if asn, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(tokens[0]), "AS")); err == nil {
+				info.ASN = asn
+				break
+			}
+		}
+	}
+
+	for _, field := range networksdbFields(doc, "AS Name") {
+		if field.Text != "" {
+			info.Name = field.Text
+			break
+		}
+	}
+
+	for _, field := range networksdbFields(doc, "Location") {
+		for _, link := range field.Links {
+			if path := networksdbPath(link.Href, "/country/"); path != "" {
+				info.CC = strings.ToUpper(strings.Trim(strings.TrimPrefix(path, "/country/"), "/"))
+				break
+			}
+		}
+	}
+
+	for _, field := range networksdbFields(doc, "CIDR") {
+		if cidr := networksdbCIDR(field.Text); cidr != "" {
+			info.Netblocks.Insert(cidr)
+		}
+	}
+
+	return info, nil
+}
+
+// parseNetworksDBIPLinks returns the paths to the pages of the addresses listed for a domain name.
+func parseNetworksDBIPLinks(page string) ([]string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	seen := stringset.New()
+	doc.Find(`a[href*="/ip/"]`).Each(func(i int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+
+		if path := networksdbPath(href, "/ip/"); path != "" && !seen.Has(path) {
+			seen.Insert(path)
+			paths = append(paths, path)
+		}
+	})
+
+	if len(paths) == 0 {
+		return nil, errors.New("Failed to extract the IP page href")
+	}
+	return paths, nil
+}
+
+// parseNetworksDBIPPageNetwork returns the netblock of the network containing the address.
+func parseNetworksDBIPPageNetwork(page string) (*net.IPNet, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range networksdbFields(doc, "Network") {
+		candidates := []string{field.Text}
+		for _, link := range field.Links {
+			candidates = append(candidates, link.Text)
+		}
+
+		for _, c := range candidates {
+			if cidr := networksdbCIDR(c); cidr != "" {
+				_, ipnet, _ := net.ParseCIDR(cidr)
+				return ipnet, nil
+			}
+		}
+	}
+
+	return nil, errors.New("Failed to extract the CIDR")
+}
+
+// parseNetworksDBDomains returns the domain names listed after the 'Domains in network' heading
+// and before the first table that follows it.
+func parseNetworksDBDomains(page string, re *regexp.Regexp) ([]string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+
+	var heading *html.Node
+	for _, n := range doc.Find("body").Nodes {
+		if heading = networksdbFindText(n, "domains in network"); heading != nil {
+			break
+		}
+	}
+	if heading == nil {
+		return nil, errors.New("Failed to extract the domain section of the page")
+	}
+
+	domains := stringset.New()
+	for n := networksdbNextNode(heading, true); n != nil; n = networksdbNextNode(n, false) {
+		if n.Type == html.ElementNode && n.Data == "table" {
+			break
+		}
+		if n.Type != html.TextNode {
+			continue
+		}
+
+		for _, d := range re.FindAllString(n.Data, -1) {
+			if d = strings.ToLower(strings.TrimSpace(d)); !strings.HasSuffix(d, "networksdb.io") {
+				domains.Insert(d)
+			}
+		}
+	}
+
+	return domains.Slice(), nil
+}
+
+// networksdbFindText returns the first text node containing the lowercase phrase.
+func networksdbFindText(n *html.Node, phrase string) *html.Node {
+	if n.Type == html.TextNode && strings.Contains(strings.ToLower(n.Data), phrase) {
+		return n
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := networksdbFindText(c, phrase); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// networksdbNextNode returns the node following n in document order,
+// skipping the descendants of n when the skip parameter is true.
+func networksdbNextNode(n *html.Node, skip bool) *html.Node {
+	if !skip && n.FirstChild != nil {
+		return n.FirstChild
+	}
+
+	for ; n != nil; n = n.Parent {
+		if n.NextSibling != nil {
+			return n.NextSibling
+		}
+	}
+	return nil
+}
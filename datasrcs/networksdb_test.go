@@ -0,0 +1,91 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/net/dns"
+)
+
+func readNetworksDBFixture(t *testing.T, name string) string {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "networksdb_"+name+".html"))
+	if err != nil {
+		t.Fatalf("Failed to read the %s fixture: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestNetworksDBIPPage(t *testing.T) {
+	for _, name := range []string{"ip", "ip_restyled"} {
+		page := readNetworksDBFixture(t, name)
+
+		if href, err := parseNetworksDBIPPageASN(page); err != nil || href != "/autonomous-system/AS13335" {
+			t.Errorf("The %s fixture provided the autonomous system href %s: %v", name, href, err)
+		}
+		if cidr, err := parseNetworksDBIPPageNetwork(page); err != nil || cidr.String() != "104.16.0.0/12" {
+			t.Errorf("The %s fixture provided the network %v: %v", name, cidr, err)
+		}
+	}
+
+	if _, err := parseNetworksDBIPPageASN("<html><body>Not found</body></html>"); err == nil {
+		t.Errorf("The autonomous system href was extracted from a page without one")
+	}
+	if _, err := parseNetworksDBIPPageNetwork("<html><body>Not found</body></html>"); err == nil {
+		t.Errorf("The network was extracted from a page without one")
+	}
+}
+
+func TestNetworksDBASNPage(t *testing.T) {
+	info, err := parseNetworksDBASNPage(readNetworksDBFixture(t, "asn"))
+	if err != nil {
+		t.Fatalf("Failed to parse the autonomous system page: %v", err)
+	}
+
+	if info.ASN != 13335 || info.Name != "CLOUDFLARENET" || info.CC != "US" {
+		t.Errorf("The page provided the ASN %d, name %q and country code %q", info.ASN, info.Name, info.CC)
+	}
+
+	netblocks := info.Netblocks.Slice()
+	sort.Strings(netblocks)
+	if strings.Join(netblocks, ",") != "104.16.0.0/12,172.64.0.0/13" {
+		t.Errorf("The page provided the netblocks %v", netblocks)
+	}
+
+	if asn := networksdbASNFromPath("/autonomous-system/AS13335"); asn != 13335 {
+		t.Errorf("The path provided the ASN %d", asn)
+	}
+}
+
+func TestNetworksDBIPLinks(t *testing.T) {
+	paths, err := parseNetworksDBIPLinks(readNetworksDBFixture(t, "domain_to_ips"))
+	if err != nil {
+		t.Fatalf("Failed to parse the domain to IPs page: %v", err)
+	}
+
+	expected := "/ip/104.22.26.77,/ip/104.22.27.77,/ip/2606:4700:10::6816:1a4d"
+	if got := strings.Join(paths, ","); got != expected {
+		t.Errorf("The page provided the paths %s", got)
+	}
+}
+
+func TestNetworksDBDomains(t *testing.T) {
+	domains, err := parseNetworksDBDomains(readNetworksDBFixture(t, "domains_in_network"), dns.AnySubdomainRegex())
+	if err != nil {
+		t.Fatalf("Failed to parse the domains in network page: %v", err)
+	}
+
+	sort.Strings(domains)
+	if got := strings.Join(domains, ","); got != "example.com,owasp.org,www.cloudflare.com" {
+		t.Errorf("The page provided the domains %s", got)
+	}
+
+	if _, err := parseNetworksDBDomains("<html><body><table></table></body></html>", dns.AnySubdomainRegex()); err == nil {
+		t.Errorf("The domain section was extracted from a page without one")
+	}
+}
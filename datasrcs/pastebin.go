@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"github.com/OWASP/Amass/v3/eventbus"
-	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/systems"
 )
@@ -72,7 +71,7 @@ func (p *Pastebin) OnDNSRequest(ctx context.Context, req *requests.DNSRequest) {
 
 	for _, id := range ids {
 		url := p.webURLDumpData(id)
-		page, err := http.RequestWebPage(url, nil, nil, "", "")
+		page, err := requestWebPage(p.sys, p, url, nil, nil, "", "")
 		if err != nil {
 			bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", p.String(), url, err))
 			return
@@ -87,7 +86,7 @@ func (p *Pastebin) OnDNSRequest(ctx context.Context, req *requests.DNSRequest) {
 // Extract the IDs from the pastebin Web response.
 func (p *Pastebin) extractIDs(domain string) ([]string, error) {
 	url := p.webURLDumpIDs(domain)
-	page, err := http.RequestWebPage(url, nil, nil, "", "")
+	page, err := requestWebPage(p.sys, p, url, nil, nil, "", "")
 	if err != nil {
 		return nil, err
 	}
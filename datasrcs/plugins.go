@@ -0,0 +1,102 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"fmt"
+	"plugin"
+	"sync"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+)
+
+// PluginSymbol is the name of the function that Go plugins must export for each data source.
+// The function must have the ServiceConstructor signature, for example:
+//
+//	func NewService(sys systems.System) requests.Service
+const PluginSymbol = "NewService"
+
+// ServiceConstructor returns a data source Service initialized, but not yet started.
+type ServiceConstructor func(sys systems.System) requests.Service
+
+var (
+	registeredLock sync.Mutex
+	registered     []ServiceConstructor
+)
+
+// RegisterSource adds the constructor of an out-of-tree data source, so that GetAllSources
+// includes the Service it returns. It is intended to be called from the init function of the
+// package providing the data source.
+func RegisterSource(constructor ServiceConstructor) {
+	if constructor == nil {
+		return
+	}
+
+	registeredLock.Lock()
+	defer registeredLock.Unlock()
+
+	registered = append(registered, constructor)
+}
+
+func registeredSources(sys systems.System) []requests.Service {
+	registeredLock.Lock()
+	defer registeredLock.Unlock()
+
+	var srvs []requests.Service
+	for _, constructor := range registered {
+		if srv := constructor(sys); srv != nil {
+			srvs = append(srvs, srv)
+		}
+	}
+	return srvs
+}
+
+func pluginSources(sys systems.System) []requests.Service {
+	paths, err := sys.Config().AcquirePlugins()
+	if err != nil {
+		sys.Config().Log.Printf("Failed to acquire the plugins: %v", err)
+		return []requests.Service{}
+	}
+
+	var srvs []requests.Service
+	for _, path := range paths {
+		srv, err := loadPlugin(sys, path)
+		if err != nil {
+			sys.Config().Log.Printf("Failed to load the plugin %s: %v", path, err)
+			continue
+		}
+
+		srvs = append(srvs, srv)
+	}
+	return srvs
+}
+
+func loadPlugin(sys systems.System, path string) (requests.Service, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := p.Lookup(PluginSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var constructor ServiceConstructor
+	switch f := sym.(type) {
+	case func(systems.System) requests.Service:
+		constructor = f
+	case *ServiceConstructor:
+		constructor = *f
+	default:
+		return nil, fmt.Errorf("The %s symbol has the wrong type: %T", PluginSymbol, sym)
+	}
+
+	srv := constructor(sys)
+	if srv == nil {
+		return nil, fmt.Errorf("The %s function returned a nil Service", PluginSymbol)
+	}
+	return srv, nil
+}
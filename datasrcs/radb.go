@@ -14,7 +14,6 @@ import (
 
 	"github.com/OWASP/Amass/v3/eventbus"
 	amassnet "github.com/OWASP/Amass/v3/net"
-	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/OWASP/Amass/v3/stringset"
@@ -108,7 +107,7 @@ func (r *RADb) executeASNAddrQuery(ctx context.Context, addr string) {
 
 	url := r.getIPURL("arin", addr)
 	headers := map[string]string{"Content-Type": "application/json"}
-	page, err := http.RequestWebPage(url, nil, headers, "", "")
+	page, err := requestWebPage(r.sys, r, url, nil, headers, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", r.String(), url, err))
 		return
@@ -169,7 +168,7 @@ func (r *RADb) executeASNQuery(ctx context.Context, asn int, addr, prefix string
 
 	url := r.getASNURL("arin", strconv.Itoa(asn))
 	headers := map[string]string{"Content-Type": "application/json"}
-	page, err := http.RequestWebPage(url, nil, headers, "", "")
+	page, err := requestWebPage(r.sys, r, url, nil, headers, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", r.String(), url, err))
 		return
@@ -252,7 +251,7 @@ func (r *RADb) netblocks(ctx context.Context, asn int) stringset.Set {
 	r.CheckRateLimit()
 	url := r.getNetblocksURL(strconv.Itoa(asn))
 	headers := map[string]string{"Content-Type": "application/json"}
-	page, err := http.RequestWebPage(url, nil, headers, "", "")
+	page, err := requestWebPage(r.sys, r, url, nil, headers, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", r.String(), url, err))
 		return netblocks
@@ -0,0 +1,64 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// ReputationProvider is implemented by the data sources that classify IP addresses, such as AbuseIPDB and GreyNoise.
+// The enumeration sends the resolved addresses to the providers as AddrRequests, and the providers publish the
+// classifications on the requests.NewReputationTopic.
+type ReputationProvider interface {
+	requests.Service
+
+	// AddressReputation queries the provider for the classification of the address, and returns
+	// nil when the provider cannot be queried, such as when the API key was not provided
+	AddressReputation(ctx context.Context, addr string) (*requests.ReputationRequest, error)
+}
+
+// ReputationProviders returns the data sources that implement the ReputationProvider interface.
+func ReputationProviders(srcs []requests.Service) []ReputationProvider {
+	var providers []ReputationProvider
+
+	for _, src := range srcs {
+		if p, ok := src.(ReputationProvider); ok {
+			providers = append(providers, p)
+		}
+	}
+	return providers
+}
+
+// publishReputation is called by the OnAddrRequest methods of the providers.
+func publishReputation(ctx context.Context, p ReputationProvider, addr string) {
+	_, bus, err := ContextConfigBus(ctx)
+	if err != nil || addr == "" {
+		return
+	}
+
+	rep, err := p.AddressReputation(ctx, addr)
+	if err != nil {
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", p.String(), addr, err))
+		return
+	}
+
+	if rep != nil {
+		bus.Publish(requests.NewReputationTopic, eventbus.PriorityHigh, rep)
+	}
+}
+
+// scoreClassification returns the classification for the confidence that the address is malicious.
+func scoreClassification(score int) string {
+	switch {
+	case score >= 75:
+		return requests.ReputationMalicious
+	case score >= 25:
+		return requests.ReputationSuspicious
+	}
+	return requests.ReputationUnknown
+}
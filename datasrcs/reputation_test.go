@@ -0,0 +1,71 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestAbuseIPDBResponse(t *testing.T) {
+	a := NewAbuseIPDB(nil)
+
+	tests := []struct {
+		page           string
+		classification string
+		score          int
+	}{
+		{`{"data":{"ipAddress":"192.0.2.1","abuseConfidenceScore":100,"usageType":"Data Center/Web Hosting/Transit","totalReports":52}}`, requests.ReputationMalicious, 100},
+		{`{"data":{"ipAddress":"192.0.2.1","abuseConfidenceScore":30,"totalReports":3}}`, requests.ReputationSuspicious, 30},
+		{`{"data":{"ipAddress":"192.0.2.1","abuseConfidenceScore":0,"totalReports":0}}`, requests.ReputationUnknown, 0},
+		{`{"data":{"ipAddress":"192.0.2.1","isWhitelisted":true,"abuseConfidenceScore":0}}`, requests.ReputationBenign, 0},
+	}
+
+	for _, test := range tests {
+		rep, err := a.parseResponse("192.0.2.1", test.page)
+		if err != nil {
+			t.Errorf("Failed to parse %s: %v", test.page, err)
+			continue
+		}
+		if rep.Classification != test.classification || rep.Score != test.score || rep.Source != "AbuseIPDB" {
+			t.Errorf("The response %s provided %+v", test.page, rep)
+		}
+	}
+
+	rep, _ := a.parseResponse("192.0.2.1", tests[0].page)
+	if len(rep.Categories) != 1 || rep.Categories[0] != "Data Center/Web Hosting/Transit" {
+		t.Errorf("The usage type was not provided as a category: %v", rep.Categories)
+	}
+	if _, err := a.parseResponse("192.0.2.1", `{"errors":[{"detail":"Daily rate limit exceeded"}]}`); err == nil {
+		t.Errorf("The error response was accepted")
+	}
+}
+
+func TestGreyNoiseResponse(t *testing.T) {
+	g := NewGreyNoise(nil)
+
+	rep := g.newRequest("192.0.2.1", &greyNoiseResponse{RIOT: true, Classification: "benign", Name: "Cloudflare"})
+	if rep.Classification != requests.ReputationBenign || len(rep.Categories) != 2 || rep.Categories[1] != "Cloudflare" {
+		t.Errorf("The common business service was classified as %+v", rep)
+	}
+
+	rep = g.newRequest("192.0.2.1", &greyNoiseResponse{Noise: true, Classification: "malicious", Name: "unknown"})
+	if rep.Classification != requests.ReputationMalicious || len(rep.Categories) != 1 || rep.Categories[0] != "noise" {
+		t.Errorf("The scanning address was classified as %+v", rep)
+	}
+
+	// Addresses that have not been observed are unknown to the provider
+	if rep = g.newRequest("192.0.2.1", &greyNoiseResponse{}); rep.Classification != requests.ReputationUnknown {
+		t.Errorf("The unobserved address was classified as %s", rep.Classification)
+	}
+}
+
+func TestReputationProviders(t *testing.T) {
+	srcs := []requests.Service{NewAbuseIPDB(nil), NewHunter(nil), NewGreyNoise(nil)}
+
+	if providers := ReputationProviders(srcs); len(providers) != 2 {
+		t.Errorf("%d of the data sources were selected as reputation providers", len(providers))
+	}
+}
@@ -15,7 +15,6 @@ import (
 
 	"github.com/OWASP/Amass/v3/eventbus"
 	amassnet "github.com/OWASP/Amass/v3/net"
-	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/OWASP/Amass/v3/systems"
@@ -92,7 +91,7 @@ func (r *Robtex) OnDNSRequest(ctx context.Context, req *requests.DNSRequest) {
 		fmt.Sprintf("Querying %s for %s subdomains", r.String(), req.Domain))
 
 	url := "https://freeapi.robtex.com/pdns/forward/" + req.Domain
-	page, err := http.RequestWebPage(url, nil, nil, "", "")
+	page, err := requestWebPage(r.sys, r, url, nil, nil, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", r.String(), url, err))
 		return
@@ -121,7 +120,7 @@ loop:
 			bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, r.String())
 
 			url = "https://freeapi.robtex.com/pdns/reverse/" + ip
-			pdns, err := http.RequestWebPage(url, nil, nil, "", "")
+			pdns, err := requestWebPage(r.sys, r, url, nil, nil, "", "")
 			if err != nil {
 				bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
 					fmt.Sprintf("%s: %s: %v", r.String(), url, err))
@@ -215,7 +214,7 @@ func (r *Robtex) origin(ctx context.Context, addr string) *requests.ASNRequest {
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, r.String())
 
 	url := "https://freeapi.robtex.com/ipquery/" + addr
-	page, err := http.RequestWebPage(url, nil, nil, "", "")
+	page, err := requestWebPage(r.sys, r, url, nil, nil, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", r.String(), url, err))
 		return nil
@@ -299,7 +298,7 @@ func (r *Robtex) netblocks(ctx context.Context, asn int) stringset.Set {
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, r.String())
 
 	url := "https://freeapi.robtex.com/asquery/" + strconv.Itoa(asn)
-	page, err := http.RequestWebPage(url, nil, nil, "", "")
+	page, err := requestWebPage(r.sys, r, url, nil, nil, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", r.String(), url, err))
 		return netblocks
@@ -114,8 +114,11 @@ func (s *Script) newLuaState(cfg *config.Config) *lua.LState {
 	L.SetGlobal("outputdir", L.NewFunction(s.outputdir))
 	L.SetGlobal("setratelimit", L.NewFunction(s.setRateLimit))
 	L.SetGlobal("checkratelimit", L.NewFunction(s.checkRateLimit))
+	L.SetGlobal("backoff", L.NewFunction(s.backoff))
 	L.SetGlobal("obtain_response", L.NewFunction(s.obtainResponse))
 	L.SetGlobal("cache_response", L.NewFunction(s.cacheResponse))
+	L.SetGlobal("get_cursor", L.NewFunction(s.getCursor))
+	L.SetGlobal("set_cursor", L.NewFunction(s.setCursor))
 	L.SetGlobal("subdomainre", lua.LString(dns.AnySubdomainRegexString()))
 	return L
 }
@@ -420,7 +423,8 @@ func (s *Script) OnASNRequest(ctx context.Context, req *requests.ASNRequest) {
 func (s *Script) OnWhoisRequest(ctx context.Context, req *requests.WhoisRequest) {
 	L := s.luaState
 
-	if s.horizontal.Type() == lua.LTNil {
+	// The horizontal callback only pivots from domain names
+	if s.horizontal.Type() == lua.LTNil || req.Domain == "" {
 		return
 	}
 
@@ -44,6 +44,7 @@ func (s *Script) config(L *lua.LState) int {
 	}
 
 	r.RawSetString("event_id", lua.LString(cfg.UUID.String()))
+	r.RawSetString("monitor", lua.LBool(cfg.Monitor))
 	r.RawSetString("max_dns_queries", lua.LNumber(cfg.MaxDNSQueries))
 
 	scope := L.NewTable()
@@ -106,6 +107,12 @@ func (s *Script) config(L *lua.LState) int {
 	tb.RawSetString("active", lua.LBool(cfg.BruteForcing))
 	tb.RawSetString("recursive", lua.LBool(cfg.Recursive))
 	tb.RawSetString("min_for_recursive", lua.LNumber(cfg.MinForRecursive))
+	tb.RawSetString("max_depth", lua.LNumber(cfg.MaxDepth))
+	depths := L.NewTable()
+	for depth, min := range cfg.MinForDepth {
+		depths.RawSetInt(depth, lua.LNumber(min))
+	}
+	tb.RawSetString("min_for_depth", depths)
 	r.RawSetString("brute_forcing", tb)
 
 	tb = L.NewTable()
@@ -133,6 +140,15 @@ func (s *Script) dataSourceConfig(L *lua.LState) int {
 	if cfg.TTL != 0 {
 		tb.RawSetString("ttl", lua.LNumber(cfg.TTL))
 	}
+	if cfg.URL != "" {
+		tb.RawSetString("url", lua.LString(strings.TrimRight(cfg.URL, "/")))
+	}
+
+	settings := L.NewTable()
+	for k, v := range cfg.Settings() {
+		settings.RawSetString(k, lua.LString(v))
+	}
+	tb.RawSetString("settings", settings)
 
 	if creds := cfg.GetCredentials(); creds != nil {
 		c := L.NewTable()
@@ -150,6 +166,12 @@ func (s *Script) dataSourceConfig(L *lua.LState) int {
 		if creds.Secret != "" {
 			c.RawSetString("secret", lua.LString(creds.Secret))
 		}
+		if creds.TokenURL != "" {
+			c.RawSetString("token_url", lua.LString(creds.TokenURL))
+		}
+		if creds.ClientID != "" {
+			c.RawSetString("client_id", lua.LString(creds.ClientID))
+		}
 		tb.RawSetString("credentials", c)
 	}
 
@@ -167,7 +189,7 @@ func (s *Script) bruteWordlist(L *lua.LState) int {
 	}
 
 	tb := L.NewTable()
-	for _, word := range cfg.Wordlist {
+	for _, word := range cfg.BruteWordlist(L.OptString(2, "")) {
 		tb.Append(lua.LString(word))
 	}
 
@@ -212,6 +234,18 @@ func (s *Script) checkRateLimit(L *lua.LState) int {
 	return 0
 }
 
+// Wrapper so scripts can report API-specific quota errors and slow down the data source.
+func (s *Script) backoff(L *lua.LState) int {
+	var d time.Duration
+
+	if num, ok := L.Get(1).(lua.LNumber); ok && num > 0 {
+		d = time.Duration(int(num)) * time.Second
+	}
+
+	s.Backoff(d)
+	return 0
+}
+
 // Wrapper so scripts can signal Amass of script activity.
 func (s *Script) active(L *lua.LState) int {
 	c := L.CheckUserData(1).Value.(*contextWrapper)
@@ -524,7 +558,16 @@ func (s *Script) request(L *lua.LState) int {
 	id, _ := getStringField(L, opt, "id")
 	pass, _ := getStringField(L, opt, "pass")
 
-	page, err := http.RequestWebPage(url, body, headers, id, pass)
+	var page string
+	var err error
+	// The credentials of the data source provide the OAuth2 client credentials
+	if L.GetField(opt, "oauth2") == lua.LTrue {
+		creds := s.sys.Config().GetDataSourceConfig(s.String()).GetCredentials()
+
+		page, err = requestWithOAuth2(s.sys, s, creds, url, body, headers)
+	} else {
+		page, err = requestWebPage(s.sys, s, url, body, headers, id, pass)
+	}
 	if err != nil {
 		L.Push(lua.LNil)
 		L.Push(lua.LString(err.Error()))
@@ -573,7 +616,7 @@ func (s *Script) scrape(L *lua.LState) int {
 	}
 
 	if resp == "" {
-		resp, err = http.RequestWebPage(url, nil, headers, id, pass)
+		resp, err = requestWebPage(s.sys, s, url, nil, headers, id, pass)
 		if err != nil {
 			bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", s.String(), url, err))
 			L.Push(lua.LFalse)
@@ -693,3 +736,33 @@ func (s *Script) cacheResponse(L *lua.LState) int {
 	s.setCachedResponse(string(u), string(resp))
 	return 0
 }
+
+// Wrapper so that scripts can obtain the position reached by the previous execution in monitor mode.
+func (s *Script) getCursor(L *lua.LState) int {
+	c := L.CheckUserData(1).Value.(*contextWrapper)
+	cfg, _, err := ContextConfigBus(c.Ctx)
+	if err != nil {
+		L.Push(lua.LString(""))
+		return 1
+	}
+
+	domain := L.CheckString(2)
+	L.Push(lua.LString(getSourceCursor(cfg, s.String(), domain)))
+	return 1
+}
+
+// Wrapper so that scripts can persist the position reached for the next execution in monitor mode.
+func (s *Script) setCursor(L *lua.LState) int {
+	c := L.CheckUserData(1).Value.(*contextWrapper)
+	cfg, _, err := ContextConfigBus(c.Ctx)
+	if err != nil {
+		return 0
+	}
+
+	domain := L.CheckString(2)
+	cursor := L.CheckString(3)
+	if err := setSourceCursor(cfg, s.String(), domain, cursor); err != nil {
+		cfg.Log.Printf("%s: Failed to save the cursor for %s: %v", s.String(), domain, err)
+	}
+	return 0
+}
@@ -4,28 +4,53 @@
 package datasrcs
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	nethttp "net/http"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/eventbus"
 	"github.com/OWASP/Amass/v3/net/dns"
+	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/systems"
 )
 
 var subRE = dns.AnySubdomainRegex()
 
+// The on-disk HTTP response caches shared by the data sources, keyed by directory
+var (
+	responseCachesLock sync.Mutex
+	responseCaches     = make(map[string]*http.ResponseCache)
+)
+
+// The name of the directory used for HTTP responses within the output directory
+const responseCacheDirName = "http_cache"
+
 // GetAllSources returns a slice of all data source services, initialized and ready.
 func GetAllSources(sys systems.System, check bool) []requests.Service {
 	srvs := []requests.Service{
+		NewAbuseIPDB(sys),
 		NewAlienVault(sys),
+		NewCertstream(sys),
 		NewCloudflare(sys),
 		NewCommonCrawl(sys),
 		NewCrtsh(sys),
 		NewDNSDB(sys),
 		NewDNSDumpster(sys),
+		NewDomainTools(sys),
+		NewGreyNoise(sys),
+		NewHunter(sys),
 		NewIPToASN(sys),
 		NewNetworksDB(sys),
 		NewPastebin(sys),
@@ -47,11 +72,27 @@ func GetAllSources(sys systems.System, check bool) []requests.Service {
 			}
 		}
 	}
+	// Include the out-of-tree data sources
+	for _, ext := range sys.Config().ExternalSources {
+		srvs = append(srvs, NewExternal(sys, ext))
+	}
+	srvs = append(srvs, registeredSources(sys)...)
+	srvs = append(srvs, pluginSources(sys)...)
+
+	// Remove the data sources of categories that are not enabled by the configuration
+	i := 0
+	for _, s := range srvs {
+		if sys.Config().SourceCategoryEnabled(s.Type()) {
+			srvs[i] = s
+			i++
+		}
+	}
+	srvs = srvs[:i]
 
 	if check {
 		// Check that the data sources have acceptable configurations for operation
 		// Filtering in-place: https://github.com/golang/go/wiki/SliceTricks
-		i := 0
+		i = 0
 		for _, s := range srvs {
 			if s.CheckConfig() == nil {
 				srvs[i] = s
@@ -67,6 +108,174 @@ func GetAllSources(sys systems.System, check bool) []requests.Service {
 	return srvs
 }
 
+// requestWebPage sends the HTTP request through the proxy configured for the data source,
+// abandons it after the timeout of the data source, retries transient failures, and reuses
+// responses from the on-disk cache when the configuration has it enabled.
+func requestWebPage(sys systems.System, srv requests.Service, urlstring string,
+	body io.Reader, hvals map[string]string, uid, secret string) (string, error) {
+	cfg := sys.Config()
+	client, err := http.ProxyClient(cfg.DataSourceProxy(srv.String()))
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DataSourceTimeout(srv.String()))
+	defer cancel()
+
+	start := time.Now()
+	page, err := withBreaker(sys, srv, func() (string, error) {
+		return http.RequestWebPageWithCache(ctx, responseCache(cfg), client,
+			cfg.DataSourceRetryPolicy(srv.String()), urlstring, body, hvals, uid, secret)
+	})
+	// The refused requests are not counted by the statistics of the data source
+	var boe *systems.BreakerOpenError
+	if errors.As(err, &boe) {
+		return "", err
+	}
+	if s, ok := sys.(systems.SourceStatsSystem); ok && s.SourceStats() != nil {
+		s.SourceStats().AddRequest(srv.String(), time.Since(start), err)
+	}
+	// Slow down the data source when it starts rejecting requests
+	if rle, ok := http.IsRateLimited(err); ok {
+		if b, ok := srv.(backoffService); ok {
+			b.Backoff(rle.RetryAfter)
+		}
+		cfg.Log.Printf("%s: Rate limited by the data source: %s", srv.String(), rle.Status)
+	}
+	return page, err
+}
+
+// withBreaker sends the request when the circuit breakers of the System permit it, and provides
+// the outcome to the breakers. The requests refused by an open breaker return a BreakerOpenError.
+func withBreaker(sys systems.System, srv requests.Service, request func() (string, error)) (string, error) {
+	b, ok := sys.(systems.BreakerSystem)
+	if !ok || b.CircuitBreakers() == nil {
+		return request()
+	}
+	breakers := b.CircuitBreakers()
+
+	if err := breakers.Allow(srv.String()); err != nil {
+		return "", err
+	}
+
+	page, err := request()
+	for _, s := range breakers.Record(srv.String(), breakerFailure(err)) {
+		sys.Config().Log.Printf("Circuit breaker %s", s)
+	}
+	return page, err
+}
+
+// breakerFailure returns a short description of the failure when it reveals a problem with the data
+// source or the network. Responses such as 404 describe the query instead, and are not counted.
+func breakerFailure(err error) string {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return ""
+	}
+
+	if rle, ok := http.IsRateLimited(err); ok {
+		return strconv.Itoa(rle.StatusCode)
+	}
+
+	var se *http.StatusError
+	if errors.As(err, &se) {
+		if se.StatusCode == nethttp.StatusUnauthorized ||
+			se.StatusCode == nethttp.StatusForbidden || se.StatusCode >= 500 {
+			return strconv.Itoa(se.StatusCode)
+		}
+		return ""
+	}
+
+	var nerr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &nerr) && nerr.Timeout()) {
+		return "timeout"
+	}
+	return "connection failed"
+}
+
+// requestWithOAuth2 sends the HTTP request with an access token obtained using the OAuth2
+// client credentials, and requests a new token once when the server rejects the current one.
+func requestWithOAuth2(sys systems.System, srv requests.Service, creds *config.Credentials,
+	urlstring string, body io.Reader, hvals map[string]string) (string, error) {
+	cc := creds.OAuth2()
+	if cc == nil {
+		return "", fmt.Errorf("%s: The credentials do not provide the OAuth2 token_url", srv.String())
+	}
+
+	cfg := sys.Config()
+	client, err := http.ProxyClient(cfg.DataSourceProxy(srv.String()))
+	if err != nil {
+		return "", err
+	}
+
+	// The body needs to be provided again when the token is replaced
+	var data []byte
+	if body != nil {
+		if data, err = ioutil.ReadAll(body); err != nil {
+			return "", err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.DataSourceTimeout(srv.String()))
+		token, err := http.OAuth2Token(ctx, client, cc)
+		cancel()
+		if err != nil {
+			return "", fmt.Errorf("%s: Failed to obtain the OAuth2 access token: %v", srv.String(), err)
+		}
+
+		headers := map[string]string{"Authorization": "Bearer " + token}
+		for k, v := range hvals {
+			headers[k] = v
+		}
+
+		var b io.Reader
+		if data != nil {
+			b = bytes.NewReader(data)
+		}
+
+		page, err := requestWebPage(sys, srv, urlstring, b, headers, "", "")
+		var se *http.StatusError
+		if attempt == 0 && errors.As(err, &se) && se.StatusCode == nethttp.StatusUnauthorized {
+			http.InvalidateOAuth2Token(cc)
+			continue
+		}
+		return page, err
+	}
+}
+
+// backoffService is implemented by services that embed the requests.BaseService.
+type backoffService interface {
+	Backoff(d time.Duration)
+}
+
+func responseCache(cfg *config.Config) *http.ResponseCache {
+	if cfg.HTTPCacheTTL <= 0 {
+		return nil
+	}
+
+	dir := config.OutputDirectory(cfg.Dir)
+	if dir == "" {
+		return nil
+	}
+	dir = filepath.Join(dir, responseCacheDirName)
+
+	responseCachesLock.Lock()
+	defer responseCachesLock.Unlock()
+
+	if rc, found := responseCaches[dir]; found {
+		return rc
+	}
+
+	rc, err := http.NewResponseCache(dir, time.Duration(cfg.HTTPCacheTTL)*time.Minute)
+	if err != nil {
+		cfg.Log.Printf("Failed to setup the HTTP response cache: %v", err)
+		return nil
+	}
+
+	responseCaches[dir] = rc
+	return rc
+}
+
 func genNewNameEvent(ctx context.Context, sys systems.System, srv requests.Service, name string) {
 	cfg, bus, err := ContextConfigBus(ctx)
 	if err != nil {
@@ -83,6 +292,18 @@ func genNewNameEvent(ctx context.Context, sys systems.System, srv requests.Servi
 	}
 }
 
+// whoisSearchTerm returns the domain name, registrant organization or email address that the
+// reverse whois request searches for.
+func whoisSearchTerm(req *requests.WhoisRequest) string {
+	switch {
+	case req.Domain != "":
+		return req.Domain
+	case req.Company != "":
+		return req.Company
+	}
+	return req.Email
+}
+
 // ContextConfigBus extracts the Config and EventBus references from the Context argument.
 func ContextConfigBus(ctx context.Context) (*config.Config, *eventbus.EventBus, error) {
 	var ok bool
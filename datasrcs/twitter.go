@@ -13,7 +13,6 @@ import (
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/eventbus"
-	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/dghubble/go-twitter/twitter"
@@ -125,7 +124,7 @@ func (t *Twitter) OnDNSRequest(ctx context.Context, req *requests.DNSRequest) {
 
 func (t *Twitter) getBearerToken() (string, error) {
 	headers := map[string]string{"Content-Type": "application/x-www-form-urlencoded;charset=UTF-8"}
-	page, err := http.RequestWebPage(
+	page, err := requestWebPage(t.sys, t,
 		"https://api.twitter.com/oauth2/token",
 		strings.NewReader("grant_type=client_credentials"),
 		headers, t.creds.Key, t.creds.Secret)
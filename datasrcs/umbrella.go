@@ -14,7 +14,6 @@ import (
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/eventbus"
-	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/OWASP/Amass/v3/stringset"
@@ -94,7 +93,7 @@ func (u *Umbrella) OnDNSRequest(ctx context.Context, req *requests.DNSRequest) {
 
 	headers := u.restHeaders()
 	url := u.restDNSURL(req.Domain)
-	page, err := http.RequestWebPage(url, nil, headers, "", "")
+	page, err := requestWebPage(u.sys, u, url, nil, headers, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", u.String(), url, err))
 		return
@@ -134,7 +133,7 @@ func (u *Umbrella) OnAddrRequest(ctx context.Context, req *requests.AddrRequest)
 
 	headers := u.restHeaders()
 	url := u.restAddrURL(req.Address)
-	page, err := http.RequestWebPage(url, nil, headers, "", "")
+	page, err := requestWebPage(u.sys, u, url, nil, headers, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", u.String(), url, err))
 		return
@@ -190,7 +189,7 @@ func (u *Umbrella) executeASNAddrQuery(ctx context.Context, req *requests.ASNReq
 
 	headers := u.restHeaders()
 	url := u.restAddrToASNURL(req.Address)
-	page, err := http.RequestWebPage(url, nil, headers, "", "")
+	page, err := requestWebPage(u.sys, u, url, nil, headers, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", u.String(), url, err))
 		return
@@ -255,7 +254,7 @@ func (u *Umbrella) executeASNQuery(ctx context.Context, req *requests.ASNRequest
 
 	headers := u.restHeaders()
 	url := u.restASNToCIDRsURL(req.ASN)
-	page, err := http.RequestWebPage(url, nil, headers, "", "")
+	page, err := requestWebPage(u.sys, u, url, nil, headers, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", u.String(), url, err))
 		return
@@ -365,7 +364,7 @@ func (u *Umbrella) queryWhois(ctx context.Context, domain string) *whoisRecord {
 	u.CheckRateLimit()
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, u.String())
 
-	record, err := http.RequestWebPage(whoisURL, nil, headers, "", "")
+	record, err := requestWebPage(u.sys, u, whoisURL, nil, headers, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", u.String(), whoisURL, err))
 		return nil
@@ -395,7 +394,7 @@ func (u *Umbrella) queryReverseWhois(ctx context.Context, apiURL string) []strin
 		bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, u.String())
 
 		fullAPIURL := fmt.Sprintf("%s&offset=%d", apiURL, count)
-		record, err := http.RequestWebPage(fullAPIURL, nil, headers, "", "")
+		record, err := requestWebPage(u.sys, u, fullAPIURL, nil, headers, "", "")
 		if err != nil {
 			bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", u.String(), apiURL, err))
 			return domains.Slice()
@@ -447,6 +446,10 @@ func (u *Umbrella) OnWhoisRequest(ctx context.Context, req *requests.WhoisReques
 		return
 	}
 
+	if req.Domain == "" && req.Email != "" {
+		u.reverseWhoisByEmail(ctx, req)
+		return
+	}
 	if !cfg.IsDomainInScope(req.Domain) {
 		return
 	}
@@ -492,6 +495,30 @@ func (u *Umbrella) OnWhoisRequest(ctx context.Context, req *requests.WhoisReques
 	}
 }
 
+// reverseWhoisByEmail publishes the domains registered using the email address of the request.
+func (u *Umbrella) reverseWhoisByEmail(ctx context.Context, req *requests.WhoisRequest) {
+	cfg, bus, err := ContextConfigBus(ctx)
+	if err != nil {
+		return
+	}
+
+	domains := stringset.New()
+	for _, d := range u.queryReverseWhois(ctx, u.reverseWhoisByEmailURL(req.Email)) {
+		if !cfg.IsDomainInScope(d) {
+			domains.Insert(d)
+		}
+	}
+
+	if len(domains) > 0 {
+		bus.Publish(requests.NewWhoisTopic, eventbus.PriorityHigh, &requests.WhoisRequest{
+			Email:      req.Email,
+			NewDomains: domains.Slice(),
+			Tag:        u.SourceType,
+			Source:     u.String(),
+		})
+	}
+}
+
 func (u *Umbrella) restHeaders() map[string]string {
 	headers := map[string]string{"Content-Type": "application/json"}
 
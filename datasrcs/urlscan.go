@@ -74,7 +74,7 @@ func (u *URLScan) OnDNSRequest(ctx context.Context, req *requests.DNSRequest) {
 		fmt.Sprintf("Querying %s for %s subdomains", u.String(), req.Domain))
 
 	url := u.searchURL(req.Domain)
-	page, err := http.RequestWebPage(url, nil, nil, "", "")
+	page, err := requestWebPage(u.sys, u, url, nil, nil, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", u.String(), url, err))
 		return
@@ -125,7 +125,7 @@ func (u *URLScan) getSubsFromResult(ctx context.Context, id string) stringset.Se
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, u.String())
 
 	url := u.resultURL(id)
-	page, err := http.RequestWebPage(url, nil, nil, "", "")
+	page, err := requestWebPage(u.sys, u, url, nil, nil, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", u.String(), url, err))
 		return subs
@@ -162,7 +162,7 @@ func (u *URLScan) attemptSubmission(ctx context.Context, domain string) string {
 	}
 	url := "https://urlscan.io/api/v1/scan/"
 	body := strings.NewReader(u.submitBody(domain))
-	page, err := http.RequestWebPage(url, body, headers, "", "")
+	page, err := requestWebPage(u.sys, u, url, body, headers, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", u.String(), url, err))
 		return ""
@@ -183,7 +183,7 @@ func (u *URLScan) attemptSubmission(ctx context.Context, domain string) string {
 
 	// Keep this data source active while waiting for the scan to complete
 	for {
-		_, err = http.RequestWebPage(result.API, nil, nil, "", "")
+		_, err = requestWebPage(u.sys, u, result.API, nil, nil, "", "")
 		if err == nil || err.Error() != "404 Not Found" {
 			break
 		}
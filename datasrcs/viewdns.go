@@ -6,13 +6,13 @@ package datasrcs
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/OWASP/Amass/v3/eventbus"
 	"github.com/OWASP/Amass/v3/net"
-	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/OWASP/Amass/v3/systems"
@@ -23,11 +23,15 @@ type ViewDNS struct {
 	requests.BaseService
 
 	SourceType string
+	sys        systems.System
 }
 
 // NewViewDNS returns he object initialized, but not yet started.
 func NewViewDNS(sys systems.System) *ViewDNS {
-	v := &ViewDNS{SourceType: requests.SCRAPE}
+	v := &ViewDNS{
+		SourceType: requests.SCRAPE,
+		sys:        sys,
+	}
 
 	v.BaseService = *requests.NewBaseService(v, "ViewDNS")
 	return v
@@ -65,7 +69,7 @@ func (v *ViewDNS) OnDNSRequest(ctx context.Context, req *requests.DNSRequest) {
 	var unique []string
 	u := v.getIPHistoryURL(req.Domain)
 	// The ViewDNS IP History lookup sometimes reveals interesting results
-	page, err := http.RequestWebPage(u, nil, nil, "", "")
+	page, err := requestWebPage(v.sys, v, u, nil, nil, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", v.String(), u, err))
 		return
@@ -94,15 +98,16 @@ func (v *ViewDNS) OnWhoisRequest(ctx context.Context, req *requests.WhoisRequest
 		return
 	}
 
-	if !cfg.IsDomainInScope(req.Domain) {
+	// The registrant name or email address can be searched for in place of the domain name
+	if req.Domain != "" && !cfg.IsDomainInScope(req.Domain) {
 		return
 	}
 
 	v.CheckRateLimit()
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, v.String())
 
-	u := v.getReverseWhoisURL(req.Domain)
-	page, err := http.RequestWebPage(u, nil, nil, "", "")
+	u := v.getReverseWhoisURL(whoisSearchTerm(req))
+	page, err := requestWebPage(v.sys, v, u, nil, nil, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", v.String(), u, err))
 		return
@@ -130,6 +135,8 @@ func (v *ViewDNS) OnWhoisRequest(ctx context.Context, req *requests.WhoisRequest
 	if len(matches) > 0 {
 		bus.Publish(requests.NewWhoisTopic, eventbus.PriorityHigh, &requests.WhoisRequest{
 			Domain:     req.Domain,
+			Company:    req.Company,
+			Email:      req.Email,
 			NewDomains: matches.Slice(),
 			Tag:        v.SourceType,
 			Source:     v.String(),
@@ -160,9 +167,9 @@ func getViewDNSTable(page string) string {
 	return page[begin+i : end]
 }
 
-func (v *ViewDNS) getReverseWhoisURL(domain string) string {
+func (v *ViewDNS) getReverseWhoisURL(term string) string {
 	format := "https://viewdns.info/reversewhois/?q=%s"
-	return fmt.Sprintf(format, domain)
+	return fmt.Sprintf(format, url.QueryEscape(term))
 }
 
 func (v *ViewDNS) getIPHistoryURL(domain string) string {
@@ -14,7 +14,6 @@ import (
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/eventbus"
-	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/systems"
 )
@@ -107,26 +106,25 @@ func (w *WhoisXML) OnWhoisRequest(ctx context.Context, req *requests.WhoisReques
 		return
 	}
 
-	if !cfg.IsDomainInScope(req.Domain) {
+	if req.Domain != "" && !cfg.IsDomainInScope(req.Domain) {
 		return
 	}
+	term := whoisSearchTerm(req)
 	bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
-		fmt.Sprintf("Querying %s for %s subdomains", w.String(), req.Domain))
+		fmt.Sprintf("Querying %s for domains registered by %s", w.String(), term))
 
 	w.CheckRateLimit()
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, w.String())
 
-	u := w.getReverseWhoisURL(req.Domain)
+	u := w.getReverseWhoisURL()
 	headers := map[string]string{"X-Authentication-Token": w.creds.Key}
 
-	var r = WhoisXMLBasicRequest{
-		Search: "historic",
-		Mode:   "purchase",
+	jr, err := json.Marshal(whoisXMLRequestBody(req))
+	if err != nil {
+		return
 	}
-	r.SearchTerms.Include = append(r.SearchTerms.Include, req.Domain)
-	jr, _ := json.Marshal(r)
 
-	page, err := http.RequestWebPage(u, bytes.NewReader(jr), headers, "", "")
+	page, err := requestWebPage(w.sys, w, u, bytes.NewReader(jr), headers, "", "")
 	if err != nil {
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s: %s: %v", w.String(), u, err))
 		return
@@ -144,6 +142,8 @@ func (w *WhoisXML) OnWhoisRequest(ctx context.Context, req *requests.WhoisReques
 	if q.Found > 0 {
 		bus.Publish(requests.NewWhoisTopic, eventbus.PriorityHigh, &requests.WhoisRequest{
 			Domain:     req.Domain,
+			Company:    req.Company,
+			Email:      req.Email,
 			NewDomains: q.List,
 			Tag:        w.SourceType,
 			Source:     w.String(),
@@ -151,6 +151,38 @@ func (w *WhoisXML) OnWhoisRequest(ctx context.Context, req *requests.WhoisReques
 	}
 }
 
-func (w *WhoisXML) getReverseWhoisURL(domain string) string {
+// whoisXMLRequestBody returns the search for the domain name, or the registrant contact fields
+// matching the organization or email address of the request.
+func whoisXMLRequestBody(req *requests.WhoisRequest) interface{} {
+	if req.Domain != "" {
+		r := WhoisXMLBasicRequest{
+			Search: "historic",
+			Mode:   "purchase",
+		}
+
+		r.SearchTerms.Include = append(r.SearchTerms.Include, req.Domain)
+		return r
+	}
+
+	r := WhoisXMLAdvanceRequest{
+		Search: "historic",
+		Mode:   "purchase",
+	}
+	if req.Company != "" {
+		r.SearchTerms = append(r.SearchTerms, WhoisXMLAdvanceSearchTerms{
+			Field: "RegistrantContact.Organization",
+			Term:  req.Company,
+		})
+	}
+	if req.Email != "" {
+		r.SearchTerms = append(r.SearchTerms, WhoisXMLAdvanceSearchTerms{
+			Field: "RegistrantContact.Email",
+			Term:  req.Email,
+		})
+	}
+	return r
+}
+
+func (w *WhoisXML) getReverseWhoisURL() string {
 	return "https://reverse-whois-api.whoisxmlapi.com/api/v2"
 }
@@ -0,0 +1,42 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package datasrcs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestWhoisXMLRequestBody(t *testing.T) {
+	tests := []struct {
+		req  *requests.WhoisRequest
+		want string
+	}{
+		{
+			req:  &requests.WhoisRequest{Domain: "example.com"},
+			want: `"basicSearchTerms":{"include":["example.com"]}`,
+		},
+		{
+			req:  &requests.WhoisRequest{Company: "Example, Inc."},
+			want: `"advancedSearchTerms":[{"field":"RegistrantContact.Organization","term":"Example, Inc."}]`,
+		},
+		{
+			req:  &requests.WhoisRequest{Email: "hostmaster@example.com"},
+			want: `"advancedSearchTerms":[{"field":"RegistrantContact.Email","term":"hostmaster@example.com"}]`,
+		},
+	}
+
+	for _, test := range tests {
+		body, err := json.Marshal(whoisXMLRequestBody(test.req))
+		if err != nil {
+			t.Fatalf("Failed to encode the request body: %v", err)
+		}
+		if !strings.Contains(string(body), test.want) {
+			t.Errorf("The request body %s did not contain %s", body, test.want)
+		}
+	}
+}
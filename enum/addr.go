@@ -4,8 +4,11 @@
 package enum
 
 import (
+	"fmt"
 	"net"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/OWASP/Amass/v3/eventbus"
 	amassnet "github.com/OWASP/Amass/v3/net"
@@ -13,12 +16,36 @@ import (
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/OWASP/Amass/v3/stringfilter"
+	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/miekg/dns"
 )
 
+// The maximum number of discovered names that will be tried as SNI values for an address.
+const maxSNICandidates = 25
+
+// The maximum number of addresses that SNI candidates are collected for.
+const maxSNIAddresses = 1 << 16
+
+// The time allowed for each liveness probe to receive a response.
+const livenessTimeout = 3 * time.Second
+
+// The source of names found by sweeping ip6.arpa names, which are reported separately
+const reverseDNSv6Source = "Reverse DNS IPv6"
+
 type addrMsg struct {
 	Req      *requests.AddrRequest
 	Resolved bool
+	// Only the certificates are pulled again, using the SNI candidates discovered since the last pull
+	CertsOnly bool
+}
+
+// sniCandidates holds the names discovered to be sharing an address.
+type sniCandidates struct {
+	names stringset.Set
+	// Set once the certificates of the address have been pulled
+	pulled bool
+	// The names discovered since the certificates were pulled
+	pending stringset.Set
 }
 
 // AddressManager handles the investigation of addresses associated with newly resolved FQDNs.
@@ -27,6 +54,10 @@ type AddressManager struct {
 	queue       *queue.Queue
 	filter      stringfilter.Filter
 	sweepFilter stringfilter.Filter
+
+	// Names discovered to be sharing each resolved address
+	namesLock sync.Mutex
+	addrNames map[string]*sniCandidates
 }
 
 // NewAddressManager returns an initialized AddressManager.
@@ -36,6 +67,7 @@ func NewAddressManager(e *Enumeration) *AddressManager {
 		queue:       queue.NewQueue(),
 		filter:      stringfilter.NewBloomFilter(1 << 16),
 		sweepFilter: stringfilter.NewBloomFilter(1 << 16),
+		addrNames:   make(map[string]*sniCandidates),
 	}
 }
 
@@ -54,6 +86,17 @@ func (r *AddressManager) InputName(req *requests.DNSRequest) {
 
 			addr := strings.TrimSpace(rec.Data)
 			if t == dns.TypeA || t == dns.TypeAAAA {
+				if r.addNameForAddr(addr, req.Name) {
+					// The certificates of the address were pulled before the name was discovered
+					r.queue.Append(&addrMsg{
+						Req: &requests.AddrRequest{
+							Address: addr,
+							Domain:  req.Domain,
+						},
+						Resolved:  true,
+						CertsOnly: true,
+					})
+				}
 				r.addResolvedAddr(addr, req.Domain)
 			}
 		}
@@ -78,6 +121,67 @@ func (r *AddressManager) addResolvedAddr(addr, domain string) {
 	}
 }
 
+// addNameForAddr returns true when the certificates of the address need to be pulled again,
+// since the name was discovered after the previous pull and no other pull is waiting.
+func (r *AddressManager) addNameForAddr(addr, name string) bool {
+	r.namesLock.Lock()
+	defer r.namesLock.Unlock()
+
+	c, found := r.addrNames[addr]
+	if !found {
+		if len(r.addrNames) >= maxSNIAddresses {
+			return false
+		}
+
+		c = &sniCandidates{
+			names:   stringset.New(),
+			pending: stringset.New(),
+		}
+		r.addrNames[addr] = c
+	}
+	if c.names.Has(name) || c.names.Len() >= maxSNICandidates {
+		return false
+	}
+
+	c.names.Insert(name)
+	if !c.pulled {
+		return false
+	}
+
+	c.pending.Insert(name)
+	return c.pending.Len() == 1
+}
+
+// namesForAddr returns the SNI candidates of the address and marks its certificates as pulled.
+func (r *AddressManager) namesForAddr(addr string) []string {
+	r.namesLock.Lock()
+	defer r.namesLock.Unlock()
+
+	c, found := r.addrNames[addr]
+	if !found {
+		return []string{}
+	}
+
+	c.pulled = true
+	c.pending = stringset.New()
+	return c.names.Slice()
+}
+
+// pendingNamesForAddr returns the SNI candidates discovered since the certificates of the address were pulled.
+func (r *AddressManager) pendingNamesForAddr(addr string) []string {
+	r.namesLock.Lock()
+	defer r.namesLock.Unlock()
+
+	c, found := r.addrNames[addr]
+	if !found {
+		return []string{}
+	}
+
+	names := c.pending.Slice()
+	c.pending = stringset.New()
+	return names
+}
+
 // OutputNames implements the FQDNManager interface.
 func (r *AddressManager) OutputNames(num int) []*requests.DNSRequest {
 	return []*requests.DNSRequest{}
@@ -120,7 +224,11 @@ func (r *AddressManager) OutputRequests(num int) int {
 	element, ok := r.queue.Next()
 	for ok {
 		msg := element.(*addrMsg)
-		go r.processAddress(msg.Req, msg.Resolved)
+		if msg.CertsOnly {
+			go r.pullCertificates(msg.Req.Address)
+		} else {
+			go r.processAddress(msg.Req, msg.Resolved)
+		}
 
 		count++
 		if count >= num {
@@ -143,6 +251,10 @@ func (r *AddressManager) Stop() error {
 	r.queue = queue.NewQueue()
 	r.filter = stringfilter.NewBloomFilter(1 << 16)
 	r.sweepFilter = stringfilter.NewBloomFilter(1 << 16)
+
+	r.namesLock.Lock()
+	r.addrNames = make(map[string]*sniCandidates)
+	r.namesLock.Unlock()
 	return nil
 }
 
@@ -151,7 +263,38 @@ func (r *AddressManager) processAddress(req *requests.AddrRequest, resolved bool
 	r.reverseDNSSweep(req.Address)
 
 	if r.enum.Config.Active && resolved {
-		r.enum.namesFromCertificates(req.Address)
+		names := r.namesForAddr(req.Address)
+
+		r.enum.namesFromCertificates(req.Address, names)
+		if r.enum.Config.VHosts {
+			r.enum.namesFromVirtualHosts(req.Address, req.Domain, names)
+		}
+	}
+	if r.enum.Config.Liveness && resolved {
+		r.probeLiveness(req.Address)
+	}
+	if resolved {
+		r.enum.classifyAddress(req)
+	}
+}
+
+func (r *AddressManager) pullCertificates(addr string) {
+	if names := r.pendingNamesForAddr(addr); len(names) > 0 {
+		r.enum.namesFromCertificates(addr, names)
+	}
+}
+
+func (r *AddressManager) probeLiveness(addr string) {
+	// Do not send probes to the reserved address ranges
+	if yes, _ := amassnet.IsReservedAddress(addr); yes {
+		return
+	}
+
+	r.enum.Bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, "Liveness")
+
+	result := amassnet.ProbeLiveness(r.enum.ctx, addr, r.enum.Config.LivenessPorts, livenessTimeout)
+	if err := r.enum.Graph.InsertLiveness(addr, result.Alive(), result.OpenPorts, time.Now()); err != nil {
+		r.enum.Bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("Liveness: %s: %v", addr, err))
 	}
 }
 
@@ -161,13 +304,19 @@ func (r *AddressManager) reverseDNSSweep(addr string) {
 		return
 	}
 
+	size := 250
+	if r.enum.Config.Active {
+		size = 500
+	}
+
 	var ips []net.IP
 	cidr := r.enum.getAddrCIDR(addr)
 	// Get information about nearby IP addresses
-	if r.enum.Config.Active {
-		ips = amassnet.CIDRSubset(cidr, addr, 500)
+	if amassnet.IsIPv6(net.ParseIP(addr)) {
+		// IPv6 prefixes are too large for contiguous ranges to be useful
+		ips = amassnet.IPv6Sample(cidr, addr, size/4)
 	} else {
-		ips = amassnet.CIDRSubset(cidr, addr, 250)
+		ips = amassnet.CIDRSubset(cidr, addr, size)
 	}
 
 	for _, ip := range ips {
@@ -218,6 +367,11 @@ func (e *Enumeration) reverseDNSQuery(ip string) {
 		return
 	}
 
+	source := "Reverse DNS"
+	if strings.HasSuffix(ptr, ".ip6.arpa") {
+		source = reverseDNSv6Source
+	}
+
 	e.Bus.Publish(requests.NameResolvedTopic, eventbus.PriorityLow, &requests.DNSRequest{
 		Name:   ptr,
 		Domain: domain,
@@ -228,7 +382,7 @@ func (e *Enumeration) reverseDNSQuery(ip string) {
 			Data: answer,
 		}},
 		Tag:    requests.DNS,
-		Source: "Reverse DNS",
+		Source: source,
 	})
 }
 
@@ -0,0 +1,72 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestAddressManagerSNICandidates(t *testing.T) {
+	r := NewAddressManager(nil)
+	addr := "2001:db8::10"
+
+	if r.addNameForAddr(addr, "www.example.com") || r.addNameForAddr(addr, "mail.example.com") {
+		t.Errorf("A pull was requested before the certificates of the address were pulled")
+	}
+	if names := r.namesForAddr(addr); len(names) != 2 {
+		t.Errorf("namesForAddr returned %v", names)
+	}
+
+	// Names discovered after the pull cause a single pull to be requested
+	if !r.addNameForAddr(addr, "api.example.com") {
+		t.Errorf("A pull was not requested for the name discovered after the certificates were pulled")
+	}
+	if r.addNameForAddr(addr, "dev.example.com") {
+		t.Errorf("Another pull was requested while the previous one was waiting")
+	}
+	if r.addNameForAddr(addr, "www.example.com") {
+		t.Errorf("A pull was requested for a name that was already tried")
+	}
+
+	names := r.pendingNamesForAddr(addr)
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "api.example.com" || names[1] != "dev.example.com" {
+		t.Errorf("pendingNamesForAddr returned %v", names)
+	}
+	if names := r.pendingNamesForAddr(addr); len(names) != 0 {
+		t.Errorf("pendingNamesForAddr returned the names of the previous pull: %v", names)
+	}
+	if !r.addNameForAddr(addr, "test.example.com") {
+		t.Errorf("A pull was not requested after the previous one completed")
+	}
+
+	for i := 0; i < maxSNICandidates; i++ {
+		r.addNameForAddr(addr, "host"+strconv.Itoa(i)+".example.com")
+	}
+	if names := r.namesForAddr(addr); len(names) != maxSNICandidates {
+		t.Errorf("The address has %d SNI candidates instead of %d", len(names), maxSNICandidates)
+	}
+}
+
+func TestAddressManagerSNIAddressLimit(t *testing.T) {
+	r := NewAddressManager(nil)
+
+	for i := 0; i < maxSNIAddresses; i++ {
+		r.addNameForAddr("10.0."+strconv.Itoa(i/256)+"."+strconv.Itoa(i%256), "www.example.com")
+	}
+	r.addNameForAddr("192.0.2.1", "www.example.com")
+
+	if len(r.addrNames) != maxSNIAddresses {
+		t.Errorf("SNI candidates are collected for %d addresses", len(r.addrNames))
+	}
+	if names := r.namesForAddr("192.0.2.1"); len(names) != 0 {
+		t.Errorf("SNI candidates were collected beyond the limit: %v", names)
+	}
+
+	if err := r.Stop(); err != nil || len(r.addrNames) != 0 {
+		t.Errorf("Stop did not release the SNI candidates")
+	}
+}
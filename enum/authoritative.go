@@ -0,0 +1,70 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/OWASP/Amass/v3/datasrcs"
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+)
+
+// addAuthoritativeServer learns the address of a name server authoritative for the zone, so that
+// the names within the zone are resolved by the server, and records the address in the graph.
+func (dms *DataManagerService) addAuthoritativeServer(ctx context.Context, zone, ns string) {
+	_, bus, err := datasrcs.ContextConfigBus(ctx)
+	if err != nil {
+		return
+	}
+
+	// Only IPv4 addresses are used, since the resolvers are setup from address and port strings
+	a, _, err := dms.sys.Pool().Resolve(ctx, ns, "A", resolvers.PriorityHigh)
+	if err != nil {
+		bus.Publish(requests.LogTopic, eventbus.PriorityLow,
+			fmt.Sprintf("DNS: Authoritative server %s for %s has no A record: %v", ns, zone, err))
+		return
+	}
+
+	for _, ans := range a {
+		if err := dms.auth.AddZoneServer(zone, ans.Data); err != nil {
+			bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("DNS: %v", err))
+			continue
+		}
+
+		bus.Publish(requests.LogTopic, eventbus.PriorityLow,
+			fmt.Sprintf("DNS: Names within %s are resolved by the authoritative server %s (%s)", zone, ns, ans.Data))
+		if err := dms.graph.InsertNameserverAddress(ns, ans.Data); err != nil {
+			bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
+				fmt.Sprintf("%s failed to insert the name server address: %v", dms.graph, err))
+		}
+	}
+}
+
+// loadAuthoritativeServers provides the authoritative servers recorded by previous enumerations of
+// the same scope, so that queries are sent to them before the zones are discovered again.
+func (e *Enumeration) loadAuthoritativeServers() {
+	for _, g := range e.Sys.GraphDatabases() {
+		events := e.scopeEvents(g)
+		if len(events) == 0 {
+			continue
+		}
+
+		for zone, servers := range g.ZoneNameservers(events...) {
+			if !e.Config.IsDomainInScope(zone) {
+				continue
+			}
+
+			for _, ns := range servers {
+				for _, addr := range ns.Addresses {
+					if err := e.authoritative.AddZoneServer(zone, addr); err == nil {
+						e.Graph.InsertNameserverAddress(ns.Name, addr)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/google/uuid"
+)
+
+// The prefix of the checkpoint files written within the output directory for each enumeration
+const checkpointFilePrefix = "checkpoint_"
+
+// Checkpoint records the progress of an enumeration, so that an interrupted run can be resumed.
+type Checkpoint struct {
+	UUID    string    `json:"uuid"`
+	Domains []string  `json:"domains"`
+	Updated time.Time `json:"updated"`
+
+	// Names that were resolved and already shared with the data sources
+	Resolved []*CheckpointName `json:"resolved,omitempty"`
+
+	// Names accepted into the enumeration that had not been answered yet
+	Pending []*CheckpointName `json:"pending,omitempty"`
+
+	// The number of times each proper subdomain was seen, which selects the brute forcing already performed
+	Subdomains map[string]int `json:"subdomains,omitempty"`
+
+	// The root domains each data source has already been queried for
+	Sources map[string][]string `json:"sources,omitempty"`
+}
+
+// CheckpointName is a name in the checkpoint, with the details required to submit it again.
+type CheckpointName struct {
+	Name   string `json:"name"`
+	Domain string `json:"domain"`
+	Tag    string `json:"tag"`
+	Source string `json:"source"`
+}
+
+func newCheckpointName(req *requests.DNSRequest) *CheckpointName {
+	return &CheckpointName{
+		Name:   req.Name,
+		Domain: req.Domain,
+		Tag:    req.Tag,
+		Source: req.Source,
+	}
+}
+
+// CheckpointPath returns the path of the checkpoint file for the enumeration within the output directory.
+func CheckpointPath(cfg *config.Config, id string) string {
+	dir := config.OutputDirectory(cfg.Dir)
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, checkpointFilePrefix+id+".json")
+}
+
+// LoadCheckpoint reads the checkpoint written by the enumeration with the provided UUID.
+func LoadCheckpoint(cfg *config.Config, id string) (*Checkpoint, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("The enumeration UUID %s is not valid: %v", id, err)
+	}
+
+	path := CheckpointPath(cfg, id)
+	if path == "" {
+		return nil, fmt.Errorf("Failed to obtain the output directory")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the checkpoint for enumeration %s: %v", id, err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("Failed to parse the checkpoint for enumeration %s: %v", id, err)
+	}
+	if cp.UUID != id {
+		return nil, fmt.Errorf("The checkpoint file belongs to enumeration %s", cp.UUID)
+	}
+	return &cp, nil
+}
+
+// SaveCheckpoint writes the checkpoint into the output directory.
+func SaveCheckpoint(cfg *config.Config, cp *Checkpoint) error {
+	path := CheckpointPath(cfg, cp.UUID)
+	if path == "" {
+		return fmt.Errorf("Failed to obtain the output directory")
+	}
+
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("Failed to write the checkpoint: %v", err)
+	}
+	// The rename prevents a partially written file from replacing the previous checkpoint
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// checkpointState tracks the progress of the enumeration between checkpoints.
+type checkpointState struct {
+	sync.Mutex
+
+	// The checkpoint the enumeration was resumed from, or nil
+	prior         *Checkpoint
+	priorResolved map[string]struct{}
+	priorSources  map[string]map[string]struct{}
+
+	queued     map[string]*CheckpointName
+	inflight   map[string]*inflightName
+	resolved   map[string]*CheckpointName
+	subdomains map[string]int
+	released   map[string]map[string]time.Time
+	last       time.Time
+}
+
+type inflightName struct {
+	Name *CheckpointName
+	Sent time.Time
+}
+
+func newCheckpointState(prior *Checkpoint) *checkpointState {
+	c := &checkpointState{
+		prior:         prior,
+		priorResolved: make(map[string]struct{}),
+		priorSources:  make(map[string]map[string]struct{}),
+		queued:        make(map[string]*CheckpointName),
+		inflight:      make(map[string]*inflightName),
+		resolved:      make(map[string]*CheckpointName),
+		subdomains:    make(map[string]int),
+		released:      make(map[string]map[string]time.Time),
+		last:          time.Now(),
+	}
+	if prior == nil {
+		return c
+	}
+
+	for _, n := range prior.Resolved {
+		c.priorResolved[n.Name] = struct{}{}
+		c.resolved[n.Name] = n
+	}
+	for _, n := range prior.Pending {
+		c.queued[n.Name] = n
+	}
+	for sub, times := range prior.Subdomains {
+		c.subdomains[sub] = times
+	}
+	for src, domains := range prior.Sources {
+		c.priorSources[src] = make(map[string]struct{})
+		for _, d := range domains {
+			c.priorSources[src][d] = struct{}{}
+		}
+	}
+	return c
+}
+
+// names returns the names from the prior checkpoint that need to be submitted again.
+func (c *checkpointState) names() []*requests.Output {
+	if c.prior == nil {
+		return nil
+	}
+
+	var results []*requests.Output
+	for _, list := range [][]*CheckpointName{c.prior.Resolved, c.prior.Pending} {
+		for _, n := range list {
+			results = append(results, &requests.Output{
+				Name:    n.Name,
+				Domain:  n.Domain,
+				Tag:     n.Tag,
+				Sources: []string{n.Source},
+			})
+		}
+	}
+	return results
+}
+
+// queue records a name accepted into the enumeration.
+func (c *checkpointState) queue(req *requests.DNSRequest) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, found := c.resolved[req.Name]; !found {
+		c.queued[req.Name] = newCheckpointName(req)
+	}
+}
+
+// sent records a name released for resolution, which can be answered after the next checkpoint.
+func (c *checkpointState) sent(req *requests.DNSRequest) {
+	c.Lock()
+	defer c.Unlock()
+
+	n, found := c.queued[req.Name]
+	if !found {
+		n = newCheckpointName(req)
+	}
+	delete(c.queued, req.Name)
+
+	if _, found := c.resolved[req.Name]; !found {
+		c.inflight[req.Name] = &inflightName{
+			Name: n,
+			Sent: time.Now(),
+		}
+	}
+}
+
+// resolve records a name that was resolved, and returns true when the name had
+// already been shared with the data sources before the enumeration was resumed.
+func (c *checkpointState) resolve(req *requests.DNSRequest) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.queued, req.Name)
+	delete(c.inflight, req.Name)
+	if _, found := c.resolved[req.Name]; !found {
+		c.resolved[req.Name] = newCheckpointName(req)
+	}
+
+	_, found := c.priorResolved[req.Name]
+	return found
+}
+
+// resolvedCount returns the number of names resolved, including the names resolved before resuming.
+func (c *checkpointState) resolvedCount() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return len(c.resolved)
+}
+
+// subdomainTimes returns the number of times each subdomain was seen by the prior enumeration.
+func (c *checkpointState) subdomainTimes() map[string]int {
+	c.Lock()
+	defer c.Unlock()
+
+	times := make(map[string]int, len(c.subdomains))
+	for sub, t := range c.subdomains {
+		times[sub] = t
+	}
+	return times
+}
+
+func (c *checkpointState) subdomain(sub string, times int) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.subdomains[sub] = times
+}
+
+// queried returns true when the prior enumeration already queried the data source for the domain.
+func (c *checkpointState) queried(src, domain string) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	_, found := c.priorSources[src][domain]
+	return found
+}
+
+func (c *checkpointState) release(src, domain string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, found := c.released[src]; !found {
+		c.released[src] = make(map[string]time.Time)
+	}
+	c.released[src][domain] = time.Now()
+}
+
+// checkpoint returns the progress made at this time. Names and domains released before the
+// previous checkpoint have had an entire interval to be answered, so they are no longer pending.
+func (c *checkpointState) checkpoint(id string, domains []string) *Checkpoint {
+	c.Lock()
+	defer c.Unlock()
+
+	now := time.Now()
+	cp := &Checkpoint{
+		UUID:       id,
+		Domains:    domains,
+		Updated:    now,
+		Subdomains: make(map[string]int, len(c.subdomains)),
+		Sources:    make(map[string][]string),
+	}
+
+	for _, n := range c.resolved {
+		cp.Resolved = append(cp.Resolved, n)
+	}
+	for _, n := range c.queued {
+		cp.Pending = append(cp.Pending, n)
+	}
+	for name, in := range c.inflight {
+		if in.Sent.Before(c.last) {
+			delete(c.inflight, name)
+			continue
+		}
+		cp.Pending = append(cp.Pending, in.Name)
+	}
+	sortCheckpointNames(cp.Resolved)
+	sortCheckpointNames(cp.Pending)
+
+	for sub, times := range c.subdomains {
+		cp.Subdomains[sub] = times
+	}
+	for src, list := range c.priorSources {
+		for d := range list {
+			cp.Sources[src] = append(cp.Sources[src], d)
+		}
+	}
+	for src, list := range c.released {
+		for d, t := range list {
+			if _, found := c.priorSources[src][d]; !found && t.Before(c.last) {
+				cp.Sources[src] = append(cp.Sources[src], d)
+			}
+		}
+	}
+	for src := range cp.Sources {
+		sort.Strings(cp.Sources[src])
+	}
+
+	c.last = now
+	return cp
+}
+
+func sortCheckpointNames(names []*CheckpointName) {
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].Name < names[j].Name
+	})
+}
+
+// Resume continues the enumeration from the checkpoint written by an interrupted execution.
+// The configuration must use the UUID and root domain names of the checkpoint.
+func (e *Enumeration) Resume(cp *Checkpoint) {
+	e.checkpoint = newCheckpointState(cp)
+}
+
+// writeCheckpoint saves the progress of the enumeration into the output directory.
+func (e *Enumeration) writeCheckpoint() {
+	cp := e.checkpoint.checkpoint(e.Config.UUID.String(), e.Config.Domains())
+
+	if err := SaveCheckpoint(e.Config, cp); err != nil {
+		e.Config.Log.Printf("Failed to save the checkpoint: %v", err)
+	}
+}
+
+// removeCheckpoint deletes the checkpoint once the enumeration has completed.
+func (e *Enumeration) removeCheckpoint() {
+	if path := CheckpointPath(e.Config, e.Config.UUID.String()); path != "" {
+		os.Remove(path)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func checkpointRequest(name string) *requests.DNSRequest {
+	return &requests.DNSRequest{
+		Name:   name,
+		Domain: "example.com",
+		Tag:    requests.CERT,
+		Source: "Crtsh",
+	}
+}
+
+func checkpointNames(names []*CheckpointName) []string {
+	var results []string
+
+	for _, n := range names {
+		results = append(results, n.Name)
+	}
+	return results
+}
+
+func equalNames(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCheckpointState(t *testing.T) {
+	c := newCheckpointState(nil)
+
+	for _, name := range []string{"www.example.com", "mail.example.com", "dev.example.com", "vpn.example.com"} {
+		c.queue(checkpointRequest(name))
+	}
+	c.sent(checkpointRequest("www.example.com"))
+	c.sent(checkpointRequest("mail.example.com"))
+	c.sent(checkpointRequest("dev.example.com"))
+	if c.resolve(checkpointRequest("www.example.com")) {
+		t.Errorf("The name was reported as resolved before resuming without a prior checkpoint")
+	}
+	c.subdomain("dev.example.com", 2)
+	c.release("Crtsh", "example.com")
+
+	cp := c.checkpoint("uuid", []string{"example.com"})
+	if got := checkpointNames(cp.Resolved); !equalNames(got, []string{"www.example.com"}) {
+		t.Errorf("The checkpoint provided the resolved names %v", got)
+	}
+	// Names sent since the previous checkpoint can still be answered
+	if got := checkpointNames(cp.Pending); !equalNames(got, []string{"dev.example.com", "mail.example.com", "vpn.example.com"}) {
+		t.Errorf("The checkpoint provided the pending names %v", got)
+	}
+	if cp.Subdomains["dev.example.com"] != 2 {
+		t.Errorf("The checkpoint provided %d for the subdomain times", cp.Subdomains["dev.example.com"])
+	}
+	// The data source was queried after the previous checkpoint, so it could still provide names
+	if len(cp.Sources) != 0 {
+		t.Errorf("The checkpoint provided the data sources %v before they had an interval to respond", cp.Sources)
+	}
+
+	// Names and domains released before the previous checkpoint have had an interval to be answered
+	c.last = time.Now().Add(time.Minute)
+	cp = c.checkpoint("uuid", []string{"example.com"})
+	if got := checkpointNames(cp.Pending); !equalNames(got, []string{"vpn.example.com"}) {
+		t.Errorf("The checkpoint provided the pending names %v after the interval", got)
+	}
+	if got := cp.Sources["Crtsh"]; !equalNames(got, []string{"example.com"}) {
+		t.Errorf("The checkpoint provided the domains %v for the data source", got)
+	}
+}
+
+func TestCheckpointResume(t *testing.T) {
+	cfg, cleanup := guardTestConfig(t)
+	defer cleanup()
+
+	prior := &Checkpoint{
+		UUID:       cfg.UUID.String(),
+		Domains:    []string{"example.com"},
+		Updated:    time.Now(),
+		Resolved:   []*CheckpointName{newCheckpointName(checkpointRequest("www.example.com"))},
+		Pending:    []*CheckpointName{newCheckpointName(checkpointRequest("vpn.example.com"))},
+		Subdomains: map[string]int{"dev.example.com": 3},
+		Sources:    map[string][]string{"Brute Forcing": {"example.com"}},
+	}
+	if err := SaveCheckpoint(cfg, prior); err != nil {
+		t.Fatalf("Failed to save the checkpoint: %v", err)
+	}
+
+	cp, err := LoadCheckpoint(cfg, cfg.UUID.String())
+	if err != nil {
+		t.Fatalf("Failed to load the checkpoint: %v", err)
+	}
+	if _, err := LoadCheckpoint(cfg, "example"); err == nil {
+		t.Errorf("Failed to report an error for an invalid enumeration UUID")
+	}
+
+	c := newCheckpointState(cp)
+	if got := c.names(); len(got) != 2 || got[0].Name != "www.example.com" || got[1].Name != "vpn.example.com" {
+		t.Errorf("The resumed enumeration submits %d names again", len(got))
+	}
+	if !c.queried("Brute Forcing", "example.com") || c.queried("Crtsh", "example.com") {
+		t.Errorf("The data sources queried before resuming were not provided by the checkpoint")
+	}
+	if c.subdomainTimes()["dev.example.com"] != 3 {
+		t.Errorf("The subdomain times were not provided by the checkpoint")
+	}
+
+	// Resolved names are resolved again, without being shared with the data sources again
+	c.queue(checkpointRequest("www.example.com"))
+	c.sent(checkpointRequest("www.example.com"))
+	if !c.resolve(checkpointRequest("www.example.com")) {
+		t.Errorf("The name resolved before resuming was not reported")
+	}
+	if c.resolve(checkpointRequest("vpn.example.com")) {
+		t.Errorf("The name pending before resuming was reported as resolved")
+	}
+
+	next := c.checkpoint(cp.UUID, cp.Domains)
+	if got := checkpointNames(next.Resolved); !equalNames(got, []string{"vpn.example.com", "www.example.com"}) {
+		t.Errorf("The checkpoint after resuming provided the resolved names %v", got)
+	}
+	if len(next.Pending) != 0 {
+		t.Errorf("The checkpoint after resuming provided the pending names %v", checkpointNames(next.Pending))
+	}
+	if got := next.Sources["Brute Forcing"]; !equalNames(got, []string{"example.com"}) {
+		t.Errorf("The checkpoint after resuming provided the domains %v for the data source", got)
+	}
+}
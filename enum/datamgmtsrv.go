@@ -17,6 +17,7 @@ import (
 	"github.com/OWASP/Amass/v3/queue"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/OWASP/Amass/v3/stringfilter"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/miekg/dns"
 	"golang.org/x/net/publicsuffix"
@@ -27,19 +28,26 @@ import (
 type DataManagerService struct {
 	requests.BaseService
 
-	sys   systems.System
-	graph *graph.Graph
-	queue *queue.Queue
-	done  chan struct{}
+	sys      systems.System
+	graph    *graph.Graph
+	queue    *queue.Queue
+	done     chan struct{}
+	nsFilter stringfilter.Filter
+
+	// The servers authoritative for the zones, or nil when the queries use the pool
+	auth       *resolvers.AuthoritativeResolvers
+	authFilter stringfilter.Filter
 }
 
 // NewDataManagerService returns he object initialized, but not yet started.
 func NewDataManagerService(sys systems.System, g *graph.Graph) *DataManagerService {
 	dms := &DataManagerService{
-		sys:   sys,
-		graph: g,
-		queue: queue.NewQueue(),
-		done:  make(chan struct{}, 2),
+		sys:        sys,
+		graph:      g,
+		queue:      queue.NewQueue(),
+		done:       make(chan struct{}, 2),
+		nsFilter:   stringfilter.NewStringFilter(),
+		authFilter: stringfilter.NewStringFilter(),
 	}
 	dms.BaseService = *requests.NewBaseService(dms, "Data Manager")
 
@@ -49,11 +57,15 @@ func NewDataManagerService(sys systems.System, g *graph.Graph) *DataManagerServi
 
 // OnDNSRequest implements the Service interface.
 func (dms *DataManagerService) OnDNSRequest(ctx context.Context, req *requests.DNSRequest) {
-	_, bus, err := datasrcs.ContextConfigBus(ctx)
+	cfg, bus, err := datasrcs.ContextConfigBus(ctx)
 	if err != nil {
 		return
 	}
 
+	if cfg.Excluded(req.Name) {
+		return
+	}
+
 	bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, dms.String())
 
 	// Check for CNAME records first
@@ -156,7 +168,7 @@ func (dms *DataManagerService) insertCNAME(ctx context.Context, req *requests.DN
 	}
 
 	domain = strings.ToLower(domain)
-	if domain == "" {
+	if domain == "" || cfg.Excluded(target) {
 		return
 	}
 
@@ -297,6 +309,13 @@ func (dms *DataManagerService) insertNS(ctx context.Context, req *requests.DNSRe
 		bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("%s failed to insert NS record: %v", dms.graph, err))
 	}
 
+	if cfg.IsDomainInScope(req.Name) && !dms.nsFilter.Duplicate(target) {
+		go dms.fingerprintNameserver(ctx, req.Name, target)
+	}
+	if dms.auth != nil && cfg.IsDomainInScope(req.Name) && !dms.authFilter.Duplicate(req.Name+","+target) {
+		go dms.addAuthoritativeServer(ctx, req.Name, target)
+	}
+
 	if target != domain {
 		dms.genNewNameEvent(ctx, target, domain)
 	}
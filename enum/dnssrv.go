@@ -32,6 +32,9 @@ type DNSService struct {
 
 	SourceType string
 	sys        systems.System
+
+	// The servers authoritative for the zones, or nil when the queries use the pool
+	auth *resolvers.AuthoritativeResolvers
 }
 
 // NewDNSService returns he object initialized, but not yet started.
@@ -98,7 +101,7 @@ func (ds *DNSService) queryInitialTypes(ctx context.Context, req *requests.DNSRe
 	for _, t := range InitialQueryTypes {
 		bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, ds.String())
 
-		if a, _, err := ds.sys.Pool().Resolve(ctx, req.Name, t, resolvers.PriorityLow); err == nil {
+		if a, _, err := ds.resolve(ctx, req.Name, t, resolvers.PriorityLow); err == nil {
 			answers = append(answers, a...)
 		} else {
 			ds.handleResolverError(ctx, err)
@@ -108,6 +111,14 @@ func (ds *DNSService) queryInitialTypes(ctx context.Context, req *requests.DNSRe
 	return answers
 }
 
+// resolve sends the query to the servers authoritative for the zone containing the name when they are known.
+func (ds *DNSService) resolve(ctx context.Context, name, qtype string, priority int) ([]requests.DNSAnswer, bool, error) {
+	if ds.auth != nil {
+		return ds.auth.Resolve(ctx, name, qtype, priority)
+	}
+	return ds.sys.Pool().Resolve(ctx, name, qtype, priority)
+}
+
 func (ds *DNSService) handleResolverError(ctx context.Context, e error) {
 	cfg, bus, err := datasrcs.ContextConfigBus(ctx)
 	if err != nil {
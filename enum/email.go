@@ -0,0 +1,30 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// newEmail stores the email addresses discovered by the data sources for domains in scope.
+func (e *Enumeration) newEmail(req *requests.EmailRequest) {
+	if req == nil || req.Email == "" {
+		return
+	}
+
+	parts := strings.Split(strings.ToLower(req.Email), "@")
+	if len(parts) != 2 || !e.Config.IsDomainInScope(parts[1]) {
+		return
+	}
+
+	e.updateLastActive("enum")
+	if _, err := e.Graph.InsertEmail(req.Email, req.Source, req.Tag, e.Config.UUID.String()); err != nil {
+		e.Bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
+			fmt.Sprintf("%s failed to insert the email address %s: %v", e.Graph, req.Email, err))
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/OWASP/Amass/v3/graph"
 	"github.com/OWASP/Amass/v3/queue"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
 	"github.com/OWASP/Amass/v3/stringfilter"
 	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/OWASP/Amass/v3/systems"
@@ -37,6 +38,21 @@ type Enumeration struct {
 	asMgr   *ASService
 	srcs    []requests.Service
 
+	// Set when the graph databases hold previous enumerations of the same scope
+	priorEvents bool
+	// The names inserted into the graph, mapped to true when no previous enumeration discovered them
+	newLock  sync.Mutex
+	newNames map[string]bool
+
+	// The servers authoritative for the zones, when names are not resolved by the pool
+	authoritative *resolvers.AuthoritativeResolvers
+
+	// The tuning selected by the passive preflight, when one was performed
+	preflight *PreflightTuning
+
+	// The progress written into the checkpoint, including the checkpoint resumed from
+	checkpoint *checkpointState
+
 	// The filter for new outgoing DNS queries
 	resFilter      stringfilter.Filter
 	resFilterCount int64
@@ -74,8 +90,11 @@ func NewEnumeration(cfg *config.Config, sys systems.System) *Enumeration {
 		logQueue:       queue.NewQueue(),
 		done:           make(chan struct{}),
 		resolvedFilter: stringfilter.NewBloomFilter(filterMaxSize),
+		checkpoint:     newCheckpointState(nil),
+		newNames:       make(map[string]bool),
 		enumStateChannels: &enumStateChans{
 			GetLastActive: make(chan chan time.Time, 10),
+			GetActiveSrcs: make(chan chan int, 10),
 			UpdateLast:    queue.NewQueue(),
 			GetPerSec:     make(chan chan *getPerSec, 10),
 			IncPerSec:     queue.NewQueue(),
@@ -90,12 +109,20 @@ func NewEnumeration(cfg *config.Config, sys systems.System) *Enumeration {
 		return e
 	}
 
-	e.dataMgr = NewDataManagerService(sys, e.Graph)
+	dms := NewDataManagerService(sys, e.Graph)
+	ds := NewDNSService(sys)
+	if cfg.Authoritative {
+		e.authoritative = resolvers.NewAuthoritativeResolvers(sys.Pool(), cfg.AuthoritativeRate)
+		dms.auth = e.authoritative
+		ds.auth = e.authoritative
+	}
+
+	e.dataMgr = dms
 	if err := e.dataMgr.Start(); err != nil {
 		return nil
 	}
 
-	e.dnsMgr = NewDNSService(sys)
+	e.dnsMgr = ds
 	if err := e.dnsMgr.Start(); err != nil {
 		return nil
 	}
@@ -104,6 +131,20 @@ func NewEnumeration(cfg *config.Config, sys systems.System) *Enumeration {
 	if err := e.asMgr.Start(); err != nil {
 		return nil
 	}
+	// The prefixes learned by previous executions are not requested from the data sources again
+	if path := cfg.ASNCachePath(); path != "" {
+		if n, err := e.asMgr.Cache.Load(path, time.Duration(cfg.ASNCacheTTL)*time.Minute); err != nil {
+			cfg.Log.Print(err)
+		} else if n > 0 {
+			cfg.Log.Printf("Loaded %d autonomous systems from the ASN cache", n)
+		}
+	}
+	// Addresses within infrastructure provided by the user are not sent to the data sources
+	for _, g := range sys.GraphDatabases() {
+		for _, r := range g.GroundTruth() {
+			e.asMgr.Cache.Override(r)
+		}
+	}
 
 	return e
 }
@@ -111,6 +152,9 @@ func NewEnumeration(cfg *config.Config, sys systems.System) *Enumeration {
 // Close cleans up resources instantiated by the Enumeration.
 func (e *Enumeration) Close() {
 	e.closedOnce.Do(func() {
+		if e.authoritative != nil {
+			e.authoritative.Stop()
+		}
 		e.Graph.Close()
 	})
 }
@@ -169,6 +213,16 @@ func (e *Enumeration) Start() error {
 	// Start the logging at this point, since data sources are used shortly
 	go e.periodicLogging()
 
+	// Record in the event that the results are only a sample of what the data sources provide
+	if e.Config.SampleSize > 0 {
+		e.Graph.SetEventMetadata(e.Config.UUID.String(), "sample_size", strconv.Itoa(e.Config.SampleSize))
+	}
+
+	// Record how the preflight tuned this enumeration
+	if e.preflight != nil {
+		e.Graph.SetEventMetadata(e.Config.UUID.String(), "preflight", e.preflight.String())
+	}
+
 	// If requests were made for specific ASNs, then those requests are
 	// send to included data sources at this point
 	for _, src := range e.srcs {
@@ -200,6 +254,8 @@ func (e *Enumeration) Start() error {
 		defer e.Bus.Unsubscribe(requests.NewAddrTopic, e.addrMgr.InputAddress)
 		e.Bus.Subscribe(requests.NewASNTopic, e.asMgr.Cache.Update)
 		defer e.Bus.Unsubscribe(requests.NewASNTopic, e.asMgr.Cache.Update)
+		e.Bus.Subscribe(requests.NewReputationTopic, e.newReputation)
+		defer e.Bus.Unsubscribe(requests.NewReputationTopic, e.newReputation)
 	}
 
 	/*
@@ -212,6 +268,8 @@ func (e *Enumeration) Start() error {
 	e.managers = append(e.managers, e.nameMgr)
 	e.Bus.Subscribe(requests.NewNameTopic, e.nameMgr.InputName)
 	defer e.Bus.Unsubscribe(requests.NewNameTopic, e.nameMgr.InputName)
+	e.Bus.Subscribe(requests.NewEmailTopic, e.newEmail)
+	defer e.Bus.Unsubscribe(requests.NewEmailTopic, e.newEmail)
 
 	/*
 	 * When not running in passive mode, the enumeration will need to keep
@@ -231,8 +289,18 @@ func (e *Enumeration) Start() error {
 	 * and names acquired from the graph database can be brought into the
 	 * enumeration
 	 */
-	go e.submitKnownNames()
+	if e.authoritative != nil {
+		e.loadAuthoritativeServers()
+	}
+	e.priorEvents = e.havePriorEvents()
+	go e.submitPriorNames()
 	go e.submitProvidedNames()
+	// The data sources do not provide the names discovered by the preflight again
+	if e.preflight != nil {
+		go e.submitKnownNames(e.preflight.findings)
+	}
+	// The names from the checkpoint of an interrupted execution continue where they were left
+	go e.submitKnownNames(e.checkpoint.names())
 
 	/*
 	 * Setup the DomainManager for releasing root domain names that are in
@@ -284,11 +352,15 @@ func (e *Enumeration) Start() error {
 	}
 
 	// Get the ball rolling before the timer fires
+	var finished bool
+	start := time.Now()
 	completed := e.useManagers()
 	more := time.NewTimer(5 * time.Second)
 	t := time.NewTimer(20 * time.Second)
 	perMin := time.NewTicker(time.Minute)
 	defer perMin.Stop()
+	progress := time.NewTicker(progressInterval)
+	defer progress.Stop()
 loop:
 	for {
 		select {
@@ -306,6 +378,7 @@ loop:
 
 			if completed == 0 && inactive {
 				// End the enumeration!
+				finished = true
 				e.Done()
 				break loop
 			}
@@ -320,6 +393,7 @@ loop:
 				if sec > 0 {
 					pct = (float64(retries) / float64(sec)) * 100
 					if e.isDataManagerQueueEmpty() && (sec < 10 || pct > 90.0) {
+						finished = true
 						e.Done()
 						break loop
 					}
@@ -328,6 +402,9 @@ loop:
 				e.Config.Log.Printf("Average DNS queries performed: %d/sec, Average retries required: %.2f%%", sec, pct)
 				e.clearPerSec()
 			}
+			e.writeCheckpoint()
+		case <-progress.C:
+			e.publishProgress(start)
 		}
 	}
 
@@ -340,6 +417,18 @@ loop:
 	if !e.Config.Passive {
 		// Attempt to fix IP address nodes without edges to netblocks
 		e.Graph.HealAddressNodes(e.asMgr.Cache, e.Config.UUID.String())
+
+		if path := e.Config.ASNCachePath(); path != "" {
+			if err := e.asMgr.Cache.Save(path); err != nil {
+				e.Config.Log.Print(err)
+			}
+		}
+	}
+	// Enumerations that were interrupted or timed out can be resumed from the checkpoint
+	if finished {
+		e.removeCheckpoint()
+	} else {
+		e.writeCheckpoint()
 	}
 	return nil
 }
@@ -429,12 +518,15 @@ func (e *Enumeration) useManagers() int {
 		for _, req := range reqs {
 			if e.Config.Passive {
 				e.updateLastActive("enum")
+				e.checkpoint.resolve(req)
 				if e.Config.IsDomainInScope(req.Name) {
+					e.markNewName(req.Name)
 					e.Graph.InsertFQDN(req.Name, req.Source, req.Tag, e.Config.UUID.String())
 				}
 				continue
 			}
 
+			e.checkpoint.sent(req)
 			e.Bus.Publish(requests.ResolveNameTopic, eventbus.PriorityLow, e.ctx, req)
 		}
 
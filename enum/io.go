@@ -4,51 +4,118 @@
 package enum
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/graph"
 	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringfilter"
 )
 
 // ExtractOutput is a convenience method for obtaining new discoveries made by the enumeration process.
+// The New field of each Output is set when the name was not found by previous enumerations.
 func (e *Enumeration) ExtractOutput(filter stringfilter.Filter, asinfo bool) []*requests.Output {
+	var results []*requests.Output
+
 	if e.Config.Passive {
-		return e.Graph.EventNames(e.Config.UUID.String(), filter)
+		results = e.Graph.EventNames(e.Config.UUID.String(), filter)
+	} else {
+		results = e.Graph.EventOutput(e.Config.UUID.String(), filter, asinfo, e.asMgr.Cache)
 	}
 
-	return e.Graph.EventOutput(e.Config.UUID.String(), filter, asinfo, e.asMgr.Cache)
+	e.newLock.Lock()
+	defer e.newLock.Unlock()
+
+	for _, o := range results {
+		o.New = e.newNames[o.Name]
+	}
+	return results
 }
 
-func (e *Enumeration) submitKnownNames() {
-	for _, g := range e.Sys.GraphDatabases() {
-		var events []string
+// markNewName decides, as the name is inserted into the graph, whether previous enumerations
+// discovered it. The findings are only migrated into the graph databases after the enumeration,
+// so the databases hold the names of the previous enumerations until then.
+func (e *Enumeration) markNewName(name string) {
+	e.newLock.Lock()
+	_, decided := e.newNames[name]
+	e.newLock.Unlock()
+	if decided {
+		return
+	}
 
-		for _, event := range g.EventList() {
-			for _, domain := range g.EventDomains(event) {
-				if e.Config.IsDomainInScope(domain) {
-					events = append(events, event)
-				}
+	// Without previous enumerations, there is nothing for the names to be new against
+	isNew := e.priorEvents
+	if isNew {
+		for _, g := range e.Sys.GraphDatabases() {
+			if _, err := g.ReadNode(name, "fqdn"); err == nil {
+				isNew = false
+				break
 			}
 		}
+	}
+
+	e.newLock.Lock()
+	if _, decided := e.newNames[name]; !decided {
+		e.newNames[name] = isNew
+	}
+	e.newLock.Unlock()
+}
+
+// havePriorEvents returns true when the graph databases hold previous enumerations of the same scope.
+func (e *Enumeration) havePriorEvents() bool {
+	for _, g := range e.Sys.GraphDatabases() {
+		if len(e.scopeEvents(g)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// submitPriorNames brings the in scope names discovered by previous enumerations into the enumeration.
+func (e *Enumeration) submitPriorNames() {
+	for _, g := range e.Sys.GraphDatabases() {
+		for _, event := range e.scopeEvents(g) {
+			var known []*requests.Output
 
-		for _, event := range events {
 			for _, output := range g.EventNames(event, nil) {
 				if e.Config.IsDomainInScope(output.Name) {
-					e.Bus.Publish(requests.NewNameTopic, eventbus.PriorityHigh, &requests.DNSRequest{
-						Name:   output.Name,
-						Domain: output.Domain,
-						Tag:    output.Tag,
-						Source: output.Sources[0],
-					})
+					known = append(known, output)
 				}
 			}
+			e.submitKnownNames(known)
 		}
 	}
 }
 
+// scopeEvents returns the events in the graph database that enumerated root domains in scope.
+func (e *Enumeration) scopeEvents(g *graph.Graph) []string {
+	var events []string
+
+	for _, event := range g.EventList() {
+		for _, domain := range g.EventDomains(event) {
+			if e.Config.IsDomainInScope(domain) {
+				events = append(events, event)
+				break
+			}
+		}
+	}
+	return events
+}
+
+func (e *Enumeration) submitKnownNames(known []*requests.Output) {
+	for _, output := range known {
+		e.Bus.Publish(requests.NewNameTopic, eventbus.PriorityHigh, &requests.DNSRequest{
+			Name:   output.Name,
+			Domain: output.Domain,
+			Tag:    output.Tag,
+			Source: output.Sources[0],
+		})
+	}
+}
+
 func (e *Enumeration) submitProvidedNames() {
 	for _, name := range e.Config.ProvidedNames {
 		if domain := e.Config.WhichDomain(name); domain != "" {
@@ -62,16 +129,31 @@ func (e *Enumeration) submitProvidedNames() {
 	}
 }
 
-func (e *Enumeration) namesFromCertificates(addr string) {
-	for _, name := range http.PullCertificateNames(addr, e.Config.Ports) {
-		if n := strings.TrimSpace(name); n != "" {
-			if domain := e.Config.WhichDomain(n); domain != "" {
-				e.Bus.Publish(requests.NewNameTopic, eventbus.PriorityHigh, &requests.DNSRequest{
-					Name:   n,
-					Domain: domain,
-					Tag:    requests.CERT,
-					Source: "Active Cert",
-				})
+func (e *Enumeration) namesFromCertificates(addr string, servernames []string) {
+	for _, cert := range http.PullCertificatesWithSNI(addr, e.Config.Ports, servernames) {
+		if cert.ServerName != "" {
+			e.Bus.Publish(requests.LogTopic, eventbus.PriorityLow, fmt.Sprintf(
+				"Active Cert: %s port %d provided a distinct certificate for SNI %s", addr, cert.Port, cert.ServerName))
+		}
+
+		if err := e.Graph.InsertAddressCertificate(addr, &graph.AddressCertificate{
+			Port:       cert.Port,
+			ServerName: cert.ServerName,
+			Names:      cert.Names,
+		}); err != nil {
+			e.Bus.Publish(requests.LogTopic, eventbus.PriorityHigh, fmt.Sprintf("Active Cert: %s: %v", addr, err))
+		}
+
+		for _, name := range cert.Names {
+			if n := strings.TrimSpace(name); n != "" {
+				if domain := e.Config.WhichDomain(n); domain != "" {
+					e.Bus.Publish(requests.NewNameTopic, eventbus.PriorityHigh, &requests.DNSRequest{
+						Name:   n,
+						Domain: domain,
+						Tag:    requests.CERT,
+						Source: "Active Cert",
+					})
+				}
 			}
 		}
 	}
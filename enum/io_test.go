@@ -0,0 +1,63 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/systems"
+)
+
+func TestMarkNewName(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AddDomain("example.com")
+
+	sys, err := systems.NewMockSystem(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create the mock system: %v", err)
+	}
+	defer sys.Shutdown()
+
+	e := &Enumeration{
+		Config:   cfg,
+		Sys:      sys,
+		newNames: make(map[string]bool),
+	}
+	if e.havePriorEvents() {
+		t.Fatalf("Previous enumerations were found in an empty graph database")
+	}
+	// Without previous enumerations, no names are marked as new
+	e.markNewName("www.example.com")
+	if e.newNames["www.example.com"] {
+		t.Errorf("The name was marked as new without any previous enumerations")
+	}
+
+	g := sys.GraphDatabases()[0]
+	if _, err := g.InsertFQDN("mail.example.com", "DNS", "dns", "ef9f9475-34ce-4c9a-9e5f-5aafd2ab4e9b"); err != nil {
+		t.Fatalf("Failed to insert the name of the previous enumeration: %v", err)
+	}
+
+	e.newNames = make(map[string]bool)
+	if e.priorEvents = e.havePriorEvents(); !e.priorEvents {
+		t.Fatalf("The previous enumeration was not found in the graph database")
+	}
+	e.markNewName("mail.example.com")
+	e.markNewName("api.example.com")
+	if e.newNames["mail.example.com"] {
+		t.Errorf("The name discovered by the previous enumeration was marked as new")
+	}
+	if !e.newNames["api.example.com"] {
+		t.Errorf("The name missing from the previous enumeration was not marked as new")
+	}
+
+	// The decision made at the first insertion is kept
+	if _, err := g.InsertFQDN("api.example.com", "DNS", "dns", "ef9f9475-34ce-4c9a-9e5f-5aafd2ab4e9b"); err != nil {
+		t.Fatalf("Failed to insert the name: %v", err)
+	}
+	e.markNewName("api.example.com")
+	if !e.newNames["api.example.com"] {
+		t.Errorf("The decision for the name changed after it was inserted again")
+	}
+}
@@ -5,12 +5,15 @@ package enum
 
 import (
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/OWASP/Amass/v3/eventbus"
 	"github.com/OWASP/Amass/v3/queue"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringfilter"
+	"github.com/OWASP/Amass/v3/stringset"
+	"github.com/OWASP/Amass/v3/systems"
 )
 
 // FQDNManager is the object type for taking in, generating and providing new DNS FQDNs.
@@ -85,18 +88,24 @@ func (r *DomainManager) OutputRequests(num int) int {
 	}
 
 	domain, index := r.nextDomainAndSrc()
+	// Data sources queried for the domain before the enumeration was resumed are skipped
+	for domain != "" && r.enum.checkpoint.queried(r.enum.srcs[index].String(), domain) {
+		domain, index = r.nextDomainAndSrc()
+	}
 	if domain == "" {
 		return 0
 	}
 
 	r.last = time.Now()
 	// Release the current domain name to the next data source
-	r.enum.srcs[index].DNSRequest(r.enum.ctx, &requests.DNSRequest{
+	src := r.enum.srcs[index]
+	src.DNSRequest(r.enum.ctx, &requests.DNSRequest{
 		Name:   domain,
 		Domain: domain,
 		Tag:    requests.DNS,
 		Source: "DNS",
 	})
+	r.enum.checkpoint.release(src.String(), domain)
 
 	return 1
 }
@@ -164,6 +173,7 @@ func (r *SubdomainManager) InputName(req *requests.DNSRequest) {
 	requests.SanitizeDNSRequest(req)
 
 	// Send every resolved name and associated DNS records to the data manager
+	r.enum.markNewName(req.Name)
 	r.enum.dataMgr.DNSRequest(r.enum.ctx, req)
 
 	if !r.enum.Config.IsDomainInScope(req.Name) {
@@ -175,6 +185,10 @@ func (r *SubdomainManager) InputName(req *requests.DNSRequest) {
 	if labels[1] == "_tcp" || labels[1] == "_udp" || labels[1] == "_tls" {
 		return
 	}
+	// The data sources and subdomain counts already include names resolved before resuming
+	if r.enum.checkpoint.resolve(req) {
+		return
+	}
 
 	r.rqueue.Append(req)
 	// Keep track of all domains and proper subdomains discovered
@@ -296,6 +310,7 @@ func (r *SubdomainManager) checkSubdomain(req *requests.DNSRequest) {
 		Source: req.Source,
 	}
 	times := r.timesForSubdomain(sub)
+	r.enum.checkpoint.subdomain(sub, times)
 
 	if sub != req.Domain {
 		r.enum.Bus.Publish(requests.SubDiscoveredTopic,
@@ -309,6 +324,7 @@ func (r *SubdomainManager) checkSubdomain(req *requests.DNSRequest) {
 
 	if nReq := r.enum.checkResFilter(subreq); nReq != nil {
 		r.queue.Append(nReq)
+		r.enum.checkpoint.queue(nReq)
 	}
 }
 
@@ -329,7 +345,8 @@ type timesReq struct {
 }
 
 func (r *SubdomainManager) timesManager() {
-	subdomains := make(map[string]int)
+	// Continue counting from the subdomains seen before the enumeration was resumed
+	subdomains := r.enum.checkpoint.subdomainTimes()
 
 	for {
 		select {
@@ -353,13 +370,29 @@ func (r *SubdomainManager) timesManager() {
 type NameManager struct {
 	enum  *Enumeration
 	queue *queue.Queue
+
+	// Track the number of names accepted from each data source per domain while sampling
+	sampleLock   sync.Mutex
+	sampleCounts map[string]int
+	srcNames     stringset.Set
+
+	// Enforces the result caps and quarantines anomalous data source volumes
+	guard *sourceGuard
 }
 
 // NewNameManager returns an initialized NameManager.
 func NewNameManager(e *Enumeration) *NameManager {
+	srcNames := stringset.New()
+	for _, src := range e.srcs {
+		srcNames.Insert(src.String())
+	}
+
 	return &NameManager{
-		enum:  e,
-		queue: queue.NewQueue(),
+		enum:         e,
+		queue:        queue.NewQueue(),
+		sampleCounts: make(map[string]int),
+		srcNames:     srcNames,
+		guard:        newSourceGuard(e.Config),
 	}
 }
 
@@ -370,11 +403,60 @@ func (r *NameManager) InputName(req *requests.DNSRequest) {
 	}
 	// Clean up the newly discovered name and domain
 	requests.SanitizeDNSRequest(req)
-	// Check that this name has not already been processed
-	if r.enum.checkResFilter(req) == nil {
+	// Names matched by the excluded name expressions are dropped before they can be resolved
+	if r.enum.Config.Excluded(req.Name) {
 		return
 	}
-	r.queue.Append(req)
+	if sys, ok := r.enum.Sys.(systems.SourceStatsSystem); ok && sys.SourceStats() != nil {
+		sys.SourceStats().AddName(req.Source)
+	}
+
+	var accepted bool
+	var released []*requests.DNSRequest
+	if r.srcNames.Has(req.Source) {
+		accepted, released = r.guard.Check(req)
+	} else {
+		accepted, released = true, r.guard.Corroborate(req.Name)
+	}
+	if accepted {
+		r.acceptNames([]*requests.DNSRequest{req})
+	}
+	// Quarantined names corroborated by this request can now enter the enumeration
+	r.acceptNames(released)
+}
+
+func (r *NameManager) acceptNames(reqs []*requests.DNSRequest) {
+	for _, req := range reqs {
+		// Check that this name has not already been processed
+		if r.enum.checkResFilter(req) == nil {
+			continue
+		}
+		if r.sampleLimitReached(req) {
+			continue
+		}
+		r.queue.Append(req)
+		r.enum.checkpoint.queue(req)
+	}
+}
+
+// sampleLimitReached returns true when the data source has already provided
+// the maximum number of names for the domain allowed while sampling.
+func (r *NameManager) sampleLimitReached(req *requests.DNSRequest) bool {
+	max := r.enum.Config.SampleSize
+	if max <= 0 || !r.srcNames.Has(req.Source) {
+		return false
+	}
+
+	r.sampleLock.Lock()
+	defer r.sampleLock.Unlock()
+
+	key := req.Source + ":" + req.Domain
+	if r.sampleCounts[key] >= max {
+		return true
+	}
+
+	r.sampleCounts[key]++
+	return false
 }
 
 // OutputNames implements the FQDNManager interface.
@@ -410,6 +492,7 @@ func (r *NameManager) RequestQueueLen() int {
 
 // Stop implements the FQDNManager interface.
 func (r *NameManager) Stop() error {
+	r.guard.Close()
 	r.queue = queue.NewQueue()
 	return nil
 }
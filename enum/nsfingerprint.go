@@ -0,0 +1,65 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/datasrcs"
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"golang.org/x/net/publicsuffix"
+)
+
+// The provider recorded for name servers within the registered domain of the zone
+const selfHostedProvider = "Self-hosted"
+
+// fingerprintNameserver identifies the DNS provider of the name server authoritative for
+// the zone, and queries the server for the software in use when active techniques are permitted.
+func (dms *DataManagerService) fingerprintNameserver(ctx context.Context, zone, ns string) {
+	cfg, bus, err := datasrcs.ContextConfigBus(ctx)
+	if err != nil {
+		return
+	}
+
+	provider := resolvers.NameserverProvider(ns)
+	if provider == "" {
+		zd, err1 := publicsuffix.EffectiveTLDPlusOne(zone)
+		nd, err2 := publicsuffix.EffectiveTLDPlusOne(ns)
+
+		if err1 == nil && err2 == nil && strings.EqualFold(zd, nd) {
+			provider = selfHostedProvider
+		}
+	}
+
+	var software, version string
+	if cfg.Active {
+		a, _, err := dms.sys.Pool().Resolve(ctx, ns, "A", resolvers.PriorityLow)
+		if err != nil {
+			a, _, err = dms.sys.Pool().Resolve(ctx, ns, "AAAA", resolvers.PriorityLow)
+		}
+
+		if err == nil && len(a) > 0 {
+			fp := resolvers.FingerprintNameserver(zone, ns, a[0].Data)
+
+			software, version = fp.Software, fp.Version
+			if software != "" || len(fp.Quirks) > 0 {
+				bus.Publish(requests.LogTopic, eventbus.PriorityLow,
+					fmt.Sprintf("DNS: Name server fingerprint: %s: software: %q, quirks: %s",
+						ns, software, strings.Join(fp.Quirks, ", ")))
+			}
+		}
+	}
+
+	if provider == "" && software == "" && version == "" {
+		return
+	}
+	if err := dms.graph.InsertNameserverFingerprint(ns, provider, software, version); err != nil {
+		bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
+			fmt.Sprintf("%s failed to insert the name server fingerprint: %v", dms.graph, err))
+	}
+}
@@ -0,0 +1,358 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/OWASP/Amass/v3/stringset"
+	"github.com/OWASP/Amass/v3/systems"
+)
+
+const (
+	// PreflightSampleSize is the number of names accepted from each data source per root domain during the preflight.
+	PreflightSampleSize = 100
+
+	// The number of minutes that the preflight is permitted to run
+	preflightTimeout = 5
+
+	// The maximum number of observed labels added to the brute forcing wordlist
+	preflightMaxLabels = 200
+
+	// The number of the largest subdomains checked for DNS wildcards, in addition to the root domains
+	preflightWildcardZones = 10
+
+	// The fraction of observed labels containing a pattern before the related alterations are used
+	preflightPatternRatio = 0.1
+
+	// The bounds of the number of concurrent DNS queries selected by the preflight
+	minPreflightDNSQueries = 100
+	maxPreflightDNSQueries = 20000
+)
+
+// PreflightTuning contains the observations of the passive preflight and the settings selected from
+// them for the active phase of the enumeration.
+type PreflightTuning struct {
+	// Names is the number of names discovered by the preflight
+	Names int `json:"names"`
+
+	// Subdomains is the number of proper subdomains containing the names
+	Subdomains int `json:"subdomains"`
+
+	// LargestZone is the number of names within the domain or subdomain containing the most names
+	LargestZone int `json:"largest_zone"`
+
+	// Wildcards are the root domains and subdomains that answered queries for names that cannot exist
+	Wildcards []string `json:"wildcards"`
+
+	// Labels are the most common labels of the names, which are added to the brute forcing wordlist
+	Labels []string `json:"labels"`
+
+	// The settings selected for the active phase
+	BruteForcing    bool `json:"brute_forcing"`
+	Recursive       bool `json:"recursive"`
+	MinForRecursive int  `json:"min_for_recursive"`
+	Alterations     bool `json:"alterations"`
+	FlipWords       bool `json:"flip_words"`
+	FlipNumbers     bool `json:"flip_numbers"`
+	AddNumbers      bool `json:"add_numbers"`
+	EditDistance    int  `json:"edit_distance"`
+	MinForWordFlip  int  `json:"min_for_word_flip"`
+	MaxDNSQueries   int  `json:"max_dns_queries"`
+
+	// Proportions of the observed labels that contain digits or hyphens
+	numbered   float64
+	hyphenated float64
+	// The zones holding the names, ordered by the number of names they contain
+	zones []string
+
+	findings []*requests.Output
+}
+
+// RunPreflight performs a quick passive enumeration of the configured domains that accepts a sample of
+// the names provided by each data source, and returns the tuning selected from the findings for the
+// active phase. The configuration is left as it was provided, and the tuning is applied by Apply.
+func RunPreflight(cfg *config.Config, sys systems.System) (*PreflightTuning, error) {
+	if err := cfg.CheckSettings(); err != nil {
+		return nil, err
+	}
+
+	saved := newPreflightSettings(cfg)
+	cfg.Preflight = false
+	cfg.Passive = true
+	cfg.Active = false
+	cfg.BruteForcing = false
+	cfg.Liveness = false
+	cfg.VHosts = false
+	// The preflight must not advance the cursors of the data sources past the results the enumeration requests
+	cfg.Monitor = false
+	cfg.SampleSize = PreflightSampleSize
+	if cfg.Timeout == 0 || cfg.Timeout > preflightTimeout {
+		cfg.Timeout = preflightTimeout
+	}
+
+	pre := NewEnumeration(cfg, sys)
+	if pre == nil {
+		saved.restore(cfg)
+		return nil, errors.New("Failed to setup the preflight enumeration")
+	}
+	defer pre.Close()
+
+	err := pre.Start()
+	saved.restore(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	t := analyzePreflight(cfg, pre.Graph.EventNames(cfg.UUID.String(), nil))
+	t.Wildcards = preflightWildcards(context.Background(), sys.Pool(), cfg, t.zones)
+	t.tune(cfg)
+	return t, nil
+}
+
+// The settings changed while the preflight executes as a passive enumeration
+type preflightSettings struct {
+	preflight, passive, active, brute, liveness, vhosts, monitor bool
+	sample, timeout                                              int
+}
+
+func newPreflightSettings(cfg *config.Config) *preflightSettings {
+	return &preflightSettings{
+		preflight: cfg.Preflight,
+		passive:   cfg.Passive,
+		active:    cfg.Active,
+		brute:     cfg.BruteForcing,
+		liveness:  cfg.Liveness,
+		vhosts:    cfg.VHosts,
+		monitor:   cfg.Monitor,
+		sample:    cfg.SampleSize,
+		timeout:   cfg.Timeout,
+	}
+}
+
+func (s *preflightSettings) restore(cfg *config.Config) {
+	cfg.Preflight = s.preflight
+	cfg.Passive = s.passive
+	cfg.Active = s.active
+	cfg.BruteForcing = s.brute
+	cfg.Liveness = s.liveness
+	cfg.VHosts = s.vhosts
+	cfg.Monitor = s.monitor
+	cfg.SampleSize = s.sample
+	cfg.Timeout = s.timeout
+}
+
+// analyzePreflight counts the names, zones and label patterns observed among the findings in scope.
+func analyzePreflight(cfg *config.Config, findings []*requests.Output) *PreflightTuning {
+	t := new(PreflightTuning)
+
+	zones := make(map[string]int)
+	labels := make(map[string]int)
+	var total, numbered, hyphenated int
+	for _, out := range findings {
+		name := strings.ToLower(strings.Trim(out.Name, "."))
+
+		domain := cfg.WhichDomain(name)
+		if domain == "" || name == domain {
+			continue
+		}
+
+		t.Names++
+		t.findings = append(t.findings, out)
+
+		parts := strings.SplitN(name, ".", 2)
+		zones[parts[1]]++
+
+		label := parts[0]
+		if label == "" || label == "*" {
+			continue
+		}
+		labels[label]++
+
+		total++
+		if strings.IndexFunc(label, unicode.IsDigit) != -1 {
+			numbered++
+		}
+		if strings.Contains(strings.Trim(label, "-"), "-") {
+			hyphenated++
+		}
+	}
+	if total > 0 {
+		t.numbered = float64(numbered) / float64(total)
+		t.hyphenated = float64(hyphenated) / float64(total)
+	}
+
+	for zone, count := range zones {
+		t.zones = append(t.zones, zone)
+		if cfg.WhichDomain(zone) != zone {
+			t.Subdomains++
+		}
+		if count > t.LargestZone {
+			t.LargestZone = count
+		}
+	}
+	sort.Slice(t.zones, func(i, j int) bool {
+		if zones[t.zones[i]] != zones[t.zones[j]] {
+			return zones[t.zones[i]] > zones[t.zones[j]]
+		}
+		return t.zones[i] < t.zones[j]
+	})
+
+	for label := range labels {
+		t.Labels = append(t.Labels, label)
+	}
+	sort.Slice(t.Labels, func(i, j int) bool {
+		if labels[t.Labels[i]] != labels[t.Labels[j]] {
+			return labels[t.Labels[i]] > labels[t.Labels[j]]
+		}
+		return t.Labels[i] < t.Labels[j]
+	})
+	if len(t.Labels) > preflightMaxLabels {
+		t.Labels = t.Labels[:preflightMaxLabels]
+	}
+	return t
+}
+
+// preflightWildcards returns the root domains, and the largest zones observed, that have DNS wildcards.
+func preflightWildcards(ctx context.Context, pool resolvers.Resolver, cfg *config.Config, zones []string) []string {
+	candidates := stringset.New(cfg.Domains()...)
+	for i, zone := range zones {
+		if i >= preflightWildcardZones {
+			break
+		}
+		candidates.Insert(zone)
+	}
+
+	var wildcards []string
+	for _, zone := range candidates.Slice() {
+		domain := cfg.WhichDomain(zone)
+		if domain == "" {
+			continue
+		}
+
+		if pool.GetWildcardType(ctx, &requests.DNSRequest{
+			Name:   "preflight." + zone,
+			Domain: domain,
+		}) != resolvers.WildcardTypeNone {
+			wildcards = append(wildcards, zone)
+		}
+	}
+
+	sort.Strings(wildcards)
+	return wildcards
+}
+
+// tune selects the settings of the active phase from the observations of the preflight. Techniques
+// disabled in the configuration remain disabled.
+func (t *PreflightTuning) tune(cfg *config.Config) {
+	wildcards := stringset.New(t.Wildcards...)
+
+	// Brute forcing is wasted on root domains answering for every name
+	for _, domain := range cfg.Domains() {
+		if !wildcards.Has(domain) {
+			t.BruteForcing = true
+			break
+		}
+	}
+
+	// Scopes with many subdomains need more discoveries before each subdomain is brute forced
+	t.Recursive = cfg.Recursive && t.BruteForcing && t.Subdomains > 0
+	switch {
+	case t.Subdomains <= 10:
+		t.MinForRecursive = 1
+	case t.Subdomains <= 100:
+		t.MinForRecursive = 2
+	default:
+		t.MinForRecursive = 3
+	}
+
+	// The alterations are generated for every name, so larger scopes receive a smaller budget
+	t.Alterations = cfg.Alterations
+	t.FlipWords = cfg.FlipWords && t.hyphenated >= preflightPatternRatio
+	t.FlipNumbers = cfg.FlipNumbers && t.numbered >= preflightPatternRatio
+	t.AddNumbers = cfg.AddNumbers && t.numbered >= preflightPatternRatio
+	switch {
+	case t.Names < 100:
+		t.EditDistance, t.MinForWordFlip = 2, 2
+	case t.Names < 1000:
+		t.EditDistance, t.MinForWordFlip = 1, 2
+	case t.Names < 5000:
+		t.EditDistance, t.MinForWordFlip = 1, 3
+	default:
+		t.EditDistance, t.MinForWordFlip = 0, 4
+	}
+
+	// Queries within wildcard zones are answered regardless, so the resolvers are spared,
+	// while large scopes without them can make use of additional concurrency
+	t.MaxDNSQueries = cfg.MaxDNSQueries
+	if len(t.Wildcards) > 0 {
+		t.MaxDNSQueries /= 2
+	} else if t.LargestZone >= 1000 || t.Names >= 5000 {
+		t.MaxDNSQueries *= 2
+	}
+	if t.MaxDNSQueries < minPreflightDNSQueries {
+		t.MaxDNSQueries = minPreflightDNSQueries
+	} else if t.MaxDNSQueries > maxPreflightDNSQueries {
+		t.MaxDNSQueries = maxPreflightDNSQueries
+	}
+}
+
+// Apply changes the configuration to the settings selected by the preflight. The observed labels
+// are tried before the configured wordlist, or the default wordlist when none was provided.
+func (t *PreflightTuning) Apply(cfg *config.Config) error {
+	cfg.BruteForcing = t.BruteForcing
+	if t.BruteForcing {
+		words := cfg.Wordlist
+		if len(words) == 0 {
+			var err error
+
+			if words, err = config.DefaultWordlist(); err != nil {
+				return err
+			}
+		}
+
+		list := append([]string(nil), t.Labels...)
+		observed := stringset.New(t.Labels...)
+		for _, word := range words {
+			if !observed.Has(word) {
+				list = append(list, word)
+			}
+		}
+		cfg.Wordlist = list
+	}
+
+	cfg.Recursive = t.Recursive
+	cfg.MinForRecursive = t.MinForRecursive
+	cfg.Alterations = t.Alterations
+	cfg.FlipWords = t.FlipWords
+	cfg.FlipNumbers = t.FlipNumbers
+	cfg.AddNumbers = t.AddNumbers
+	cfg.EditDistance = t.EditDistance
+	cfg.MinForWordFlip = t.MinForWordFlip
+	cfg.MaxDNSQueries = t.MaxDNSQueries
+	return nil
+}
+
+// String returns the tuning as the JSON recorded in the event metadata.
+func (t *PreflightTuning) String() string {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// SetPreflight provides the tuning selected by RunPreflight, so that the enumeration records it in the
+// event metadata and continues from the names discovered by the preflight.
+func (e *Enumeration) SetPreflight(t *PreflightTuning) {
+	e.preflight = t
+}
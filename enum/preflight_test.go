@@ -0,0 +1,142 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+)
+
+func preflightFindings(names ...string) []*requests.Output {
+	var findings []*requests.Output
+
+	for _, name := range names {
+		findings = append(findings, &requests.Output{
+			Name:    name,
+			Domain:  "example.com",
+			Tag:     requests.CERT,
+			Sources: []string{"Crtsh"},
+		})
+	}
+	return findings
+}
+
+func TestAnalyzePreflight(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AddDomain("example.com")
+
+	tuning := analyzePreflight(cfg, preflightFindings(
+		"www.example.com",
+		"api.example.com",
+		"api.dev.example.com",
+		"web01.dev.example.com",
+		"web02.dev.example.com",
+		"www.owasp.org",
+		"example.com",
+	))
+
+	if tuning.Names != 5 || len(tuning.findings) != 5 {
+		t.Errorf("analyzePreflight counted %d names instead of 5", tuning.Names)
+	}
+	if tuning.Subdomains != 1 || tuning.LargestZone != 3 {
+		t.Errorf("analyzePreflight found %d subdomains and the largest zone of %d names", tuning.Subdomains, tuning.LargestZone)
+	}
+	if expected := []string{"dev.example.com", "example.com"}; !reflect.DeepEqual(tuning.zones, expected) {
+		t.Errorf("analyzePreflight ordered the zones %v, expected %v", tuning.zones, expected)
+	}
+	if expected := []string{"api", "web01", "web02", "www"}; !reflect.DeepEqual(tuning.Labels, expected) {
+		t.Errorf("analyzePreflight selected the labels %v, expected %v", tuning.Labels, expected)
+	}
+	if tuning.numbered != 0.4 || tuning.hyphenated != 0 {
+		t.Errorf("analyzePreflight found the label patterns %f and %f", tuning.numbered, tuning.hyphenated)
+	}
+
+	if w := preflightWildcards(context.Background(), resolvers.NewMockResolver(), cfg, tuning.zones); len(w) != 0 {
+		t.Errorf("preflightWildcards returned %v without DNS wildcards", w)
+	}
+}
+
+func TestPreflightTune(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AddDomain("example.com")
+	cfg.Wordlist = []string{"mail", "www", "vpn"}
+
+	var names []string
+	for i := 0; i < 2000; i++ {
+		names = append(names, "host-"+strconv.Itoa(i)+".sub"+strconv.Itoa(i%200)+".example.com")
+	}
+	tuning := analyzePreflight(cfg, preflightFindings(append(names, "www.example.com")...))
+	tuning.tune(cfg)
+
+	if !tuning.BruteForcing || !tuning.Recursive || tuning.MinForRecursive != 3 {
+		t.Errorf("The brute forcing was tuned to %t, recursive %t and %d", tuning.BruteForcing, tuning.Recursive, tuning.MinForRecursive)
+	}
+	if !tuning.FlipWords || !tuning.FlipNumbers || !tuning.AddNumbers {
+		t.Errorf("The alterations for the label patterns were not selected")
+	}
+	if tuning.EditDistance != 1 || tuning.MinForWordFlip != 3 {
+		t.Errorf("The alteration budget was tuned to %d and %d", tuning.EditDistance, tuning.MinForWordFlip)
+	}
+	if tuning.MaxDNSQueries != cfg.MaxDNSQueries {
+		t.Errorf("The concurrency was tuned to %d without a reason", tuning.MaxDNSQueries)
+	}
+
+	if err := tuning.Apply(cfg); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if !cfg.BruteForcing || cfg.MinForRecursive != 3 || cfg.EditDistance != 1 {
+		t.Errorf("Apply did not change the configuration")
+	}
+	// The observed labels are tried first, without repeating the configured words
+	if l := len(cfg.Wordlist); l != preflightMaxLabels+3 || !reflect.DeepEqual(cfg.Wordlist[l-3:], []string{"mail", "www", "vpn"}) {
+		t.Errorf("Apply selected %d words ending with %v", l, cfg.Wordlist[l-3:])
+	}
+}
+
+func TestPreflightTuneWildcards(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.AddDomain("example.com")
+	cfg.Recursive = false
+	cfg.MaxDNSQueries = 1000
+
+	tuning := analyzePreflight(cfg, preflightFindings("www.example.com", "api.dev.example.com"))
+	tuning.Wildcards = []string{"example.com"}
+	tuning.tune(cfg)
+
+	if tuning.BruteForcing || tuning.Recursive {
+		t.Errorf("Brute forcing was selected for the root domain with a wildcard")
+	}
+	if tuning.EditDistance != 2 || tuning.MinForWordFlip != 2 {
+		t.Errorf("The alteration budget was tuned to %d and %d", tuning.EditDistance, tuning.MinForWordFlip)
+	}
+	if tuning.MaxDNSQueries != 500 {
+		t.Errorf("The concurrency was tuned to %d instead of 500", tuning.MaxDNSQueries)
+	}
+	if s := tuning.String(); s == "" {
+		t.Errorf("The tuning could not be recorded")
+	}
+}
+
+func TestPreflightSettings(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Monitor = true
+	cfg.BruteForcing = true
+	cfg.SampleSize = 0
+
+	saved := newPreflightSettings(cfg)
+	cfg.Monitor = false
+	cfg.BruteForcing = false
+	cfg.SampleSize = PreflightSampleSize
+
+	saved.restore(cfg)
+	if !cfg.Monitor || !cfg.BruteForcing || cfg.SampleSize != 0 {
+		t.Errorf("The settings changed by the preflight were not restored")
+	}
+}
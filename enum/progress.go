@@ -0,0 +1,76 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"time"
+
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// The interval between the progress messages published during the enumeration
+const progressInterval = 2 * time.Second
+
+// Progress is published on the requests.EnumProgressTopic periodically while the enumeration executes.
+type Progress struct {
+	// Names is the number of names resolved, or discovered in passive mode
+	Names int
+
+	// The average number of DNS queries performed per second during the last minute
+	QueriesPerSec int64
+
+	// The number of data sources that reported activity recently
+	ActiveSources int
+
+	// The names waiting to be released by the managers, the DNS requests waiting to be
+	// performed and the resolved names waiting to be stored in the graph
+	NameQueue int
+	DNSQueue  int
+	DataQueue int
+
+	Elapsed time.Duration
+
+	// ETA is a rough estimate of the time required to resolve the queued names, limited by
+	// the timeout when one was provided. It is zero when an estimate is not available.
+	ETA time.Duration
+}
+
+func (e *Enumeration) progress(start time.Time) *Progress {
+	p := &Progress{
+		Names:         e.checkpoint.resolvedCount(),
+		ActiveSources: e.activeSources(),
+		DNSQueue:      e.dnsNamesRemaining(),
+		Elapsed:       time.Since(start),
+	}
+
+	for _, mgr := range e.managers {
+		p.NameQueue += mgr.NameQueueLen()
+	}
+	if e.dataMgr != nil {
+		p.DataQueue = e.dataMgr.RequestLen()
+	}
+
+	if !e.Config.Passive {
+		p.QueriesPerSec, _ = e.dnsQueriesPerSec()
+		if p.QueriesPerSec > 0 {
+			queries := int64((p.NameQueue + p.DNSQueue) * len(InitialQueryTypes))
+			p.ETA = time.Duration(queries/p.QueriesPerSec) * time.Second
+		}
+	}
+	if e.Config.Timeout > 0 {
+		remaining := time.Duration(e.Config.Timeout)*time.Minute - p.Elapsed
+		if remaining < 0 {
+			remaining = 0
+		}
+		if p.ETA == 0 || remaining < p.ETA {
+			p.ETA = remaining
+		}
+	}
+	return p
+}
+
+func (e *Enumeration) publishProgress(start time.Time) {
+	e.Bus.Publish(requests.EnumProgressTopic, eventbus.PriorityLow, e.progress(start))
+}
@@ -0,0 +1,214 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/stringset"
+)
+
+// The name of the file within the output directory that stores the usual data source volumes
+const sourceVolumesFileName = "source_volumes.json"
+
+// Anomalies are not detected until a data source usually provides this many names for a
+// domain, since small volumes vary too much between executions
+const minAnomalyBaseline = 10
+
+// The usual number of names provided per data source and root domain
+type sourceVolumes map[string]map[string]int
+
+// sourceGuard protects the enumeration from poisoned or wildcarded third-party datasets by
+// enforcing result caps and quarantining names from data sources that suddenly provide far
+// more names than usual. Quarantined names are released once another data source provides them.
+type sourceGuard struct {
+	sync.Mutex
+	cfg         *config.Config
+	baseline    sourceVolumes
+	counts      map[string]int
+	accepted    map[string]int
+	capped      stringset.Set
+	quarantined stringset.Set
+	corroborate stringset.Set
+	held        map[string][]*requests.DNSRequest
+}
+
+func newSourceGuard(cfg *config.Config) *sourceGuard {
+	baseline, err := loadSourceVolumes(cfg)
+	if err != nil {
+		baseline = make(sourceVolumes)
+	}
+
+	return &sourceGuard{
+		cfg:         cfg,
+		baseline:    baseline,
+		counts:      make(map[string]int),
+		accepted:    make(map[string]int),
+		capped:      stringset.New(),
+		quarantined: stringset.New(),
+		corroborate: stringset.New(),
+		held:        make(map[string][]*requests.DNSRequest),
+	}
+}
+
+// Check returns true when the name provided by the data source should enter the enumeration.
+// The quarantined requests corroborated by the name are also returned.
+func (g *sourceGuard) Check(req *requests.DNSRequest) (bool, []*requests.DNSRequest) {
+	g.Lock()
+	defer g.Unlock()
+
+	key := req.Source + ":" + req.Domain
+	g.counts[key]++
+
+	if max := g.cfg.DataSourceMaxResults(req.Source); max > 0 && g.accepted[key] >= max {
+		if !g.capped.Has(key) {
+			g.capped.Insert(key)
+			g.cfg.Log.Printf("%s: Reached the maximum of %d names for %s", req.Source, max, req.Domain)
+		}
+		return false, nil
+	}
+
+	if !g.quarantined.Has(key) && g.anomalous(req.Source, req.Domain, g.counts[key]) {
+		g.quarantined.Insert(key)
+		g.cfg.Log.Printf("%s: Provided more than %d times the usual %d names for %s, so the "+
+			"remaining names are quarantined until corroborated", req.Source,
+			g.cfg.SourceAnomalyFactor, g.baseline[req.Source][req.Domain], req.Domain)
+	}
+	if g.quarantined.Has(key) && !g.corroborate.Has(req.Name) {
+		g.held[req.Name] = append(g.held[req.Name], req)
+		return false, nil
+	}
+
+	g.accepted[key]++
+	g.corroborate.Insert(req.Name)
+
+	released := g.held[req.Name]
+	delete(g.held, req.Name)
+	return true, released
+}
+
+// Corroborate records a name provided by a source outside of the guard, such as DNS
+// resolution, and returns the quarantined requests released by it.
+func (g *sourceGuard) Corroborate(name string) []*requests.DNSRequest {
+	g.Lock()
+	defer g.Unlock()
+
+	g.corroborate.Insert(name)
+	released := g.held[name]
+	delete(g.held, name)
+	return released
+}
+
+// must be called while holding the lock
+func (g *sourceGuard) anomalous(source, domain string, count int) bool {
+	factor := g.cfg.SourceAnomalyFactor
+	if factor <= 0 {
+		return false
+	}
+
+	usual := g.baseline[source][domain]
+	return usual >= minAnomalyBaseline && count > usual*factor
+}
+
+// Close logs the names that were never corroborated and saves the usual volumes.
+func (g *sourceGuard) Close() {
+	g.Lock()
+	defer g.Unlock()
+
+	remaining := make(map[string]int)
+	for _, reqs := range g.held {
+		for _, req := range reqs {
+			remaining[req.Source+" names for "+req.Domain]++
+		}
+	}
+	for desc, num := range remaining {
+		g.cfg.Log.Printf("Discarded %d quarantined %s that were never corroborated", num, desc)
+	}
+
+	// Executions in monitor mode only obtain a portion of the usual volume
+	if g.cfg.Monitor || len(g.accepted) == 0 {
+		return
+	}
+
+	for key, num := range g.accepted {
+		source, domain := splitGuardKey(key)
+		if _, found := g.baseline[source]; !found {
+			g.baseline[source] = make(map[string]int)
+		}
+		// The volume is smoothed across executions
+		if prev, found := g.baseline[source][domain]; found {
+			num = (prev + num) / 2
+		}
+		g.baseline[source][domain] = num
+	}
+
+	if err := saveSourceVolumes(g.cfg, g.baseline); err != nil {
+		g.cfg.Log.Printf("Failed to save the data source volumes: %v", err)
+	}
+}
+
+func splitGuardKey(key string) (string, string) {
+	// Domain names cannot contain the separator, but data source names could
+	for i := len(key) - 1; i >= 0; i-- {
+		if key[i] == ':' {
+			return key[:i], key[i+1:]
+		}
+	}
+	return key, ""
+}
+
+func sourceVolumesPath(cfg *config.Config) string {
+	dir := config.OutputDirectory(cfg.Dir)
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, sourceVolumesFileName)
+}
+
+func loadSourceVolumes(cfg *config.Config) (sourceVolumes, error) {
+	path := sourceVolumesPath(cfg)
+	if path == "" {
+		return nil, fmt.Errorf("Failed to obtain the output directory")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make(sourceVolumes)
+	if err := json.Unmarshal(data, &volumes); err != nil {
+		return nil, fmt.Errorf("Failed to parse the data source volumes: %v", err)
+	}
+	return volumes, nil
+}
+
+func saveSourceVolumes(cfg *config.Config, volumes sourceVolumes) error {
+	path := sourceVolumesPath(cfg)
+	if path == "" {
+		return fmt.Errorf("Failed to obtain the output directory")
+	}
+
+	data, err := json.MarshalIndent(volumes, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("Failed to write the data source volumes: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
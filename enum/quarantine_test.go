@@ -0,0 +1,102 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func guardTestConfig(t *testing.T) (*config.Config, func()) {
+	dir, err := ioutil.TempDir("", "guard")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+
+	cfg := config.NewConfig()
+	cfg.Dir = dir
+	return cfg, func() { os.RemoveAll(dir) }
+}
+
+func guardRequest(source string, i int) *requests.DNSRequest {
+	return &requests.DNSRequest{
+		Name:   "www" + strconv.Itoa(i) + ".example.com",
+		Domain: "example.com",
+		Source: source,
+	}
+}
+
+func TestSourceGuardMaxResults(t *testing.T) {
+	cfg, cleanup := guardTestConfig(t)
+	defer cleanup()
+
+	cfg.MaxSourceResults = 5
+	cfg.GetDataSourceConfig("Crtsh").MaxResults = 2
+	g := newSourceGuard(cfg)
+
+	var accepted int
+	for i := 0; i < 10; i++ {
+		if ok, _ := g.Check(guardRequest("Crtsh", i)); ok {
+			accepted++
+		}
+	}
+	if accepted != 2 {
+		t.Errorf("Accepted %d names from the data source with a cap of 2", accepted)
+	}
+
+	accepted = 0
+	for i := 0; i < 10; i++ {
+		if ok, _ := g.Check(guardRequest("AlienVault", i)); ok {
+			accepted++
+		}
+	}
+	if accepted != 5 {
+		t.Errorf("Accepted %d names from the data source with the global cap of 5", accepted)
+	}
+}
+
+func TestSourceGuardQuarantine(t *testing.T) {
+	cfg, cleanup := guardTestConfig(t)
+	defer cleanup()
+
+	cfg.SourceAnomalyFactor = 2
+	if err := saveSourceVolumes(cfg, sourceVolumes{"Crtsh": {"example.com": 10}}); err != nil {
+		t.Fatalf("Failed to save the data source volumes: %v", err)
+	}
+	g := newSourceGuard(cfg)
+
+	var accepted int
+	for i := 0; i < 30; i++ {
+		if ok, _ := g.Check(guardRequest("Crtsh", i)); ok {
+			accepted++
+		}
+	}
+	if accepted != 20 {
+		t.Fatalf("Accepted %d names before the quarantine, expected 20", accepted)
+	}
+
+	// Another data source corroborates one of the quarantined names
+	ok, released := g.Check(guardRequest("AlienVault", 25))
+	if !ok || len(released) != 1 || released[0].Source != "Crtsh" {
+		t.Errorf("The corroborated name was not released from the quarantine")
+	}
+	if released := g.Corroborate(guardRequest("", 26).Name); len(released) != 1 {
+		t.Errorf("The name resolved by the enumeration was not released from the quarantine")
+	}
+
+	g.Close()
+	volumes, err := loadSourceVolumes(cfg)
+	if err != nil {
+		t.Fatalf("Failed to load the data source volumes: %v", err)
+	}
+	// The quarantined names do not contribute to the usual volume
+	if v := volumes["Crtsh"]["example.com"]; v != 15 {
+		t.Errorf("The usual volume was %d, expected 15", v)
+	}
+}
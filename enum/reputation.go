@@ -0,0 +1,45 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OWASP/Amass/v3/datasrcs"
+	"github.com/OWASP/Amass/v3/eventbus"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// classifyAddress sends the resolved address to the IP reputation providers selected for the enumeration.
+func (e *Enumeration) classifyAddress(req *requests.AddrRequest) {
+	// The reserved address ranges are not known to the providers
+	if yes, _ := amassnet.IsReservedAddress(req.Address); yes {
+		return
+	}
+
+	for _, p := range datasrcs.ReputationProviders(e.srcs) {
+		p.AddrRequest(e.ctx, req)
+	}
+}
+
+// newReputation stores the classifications of the addresses published by the IP reputation providers.
+func (e *Enumeration) newReputation(req *requests.ReputationRequest) {
+	if req == nil || req.Address == "" || req.Classification == "" {
+		return
+	}
+
+	e.updateLastActive("enum")
+	if err := e.Graph.InsertReputation(req.Address, &requests.ReputationInfo{
+		Source:         req.Source,
+		Classification: req.Classification,
+		Score:          req.Score,
+		Categories:     req.Categories,
+		Checked:        time.Now().UTC(),
+	}); err != nil {
+		e.Bus.Publish(requests.LogTopic, eventbus.PriorityHigh,
+			fmt.Sprintf("%s failed to insert the reputation of %s: %v", e.Graph, req.Address, err))
+	}
+}
@@ -9,10 +9,15 @@ import (
 	"github.com/OWASP/Amass/v3/queue"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/resolvers"
+	"github.com/OWASP/Amass/v3/stringset"
 )
 
+// The data sources that reported activity within this window are considered active
+const activeSourceWindow = 30 * time.Second
+
 type enumStateChans struct {
 	GetLastActive chan chan time.Time
+	GetActiveSrcs chan chan int
 	UpdateLast    *queue.Queue
 	GetPerSec     chan chan *getPerSec
 	IncPerSec     *queue.Queue
@@ -36,6 +41,12 @@ func (e *Enumeration) manageEnumState(chs *enumStateChans) {
 	last := time.Now()
 	perSecFirst := time.Now()
 	perSecLast := time.Now()
+	// The last time each data source reported activity
+	srcActive := make(map[string]time.Time)
+	srcNames := stringset.New()
+	for _, src := range e.srcs {
+		srcNames.Insert(src.String())
+	}
 
 	perSecCallback := func(element interface{}) {
 		inc := element.(*incPerSec)
@@ -57,6 +68,9 @@ func (e *Enumeration) manageEnumState(chs *enumStateChans) {
 	}
 	updateLastCallback := func(element interface{}) {
 		srv := element.(string)
+		if srcNames.Has(srv) {
+			srcActive[srv] = time.Now()
+		}
 		// Only update active for core services once we run out of new FQDNs
 		if numSeqZeros >= 3 && !e.Config.Passive {
 			var found bool
@@ -80,6 +94,14 @@ func (e *Enumeration) manageEnumState(chs *enumStateChans) {
 			return
 		case get := <-chs.GetLastActive:
 			get <- last
+		case get := <-chs.GetActiveSrcs:
+			var active int
+			for _, t := range srcActive {
+				if time.Since(t) < activeSourceWindow {
+					active++
+				}
+			}
+			get <- active
 		case <-chs.UpdateLast.Signal:
 			chs.UpdateLast.Process(updateLastCallback)
 		case gsec := <-chs.GetPerSec:
@@ -113,6 +135,14 @@ func (e *Enumeration) lastActive() time.Time {
 	return <-ch
 }
 
+// activeSources returns the number of data sources that reported activity within the activeSourceWindow.
+func (e *Enumeration) activeSources() int {
+	ch := make(chan int, 2)
+
+	e.enumStateChannels.GetActiveSrcs <- ch
+	return <-ch
+}
+
 func (e *Enumeration) updateLastActive(srv string) {
 	e.enumStateChannels.UpdateLast.Append(srv)
 }
@@ -0,0 +1,102 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package enum
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/eventbus"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/net/http"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/stringset"
+	"github.com/miekg/dns"
+)
+
+// The source of the names discovered by probing web servers for virtual hosts
+const vhostSource = "Active VHost"
+
+// namesFromVirtualHosts probes the web servers at the address using Host headers built from the
+// discovered names and the vhosts wordlist. Confirmed virtual hosts that are not yet known to the
+// enumeration are resolved to the address, since they often lack DNS records.
+func (e *Enumeration) namesFromVirtualHosts(addr, domain string, names []string) {
+	// Do not send probes to the reserved address ranges
+	if yes, _ := amassnet.IsReservedAddress(addr); yes {
+		return
+	}
+
+	domains := stringset.New()
+	if domain != "" {
+		domains.Insert(domain)
+	}
+	for _, name := range names {
+		if d := e.Config.WhichDomain(name); d != "" {
+			domains.Insert(d)
+		}
+	}
+
+	served := stringset.New(names...)
+	known := stringset.New(e.Graph.EventFQDNs(e.Config.UUID.String())...)
+	for _, d := range domains.Slice() {
+		candidates := stringset.New()
+
+		for _, word := range e.Config.VHostWordlist {
+			if label := strings.Trim(strings.TrimSpace(word), "."); label != "" {
+				candidates.Insert(label + "." + d)
+			}
+		}
+		// Names already discovered could also be served by this address
+		for _, name := range known.Slice() {
+			if e.Config.WhichDomain(name) == d {
+				candidates.Insert(name)
+			}
+		}
+		candidates.Subtract(served)
+		if candidates.Len() == 0 {
+			continue
+		}
+
+		found := stringset.New()
+		for _, port := range e.Config.VHostPorts {
+			e.Bus.Publish(requests.SetActiveTopic, eventbus.PriorityCritical, vhostSource)
+
+			for _, host := range http.VirtualHosts(e.ctx, addr, port, d, candidates.Slice()) {
+				if found.Has(host) {
+					continue
+				}
+				found.Insert(host)
+
+				if known.Has(host) {
+					e.Bus.Publish(requests.LogTopic, eventbus.PriorityLow, fmt.Sprintf(
+						"%s: %s port %d also serves the virtual host %s", vhostSource, addr, port, host))
+					continue
+				}
+
+				e.newVirtualHost(host, d, addr)
+			}
+		}
+	}
+}
+
+func (e *Enumeration) newVirtualHost(name, domain, addr string) {
+	rrtype := dns.TypeA
+	if amassnet.IsIPv6(net.ParseIP(addr)) {
+		rrtype = dns.TypeAAAA
+	}
+
+	e.Bus.Publish(requests.NameResolvedTopic, eventbus.PriorityLow, &requests.DNSRequest{
+		Name:   name,
+		Domain: domain,
+		Records: []requests.DNSAnswer{{
+			Name: name,
+			Type: int(rrtype),
+			TTL:  0,
+			Data: addr,
+		}},
+		Tag:    requests.VHOST,
+		Source: vhostSource,
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package eventbus
+
+import (
+	"sync"
+	"time"
+)
+
+// Recorder keeps the messages published on the topics of an EventBus, and is
+// intended for tests that verify the events generated by Amass.
+type Recorder struct {
+	sync.Mutex
+	bus       *EventBus
+	callbacks map[string]func(...interface{})
+	messages  map[string][][]interface{}
+	signal    chan struct{}
+}
+
+// NewRecorder returns a Recorder subscribed to the topics provided on bus.
+func NewRecorder(bus *EventBus, topics ...string) *Recorder {
+	r := &Recorder{
+		bus:       bus,
+		callbacks: make(map[string]func(...interface{})),
+		messages:  make(map[string][][]interface{}),
+		signal:    make(chan struct{}, 1),
+	}
+
+	for _, topic := range topics {
+		t := topic
+		fn := func(args ...interface{}) {
+			r.record(t, args)
+		}
+
+		r.callbacks[t] = fn
+		bus.Subscribe(t, fn)
+	}
+	return r
+}
+
+func (r *Recorder) record(topic string, args []interface{}) {
+	r.Lock()
+	r.messages[topic] = append(r.messages[topic], args)
+	r.Unlock()
+
+	select {
+	case r.signal <- struct{}{}:
+	default:
+	}
+}
+
+// Messages returns the arguments of each message published on the topic, in the order received.
+func (r *Recorder) Messages(topic string) [][]interface{} {
+	r.Lock()
+	defer r.Unlock()
+
+	return append([][]interface{}(nil), r.messages[topic]...)
+}
+
+// Len returns the number of messages received on the topic.
+func (r *Recorder) Len(topic string) int {
+	r.Lock()
+	defer r.Unlock()
+
+	return len(r.messages[topic])
+}
+
+// Wait blocks until n messages have been received on the topic, and returns
+// false when the timeout expires first.
+func (r *Recorder) Wait(topic string, n int, timeout time.Duration) bool {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	for r.Len(topic) < n {
+		select {
+		case <-r.signal:
+		case <-t.C:
+			return r.Len(topic) >= n
+		}
+	}
+	return true
+}
+
+// Stop unsubscribes the Recorder from the topics of the EventBus.
+func (r *Recorder) Stop() {
+	r.Lock()
+	defer r.Unlock()
+
+	for topic, fn := range r.callbacks {
+		r.bus.Unsubscribe(topic, fn)
+	}
+	r.callbacks = make(map[string]func(...interface{}))
+}
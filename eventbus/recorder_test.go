@@ -0,0 +1,44 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package eventbus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Stop()
+
+	r := NewRecorder(bus, "first", "second")
+	time.Sleep(100 * time.Millisecond)
+
+	bus.Publish("first", PriorityHigh, "one", 1)
+	bus.Publish("first", PriorityHigh, "two", 2)
+	bus.Publish("second", PriorityLow, true)
+	bus.Publish("ignored", PriorityLow, true)
+
+	if !r.Wait("first", 2, 5*time.Second) {
+		t.Fatalf("Only %d messages were received on the first topic", r.Len("first"))
+	}
+	if !r.Wait("second", 1, 5*time.Second) {
+		t.Fatalf("The message was not received on the second topic")
+	}
+
+	msgs := r.Messages("first")
+	if len(msgs[0]) != 2 || msgs[0][0] != "one" || msgs[0][1] != 1 {
+		t.Errorf("Unexpected arguments for the first message: %v", msgs[0])
+	}
+	if r.Len("ignored") != 0 {
+		t.Errorf("Messages were recorded for a topic without a subscription")
+	}
+
+	r.Stop()
+	time.Sleep(100 * time.Millisecond)
+	bus.Publish("second", PriorityLow, false)
+	if r.Wait("second", 2, 500*time.Millisecond) {
+		t.Errorf("A message was recorded after the Recorder was stopped")
+	}
+}
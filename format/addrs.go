@@ -0,0 +1,51 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// AddressWriter writes each address of the findings once, on a line of its own and without
+// the names, so the output can be provided directly to tools such as port scanners.
+type AddressWriter struct {
+	writer io.Writer
+	seen   map[string]struct{}
+}
+
+// NewAddressWriter returns a writer for the addresses of the findings.
+func NewAddressWriter(output io.Writer) *AddressWriter {
+	return &AddressWriter{
+		writer: output,
+		seen:   make(map[string]struct{}),
+	}
+}
+
+// Write adds the addresses of the finding that have not already been written.
+func (w *AddressWriter) Write(out *requests.Output) error {
+	for _, addr := range out.Addresses {
+		if addr.Address == nil {
+			continue
+		}
+
+		ip := addr.Address.String()
+		if _, found := w.seen[ip]; found {
+			continue
+		}
+		w.seen[ip] = struct{}{}
+
+		if _, err := fmt.Fprintln(w.writer, ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Total returns the number of unique addresses written.
+func (w *AddressWriter) Total() int {
+	return len(w.seen)
+}
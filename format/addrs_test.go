@@ -0,0 +1,48 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestAddressWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewAddressWriter(&buf)
+
+	outputs := []*requests.Output{
+		{
+			Name: "www.example.com",
+			Addresses: []requests.AddressInfo{
+				{Address: net.ParseIP("192.0.2.1")},
+				{Address: net.ParseIP("2001:db8::1")},
+			},
+		},
+		{Name: "mail.example.com"},
+		{
+			Name: "api.example.com",
+			Addresses: []requests.AddressInfo{
+				{Address: net.ParseIP("192.0.2.1")},
+				{Address: net.ParseIP("192.0.2.2")},
+			},
+		},
+	}
+	for _, out := range outputs {
+		if err := w.Write(out); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	expected := "192.0.2.1\n2001:db8::1\n192.0.2.2\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("Expected the addresses:\n%s\nGot:\n%s", expected, got)
+	}
+	if w.Total() != 3 {
+		t.Errorf("Expected a total of 3 addresses, got %d", w.Total())
+	}
+}
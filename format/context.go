@@ -0,0 +1,216 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/requests"
+	"gopkg.in/yaml.v2"
+)
+
+// ContextMap provides the organizational context of the DNS names and addresses
+// described by the user, such as the team that owns the assets.
+type ContextMap struct {
+	names map[string]*requests.AssetContext
+	addrs *amassnet.PrefixIndex
+}
+
+type contextEntry struct {
+	Name        string `yaml:"name"`
+	CIDR        string `yaml:"cidr"`
+	Owner       string `yaml:"owner"`
+	Environment string `yaml:"environment"`
+	Criticality string `yaml:"criticality"`
+}
+
+// NewContextMap returns an empty ContextMap.
+func NewContextMap() *ContextMap {
+	return &ContextMap{
+		names: make(map[string]*requests.AssetContext),
+		addrs: amassnet.NewPrefixIndex(),
+	}
+}
+
+// LoadContextFile reads the context file at path, which is parsed as YAML when
+// the file has a .yaml or .yml extension, and as CSV otherwise.
+func LoadContextFile(path string) (*ContextMap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open the context file %s: %v", path, err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ReadContextYAML(f)
+	}
+	return ReadContextCSV(f)
+}
+
+// ReadContextYAML returns a ContextMap built from a YAML list of entries providing a
+// name or cidr key, along with the owner, environment and criticality keys.
+func ReadContextYAML(r io.Reader) (*ContextMap, error) {
+	var entries []contextEntry
+
+	if err := yaml.NewDecoder(r).Decode(&entries); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("Failed to parse the YAML context file: %v", err)
+	}
+
+	cm := NewContextMap()
+	for i, e := range entries {
+		asset := e.Name
+		if asset == "" {
+			asset = e.CIDR
+		}
+
+		if err := cm.Add(asset, &requests.AssetContext{
+			Owner:       e.Owner,
+			Environment: e.Environment,
+			Criticality: e.Criticality,
+		}); err != nil {
+			return nil, fmt.Errorf("Entry %d of the context file: %v", i+1, err)
+		}
+	}
+	return cm, nil
+}
+
+// ReadContextCSV returns a ContextMap built from CSV records with the asset (a name,
+// address or CIDR), owner, environment and criticality columns. The header row is optional.
+func ReadContextCSV(r io.Reader) (*ContextMap, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	cm := NewContextMap()
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed to parse the CSV context file: %v", err)
+		}
+
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "asset") {
+			continue
+		}
+
+		fields := make([]string, 4)
+		copy(fields, record)
+		if err := cm.Add(fields[0], &requests.AssetContext{
+			Owner:       strings.TrimSpace(fields[1]),
+			Environment: strings.TrimSpace(fields[2]),
+			Criticality: strings.TrimSpace(fields[3]),
+		}); err != nil {
+			return nil, fmt.Errorf("Line %d of the context file: %v", line, err)
+		}
+	}
+	return cm, nil
+}
+
+// Add provides the context for the asset, which is a CIDR, an IP address or a DNS name.
+// The context of a DNS name also applies to the subdomains of the name.
+func (cm *ContextMap) Add(asset string, ctx *requests.AssetContext) error {
+	asset = strings.ToLower(strings.TrimSpace(asset))
+	if asset == "" {
+		return fmt.Errorf("No name or CIDR was provided")
+	}
+
+	if _, ipnet, err := net.ParseCIDR(asset); err == nil {
+		cm.addrs.Insert(ipnet, ctx)
+		return nil
+	}
+	if ip := net.ParseIP(asset); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			bits = 8 * net.IPv4len
+		}
+
+		cm.addrs.Insert(&net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, ctx)
+		return nil
+	}
+
+	cm.names[strings.Trim(asset, ".")] = ctx
+	return nil
+}
+
+// Lookup returns the context for the DNS name and addresses, or nil when none was provided.
+// The most specific name takes precedence, and the addresses fill in the values it lacks.
+func (cm *ContextMap) Lookup(name string, addrs []requests.AddressInfo) *requests.AssetContext {
+	if cm == nil {
+		return nil
+	}
+
+	var result requests.AssetContext
+	var found bool
+	labels := strings.Split(strings.ToLower(strings.Trim(name, ".")), ".")
+	for i := range labels {
+		if ctx, ok := cm.names[strings.Join(labels[i:], ".")]; ok {
+			result = *ctx
+			found = true
+			break
+		}
+	}
+
+	for _, addr := range addrs {
+		if addr.Address == nil {
+			continue
+		}
+		if cidr, value := cm.addrs.Lookup(addr.Address); cidr != nil {
+			ctx := value.(*requests.AssetContext)
+
+			if result.Owner == "" {
+				result.Owner = ctx.Owner
+			}
+			if result.Environment == "" {
+				result.Environment = ctx.Environment
+			}
+			if result.Criticality == "" {
+				result.Criticality = ctx.Criticality
+			}
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return &result
+}
+
+// Annotate sets the context of the output using the name and addresses.
+func (cm *ContextMap) Annotate(out *requests.Output) {
+	if cm == nil || out == nil {
+		return
+	}
+
+	out.Context = cm.Lookup(out.Name, out.Addresses)
+}
+
+// ContextLine returns the values of the context formatted for the terminal output.
+func ContextLine(ctx *requests.AssetContext) string {
+	if ctx == nil {
+		return ""
+	}
+
+	var parts []string
+	for _, v := range []string{ctx.Owner, ctx.Environment, ctx.Criticality} {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
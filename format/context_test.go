@@ -0,0 +1,61 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestReadContextYAML(t *testing.T) {
+	cm, err := ReadContextYAML(strings.NewReader(`
+- name: example.com
+  owner: Platform Team
+  environment: prod
+- name: dev.example.com
+  environment: dev
+- cidr: 192.0.2.0/24
+  owner: Network Team
+  criticality: high
+`))
+	if err != nil {
+		t.Fatalf("ReadContextYAML failed: %v", err)
+	}
+
+	ctx := cm.Lookup("api.dev.example.com", []requests.AddressInfo{{Address: net.ParseIP("192.0.2.10")}})
+	if ctx == nil || ctx.Environment != "dev" || ctx.Owner != "Network Team" || ctx.Criticality != "high" {
+		t.Errorf("Unexpected context for the subdomain: %+v", ctx)
+	}
+	if ctx := cm.Lookup("www.example.com", nil); ctx == nil || ctx.Owner != "Platform Team" || ctx.Environment != "prod" {
+		t.Errorf("Unexpected context for the name: %+v", ctx)
+	}
+	if ctx := cm.Lookup("www.example.org", []requests.AddressInfo{{Address: net.ParseIP("198.51.100.1")}}); ctx != nil {
+		t.Errorf("Context was returned for an unknown asset: %+v", ctx)
+	}
+}
+
+func TestReadContextCSV(t *testing.T) {
+	cm, err := ReadContextCSV(strings.NewReader("asset,owner,environment,criticality\n" +
+		"# Corporate mail\n" +
+		"mail.example.com,IT,prod,high\n" +
+		"198.51.100.7,Security\n"))
+	if err != nil {
+		t.Fatalf("ReadContextCSV failed: %v", err)
+	}
+
+	out := &requests.Output{
+		Name:      "vpn.example.com",
+		Addresses: []requests.AddressInfo{{Address: net.ParseIP("198.51.100.7")}},
+	}
+	cm.Annotate(out)
+	if out.Context == nil || out.Context.Owner != "Security" || out.Context.Environment != "" {
+		t.Errorf("Unexpected context for the address: %+v", out.Context)
+	}
+	if line := ContextLine(cm.Lookup("mail.example.com", nil)); line != "[IT, prod, high]" {
+		t.Errorf("Unexpected context line: %s", line)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// CSVColumns are the columns of the rows written by the CSVWriter.
+var CSVColumns = []string{"name", "domain", "address", "cidr", "asn", "description", "tag", "sources"}
+
+// CSVWriter writes findings as comma-separated values with one row for each address of the name.
+// Names without addresses are written as a single row with the address columns left empty.
+type CSVWriter struct {
+	writer *csv.Writer
+}
+
+// NewCSVWriter writes the header row to the output and returns a writer for the findings.
+func NewCSVWriter(output io.Writer) (*CSVWriter, error) {
+	w := &CSVWriter{writer: csv.NewWriter(output)}
+
+	if err := w.writer.Write(CSVColumns); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write adds the rows for the finding.
+func (w *CSVWriter) Write(out *requests.Output) error {
+	sources := strings.Join(out.Sources, ";")
+
+	if len(out.Addresses) == 0 {
+		return w.writer.Write([]string{out.Name, out.Domain, "", "", "", "", out.Tag, sources})
+	}
+
+	for _, addr := range out.Addresses {
+		var ip, asn string
+
+		if addr.Address != nil {
+			ip = addr.Address.String()
+		}
+		if addr.ASN != 0 {
+			asn = strconv.Itoa(addr.ASN)
+		}
+
+		row := []string{out.Name, out.Domain, ip, addr.CIDRStr, asn, addr.Description, out.Tag, sources}
+		if err := w.writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes any buffered rows to the output.
+func (w *CSVWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
@@ -0,0 +1,68 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := NewCSVWriter(&buf)
+	if err != nil {
+		t.Fatalf("NewCSVWriter failed: %v", err)
+	}
+
+	outputs := []*requests.Output{
+		{
+			Name:    "www.example.com",
+			Domain:  "example.com",
+			Tag:     requests.DNS,
+			Sources: []string{"DNS", "Crtsh"},
+			Addresses: []requests.AddressInfo{
+				{Address: net.ParseIP("192.0.2.1"), CIDRStr: "192.0.2.0/24", ASN: 64512, Description: "Example, Inc"},
+				{Address: net.ParseIP("2001:db8::1"), CIDRStr: "2001:db8::/32", ASN: 64513, Description: "Example IPv6"},
+			},
+		},
+		{Name: "mail.example.com", Domain: "example.com", Tag: requests.CERT, Sources: []string{"Active Cert"}},
+	}
+	for _, out := range outputs {
+		if err := w.Write(out); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read the CSV output: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("The CSV output has %d rows, expected 4", len(records))
+	}
+	if records[0][0] != "name" || len(records[0]) != len(CSVColumns) {
+		t.Errorf("The header row is incorrect: %v", records[0])
+	}
+
+	expected := []string{"www.example.com", "example.com", "192.0.2.1", "192.0.2.0/24", "64512", "Example, Inc", "dns", "DNS;Crtsh"}
+	for i, v := range expected {
+		if records[1][i] != v {
+			t.Errorf("Column %s of the first row is %q, expected %q", CSVColumns[i], records[1][i], v)
+		}
+	}
+	if records[2][2] != "2001:db8::1" || records[2][4] != "64513" {
+		t.Errorf("The second address row is incorrect: %v", records[2])
+	}
+	if records[3][0] != "mail.example.com" || records[3][2] != "" || records[3][7] != "Active Cert" {
+		t.Errorf("The row without addresses is incorrect: %v", records[3])
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"sort"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/stringset"
+)
+
+// OutputDiff contains the differences between the findings of two enumerations.
+type OutputDiff struct {
+	Added     []*requests.Output `json:"added"`
+	Removed   []*requests.Output `json:"removed"`
+	Changed   []*AddressChange   `json:"changed"`
+	Netblocks []string           `json:"new_netblocks"`
+}
+
+// AddressChange describes a name discovered by both enumerations that resolved to different addresses.
+type AddressChange struct {
+	Name    string   `json:"name"`
+	Domain  string   `json:"domain"`
+	Older   []string `json:"older_addresses"`
+	Newer   []string `json:"newer_addresses"`
+	Added   []string `json:"added_addresses"`
+	Removed []string `json:"removed_addresses"`
+}
+
+// Empty returns true when the enumerations did not differ.
+func (d *OutputDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && len(d.Netblocks) == 0
+}
+
+// DiffOutput compares the findings of an older and a newer enumeration. The names, address changes and
+// netblocks are each sorted, and the netblocks are those containing addresses only in the newer findings.
+func DiffOutput(older, newer []*requests.Output) *OutputDiff {
+	diff := &OutputDiff{
+		Added:     []*requests.Output{},
+		Removed:   []*requests.Output{},
+		Changed:   []*AddressChange{},
+		Netblocks: []string{},
+	}
+
+	oldmap := make(map[string]*requests.Output)
+	for _, o := range older {
+		oldmap[o.Name] = o
+	}
+	newmap := make(map[string]*requests.Output)
+	for _, o := range newer {
+		newmap[o.Name] = o
+	}
+
+	for name, o := range newmap {
+		prev, found := oldmap[name]
+		if !found {
+			diff.Added = append(diff.Added, o)
+			continue
+		}
+
+		oldaddrs := outputAddresses(prev)
+		newaddrs := outputAddresses(o)
+		added := stringset.New(newaddrs...)
+		added.Subtract(stringset.New(oldaddrs...))
+		removed := stringset.New(oldaddrs...)
+		removed.Subtract(stringset.New(newaddrs...))
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		a := added.Slice()
+		sort.Strings(a)
+		r := removed.Slice()
+		sort.Strings(r)
+		diff.Changed = append(diff.Changed, &AddressChange{
+			Name:    name,
+			Domain:  o.Domain,
+			Older:   oldaddrs,
+			Newer:   newaddrs,
+			Added:   a,
+			Removed: r,
+		})
+	}
+
+	for name, o := range oldmap {
+		if _, found := newmap[name]; !found {
+			diff.Removed = append(diff.Removed, o)
+		}
+	}
+
+	oldnets := stringset.New()
+	for _, o := range older {
+		for _, a := range o.Addresses {
+			oldnets.Insert(a.CIDRStr)
+		}
+	}
+	newnets := stringset.New()
+	for _, o := range newer {
+		for _, a := range o.Addresses {
+			if a.CIDRStr != "" && !oldnets.Has(a.CIDRStr) {
+				newnets.Insert(a.CIDRStr)
+			}
+		}
+	}
+	diff.Netblocks = append(diff.Netblocks, newnets.Slice()...)
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Name < diff.Added[j].Name })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Name < diff.Removed[j].Name })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Name < diff.Changed[j].Name })
+	sort.Strings(diff.Netblocks)
+	return diff
+}
+
+func outputAddresses(out *requests.Output) []string {
+	set := stringset.New()
+
+	for _, a := range out.Addresses {
+		if a.Address != nil {
+			set.Insert(a.Address.String())
+		}
+	}
+
+	addrs := set.Slice()
+	sort.Strings(addrs)
+	return addrs
+}
@@ -0,0 +1,90 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func diffTestOutput(name string, addrs ...string) *requests.Output {
+	out := &requests.Output{Name: name, Domain: "example.com"}
+
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		_, cidr, _ := net.ParseCIDR(ip.Mask(net.CIDRMask(24, 32)).String() + "/24")
+
+		out.Addresses = append(out.Addresses, requests.AddressInfo{
+			Address:  ip,
+			Netblock: cidr,
+			CIDRStr:  cidr.String(),
+		})
+	}
+	return out
+}
+
+func TestDiffOutput(t *testing.T) {
+	older := []*requests.Output{
+		diffTestOutput("www.example.com", "192.0.2.1"),
+		diffTestOutput("mail.example.com", "192.0.2.2"),
+		diffTestOutput("old.example.com", "192.0.2.3"),
+		diffTestOutput("same.example.com", "192.0.2.4", "192.0.2.5"),
+	}
+	newer := []*requests.Output{
+		diffTestOutput("www.example.com", "198.51.100.1"),
+		diffTestOutput("mail.example.com", "192.0.2.2", "192.0.2.6"),
+		diffTestOutput("same.example.com", "192.0.2.5", "192.0.2.4"),
+		diffTestOutput("new.example.com", "203.0.113.7"),
+		diffTestOutput("api.example.com"),
+	}
+
+	diff := DiffOutput(older, newer)
+	if diff.Empty() {
+		t.Fatalf("DiffOutput did not find any differences")
+	}
+
+	var added []string
+	for _, o := range diff.Added {
+		added = append(added, o.Name)
+	}
+	if expected := []string{"api.example.com", "new.example.com"}; !reflect.DeepEqual(added, expected) {
+		t.Errorf("DiffOutput added %v, expected %v", added, expected)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Name != "old.example.com" {
+		t.Errorf("DiffOutput removed %v, expected old.example.com", diff.Removed)
+	}
+
+	expected := []*AddressChange{
+		{
+			Name:    "mail.example.com",
+			Domain:  "example.com",
+			Older:   []string{"192.0.2.2"},
+			Newer:   []string{"192.0.2.2", "192.0.2.6"},
+			Added:   []string{"192.0.2.6"},
+			Removed: []string{},
+		},
+		{
+			Name:    "www.example.com",
+			Domain:  "example.com",
+			Older:   []string{"192.0.2.1"},
+			Newer:   []string{"198.51.100.1"},
+			Added:   []string{"198.51.100.1"},
+			Removed: []string{"192.0.2.1"},
+		},
+	}
+	if !reflect.DeepEqual(diff.Changed, expected) {
+		t.Errorf("DiffOutput changed %+v, expected %+v", diff.Changed, expected)
+	}
+
+	if nets := []string{"198.51.100.0/24", "203.0.113.0/24"}; !reflect.DeepEqual(diff.Netblocks, nets) {
+		t.Errorf("DiffOutput found the new netblocks %v, expected %v", diff.Netblocks, nets)
+	}
+
+	if d := DiffOutput(older, older); !d.Empty() {
+		t.Errorf("DiffOutput found differences between the same findings: %+v", d)
+	}
+}
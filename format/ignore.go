@@ -0,0 +1,131 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/stringset"
+)
+
+// IgnoreList identifies the assets that should not generate change notifications.
+type IgnoreList struct {
+	names   stringset.Set
+	regexes []*regexp.Regexp
+	addrs   []net.IP
+	cidrs   []*net.IPNet
+}
+
+// NewIgnoreList returns an IgnoreList built from the entries provided. Each entry is a DNS name,
+// a regular expression enclosed in slashes (e.g. /^cdn[0-9]+\./), an IP address or a CIDR.
+func NewIgnoreList(entries ...string) (*IgnoreList, error) {
+	il := &IgnoreList{names: stringset.New()}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if _, ipnet, err := net.ParseCIDR(entry); err == nil {
+			il.cidrs = append(il.cidrs, ipnet)
+		} else if ip := net.ParseIP(entry); ip != nil {
+			il.addrs = append(il.addrs, ip)
+		} else if len(entry) > 2 && strings.HasPrefix(entry, "/") && strings.HasSuffix(entry, "/") {
+			re, err := regexp.Compile(entry[1 : len(entry)-1])
+			if err != nil {
+				return nil, fmt.Errorf("Failed to compile the ignore expression %s: %v", entry, err)
+			}
+
+			il.regexes = append(il.regexes, re)
+		} else {
+			il.names.Insert(strings.Trim(entry, "."))
+		}
+	}
+
+	return il, nil
+}
+
+// ReadIgnoreList returns an IgnoreList built from the lines read from r.
+// Blank lines and lines starting with a '#' are skipped.
+func ReadIgnoreList(r io.Reader) (*IgnoreList, error) {
+	var entries []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line != "" && !strings.HasPrefix(line, "#") {
+			entries = append(entries, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to read the ignore list: %v", err)
+	}
+
+	return NewIgnoreList(entries...)
+}
+
+// Ignored returns true when the name of the output matches the list, or when
+// all the addresses of the output are within the addresses and CIDRs of the list.
+func (il *IgnoreList) Ignored(out *requests.Output) bool {
+	if il == nil || out == nil {
+		return false
+	}
+
+	name := strings.ToLower(out.Name)
+	if il.names.Has(name) {
+		return true
+	}
+	for _, re := range il.regexes {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+
+	if len(out.Addresses) == 0 || (len(il.addrs) == 0 && len(il.cidrs) == 0) {
+		return false
+	}
+	for _, addr := range out.Addresses {
+		if !il.addressIgnored(addr.Address) {
+			return false
+		}
+	}
+	return true
+}
+
+func (il *IgnoreList) addressIgnored(ip net.IP) bool {
+	for _, addr := range il.addrs {
+		if addr.Equal(ip) {
+			return true
+		}
+	}
+	for _, ipnet := range il.cidrs {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddNames includes the names provided in the list, and returns the list.
+// A new IgnoreList is created when the receiver is nil.
+func (il *IgnoreList) AddNames(names ...string) *IgnoreList {
+	if il == nil {
+		il = &IgnoreList{names: stringset.New()}
+	}
+
+	for _, name := range names {
+		if name = strings.Trim(strings.TrimSpace(name), "."); name != "" {
+			il.names.Insert(name)
+		}
+	}
+	return il
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestIgnoreList(t *testing.T) {
+	il, err := NewIgnoreList("Dynamic.example.com", `/^cdn[0-9]+\.example\.com$/`, "192.0.2.0/24", "198.51.100.7")
+	if err != nil {
+		t.Fatalf("NewIgnoreList failed: %v", err)
+	}
+
+	addrs := func(ips ...string) []requests.AddressInfo {
+		var infos []requests.AddressInfo
+		for _, ip := range ips {
+			infos = append(infos, requests.AddressInfo{Address: net.ParseIP(ip)})
+		}
+		return infos
+	}
+
+	tests := []struct {
+		Out      *requests.Output
+		Expected bool
+	}{
+		{&requests.Output{Name: "dynamic.example.com", Addresses: addrs("203.0.113.1")}, true},
+		{&requests.Output{Name: "cdn12.example.com"}, true},
+		{&requests.Output{Name: "cdn.example.com"}, false},
+		{&requests.Output{Name: "www.example.com", Addresses: addrs("192.0.2.10", "198.51.100.7")}, true},
+		{&requests.Output{Name: "www.example.com", Addresses: addrs("192.0.2.10", "203.0.113.1")}, false},
+		{&requests.Output{Name: "www.example.com"}, false},
+	}
+
+	for _, test := range tests {
+		if got := il.Ignored(test.Out); got != test.Expected {
+			t.Errorf("%s with %v was ignored: %t, expected: %t", test.Out.Name, test.Out.Addresses, got, test.Expected)
+		}
+	}
+
+	if _, err := NewIgnoreList("/[/"); err == nil {
+		t.Errorf("NewIgnoreList accepted an invalid regular expression")
+	}
+}
+
+func TestReadIgnoreList(t *testing.T) {
+	il, err := ReadIgnoreList(strings.NewReader("# Rotating CDN hosts\n\n/^edge\\S+\\.example\\.com$/\n"))
+	if err != nil {
+		t.Fatalf("ReadIgnoreList failed: %v", err)
+	}
+
+	if !il.Ignored(&requests.Output{Name: "edge-7.example.com"}) {
+		t.Errorf("The regular expression read from the list was not applied")
+	}
+	if il.Ignored(&requests.Output{Name: "# rotating cdn hosts"}) {
+		t.Errorf("The comment was included in the list")
+	}
+}
+
+func TestIgnoreListAddNames(t *testing.T) {
+	var il *IgnoreList
+
+	il = il.AddNames("www.example.com.", " ")
+	if !il.Ignored(&requests.Output{Name: "WWW.example.com"}) {
+		t.Errorf("The name added to the nil list was not ignored")
+	}
+	if il.Ignored(&requests.Output{Name: "example.com"}) {
+		t.Errorf("A name that was not added was ignored")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+const (
+	jsonSyncCount    = 100
+	jsonSyncInterval = 5 * time.Second
+)
+
+// JSONLinesWriter streams enumeration findings to a file as line-delimited JSON (NDJSON).
+// Each finding is written as a complete line as soon as it is received, so the file
+// contains every finding written before the process exited, even when it was killed.
+type JSONLinesWriter struct {
+	sync.Mutex
+	file     *os.File
+	stream   bool
+	unsynced int
+	lastSync time.Time
+}
+
+// NewJSONLinesWriter truncates or creates the file at path and returns a writer for it.
+func NewJSONLinesWriter(path string) (*JSONLinesWriter, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONLinesWriter{
+		file:     f,
+		lastSync: time.Now(),
+	}, nil
+}
+
+// NewJSONLinesStream returns a writer for a file that is already open, such as os.Stdout, so the findings
+// can be consumed by other programs as they are received. The stream is not synced or closed by the writer.
+func NewJSONLinesStream(f *os.File) *JSONLinesWriter {
+	return &JSONLinesWriter{
+		file:     f,
+		stream:   true,
+		lastSync: time.Now(),
+	}
+}
+
+// Write appends the finding to the file as a single line of JSON.
+func (w *JSONLinesWriter) Write(out *requests.Output) error {
+	return w.WriteRecord(out)
+}
+
+// WriteRecord appends any value that can be marshaled to the file as a single line of JSON,
+// so the writer can stream records other than the enumeration findings.
+func (w *JSONLinesWriter) WriteRecord(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	// A single write for the entire line prevents findings from being interleaved
+	if _, err := w.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	w.unsynced++
+	if w.unsynced >= jsonSyncCount || time.Since(w.lastSync) >= jsonSyncInterval {
+		return w.sync()
+	}
+	return nil
+}
+
+// Sync commits the findings written so far to stable storage.
+func (w *JSONLinesWriter) Sync() error {
+	w.Lock()
+	defer w.Unlock()
+
+	return w.sync()
+}
+
+// must be called while holding the lock
+func (w *JSONLinesWriter) sync() error {
+	w.unsynced = 0
+	w.lastSync = time.Now()
+	if w.stream {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// Close syncs and closes the file.
+func (w *JSONLinesWriter) Close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	w.sync()
+	if w.stream {
+		return nil
+	}
+	return w.file.Close()
+}
+
+// ReadJSONLines returns the findings read from line-delimited JSON. A final line left
+// incomplete by an abnormal exit is ignored, while malformed lines elsewhere cause an error.
+func ReadJSONLines(r io.Reader) ([]*requests.Output, error) {
+	var results []*requests.Output
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		complete := err == nil
+		if err != nil && err != io.EOF {
+			return results, err
+		}
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var out requests.Output
+
+			if e := json.Unmarshal(line, &out); e == nil {
+				results = append(results, &out)
+			} else if complete {
+				return results, e
+			}
+		}
+
+		if !complete {
+			break
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestJSONLinesWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jsonlines")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "amass.json")
+	w, err := NewJSONLinesWriter(path)
+	if err != nil {
+		t.Fatalf("NewJSONLinesWriter failed: %v", err)
+	}
+
+	names := []string{"www.example.com", "mail.example.com"}
+	for _, name := range names {
+		if err := w.Write(&requests.Output{Name: name, Domain: "example.com"}); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	// The findings must be readable before the writer is closed
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open the JSON file: %v", err)
+	}
+	results, err := ReadJSONLines(f)
+	f.Close()
+	if err != nil || len(results) != len(names) {
+		t.Fatalf("ReadJSONLines returned %d findings and error %v", len(results), err)
+	}
+	for i, out := range results {
+		if out.Name != names[i] {
+			t.Errorf("Finding %d has the name %s, expected %s", i, out.Name, names[i])
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+}
+
+func TestJSONLinesStream(t *testing.T) {
+	r, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create the pipe: %v", err)
+	}
+	defer r.Close()
+
+	w := NewJSONLinesStream(pw)
+	for i := 0; i < jsonSyncCount+1; i++ {
+		if err := w.Write(&requests.Output{Name: "www.example.com", Domain: "example.com"}); err != nil {
+			t.Fatalf("Write to the stream failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Close of the stream failed: %v", err)
+	}
+	// The stream is left open for other output
+	if _, err := pw.Write([]byte("{\"name\":\"mail.example.com\"}\n")); err != nil {
+		t.Errorf("The stream was closed by the writer: %v", err)
+	}
+	pw.Close()
+
+	results, err := ReadJSONLines(r)
+	if err != nil || len(results) != jsonSyncCount+2 {
+		t.Fatalf("ReadJSONLines returned %d findings from the stream and error %v", len(results), err)
+	}
+	if results[len(results)-1].Name != "mail.example.com" {
+		t.Errorf("The last finding has the name %s", results[len(results)-1].Name)
+	}
+}
+
+func TestJSONLinesWriterRecord(t *testing.T) {
+	r, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create the pipe: %v", err)
+	}
+	defer r.Close()
+
+	w := NewJSONLinesStream(pw)
+	record := struct {
+		Type string `json:"type"`
+		ASN  int    `json:"asn"`
+	}{Type: "asn", ASN: 64496}
+	if err := w.WriteRecord(record); err != nil {
+		t.Fatalf("WriteRecord failed: %v", err)
+	}
+	w.Close()
+	pw.Close()
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read the stream: %v", err)
+	}
+	if got := string(data); got != "{\"type\":\"asn\",\"asn\":64496}\n" {
+		t.Errorf("WriteRecord wrote %q", got)
+	}
+}
+
+func TestReadJSONLinesTruncated(t *testing.T) {
+	data := `{"name":"www.example.com","domain":"example.com"}
+{"name":"mail.example.com","domain":"example.com"}
+{"name":"ftp.exam`
+
+	results, err := ReadJSONLines(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadJSONLines failed on the incomplete final line: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("ReadJSONLines returned %d findings, expected 2", len(results))
+	}
+
+	if _, err := ReadJSONLines(strings.NewReader("{bad}\n" + data)); err == nil {
+		t.Errorf("ReadJSONLines did not return an error for a malformed line")
+	}
+}
@@ -6,6 +6,7 @@ package format
 import (
 	"fmt"
 	"net"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -15,6 +16,10 @@ import (
 // ParseStrings implements the flag.Value interface.
 type ParseStrings []string
 
+// ParseTerms implements the flag.Value interface, and keeps commas within each value,
+// since search terms such as organization names can contain them.
+type ParseTerms []string
+
 // ParseInts implements the flag.Value interface.
 type ParseInts []int
 
@@ -24,6 +29,9 @@ type ParseIPs []net.IP
 // ParseCIDRs implements the flag.Value interface.
 type ParseCIDRs []*net.IPNet
 
+// ParseRegexps implements the flag.Value interface.
+type ParseRegexps []*regexp.Regexp
+
 func (p *ParseStrings) String() string {
 	if p == nil {
 		return ""
@@ -44,6 +52,23 @@ func (p *ParseStrings) Set(s string) error {
 	return nil
 }
 
+func (p *ParseTerms) String() string {
+	if p == nil {
+		return ""
+	}
+	return strings.Join(*p, ",")
+}
+
+// Set implements the flag.Value interface.
+func (p *ParseTerms) Set(s string) error {
+	if s = strings.TrimSpace(s); s == "" {
+		return fmt.Errorf("String parsing failed")
+	}
+
+	*p = append(*p, s)
+	return nil
+}
+
 func (p *ParseInts) String() string {
 	if p == nil {
 		return ""
@@ -169,3 +194,41 @@ func (p *ParseCIDRs) Set(s string) error {
 	}
 	return nil
 }
+
+func (p *ParseRegexps) String() string {
+	if p == nil {
+		return ""
+	}
+
+	var exprs []string
+	for _, re := range *p {
+		exprs = append(exprs, re.String())
+	}
+	return strings.Join(exprs, ",")
+}
+
+// Set implements the flag.Value interface. The value is not split on commas,
+// since they are part of the regular expression syntax.
+func (p *ParseRegexps) Set(s string) error {
+	if s == "" {
+		return fmt.Errorf("Regular expression parsing failed")
+	}
+
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid regular expression: %v", s, err)
+	}
+
+	*p = append(*p, re)
+	return nil
+}
+
+// AnyMatch returns true when one of the regular expressions matches the string.
+func (p ParseRegexps) AnyMatch(s string) bool {
+	for _, re := range p {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,476 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// QueryFields are the fields of the findings that queries can compare.
+var QueryFields = []string{"name", "domain", "tag", "source", "addr", "cidr", "asn", "desc", "new", "verified", "reputation", "score"}
+
+// Alternative names accepted for the query fields
+var queryFieldAliases = map[string]string{
+	"ip":       "addr",
+	"address":  "addr",
+	"netblock": "cidr",
+	"src":      "source",
+	"as":       "asn",
+	"rep":      "reputation",
+}
+
+// Query selects the findings that satisfy a boolean expression of comparisons, such as
+// name ~ "^vpn" AND (asn = 13335 OR cidr = 104.16.0.0/12) AND NOT source = Crtsh.
+type Query struct {
+	root   queryNode
+	fields map[string]struct{}
+}
+
+type queryNode interface {
+	match(out *requests.Output) bool
+}
+
+type queryAnd struct{ left, right queryNode }
+
+type queryOr struct{ left, right queryNode }
+
+type queryNot struct{ node queryNode }
+
+// queryCondition compares a field of the findings with a value. Fields holding several values,
+// such as the addresses and data sources, satisfy the condition when any of the values does,
+// while the != and !~ operators are satisfied when none of the values are equal or match.
+type queryCondition struct {
+	field  string
+	negate bool
+	test   func(value string) bool
+}
+
+func (n *queryAnd) match(out *requests.Output) bool {
+	return n.left.match(out) && n.right.match(out)
+}
+
+func (n *queryOr) match(out *requests.Output) bool {
+	return n.left.match(out) || n.right.match(out)
+}
+
+func (n *queryNot) match(out *requests.Output) bool {
+	return !n.node.match(out)
+}
+
+func (c *queryCondition) match(out *requests.Output) bool {
+	var found bool
+
+	for _, value := range queryValues(c.field, out) {
+		if c.test(value) {
+			found = true
+			break
+		}
+	}
+	return found != c.negate
+}
+
+func queryValues(field string, out *requests.Output) []string {
+	var values []string
+
+	switch field {
+	case "name":
+		values = []string{out.Name}
+	case "domain":
+		values = []string{out.Domain}
+	case "tag":
+		values = []string{out.Tag}
+	case "source":
+		values = out.Sources
+	case "new":
+		values = []string{strconv.FormatBool(out.New)}
+	case "verified":
+		values = []string{strconv.FormatBool(out.Verified)}
+	case "addr", "cidr", "asn", "desc":
+		for _, a := range out.Addresses {
+			switch field {
+			case "addr":
+				values = append(values, a.Address.String())
+			case "cidr":
+				values = append(values, a.CIDRStr)
+			case "asn":
+				values = append(values, strconv.Itoa(a.ASN))
+			case "desc":
+				values = append(values, a.Description)
+			}
+		}
+	case "reputation", "score":
+		for _, a := range out.Addresses {
+			for _, r := range a.Reputation {
+				if field == "reputation" {
+					values = append(values, r.Classification)
+				} else {
+					values = append(values, strconv.Itoa(r.Score))
+				}
+			}
+		}
+	}
+	return values
+}
+
+// ParseQuery returns the Query for the expression. The comparisons have the form 'field operator value'
+// and are combined with the AND, OR and NOT keywords and parentheses. The operators are = and != for
+// equality, ~ and !~ for regular expression matches, and <, <=, > and >= for the ASN and the reputation
+// score. The reputation is the classification of the addresses by the IP reputation providers. The equality of
+// addresses and netblocks is also satisfied by a CIDR that contains the address or an address within the
+// netblock. Values containing spaces or operator characters must be enclosed in double quotes.
+func ParseQuery(expr string) (*Query, error) {
+	tokens, err := lexQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("The query is empty")
+	}
+
+	p := &queryParser{tokens: tokens, fields: make(map[string]struct{})}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t != nil {
+		return nil, fmt.Errorf("Unexpected %q at position %d of the query", t.text, t.pos+1)
+	}
+
+	return &Query{root: root, fields: p.fields}, nil
+}
+
+// Match returns true when the finding satisfies the query. Every finding matches a nil Query.
+func (q *Query) Match(out *requests.Output) bool {
+	if q == nil {
+		return true
+	}
+	return q.root.match(out)
+}
+
+// UsesASNInfo returns true when the query compares the netblocks or autonomous systems of the addresses.
+func (q *Query) UsesASNInfo() bool {
+	if q == nil {
+		return false
+	}
+
+	for _, field := range []string{"cidr", "asn", "desc"} {
+		if _, found := q.fields[field]; found {
+			return true
+		}
+	}
+	return false
+}
+
+type queryTokenType int
+
+const (
+	queryWord queryTokenType = iota
+	queryString
+	queryOperator
+	queryOpen
+	queryClose
+)
+
+type queryToken struct {
+	kind queryTokenType
+	text string
+	pos  int
+}
+
+const queryOperatorChars = "=!~<>"
+
+func lexQuery(expr string) ([]*queryToken, error) {
+	var tokens []*queryToken
+
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(' || c == ')':
+			kind := queryOpen
+			if c == ')' {
+				kind = queryClose
+			}
+
+			tokens = append(tokens, &queryToken{kind: kind, text: string(c), pos: i})
+			i++
+		case c == '"':
+			start := i
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("The quoted value at position %d of the query is not terminated", start+1)
+			}
+			i++
+
+			value, err := strconv.Unquote(string(runes[start:i]))
+			if err != nil {
+				return nil, fmt.Errorf("The quoted value at position %d of the query is invalid: %v", start+1, err)
+			}
+			tokens = append(tokens, &queryToken{kind: queryString, text: value, pos: start})
+		case strings.ContainsRune(queryOperatorChars, c):
+			start := i
+			for i < len(runes) && strings.ContainsRune(queryOperatorChars, runes[i]) {
+				i++
+			}
+			tokens = append(tokens, &queryToken{kind: queryOperator, text: string(runes[start:i]), pos: start})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()"`+queryOperatorChars, runes[i]) {
+				i++
+			}
+			tokens = append(tokens, &queryToken{kind: queryWord, text: string(runes[start:i]), pos: start})
+		}
+	}
+
+	return tokens, nil
+}
+
+type queryParser struct {
+	tokens []*queryToken
+	pos    int
+	fields map[string]struct{}
+}
+
+func (p *queryParser) peek() *queryToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *queryParser) next() *queryToken {
+	t := p.peek()
+	if t != nil {
+		p.pos++
+	}
+	return t
+}
+
+func (p *queryParser) keyword(word string) bool {
+	if t := p.peek(); t != nil && t.kind == queryWord && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &queryOr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &queryAnd{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	if p.keyword("not") {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &queryNot{node: node}, nil
+	}
+
+	t := p.next()
+	if t == nil {
+		return nil, fmt.Errorf("The query ended while a comparison was expected")
+	}
+
+	if t.kind == queryOpen {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if c := p.next(); c == nil || c.kind != queryClose {
+			return nil, fmt.Errorf("The parenthesis at position %d of the query is not closed", t.pos+1)
+		}
+		return node, nil
+	}
+	if t.kind != queryWord {
+		return nil, fmt.Errorf("Unexpected %q at position %d of the query", t.text, t.pos+1)
+	}
+
+	field := strings.ToLower(t.text)
+	if alias, found := queryFieldAliases[field]; found {
+		field = alias
+	}
+	if !validQueryField(field) {
+		return nil, fmt.Errorf("The query field %s is not one of %s", t.text, strings.Join(QueryFields, ", "))
+	}
+
+	op := p.next()
+	if op == nil || op.kind != queryOperator {
+		return nil, fmt.Errorf("An operator was expected after the field %s of the query", t.text)
+	}
+
+	value := p.next()
+	if value == nil || (value.kind != queryWord && value.kind != queryString) {
+		return nil, fmt.Errorf("A value was expected after the operator at position %d of the query", op.pos+1)
+	}
+
+	cond, err := newQueryCondition(field, op.text, value.text)
+	if err != nil {
+		return nil, err
+	}
+
+	p.fields[field] = struct{}{}
+	return cond, nil
+}
+
+func validQueryField(field string) bool {
+	for _, f := range QueryFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func newQueryCondition(field, op, value string) (*queryCondition, error) {
+	cond := &queryCondition{field: field}
+
+	switch op {
+	case "!=", "!~":
+		cond.negate = true
+		op = op[1:]
+		if op == "=" {
+			op = "=="
+		}
+	case "=":
+		op = "=="
+	}
+
+	var err error
+	switch op {
+	case "~":
+		var re *regexp.Regexp
+
+		if re, err = regexp.Compile(value); err != nil {
+			return nil, fmt.Errorf("Failed to compile the query expression %s: %v", value, err)
+		}
+		cond.test = re.MatchString
+	case "==":
+		cond.test, err = queryEquality(field, value)
+	case "<", "<=", ">", ">=":
+		cond.test, err = queryOrdering(field, op, value)
+	default:
+		err = fmt.Errorf("The query operator %s is not supported", op)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cond, nil
+}
+
+func queryEquality(field, value string) (func(string) bool, error) {
+	switch field {
+	case "addr", "cidr":
+		if ip := net.ParseIP(value); ip != nil {
+			return func(v string) bool {
+				if _, ipnet, err := net.ParseCIDR(v); err == nil {
+					return ipnet.Contains(ip)
+				}
+				return ip.Equal(net.ParseIP(v))
+			}, nil
+		}
+		if _, ipnet, err := net.ParseCIDR(value); err == nil {
+			return func(v string) bool {
+				if ip := net.ParseIP(v); ip != nil {
+					return ipnet.Contains(ip)
+				}
+				_, other, err := net.ParseCIDR(v)
+				return err == nil && other.String() == ipnet.String()
+			}, nil
+		}
+		return nil, fmt.Errorf("The query value %s is not an IP address or CIDR", value)
+	case "asn", "score":
+		return queryOrdering(field, "==", value)
+	case "new", "verified":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("The query value %s is not true or false", value)
+		}
+
+		expected := strconv.FormatBool(b)
+		return func(v string) bool { return v == expected }, nil
+	}
+
+	return func(v string) bool { return strings.EqualFold(v, value) }, nil
+}
+
+func queryOrdering(field, op, value string) (func(string) bool, error) {
+	var num int
+	var err error
+	switch field {
+	case "asn":
+		if num, err = strconv.Atoi(strings.TrimPrefix(strings.ToUpper(value), "AS")); err != nil {
+			return nil, fmt.Errorf("The query value %s is not an ASN", value)
+		}
+	case "score":
+		if num, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("The query value %s is not a reputation score", value)
+		}
+	default:
+		return nil, fmt.Errorf("The query operator %s can only be used with the asn and score fields", op)
+	}
+
+	return func(v string) bool {
+		n, err := strconv.Atoi(v)
+		// Addresses without AS information have the ASN zero
+		if err != nil || (field == "asn" && n == 0) {
+			return false
+		}
+
+		switch op {
+		case "<":
+			return n < num
+		case "<=":
+			return n <= num
+		case ">":
+			return n > num
+		case ">=":
+			return n >= num
+		}
+		return n == num
+	}, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"net"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestQuery(t *testing.T) {
+	vpn := &requests.Output{
+		Name:    "vpn.owasp.org",
+		Domain:  "owasp.org",
+		Tag:     "cert",
+		Sources: []string{"Crtsh", "DNS"},
+		Addresses: []requests.AddressInfo{
+			{Address: net.ParseIP("104.16.0.1"), CIDRStr: "104.16.0.0/12", ASN: 13335, Description: "CLOUDFLARENET"},
+			{Address: net.ParseIP("192.0.2.1"), CIDRStr: "192.0.2.0/24", ASN: 64512, Description: "TEST"},
+		},
+	}
+	www := &requests.Output{
+		Name:    "www.owasp.org",
+		Domain:  "owasp.org",
+		Tag:     "dns",
+		Sources: []string{"DNS"},
+		New:     true,
+		Addresses: []requests.AddressInfo{{
+			Address: net.ParseIP("198.51.100.7"),
+			Reputation: []*requests.ReputationInfo{
+				{Source: "AbuseIPDB", Classification: requests.ReputationMalicious, Score: 90},
+				{Source: "GreyNoise", Classification: requests.ReputationUnknown},
+			},
+		}},
+	}
+
+	tests := []struct {
+		Expr string
+		VPN  bool
+		WWW  bool
+	}{
+		{`name ~ "^vpn" AND asn = 13335`, true, false},
+		{`name ~ "^vpn" and asn = AS64513`, false, false},
+		{`domain = OWASP.org`, true, true},
+		{`source = crtsh`, true, false},
+		{`source != crtsh`, false, true},
+		{`NOT source = crtsh`, false, true},
+		{`ip = 104.16.0.0/12`, true, false},
+		{`addr = 198.51.100.7 OR cidr = 192.0.2.15`, true, true},
+		{`netblock = 104.16.0.0/12 and desc ~ "(?i)cloudflare"`, true, false},
+		{`asn >= 64512`, true, false},
+		{`asn < 1000`, false, false},
+		{`new = true`, false, true},
+		{`verified = false AND (tag = cert OR tag = api)`, true, false},
+		{`name !~ "^(vpn|mail)\\."`, false, true},
+		{`reputation = malicious`, false, true},
+		{`rep != benign AND score >= 75`, false, true},
+		{`rep != benign`, true, true},
+		{`score > 90`, false, false},
+	}
+
+	for _, test := range tests {
+		q, err := ParseQuery(test.Expr)
+		if err != nil {
+			t.Errorf("ParseQuery failed for %s: %v", test.Expr, err)
+			continue
+		}
+
+		if got := q.Match(vpn); got != test.VPN {
+			t.Errorf("The query %s returned %t for %s", test.Expr, got, vpn.Name)
+		}
+		if got := q.Match(www); got != test.WWW {
+			t.Errorf("The query %s returned %t for %s", test.Expr, got, www.Name)
+		}
+	}
+
+	var q *Query
+	if !q.Match(www) || q.UsesASNInfo() {
+		t.Errorf("The nil query did not match every finding")
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"name",
+		"name =",
+		"owner = example",
+		`name ~ "[vpn"`,
+		`name = "vpn`,
+		"name < vpn",
+		"asn = example",
+		"addr = example",
+		"new = maybe",
+		"(name = vpn",
+		"name = vpn AND",
+		"name = vpn example",
+		"name =~ vpn",
+	} {
+		if _, err := ParseQuery(expr); err == nil {
+			t.Errorf("ParseQuery did not fail for %q", expr)
+		}
+	}
+}
+
+func TestQueryUsesASNInfo(t *testing.T) {
+	if q, _ := ParseQuery("name ~ vpn OR source = DNS"); q.UsesASNInfo() {
+		t.Errorf("The query without address details requested the ASN information")
+	}
+	if q, _ := ParseQuery("name ~ vpn AND NOT asn = 13335"); !q.UsesASNInfo() {
+		t.Errorf("The query comparing the ASN did not request the ASN information")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// SummaryCSVColumns are the columns of the rows written by FprintEnumerationSummaryCSV.
+var SummaryCSVColumns = []string{"asn", "description", "netblock", "names"}
+
+type summaryNetblock struct {
+	CIDR  string `json:"cidr"`
+	Names int    `json:"names"`
+}
+
+type summaryASN struct {
+	ASN         int               `json:"asn"`
+	Description string            `json:"description"`
+	Names       int               `json:"names"`
+	Netblocks   []summaryNetblock `json:"netblocks"`
+}
+
+type summaryRecord struct {
+	Total int            `json:"total"`
+	Tags  map[string]int `json:"tags"`
+	ASNs  []summaryASN   `json:"asns"`
+}
+
+// summaryASNs returns the autonomous systems in ascending order with their netblocks sorted.
+func summaryASNs(asns map[int]*ASNSummaryData) []summaryASN {
+	results := []summaryASN{}
+
+	for asn, data := range asns {
+		s := summaryASN{
+			ASN:         asn,
+			Description: data.Name,
+			Netblocks:   []summaryNetblock{},
+		}
+
+		for cidr, names := range data.Netblocks {
+			s.Names += names
+			s.Netblocks = append(s.Netblocks, summaryNetblock{CIDR: cidr, Names: names})
+		}
+		sort.Slice(s.Netblocks, func(i, j int) bool {
+			return s.Netblocks[i].CIDR < s.Netblocks[j].CIDR
+		})
+
+		results = append(results, s)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ASN < results[j].ASN
+	})
+	return results
+}
+
+// FprintEnumerationSummaryJSON writes the summary information as a single JSON object providing the
+// total, the counts for each tag and the autonomous systems with the counts for each netblock.
+func FprintEnumerationSummaryJSON(out io.Writer, total int, tags map[string]int, asns map[int]*ASNSummaryData) error {
+	if tags == nil {
+		tags = make(map[string]int)
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&summaryRecord{
+		Total: total,
+		Tags:  tags,
+		ASNs:  summaryASNs(asns),
+	})
+}
+
+// FprintEnumerationSummaryCSV writes the ASN and netblock breakdown of the summary information
+// as comma-separated values, with a header row followed by one row for each netblock.
+func FprintEnumerationSummaryCSV(out io.Writer, asns map[int]*ASNSummaryData) error {
+	w := csv.NewWriter(out)
+
+	if err := w.Write(SummaryCSVColumns); err != nil {
+		return err
+	}
+
+	for _, s := range summaryASNs(asns) {
+		asn := strconv.Itoa(s.ASN)
+
+		for _, n := range s.Netblocks {
+			if err := w.Write([]string{asn, s.Description, n.CIDR, strconv.Itoa(n.Names)}); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func testSummaryASNs() map[int]*ASNSummaryData {
+	return map[int]*ASNSummaryData{
+		64513: {Name: "Example IPv6", Netblocks: map[string]int{"2001:db8::/32": 1}},
+		64512: {Name: "Example, Inc", Netblocks: map[string]int{"198.51.100.0/24": 1, "192.0.2.0/24": 3}},
+	}
+}
+
+func TestFprintEnumerationSummaryJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := FprintEnumerationSummaryJSON(&buf, 5, map[string]int{"dns": 4, "cert": 1}, testSummaryASNs()); err != nil {
+		t.Fatalf("FprintEnumerationSummaryJSON failed: %v", err)
+	}
+
+	var summary summaryRecord
+	if err := json.Unmarshal(buf.Bytes(), &summary); err != nil {
+		t.Fatalf("Failed to parse the JSON summary: %v", err)
+	}
+	if summary.Total != 5 || summary.Tags["dns"] != 4 || summary.Tags["cert"] != 1 {
+		t.Errorf("The JSON summary has the total %d and tags %v", summary.Total, summary.Tags)
+	}
+	if len(summary.ASNs) != 2 || summary.ASNs[0].ASN != 64512 || summary.ASNs[1].ASN != 64513 {
+		t.Fatalf("The autonomous systems are not in ascending order: %v", summary.ASNs)
+	}
+
+	expected := []summaryNetblock{{CIDR: "192.0.2.0/24", Names: 3}, {CIDR: "198.51.100.0/24", Names: 1}}
+	if first := summary.ASNs[0]; first.Names != 4 || !reflect.DeepEqual(first.Netblocks, expected) {
+		t.Errorf("The first autonomous system has %d names and the netblocks %v", first.Names, first.Netblocks)
+	}
+
+	buf.Reset()
+	if err := FprintEnumerationSummaryJSON(&buf, 0, nil, nil); err != nil {
+		t.Fatalf("FprintEnumerationSummaryJSON failed without findings: %v", err)
+	}
+	var empty map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &empty); err != nil || empty["asns"] == nil || empty["tags"] == nil {
+		t.Errorf("The empty JSON summary is not usable: %s", buf.String())
+	}
+}
+
+func TestFprintEnumerationSummaryCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := FprintEnumerationSummaryCSV(&buf, testSummaryASNs()); err != nil {
+		t.Fatalf("FprintEnumerationSummaryCSV failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read the CSV summary: %v", err)
+	}
+
+	expected := [][]string{
+		SummaryCSVColumns,
+		{"64512", "Example, Inc", "192.0.2.0/24", "3"},
+		{"64512", "Example, Inc", "198.51.100.0/24", "1"},
+		{"64513", "Example IPv6", "2001:db8::/32", "1"},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("The CSV summary has the rows %v", records)
+	}
+}
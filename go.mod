@@ -19,8 +19,9 @@ require (
 	github.com/go-sql-driver/mysql v1.5.0 // indirect
 	github.com/gobuffalo/packr/v2 v2.8.0 // indirect
 	github.com/gobwas/pool v0.2.1 // indirect
-	github.com/gobwas/ws v1.0.3 // indirect
+	github.com/gobwas/ws v1.0.3
 	github.com/gogo/protobuf v1.3.1 // indirect
+	github.com/gomodule/redigo v1.8.2
 	github.com/google/uuid v1.1.2
 	github.com/gorilla/websocket v1.4.2 // indirect
 	github.com/jmoiron/sqlx v1.2.0
@@ -28,7 +29,9 @@ require (
 	github.com/lib/pq v1.8.0
 	github.com/mailru/easyjson v0.7.6 // indirect
 	github.com/mattn/go-colorable v0.1.7 // indirect
+	github.com/mattn/go-sqlite3 v1.10.0
 	github.com/miekg/dns v1.1.31
+	github.com/neo4j/neo4j-go-driver/v4 v4.2.4
 	github.com/northwesternmutual/grammes v1.1.2
 	github.com/prometheus/common v0.13.0 // indirect
 	github.com/rakyll/statik v0.1.7
@@ -49,6 +52,7 @@ require (
 	google.golang.org/appengine v1.6.6 // indirect
 	google.golang.org/protobuf v1.25.0 // indirect
 	gopkg.in/ini.v1 v1.55.0 // indirect
+	gopkg.in/yaml.v2 v2.3.0
 	honnef.co/go/tools v0.0.1-2020.1.3 // indirect
 	layeh.com/gopher-json v0.0.0-20190114024228-97fed8db8427
 )
@@ -0,0 +1,74 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import "runtime"
+
+// Capability describes whether a graph database system can be used by this binary.
+type Capability struct {
+	System    string `json:"system"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// The build constraints of the binary, which are set by the files with the matching tags
+var (
+	cgoDisabled bool
+	puregoBuild bool
+)
+
+// Capabilities returns the graph database systems accepted by NewGraphDatabase, and reports the
+// systems that are missing from binaries built with CGO_ENABLED=0 or the purego build tag.
+// The plugins entry reports whether data sources can be loaded from Go plugins.
+func Capabilities() []*Capability {
+	caps := []*Capability{
+		{System: "gremlin", Available: true},
+		{System: "local", Available: true},
+		{System: "mysql", Available: true},
+		{System: "neo4j", Available: true},
+		{System: "postgres", Available: true},
+		{System: "postgresql", Available: true},
+		{System: "redis", Available: true},
+		{System: "sqlite", Available: sqliteAvailable},
+	}
+
+	for _, c := range caps {
+		if c.Available {
+			continue
+		}
+
+		c.Reason = "This binary was built without cgo"
+		// Binaries built with cgo leave out systems when the purego tag is provided
+		if puregoBuild && !cgoDisabled {
+			c.Reason = "This binary was built with the purego tag"
+		}
+	}
+	return append(caps, pluginsCapability())
+}
+
+// pluginsCapability reports whether Go plugins can be opened, which requires cgo and an
+// operating system supported by the plugin package.
+func pluginsCapability() *Capability {
+	c := &Capability{System: "plugins"}
+
+	switch {
+	case cgoDisabled:
+		c.Reason = "This binary was built without cgo"
+	case runtime.GOOS != "linux" && runtime.GOOS != "darwin" && runtime.GOOS != "freebsd":
+		c.Reason = "Go plugins are not supported on " + runtime.GOOS
+	default:
+		c.Available = true
+	}
+	return c
+}
+
+// SystemAvailable returns true when the graph database system can be used by this binary.
+func SystemAvailable(system string) bool {
+	for _, c := range Capabilities() {
+		if c.System == system {
+			return c.Available
+		}
+	}
+	return false
+}
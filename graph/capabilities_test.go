@@ -0,0 +1,54 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	if !SystemAvailable("local") {
+		t.Errorf("The local system was reported as unavailable")
+	}
+	if SystemAvailable("arangodb") {
+		t.Errorf("An unsupported system was reported as available")
+	}
+
+	var plugins bool
+	for _, c := range Capabilities() {
+		if !c.Available && c.Reason == "" {
+			t.Errorf("The %s system is unavailable without a reason", c.System)
+		}
+		if c.System == "plugins" {
+			plugins = true
+			if cgoDisabled && c.Available {
+				t.Errorf("Plugins were reported as available in a binary built without cgo")
+			}
+		}
+		// Binaries built with cgo are only missing the sqlite system due to the purego tag
+		if c.System == "sqlite" && !c.Available && !cgoDisabled && !strings.Contains(c.Reason, "purego") {
+			t.Errorf("The reason %q does not describe the build of the binary", c.Reason)
+		}
+	}
+	if !plugins {
+		t.Errorf("The capabilities did not report whether plugins are available")
+	}
+
+	dir, err := ioutil.TempDir("", "capabilities")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := NewSQLiteGraph(dir, "")
+	if SystemAvailable("sqlite") != (err == nil) {
+		t.Errorf("The sqlite capability does not match the result of NewSQLiteGraph: %v", err)
+	}
+	if err == nil {
+		db.Close()
+	}
+}
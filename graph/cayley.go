@@ -24,6 +24,7 @@ type CayleyGraph struct {
 	store  *cayley.Handle
 	name   string
 	path   string
+	opts   graph.Options
 	isBolt bool
 	noSync bool
 }
@@ -94,6 +95,7 @@ func NewCayleyGraph(system, path string, options string) *CayleyGraph {
 		store:  store,
 		name:   name,
 		path:   path,
+		opts:   opts,
 		isBolt: isbolt,
 		noSync: nosync,
 	}
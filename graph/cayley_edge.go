@@ -193,6 +193,34 @@ func (g *CayleyGraph) ReadOutEdges(node Node, predicates ...string) ([]*Edge, er
 	return edges, nil
 }
 
+// readTypedOutEdges returns the edges leaving the node with the types of the nodes they enter,
+// which the quad store obtains by joining the edges with the type quads in a single query.
+func (g *CayleyGraph) readTypedOutEdges(node Node) ([]*typedEdge, error) {
+	g.Lock()
+	defer g.Unlock()
+
+	nstr := g.NodeToID(node)
+	if nstr == "" || !g.nodeExists(nstr, "") {
+		return nil, fmt.Errorf("%s: readTypedOutEdges: Invalid node reference argument", g.String())
+	}
+
+	p := cayley.StartPath(g.store, quad.IRI(nstr)).OutWithTags([]string{"predicate"}).Tag("object").Out(quad.IRI("type")).Tag("type")
+
+	var edges []*typedEdge
+	p.Iterate(context.TODO()).TagValues(nil, func(m map[string]quad.Value) {
+		edges = append(edges, &typedEdge{
+			Predicate: valToStr(m["predicate"]),
+			To:        valToStr(m["object"]),
+			Type:      valToStr(m["type"]),
+		})
+	})
+
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%s: readTypedOutEdges: Failed to discover edges leaving the node %s", g.String(), nstr)
+	}
+	return edges, nil
+}
+
 // CountOutEdges implements the GraphDatabase interface.
 func (g *CayleyGraph) CountOutEdges(node Node, predicates ...string) (int, error) {
 	g.Lock()
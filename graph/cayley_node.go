@@ -84,6 +84,32 @@ func (g *CayleyGraph) AllOutNodes(node Node) ([]Node, error) {
 	return nodes, nil
 }
 
+// CountOutNodesOfType returns the number of unique nodes of the type that the parameter node has
+// out edges to, using the predicates when provided. The hasOut predicate, when not empty, only
+// counts the nodes that have an out edge with that predicate. The counting takes place in the store.
+func (g *CayleyGraph) CountOutNodesOfType(node Node, ntype, hasOut string, predicates ...string) (int, error) {
+	g.Lock()
+	defer g.Unlock()
+
+	nstr := g.NodeToID(node)
+	if nstr == "" || !g.nodeExists(nstr, "") {
+		return 0, fmt.Errorf("%s: CountOutNodesOfType: Invalid node reference argument", g.String())
+	}
+
+	p := cayley.StartPath(g.store, quad.IRI(nstr))
+	if len(predicates) == 0 {
+		p = p.Out()
+	} else {
+		p = p.Out(strsToVals(predicates...))
+	}
+	p = p.Has(quad.IRI("type"), quad.String(ntype))
+	if hasOut != "" {
+		p = p.Has(quad.IRI(hasOut))
+	}
+
+	return g.optimizedCount(p.Unique()), nil
+}
+
 // InsertNode implements the GraphDatabase interface.
 func (g *CayleyGraph) InsertNode(id, ntype string) (Node, error) {
 	g.Lock()
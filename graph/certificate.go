@@ -0,0 +1,83 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+)
+
+// AddressCertificate describes a certificate provided by the address, along with the port
+// and the SNI value that caused the server to provide it.
+type AddressCertificate struct {
+	Port       int      `json:"port"`
+	ServerName string   `json:"server_name,omitempty"`
+	Names      []string `json:"names"`
+}
+
+// InsertAddressCertificate records the certificate obtained from the address as a property of the ipaddr
+// node. Only the most recent certificate obtained with each port and SNI value is kept.
+func (g *Graph) InsertAddressCertificate(addr string, cert *AddressCertificate) error {
+	if addr == "" {
+		return errors.New("Graph: InsertAddressCertificate: Invalid address provided")
+	}
+	if cert == nil || cert.Port <= 0 {
+		return errors.New("Graph: InsertAddressCertificate: Invalid certificate provided")
+	}
+
+	// The certificate can be obtained before the address has been entered by the DNS record
+	node, err := g.InsertNodeIfNotExist(addr, "ipaddr")
+	if err != nil {
+		return err
+	}
+
+	if properties, err := g.db.ReadProperties(node, "certificate"); err == nil {
+		for _, p := range properties {
+			var prev AddressCertificate
+
+			if err := json.Unmarshal([]byte(p.Value), &prev); err != nil ||
+				prev.Port != cert.Port || prev.ServerName != cert.ServerName {
+				continue
+			}
+			g.db.DeleteProperty(node, p.Predicate, p.Value)
+		}
+	}
+
+	data, err := json.Marshal(cert)
+	if err != nil {
+		return err
+	}
+	return g.db.InsertProperty(node, "certificate", string(data))
+}
+
+// AddressCertificates returns the certificates obtained from the address, sorted by port and SNI value.
+func (g *Graph) AddressCertificates(addr string) []*AddressCertificate {
+	node, err := g.db.ReadNode(addr, "ipaddr")
+	if err != nil {
+		return nil
+	}
+
+	properties, err := g.db.ReadProperties(node, "certificate")
+	if err != nil {
+		return nil
+	}
+
+	var results []*AddressCertificate
+	for _, p := range properties {
+		var cert AddressCertificate
+
+		if err := json.Unmarshal([]byte(p.Value), &cert); err == nil {
+			results = append(results, &cert)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Port != results[j].Port {
+			return results[i].Port < results[j].Port
+		}
+		return results[i].ServerName < results[j].ServerName
+	})
+	return results
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAddressCertificates(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	addr := "2001:db8::10"
+	if err := g.InsertAddressCertificate("", &AddressCertificate{Port: 443}); err == nil {
+		t.Errorf("InsertAddressCertificate did not return an error when provided an empty address")
+	}
+	if err := g.InsertAddressCertificate(addr, &AddressCertificate{}); err == nil {
+		t.Errorf("InsertAddressCertificate did not return an error when provided an invalid port")
+	}
+	if certs := g.AddressCertificates(addr); len(certs) != 0 {
+		t.Errorf("AddressCertificates returned %d certificates before any were inserted", len(certs))
+	}
+
+	for _, cert := range []*AddressCertificate{
+		{Port: 8443, Names: []string{"admin.example.com"}},
+		{Port: 443, ServerName: "www.example.com", Names: []string{"www.example.com", "example.com"}},
+		{Port: 443, Names: []string{"default.example.com"}},
+		// A more recent certificate replaces the previous one for the port and SNI value
+		{Port: 443, ServerName: "www.example.com", Names: []string{"www.example.com"}},
+	} {
+		if err := g.InsertAddressCertificate(addr, cert); err != nil {
+			t.Fatalf("Failed to insert the certificate: %v", err)
+		}
+	}
+
+	expected := []*AddressCertificate{
+		{Port: 443, Names: []string{"default.example.com"}},
+		{Port: 443, ServerName: "www.example.com", Names: []string{"www.example.com"}},
+		{Port: 8443, Names: []string{"admin.example.com"}},
+	}
+	if certs := g.AddressCertificates(addr); !reflect.DeepEqual(certs, expected) {
+		t.Errorf("AddressCertificates returned %v", certs)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cayleygraph/cayley"
+	"github.com/cayleygraph/cayley/graph"
+	"github.com/cayleygraph/quad"
+)
+
+// The file holding the quads of the local database within its directory.
+const boltFileName = "indexes.bolt"
+
+// CompactStats describes the outcome of compacting a graph database.
+type CompactStats struct {
+	// Quads is the number of quads rewritten, or zero when the database was compacted in place
+	Quads int
+	// The size of the database in bytes, or zero when the size cannot be obtained
+	Before int64
+	After  int64
+}
+
+// compacter is implemented by the graph databases that can reclaim the space of removed data.
+type compacter interface {
+	compact(ctx context.Context) (*CompactStats, error)
+}
+
+// Compact reclaims the space held by the nodes, edges and properties removed from the graph database,
+// and rebuilds the indexes. The local database is rewritten into a new file that replaces the
+// original, so the graph database must not be used by other processes during the compaction.
+func (g *Graph) Compact(ctx context.Context) (*CompactStats, error) {
+	c, ok := g.db.(compacter)
+	if !ok {
+		return nil, fmt.Errorf("Graph: Compact: The %s graph database cannot be compacted", g.String())
+	}
+
+	return c.compact(ctx)
+}
+
+// compact copies the quads that have not been removed into a new Bolt store, which drops the
+// tombstones of the removed quads along with the pages that Bolt would otherwise keep.
+func (g *CayleyGraph) compact(ctx context.Context) (*CompactStats, error) {
+	if !g.isBolt {
+		return nil, fmt.Errorf("%s: Compact: Only the local database can be compacted", g.String())
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	file := filepath.Join(g.path, boltFileName)
+	stats := &CompactStats{Before: fileSize(file)}
+
+	tmp, err := ioutil.TempDir(g.path, "compact")
+	if err != nil {
+		return nil, fmt.Errorf("%s: Compact: %v", g.String(), err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := graph.InitQuadStore("bolt", tmp, g.opts); err != nil {
+		return nil, fmt.Errorf("%s: Compact: %v", g.String(), err)
+	}
+	store, err := cayley.NewGraph("bolt", tmp, g.opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: Compact: %v", g.String(), err)
+	}
+	dest := &CayleyGraph{store: store}
+
+	// The quads are written as they are read, so the store does not need to fit in memory
+	var batch []quad.Quad
+	flush := func() error {
+		err := dest.writeQuads(ctx, batch, nil)
+
+		stats.Quads += len(batch)
+		batch = batch[:0]
+		return err
+	}
+
+	it := g.store.QuadsAllIterator()
+	for err == nil && it.Next(ctx) {
+		if batch = append(batch, g.store.Quad(it.Result())); len(batch) >= MigrateBatchSize {
+			err = flush()
+		}
+	}
+	if err == nil {
+		err = it.Err()
+	}
+	it.Close()
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err == nil {
+		err = flush()
+	}
+	store.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: Compact: %v", g.String(), err)
+	}
+
+	// The original store is closed before its file is replaced, and reopened afterwards
+	g.store.Close()
+	err = os.Rename(filepath.Join(tmp, boltFileName), file)
+	if h, oerr := cayley.NewGraph("bolt", g.path, g.opts); oerr == nil {
+		g.store = h
+	} else if err == nil {
+		err = oerr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: Compact: %v", g.String(), err)
+	}
+
+	stats.After = fileSize(file)
+	return stats, nil
+}
+
+func fileSize(path string) int64 {
+	if info, err := os.Stat(path); err == nil {
+		return info.Size()
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compact")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	g := NewGraph(NewCayleyGraph("local", dir, ""))
+	if g == nil {
+		t.Fatalf("Failed to create the local graph database")
+	}
+	defer g.Close()
+
+	for _, uuid := range []string{"kept", "removed"} {
+		for i := 0; i < 200; i++ {
+			name := fmt.Sprintf("%s%d.owasp.org", uuid, i)
+
+			if _, err := g.InsertFQDN(name, "DNS", "dns", uuid); err != nil {
+				t.Fatalf("Failed to insert the FQDN %s: %v", name, err)
+			}
+		}
+	}
+	if _, err := g.RemoveEvent("removed"); err != nil {
+		t.Fatalf("Failed to remove the event: %v", err)
+	}
+
+	kept := len(g.EventFQDNs("kept"))
+
+	stats, err := g.Compact(context.Background())
+	if err != nil {
+		t.Fatalf("Compact returned an error: %v", err)
+	}
+	if stats.Quads == 0 || stats.Before == 0 || stats.After == 0 || stats.After > stats.Before {
+		t.Errorf("Compact reported %d quads, and %d bytes before and %d bytes after", stats.Quads, stats.Before, stats.After)
+	}
+	if entries, _ := ioutil.ReadDir(dir); len(entries) != 1 || entries[0].Name() != boltFileName {
+		t.Errorf("The compaction left %d entries in the directory", len(entries))
+	}
+
+	// The reopened store still holds the findings and accepts new ones
+	if names := g.EventFQDNs("kept"); len(names) != kept {
+		t.Errorf("The compacted database holds %d FQDNs of the enumeration, expected %d", len(names), kept)
+	}
+	if names := g.EventFQDNs("removed"); len(names) != 0 {
+		t.Errorf("The compacted database holds %d FQDNs of the removed enumeration", len(names))
+	}
+	if _, err := g.InsertFQDN("www.owasp.org", "DNS", "dns", "kept"); err != nil {
+		t.Errorf("Failed to insert the FQDN after the compaction: %v", err)
+	}
+	g.Close()
+
+	again := NewGraph(NewCayleyGraph("local", dir, ""))
+	if again == nil {
+		t.Fatalf("Failed to open the compacted database")
+	}
+	defer again.Close()
+	if names := again.EventFQDNs("kept"); len(names) != kept+1 {
+		t.Errorf("The compacted database holds %d FQDNs after reopening, expected %d", len(names), kept+1)
+	}
+	if _, err := os.Stat(filepath.Join(dir, boltFileName)); err != nil {
+		t.Errorf("The database file is missing: %v", err)
+	}
+}
+
+func TestCompactUnsupported(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	if _, err := g.Compact(context.Background()); err == nil {
+		t.Errorf("Compact did not return an error for the in-memory graph database")
+	}
+	if _, err := NewGraph(&plainDatabase{g.db}).Compact(context.Background()); err == nil {
+		t.Errorf("Compact did not return an error for a graph database without support")
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+var cypherStringReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// WriteCypher writes a Neo4j Cypher script that creates the events identified by the uuids, along with
+// the nodes, relationships and properties associated with them. The script uses the data model of the
+// neo4j graph database system, so the findings can be imported with cypher-shell into an empty database
+// and queried the same way as findings stored over a Bolt connection.
+func (g *Graph) WriteCypher(w io.Writer, uuids ...string) error {
+	nodes := make(map[string]string)
+
+	for _, uuid := range uuids {
+		event, err := g.db.ReadNode(uuid, "event")
+		if err != nil {
+			return fmt.Errorf("Graph: WriteCypher: Failed to find the event %s", uuid)
+		}
+		nodes[g.db.NodeToID(event)] = "event"
+
+		edges, err := g.db.ReadOutEdges(event)
+		if err != nil {
+			continue
+		}
+
+		for _, edge := range edges {
+			id := g.db.NodeToID(edge.To)
+			if _, found := nodes[id]; found {
+				continue
+			}
+
+			if p, err := g.db.ReadProperties(edge.To, "type"); err == nil && len(p) > 0 {
+				nodes[id] = p[0].Value
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	bufwr := bufio.NewWriter(w)
+	fmt.Fprintf(bufwr, "CREATE CONSTRAINT amass_node_id IF NOT EXISTS FOR (n:%s) REQUIRE n.id IS UNIQUE;\n", neo4jNodeLabel)
+
+	for _, id := range ids {
+		ntype := nodes[id]
+		node, err := g.db.ReadNode(id, ntype)
+		if err != nil {
+			continue
+		}
+
+		props := []string{"id: " + cypherString(id), "type: " + cypherString(ntype)}
+		props = append(props, cypherPropertyLists(g.db, node)...)
+
+		fmt.Fprintf(bufwr, "CREATE (:%s:%s {%s});\n", neo4jNodeLabel, cypherName(ntype), strings.Join(props, ", "))
+	}
+
+	for _, id := range ids {
+		node, err := g.db.ReadNode(id, nodes[id])
+		if err != nil {
+			continue
+		}
+
+		edges, err := g.db.ReadOutEdges(node)
+		if err != nil {
+			continue
+		}
+
+		for _, edge := range edges {
+			to := g.db.NodeToID(edge.To)
+			// Relationships leaving the selected events, such as cached data source responses, are omitted
+			if _, found := nodes[to]; !found {
+				continue
+			}
+
+			fmt.Fprintf(bufwr, "MATCH (a:%s {id: %s}), (b:%s {id: %s}) CREATE (a)-[:%s]->(b);\n", neo4jNodeLabel,
+				cypherString(id), neo4jNodeLabel, cypherString(to), cypherName(edge.Predicate))
+		}
+	}
+
+	return bufwr.Flush()
+}
+
+// cypherPropertyLists returns the properties of the node as Cypher map entries holding lists of strings.
+func cypherPropertyLists(db GraphDatabase, node Node) []string {
+	properties, err := db.ReadProperties(node)
+	if err != nil {
+		return nil
+	}
+
+	var preds []string
+	values := make(map[string][]string)
+	for _, p := range properties {
+		if p.Predicate == "id" || p.Predicate == "type" {
+			continue
+		}
+
+		if _, found := values[p.Predicate]; !found {
+			preds = append(preds, p.Predicate)
+		}
+		values[p.Predicate] = append(values[p.Predicate], cypherString(p.Value))
+	}
+	sort.Strings(preds)
+
+	var entries []string
+	for _, pred := range preds {
+		entries = append(entries, cypherName(pred)+": ["+strings.Join(values[pred], ", ")+"]")
+	}
+	return entries
+}
+
+// cypherString returns the value as a Cypher string literal.
+func cypherString(value string) string {
+	return `"` + cypherStringReplacer.Replace(value) + `"`
+}
@@ -0,0 +1,54 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteCypher(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+
+	if err := g.InsertA("www.owasp.org", "192.0.2.1", "DNS", "dns", "cyphertest"); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	if err := g.InsertLiveness("192.0.2.1", true, []int{443}, time.Now()); err != nil {
+		t.Fatalf("Failed to insert the liveness: %v", err)
+	}
+	if err := g.CacheSourceData("DNS", "dns", "owasp.org", `{"data": "cached"}`); err != nil {
+		t.Fatalf("Failed to cache the source data: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := g.WriteCypher(&buf, "cyphertest"); err != nil {
+		t.Fatalf("WriteCypher failed: %v", err)
+	}
+	script := buf.String()
+
+	for _, want := range []string{
+		"CREATE CONSTRAINT amass_node_id IF NOT EXISTS FOR (n:Amass) REQUIRE n.id IS UNIQUE;\n",
+		"CREATE (:Amass:`event` {id: \"cyphertest\", type: \"event\", ",
+		"CREATE (:Amass:`ipaddr` {id: \"192.0.2.1\", type: \"ipaddr\", ",
+		"`open_port`: [\"443\"]",
+		"MATCH (a:Amass {id: \"www.owasp.org\"}), (b:Amass {id: \"192.0.2.1\"}) CREATE (a)-[:`a_record`]->(b);\n",
+		"MATCH (a:Amass {id: \"cyphertest\"}), (b:Amass {id: \"DNS\"}) CREATE (a)-[:`used`]->(b);\n",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("The script is missing %s", want)
+		}
+	}
+	if strings.Contains(script, "cached") {
+		t.Errorf("The cached data source responses were included in the script")
+	}
+
+	if err := g.WriteCypher(&buf, "missing"); err == nil {
+		t.Errorf("WriteCypher did not fail for a missing event")
+	}
+	if s := cypherString("a \"b\" \\ c\n"); s != `"a \"b\" \\ c\n"` {
+		t.Errorf("cypherString returned %s", s)
+	}
+}
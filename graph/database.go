@@ -0,0 +1,114 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// GraphDatabase is the interface implemented by the stores that hold the Amass data model.
+type GraphDatabase interface {
+	// String returns a description for the graph database
+	String() string
+
+	// Close releases the resources held by the graph database
+	Close()
+
+	// DumpGraph returns a textual representation of all data in the graph database
+	DumpGraph() string
+
+	// NodeToID returns the identifier of the node
+	NodeToID(n Node) string
+
+	// AllNodesOfType returns the nodes of the types provided, or all nodes when no types are provided
+	AllNodesOfType(ntypes ...string) ([]Node, error)
+
+	// AllOutNodes returns the nodes that the node has out edges to
+	AllOutNodes(node Node) ([]Node, error)
+
+	// InsertNode creates the node when it does not already exist
+	InsertNode(id, ntype string) (Node, error)
+
+	// ReadNode returns the node matching the id and type, or any type when ntype is empty
+	ReadNode(id, ntype string) (Node, error)
+
+	// DeleteNode removes the node along with its edges and properties
+	DeleteNode(node Node) error
+
+	// InsertEdge creates the edge when it does not already exist
+	InsertEdge(edge *Edge) error
+
+	// ReadEdges returns the edges entering and leaving the node with the predicates provided
+	ReadEdges(node Node, predicates ...string) ([]*Edge, error)
+
+	// CountEdges returns the number of edges entering and leaving the node with the predicates provided
+	CountEdges(node Node, predicates ...string) (int, error)
+
+	// ReadInEdges returns the edges entering the node with the predicates provided
+	ReadInEdges(node Node, predicates ...string) ([]*Edge, error)
+
+	// CountInEdges returns the number of edges entering the node with the predicates provided
+	CountInEdges(node Node, predicates ...string) (int, error)
+
+	// ReadOutEdges returns the edges leaving the node with the predicates provided
+	ReadOutEdges(node Node, predicates ...string) ([]*Edge, error)
+
+	// CountOutEdges returns the number of edges leaving the node with the predicates provided
+	CountOutEdges(node Node, predicates ...string) (int, error)
+
+	// CountOutNodesOfType returns the number of unique nodes of the type that the node has out
+	// edges to, only counting the nodes with an out edge using the hasOut predicate when provided
+	CountOutNodesOfType(node Node, ntype, hasOut string, predicates ...string) (int, error)
+
+	// DeleteEdge removes the edge from the graph database
+	DeleteEdge(edge *Edge) error
+
+	// InsertProperty stores the property of the node when it does not already exist
+	InsertProperty(node Node, predicate, value string) error
+
+	// ReadProperties returns the properties of the node with the predicates provided,
+	// and the type of the node is always available as the "type" property
+	ReadProperties(node Node, predicates ...string) ([]*Property, error)
+
+	// CountProperties returns the number of properties of the node with the predicates provided
+	CountProperties(node Node, predicates ...string) (int, error)
+
+	// DeleteProperty removes the property from the node
+	DeleteProperty(node Node, predicate, value string) error
+}
+
+// NewGraphDatabase returns the graph database for the system identified in the configuration.
+// The postgresql and sqlite systems store the nodes and edges in tables of their own, the neo4j
+// system connects to a Neo4j server using the Bolt protocol, the gremlin system connects to a
+// TinkerPop server such as Amazon Neptune, the redis system keeps short-lived findings in a Redis
+// server, and the remaining systems are handled by the Cayley quad stores.
+func NewGraphDatabase(system, url, username, password, dbname, options string) (GraphDatabase, error) {
+	switch system {
+	case "postgresql":
+		return NewPostgresGraph(url, options)
+	case "sqlite":
+		return NewSQLiteGraph(url, options)
+	case "neo4j":
+		return NewNeo4jGraph(url, username, password, dbname)
+	case "gremlin":
+		return NewGremlinGraph(url, username, password, options)
+	case "redis":
+		return NewRedisGraph(url, username, password, options)
+	}
+
+	if c := NewCayleyGraph(system, url, options); c != nil {
+		return c, nil
+	}
+	return nil, fmt.Errorf("Failed to create the %s graph database", system)
+}
+
+func isNilDatabase(database GraphDatabase) bool {
+	if database == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(database)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
@@ -0,0 +1,291 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// DumpFormat identifies the line-delimited JSON dumps written by Dump.
+const DumpFormat = "amass-graph"
+
+// DumpVersion is the version of the dump schema written by Dump.
+const DumpVersion = 1
+
+// The maximum accepted length of a line within a dump
+const maxDumpLineLength = 16 * 1024 * 1024
+
+// DumpHeader is the first line of a dump, identifying the schema used by the remaining lines.
+type DumpHeader struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+}
+
+// DumpNode is a line of the dump describing a node along with the values of its properties.
+type DumpNode struct {
+	Kind       string              `json:"kind"`
+	ID         string              `json:"id"`
+	Type       string              `json:"type"`
+	Properties map[string][]string `json:"properties,omitempty"`
+}
+
+// DumpEdge is a line of the dump describing an edge between two nodes.
+type DumpEdge struct {
+	Kind      string `json:"kind"`
+	From      string `json:"from"`
+	FromType  string `json:"from_type"`
+	Predicate string `json:"predicate"`
+	To        string `json:"to"`
+	ToType    string `json:"to_type"`
+}
+
+type dumpRef struct {
+	ID   string
+	Type string
+}
+
+// Dump writes every node, property and edge of the graph database as newline-delimited JSON.
+// The DumpHeader is written first, followed by a DumpNode line for each node and then a DumpEdge
+// line for each edge. Nodes are sorted by type and identifier, and edges by the nodes and predicate,
+// so dumps of the same data are identical regardless of the graph database system that held it.
+func (g *Graph) Dump(w io.Writer) error {
+	nodes, err := g.db.AllNodesOfType()
+	if err != nil {
+		return fmt.Errorf("Graph: Dump: Failed to obtain the nodes: %v", err)
+	}
+
+	var refs []dumpRef
+	for _, node := range nodes {
+		if ntype := g.nodeType(node); ntype != "" {
+			refs = append(refs, dumpRef{ID: g.db.NodeToID(node), Type: ntype})
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Type != refs[j].Type {
+			return refs[i].Type < refs[j].Type
+		}
+		return refs[i].ID < refs[j].ID
+	})
+
+	bufwr := bufio.NewWriter(w)
+	enc := json.NewEncoder(bufwr)
+	if err := enc.Encode(&DumpHeader{Format: DumpFormat, Version: DumpVersion}); err != nil {
+		return err
+	}
+
+	for _, ref := range refs {
+		node, err := g.db.ReadNode(ref.ID, ref.Type)
+		if err != nil {
+			continue
+		}
+
+		if err := enc.Encode(g.dumpNode(node, ref)); err != nil {
+			return err
+		}
+	}
+
+	for _, ref := range refs {
+		node, err := g.db.ReadNode(ref.ID, ref.Type)
+		if err != nil {
+			continue
+		}
+
+		edges, err := g.db.ReadOutEdges(node)
+		if err != nil {
+			continue
+		}
+
+		var lines []*DumpEdge
+		for _, edge := range edges {
+			if totype := g.nodeType(edge.To); totype != "" {
+				lines = append(lines, &DumpEdge{
+					Kind:      "edge",
+					From:      ref.ID,
+					FromType:  ref.Type,
+					Predicate: edge.Predicate,
+					To:        g.db.NodeToID(edge.To),
+					ToType:    totype,
+				})
+			}
+		}
+		sort.Slice(lines, func(i, j int) bool {
+			if lines[i].Predicate != lines[j].Predicate {
+				return lines[i].Predicate < lines[j].Predicate
+			}
+			if lines[i].ToType != lines[j].ToType {
+				return lines[i].ToType < lines[j].ToType
+			}
+			return lines[i].To < lines[j].To
+		})
+
+		for _, line := range lines {
+			if err := enc.Encode(line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return bufwr.Flush()
+}
+
+func (g *Graph) dumpNode(node Node, ref dumpRef) *DumpNode {
+	line := &DumpNode{
+		Kind: "node",
+		ID:   ref.ID,
+		Type: ref.Type,
+	}
+
+	properties, err := g.db.ReadProperties(node)
+	if err != nil {
+		return line
+	}
+
+	for _, p := range properties {
+		if p.Predicate == "id" || p.Predicate == "type" {
+			continue
+		}
+
+		if line.Properties == nil {
+			line.Properties = make(map[string][]string)
+		}
+		line.Properties[p.Predicate] = append(line.Properties[p.Predicate], p.Value)
+	}
+	for _, values := range line.Properties {
+		sort.Strings(values)
+	}
+	return line
+}
+
+func (g *Graph) nodeType(node Node) string {
+	if p, err := g.db.ReadProperties(node, "type"); err == nil && len(p) > 0 {
+		return p[0].Value
+	}
+	return ""
+}
+
+// Restore inserts the nodes, properties and edges from a dump written by Dump into the graph
+// database. Data already held by the graph database is kept, so dumps can be restored into
+// databases that hold other enumerations. The number of nodes and edges restored is returned.
+func (g *Graph) Restore(r io.Reader) (int, int, error) {
+	var nodes, edges int
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxDumpLineLength)
+
+	var header bool
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		if len(data) == 0 {
+			continue
+		}
+
+		if !header {
+			var h DumpHeader
+
+			if err := json.Unmarshal(data, &h); err != nil || h.Format != DumpFormat {
+				return nodes, edges, errors.New("Graph: Restore: The input is not an Amass graph dump")
+			}
+			if h.Version > DumpVersion {
+				return nodes, edges, fmt.Errorf("Graph: Restore: The dump version %d is not supported", h.Version)
+			}
+
+			header = true
+			continue
+		}
+
+		var kind struct {
+			Kind string `json:"kind"`
+		}
+		if err := json.Unmarshal(data, &kind); err != nil {
+			return nodes, edges, fmt.Errorf("Graph: Restore: Line %d: %v", line, err)
+		}
+
+		switch kind.Kind {
+		case "node":
+			var n DumpNode
+
+			if err := json.Unmarshal(data, &n); err != nil {
+				return nodes, edges, fmt.Errorf("Graph: Restore: Line %d: %v", line, err)
+			}
+			if err := g.restoreNode(&n); err != nil {
+				return nodes, edges, fmt.Errorf("Graph: Restore: Line %d: %v", line, err)
+			}
+			nodes++
+		case "edge":
+			var e DumpEdge
+
+			if err := json.Unmarshal(data, &e); err != nil {
+				return nodes, edges, fmt.Errorf("Graph: Restore: Line %d: %v", line, err)
+			}
+			if err := g.restoreEdge(&e); err != nil {
+				return nodes, edges, fmt.Errorf("Graph: Restore: Line %d: %v", line, err)
+			}
+			edges++
+		default:
+			return nodes, edges, fmt.Errorf("Graph: Restore: Line %d: Unknown kind %q", line, kind.Kind)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nodes, edges, err
+	}
+	if !header {
+		return nodes, edges, errors.New("Graph: Restore: The input is not an Amass graph dump")
+	}
+	return nodes, edges, nil
+}
+
+func (g *Graph) restoreNode(n *DumpNode) error {
+	if n.ID == "" || n.Type == "" {
+		return errors.New("The node is missing the id or type")
+	}
+
+	node, err := g.db.InsertNode(n.ID, n.Type)
+	if err != nil {
+		return err
+	}
+
+	var preds []string
+	for pred := range n.Properties {
+		preds = append(preds, pred)
+	}
+	sort.Strings(preds)
+
+	for _, pred := range preds {
+		for _, value := range n.Properties[pred] {
+			if err := g.db.InsertProperty(node, pred, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *Graph) restoreEdge(e *DumpEdge) error {
+	if e.From == "" || e.FromType == "" || e.To == "" || e.ToType == "" || e.Predicate == "" {
+		return errors.New("The edge is missing the nodes or predicate")
+	}
+
+	// The nodes are created when the edge appears before them in the dump
+	from, err := g.db.InsertNode(e.From, e.FromType)
+	if err != nil {
+		return err
+	}
+
+	to, err := g.db.InsertNode(e.To, e.ToType)
+	if err != nil {
+		return err
+	}
+
+	return g.db.InsertEdge(&Edge{
+		Predicate: e.Predicate,
+		From:      from,
+		To:        to,
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDumpRestore(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+
+	if err := g.InsertA("www.example.domain", "192.0.2.1", "DNS", "dns", "event1"); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	if err := g.InsertCNAME("ftp.example.domain", "www.example.domain", "DNS", "dns", "event1"); err != nil {
+		t.Fatalf("Failed to insert the CNAME record: %v", err)
+	}
+	if err := g.InsertInfrastructure(64512, "Example, Inc", "192.0.2.1", "192.0.2.0/24", "RIR", "api", "event1"); err != nil {
+		t.Fatalf("Failed to insert the infrastructure: %v", err)
+	}
+
+	var dump bytes.Buffer
+	if err := g.Dump(&dump); err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(dump.String()), "\n")
+	if !strings.Contains(lines[0], `"format":"amass-graph"`) {
+		t.Errorf("The dump does not begin with the header: %s", lines[0])
+	}
+
+	restored := NewGraph(NewCayleyGraphMemory())
+	nodes, edges, err := restored.Restore(bytes.NewReader(dump.Bytes()))
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if nodes == 0 || edges == 0 || nodes+edges != len(lines)-1 {
+		t.Errorf("Restore provided %d nodes and %d edges from %d lines", nodes, edges, len(lines)-1)
+	}
+
+	var again bytes.Buffer
+	if err := restored.Dump(&again); err != nil {
+		t.Fatalf("Dump of the restored graph failed: %v", err)
+	}
+	if again.String() != dump.String() {
+		t.Errorf("The restored graph produced a different dump:\n%s\n%s", dump.String(), again.String())
+	}
+
+	if names := restored.EventFQDNs("event1"); len(names) != len(g.EventFQDNs("event1")) {
+		t.Errorf("The restored event has the names %v", names)
+	}
+}
+
+func TestRestoreInvalidInput(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+
+	for _, input := range []string{
+		"",
+		`{"kind":"node","id":"www.example.domain","type":"fqdn"}`,
+		`{"format":"amass-graph","version":99}`,
+		"{\"format\":\"amass-graph\",\"version\":1}\n{\"kind\":\"quad\"}",
+		"{\"format\":\"amass-graph\",\"version\":1}\n{\"kind\":\"edge\",\"from\":\"a\"}",
+	} {
+		if _, _, err := g.Restore(strings.NewReader(input)); err == nil {
+			t.Errorf("Restore accepted the invalid input: %q", input)
+		}
+	}
+}
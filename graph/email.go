@@ -0,0 +1,82 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// EmailInfo describes an email address discovered for a domain in the graph.
+type EmailInfo struct {
+	Address string
+	Domain  string
+	Sources []string
+}
+
+// InsertEmail adds an email address to the graph and links it to the domain name of the address.
+func (g *Graph) InsertEmail(email, source, tag, eventID string) (Node, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	parts := strings.Split(email, "@")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.New("Graph: InsertEmail: Invalid email address provided")
+	}
+
+	emailNode, err := g.InsertNodeIfNotExist(email, "email")
+	if err != nil {
+		return emailNode, err
+	}
+
+	domainNode, err := g.InsertFQDN(parts[1], source, tag, eventID)
+	if err != nil {
+		return emailNode, err
+	}
+
+	if err := g.InsertEdge(&Edge{
+		Predicate: "email_domain",
+		From:      emailNode,
+		To:        domainNode,
+	}); err != nil {
+		return emailNode, err
+	}
+
+	if err := g.AddNodeToEvent(emailNode, source, tag, eventID); err != nil {
+		return emailNode, err
+	}
+	return emailNode, nil
+}
+
+// EventEmails returns the email addresses discovered during the events, ordered by domain and address.
+func (g *Graph) EventEmails(events ...string) []*EmailInfo {
+	var emails []*EmailInfo
+
+	nodes, err := g.AllNodesOfType("email", events...)
+	if err != nil {
+		return emails
+	}
+
+	for _, node := range nodes {
+		info := &EmailInfo{Address: g.db.NodeToID(node)}
+
+		if edges, err := g.db.ReadOutEdges(node, "email_domain"); err == nil && len(edges) > 0 {
+			info.Domain = g.db.NodeToID(edges[0].To)
+		}
+		if sources, err := g.NodeSources(node, events...); err == nil {
+			sort.Strings(sources)
+			info.Sources = sources
+		}
+
+		emails = append(emails, info)
+	}
+
+	sort.Slice(emails, func(i, j int) bool {
+		if emails[i].Domain != emails[j].Domain {
+			return emails[i].Domain < emails[j].Domain
+		}
+		return emails[i].Address < emails[j].Address
+	})
+	return emails
+}
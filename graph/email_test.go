@@ -0,0 +1,43 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"testing"
+)
+
+func TestEventEmails(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	eventID := "c3d2a6b1-7e0f-4c38-9a57-2b8d1e4f6a90"
+	for _, email := range []string{"Jeff.Foley@owasp.org", "admin@owasp.org", "info@example.com"} {
+		if _, err := g.InsertEmail(email, "Hunter", "api", eventID); err != nil {
+			t.Fatalf("Failed to insert the email address %s: %v", email, err)
+		}
+	}
+	if _, err := g.InsertEmail("invalid.owasp.org", "Hunter", "api", eventID); err == nil {
+		t.Errorf("InsertEmail accepted an address without a domain name")
+	}
+
+	emails := g.EventEmails(eventID)
+	if len(emails) != 3 {
+		t.Fatalf("EventEmails returned %d addresses instead of 3", len(emails))
+	}
+
+	first := emails[0]
+	if first.Address != "info@example.com" || first.Domain != "example.com" {
+		t.Errorf("Unexpected first email address: %+v", first)
+	}
+	if len(first.Sources) != 1 || first.Sources[0] != "Hunter" {
+		t.Errorf("Unexpected sources for the email address: %v", first.Sources)
+	}
+	if emails[2].Address != "jeff.foley@owasp.org" {
+		t.Errorf("The email address was not normalized: %s", emails[2].Address)
+	}
+
+	if domains := g.EventDomains(eventID); len(domains) != 2 {
+		t.Errorf("The domains of the email addresses were not added to the event: %v", domains)
+	}
+}
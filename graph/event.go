@@ -5,11 +5,10 @@ package graph
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/OWASP/Amass/v3/stringset"
-	"github.com/cayleygraph/cayley"
-	"github.com/cayleygraph/quad"
 	"golang.org/x/net/publicsuffix"
 )
 
@@ -116,26 +115,29 @@ func (g *Graph) InEventScope(node Node, uuid string, predicates ...string) bool
 	return false
 }
 
-// EventsInScope returns the events that include all of the domain arguments.
+// EventsInScope returns the events that include any of the domain arguments.
 func (g *Graph) EventsInScope(d ...string) []string {
-	g.db.Lock()
-	defer g.db.Unlock()
-
-	var events []string
-	var domains []quad.Value
+	events := stringset.New()
 
 	for _, domain := range d {
-		domains = append(domains, quad.IRI(domain))
-	}
+		node, err := g.db.ReadNode(domain, "fqdn")
+		if err != nil {
+			continue
+		}
 
-	p := cayley.StartPath(g.db.store).Has(quad.IRI("type"), quad.String("event")).Tag("event")
-	p = p.Out(quad.IRI("domain")).Is(domains...).Back("event").Unique()
+		edges, err := g.db.ReadInEdges(node, "domain")
+		if err != nil {
+			continue
+		}
 
-	g.db.optimizedIterate(p, func(value quad.Value) {
-		events = append(events, valToStr(value))
-	})
+		for _, edge := range edges {
+			if id := g.db.NodeToID(edge.From); id != "" {
+				events.Insert(id)
+			}
+		}
+	}
 
-	return events
+	return events.Slice()
 }
 
 // EventList returns a list of event UUIDs found in the graph.
@@ -213,6 +215,42 @@ func (g *Graph) EventSubdomains(events ...string) []string {
 	return names
 }
 
+// SetEventMetadata stores the value as the key property of the event identified by the uuid parameter.
+// Any value previously stored for the key is replaced.
+func (g *Graph) SetEventMetadata(uuid, key, value string) error {
+	if key == "" || value == "" {
+		return errors.New("Graph: SetEventMetadata: Invalid arguments provided")
+	}
+
+	event, err := g.InsertEvent(uuid)
+	if err != nil {
+		return err
+	}
+
+	if properties, err := g.db.ReadProperties(event, key); err == nil {
+		for _, p := range properties {
+			g.db.DeleteProperty(event, p.Predicate, p.Value)
+		}
+	}
+
+	return g.db.InsertProperty(event, key, value)
+}
+
+// EventMetadata returns the value stored as the key property of the event identified by the uuid parameter.
+func (g *Graph) EventMetadata(uuid, key string) (string, error) {
+	event, err := g.db.ReadNode(uuid, "event")
+	if err != nil {
+		return "", err
+	}
+
+	properties, err := g.db.ReadProperties(event, key)
+	if err != nil || len(properties) == 0 {
+		return "", fmt.Errorf("Graph: EventMetadata: The event %s has no %s value", uuid, key)
+	}
+
+	return properties[0].Value, nil
+}
+
 // EventDateRange returns the date range associated with the provided event UUID.
 func (g *Graph) EventDateRange(uuid string) (time.Time, time.Time) {
 	var start, finish time.Time
@@ -231,3 +269,53 @@ func (g *Graph) EventDateRange(uuid string) (time.Time, time.Time) {
 
 	return start, finish
 }
+
+// EventStats contains the number of findings and the duration of an event.
+type EventStats struct {
+	UUID      string
+	Names     int
+	Addresses int
+	Netblocks int
+	ASNs      int
+	Sources   int
+	Start     time.Time
+	Finish    time.Time
+	Duration  time.Duration
+}
+
+// EventStats returns the counts of the names, addresses, netblocks, ASNs and data sources
+// associated with the event identified by the uuid parameter.
+func (g *Graph) EventStats(uuid string) (*EventStats, error) {
+	event, err := g.db.ReadNode(uuid, "event")
+	if err != nil {
+		return nil, fmt.Errorf("Graph: EventStats: Failed to find the event %s", uuid)
+	}
+
+	stats := &EventStats{UUID: uuid}
+	stats.Start, stats.Finish = g.EventDateRange(uuid)
+	if !stats.Start.IsZero() && stats.Finish.After(stats.Start) {
+		stats.Duration = stats.Finish.Sub(stats.Start)
+	}
+
+	for _, c := range []struct {
+		count  *int
+		ntype  string
+		hasOut string
+		preds  []string
+	}{
+		// The top-level domain nodes are the only fqdn nodes lacking the root edge
+		{count: &stats.Names, ntype: "fqdn", hasOut: "root"},
+		{count: &stats.Addresses, ntype: "ipaddr"},
+		{count: &stats.Netblocks, ntype: "netblock"},
+		{count: &stats.ASNs, ntype: "as"},
+		{count: &stats.Sources, ntype: "source", preds: []string{"used"}},
+	} {
+		n, err := g.db.CountOutNodesOfType(event, c.ntype, c.hasOut, c.preds...)
+		if err != nil {
+			return nil, err
+		}
+		*c.count = n
+	}
+
+	return stats, nil
+}
@@ -101,3 +101,65 @@ func TestEvent(t *testing.T) {
 	}
 	g.Close()
 }
+
+func TestEventMetadata(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	uuid := "ef9f9475-34ce-4c9a-9e5f-5aafd2ab4e9b"
+
+	if err := g.SetEventMetadata(uuid, "", "value"); err == nil {
+		t.Errorf("SetEventMetadata did not return an error when provided an empty key")
+	}
+	if _, err := g.EventMetadata(uuid, "sample_size"); err == nil {
+		t.Errorf("EventMetadata did not return an error for an event that does not exist")
+	}
+
+	if err := g.SetEventMetadata(uuid, "sample_size", "10"); err != nil {
+		t.Errorf("SetEventMetadata returned an error: %v", err)
+	}
+	if err := g.SetEventMetadata(uuid, "sample_size", "20"); err != nil {
+		t.Errorf("SetEventMetadata returned an error: %v", err)
+	}
+	if v, err := g.EventMetadata(uuid, "sample_size"); err != nil || v != "20" {
+		t.Errorf("EventMetadata returned %s instead of the most recent value", v)
+	}
+}
+
+func TestEventStats(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	uuid := "3c1e0c9b-0d8f-4b7e-9a5d-2f6c8e1b4a7d"
+	if _, err := g.EventStats(uuid); err == nil {
+		t.Errorf("EventStats did not fail for a missing event")
+	}
+
+	for _, name := range []string{"owasp.org", "www.owasp.org", "api.owasp.org"} {
+		if _, err := g.InsertFQDN(name, "DNS", "dns", uuid); err != nil {
+			t.Fatalf("Failed to insert the FQDN %s: %v", name, err)
+		}
+	}
+	if err := g.InsertA("www.owasp.org", "104.22.26.165", "DNS", "dns", uuid); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	if err := g.InsertAAAA("api.owasp.org", "2606:4700:10::6816:1aa5", "Crtsh", "cert", uuid); err != nil {
+		t.Fatalf("Failed to insert the AAAA record: %v", err)
+	}
+	if err := g.InsertInfrastructure(13335, "CLOUDFLARENET", "104.22.26.165", "104.22.16.0/20", "RADb", "api", uuid); err != nil {
+		t.Fatalf("Failed to insert the infrastructure: %v", err)
+	}
+	// Findings of other events are not counted
+	if _, err := g.InsertFQDN("www.example.com", "DNS", "dns", "other-event"); err != nil {
+		t.Fatalf("Failed to insert the FQDN: %v", err)
+	}
+
+	stats, err := g.EventStats(uuid)
+	if err != nil {
+		t.Fatalf("EventStats failed: %v", err)
+	}
+	if stats.Names != 3 || stats.Addresses != 2 || stats.Netblocks != 1 || stats.ASNs != 1 || stats.Sources != 3 {
+		t.Errorf("Unexpected event statistics: %+v", stats)
+	}
+	if stats.Start.IsZero() || stats.Finish.Before(stats.Start) || stats.Duration < 0 {
+		t.Errorf("Unexpected event date range: %v -> %v", stats.Start, stats.Finish)
+	}
+}
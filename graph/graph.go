@@ -10,7 +10,7 @@ import (
 
 // Graph implements the Amass network infrastructure data model.
 type Graph struct {
-	db            *CayleyGraph
+	db            GraphDatabase
 	alreadyClosed bool
 
 	// eventFinishes maintains a cache of the latest finish time for each event
@@ -20,8 +20,8 @@ type Graph struct {
 }
 
 // NewGraph accepts a graph database that stores the Graph created and maintained by the data model.
-func NewGraph(database *CayleyGraph) *Graph {
-	if database == nil {
+func NewGraph(database GraphDatabase) *Graph {
+	if isNilDatabase(database) {
 		return nil
 	}
 
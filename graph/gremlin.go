@@ -0,0 +1,709 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The vertex property holding the identifier of each node, since the vertex ids are assigned by the server.
+const gremlinIDKey = "amass_id"
+
+// The number of connections opened with the server when the pool option is not provided.
+const gremlinDefaultPoolSize = 4
+
+// The number of times a script is sent again over a new connection after the previous one was lost.
+const gremlinReconnectAttempts = 3
+
+// GremlinGraph implements the GraphDatabase interface using a TinkerPop server, such as JanusGraph or
+// Amazon Neptune, accessed with the Gremlin Server WebSocket protocol. Each node is a vertex labeled
+// with its type, edges are labeled with the predicate, and node properties are vertex properties.
+type GremlinGraph struct {
+	settings *gremlinSettings
+	// The pool of connections, where the nil entries are connected again when they are used
+	conns     chan *gremlinConn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewGremlinGraph returns a GremlinGraph connected to the server at the ws:// or wss:// URL provided.
+// The options are comma separated key=value pairs: ca provides a PEM file of the certificate authorities
+// trusted by wss:// connections, cert and key provide the PEM files of a client certificate, pool sets
+// the number of concurrent connections (Default: 4), auth=token sends the password as a bearer token
+// with the handshake, iam=true signs the handshake for Amazon Neptune IAM authentication, and region
+// names the AWS region of the Neptune cluster when it cannot be taken from the URL.
+// With IAM authentication, the username and password are the AWS access key and secret key, and the
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables are used
+// when they are missing.
+func NewGremlinGraph(rawurl, username, password, options string) (*GremlinGraph, error) {
+	s, size, err := gremlinOptions(rawurl, username, password, options)
+	if err != nil {
+		return nil, fmt.Errorf("NewGremlinGraph: %v", err)
+	}
+
+	conn, err := dialGremlin(s)
+	if err != nil {
+		return nil, fmt.Errorf("NewGremlinGraph: Failed to connect to %s: %v", rawurl, err)
+	}
+
+	g := &GremlinGraph{
+		settings: s,
+		conns:    make(chan *gremlinConn, size),
+		done:     make(chan struct{}),
+	}
+	// The remaining connections are established once the queries need them
+	g.conns <- conn
+	for i := 1; i < size; i++ {
+		g.conns <- nil
+	}
+
+	if _, err := g.query("g.V().limit(1).count()"); err != nil {
+		g.Close()
+		return nil, fmt.Errorf("NewGremlinGraph: Failed to query %s: %v", rawurl, err)
+	}
+	return g, nil
+}
+
+func gremlinOptions(rawurl, username, password, options string) (*gremlinSettings, int, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") {
+		return nil, 0, fmt.Errorf("Invalid Gremlin server URL: %s", rawurl)
+	}
+
+	s := &gremlinSettings{
+		URL:      rawurl,
+		Username: username,
+		Password: password,
+	}
+	if u.Scheme == "wss" {
+		// Amazon Neptune only accepts TLS 1.2 and later
+		s.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	var iam, token bool
+	var cert, key string
+	size := gremlinDefaultPoolSize
+	for _, opt := range strings.Split(options, ",") {
+		parts := strings.SplitN(opt, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		switch key {
+		case "ca":
+			if s.TLS == nil {
+				return nil, 0, fmt.Errorf("The ca option requires a wss:// URL")
+			}
+
+			pem, err := ioutil.ReadFile(value)
+			if err != nil {
+				return nil, 0, fmt.Errorf("Failed to read the certificate authorities: %v", err)
+			}
+
+			s.TLS.RootCAs = x509.NewCertPool()
+			if !s.TLS.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, 0, fmt.Errorf("No certificates were found in %s", value)
+			}
+		case "cert":
+			cert = value
+		case "key":
+			key = value
+		case "pool":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, 0, fmt.Errorf("Invalid pool option: %s", value)
+			}
+			size = n
+		case "auth":
+			token = value == "token"
+		case "iam":
+			iam = value == "true"
+		case "region":
+			s.Region = value
+		}
+	}
+
+	if cert != "" || key != "" {
+		if s.TLS == nil {
+			return nil, 0, fmt.Errorf("The client certificate requires a wss:// URL")
+		}
+
+		pair, err := tls.LoadX509KeyPair(cert, key)
+		if err != nil {
+			return nil, 0, fmt.Errorf("Failed to load the client certificate: %v", err)
+		}
+		s.TLS.Certificates = []tls.Certificate{pair}
+	}
+
+	if token {
+		if iam {
+			return nil, 0, fmt.Errorf("The token and IAM authentication cannot be used together")
+		}
+
+		s.Token = password
+		s.Username = ""
+		s.Password = ""
+	}
+
+	if !iam {
+		s.Region = ""
+		return s, size, nil
+	}
+
+	if s.Region == "" {
+		s.Region = neptuneRegion(u.Hostname())
+	}
+	if s.Region == "" {
+		s.Region = os.Getenv("AWS_REGION")
+	}
+	if s.Region == "" {
+		return nil, 0, fmt.Errorf("The AWS region of the Neptune cluster was not provided")
+	}
+
+	s.Creds = envAWSCredentials()
+	if username != "" {
+		s.Creds = &awsCredentials{AccessKey: username, SecretKey: password}
+	}
+	if s.Creds.AccessKey == "" || s.Creds.SecretKey == "" {
+		return nil, 0, fmt.Errorf("The AWS credentials for IAM authentication were not provided")
+	}
+	// The handshake signature replaces the authentication requested by the server
+	s.Username = ""
+	s.Password = ""
+	return s, size, nil
+}
+
+// neptuneRegion returns the region within a Neptune endpoint, such as
+// mycluster.cluster-abc123.us-east-1.neptune.amazonaws.com.
+func neptuneRegion(host string) string {
+	labels := strings.Split(host, ".")
+
+	for i := 1; i < len(labels); i++ {
+		if labels[i] == "neptune" && i+1 < len(labels) && labels[i+1] == "amazonaws" {
+			return labels[i-1]
+		}
+	}
+	return ""
+}
+
+// String implements the GraphDatabase interface.
+func (g *GremlinGraph) String() string {
+	return "gremlin"
+}
+
+// Close implements the GraphDatabase interface.
+func (g *GremlinGraph) Close() {
+	g.closeOnce.Do(func() {
+		close(g.done)
+
+		// Wait for the scripts being evaluated to release their connections
+		for i := 0; i < cap(g.conns); i++ {
+			if conn := <-g.conns; conn != nil {
+				conn.Close()
+			}
+		}
+	})
+}
+
+// query evaluates the script using a connection from the pool. When the connection was lost,
+// the script is sent again over a new connection, waiting longer before each attempt, which is
+// safe since the scripts only create the elements that are missing.
+func (g *GremlinGraph) query(script string) ([]interface{}, error) {
+	var conn *gremlinConn
+
+	select {
+	case conn = <-g.conns:
+	case <-g.done:
+		return nil, fmt.Errorf("The Gremlin graph database has been closed")
+	}
+	defer func() { g.conns <- conn }()
+
+	var err error
+	for attempt := 0; attempt <= gremlinReconnectAttempts; attempt++ {
+		if attempt > 0 {
+			t := time.NewTimer(time.Duration(1<<uint(attempt-1)) * time.Second)
+
+			select {
+			case <-t.C:
+			case <-g.done:
+				t.Stop()
+				return nil, fmt.Errorf("The Gremlin graph database has been closed")
+			}
+		}
+
+		if conn == nil {
+			if conn, err = dialGremlin(g.settings); err != nil {
+				continue
+			}
+		}
+
+		var results []interface{}
+		results, err = conn.Submit(script)
+		if _, status := err.(*gremlinStatusError); err == nil || status {
+			return results, err
+		}
+
+		conn.Close()
+		conn = nil
+	}
+	return nil, err
+}
+
+// gremlinNode returns the traversal of the vertex with the identifier provided.
+func gremlinNode(id string) string {
+	return "g.V().has(" + gremlinString(gremlinIDKey) + ", " + gremlinString(id) + ")"
+}
+
+// The projection of the edges into the predicate and the identifier at the other end.
+func gremlinEdgeProjection(end string) string {
+	return ".project('p', 'n').by(T.label).by(__." + end + "().values(" + gremlinString(gremlinIDKey) + "))"
+}
+
+// DumpGraph implements the GraphDatabase interface.
+func (g *GremlinGraph) DumpGraph() string {
+	var out strings.Builder
+
+	key := gremlinString(gremlinIDKey)
+	if results, err := g.query("g.V().has(" + key + ").project('n', 't', 'p').by(" +
+		key + ").by(T.label).by(__.valueMap())"); err == nil {
+		for _, r := range results {
+			m, _ := r.(map[string]interface{})
+			id := gremlinStringValue(m["n"])
+
+			for _, p := range gremlinProperties(gremlinStringValue(m["t"]), m["p"], nil) {
+				fmt.Fprintf(&out, "%s -> %s -> %s\n", id, p.Predicate, p.Value)
+			}
+		}
+	}
+
+	if results, err := g.query("g.E().project('f', 'p', 't').by(__.outV().values(" +
+		key + ")).by(T.label).by(__.inV().values(" + key + "))"); err == nil {
+		for _, r := range results {
+			m, _ := r.(map[string]interface{})
+
+			fmt.Fprintf(&out, "%s -> %s -> %s\n", gremlinStringValue(m["f"]),
+				gremlinStringValue(m["p"]), gremlinStringValue(m["t"]))
+		}
+	}
+
+	return out.String()
+}
+
+// NodeToID implements the GraphDatabase interface.
+func (g *GremlinGraph) NodeToID(n Node) string {
+	if id, ok := n.(string); ok {
+		return id
+	}
+	return ""
+}
+
+// AllNodesOfType implements the GraphDatabase interface.
+func (g *GremlinGraph) AllNodesOfType(ntypes ...string) ([]Node, error) {
+	script := "g.V().has(" + gremlinString(gremlinIDKey) + ")"
+	if len(ntypes) > 0 {
+		script = "g.V().hasLabel(" + gremlinStrings(ntypes) + ")"
+	}
+
+	results, err := g.query(script + ".values(" + gremlinString(gremlinIDKey) + ")")
+	if err != nil {
+		return nil, fmt.Errorf("%s: AllNodesOfType: %v", g.String(), err)
+	}
+
+	nodes := gremlinNodes(results)
+	if len(nodes) == 0 {
+		return nodes, fmt.Errorf("%s: AllNodesOfType: No nodes found", g.String())
+	}
+	return nodes, nil
+}
+
+// AllOutNodes implements the GraphDatabase interface.
+func (g *GremlinGraph) AllOutNodes(node Node) ([]Node, error) {
+	nstr := g.NodeToID(node)
+
+	results, err := g.query(gremlinNode(nstr) + ".out().dedup().values(" + gremlinString(gremlinIDKey) + ")")
+	if err != nil {
+		return nil, fmt.Errorf("%s: AllOutNodes: %v", g.String(), err)
+	}
+
+	nodes := gremlinNodes(results)
+	if len(nodes) == 0 {
+		return nodes, fmt.Errorf("%s: AllOutNodes: No nodes found that %s has out edges to", g.String(), nstr)
+	}
+	return nodes, nil
+}
+
+// InsertNode implements the GraphDatabase interface.
+func (g *GremlinGraph) InsertNode(id, ntype string) (Node, error) {
+	if id == "" || ntype == "" {
+		return nil, fmt.Errorf("%s: InsertNode: Empty required arguments", g.String())
+	}
+
+	script := gremlinNode(id) + ".fold().coalesce(__.unfold(), __.addV(" + gremlinString(ntype) +
+		").property(" + gremlinString(gremlinIDKey) + ", " + gremlinString(id) + ")).count()"
+	if _, err := g.query(script); err != nil {
+		return nil, fmt.Errorf("%s: InsertNode: %v", g.String(), err)
+	}
+	return id, nil
+}
+
+// ReadNode implements the GraphDatabase interface.
+func (g *GremlinGraph) ReadNode(id, ntype string) (Node, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%s: ReadNode: Empty node id provided", g.String())
+	}
+
+	script := gremlinNode(id)
+	if ntype != "" {
+		script += ".hasLabel(" + gremlinString(ntype) + ")"
+	}
+
+	results, err := g.query(script + ".count()")
+	if err != nil || gremlinCount(results) == 0 {
+		return nil, fmt.Errorf("%s: ReadNode: Node %s does not exist", g.String(), id)
+	}
+	return id, nil
+}
+
+// DeleteNode implements the GraphDatabase interface.
+func (g *GremlinGraph) DeleteNode(node Node) error {
+	id := g.NodeToID(node)
+	if id == "" {
+		return fmt.Errorf("%s: DeleteNode: Empty node id provided", g.String())
+	}
+
+	// Removing the vertex also removes the edges and properties
+	results, err := g.query(gremlinNode(id) + ".sideEffect(__.drop()).count()")
+	if err != nil {
+		return fmt.Errorf("%s: DeleteNode: %v", g.String(), err)
+	}
+	if gremlinCount(results) == 0 {
+		return fmt.Errorf("%s: DeleteNode: Node %s does not exist", g.String(), id)
+	}
+	return nil
+}
+
+// InsertEdge implements the GraphDatabase interface.
+func (g *GremlinGraph) InsertEdge(edge *Edge) error {
+	if edge.Predicate == "" {
+		return fmt.Errorf("%s: InsertEdge: Empty edge predicate", g.String())
+	}
+	from := g.NodeToID(edge.From)
+	if from == "" {
+		return fmt.Errorf("%s: InsertEdge: Invalid from node", g.String())
+	}
+	to := g.NodeToID(edge.To)
+	if to == "" {
+		return fmt.Errorf("%s: InsertEdge: Invalid to node", g.String())
+	}
+
+	pred := gremlinString(edge.Predicate)
+	script := gremlinNode(from) + ".as('a')." + strings.TrimPrefix(gremlinNode(to), "g.") +
+		".coalesce(__.inE(" + pred + ").where(__.outV().as('a')), __.addE(" + pred + ").from('a')).count()"
+	results, err := g.query(script)
+	if err != nil {
+		return fmt.Errorf("%s: InsertEdge: %v", g.String(), err)
+	}
+	if gremlinCount(results) == 0 {
+		return fmt.Errorf("%s: InsertEdge: Invalid from or to node", g.String())
+	}
+	return nil
+}
+
+// ReadEdges implements the GraphDatabase interface.
+func (g *GremlinGraph) ReadEdges(node Node, predicates ...string) ([]*Edge, error) {
+	var edges []*Edge
+
+	if e, err := g.ReadInEdges(node, predicates...); err == nil {
+		edges = append(edges, e...)
+	}
+
+	if e, err := g.ReadOutEdges(node, predicates...); err == nil {
+		edges = append(edges, e...)
+	}
+
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%s: ReadEdges: Failed to discover edges for the node %s", g.String(), g.NodeToID(node))
+	}
+	return edges, nil
+}
+
+// CountEdges implements the GraphDatabase interface.
+func (g *GremlinGraph) CountEdges(node Node, predicates ...string) (int, error) {
+	var count int
+
+	if c, err := g.CountInEdges(node, predicates...); err == nil {
+		count += c
+	} else {
+		return 0, fmt.Errorf("%s: CountEdges: %v", g.String(), err)
+	}
+
+	if c, err := g.CountOutEdges(node, predicates...); err == nil {
+		count += c
+	}
+	return count, nil
+}
+
+// ReadInEdges implements the GraphDatabase interface.
+func (g *GremlinGraph) ReadInEdges(node Node, predicates ...string) ([]*Edge, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return nil, fmt.Errorf("%s: ReadInEdges: Invalid node reference argument", g.String())
+	}
+
+	results, err := g.query(gremlinNode(nstr) + ".inE(" + gremlinStrings(predicates) + ")" + gremlinEdgeProjection("outV"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: ReadInEdges: %v", g.String(), err)
+	}
+
+	var edges []*Edge
+	for _, r := range results {
+		m, _ := r.(map[string]interface{})
+
+		edges = append(edges, &Edge{
+			Predicate: gremlinStringValue(m["p"]),
+			From:      gremlinStringValue(m["n"]),
+			To:        nstr,
+		})
+	}
+
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%s: ReadInEdges: Failed to discover edges coming into the node %s", g.String(), nstr)
+	}
+	return edges, nil
+}
+
+// CountInEdges implements the GraphDatabase interface.
+func (g *GremlinGraph) CountInEdges(node Node, predicates ...string) (int, error) {
+	return g.countEdges("CountInEdges", "inE", node, predicates)
+}
+
+// ReadOutEdges implements the GraphDatabase interface.
+func (g *GremlinGraph) ReadOutEdges(node Node, predicates ...string) ([]*Edge, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return nil, fmt.Errorf("%s: ReadOutEdges: Invalid node reference argument", g.String())
+	}
+
+	results, err := g.query(gremlinNode(nstr) + ".outE(" + gremlinStrings(predicates) + ")" + gremlinEdgeProjection("inV"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: ReadOutEdges: %v", g.String(), err)
+	}
+
+	var edges []*Edge
+	for _, r := range results {
+		m, _ := r.(map[string]interface{})
+
+		edges = append(edges, &Edge{
+			Predicate: gremlinStringValue(m["p"]),
+			From:      nstr,
+			To:        gremlinStringValue(m["n"]),
+		})
+	}
+
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%s: ReadOutEdges: Failed to discover edges leaving the node %s", g.String(), nstr)
+	}
+	return edges, nil
+}
+
+// CountOutEdges implements the GraphDatabase interface.
+func (g *GremlinGraph) CountOutEdges(node Node, predicates ...string) (int, error) {
+	return g.countEdges("CountOutEdges", "outE", node, predicates)
+}
+
+func (g *GremlinGraph) countEdges(method, step string, node Node, predicates []string) (int, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return 0, fmt.Errorf("%s: %s: Invalid node reference argument", g.String(), method)
+	}
+
+	// The local count is missing from the results when the node does not exist
+	results, err := g.query(gremlinNode(nstr) + ".local(__." + step + "(" + gremlinStrings(predicates) + ").count())")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s: %v", g.String(), method, err)
+	}
+	if len(results) == 0 {
+		return 0, fmt.Errorf("%s: %s: Invalid node reference argument", g.String(), method)
+	}
+	return gremlinCount(results), nil
+}
+
+// CountOutNodesOfType implements the GraphDatabase interface.
+func (g *GremlinGraph) CountOutNodesOfType(node Node, ntype, hasOut string, predicates ...string) (int, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return 0, fmt.Errorf("%s: CountOutNodesOfType: Invalid node reference argument", g.String())
+	}
+
+	inner := "__.out(" + gremlinStrings(predicates) + ").hasLabel(" + gremlinString(ntype) + ")"
+	if hasOut != "" {
+		inner += ".where(__.outE(" + gremlinString(hasOut) + "))"
+	}
+
+	results, err := g.query(gremlinNode(nstr) + ".local(" + inner + ".dedup().count())")
+	if err != nil {
+		return 0, fmt.Errorf("%s: CountOutNodesOfType: %v", g.String(), err)
+	}
+	if len(results) == 0 {
+		return 0, fmt.Errorf("%s: CountOutNodesOfType: Invalid node reference argument", g.String())
+	}
+	return gremlinCount(results), nil
+}
+
+// DeleteEdge implements the GraphDatabase interface.
+func (g *GremlinGraph) DeleteEdge(edge *Edge) error {
+	from := g.NodeToID(edge.From)
+	to := g.NodeToID(edge.To)
+	if from == "" || to == "" {
+		return fmt.Errorf("%s: DeleteEdge: Invalid edge reference argument", g.String())
+	}
+
+	script := gremlinNode(from) + ".outE(" + gremlinString(edge.Predicate) + ").where(__.inV().has(" +
+		gremlinString(gremlinIDKey) + ", " + gremlinString(to) + ")).sideEffect(__.drop()).count()"
+	results, err := g.query(script)
+	if err != nil {
+		return fmt.Errorf("%s: DeleteEdge: %v", g.String(), err)
+	}
+	if gremlinCount(results) == 0 {
+		return fmt.Errorf("%s: DeleteEdge: The edge does not exist", g.String())
+	}
+	return nil
+}
+
+// InsertProperty implements the GraphDatabase interface.
+func (g *GremlinGraph) InsertProperty(node Node, predicate, value string) error {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return fmt.Errorf("%s: InsertProperty: Invalid node reference argument", g.String())
+	} else if predicate == "" || predicate == gremlinIDKey || predicate == "type" {
+		return fmt.Errorf("%s: InsertProperty: Invalid predicate argument", g.String())
+	}
+
+	// The set cardinality keeps a single copy of each value
+	script := gremlinNode(nstr) + ".property(set, " + gremlinString(predicate) + ", " + gremlinString(value) + ").count()"
+	results, err := g.query(script)
+	if err != nil {
+		return fmt.Errorf("%s: InsertProperty: %v", g.String(), err)
+	}
+	if gremlinCount(results) == 0 {
+		return fmt.Errorf("%s: InsertProperty: Invalid node reference argument", g.String())
+	}
+	return nil
+}
+
+// ReadProperties implements the GraphDatabase interface.
+func (g *GremlinGraph) ReadProperties(node Node, predicates ...string) ([]*Property, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return nil, fmt.Errorf("%s: ReadProperties: Invalid node reference argument", g.String())
+	}
+
+	results, err := g.query(gremlinNode(nstr) + ".limit(1).project('t', 'p').by(T.label).by(__.valueMap())")
+	if err != nil || len(results) == 0 {
+		return nil, fmt.Errorf("%s: ReadProperties: Invalid node reference argument", g.String())
+	}
+
+	m, _ := results[0].(map[string]interface{})
+	return gremlinProperties(gremlinStringValue(m["t"]), m["p"], predicates), nil
+}
+
+// CountProperties implements the GraphDatabase interface.
+func (g *GremlinGraph) CountProperties(node Node, predicates ...string) (int, error) {
+	properties, err := g.ReadProperties(node, predicates...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: CountProperties: Invalid node reference argument", g.String())
+	}
+	return len(properties), nil
+}
+
+// DeleteProperty implements the GraphDatabase interface.
+func (g *GremlinGraph) DeleteProperty(node Node, predicate, value string) error {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return fmt.Errorf("%s: DeleteProperty: Invalid node reference argument", g.String())
+	}
+
+	script := gremlinNode(nstr) + ".properties(" + gremlinString(predicate) + ").hasValue(" +
+		gremlinString(value) + ").sideEffect(__.drop()).count()"
+	results, err := g.query(script)
+	if err != nil {
+		return fmt.Errorf("%s: DeleteProperty: %v", g.String(), err)
+	}
+	if gremlinCount(results) == 0 {
+		return fmt.Errorf("%s: DeleteProperty: The property does not exist on node: %s", g.String(), nstr)
+	}
+	return nil
+}
+
+// gremlinProperties converts the vertex label and the value map of a vertex into the Amass properties
+// with the predicates provided.
+func gremlinProperties(ntype string, value interface{}, predicates []string) []*Property {
+	props, _ := value.(map[string]interface{})
+
+	vals := map[string][]interface{}{"type": {ntype}}
+	for k, v := range props {
+		if k != gremlinIDKey && k != "type" {
+			vals[k] = gremlinList(v)
+		}
+	}
+
+	keys := make([]string, 0, len(vals))
+	for k := range vals {
+		if len(predicates) == 0 || containsString(predicates, k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var properties []*Property
+	for _, k := range keys {
+		for _, v := range vals[k] {
+			if s, ok := v.(string); ok {
+				properties = append(properties, &Property{Predicate: k, Value: s})
+			}
+		}
+	}
+	return properties
+}
+
+func gremlinNodes(results []interface{}) []Node {
+	var nodes []Node
+
+	for _, r := range results {
+		if id := gremlinStringValue(r); id != "" {
+			nodes = append(nodes, id)
+		}
+	}
+	return nodes
+}
+
+func gremlinStringValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return ""
+}
+
+func gremlinCount(results []interface{}) int {
+	if len(results) == 0 {
+		return 0
+	}
+
+	if n, ok := results[0].(float64); ok {
+		return int(n)
+	}
+	return 0
+}
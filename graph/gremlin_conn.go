@@ -0,0 +1,297 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/google/uuid"
+)
+
+// The GraphSON version requested from the server for the results of each script.
+const gremlinMimeType = "application/vnd.gremlin-v3.0+json"
+
+const (
+	gremlinDialTimeout    = 30 * time.Second
+	gremlinRequestTimeout = 2 * time.Minute
+)
+
+// The response status codes of the Gremlin Server protocol.
+const (
+	gremlinStatusSuccess        = 200
+	gremlinStatusNoContent      = 204
+	gremlinStatusPartialContent = 206
+	gremlinStatusAuthenticate   = 407
+)
+
+// gremlinSettings provides what is required for connecting with a Gremlin server.
+type gremlinSettings struct {
+	URL      string
+	Username string
+	Password string
+	// When Token is not empty, it is sent as a bearer token with the handshake
+	Token string
+	TLS   *tls.Config
+	// When Region is not empty, the handshake is signed for Amazon Neptune IAM authentication
+	Region string
+	Creds  *awsCredentials
+}
+
+// gremlinConn is a WebSocket connection with a Gremlin server that submits one script at a time.
+type gremlinConn struct {
+	conn     net.Conn
+	br       *bufio.Reader
+	rw       io.ReadWriter
+	username string
+	password string
+}
+
+// gremlinStatusError is returned when the server responds to a script with an error status,
+// so the connection remains usable.
+type gremlinStatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *gremlinStatusError) Error() string {
+	return fmt.Sprintf("The Gremlin server returned status %d: %s", e.Code, e.Message)
+}
+
+type gremlinRequest struct {
+	RequestID string                 `json:"requestId"`
+	Op        string                 `json:"op"`
+	Processor string                 `json:"processor"`
+	Args      map[string]interface{} `json:"args"`
+}
+
+type gremlinResponse struct {
+	RequestID string `json:"requestId"`
+	Status    struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"status"`
+	Result struct {
+		Data interface{} `json:"data"`
+	} `json:"result"`
+}
+
+func dialGremlin(s *gremlinSettings) (*gremlinConn, error) {
+	u, err := url.ParseRequestURI(s.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	d := ws.Dialer{
+		Timeout:   gremlinDialTimeout,
+		TLSConfig: s.TLS,
+	}
+	if s.Region != "" {
+		// The signed host must match the Host header written by the dialer, and each
+		// connection is signed again, since the signatures expire after five minutes
+		d.Header = ws.HandshakeHeaderHTTP(signV4("GET", u.Host, u.EscapedPath(), s.Region, "neptune-db", s.Creds, time.Now()))
+	} else if s.Token != "" {
+		d.Header = ws.HandshakeHeaderHTTP(http.Header{"Authorization": []string{"Bearer " + s.Token}})
+	}
+
+	conn, br, _, err := d.Dial(context.Background(), s.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &gremlinConn{
+		conn:     conn,
+		br:       br,
+		username: s.Username,
+		password: s.Password,
+	}
+	c.rw = struct {
+		io.Reader
+		io.Writer
+	}{Reader: conn, Writer: conn}
+	// The dialer can return data read beyond the handshake
+	if br != nil {
+		c.rw = struct {
+			io.Reader
+			io.Writer
+		}{Reader: io.MultiReader(br, conn), Writer: conn}
+	}
+	return c, nil
+}
+
+// Close releases the connection with the Gremlin server.
+func (c *gremlinConn) Close() {
+	c.conn.Close()
+	if c.br != nil {
+		ws.PutReader(c.br)
+		c.br = nil
+	}
+}
+
+// Submit evaluates the Gremlin script on the server and returns the results from all the responses.
+func (c *gremlinConn) Submit(script string) ([]interface{}, error) {
+	_ = c.conn.SetDeadline(time.Now().Add(gremlinRequestTimeout))
+	defer func() { _ = c.conn.SetDeadline(time.Time{}) }()
+
+	req := &gremlinRequest{
+		RequestID: uuid.New().String(),
+		Op:        "eval",
+		Args: map[string]interface{}{
+			"gremlin":  script,
+			"language": "gremlin-groovy",
+		},
+	}
+	if err := c.send(req); err != nil {
+		return nil, err
+	}
+
+	var results []interface{}
+	for {
+		msg, _, err := wsutil.ReadServerData(c.rw)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp gremlinResponse
+		if err := json.Unmarshal(msg, &resp); err != nil {
+			return nil, fmt.Errorf("Failed to parse the Gremlin response: %v", err)
+		}
+		if resp.RequestID != "" && resp.RequestID != req.RequestID {
+			continue
+		}
+
+		switch resp.Status.Code {
+		case gremlinStatusSuccess, gremlinStatusPartialContent:
+			results = append(results, gremlinList(gremlinValue(resp.Result.Data))...)
+			if resp.Status.Code == gremlinStatusSuccess {
+				return results, nil
+			}
+		case gremlinStatusNoContent:
+			return results, nil
+		case gremlinStatusAuthenticate:
+			if err := c.authenticate(req.RequestID); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, &gremlinStatusError{Code: resp.Status.Code, Message: resp.Status.Message}
+		}
+	}
+}
+
+// authenticate answers the challenge from the server using the SASL PLAIN mechanism.
+func (c *gremlinConn) authenticate(id string) error {
+	if c.username == "" {
+		return &gremlinStatusError{Code: gremlinStatusAuthenticate, Message: "No credentials were provided"}
+	}
+
+	return c.send(&gremlinRequest{
+		RequestID: id,
+		Op:        "authentication",
+		Args: map[string]interface{}{
+			"saslMechanism": "PLAIN",
+			"sasl":          base64.StdEncoding.EncodeToString([]byte("\x00" + c.username + "\x00" + c.password)),
+		},
+	})
+}
+
+func (c *gremlinConn) send(req *gremlinRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	// The binary frame starts with the length and type of the serialization requested
+	msg := append([]byte{byte(len(gremlinMimeType))}, gremlinMimeType...)
+	return wsutil.WriteClientBinary(c.conn, append(msg, body...))
+}
+
+// gremlinValue removes the GraphSON type information from the value, so that lists and sets
+// become slices, maps become maps keyed by strings, and numbers become float64 values.
+func gremlinValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		list := make([]interface{}, 0, len(t))
+		for _, e := range t {
+			list = append(list, gremlinValue(e))
+		}
+		return list
+	case map[string]interface{}:
+		gtype, ok := t["@type"].(string)
+		if !ok {
+			m := make(map[string]interface{}, len(t))
+			for k, e := range t {
+				m[k] = gremlinValue(e)
+			}
+			return m
+		}
+
+		value := t["@value"]
+		switch gtype {
+		case "g:Map":
+			pairs, _ := value.([]interface{})
+
+			m := make(map[string]interface{}, len(pairs)/2)
+			for i := 0; i+1 < len(pairs); i += 2 {
+				m[fmt.Sprint(gremlinValue(pairs[i]))] = gremlinValue(pairs[i+1])
+			}
+			return m
+		case "g:BulkSet":
+			pairs, _ := value.([]interface{})
+
+			var list []interface{}
+			for i := 0; i+1 < len(pairs); i += 2 {
+				e := gremlinValue(pairs[i])
+				bulk, _ := gremlinValue(pairs[i+1]).(float64)
+				for j := 0; j < int(bulk); j++ {
+					list = append(list, e)
+				}
+			}
+			return list
+		case "g:Traverser":
+			if m, ok := value.(map[string]interface{}); ok {
+				return gremlinValue(m["value"])
+			}
+		}
+		return gremlinValue(value)
+	}
+	return v
+}
+
+func gremlinList(v interface{}) []interface{} {
+	if v == nil {
+		return nil
+	}
+	if list, ok := v.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{v}
+}
+
+// gremlinString returns the string as a literal for use in Gremlin scripts. The values are
+// written into the scripts, since Amazon Neptune does not accept parameter bindings.
+func gremlinString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return "'" + r.Replace(s) + "'"
+}
+
+func gremlinStrings(strs []string) string {
+	quoted := make([]string, 0, len(strs))
+
+	for _, s := range strs {
+		quoted = append(quoted, gremlinString(s))
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,265 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+)
+
+func TestGremlinValues(t *testing.T) {
+	if s := gremlinString(`it's a\b`); s != `'it\'s a\\b'` {
+		t.Errorf("gremlinString returned %s", s)
+	}
+
+	var data interface{}
+	typed := `{"@type":"g:List","@value":[{"@type":"g:Map","@value":["p","a_record","n",{"@type":"g:Int64","@value":2}]},
+		{"@type":"g:BulkSet","@value":["owasp.org",{"@type":"g:Int64","@value":2}]}]}`
+	if err := json.Unmarshal([]byte(typed), &data); err != nil {
+		t.Fatalf("Failed to parse the GraphSON: %v", err)
+	}
+
+	list := gremlinList(gremlinValue(data))
+	if len(list) != 2 {
+		t.Fatalf("gremlinValue returned %v", list)
+	}
+	if m, ok := list[0].(map[string]interface{}); !ok || m["p"] != "a_record" || m["n"] != float64(2) {
+		t.Errorf("gremlinValue did not convert the map: %v", list[0])
+	}
+	if s, ok := list[1].([]interface{}); !ok || len(s) != 2 || s[1] != "owasp.org" {
+		t.Errorf("gremlinValue did not expand the bulk set: %v", list[1])
+	}
+
+	props := map[string]interface{}{
+		gremlinIDKey: []interface{}{"owasp.org"},
+		"tag":        []interface{}{"dns", "cert"},
+	}
+	if p := gremlinProperties("fqdn", props, nil); len(p) != 3 || p[0].Predicate != "tag" || p[2].Value != "fqdn" {
+		t.Errorf("gremlinProperties did not convert all the properties: %v", p)
+	}
+	if p := gremlinProperties("fqdn", props, []string{"type"}); len(p) != 1 || p[0].Value != "fqdn" {
+		t.Errorf("gremlinProperties did not filter the properties by predicate: %v", p)
+	}
+}
+
+func TestGremlinOptions(t *testing.T) {
+	if _, _, err := gremlinOptions("http://localhost:8182/gremlin", "", "", ""); err == nil {
+		t.Errorf("gremlinOptions accepted a URL that is not using WebSockets")
+	}
+	if _, _, err := gremlinOptions("ws://localhost:8182/gremlin", "", "", "ca=ca.pem"); err == nil {
+		t.Errorf("gremlinOptions accepted certificate authorities without TLS")
+	}
+
+	if _, _, err := gremlinOptions("ws://localhost:8182/gremlin", "", "", "pool=0"); err == nil {
+		t.Errorf("gremlinOptions accepted an empty connection pool")
+	}
+	if _, _, err := gremlinOptions("wss://localhost:8182/gremlin", "", "", "cert=missing.pem,key=missing.key"); err == nil {
+		t.Errorf("gremlinOptions accepted a missing client certificate")
+	}
+
+	s, size, err := gremlinOptions("wss://localhost:8182/gremlin", "", "secret", "auth=token, pool=8")
+	if err != nil || size != 8 || s.Token != "secret" || s.Password != "" {
+		t.Errorf("gremlinOptions did not configure the token and pool: %+v, %d, %v", s, size, err)
+	}
+
+	url := "wss://db.cluster-abc123.us-west-2.neptune.amazonaws.com:8182/gremlin"
+	if _, _, err := gremlinOptions(url, "AKIDEXAMPLE", "secret", "iam=true,auth=token"); err == nil {
+		t.Errorf("gremlinOptions accepted the token and IAM authentication together")
+	}
+
+	s, _, err = gremlinOptions(url, "AKIDEXAMPLE", "secret", "iam=true")
+	if err != nil {
+		t.Fatalf("gremlinOptions failed for the Neptune endpoint: %v", err)
+	}
+	if s.Region != "us-west-2" || s.Creds.AccessKey != "AKIDEXAMPLE" || s.Username != "" || s.TLS == nil {
+		t.Errorf("gremlinOptions returned the wrong settings for the Neptune endpoint: %+v", s)
+	}
+	if s, _, err := gremlinOptions(url, "AKIDEXAMPLE", "secret", "iam=true, region=eu-west-1"); err != nil || s.Region != "eu-west-1" {
+		t.Errorf("gremlinOptions ignored the region option: %v", err)
+	}
+	if s, _, err := gremlinOptions(url, "user", "pass", ""); err != nil || s.Region != "" || s.Username != "user" {
+		t.Errorf("gremlinOptions signed the handshake without the iam option: %v", err)
+	}
+}
+
+// gremlinTestServer answers each script with the results provided, after requiring the SASL
+// authentication of the test user, and drops the connection the first time a script ends with drop.
+func gremlinTestServer(t *testing.T, results map[string]string) *httptest.Server {
+	var lock sync.Mutex
+	dropped := make(map[string]bool)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, _, err := ws.UpgradeHTTP(r, w)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			msg, err := wsutil.ReadClientBinary(conn)
+			if err != nil {
+				return
+			}
+			if n := int(msg[0]); n >= len(msg) || string(msg[1:n+1]) != gremlinMimeType {
+				t.Errorf("The request did not start with the serialization requested")
+				return
+			}
+
+			var req gremlinRequest
+			if err := json.Unmarshal(msg[int(msg[0])+1:], &req); err != nil {
+				t.Errorf("Failed to parse the request: %v", err)
+				return
+			}
+
+			respond := func(code int, data string) {
+				resp := fmt.Sprintf(`{"requestId":"%s","status":{"code":%d,"message":""},"result":{"data":%s}}`,
+					req.RequestID, code, data)
+				_ = wsutil.WriteServerText(conn, []byte(resp))
+			}
+
+			switch req.Op {
+			case "eval":
+				script, _ := req.Args["gremlin"].(string)
+
+				lock.Lock()
+				drop := strings.HasSuffix(script, "drop()") && !dropped[script]
+				dropped[script] = true
+				lock.Unlock()
+				if drop {
+					return
+				}
+
+				if data, found := results[script]; found {
+					respond(gremlinStatusAuthenticate, "null")
+					respond(gremlinStatusPartialContent, `{"@type":"g:List","@value":["extra"]}`)
+					respond(gremlinStatusSuccess, data)
+				} else {
+					respond(597, "null")
+				}
+			case "authentication":
+				sasl, _ := req.Args["sasl"].(string)
+				if b, _ := base64.StdEncoding.DecodeString(sasl); string(b) != "\x00user\x00pass" {
+					respond(401, "null")
+				}
+			}
+		}
+	}))
+}
+
+func TestGremlinConn(t *testing.T) {
+	srv := gremlinTestServer(t, map[string]string{
+		"g.V().limit(1).count()": `{"@type":"g:List","@value":[{"@type":"g:Int64","@value":0}]}`,
+		"g.V().drop()":           `{"@type":"g:List","@value":[]}`,
+	})
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/gremlin"
+	if _, err := NewGremlinGraph(url, "", "", ""); err == nil {
+		t.Errorf("NewGremlinGraph succeeded without answering the authentication challenge")
+	}
+
+	db, err := NewGremlinGraph(url, "user", "pass", "pool=2")
+	if err != nil {
+		t.Fatalf("NewGremlinGraph failed: %v", err)
+	}
+
+	results, err := db.query("g.V().limit(1).count()")
+	if err != nil || len(results) != 2 || results[0] != "extra" || gremlinCount(results[1:]) != 0 {
+		t.Errorf("The partial responses were not combined: %v, %v", results, err)
+	}
+	if _, err := db.ReadNode("owasp.org", "fqdn"); err == nil {
+		t.Errorf("ReadNode did not return the error from the server")
+	}
+
+	// The script is sent again after the server drops the connection
+	if results, err := db.query("g.V().drop()"); err != nil || len(results) != 1 {
+		t.Errorf("The script was not sent again over a new connection: %v, %v", results, err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := db.query("g.V().limit(1).count()"); err != nil {
+				t.Errorf("The concurrent query failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	db.Close()
+	if _, err := db.query("g.V().limit(1).count()"); err == nil {
+		t.Errorf("The query succeeded after the graph database was closed")
+	}
+}
+
+func TestGremlinGraph(t *testing.T) {
+	url := os.Getenv("AMASS_GREMLIN_URL")
+	if url == "" {
+		t.Skip("AMASS_GREMLIN_URL is not set")
+	}
+
+	db, err := NewGremlinGraph(url, os.Getenv("AMASS_GREMLIN_USERNAME"),
+		os.Getenv("AMASS_GREMLIN_PASSWORD"), os.Getenv("AMASS_GREMLIN_OPTIONS"))
+	if err != nil {
+		t.Fatalf("Failed to connect to the Gremlin server: %v", err)
+	}
+	g := NewGraph(db)
+	defer g.Close()
+
+	if _, err := g.InsertEvent("gremlintest"); err != nil {
+		t.Fatalf("Failed to insert the event: %v", err)
+	}
+	if err := g.InsertA("www.gremlintest.owasp.org", "192.0.2.1", "DNS", "dns", "gremlintest"); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	defer func() {
+		for _, id := range []string{"gremlintest", "www.gremlintest.owasp.org", "owasp.org", "192.0.2.1", "DNS"} {
+			if node, err := db.ReadNode(id, ""); err == nil {
+				_ = db.DeleteNode(node)
+			}
+		}
+	}()
+
+	node, err := db.ReadNode("www.gremlintest.owasp.org", "fqdn")
+	if err != nil {
+		t.Fatalf("Failed to read the FQDN node: %v", err)
+	}
+	if n, err := db.CountOutEdges(node, "a_record"); err != nil || n != 1 {
+		t.Errorf("CountOutEdges returned %d, %v", n, err)
+	}
+	if sources, err := g.NodeSources(node, "gremlintest"); err != nil || len(sources) != 1 || sources[0] != "DNS" {
+		t.Errorf("NodeSources returned %v, %v", sources, err)
+	}
+
+	if err := db.InsertProperty(node, "open_port", "443"); err != nil {
+		t.Fatalf("Failed to insert the property: %v", err)
+	}
+	if n, err := db.CountProperties(node); err != nil || n != 2 {
+		t.Errorf("CountProperties returned %d, %v instead of the type and port", n, err)
+	}
+	if err := db.DeleteProperty(node, "open_port", "443"); err != nil {
+		t.Errorf("Failed to delete the property: %v", err)
+	}
+
+	addr, _ := db.ReadNode("192.0.2.1", "ipaddr")
+	if err := db.DeleteNode(node); err != nil {
+		t.Fatalf("Failed to delete the FQDN node: %v", err)
+	}
+	if n, err := db.CountInEdges(addr, "a_record"); err != nil || n != 0 {
+		t.Errorf("DeleteNode left %d edges pointing at the address", n)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/stringset"
+)
+
+// GroundTruthSource is the data source name used for infrastructure provided by the user.
+const GroundTruthSource = "Ground Truth"
+
+// InsertGroundTruth adds/updates an autonomous system and netblock provided by the user for each
+// of the events. The owner replaces the AS description when provided, and the netblock is marked
+// so the mapping takes precedence over data source results.
+func (g *Graph) InsertGroundTruth(asn int, cidr, owner string, eventIDs ...string) error {
+	if asn <= 0 {
+		return fmt.Errorf("Graph: InsertGroundTruth: Invalid ASN provided: %d", asn)
+	}
+
+	_, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return fmt.Errorf("Graph: InsertGroundTruth: Invalid CIDR provided: %v", err)
+	}
+	cidr = ipnet.String()
+
+	if len(eventIDs) == 0 {
+		return errors.New("Graph: InsertGroundTruth: No events provided")
+	}
+
+	asnStr := strconv.Itoa(asn)
+	if owner == "" {
+		owner = g.ReadASDescription(asnStr)
+	}
+
+	for _, eventID := range eventIDs {
+		asNode, err := g.InsertAS(asnStr, owner, GroundTruthSource, requests.RIR, eventID)
+		if err != nil {
+			return err
+		}
+
+		cidrNode, err := g.InsertNetblock(cidr, GroundTruthSource, requests.RIR, eventID)
+		if err != nil {
+			return err
+		}
+
+		if err := g.InsertEdge(&Edge{
+			Predicate: "prefix",
+			From:      asNode,
+			To:        cidrNode,
+		}); err != nil {
+			return err
+		}
+
+		// The property identifies the AS that the netblock was assigned to by the user
+		if p, err := g.db.ReadProperties(cidrNode, "ground_truth"); err == nil {
+			for _, prop := range p {
+				g.db.DeleteProperty(cidrNode, prop.Predicate, prop.Value)
+			}
+		}
+		if err := g.db.InsertProperty(cidrNode, "ground_truth", asnStr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GroundTruth returns the ASN and netblock mappings provided by the user for the events,
+// or all the events when none are specified.
+func (g *Graph) GroundTruth(events ...string) []*requests.ASNRequest {
+	var results []*requests.ASNRequest
+
+	nodes, err := g.AllNodesOfType("netblock", events...)
+	if err != nil {
+		return results
+	}
+
+	for _, node := range nodes {
+		p, err := g.db.ReadProperties(node, "ground_truth")
+		if err != nil || len(p) == 0 {
+			continue
+		}
+
+		asn, err := strconv.Atoi(p[0].Value)
+		if err != nil {
+			continue
+		}
+
+		cidr := g.db.NodeToID(node)
+		results = append(results, &requests.ASNRequest{
+			ASN:         asn,
+			Prefix:      cidr,
+			Netblocks:   stringset.New(cidr),
+			Description: g.ReadASDescription(p[0].Value),
+			Tag:         requests.RIR,
+			Source:      GroundTruthSource,
+		})
+	}
+	return results
+}
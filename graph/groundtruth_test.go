@@ -0,0 +1,37 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"testing"
+)
+
+func TestGroundTruth(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	eventID := "ef9f9475-34ff-4e15-a8e7-3d6b7c8e6f2a"
+	if err := g.InsertInfrastructure(15169, "GOOGLE", "8.8.8.8", "8.8.8.0/24", "RIR", "rir", eventID); err != nil {
+		t.Fatalf("Failed to insert the infrastructure: %v", err)
+	}
+
+	if err := g.InsertGroundTruth(64512, "8.8.8.0/24", "Example Corp"); err == nil {
+		t.Errorf("InsertGroundTruth did not return an error without events")
+	}
+	if err := g.InsertGroundTruth(64512, "8.8.8.300/24", "Example Corp", eventID); err == nil {
+		t.Errorf("InsertGroundTruth did not return an error for an invalid CIDR")
+	}
+	if err := g.InsertGroundTruth(64512, "8.8.8.1/24", "Example Corp", eventID); err != nil {
+		t.Fatalf("Failed to insert the ground truth: %v", err)
+	}
+
+	truth := g.GroundTruth(eventID)
+	if len(truth) != 1 {
+		t.Fatalf("Expected one ground truth mapping and received %d", len(truth))
+	}
+	if r := truth[0]; r.ASN != 64512 || r.Prefix != "8.8.8.0/24" ||
+		r.Description != "Example Corp" || r.Source != GroundTruthSource {
+		t.Errorf("Unexpected ground truth was returned: %+v", r)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"github.com/OWASP/Amass/v3/stringset"
+)
+
+// typedEdge is an edge leaving a node along with the type of the node it enters.
+type typedEdge struct {
+	Predicate string
+	To        Node
+	Type      string
+}
+
+// typedEdgeReader is implemented by the graph databases that can return the edges leaving a node
+// together with the types of the nodes they enter using a single query.
+type typedEdgeReader interface {
+	readTypedOutEdges(node Node) ([]*typedEdge, error)
+}
+
+// eventIndex holds the nodes associated with an event by node type, along with the predicates of
+// the edges leaving the event, so the findings of large events can be read without querying the
+// graph database for the type and the event scope of every node.
+type eventIndex struct {
+	uuid  string
+	types map[string][]Node
+	preds map[string][]string
+}
+
+// newEventIndex reads the nodes associated with the event identified by the uuid.
+func (g *Graph) newEventIndex(uuid string) *eventIndex {
+	idx := &eventIndex{
+		uuid:  uuid,
+		types: make(map[string][]Node),
+		preds: make(map[string][]string),
+	}
+
+	event, err := g.db.ReadNode(uuid, "event")
+	if err != nil {
+		return idx
+	}
+
+	var edges []*typedEdge
+	if r, ok := g.db.(typedEdgeReader); ok {
+		edges, _ = r.readTypedOutEdges(event)
+	} else {
+		edges = g.readTypedOutEdges(event)
+	}
+
+	seen := stringset.New()
+	for _, edge := range edges {
+		id := g.db.NodeToID(edge.To)
+		if id == "" {
+			continue
+		}
+
+		if key := edge.Type + "|" + id; !seen.Has(key) {
+			seen.Insert(key)
+			idx.types[edge.Type] = append(idx.types[edge.Type], edge.To)
+		}
+		if !containsString(idx.preds[id], edge.Predicate) {
+			idx.preds[id] = append(idx.preds[id], edge.Predicate)
+		}
+	}
+	return idx
+}
+
+// readTypedOutEdges reads the type of each node entered by the edges leaving the node, for the
+// graph databases that do not implement the typedEdgeReader interface.
+func (g *Graph) readTypedOutEdges(node Node) []*typedEdge {
+	edges, err := g.db.ReadOutEdges(node)
+	if err != nil {
+		return nil
+	}
+
+	types := make(map[string]string)
+	var results []*typedEdge
+	for _, edge := range edges {
+		id := g.db.NodeToID(edge.To)
+
+		t, found := types[id]
+		if !found {
+			if p, err := g.db.ReadProperties(edge.To, "type"); err == nil && len(p) > 0 {
+				t = p[0].Value
+			}
+			types[id] = t
+		}
+		if t == "" {
+			continue
+		}
+
+		results = append(results, &typedEdge{
+			Predicate: edge.Predicate,
+			To:        edge.To,
+			Type:      t,
+		})
+	}
+	return results
+}
+
+// Nodes returns the nodes of the type associated with the event.
+func (idx *eventIndex) Nodes(ntype string) []Node {
+	return idx.types[ntype]
+}
+
+// InScope returns true when the event has an edge entering the node, using one of the
+// predicates when provided, as checked by the InEventScope method of the Graph.
+func (idx *eventIndex) InScope(id string, predicates ...string) bool {
+	preds, found := idx.preds[id]
+	if !found {
+		return false
+	}
+	if len(predicates) == 0 {
+		return true
+	}
+
+	for _, pred := range predicates {
+		if containsString(preds, pred) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sources returns the names of the data sources that associated the node with the event, as
+// returned by the NodeSources method of the Graph for the event.
+func (idx *eventIndex) Sources(id string) []string {
+	var sources []string
+
+	for _, pred := range idx.preds[id] {
+		if !notDataSourceSet.Has(pred) {
+			sources = append(sources, pred)
+		}
+	}
+	return sources
+}
@@ -0,0 +1,72 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// genericDatabase hides the optional interfaces of the graph database it embeds.
+type genericDatabase struct {
+	GraphDatabase
+}
+
+func TestEventIndex(t *testing.T) {
+	db := NewCayleyGraphMemory()
+	g := NewGraph(db)
+	defer g.Close()
+
+	uuid := "ef9f9475-34ff-4e15-a8e7-3d6b7c8e6f2a"
+	other := "0a6c8f4e-4b1d-4e77-9c1f-2d3c8e0b9a11"
+	if err := g.InsertA("www.owasp.org", "192.168.1.1", "DNS", "dns", uuid); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	if err := g.InsertCNAME("ftp.owasp.org", "www.owasp.org", "Crtsh", "cert", uuid); err != nil {
+		t.Fatalf("Failed to insert the CNAME record: %v", err)
+	}
+	if err := g.InsertAAAA("api.owasp.org", "2001:db8::1", "DNS", "dns", other); err != nil {
+		t.Fatalf("Failed to insert the AAAA record: %v", err)
+	}
+	if err := g.InsertInfrastructure(26808, "Test AS", "192.168.1.1", "192.168.1.0/24", "RIR", "api", uuid); err != nil {
+		t.Fatalf("Failed to insert the infrastructure: %v", err)
+	}
+
+	for _, g := range []*Graph{g, NewGraph(&genericDatabase{GraphDatabase: db})} {
+		idx := g.newEventIndex(uuid)
+
+		var names []string
+		for _, n := range idx.Nodes("fqdn") {
+			names = append(names, g.db.NodeToID(n))
+		}
+		sort.Strings(names)
+		if !reflect.DeepEqual(names, []string{"ftp.owasp.org", "org", "owasp.org", "www.owasp.org"}) {
+			t.Errorf("The %s event index returned the names %v", g.db.String(), names)
+		}
+
+		if !idx.InScope("192.168.1.1", "DNS") || idx.InScope("192.168.1.1", "RIR") || idx.InScope("2001:db8::1") {
+			t.Errorf("The %s event index failed to report the scope of the addresses", g.db.String())
+		}
+		if !idx.InScope("192.168.1.0/24", "RIR") || !idx.InScope("26808", "RIR") {
+			t.Errorf("The %s event index failed to report the scope of the infrastructure", g.db.String())
+		}
+		if got := idx.Sources("ftp.owasp.org"); !reflect.DeepEqual(got, []string{"Crtsh"}) {
+			t.Errorf("The %s event index returned the sources %v", g.db.String(), got)
+		}
+	}
+
+	expected := g.EventOutput(uuid, nil, true, nil)
+	got := NewGraph(&genericDatabase{GraphDatabase: db}).EventOutput(uuid, nil, true, nil)
+	if len(expected) != 2 || !reflect.DeepEqual(sortedOutput(got), sortedOutput(expected)) {
+		t.Errorf("EventOutput returned %v using the fast path and %v using the generic reads", expected, got)
+	}
+}
+
+func sortedOutput(output []*requests.Output) []*requests.Output {
+	sort.Slice(output, func(i, j int) bool { return output[i].Name < output[j].Name })
+	return output
+}
@@ -7,12 +7,12 @@ import (
 	"context"
 	"net"
 	"strconv"
+	"sync"
 
 	amassnet "github.com/OWASP/Amass/v3/net"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringfilter"
 	"golang.org/x/net/publicsuffix"
-	"golang.org/x/sync/semaphore"
 )
 
 // EventOutput returns findings within the receiver Graph for the event identified by the uuid string
@@ -22,9 +22,26 @@ import (
 func (g *Graph) EventOutput(uuid string, filter stringfilter.Filter, asninfo bool, cache *amassnet.ASNCache) []*requests.Output {
 	var results []*requests.Output
 
-	names := g.getEventNameNodes(uuid)
+	_ = g.StreamEventOutput(context.Background(), uuid, filter, asninfo, cache, func(o *requests.Output) error {
+		results = append(results, o)
+		return nil
+	})
+	return results
+}
+
+// The number of findings built at the same time by StreamEventOutput
+const eventOutputWorkers = 10
+
+// StreamEventOutput provides the findings of EventOutput to the callback as each finding is built, so
+// the findings of large events are not held in memory together. The callback is executed by the calling
+// goroutine, and the streaming stops when the callback returns an error or the context is cancelled.
+// The error returned by the callback, or the error of the context, is returned by StreamEventOutput.
+func (g *Graph) StreamEventOutput(ctx context.Context, uuid string, filter stringfilter.Filter,
+	asninfo bool, cache *amassnet.ASNCache, callback func(*requests.Output) error) error {
+	idx := g.newEventIndex(uuid)
+	names := idx.Nodes("fqdn")
 	if len(names) == 0 {
-		return results
+		return nil
 	}
 
 	// Make sure a filter has been created
@@ -37,26 +54,63 @@ func (g *Graph) EventOutput(uuid string, filter stringfilter.Filter, asninfo boo
 		cache = amassnet.NewASNCache()
 	}
 
-	var count int
-	sem := semaphore.NewWeighted(10)
-	output := make(chan *requests.Output, len(names))
-	for _, name := range names {
-		if n := g.db.NodeToID(name); n == "" || filter.Has(n) {
-			continue
-		}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		sem.Acquire(context.TODO(), 1)
-		go g.buildOutput(name, uuid, asninfo, cache, output, sem)
-		count++
-	}
+	jobs := make(chan Node, eventOutputWorkers)
+	go func() {
+		defer close(jobs)
 
-	for i := 0; i < count; i++ {
-		if o := <-output; o != nil && !filter.Duplicate(o.Name) {
-			results = append(results, o)
+		for _, name := range names {
+			if n := g.db.NodeToID(name); n == "" || filter.Has(n) {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- name:
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	output := make(chan *requests.Output, eventOutputWorkers)
+	for i := 0; i < eventOutputWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for name := range jobs {
+				if o := g.buildOutput(name, idx, asninfo, cache); o != nil {
+					output <- o
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(output)
+	}()
+
+	var err error
+	// The output channel is drained after an error, so the workers are able to finish
+	for o := range output {
+		if err == nil && !filter.Duplicate(o.Name) {
+			if err = callback(o); err != nil {
+				cancel()
+			}
 		}
 	}
 
-	return results
+	if err == nil {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		default:
+		}
+	}
+	return err
 }
 
 // EventNames returns findings within the receiver Graph for the event identified by the uuid string
@@ -64,7 +118,8 @@ func (g *Graph) EventOutput(uuid string, filter stringfilter.Filter, asninfo boo
 func (g *Graph) EventNames(uuid string, filter stringfilter.Filter) []*requests.Output {
 	var results []*requests.Output
 
-	names := g.getEventNameNodes(uuid)
+	idx := g.newEventIndex(uuid)
+	names := idx.Nodes("fqdn")
 	if len(names) == 0 {
 		return results
 	}
@@ -75,7 +130,7 @@ func (g *Graph) EventNames(uuid string, filter stringfilter.Filter) []*requests.
 	}
 
 	for _, name := range names {
-		if o := g.buildNameInfo(name, uuid); o != nil && !filter.Duplicate(o.Name) {
+		if o := g.buildNameInfo(name, idx); o != nil && !filter.Duplicate(o.Name) {
 			results = append(results, o)
 		}
 	}
@@ -83,57 +138,26 @@ func (g *Graph) EventNames(uuid string, filter stringfilter.Filter) []*requests.
 	return results
 }
 
-func (g *Graph) getEventNameNodes(uuid string) []Node {
-	var names []Node
-
-	event, err := g.db.ReadNode(uuid, "event")
-	if err != nil {
-		return names
-	}
-
-	edges, err := g.db.ReadOutEdges(event)
-	if err != nil {
-		return names
-	}
-
-	for _, edge := range edges {
-		p, err := g.db.ReadProperties(edge.To, "type")
-
-		if err != nil || len(p) == 0 || p[0].Value != "fqdn" {
-			continue
-		}
-
-		names = append(names, edge.To)
-	}
-
-	return names
-}
-
-func (g *Graph) buildOutput(sub Node, uuid string, asninfo bool,
-	cache *amassnet.ASNCache, c chan *requests.Output, sem *semaphore.Weighted) {
-	defer sem.Release(1)
-
-	output := g.buildNameInfo(sub, uuid)
+func (g *Graph) buildOutput(sub Node, idx *eventIndex, asninfo bool, cache *amassnet.ASNCache) *requests.Output {
+	output := g.buildNameInfo(sub, idx)
 	if output == nil {
-		c <- nil
-		return
+		return nil
 	}
 
 	addrs, err := g.NameToAddrs(sub)
 	if err != nil {
-		c <- nil
-		return
+		return nil
 	}
 
 	var num int
 	addrChan := make(chan *requests.AddressInfo, 100)
 	for _, addr := range addrs {
-		if !g.InEventScope(addr, uuid, "DNS") {
+		if !idx.InScope(g.db.NodeToID(addr), "DNS") {
 			continue
 		}
 
 		num++
-		go g.buildAddrInfo(addr, uuid, asninfo, cache, addrChan)
+		go g.buildAddrInfo(addr, idx, asninfo, cache, addrChan)
 	}
 
 	for i := 0; i < num; i++ {
@@ -145,18 +169,16 @@ func (g *Graph) buildOutput(sub Node, uuid string, asninfo bool,
 	}
 
 	if len(output.Addresses) == 0 {
-		c <- nil
-		return
+		return nil
 	}
-
-	c <- output
+	return output
 }
 
-func (g *Graph) buildNameInfo(sub Node, uuid string) *requests.Output {
+func (g *Graph) buildNameInfo(sub Node, idx *eventIndex) *requests.Output {
 	substr := g.db.NodeToID(sub)
 
-	sources, err := g.NodeSources(sub, uuid)
-	if err != nil {
+	sources := idx.Sources(substr)
+	if len(sources) == 0 {
 		return nil
 	}
 
@@ -173,13 +195,13 @@ func (g *Graph) buildNameInfo(sub Node, uuid string) *requests.Output {
 	}
 }
 
-func (g *Graph) buildAddrInfo(addr Node, uuid string, asninfo bool, cache *amassnet.ASNCache, c chan *requests.AddressInfo) {
-	if !g.InEventScope(addr, uuid, "DNS") {
+func (g *Graph) buildAddrInfo(addr Node, idx *eventIndex, asninfo bool, cache *amassnet.ASNCache, c chan *requests.AddressInfo) {
+	address := g.db.NodeToID(addr)
+	if !idx.InScope(address, "DNS") {
 		c <- nil
 		return
 	}
 
-	address := g.db.NodeToID(addr)
 	ainfo := &requests.AddressInfo{Address: net.ParseIP(address)}
 	// Check if this request is just for the address
 	if !asninfo {
@@ -211,7 +233,7 @@ func (g *Graph) buildAddrInfo(addr Node, uuid string, asninfo bool, cache *amass
 	var cidr string
 	var cidrNode Node
 	for _, edge := range edges {
-		if g.InEventScope(edge.From, uuid, "RIR") {
+		if idx.InScope(g.db.NodeToID(edge.From), "RIR") {
 			cidrNode = edge.From
 			cidr = g.db.NodeToID(edge.From)
 			break
@@ -235,7 +257,7 @@ func (g *Graph) buildAddrInfo(addr Node, uuid string, asninfo bool, cache *amass
 	var asn string
 	var asNode Node
 	for _, edge := range edges {
-		if g.InEventScope(edge.From, uuid, "RIR") {
+		if idx.InScope(g.db.NodeToID(edge.From), "RIR") {
 			asNode = edge.From
 			asn = g.db.NodeToID(edge.From)
 			break
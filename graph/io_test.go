@@ -1,7 +1,12 @@
 package graph
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
 )
 
 func TestIO(t *testing.T) {
@@ -24,3 +29,48 @@ func TestIO(t *testing.T) {
 	}
 
 }
+
+func TestStreamEventOutput(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	uuid := "ef9f9475-34ff-4e15-a8e7-3d6b7c8e6f2a"
+	for i := 0; i < 50; i++ {
+		if err := g.InsertA(fmt.Sprintf("host%d.owasp.org", i), fmt.Sprintf("192.168.1.%d", i+1), "DNS", "dns", uuid); err != nil {
+			t.Fatalf("Failed to insert the A record: %v", err)
+		}
+	}
+
+	names := make(map[string]struct{})
+	err := g.StreamEventOutput(context.Background(), uuid, nil, false, nil, func(o *requests.Output) error {
+		names[o.Name] = struct{}{}
+		return nil
+	})
+	if err != nil || len(names) != 50 {
+		t.Errorf("StreamEventOutput provided %d names and returned the error %v", len(names), err)
+	}
+	if got := g.EventOutput(uuid, nil, false, nil); len(got) != len(names) {
+		t.Errorf("EventOutput returned %d names and StreamEventOutput provided %d", len(got), len(names))
+	}
+
+	var count int
+	stop := errors.New("stop")
+	err = g.StreamEventOutput(context.Background(), uuid, nil, false, nil, func(o *requests.Output) error {
+		count++
+		if count == 5 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || count != 5 {
+		t.Errorf("StreamEventOutput continued after the callback failed: %d names and the error %v", count, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := g.StreamEventOutput(ctx, uuid, nil, false, nil, func(o *requests.Output) error {
+		return nil
+	}); err == nil {
+		t.Errorf("StreamEventOutput did not report the cancelled context")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// InsertLiveness records the outcome of the liveness probes sent to the address as properties of the ipaddr node.
+// The times of the most recent probe and response are kept, so results merged from other graphs remain ordered.
+func (g *Graph) InsertLiveness(addr string, alive bool, ports []int, probed time.Time) error {
+	if addr == "" {
+		return errors.New("Graph: InsertLiveness: Invalid address provided")
+	}
+
+	// The probe can complete before the address has been entered by the DNS record
+	node, err := g.InsertNodeIfNotExist(addr, "ipaddr")
+	if err != nil {
+		return err
+	}
+
+	// Results of older probes, such as those merged from other graphs, do not replace the recent ones
+	if latest, err := g.latestTime(node, "probed"); err == nil && latest.After(probed) {
+		return nil
+	}
+	if err := g.replaceLatestTime(node, "probed", probed); err != nil {
+		return err
+	}
+
+	// The open ports are replaced with those reported by this probe
+	if properties, err := g.db.ReadProperties(node, "open_port"); err == nil {
+		for _, p := range properties {
+			if err := g.db.DeleteProperty(node, p.Predicate, p.Value); err != nil {
+				return err
+			}
+		}
+	}
+	if !alive {
+		return nil
+	}
+	if err := g.replaceLatestTime(node, "alive", probed); err != nil {
+		return err
+	}
+
+	for _, port := range ports {
+		if err := g.db.InsertProperty(node, "open_port", strconv.Itoa(port)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddressLiveness returns true for alive when the most recent liveness probe of the address received
+// a response, and true for probed when the address has been probed at all.
+func (g *Graph) AddressLiveness(addr string) (alive, probed bool) {
+	node, err := g.db.ReadNode(addr, "ipaddr")
+	if err != nil {
+		return false, false
+	}
+
+	last, err := g.latestTime(node, "probed")
+	if err != nil {
+		return false, false
+	}
+
+	responded, err := g.latestTime(node, "alive")
+	if err != nil {
+		return false, true
+	}
+
+	return !responded.Before(last), true
+}
+
+// AddressOpenPorts returns the TCP ports that accepted connections during the most recent liveness probe of the address.
+func (g *Graph) AddressOpenPorts(addr string) []int {
+	var ports []int
+
+	node, err := g.db.ReadNode(addr, "ipaddr")
+	if err != nil {
+		return ports
+	}
+
+	properties, err := g.db.ReadProperties(node, "open_port")
+	if err != nil {
+		return ports
+	}
+
+	for _, p := range properties {
+		if port, err := strconv.Atoi(p.Value); err == nil {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+func (g *Graph) replaceLatestTime(node Node, predicate string, t time.Time) error {
+	if latest, err := g.latestTime(node, predicate); err == nil && latest.After(t) {
+		return nil
+	}
+
+	if properties, err := g.db.ReadProperties(node, predicate); err == nil {
+		for _, p := range properties {
+			g.db.DeleteProperty(node, p.Predicate, p.Value)
+		}
+	}
+
+	return g.db.InsertProperty(node, predicate, t.UTC().Format(time.RFC3339))
+}
+
+func (g *Graph) latestTime(node Node, predicate string) (time.Time, error) {
+	var latest time.Time
+
+	properties, err := g.db.ReadProperties(node, predicate)
+	if err != nil {
+		return latest, err
+	}
+
+	for _, p := range properties {
+		if t, err := time.Parse(time.RFC3339, p.Value); err == nil && t.After(latest) {
+			latest = t
+		}
+	}
+	if latest.IsZero() {
+		return latest, fmt.Errorf("Graph: The node has no %s property", predicate)
+	}
+	return latest, nil
+}
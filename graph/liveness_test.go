@@ -0,0 +1,62 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLiveness(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	addr := "192.168.1.1"
+	uuid := "ef9f9475-34ce-4c9a-9e5f-5aafd2ab4e9b"
+	if err := g.InsertLiveness("", true, []int{443}, time.Now()); err == nil {
+		t.Errorf("InsertLiveness did not return an error when provided an empty address")
+	}
+	if _, err := g.InsertAddress(addr, "test", "dns", uuid); err != nil {
+		t.Fatalf("Failed to insert the address: %v", err)
+	}
+	if _, probed := g.AddressLiveness(addr); probed {
+		t.Errorf("AddressLiveness reported an address as probed before any probes")
+	}
+
+	first := time.Now().Add(-time.Hour)
+	if err := g.InsertLiveness(addr, true, []int{443}, first); err != nil {
+		t.Errorf("InsertLiveness returned an error: %v", err)
+	}
+	if alive, probed := g.AddressLiveness(addr); !alive || !probed {
+		t.Errorf("AddressLiveness failed to report the address as alive")
+	}
+	if ports := g.AddressOpenPorts(addr); len(ports) != 1 || ports[0] != 443 {
+		t.Errorf("AddressOpenPorts returned %v instead of the open port", ports)
+	}
+
+	second := first.Add(time.Minute)
+	if err := g.InsertLiveness(addr, true, []int{80}, second); err != nil {
+		t.Errorf("InsertLiveness returned an error: %v", err)
+	}
+	if ports := g.AddressOpenPorts(addr); len(ports) != 1 || ports[0] != 80 {
+		t.Errorf("AddressOpenPorts returned %v instead of the port from the most recent probe", ports)
+	}
+	// The results of an older probe must not replace the ports
+	if err := g.InsertLiveness(addr, true, []int{443}, first); err != nil {
+		t.Errorf("InsertLiveness returned an error: %v", err)
+	}
+	if ports := g.AddressOpenPorts(addr); len(ports) != 1 || ports[0] != 80 {
+		t.Errorf("AddressOpenPorts returned %v after an older probe was inserted", ports)
+	}
+
+	if err := g.InsertLiveness(addr, false, nil, time.Now()); err != nil {
+		t.Errorf("InsertLiveness returned an error: %v", err)
+	}
+	if alive, probed := g.AddressLiveness(addr); alive || !probed {
+		t.Errorf("AddressLiveness reported the address as alive after the most recent probe failed")
+	}
+	if ports := g.AddressOpenPorts(addr); len(ports) != 0 {
+		t.Errorf("AddressOpenPorts returned %v after the most recent probe failed", ports)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"errors"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// InsertLookalike adds the domain name resembling info.Of to the graph, along with the
+// registrar and creation date as properties of the fqdn node.
+func (g *Graph) InsertLookalike(name string, info *requests.LookalikeInfo, source, eventID string) error {
+	if name == "" || info == nil || info.Of == "" {
+		return errors.New("Graph: InsertLookalike: Invalid lookalike domain provided")
+	}
+
+	node, err := g.InsertFQDN(name, source, requests.LOOKALIKE, eventID)
+	if err != nil {
+		return err
+	}
+
+	ofNode, err := g.InsertNodeIfNotExist(info.Of, "fqdn")
+	if err != nil {
+		return err
+	}
+
+	if err := g.InsertEdge(&Edge{
+		Predicate: "lookalike_of",
+		From:      node,
+		To:        ofNode,
+	}); err != nil {
+		return err
+	}
+
+	var created string
+	if !info.Created.IsZero() {
+		created = info.Created.UTC().Format(time.RFC3339)
+	}
+
+	for pred, value := range map[string]string{
+		"registrar":  info.Registrar,
+		"registered": created,
+	} {
+		if value == "" {
+			continue
+		}
+
+		if p, err := g.db.ReadProperties(node, pred); err == nil {
+			for _, prop := range p {
+				if err := g.db.DeleteProperty(node, pred, prop.Value); err != nil {
+					return err
+				}
+			}
+		}
+		if err := g.db.InsertProperty(node, pred, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EventLookalikes returns the registration details of the lookalike domains discovered during the events.
+func (g *Graph) EventLookalikes(events ...string) map[string]*requests.LookalikeInfo {
+	results := make(map[string]*requests.LookalikeInfo)
+
+	nodes, err := g.AllNodesOfType("fqdn", events...)
+	if err != nil {
+		return results
+	}
+
+	for _, node := range nodes {
+		edges, err := g.db.ReadOutEdges(node, "lookalike_of")
+		if err != nil || len(edges) == 0 {
+			continue
+		}
+
+		info := &requests.LookalikeInfo{Of: g.db.NodeToID(edges[0].To)}
+		if p, err := g.db.ReadProperties(node, "registrar", "registered"); err == nil {
+			for _, prop := range p {
+				switch prop.Predicate {
+				case "registrar":
+					info.Registrar = prop.Value
+				case "registered":
+					if t, err := time.Parse(time.RFC3339, prop.Value); err == nil {
+						info.Created = t
+					}
+				}
+			}
+		}
+
+		results[g.db.NodeToID(node)] = info
+	}
+	return results
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestEventLookalikes(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	eventID := "c1a4b8f0-5d3e-4f7a-9b2c-6e8d0f1a2b3c"
+	if _, err := g.InsertEvent(eventID); err != nil {
+		t.Fatalf("Failed to insert the event: %v", err)
+	}
+	if _, err := g.InsertFQDN("owasp.org", "DNS", "dns", eventID); err != nil {
+		t.Fatalf("Failed to insert the FQDN: %v", err)
+	}
+
+	created := time.Date(2019, time.March, 4, 10, 30, 0, 0, time.UTC)
+	if err := g.InsertLookalike("0wasp.org", &requests.LookalikeInfo{
+		Of:        "owasp.org",
+		Registrar: "Example Registrar, Inc.",
+		Created:   created,
+	}, "Lookalike", eventID); err != nil {
+		t.Fatalf("Failed to insert the lookalike domain: %v", err)
+	}
+	if err := g.InsertLookalike("owasp.com", &requests.LookalikeInfo{Of: "owasp.org"}, "Lookalike", eventID); err != nil {
+		t.Fatalf("Failed to insert the lookalike domain: %v", err)
+	}
+	if err := g.InsertLookalike("owasp.net", &requests.LookalikeInfo{}, "Lookalike", eventID); err == nil {
+		t.Errorf("InsertLookalike accepted a lookalike without the original domain")
+	}
+
+	results := g.EventLookalikes(eventID)
+	if len(results) != 2 {
+		t.Fatalf("EventLookalikes returned %d domains instead of 2: %v", len(results), results)
+	}
+
+	info, found := results["0wasp.org"]
+	if !found || info.Of != "owasp.org" || info.Registrar != "Example Registrar, Inc." || !info.Created.Equal(created) {
+		t.Errorf("Unexpected lookalike information: %+v", info)
+	}
+	if info, found := results["owasp.com"]; !found || info.Registrar != "" || !info.Created.IsZero() {
+		t.Errorf("Unexpected lookalike information: %+v", info)
+	}
+
+	if node, err := g.db.ReadNode("0wasp.org", "fqdn"); err != nil {
+		t.Errorf("The lookalike domain was not inserted as an fqdn node")
+	} else if srcs, err := g.NodeSources(node, eventID); err != nil || len(srcs) == 0 {
+		t.Errorf("The lookalike domain was not associated with the event")
+	}
+}
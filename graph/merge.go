@@ -0,0 +1,131 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OWASP/Amass/v3/stringset"
+)
+
+// MergeEvents consolidates the events identified by the uuids into the event identified by the into
+// parameter, which can be one of the merged events or a new identifier. The consolidated event is
+// associated with every finding and data source of the merged events, starts at the earliest start
+// and finishes at the latest finish. The metadata of the first event providing each key is kept,
+// and the merged events are removed from the graph afterwards.
+func (g *Graph) MergeEvents(into string, uuids ...string) error {
+	if into == "" {
+		return errors.New("Graph: MergeEvents: Invalid event identifier provided")
+	}
+
+	events := stringset.New()
+	var ordered []string
+	for _, uuid := range uuids {
+		if uuid != into && !events.Has(uuid) {
+			events.Insert(uuid)
+			ordered = append(ordered, uuid)
+		}
+	}
+
+	var start, finish time.Time
+	// An existing event receiving the others counts as one of the merged events
+	if _, err := g.db.ReadNode(into, "event"); err == nil {
+		start, finish = g.EventDateRange(into)
+	} else if len(ordered) == 1 {
+		ordered = nil
+	}
+	if len(ordered) == 0 {
+		return errors.New("Graph: MergeEvents: At least two events must be merged")
+	}
+
+	var sources []Node
+
+	for _, uuid := range ordered {
+		event, err := g.db.ReadNode(uuid, "event")
+		if err != nil {
+			return fmt.Errorf("Graph: MergeEvents: Failed to find the event %s", uuid)
+		}
+		sources = append(sources, event)
+
+		s, f := g.EventDateRange(uuid)
+		if start.IsZero() || (!s.IsZero() && s.Before(start)) {
+			start = s
+		}
+		if f.After(finish) {
+			finish = f
+		}
+	}
+
+	g.eventFinishLock.Lock()
+	defer g.eventFinishLock.Unlock()
+
+	target, err := g.db.InsertNode(into, "event")
+	if err != nil {
+		return fmt.Errorf("Graph: MergeEvents: Failed to create the event %s: %v", into, err)
+	}
+
+	keys := stringset.New("id", "type", "start", "finish")
+	if properties, err := g.db.ReadProperties(target); err == nil {
+		for _, p := range properties {
+			keys.Insert(p.Predicate)
+		}
+	}
+
+	for _, event := range sources {
+		if properties, err := g.db.ReadProperties(event); err == nil {
+			provided := stringset.New()
+
+			for _, p := range properties {
+				if keys.Has(p.Predicate) {
+					continue
+				}
+				if err := g.db.InsertProperty(target, p.Predicate, p.Value); err != nil {
+					return fmt.Errorf("Graph: MergeEvents: Failed to copy the %s metadata: %v", p.Predicate, err)
+				}
+				provided.Insert(p.Predicate)
+			}
+			keys.Union(provided)
+		}
+
+		edges, err := g.db.ReadOutEdges(event)
+		if err != nil {
+			continue
+		}
+		// Edges already held by the consolidated event are not duplicated by the graph database
+		for _, edge := range edges {
+			if err := g.db.InsertEdge(&Edge{
+				Predicate: edge.Predicate,
+				From:      target,
+				To:        edge.To,
+			}); err != nil {
+				return fmt.Errorf("Graph: MergeEvents: Failed to copy the %s edge: %v", edge.Predicate, err)
+			}
+		}
+	}
+
+	for pred, t := range map[string]time.Time{"start": start, "finish": finish} {
+		if p, err := g.db.ReadProperties(target, pred); err == nil {
+			for _, prop := range p {
+				if err := g.db.DeleteProperty(target, pred, prop.Value); err != nil {
+					return err
+				}
+			}
+		}
+		if err := g.db.InsertProperty(target, pred, t.Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
+	g.eventFinishes[into] = finish.Format(time.RFC3339)
+
+	for i, event := range sources {
+		delete(g.eventFinishes, ordered[i])
+
+		if err := g.db.DeleteNode(event); err != nil {
+			return fmt.Errorf("Graph: MergeEvents: Failed to delete the event %s: %v", ordered[i], err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeEvents(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	g := NewGraph(NewCayleyGraphMemory())
+
+	insertDatedEvent(t, g, "first", "www.owasp.org", now.Add(-2*time.Hour))
+	insertDatedEvent(t, g, "second", "mail.owasp.org", now.Add(-time.Hour))
+	insertDatedEvent(t, g, "third", "www.owasp.org", now)
+	insertDatedEvent(t, g, "other", "www.example.com", now)
+	if err := g.SetEventMetadata("second", "sample_size", "10"); err != nil {
+		t.Fatalf("Failed to set the event metadata: %v", err)
+	}
+
+	if err := g.MergeEvents("merged", "first"); err == nil {
+		t.Errorf("MergeEvents accepted a single event")
+	}
+	if err := g.MergeEvents("merged", "first", "missing"); err == nil {
+		t.Errorf("MergeEvents accepted a missing event")
+	}
+	if err := g.MergeEvents("merged", "first", "second", "third"); err != nil {
+		t.Fatalf("MergeEvents failed: %v", err)
+	}
+
+	if !checkTestResult([]string{"merged", "other"}, g.EventList()) {
+		t.Errorf("The events remaining after the merge were %v", g.EventList())
+	}
+	if names := g.EventFQDNs("merged"); !checkTestResult([]string{"www.owasp.org", "mail.owasp.org", "owasp.org", "org"}, names) {
+		t.Errorf("The merged event provided the names %v", names)
+	}
+	if start, finish := g.EventDateRange("merged"); !start.Equal(now.Add(-2*time.Hour)) || !finish.Equal(now) {
+		t.Errorf("The merged event has the date range %v -> %v", start, finish)
+	}
+	if size, err := g.EventMetadata("merged", "sample_size"); err != nil || size != "10" {
+		t.Errorf("The merged event did not keep the metadata: %s %v", size, err)
+	}
+	if sources := g.EventSources("merged"); len(sources) != 1 || sources[0] != "DNS" {
+		t.Errorf("The merged event provided the data sources %v", sources)
+	}
+
+	// Merging into an existing event keeps its findings
+	if err := g.MergeEvents("other", "merged"); err != nil {
+		t.Fatalf("MergeEvents failed to merge into an existing event: %v", err)
+	}
+	if names := g.EventFQDNs("other"); len(names) != 7 {
+		t.Errorf("The existing event provided the names %v after the merge", names)
+	}
+	if start, _ := g.EventDateRange("other"); !start.Equal(now.Add(-2 * time.Hour)) {
+		t.Errorf("The existing event did not obtain the earliest start: %v", start)
+	}
+}
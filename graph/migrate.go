@@ -6,71 +6,468 @@ package graph
 import (
 	"context"
 	"errors"
+	"sync"
 
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/requests"
 	"github.com/cayleygraph/cayley"
 	"github.com/cayleygraph/cayley/graph"
 	"github.com/cayleygraph/cayley/writer"
 	"github.com/cayleygraph/quad"
 )
 
+// MigrateBatchSize is the number of quads, or of nodes and edges, written to the destination graph at a time.
+const MigrateBatchSize = 1000
+
+// The number of events read from the source graph at the same time during a migration.
+const migrateWorkers = 10
+
+// MigrateProgress is published on the requests.MigrateProgressTopic after each batch of the migration is written.
+type MigrateProgress struct {
+	// Graph describes the destination graph database
+	Graph string
+	// Written is the number of quads, or of nodes and edges, written so far
+	Written int
+	Total   int
+}
+
 // MigrateEvents copies the nodes and edges related to the Events identified by the uuids from the receiver Graph into another.
 func (g *Graph) MigrateEvents(to *Graph, uuids ...string) error {
-	g.db.Lock()
-	defer g.db.Unlock()
+	return g.MigrateEventsWithContext(context.Background(), to, uuids...)
+}
+
+// MigrateEventsWithContext copies the Events like MigrateEvents. The events are read concurrently, and the data
+// of all the events is then written in shared batches of MigrateBatchSize. The progress is published on the
+// event bus held by the context, and the migration stops when the context is cancelled, leaving the batches
+// already written in the destination graph.
+func (g *Graph) MigrateEventsWithContext(ctx context.Context, to *Graph, uuids ...string) error {
+	progress := newMigrateReporter(ctx, to)
+
+	// The quads can be copied directly between Cayley stores
+	if from, ok := g.db.(*CayleyGraph); ok {
+		if dest, ok := to.db.(*CayleyGraph); ok {
+			return from.migrateEvents(ctx, dest, progress, uuids...)
+		}
+	}
+
+	return g.copyEvents(ctx, to, progress, uuids...)
+}
+
+type migrateReporter struct {
+	bus     *eventbus.EventBus
+	graph   string
+	written int
+	total   int
+}
+
+func newMigrateReporter(ctx context.Context, to *Graph) *migrateReporter {
+	r := &migrateReporter{graph: to.String()}
+
+	if b := ctx.Value(requests.ContextEventBus); b != nil {
+		r.bus = b.(*eventbus.EventBus)
+	}
+	return r
+}
+
+func (r *migrateReporter) add(n int) {
+	r.written += n
 
-	var events []quad.Value
-	for _, event := range uuids {
-		events = append(events, quad.IRI(event))
+	if r.bus != nil {
+		r.bus.Publish(requests.MigrateProgressTopic, eventbus.PriorityHigh, &MigrateProgress{
+			Graph:   r.graph,
+			Written: r.written,
+			Total:   r.total,
+		})
 	}
+}
+
+// readConcurrently executes the read function for each event using the number of workers. The reads stop
+// after the first error, which is returned, or when the context is cancelled.
+func readConcurrently(ctx context.Context, workers int, uuids []string, read func(ctx context.Context, uuid string) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
+	jobs := make(chan string, workers)
+	go func() {
+		defer close(jobs)
+
+		for _, uuid := range uuids {
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- uuid:
+			}
+		}
+	}()
+
+	var err error
+	var once sync.Once
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for uuid := range jobs {
+				if e := read(ctx, uuid); e != nil {
+					once.Do(func() {
+						err = e
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
+
+func (g *CayleyGraph) migrateEvents(ctx context.Context, to *CayleyGraph, progress *migrateReporter, uuids ...string) error {
+	g.Lock()
+	defer g.Unlock()
+
+	var lock sync.Mutex
 	var quads []quad.Quad
-	var vals []quad.Value
-	// Build quads for the events in scope
-	p := cayley.StartPath(g.db.store, events...).Has(quad.IRI("type"), quad.String("event"))
-	p = p.Tag("subject").OutWithTags([]string{"predicate"}).Tag("object")
-	p.Iterate(context.TODO()).TagValues(nil, func(m map[string]quad.Value) {
-		vals = append(vals, m["object"])
-		quads = append(quads, quad.Make(m["subject"], m["predicate"], m["object"], nil))
-	})
-	// Build quads for all nodes associated with the events in scope
-	p = cayley.StartPath(g.db.store, vals...).Has(quad.IRI("type")).Unique()
-	p = p.Tag("subject").OutWithTags([]string{"predicate"}).Tag("object")
-	p.Iterate(context.TODO()).TagValues(nil, func(m map[string]quad.Value) {
-		quads = append(quads, quad.Make(m["subject"], m["predicate"], m["object"], nil))
+	// The nodes shared by several events are only read by the first worker to reach them
+	claimed := make(map[quad.Value]struct{})
+
+	workers := migrateWorkers
+	// Iterating the memory store modifies its state, so it is not queried concurrently
+	if g.name == "memory" {
+		workers = 1
+	}
+
+	err := readConcurrently(ctx, workers, uuids, func(ctx context.Context, uuid string) error {
+		var vals []quad.Value
+		var eventQuads []quad.Quad
+		// Build quads for the event
+		p := cayley.StartPath(g.store, quad.IRI(uuid)).Has(quad.IRI("type"), quad.String("event"))
+		p = p.Tag("subject").OutWithTags([]string{"predicate"}).Tag("object")
+		if err := p.Iterate(ctx).TagValues(nil, func(m map[string]quad.Value) {
+			vals = append(vals, m["object"])
+			eventQuads = append(eventQuads, quad.Make(m["subject"], m["predicate"], m["object"], nil))
+		}); err != nil {
+			return err
+		}
+
+		var fresh []quad.Value
+		lock.Lock()
+		for _, v := range vals {
+			if _, found := claimed[v]; !found {
+				claimed[v] = struct{}{}
+				fresh = append(fresh, v)
+			}
+		}
+		lock.Unlock()
+
+		// Build quads for the nodes associated with the event. Filtering the nodes by the presence of a
+		// type compares each typed node with all the values, so the values are traversed directly
+		if len(fresh) > 0 {
+			p = cayley.StartPath(g.store, fresh...).Tag("subject").OutWithTags([]string{"predicate"}).Tag("object")
+			if err := p.Iterate(ctx).TagValues(nil, func(m map[string]quad.Value) {
+				eventQuads = append(eventQuads, quad.Make(m["subject"], m["predicate"], m["object"], nil))
+			}); err != nil {
+				return err
+			}
+		}
+
+		// The iterators stop early when the context is cancelled
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		lock.Lock()
+		quads = append(quads, eventQuads...)
+		lock.Unlock()
+		return nil
 	})
+	if err != nil {
+		return err
+	}
 
+	progress.total = len(quads)
+	return to.writeQuads(ctx, quads, progress)
+}
+
+// writeQuads adds the quads to the store in batches, ignoring the quads that are already present.
+func (g *CayleyGraph) writeQuads(ctx context.Context, quads []quad.Quad, progress *migrateReporter) error {
 	opts := make(graph.Options)
 	opts["ignore_missing"] = true
 	opts["ignore_duplicate"] = true
 
-	w, err := writer.NewSingleReplication(to.db.store, opts)
-	if len(quads) > 0 {
-		err = w.AddQuadSet(quads)
+	w, err := writer.NewSingleReplication(g.store, opts)
+	if err != nil {
+		return err
 	}
 
-	return err
+	for start := 0; start < len(quads); start += MigrateBatchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		end := start + MigrateBatchSize
+		if end > len(quads) {
+			end = len(quads)
+		}
+		if err := w.AddQuadSet(quads[start:end]); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress.add(end - start)
+		}
+	}
+	return nil
+}
+
+// migrateNode is a node read from the source graph along with its properties.
+type migrateNode struct {
+	id         string
+	ntype      string
+	properties []*Property
+}
+
+// batchInserter is implemented by the graph databases that can write many nodes and edges in a single operation.
+// The edges only refer to nodes provided in the same or an earlier batch.
+type batchInserter interface {
+	insertBatch(nodes []*migrateNode, edges []*Edge) error
+}
+
+func insertBatch(db GraphDatabase, nodes []*migrateNode, edges []*Edge) error {
+	if b, ok := db.(batchInserter); ok {
+		return b.insertBatch(nodes, edges)
+	}
+
+	for _, node := range nodes {
+		n, err := db.InsertNode(node.id, node.ntype)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range node.properties {
+			if err := db.InsertProperty(n, p.Predicate, p.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, edge := range edges {
+		if err := db.InsertEdge(edge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *CayleyGraph) insertBatch(nodes []*migrateNode, edges []*Edge) error {
+	var quads []quad.Quad
+
+	for _, node := range nodes {
+		if node.id == "" || node.ntype == "" {
+			continue
+		}
+
+		quads = append(quads, quad.Make(quad.IRI(node.id), quad.IRI("type"), quad.String(node.ntype), nil))
+
+		for _, p := range node.properties {
+			quads = append(quads, quad.Make(quad.IRI(node.id), quad.IRI(p.Predicate), quad.String(p.Value), nil))
+		}
+	}
+	for _, edge := range edges {
+		quads = append(quads, quad.Make(quad.IRI(g.NodeToID(edge.From)),
+			quad.IRI(edge.Predicate), quad.IRI(g.NodeToID(edge.To)), nil))
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	return g.writeQuads(context.Background(), quads, nil)
+}
+
+// copyEvents copies the events using the GraphDatabase interface, for graphs that are not both Cayley stores.
+// The nodes and edges are read before they are written, so that the destination receives them in batches.
+func (g *Graph) copyEvents(ctx context.Context, to *Graph, progress *migrateReporter, uuids ...string) error {
+	nodes, edges, err := g.readEvents(ctx, uuids...)
+	if err != nil {
+		return err
+	}
+
+	progress.total = len(nodes) + len(edges)
+	for start := 0; start < progress.total; start += MigrateBatchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		end := start + MigrateBatchSize
+		if end > progress.total {
+			end = progress.total
+		}
+
+		// The nodes are written before the edges that refer to them
+		var nbatch []*migrateNode
+		var ebatch []*Edge
+		if start < len(nodes) {
+			last := end
+			if last > len(nodes) {
+				last = len(nodes)
+			}
+			nbatch = nodes[start:last]
+		}
+		if end > len(nodes) {
+			first := start - len(nodes)
+			if first < 0 {
+				first = 0
+			}
+			ebatch = edges[first : end-len(nodes)]
+		}
+
+		if err := insertBatch(to.db, nbatch, ebatch); err != nil {
+			return err
+		}
+		progress.add(end - start)
+	}
+	return nil
+}
+
+// readEvents returns the nodes and edges related to the events, which are the event nodes, the nodes
+// the events have out edges to, and the nodes those have out edges to. The events are read concurrently.
+func (g *Graph) readEvents(ctx context.Context, uuids ...string) ([]*migrateNode, []*Edge, error) {
+	r := &eventReader{
+		g:     g,
+		read:  make(map[string]struct{}),
+		edges: make(map[string]struct{}),
+	}
+
+	if err := readConcurrently(ctx, migrateWorkers, uuids, r.readEvent); err != nil {
+		return nil, nil, err
+	}
+	return r.nodes, r.edgeList, nil
+}
+
+// eventReader collects the nodes and edges read by the migration workers, so the nodes shared
+// by several events are only read once.
+type eventReader struct {
+	sync.Mutex
+	g        *Graph
+	nodes    []*migrateNode
+	edgeList []*Edge
+	// Node identifiers are case sensitive, so a stringset cannot be used
+	read map[string]struct{}
+	// The nodes with out edges that have already been read
+	edges map[string]struct{}
+}
+
+// claim returns true when the identifier had not already been claimed in the set by another worker.
+func (r *eventReader) claim(set map[string]struct{}, id string) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, found := set[id]; found {
+		return false
+	}
+	set[id] = struct{}{}
+	return true
+}
+
+func (r *eventReader) readEvent(ctx context.Context, uuid string) error {
+	event, err := r.g.db.ReadNode(uuid, "event")
+	if err != nil {
+		return nil
+	}
+
+	if err := r.readNode(event); err != nil {
+		return err
+	}
+	r.claim(r.edges, r.g.db.NodeToID(event))
+
+	targets, err := r.readEdges(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	// Read the edges leaving the nodes associated with the event
+	for _, node := range targets {
+		if id := r.g.db.NodeToID(node); id != "" && r.claim(r.edges, id) {
+			if _, err := r.readEdges(ctx, node); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *eventReader) readNode(node Node) error {
+	id := r.g.db.NodeToID(node)
+	if !r.claim(r.read, id) {
+		return nil
+	}
+
+	properties, err := r.g.db.ReadProperties(node)
+	if err != nil {
+		return err
+	}
+
+	n := &migrateNode{id: id}
+	for _, p := range properties {
+		if p.Predicate == "type" {
+			n.ntype = p.Value
+			continue
+		}
+		n.properties = append(n.properties, p)
+	}
+
+	r.Lock()
+	r.nodes = append(r.nodes, n)
+	r.Unlock()
+	return nil
+}
+
+func (r *eventReader) readEdges(ctx context.Context, node Node) ([]Node, error) {
+	var targets []Node
+
+	if err := ctx.Err(); err != nil {
+		return targets, err
+	}
+
+	out, err := r.g.db.ReadOutEdges(node)
+	if err != nil {
+		// Nodes without out edges are expected
+		return targets, nil
+	}
+
+	var edges []*Edge
+	for _, edge := range out {
+		if err := r.readNode(edge.To); err != nil {
+			return targets, err
+		}
+
+		edges = append(edges, &Edge{
+			Predicate: edge.Predicate,
+			From:      r.g.db.NodeToID(edge.From),
+			To:        r.g.db.NodeToID(edge.To),
+		})
+		targets = append(targets, edge.To)
+	}
+
+	r.Lock()
+	r.edgeList = append(r.edgeList, edges...)
+	r.Unlock()
+	return targets, nil
 }
 
 // MigrateEventsInScope copies the nodes and edges related to the Events identified by the uuids from the receiver Graph into another.
 func (g *Graph) MigrateEventsInScope(to *Graph, d []string) error {
+	return g.MigrateEventsInScopeWithContext(context.Background(), to, d)
+}
+
+// MigrateEventsInScopeWithContext performs MigrateEventsInScope using MigrateEventsWithContext.
+func (g *Graph) MigrateEventsInScopeWithContext(ctx context.Context, to *Graph, d []string) error {
 	if len(d) == 0 {
 		return errors.New("MigrateEventsInScope: No domain names provided")
 	}
 
-	var domains []quad.Value
-	for _, domain := range d {
-		domains = append(domains, quad.IRI(domain))
-	}
-
-	g.db.Lock()
-	var uuids []string
-	// Obtain the events that are in scope according to the domain name arguments
-	p := cayley.StartPath(g.db.store).Has(quad.IRI("type"), quad.String("event")).Tag("event")
-	p = p.Out(quad.IRI("domain")).And(cayley.StartPath(g.db.store, domains...)).Back("event").Unique().Tag("uuid")
-	p.Iterate(context.TODO()).TagValues(nil, func(m map[string]quad.Value) {
-		uuids = append(uuids, valToStr(m["uuid"]))
-	})
-	g.db.Unlock()
-
-	return g.MigrateEvents(to, uuids...)
+	return g.MigrateEventsWithContext(ctx, to, g.EventsInScope(d...)...)
 }
@@ -0,0 +1,167 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// plainDatabase hides the Cayley store and its batch writes behind the GraphDatabase interface
+type plainDatabase struct {
+	GraphDatabase
+}
+
+func migrateTestGraph(t *testing.T, uuid string, names int) *Graph {
+	g := NewGraph(NewCayleyGraphMemory())
+
+	if _, err := g.InsertEvent(uuid); err != nil {
+		t.Fatalf("Failed to insert the event: %v", err)
+	}
+	for i := 0; i < names; i++ {
+		name := fmt.Sprintf("host%d.owasp.org", i)
+
+		if _, err := g.InsertFQDN(name, "DNS", "dns", uuid); err != nil {
+			t.Fatalf("Failed to insert the FQDN %s: %v", name, err)
+		}
+	}
+	if err := g.InsertA("host0.owasp.org", "192.0.2.1", "DNS", "dns", uuid); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	return g
+}
+
+func TestMigrateEvents(t *testing.T) {
+	uuid := "migratetest"
+	from := migrateTestGraph(t, uuid, 10)
+	expected := len(from.EventFQDNs(uuid))
+
+	tests := []struct {
+		name     string
+		from, to *Graph
+	}{
+		{"Cayley stores", from, NewGraph(NewCayleyGraphMemory())},
+		{"Cayley batches", NewGraph(&plainDatabase{from.db}), NewGraph(NewCayleyGraphMemory())},
+		{"Interface methods", from, NewGraph(&plainDatabase{NewCayleyGraphMemory()})},
+	}
+
+	for _, test := range tests {
+		if err := test.from.MigrateEvents(test.to, uuid); err != nil {
+			t.Errorf("%s: Failed to migrate the event: %v", test.name, err)
+			continue
+		}
+
+		if got := len(test.to.EventFQDNs(uuid)); got != expected {
+			t.Errorf("%s: %d FQDNs were migrated, expected %d", test.name, got, expected)
+		}
+		if _, err := test.to.db.ReadNode("192.0.2.1", "ipaddr"); err != nil {
+			t.Errorf("%s: The address was not migrated", test.name)
+		}
+	}
+}
+
+func TestMigrateManyEvents(t *testing.T) {
+	from := NewGraph(NewCayleyGraphMemory())
+
+	// More events than workers, which share an address and a data source
+	var uuids []string
+	for i := 0; i < 2*migrateWorkers+3; i++ {
+		uuid := fmt.Sprintf("migratetest%d", i)
+		uuids = append(uuids, uuid)
+
+		if _, err := from.InsertEvent(uuid); err != nil {
+			t.Fatalf("Failed to insert the event: %v", err)
+		}
+		if err := from.InsertA(fmt.Sprintf("host%d.owasp.org", i), "192.0.2.1", "DNS", "dns", uuid); err != nil {
+			t.Fatalf("Failed to insert the A record: %v", err)
+		}
+		if err := from.InsertA("www.owasp.org", fmt.Sprintf("192.0.2.%d", i+10), "DNS", "dns", uuid); err != nil {
+			t.Fatalf("Failed to insert the A record: %v", err)
+		}
+	}
+
+	for name, src := range map[string]*Graph{
+		"Cayley stores":     from,
+		"Interface methods": NewGraph(&plainDatabase{from.db}),
+	} {
+		to := NewGraph(NewCayleyGraphMemory())
+
+		if err := src.MigrateEvents(to, uuids...); err != nil {
+			t.Errorf("%s: Failed to migrate the events: %v", name, err)
+			continue
+		}
+
+		for i, uuid := range uuids {
+			if got, expected := len(to.EventFQDNs(uuid)), len(from.EventFQDNs(uuid)); got != expected {
+				t.Errorf("%s: %d FQDNs were migrated for the event %s, expected %d", name, got, uuid, expected)
+			}
+
+			addr := fmt.Sprintf("192.0.2.%d", i+10)
+			if !containsString(to.NameAddresses("www.owasp.org"), addr) {
+				t.Errorf("%s: The address %s of the shared name was not migrated", name, addr)
+			}
+		}
+		if got := to.AddressNames("192.0.2.1"); len(got) != len(uuids) {
+			t.Errorf("%s: The shared address has %d names, expected %d", name, len(got), len(uuids))
+		}
+	}
+}
+
+func TestMigrateEventsProgress(t *testing.T) {
+	uuid := "migratetest"
+	from := migrateTestGraph(t, uuid, MigrateBatchSize)
+
+	bus := eventbus.NewEventBus()
+	defer bus.Stop()
+	r := eventbus.NewRecorder(bus, requests.MigrateProgressTopic)
+	defer r.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	ctx := context.WithValue(context.Background(), requests.ContextEventBus, bus)
+	for _, src := range []*Graph{from, NewGraph(&plainDatabase{from.db})} {
+		before := r.Len(requests.MigrateProgressTopic)
+
+		if err := src.MigrateEventsWithContext(ctx, NewGraph(NewCayleyGraphMemory()), uuid); err != nil {
+			t.Fatalf("Failed to migrate the event: %v", err)
+		}
+		// Each name has an edge from the event, so more than one batch is written
+		if !r.Wait(requests.MigrateProgressTopic, before+2, 5*time.Second) {
+			t.Fatalf("Only %d progress messages were received", r.Len(requests.MigrateProgressTopic)-before)
+		}
+		time.Sleep(100 * time.Millisecond)
+
+		msgs := r.Messages(requests.MigrateProgressTopic)[before:]
+		last := msgs[len(msgs)-1][0].(*MigrateProgress)
+		if last.Total == 0 || last.Written != last.Total {
+			t.Errorf("The final progress message reported %d of %d written", last.Written, last.Total)
+		}
+		if first := msgs[0][0].(*MigrateProgress); first.Written != MigrateBatchSize {
+			t.Errorf("The first progress message reported %d written, expected %d", first.Written, MigrateBatchSize)
+		}
+	}
+}
+
+func TestMigrateEventsCancel(t *testing.T) {
+	uuid := "migratetest"
+	from := migrateTestGraph(t, uuid, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, src := range []*Graph{from, NewGraph(&plainDatabase{from.db})} {
+		to := NewGraph(NewCayleyGraphMemory())
+
+		if err := src.MigrateEventsWithContext(ctx, to, uuid); err != context.Canceled {
+			t.Errorf("The cancelled migration returned %v", err)
+		}
+		if names := to.EventFQDNs(uuid); len(names) != 0 {
+			t.Errorf("The cancelled migration wrote %d FQDNs", len(names))
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"errors"
+	"sort"
+)
+
+// NameserverInfo describes a name server that is authoritative for zones in the graph.
+type NameserverInfo struct {
+	Name     string
+	Provider string
+	Software string
+	Version  string
+	// Addresses were used to send queries directly to the name server
+	Addresses []string
+}
+
+// InsertNameserverFingerprint records the DNS provider and software identified for the name
+// server as properties of the fqdn node. Empty values do not replace previous findings.
+func (g *Graph) InsertNameserverFingerprint(ns, provider, software, version string) error {
+	if ns == "" {
+		return errors.New("Graph: InsertNameserverFingerprint: Invalid name server provided")
+	}
+
+	node, err := g.InsertNodeIfNotExist(ns, "fqdn")
+	if err != nil {
+		return err
+	}
+
+	for pred, value := range map[string]string{
+		"dns_provider": provider,
+		"dns_software": software,
+		"dns_version":  version,
+	} {
+		if value == "" {
+			continue
+		}
+
+		if p, err := g.db.ReadProperties(node, pred); err == nil {
+			for _, prop := range p {
+				if err := g.db.DeleteProperty(node, pred, prop.Value); err != nil {
+					return err
+				}
+			}
+		}
+		if err := g.db.InsertProperty(node, pred, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InsertNameserverAddress records the address used to reach the name server as a property of the
+// fqdn node, so that later enumerations can query the server without discovering it again.
+func (g *Graph) InsertNameserverAddress(ns, addr string) error {
+	if ns == "" || addr == "" {
+		return errors.New("Graph: InsertNameserverAddress: Invalid name server or address provided")
+	}
+
+	node, err := g.InsertNodeIfNotExist(ns, "fqdn")
+	if err != nil {
+		return err
+	}
+
+	if p, err := g.db.ReadProperties(node, "dns_address"); err == nil {
+		for _, prop := range p {
+			if prop.Value == addr {
+				return nil
+			}
+		}
+	}
+	return g.db.InsertProperty(node, "dns_address", addr)
+}
+
+// ReadNameserverInfo returns the fingerprint recorded for the name server.
+func (g *Graph) ReadNameserverInfo(ns string) *NameserverInfo {
+	info := &NameserverInfo{Name: ns}
+
+	node, err := g.db.ReadNode(ns, "fqdn")
+	if err != nil {
+		return info
+	}
+
+	if p, err := g.db.ReadProperties(node, "dns_provider", "dns_software", "dns_version", "dns_address"); err == nil {
+		for _, prop := range p {
+			switch prop.Predicate {
+			case "dns_address":
+				info.Addresses = append(info.Addresses, prop.Value)
+			case "dns_provider":
+				info.Provider = prop.Value
+			case "dns_software":
+				info.Software = prop.Value
+			case "dns_version":
+				info.Version = prop.Value
+			}
+		}
+	}
+	sort.Strings(info.Addresses)
+	return info
+}
+
+// ZoneNameservers returns the name servers of each zone discovered during the events.
+func (g *Graph) ZoneNameservers(events ...string) map[string][]*NameserverInfo {
+	zones := make(map[string][]*NameserverInfo)
+
+	nodes, err := g.AllNodesOfType("fqdn", events...)
+	if err != nil {
+		return zones
+	}
+
+	for _, node := range nodes {
+		edges, err := g.db.ReadOutEdges(node, "ns_record")
+		if err != nil || len(edges) == 0 {
+			continue
+		}
+
+		var servers []*NameserverInfo
+		for _, ns := range g.nodeIDs(edgeTargets(edges)) {
+			servers = append(servers, g.ReadNameserverInfo(ns))
+		}
+		sort.Slice(servers, func(i, j int) bool {
+			return servers[i].Name < servers[j].Name
+		})
+
+		zones[g.db.NodeToID(node)] = servers
+	}
+	return zones
+}
+
+func edgeTargets(edges []*Edge) []Node {
+	var nodes []Node
+
+	for _, edge := range edges {
+		nodes = append(nodes, edge.To)
+	}
+	return nodes
+}
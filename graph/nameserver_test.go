@@ -0,0 +1,64 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"testing"
+)
+
+func TestZoneNameservers(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	eventID := "ef9f9475-34ff-4e15-a8e7-3d6b7c8e6f2a"
+	if err := g.InsertNS("owasp.org", "kate.ns.cloudflare.com", "DNS", "dns", eventID); err != nil {
+		t.Fatalf("Failed to insert the NS record: %v", err)
+	}
+	if err := g.InsertNameserverFingerprint("kate.ns.cloudflare.com", "Cloudflare", "", "old"); err != nil {
+		t.Fatalf("Failed to insert the fingerprint: %v", err)
+	}
+	// Empty values do not replace the previous findings
+	if err := g.InsertNameserverFingerprint("kate.ns.cloudflare.com", "", "Knot DNS", "3.0.2"); err != nil {
+		t.Fatalf("Failed to insert the fingerprint: %v", err)
+	}
+
+	zones := g.ZoneNameservers(eventID)
+	servers, found := zones["owasp.org"]
+	if !found || len(servers) != 1 {
+		t.Fatalf("The name servers of the zone were not returned: %v", zones)
+	}
+
+	ns := servers[0]
+	if ns.Name != "kate.ns.cloudflare.com" || ns.Provider != "Cloudflare" ||
+		ns.Software != "Knot DNS" || ns.Version != "3.0.2" {
+		t.Errorf("Unexpected name server information: %+v", ns)
+	}
+}
+
+func TestInsertNameserverAddress(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	eventID := "ef9f9475-34ff-4e15-a8e7-3d6b7c8e6f2a"
+	if err := g.InsertNS("owasp.org", "kate.ns.cloudflare.com", "DNS", "dns", eventID); err != nil {
+		t.Fatalf("Failed to insert the NS record: %v", err)
+	}
+	if err := g.InsertNameserverAddress("kate.ns.cloudflare.com", ""); err == nil {
+		t.Errorf("An empty name server address was accepted")
+	}
+
+	for _, addr := range []string{"173.245.58.124", "108.162.192.124", "173.245.58.124"} {
+		if err := g.InsertNameserverAddress("kate.ns.cloudflare.com", addr); err != nil {
+			t.Fatalf("Failed to insert the name server address: %v", err)
+		}
+	}
+
+	servers := g.ZoneNameservers(eventID)["owasp.org"]
+	if len(servers) != 1 {
+		t.Fatalf("The name servers of the zone were not returned: %v", servers)
+	}
+	if addrs := servers[0].Addresses; len(addrs) != 2 || addrs[0] != "108.162.192.124" || addrs[1] != "173.245.58.124" {
+		t.Errorf("The name server addresses were returned as %v", addrs)
+	}
+}
@@ -0,0 +1,542 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// The label shared by all the nodes stored in Neo4j, in addition to the label for the node type.
+const neo4jNodeLabel = "Amass"
+
+// Neo4jGraph implements the GraphDatabase interface using a Neo4j server accessed over the Bolt protocol.
+// Each node is labeled with its type, edges become relationships named after the predicate, and the
+// properties of a node are stored as lists of strings, so analysts can query the findings using Cypher.
+type Neo4jGraph struct {
+	driver   neo4j.Driver
+	database string
+}
+
+// NewNeo4jGraph returns a Neo4jGraph connected to the server at the bolt:// or neo4j:// URL provided.
+// The database is the name of the Neo4j database used, or the default database when empty.
+func NewNeo4jGraph(url, username, password, database string) (*Neo4jGraph, error) {
+	auth := neo4j.NoAuth()
+	if username != "" {
+		auth = neo4j.BasicAuth(username, password, "")
+	}
+
+	driver, err := neo4j.NewDriver(url, auth)
+	if err != nil {
+		return nil, fmt.Errorf("NewNeo4jGraph: %v", err)
+	}
+	if err := driver.VerifyConnectivity(); err != nil {
+		driver.Close()
+		return nil, fmt.Errorf("NewNeo4jGraph: Failed to connect to %s: %v", url, err)
+	}
+
+	g := &Neo4jGraph{
+		driver:   driver,
+		database: database,
+	}
+
+	// The node identifiers are looked up by all the operations
+	if _, err := g.write("CREATE CONSTRAINT amass_node_id IF NOT EXISTS FOR (n:"+neo4jNodeLabel+") REQUIRE n.id IS UNIQUE", nil); err != nil {
+		driver.Close()
+		return nil, fmt.Errorf("NewNeo4jGraph: Failed to create the node constraint: %v", err)
+	}
+	return g, nil
+}
+
+// String implements the GraphDatabase interface.
+func (g *Neo4jGraph) String() string {
+	return "neo4j"
+}
+
+// Close implements the GraphDatabase interface.
+func (g *Neo4jGraph) Close() {
+	g.driver.Close()
+}
+
+// DumpGraph implements the GraphDatabase interface.
+func (g *Neo4jGraph) DumpGraph() string {
+	var out strings.Builder
+
+	if records, err := g.read("MATCH (n:"+neo4jNodeLabel+") RETURN n.id, properties(n)", nil); err == nil {
+		for _, rec := range records {
+			id := recordString(rec, 0)
+
+			for _, p := range neo4jProperties(rec.Values[1], nil) {
+				fmt.Fprintf(&out, "%s -> %s -> %s\n", id, p.Predicate, p.Value)
+			}
+		}
+	}
+
+	query := "MATCH (a:" + neo4jNodeLabel + ")-[r]->(b:" + neo4jNodeLabel + ") RETURN a.id, type(r), b.id"
+	if records, err := g.read(query, nil); err == nil {
+		for _, rec := range records {
+			fmt.Fprintf(&out, "%s -> %s -> %s\n", recordString(rec, 0), recordString(rec, 1), recordString(rec, 2))
+		}
+	}
+
+	return out.String()
+}
+
+// NodeToID implements the GraphDatabase interface.
+func (g *Neo4jGraph) NodeToID(n Node) string {
+	if id, ok := n.(string); ok {
+		return id
+	}
+	return ""
+}
+
+// AllNodesOfType implements the GraphDatabase interface.
+func (g *Neo4jGraph) AllNodesOfType(ntypes ...string) ([]Node, error) {
+	query := "MATCH (n:" + neo4jNodeLabel + ") WHERE size($types) = 0 OR n.type IN $types RETURN n.id"
+
+	records, err := g.read(query, map[string]interface{}{"types": stringList(ntypes)})
+	if err != nil {
+		return nil, fmt.Errorf("%s: AllNodesOfType: %v", g.String(), err)
+	}
+
+	nodes := recordNodes(records)
+	if len(nodes) == 0 {
+		return nodes, fmt.Errorf("%s: AllNodesOfType: No nodes found", g.String())
+	}
+	return nodes, nil
+}
+
+// AllOutNodes implements the GraphDatabase interface.
+func (g *Neo4jGraph) AllOutNodes(node Node) ([]Node, error) {
+	nstr := g.NodeToID(node)
+	query := "MATCH (n:" + neo4jNodeLabel + " {id: $id})-->(m:" + neo4jNodeLabel + ") RETURN DISTINCT m.id"
+
+	records, err := g.read(query, map[string]interface{}{"id": nstr})
+	if err != nil {
+		return nil, fmt.Errorf("%s: AllOutNodes: %v", g.String(), err)
+	}
+
+	nodes := recordNodes(records)
+	if len(nodes) == 0 {
+		return nodes, fmt.Errorf("%s: AllOutNodes: No nodes found that %s has out edges to", g.String(), nstr)
+	}
+	return nodes, nil
+}
+
+// InsertNode implements the GraphDatabase interface.
+func (g *Neo4jGraph) InsertNode(id, ntype string) (Node, error) {
+	if id == "" || ntype == "" {
+		return nil, fmt.Errorf("%s: InsertNode: Empty required arguments", g.String())
+	}
+
+	query := "MERGE (n:" + neo4jNodeLabel + " {id: $id}) ON CREATE SET n.type = $type, n:" + cypherName(ntype)
+	if _, err := g.write(query, map[string]interface{}{"id": id, "type": ntype}); err != nil {
+		return nil, fmt.Errorf("%s: InsertNode: %v", g.String(), err)
+	}
+	return id, nil
+}
+
+// ReadNode implements the GraphDatabase interface.
+func (g *Neo4jGraph) ReadNode(id, ntype string) (Node, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%s: ReadNode: Empty node id provided", g.String())
+	}
+
+	query := "MATCH (n:" + neo4jNodeLabel + " {id: $id}) WHERE $type = '' OR n.type = $type RETURN n.id"
+	records, err := g.read(query, map[string]interface{}{"id": id, "type": ntype})
+	if err != nil || len(records) == 0 {
+		return nil, fmt.Errorf("%s: ReadNode: Node %s does not exist", g.String(), id)
+	}
+	return id, nil
+}
+
+// DeleteNode implements the GraphDatabase interface.
+func (g *Neo4jGraph) DeleteNode(node Node) error {
+	id := g.NodeToID(node)
+	if id == "" {
+		return fmt.Errorf("%s: DeleteNode: Empty node id provided", g.String())
+	}
+
+	query := "MATCH (n:" + neo4jNodeLabel + " {id: $id}) DETACH DELETE n RETURN count(*)"
+	records, err := g.write(query, map[string]interface{}{"id": id})
+	if err != nil {
+		return fmt.Errorf("%s: DeleteNode: %v", g.String(), err)
+	}
+	if recordCount(records) == 0 {
+		return fmt.Errorf("%s: DeleteNode: Node %s does not exist", g.String(), id)
+	}
+	return nil
+}
+
+// InsertEdge implements the GraphDatabase interface.
+func (g *Neo4jGraph) InsertEdge(edge *Edge) error {
+	if edge.Predicate == "" {
+		return fmt.Errorf("%s: InsertEdge: Empty edge predicate", g.String())
+	}
+	from := g.NodeToID(edge.From)
+	if from == "" {
+		return fmt.Errorf("%s: InsertEdge: Invalid from node", g.String())
+	}
+	to := g.NodeToID(edge.To)
+	if to == "" {
+		return fmt.Errorf("%s: InsertEdge: Invalid to node", g.String())
+	}
+
+	query := "MATCH (a:" + neo4jNodeLabel + " {id: $from}), (b:" + neo4jNodeLabel +
+		" {id: $to}) MERGE (a)-[:" + cypherName(edge.Predicate) + "]->(b) RETURN count(*)"
+	records, err := g.write(query, map[string]interface{}{"from": from, "to": to})
+	if err != nil {
+		return fmt.Errorf("%s: InsertEdge: %v", g.String(), err)
+	}
+	if recordCount(records) == 0 {
+		return fmt.Errorf("%s: InsertEdge: Invalid from or to node", g.String())
+	}
+	return nil
+}
+
+// ReadEdges implements the GraphDatabase interface.
+func (g *Neo4jGraph) ReadEdges(node Node, predicates ...string) ([]*Edge, error) {
+	var edges []*Edge
+
+	if e, err := g.ReadInEdges(node, predicates...); err == nil {
+		edges = append(edges, e...)
+	}
+
+	if e, err := g.ReadOutEdges(node, predicates...); err == nil {
+		edges = append(edges, e...)
+	}
+
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%s: ReadEdges: Failed to discover edges for the node %s", g.String(), g.NodeToID(node))
+	}
+	return edges, nil
+}
+
+// CountEdges implements the GraphDatabase interface.
+func (g *Neo4jGraph) CountEdges(node Node, predicates ...string) (int, error) {
+	var count int
+
+	if c, err := g.CountInEdges(node, predicates...); err == nil {
+		count += c
+	} else {
+		return 0, fmt.Errorf("%s: CountEdges: %v", g.String(), err)
+	}
+
+	if c, err := g.CountOutEdges(node, predicates...); err == nil {
+		count += c
+	}
+	return count, nil
+}
+
+// ReadInEdges implements the GraphDatabase interface.
+func (g *Neo4jGraph) ReadInEdges(node Node, predicates ...string) ([]*Edge, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return nil, fmt.Errorf("%s: ReadInEdges: Invalid node reference argument", g.String())
+	}
+
+	query := "MATCH (a:" + neo4jNodeLabel + ")-[r]->(n:" + neo4jNodeLabel +
+		" {id: $id}) WHERE size($preds) = 0 OR type(r) IN $preds RETURN type(r), a.id"
+	records, err := g.read(query, map[string]interface{}{"id": nstr, "preds": stringList(predicates)})
+	if err != nil {
+		return nil, fmt.Errorf("%s: ReadInEdges: %v", g.String(), err)
+	}
+
+	var edges []*Edge
+	for _, rec := range records {
+		edges = append(edges, &Edge{
+			Predicate: recordString(rec, 0),
+			From:      recordString(rec, 1),
+			To:        nstr,
+		})
+	}
+
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%s: ReadInEdges: Failed to discover edges coming into the node %s", g.String(), nstr)
+	}
+	return edges, nil
+}
+
+// CountInEdges implements the GraphDatabase interface.
+func (g *Neo4jGraph) CountInEdges(node Node, predicates ...string) (int, error) {
+	return g.countEdges("CountInEdges", "(n)<-[r]-()", node, predicates)
+}
+
+// ReadOutEdges implements the GraphDatabase interface.
+func (g *Neo4jGraph) ReadOutEdges(node Node, predicates ...string) ([]*Edge, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return nil, fmt.Errorf("%s: ReadOutEdges: Invalid node reference argument", g.String())
+	}
+
+	query := "MATCH (n:" + neo4jNodeLabel + " {id: $id})-[r]->(b:" + neo4jNodeLabel +
+		") WHERE size($preds) = 0 OR type(r) IN $preds RETURN type(r), b.id"
+	records, err := g.read(query, map[string]interface{}{"id": nstr, "preds": stringList(predicates)})
+	if err != nil {
+		return nil, fmt.Errorf("%s: ReadOutEdges: %v", g.String(), err)
+	}
+
+	var edges []*Edge
+	for _, rec := range records {
+		edges = append(edges, &Edge{
+			Predicate: recordString(rec, 0),
+			From:      nstr,
+			To:        recordString(rec, 1),
+		})
+	}
+
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%s: ReadOutEdges: Failed to discover edges leaving the node %s", g.String(), nstr)
+	}
+	return edges, nil
+}
+
+// CountOutEdges implements the GraphDatabase interface.
+func (g *Neo4jGraph) CountOutEdges(node Node, predicates ...string) (int, error) {
+	return g.countEdges("CountOutEdges", "(n)-[r]->()", node, predicates)
+}
+
+func (g *Neo4jGraph) countEdges(method, pattern string, node Node, predicates []string) (int, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return 0, fmt.Errorf("%s: %s: Invalid node reference argument", g.String(), method)
+	}
+
+	query := "MATCH (n:" + neo4jNodeLabel + " {id: $id}) OPTIONAL MATCH " + pattern +
+		" WHERE size($preds) = 0 OR type(r) IN $preds RETURN count(r)"
+	records, err := g.read(query, map[string]interface{}{"id": nstr, "preds": stringList(predicates)})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s: %v", g.String(), method, err)
+	}
+	if len(records) == 0 {
+		return 0, fmt.Errorf("%s: %s: Invalid node reference argument", g.String(), method)
+	}
+	return recordCount(records), nil
+}
+
+// CountOutNodesOfType implements the GraphDatabase interface.
+func (g *Neo4jGraph) CountOutNodesOfType(node Node, ntype, hasOut string, predicates ...string) (int, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return 0, fmt.Errorf("%s: CountOutNodesOfType: Invalid node reference argument", g.String())
+	}
+
+	query := "MATCH (n:" + neo4jNodeLabel + " {id: $id}) OPTIONAL MATCH (n)-[r]->(m:" + neo4jNodeLabel + ")" +
+		" WHERE m.type = $type AND (size($preds) = 0 OR type(r) IN $preds)" +
+		" AND ($out = '' OR size([(m)-[h]->() WHERE type(h) = $out | h]) > 0) RETURN count(DISTINCT m)"
+	records, err := g.read(query, map[string]interface{}{
+		"id":    nstr,
+		"type":  ntype,
+		"out":   hasOut,
+		"preds": stringList(predicates),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: CountOutNodesOfType: %v", g.String(), err)
+	}
+	if len(records) == 0 {
+		return 0, fmt.Errorf("%s: CountOutNodesOfType: Invalid node reference argument", g.String())
+	}
+	return recordCount(records), nil
+}
+
+// DeleteEdge implements the GraphDatabase interface.
+func (g *Neo4jGraph) DeleteEdge(edge *Edge) error {
+	from := g.NodeToID(edge.From)
+	to := g.NodeToID(edge.To)
+	if from == "" || to == "" {
+		return fmt.Errorf("%s: DeleteEdge: Invalid edge reference argument", g.String())
+	}
+
+	query := "MATCH (a:" + neo4jNodeLabel + " {id: $from})-[r]->(b:" + neo4jNodeLabel +
+		" {id: $to}) WHERE type(r) = $pred DELETE r RETURN count(*)"
+	records, err := g.write(query, map[string]interface{}{"from": from, "to": to, "pred": edge.Predicate})
+	if err != nil {
+		return fmt.Errorf("%s: DeleteEdge: %v", g.String(), err)
+	}
+	if recordCount(records) == 0 {
+		return fmt.Errorf("%s: DeleteEdge: The edge does not exist", g.String())
+	}
+	return nil
+}
+
+// InsertProperty implements the GraphDatabase interface.
+func (g *Neo4jGraph) InsertProperty(node Node, predicate, value string) error {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return fmt.Errorf("%s: InsertProperty: Invalid node reference argument", g.String())
+	} else if predicate == "" || predicate == "id" || predicate == "type" {
+		return fmt.Errorf("%s: InsertProperty: Invalid predicate argument", g.String())
+	}
+
+	p := "n." + cypherName(predicate)
+	query := "MATCH (n:" + neo4jNodeLabel + " {id: $id}) SET " + p + " = CASE WHEN " + p +
+		" IS NULL THEN [$value] WHEN $value IN " + p + " THEN " + p + " ELSE " + p + " + $value END RETURN count(*)"
+	records, err := g.write(query, map[string]interface{}{"id": nstr, "value": value})
+	if err != nil {
+		return fmt.Errorf("%s: InsertProperty: %v", g.String(), err)
+	}
+	if recordCount(records) == 0 {
+		return fmt.Errorf("%s: InsertProperty: Invalid node reference argument", g.String())
+	}
+	return nil
+}
+
+// ReadProperties implements the GraphDatabase interface.
+func (g *Neo4jGraph) ReadProperties(node Node, predicates ...string) ([]*Property, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return nil, fmt.Errorf("%s: ReadProperties: Invalid node reference argument", g.String())
+	}
+
+	records, err := g.read("MATCH (n:"+neo4jNodeLabel+" {id: $id}) RETURN properties(n)", map[string]interface{}{"id": nstr})
+	if err != nil || len(records) == 0 {
+		return nil, fmt.Errorf("%s: ReadProperties: Invalid node reference argument", g.String())
+	}
+	return neo4jProperties(records[0].Values[0], predicates), nil
+}
+
+// CountProperties implements the GraphDatabase interface.
+func (g *Neo4jGraph) CountProperties(node Node, predicates ...string) (int, error) {
+	properties, err := g.ReadProperties(node, predicates...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: CountProperties: Invalid node reference argument", g.String())
+	}
+	return len(properties), nil
+}
+
+// DeleteProperty implements the GraphDatabase interface.
+func (g *Neo4jGraph) DeleteProperty(node Node, predicate, value string) error {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return fmt.Errorf("%s: DeleteProperty: Invalid node reference argument", g.String())
+	}
+
+	// Setting the property to null removes it once the last value is deleted
+	p := "n." + cypherName(predicate)
+	query := "MATCH (n:" + neo4jNodeLabel + " {id: $id}) WHERE $value IN " + p + " WITH n, [v IN " + p +
+		" WHERE v <> $value] AS vals SET " + p + " = CASE WHEN size(vals) = 0 THEN null ELSE vals END RETURN count(*)"
+	records, err := g.write(query, map[string]interface{}{"id": nstr, "value": value})
+	if err != nil {
+		return fmt.Errorf("%s: DeleteProperty: %v", g.String(), err)
+	}
+	if recordCount(records) == 0 {
+		return fmt.Errorf("%s: DeleteProperty: The property does not exist on node: %s", g.String(), nstr)
+	}
+	return nil
+}
+
+func (g *Neo4jGraph) read(cypher string, params map[string]interface{}) ([]*neo4j.Record, error) {
+	return g.run(neo4j.AccessModeRead, cypher, params)
+}
+
+func (g *Neo4jGraph) write(cypher string, params map[string]interface{}) ([]*neo4j.Record, error) {
+	return g.run(neo4j.AccessModeWrite, cypher, params)
+}
+
+func (g *Neo4jGraph) run(mode neo4j.AccessMode, cypher string, params map[string]interface{}) ([]*neo4j.Record, error) {
+	session := g.driver.NewSession(neo4j.SessionConfig{
+		AccessMode:   mode,
+		DatabaseName: g.database,
+	})
+	defer session.Close()
+
+	work := func(tx neo4j.Transaction) (interface{}, error) {
+		result, err := tx.Run(cypher, params)
+		if err != nil {
+			return nil, err
+		}
+		return result.Collect()
+	}
+
+	var err error
+	var records interface{}
+	if mode == neo4j.AccessModeWrite {
+		records, err = session.WriteTransaction(work)
+	} else {
+		records, err = session.ReadTransaction(work)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return records.([]*neo4j.Record), nil
+}
+
+// neo4jProperties converts the properties of a Neo4j node into the Amass properties with the predicates provided.
+func neo4jProperties(value interface{}, predicates []string) []*Property {
+	props, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		if k != "id" && (len(predicates) == 0 || containsString(predicates, k)) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var properties []*Property
+	for _, k := range keys {
+		switch v := props[k].(type) {
+		case string:
+			properties = append(properties, &Property{Predicate: k, Value: v})
+		case []interface{}:
+			for _, val := range v {
+				if s, ok := val.(string); ok {
+					properties = append(properties, &Property{Predicate: k, Value: s})
+				}
+			}
+		}
+	}
+	return properties
+}
+
+// cypherName returns the label, relationship type or property name quoted for use in Cypher queries.
+func cypherName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func stringList(strs []string) []interface{} {
+	list := make([]interface{}, 0, len(strs))
+
+	for _, s := range strs {
+		list = append(list, s)
+	}
+	return list
+}
+
+func recordNodes(records []*neo4j.Record) []Node {
+	var nodes []Node
+
+	for _, rec := range records {
+		if id := recordString(rec, 0); id != "" {
+			nodes = append(nodes, id)
+		}
+	}
+	return nodes
+}
+
+func recordString(rec *neo4j.Record, i int) string {
+	if i < len(rec.Values) {
+		if s, ok := rec.Values[i].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+func recordCount(records []*neo4j.Record) int {
+	if len(records) == 0 || len(records[0].Values) == 0 {
+		return 0
+	}
+
+	if n, ok := records[0].Values[0].(int64); ok {
+		return int(n)
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"os"
+	"testing"
+)
+
+func TestNeo4jProperties(t *testing.T) {
+	if name := cypherName("Brute`Forcing"); name != "`Brute``Forcing`" {
+		t.Errorf("cypherName returned %s", name)
+	}
+
+	props := map[string]interface{}{
+		"id":   "owasp.org",
+		"type": "fqdn",
+		"tag":  []interface{}{"dns", "cert"},
+	}
+	if p := neo4jProperties(props, nil); len(p) != 3 || p[0].Predicate != "tag" || p[2].Value != "fqdn" {
+		t.Errorf("neo4jProperties did not convert all the properties: %v", p)
+	}
+	if p := neo4jProperties(props, []string{"type"}); len(p) != 1 || p[0].Value != "fqdn" {
+		t.Errorf("neo4jProperties did not filter the properties by predicate: %v", p)
+	}
+}
+
+func TestNeo4jGraph(t *testing.T) {
+	url := os.Getenv("AMASS_NEO4J_URL")
+	if url == "" {
+		t.Skip("AMASS_NEO4J_URL is not set")
+	}
+
+	db, err := NewNeo4jGraph(url, os.Getenv("AMASS_NEO4J_USERNAME"), os.Getenv("AMASS_NEO4J_PASSWORD"), "")
+	if err != nil {
+		t.Fatalf("Failed to connect to the Neo4j server: %v", err)
+	}
+	g := NewGraph(db)
+	defer g.Close()
+
+	if _, err := g.InsertEvent("neo4jtest"); err != nil {
+		t.Fatalf("Failed to insert the event: %v", err)
+	}
+	if _, err := g.InsertFQDN("www.neo4jtest.owasp.org", "Brute Forcing", "brute", "neo4jtest"); err != nil {
+		t.Fatalf("Failed to insert the FQDN: %v", err)
+	}
+	defer func() {
+		for _, id := range []string{"neo4jtest", "www.neo4jtest.owasp.org", "Brute Forcing"} {
+			if node, err := db.ReadNode(id, ""); err == nil {
+				_ = db.DeleteNode(node)
+			}
+		}
+	}()
+
+	node, err := db.ReadNode("www.neo4jtest.owasp.org", "fqdn")
+	if err != nil {
+		t.Fatalf("Failed to read the FQDN node: %v", err)
+	}
+	if edges, err := db.ReadInEdges(node, "Brute Forcing"); err != nil || len(edges) != 1 {
+		t.Errorf("ReadInEdges did not return the edge from the event: %v", err)
+	}
+	if sources, err := g.NodeSources(node, "neo4jtest"); err != nil || len(sources) != 1 || sources[0] != "Brute Forcing" {
+		t.Errorf("NodeSources returned %v, %v", sources, err)
+	}
+
+	if err := db.InsertProperty(node, "open_port", "443"); err != nil {
+		t.Fatalf("Failed to insert the property: %v", err)
+	}
+	if n, err := db.CountProperties(node, "open_port"); err != nil || n != 1 {
+		t.Errorf("CountProperties returned %d, %v", n, err)
+	}
+	if err := db.DeleteProperty(node, "open_port", "443"); err != nil {
+		t.Errorf("Failed to delete the property: %v", err)
+	}
+	if err := db.DeleteProperty(node, "open_port", "443"); err == nil {
+		t.Errorf("DeleteProperty did not fail for a missing property")
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Ownership records that control of a root domain was proven with a verification token.
+type Ownership struct {
+	Domain   string
+	Method   string
+	Verified time.Time
+}
+
+func ownershipID(domain string) string {
+	return domain + "-ownership"
+}
+
+// InsertOwnership records that ownership of the domain was verified by the method at the time provided.
+// An existing record for the domain is replaced.
+func (g *Graph) InsertOwnership(domain, method string, verified time.Time) error {
+	domain = strings.ToLower(strings.Trim(strings.TrimSpace(domain), "."))
+	if domain == "" {
+		return errors.New("Graph: InsertOwnership: Invalid domain name provided")
+	}
+
+	node, err := g.InsertNodeIfNotExist(ownershipID(domain), "ownership")
+	if err != nil {
+		return err
+	}
+
+	for _, pred := range []string{"domain", "method", "verified"} {
+		if p, err := g.db.ReadProperties(node, pred); err == nil {
+			for _, prop := range p {
+				if err := g.db.DeleteProperty(node, pred, prop.Value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if err := g.db.InsertProperty(node, "domain", domain); err != nil {
+		return err
+	}
+	if err := g.db.InsertProperty(node, "method", method); err != nil {
+		return err
+	}
+	return g.db.InsertProperty(node, "verified", verified.UTC().Format(time.RFC3339))
+}
+
+// DeleteOwnership removes the verified ownership of the domain.
+func (g *Graph) DeleteOwnership(domain string) error {
+	domain = strings.ToLower(strings.Trim(strings.TrimSpace(domain), "."))
+
+	node, err := g.db.ReadNode(ownershipID(domain), "ownership")
+	if err != nil {
+		return fmt.Errorf("Graph: DeleteOwnership: Ownership of %s has not been verified", domain)
+	}
+	return g.db.DeleteNode(node)
+}
+
+// Ownerships returns the domains with verified ownership, sorted by domain name.
+func (g *Graph) Ownerships() []*Ownership {
+	nodes, err := g.db.AllNodesOfType("ownership")
+	if err != nil {
+		return nil
+	}
+
+	var results []*Ownership
+	for _, node := range nodes {
+		properties, err := g.db.ReadProperties(node, "domain", "method", "verified")
+		if err != nil {
+			continue
+		}
+
+		o := new(Ownership)
+		for _, p := range properties {
+			switch p.Predicate {
+			case "domain":
+				o.Domain = p.Value
+			case "method":
+				o.Method = p.Value
+			case "verified":
+				o.Verified, _ = time.Parse(time.RFC3339, p.Value)
+			}
+		}
+		if o.Domain != "" {
+			results = append(results, o)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Domain < results[j].Domain
+	})
+	return results
+}
@@ -0,0 +1,51 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOwnerships(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	g := NewGraph(NewCayleyGraphMemory())
+
+	if err := g.InsertOwnership("", "dns", now); err == nil {
+		t.Errorf("InsertOwnership accepted an empty domain name")
+	}
+	if err := g.InsertOwnership("OWASP.org.", "dns", now); err != nil {
+		t.Fatalf("Failed to insert the ownership: %v", err)
+	}
+	if err := g.InsertOwnership("example.com", "http", now); err != nil {
+		t.Fatalf("Failed to insert the ownership: %v", err)
+	}
+
+	o := g.Ownerships()
+	if len(o) != 2 || o[0].Domain != "example.com" || o[1].Domain != "owasp.org" {
+		t.Fatalf("Ownerships returned %v instead of the sorted domains", o)
+	}
+	if o[1].Method != "dns" || !o[1].Verified.Equal(now) {
+		t.Errorf("Ownerships returned the method %s and time %v", o[1].Method, o[1].Verified)
+	}
+
+	// The record is replaced when the ownership is verified again
+	later := now.Add(time.Hour)
+	if err := g.InsertOwnership("owasp.org", "http", later); err != nil {
+		t.Fatalf("Failed to replace the ownership: %v", err)
+	}
+	if o := g.Ownerships(); len(o) != 2 || o[1].Method != "http" || !o[1].Verified.Equal(later) {
+		t.Errorf("The ownership was not replaced: %v", o[1])
+	}
+
+	if err := g.DeleteOwnership("example.com"); err != nil {
+		t.Errorf("Failed to delete the ownership: %v", err)
+	}
+	if err := g.DeleteOwnership("example.com"); err == nil {
+		t.Errorf("DeleteOwnership did not fail for a missing ownership")
+	}
+	if o := g.Ownerships(); len(o) != 1 {
+		t.Errorf("Ownerships returned %d entries after the deletion", len(o))
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	_ "github.com/lib/pq" // Registers the postgres database/sql driver
+)
+
+// NewPostgresGraph returns an SQLGraph that stores the nodes and edges in PostgreSQL tables.
+// The options are comma separated key=value pairs added to the connection URL, such as sslmode=disable.
+func NewPostgresGraph(path, options string) (*SQLGraph, error) {
+	u, err := url.Parse(path)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		return nil, fmt.Errorf("NewPostgresGraph: Invalid PostgreSQL connection URL: %s", path)
+	}
+
+	q := u.Query()
+	for _, opt := range strings.Split(options, ",") {
+		s := strings.SplitN(opt, "=", 2)
+		if len(s) != 2 {
+			continue
+		}
+
+		q.Set(strings.TrimSpace(s[0]), strings.TrimSpace(s[1]))
+	}
+	u.RawQuery = q.Encode()
+
+	db, err := sql.Open("postgres", u.String())
+	if err != nil {
+		return nil, fmt.Errorf("NewPostgresGraph: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("NewPostgresGraph: Failed to connect to the database: %v", err)
+	}
+
+	g, err := NewSQLGraph("postgresql", db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return g, nil
+}
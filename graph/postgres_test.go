@@ -0,0 +1,59 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPostgresGraph(t *testing.T) {
+	if _, err := NewPostgresGraph("bolt:///tmp/amass", ""); err == nil {
+		t.Errorf("NewPostgresGraph accepted a URL without the postgres scheme")
+	}
+
+	url := os.Getenv("AMASS_POSTGRES_URL")
+	if url == "" {
+		t.Skip("AMASS_POSTGRES_URL is not set")
+	}
+
+	db, err := NewPostgresGraph(url, "")
+	if err != nil {
+		t.Fatalf("Failed to connect to the PostgreSQL database: %v", err)
+	}
+	g := NewGraph(db)
+	defer g.Close()
+
+	if _, err := g.InsertEvent("pgtest"); err != nil {
+		t.Fatalf("Failed to insert the event: %v", err)
+	}
+	if _, err := g.InsertFQDN("www.pgtest.owasp.org", "DNS", "dns", "pgtest"); err != nil {
+		t.Fatalf("Failed to insert the FQDN: %v", err)
+	}
+	defer func() {
+		for _, id := range []string{"pgtest", "www.pgtest.owasp.org"} {
+			if node, err := db.ReadNode(id, ""); err == nil {
+				_ = db.DeleteNode(node)
+			}
+		}
+	}()
+
+	node, err := db.ReadNode("www.pgtest.owasp.org", "fqdn")
+	if err != nil {
+		t.Fatalf("Failed to read the FQDN node: %v", err)
+	}
+	if _, err := db.ReadNode("www.pgtest.owasp.org", "ipaddr"); err == nil {
+		t.Errorf("ReadNode returned the node for the wrong type")
+	}
+	if props, err := db.ReadProperties(node, "type"); err != nil || len(props) != 1 || props[0].Value != "fqdn" {
+		t.Errorf("ReadProperties did not return the node type")
+	}
+	if edges, err := db.ReadInEdges(node, "DNS"); err != nil || len(edges) != 1 || db.NodeToID(edges[0].From) != "pgtest" {
+		t.Errorf("ReadInEdges did not return the edge from the event")
+	}
+
+	if sources, err := g.NodeSources(node, "pgtest"); err != nil || len(sources) != 1 || sources[0] != "DNS" {
+		t.Errorf("NodeSources returned %v, %v", sources, err)
+	}
+}
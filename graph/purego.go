@@ -0,0 +1,10 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// +build purego
+
+package graph
+
+func init() {
+	puregoBuild = true
+}
@@ -0,0 +1,164 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/OWASP/Amass/v3/stringset"
+)
+
+// The methods below navigate the relationships of the data model by node identifier,
+// which supports query interfaces that are not aware of the graph database nodes.
+
+// NameAddresses returns the IP addresses that the FQDN resolves to, including through CNAMEs.
+func (g *Graph) NameAddresses(name string) []string {
+	node, err := g.db.ReadNode(name, "fqdn")
+	if err != nil {
+		return nil
+	}
+
+	nodes, err := g.NameToAddrs(node)
+	if err != nil {
+		return nil
+	}
+
+	return g.nodeIDs(nodes)
+}
+
+// NameEvents returns the UUIDs of the events that discovered the FQDN.
+func (g *Graph) NameEvents(name string) []string {
+	node, err := g.db.ReadNode(name, "fqdn")
+	if err != nil {
+		return nil
+	}
+
+	return g.nodeEvents(node)
+}
+
+// NameFirstSeen returns the start of the earliest event that discovered the FQDN,
+// or the zero time when the name was not discovered by an event.
+func (g *Graph) NameFirstSeen(name string) time.Time {
+	var first time.Time
+
+	for _, uuid := range g.NameEvents(name) {
+		if start, _ := g.EventDateRange(uuid); !start.IsZero() && (first.IsZero() || start.Before(first)) {
+			first = start
+		}
+	}
+	return first
+}
+
+// EventSources returns the names of the data sources used during the event.
+func (g *Graph) EventSources(uuid string) []string {
+	return g.outNodeIDs(uuid, "event", "used")
+}
+
+// SourceNames returns the names of all data sources in the graph.
+func (g *Graph) SourceNames() []string {
+	nodes, err := g.db.AllNodesOfType("source")
+	if err != nil {
+		return nil
+	}
+	return g.nodeIDs(nodes)
+}
+
+// AddressNames returns the FQDNs that have A or AAAA records for the IP address.
+func (g *Graph) AddressNames(addr string) []string {
+	return g.inNodeIDs(addr, "ipaddr", "a_record", "aaaa_record")
+}
+
+// AddressNetblock returns the CIDR of the netblock containing the IP address.
+func (g *Graph) AddressNetblock(addr string) string {
+	if cidrs := g.inNodeIDs(addr, "ipaddr", "contains"); len(cidrs) > 0 {
+		return cidrs[0]
+	}
+	return ""
+}
+
+// NetblockAddresses returns the IP addresses in the graph that the netblock contains.
+func (g *Graph) NetblockAddresses(cidr string) []string {
+	return g.outNodeIDs(cidr, "netblock", "contains")
+}
+
+// NetblockASN returns the autonomous system number announcing the netblock, or zero when unknown.
+func (g *Graph) NetblockASN(cidr string) int {
+	if asns := g.inNodeIDs(cidr, "netblock", "prefix"); len(asns) > 0 {
+		if asn, err := strconv.Atoi(asns[0]); err == nil {
+			return asn
+		}
+	}
+	return 0
+}
+
+// ASNNetblocks returns the CIDRs of the netblocks announced by the autonomous system.
+func (g *Graph) ASNNetblocks(asn int) []string {
+	return g.outNodeIDs(strconv.Itoa(asn), "as", "prefix")
+}
+
+func (g *Graph) inNodeIDs(id, ntype string, predicates ...string) []string {
+	node, err := g.db.ReadNode(id, ntype)
+	if err != nil {
+		return nil
+	}
+
+	edges, err := g.db.ReadInEdges(node, predicates...)
+	if err != nil {
+		return nil
+	}
+
+	var nodes []Node
+	for _, edge := range edges {
+		nodes = append(nodes, edge.From)
+	}
+	return g.nodeIDs(nodes)
+}
+
+func (g *Graph) outNodeIDs(id, ntype string, predicates ...string) []string {
+	node, err := g.db.ReadNode(id, ntype)
+	if err != nil {
+		return nil
+	}
+
+	edges, err := g.db.ReadOutEdges(node, predicates...)
+	if err != nil {
+		return nil
+	}
+	return g.nodeIDs(edgeTargets(edges))
+}
+
+func (g *Graph) nodeEvents(node Node) []string {
+	edges, err := g.db.ReadInEdges(node)
+	if err != nil {
+		return nil
+	}
+
+	events := stringset.New()
+	for _, edge := range edges {
+		p, err := g.db.ReadProperties(edge.From, "type")
+
+		if err == nil && len(p) > 0 && p[0].Value == "event" {
+			events.Insert(g.db.NodeToID(edge.From))
+		}
+	}
+	return events.Slice()
+}
+
+func (g *Graph) nodeIDs(nodes []Node) []string {
+	var ids []string
+	// The string set is not used, since it does not preserve the case of data source names
+	filter := make(map[string]struct{})
+
+	for _, node := range nodes {
+		id := g.db.NodeToID(node)
+		if _, found := filter[id]; id == "" || found {
+			continue
+		}
+
+		filter[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,71 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryRelationships(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	if err := g.InsertA("www.owasp.org", "192.168.1.1", "DNS", "dns", "ef9f9475-34ff-4e15-a8e7-3d6b7c8e6f2a"); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	if err := g.InsertInfrastructure(26808, "Test AS", "192.168.1.1", "192.168.1.0/24",
+		"RIR", "api", "ef9f9475-34ff-4e15-a8e7-3d6b7c8e6f2a"); err != nil {
+		t.Fatalf("Failed to insert the infrastructure: %v", err)
+	}
+
+	if got := g.NameAddresses("www.owasp.org"); !reflect.DeepEqual(got, []string{"192.168.1.1"}) {
+		t.Errorf("NameAddresses returned %v", got)
+	}
+	if got := g.AddressNames("192.168.1.1"); !reflect.DeepEqual(got, []string{"www.owasp.org"}) {
+		t.Errorf("AddressNames returned %v", got)
+	}
+	if got := g.AddressNetblock("192.168.1.1"); got != "192.168.1.0/24" {
+		t.Errorf("AddressNetblock returned %s", got)
+	}
+	if got := g.NetblockAddresses("192.168.1.0/24"); !reflect.DeepEqual(got, []string{"192.168.1.1"}) {
+		t.Errorf("NetblockAddresses returned %v", got)
+	}
+	if got := g.NetblockASN("192.168.1.0/24"); got != 26808 {
+		t.Errorf("NetblockASN returned %d", got)
+	}
+	if got := g.ASNNetblocks(26808); !reflect.DeepEqual(got, []string{"192.168.1.0/24"}) {
+		t.Errorf("ASNNetblocks returned %v", got)
+	}
+	if got := g.NameEvents("www.owasp.org"); !reflect.DeepEqual(got, []string{"ef9f9475-34ff-4e15-a8e7-3d6b7c8e6f2a"}) {
+		t.Errorf("NameEvents returned %v", got)
+	}
+	if got := g.EventSources("ef9f9475-34ff-4e15-a8e7-3d6b7c8e6f2a"); len(got) != 2 {
+		t.Errorf("EventSources returned %v", got)
+	}
+	if got := g.SourceNames(); len(got) != 2 {
+		t.Errorf("SourceNames returned %v", got)
+	}
+}
+
+func TestNameFirstSeen(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	first := time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+	insertDatedEvent(t, g, "later", "www.owasp.org", first.Add(48*time.Hour))
+	insertDatedEvent(t, g, "earlier", "www.owasp.org", first)
+	insertDatedEvent(t, g, "other", "api.owasp.org", first.Add(24*time.Hour))
+
+	if got := g.NameFirstSeen("www.owasp.org"); !got.Equal(first) {
+		t.Errorf("NameFirstSeen returned %s, expected %s", got, first)
+	}
+	if got := g.NameFirstSeen("api.owasp.org"); !got.Equal(first.Add(24 * time.Hour)) {
+		t.Errorf("NameFirstSeen returned %s for the name of a single event", got)
+	}
+	if got := g.NameFirstSeen("mail.owasp.org"); !got.IsZero() {
+		t.Errorf("NameFirstSeen returned %s for a name that was not discovered", got)
+	}
+}
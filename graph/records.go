@@ -0,0 +1,96 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// The maximum number of CNAME records followed from a name, as done by CNAMEToAddrs.
+const maxCNAMEChain = 10
+
+// DNSRecord is a relationship discovered through DNS between a name and another name or an address.
+type DNSRecord struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Target string `json:"target"`
+}
+
+// NameRecords returns the DNS records stored in the graph for the FQDN. The CNAME chain is followed
+// to the A and AAAA records of the last name, and the MX, NS and SRV records of the name are
+// provided along with the PTR records that point to it.
+func (g *Graph) NameRecords(name string) []*DNSRecord {
+	node, err := g.db.ReadNode(name, "fqdn")
+	if err != nil {
+		return nil
+	}
+
+	id := g.db.NodeToID(node)
+	records := g.cnameChainRecords(node)
+	for _, pred := range []string{"mx_record", "ns_record", "srv_record"} {
+		records = append(records, g.sortedRecords(id, pred, g.outNodeIDs(id, "fqdn", pred))...)
+	}
+
+	// The SRV records of the subdomain belong to its service names
+	services := g.inNodeIDs(id, "fqdn", "service")
+	sort.Strings(services)
+	for _, service := range services {
+		records = append(records, g.sortedRecords(service, "srv_record", g.outNodeIDs(service, "fqdn", "srv_record"))...)
+	}
+
+	ptrs := g.inNodeIDs(id, "fqdn", "ptr_record")
+	sort.Strings(ptrs)
+	for _, ptr := range ptrs {
+		records = append(records, &DNSRecord{Type: "PTR", Name: ptr, Target: id})
+	}
+	return records
+}
+
+func (g *Graph) cnameChainRecords(node Node) []*DNSRecord {
+	var records []*DNSRecord
+	seen := make(map[string]struct{})
+
+	cur := node
+	for i := 0; i < maxCNAMEChain; i++ {
+		id := g.db.NodeToID(cur)
+		seen[id] = struct{}{}
+
+		edges, err := g.db.ReadOutEdges(cur, "cname_record")
+		if err != nil || len(edges) == 0 {
+			break
+		}
+
+		next := edges[0].To
+		target := g.db.NodeToID(next)
+		records = append(records, &DNSRecord{Type: "CNAME", Name: id, Target: target})
+		// Stop at a loop in the chain
+		if _, found := seen[target]; found {
+			return records
+		}
+		cur = next
+	}
+
+	id := g.db.NodeToID(cur)
+	for _, pred := range []string{"a_record", "aaaa_record"} {
+		edges, err := g.db.ReadOutEdges(cur, pred)
+		if err != nil {
+			continue
+		}
+
+		records = append(records, g.sortedRecords(id, pred, g.nodeIDs(edgeTargets(edges)))...)
+	}
+	return records
+}
+
+func (g *Graph) sortedRecords(name, pred string, targets []string) []*DNSRecord {
+	rtype := strings.ToUpper(strings.TrimSuffix(pred, "_record"))
+
+	sort.Strings(targets)
+	records := make([]*DNSRecord, 0, len(targets))
+	for _, target := range targets {
+		records = append(records, &DNSRecord{Type: rtype, Name: name, Target: target})
+	}
+	return records
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNameRecords(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	event := "ef9f9475-34ff-4e15-a8e7-3d6b7c8e6f2a"
+	inserts := []func() error{
+		func() error { return g.InsertCNAME("www.owasp.org", "cdn.owasp.org", "DNS", "dns", event) },
+		func() error { return g.InsertCNAME("cdn.owasp.org", "edge.cdn.net", "DNS", "dns", event) },
+		func() error { return g.InsertA("edge.cdn.net", "192.168.1.2", "DNS", "dns", event) },
+		func() error { return g.InsertA("edge.cdn.net", "192.168.1.1", "DNS", "dns", event) },
+		func() error { return g.InsertAAAA("edge.cdn.net", "2001:db8::1", "DNS", "dns", event) },
+		func() error { return g.InsertMX("owasp.org", "mail.owasp.org", "DNS", "dns", event) },
+		func() error { return g.InsertNS("owasp.org", "ns2.owasp.org", "DNS", "dns", event) },
+		func() error { return g.InsertNS("owasp.org", "ns1.owasp.org", "DNS", "dns", event) },
+		func() error {
+			return g.InsertSRV("owasp.org", "_sip._tcp.owasp.org", "sip.owasp.org", "DNS", "dns", event)
+		},
+		func() error { return g.InsertPTR("1.1.168.192.in-addr.arpa", "owasp.org", "DNS", "dns", event) },
+		func() error { return g.InsertA("owasp.org", "192.168.1.1", "DNS", "dns", event) },
+	}
+	for _, insert := range inserts {
+		if err := insert(); err != nil {
+			t.Fatalf("Failed to insert the records: %v", err)
+		}
+	}
+
+	expected := []*DNSRecord{
+		{Type: "CNAME", Name: "www.owasp.org", Target: "cdn.owasp.org"},
+		{Type: "CNAME", Name: "cdn.owasp.org", Target: "edge.cdn.net"},
+		{Type: "A", Name: "edge.cdn.net", Target: "192.168.1.1"},
+		{Type: "A", Name: "edge.cdn.net", Target: "192.168.1.2"},
+		{Type: "AAAA", Name: "edge.cdn.net", Target: "2001:db8::1"},
+	}
+	if got := g.NameRecords("www.owasp.org"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("NameRecords returned %v for the CNAME chain", recordStrings(got))
+	}
+
+	expected = []*DNSRecord{
+		{Type: "A", Name: "owasp.org", Target: "192.168.1.1"},
+		{Type: "MX", Name: "owasp.org", Target: "mail.owasp.org"},
+		{Type: "NS", Name: "owasp.org", Target: "ns1.owasp.org"},
+		{Type: "NS", Name: "owasp.org", Target: "ns2.owasp.org"},
+		{Type: "SRV", Name: "_sip._tcp.owasp.org", Target: "sip.owasp.org"},
+		{Type: "PTR", Name: "1.1.168.192.in-addr.arpa", Target: "owasp.org"},
+	}
+	if got := g.NameRecords("owasp.org"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("NameRecords returned %v for the domain", recordStrings(got))
+	}
+
+	if got := g.NameRecords("missing.owasp.org"); len(got) != 0 {
+		t.Errorf("NameRecords returned %v for a name not in the graph", recordStrings(got))
+	}
+}
+
+func TestNameRecordsCNAMELoop(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	event := "ef9f9475-34ff-4e15-a8e7-3d6b7c8e6f2a"
+	if err := g.InsertCNAME("a.owasp.org", "b.owasp.org", "DNS", "dns", event); err != nil {
+		t.Fatalf("Failed to insert the CNAME record: %v", err)
+	}
+	if err := g.InsertCNAME("b.owasp.org", "a.owasp.org", "DNS", "dns", event); err != nil {
+		t.Fatalf("Failed to insert the CNAME record: %v", err)
+	}
+
+	if got := g.NameRecords("a.owasp.org"); len(got) != 2 {
+		t.Errorf("NameRecords returned %v for the CNAME loop", recordStrings(got))
+	}
+}
+
+func recordStrings(records []*DNSRecord) []string {
+	var results []string
+
+	for _, r := range records {
+		results = append(results, r.Type+" "+r.Name+" "+r.Target)
+	}
+	return results
+}
@@ -0,0 +1,612 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// The separator used within the set members holding edges and properties.
+const redisSep = "\x00"
+
+// RedisGraph implements the GraphDatabase interface using a Redis server, which permits several Amass
+// processes to share short-lived findings without the cost of persisting them. The node types are held
+// in a hash, and the edges and properties of each node are held in sets.
+type RedisGraph struct {
+	pool   *redis.Pool
+	prefix string
+	// The number of seconds before the keys written expire (zero keeps the keys)
+	expire int
+}
+
+// NewRedisGraph returns a RedisGraph connected to the server at the redis:// or rediss:// URL provided,
+// authenticating with the username when the server uses access control lists.
+// The options are comma separated key=value pairs: prefix sets the beginning of the key names
+// (Default: amass), and expire provides the number of minutes that the findings are kept.
+func NewRedisGraph(url, username, password, options string) (*RedisGraph, error) {
+	g := &RedisGraph{prefix: "amass"}
+
+	for _, opt := range strings.Split(options, ",") {
+		s := strings.SplitN(opt, "=", 2)
+		if len(s) != 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(s[0])
+		value := strings.TrimSpace(s[1])
+		switch key {
+		case "prefix":
+			g.prefix = value
+		case "expire":
+			minutes, err := strconv.Atoi(value)
+			if err != nil || minutes < 0 {
+				return nil, fmt.Errorf("NewRedisGraph: Invalid expire option: %s", value)
+			}
+			g.expire = minutes * 60
+		}
+	}
+
+	var dialopts []redis.DialOption
+	if username != "" {
+		dialopts = append(dialopts, redis.DialUsername(username))
+	}
+	if password != "" {
+		dialopts = append(dialopts, redis.DialPassword(password))
+	}
+
+	g.pool = &redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: 5 * time.Minute,
+		Dial: func() (redis.Conn, error) {
+			return redis.DialURL(url, dialopts...)
+		},
+	}
+
+	conn := g.pool.Get()
+	defer conn.Close()
+	if _, err := conn.Do("PING"); err != nil {
+		g.pool.Close()
+		return nil, fmt.Errorf("NewRedisGraph: Failed to connect to %s: %v", url, err)
+	}
+	return g, nil
+}
+
+// String implements the GraphDatabase interface.
+func (g *RedisGraph) String() string {
+	return "redis"
+}
+
+// Close implements the GraphDatabase interface.
+func (g *RedisGraph) Close() {
+	g.pool.Close()
+}
+
+func (g *RedisGraph) nodesKey() string {
+	return g.prefix + ":nodes"
+}
+
+func (g *RedisGraph) typeKey(ntype string) string {
+	return g.prefix + ":type:" + ntype
+}
+
+func (g *RedisGraph) outKey(id string) string {
+	return g.prefix + ":out:" + id
+}
+
+func (g *RedisGraph) inKey(id string) string {
+	return g.prefix + ":in:" + id
+}
+
+func (g *RedisGraph) propKey(id string) string {
+	return g.prefix + ":prop:" + id
+}
+
+// DumpGraph implements the GraphDatabase interface.
+func (g *RedisGraph) DumpGraph() string {
+	conn := g.pool.Get()
+	defer conn.Close()
+
+	nodes, err := redis.StringMap(conn.Do("HGETALL", g.nodesKey()))
+	if err != nil {
+		return ""
+	}
+
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var out strings.Builder
+	for _, id := range ids {
+		fmt.Fprintf(&out, "%s -> type -> %s\n", id, nodes[id])
+
+		for _, key := range []string{g.outKey(id), g.propKey(id)} {
+			members, _ := redis.Strings(conn.Do("SMEMBERS", key))
+			sort.Strings(members)
+
+			for _, m := range members {
+				if pred, obj, ok := splitMember(m); ok {
+					fmt.Fprintf(&out, "%s -> %s -> %s\n", id, pred, obj)
+				}
+			}
+		}
+	}
+	return out.String()
+}
+
+// NodeToID implements the GraphDatabase interface.
+func (g *RedisGraph) NodeToID(n Node) string {
+	if id, ok := n.(string); ok {
+		return id
+	}
+	return ""
+}
+
+// AllNodesOfType implements the GraphDatabase interface.
+func (g *RedisGraph) AllNodesOfType(ntypes ...string) ([]Node, error) {
+	conn := g.pool.Get()
+	defer conn.Close()
+
+	var ids []string
+	var err error
+	if len(ntypes) == 0 {
+		ids, err = redis.Strings(conn.Do("HKEYS", g.nodesKey()))
+	} else {
+		args := redis.Args{}
+		for _, t := range ntypes {
+			args = args.Add(g.typeKey(t))
+		}
+		ids, err = redis.Strings(conn.Do("SUNION", args...))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: AllNodesOfType: %v", g.String(), err)
+	}
+
+	var nodes []Node
+	for _, id := range ids {
+		nodes = append(nodes, id)
+	}
+	if len(nodes) == 0 {
+		return nodes, fmt.Errorf("%s: AllNodesOfType: No nodes found", g.String())
+	}
+	return nodes, nil
+}
+
+// AllOutNodes implements the GraphDatabase interface.
+func (g *RedisGraph) AllOutNodes(node Node) ([]Node, error) {
+	nstr := g.NodeToID(node)
+
+	edges, err := g.readEdges(g.outKey(nstr), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: AllOutNodes: %v", g.String(), err)
+	}
+
+	var nodes []Node
+	seen := make(map[string]struct{})
+	for _, e := range edges {
+		if _, found := seen[e[1]]; !found {
+			seen[e[1]] = struct{}{}
+			nodes = append(nodes, e[1])
+		}
+	}
+	if len(nodes) == 0 {
+		return nodes, fmt.Errorf("%s: AllOutNodes: No nodes found that %s has out edges to", g.String(), nstr)
+	}
+	return nodes, nil
+}
+
+// InsertNode implements the GraphDatabase interface.
+func (g *RedisGraph) InsertNode(id, ntype string) (Node, error) {
+	if id == "" || ntype == "" {
+		return nil, fmt.Errorf("%s: InsertNode: Empty required arguments", g.String())
+	}
+
+	conn := g.pool.Get()
+	defer conn.Close()
+
+	created, err := redis.Bool(conn.Do("HSETNX", g.nodesKey(), id, ntype))
+	if err != nil {
+		return nil, fmt.Errorf("%s: InsertNode: %v", g.String(), err)
+	}
+	if created {
+		if _, err := conn.Do("SADD", g.typeKey(ntype), id); err != nil {
+			return nil, fmt.Errorf("%s: InsertNode: %v", g.String(), err)
+		}
+		g.touch(conn, g.nodesKey(), g.typeKey(ntype))
+	}
+	return id, nil
+}
+
+// ReadNode implements the GraphDatabase interface.
+func (g *RedisGraph) ReadNode(id, ntype string) (Node, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%s: ReadNode: Empty node id provided", g.String())
+	}
+
+	if t, err := g.nodeType(id); err != nil || (ntype != "" && t != ntype) {
+		return nil, fmt.Errorf("%s: ReadNode: Node %s does not exist", g.String(), id)
+	}
+	return id, nil
+}
+
+// DeleteNode implements the GraphDatabase interface.
+func (g *RedisGraph) DeleteNode(node Node) error {
+	id := g.NodeToID(node)
+	if id == "" {
+		return fmt.Errorf("%s: DeleteNode: Empty node id provided", g.String())
+	}
+
+	ntype, err := g.nodeType(id)
+	if err != nil {
+		return fmt.Errorf("%s: DeleteNode: Node %s does not exist", g.String(), id)
+	}
+	out, _ := g.readEdges(g.outKey(id), nil)
+	in, _ := g.readEdges(g.inKey(id), nil)
+
+	conn := g.pool.Get()
+	defer conn.Close()
+
+	// Remove the other end of the edges before the node itself
+	conn.Send("MULTI")
+	for _, e := range out {
+		conn.Send("SREM", g.inKey(e[1]), joinMember(e[0], id))
+	}
+	for _, e := range in {
+		conn.Send("SREM", g.outKey(e[1]), joinMember(e[0], id))
+	}
+	conn.Send("DEL", g.outKey(id), g.inKey(id), g.propKey(id))
+	conn.Send("SREM", g.typeKey(ntype), id)
+	conn.Send("HDEL", g.nodesKey(), id)
+	if _, err := conn.Do("EXEC"); err != nil {
+		return fmt.Errorf("%s: DeleteNode: %v", g.String(), err)
+	}
+	return nil
+}
+
+// InsertEdge implements the GraphDatabase interface.
+func (g *RedisGraph) InsertEdge(edge *Edge) error {
+	if edge.Predicate == "" {
+		return fmt.Errorf("%s: InsertEdge: Empty edge predicate", g.String())
+	}
+	from := g.NodeToID(edge.From)
+	if _, err := g.nodeType(from); from == "" || err != nil {
+		return fmt.Errorf("%s: InsertEdge: Invalid from node", g.String())
+	}
+	to := g.NodeToID(edge.To)
+	if _, err := g.nodeType(to); to == "" || err != nil {
+		return fmt.Errorf("%s: InsertEdge: Invalid to node", g.String())
+	}
+
+	conn := g.pool.Get()
+	defer conn.Close()
+
+	conn.Send("MULTI")
+	conn.Send("SADD", g.outKey(from), joinMember(edge.Predicate, to))
+	conn.Send("SADD", g.inKey(to), joinMember(edge.Predicate, from))
+	if _, err := conn.Do("EXEC"); err != nil {
+		return fmt.Errorf("%s: InsertEdge: %v", g.String(), err)
+	}
+
+	g.touch(conn, g.outKey(from), g.inKey(to))
+	return nil
+}
+
+// ReadEdges implements the GraphDatabase interface.
+func (g *RedisGraph) ReadEdges(node Node, predicates ...string) ([]*Edge, error) {
+	var edges []*Edge
+
+	if e, err := g.ReadInEdges(node, predicates...); err == nil {
+		edges = append(edges, e...)
+	}
+
+	if e, err := g.ReadOutEdges(node, predicates...); err == nil {
+		edges = append(edges, e...)
+	}
+
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%s: ReadEdges: Failed to discover edges for the node %s", g.String(), g.NodeToID(node))
+	}
+	return edges, nil
+}
+
+// CountEdges implements the GraphDatabase interface.
+func (g *RedisGraph) CountEdges(node Node, predicates ...string) (int, error) {
+	var count int
+
+	if c, err := g.CountInEdges(node, predicates...); err == nil {
+		count += c
+	} else {
+		return 0, fmt.Errorf("%s: CountEdges: %v", g.String(), err)
+	}
+
+	if c, err := g.CountOutEdges(node, predicates...); err == nil {
+		count += c
+	}
+	return count, nil
+}
+
+// ReadInEdges implements the GraphDatabase interface.
+func (g *RedisGraph) ReadInEdges(node Node, predicates ...string) ([]*Edge, error) {
+	nstr := g.NodeToID(node)
+	if _, err := g.nodeType(nstr); nstr == "" || err != nil {
+		return nil, fmt.Errorf("%s: ReadInEdges: Invalid node reference argument", g.String())
+	}
+
+	members, err := g.readEdges(g.inKey(nstr), predicates)
+	if err != nil {
+		return nil, fmt.Errorf("%s: ReadInEdges: %v", g.String(), err)
+	}
+
+	var edges []*Edge
+	for _, m := range members {
+		edges = append(edges, &Edge{
+			Predicate: m[0],
+			From:      m[1],
+			To:        nstr,
+		})
+	}
+
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%s: ReadInEdges: Failed to discover edges coming into the node %s", g.String(), nstr)
+	}
+	return edges, nil
+}
+
+// CountInEdges implements the GraphDatabase interface.
+func (g *RedisGraph) CountInEdges(node Node, predicates ...string) (int, error) {
+	nstr := g.NodeToID(node)
+	if _, err := g.nodeType(nstr); nstr == "" || err != nil {
+		return 0, fmt.Errorf("%s: CountInEdges: Invalid node reference argument", g.String())
+	}
+
+	members, err := g.readEdges(g.inKey(nstr), predicates)
+	if err != nil {
+		return 0, fmt.Errorf("%s: CountInEdges: %v", g.String(), err)
+	}
+	return len(members), nil
+}
+
+// ReadOutEdges implements the GraphDatabase interface.
+func (g *RedisGraph) ReadOutEdges(node Node, predicates ...string) ([]*Edge, error) {
+	nstr := g.NodeToID(node)
+	if _, err := g.nodeType(nstr); nstr == "" || err != nil {
+		return nil, fmt.Errorf("%s: ReadOutEdges: Invalid node reference argument", g.String())
+	}
+
+	members, err := g.readEdges(g.outKey(nstr), predicates)
+	if err != nil {
+		return nil, fmt.Errorf("%s: ReadOutEdges: %v", g.String(), err)
+	}
+
+	var edges []*Edge
+	for _, m := range members {
+		edges = append(edges, &Edge{
+			Predicate: m[0],
+			From:      nstr,
+			To:        m[1],
+		})
+	}
+
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%s: ReadOutEdges: Failed to discover edges leaving the node %s", g.String(), nstr)
+	}
+	return edges, nil
+}
+
+// CountOutEdges implements the GraphDatabase interface.
+func (g *RedisGraph) CountOutEdges(node Node, predicates ...string) (int, error) {
+	nstr := g.NodeToID(node)
+	if _, err := g.nodeType(nstr); nstr == "" || err != nil {
+		return 0, fmt.Errorf("%s: CountOutEdges: Invalid node reference argument", g.String())
+	}
+
+	members, err := g.readEdges(g.outKey(nstr), predicates)
+	if err != nil {
+		return 0, fmt.Errorf("%s: CountOutEdges: %v", g.String(), err)
+	}
+	return len(members), nil
+}
+
+// CountOutNodesOfType implements the GraphDatabase interface.
+func (g *RedisGraph) CountOutNodesOfType(node Node, ntype, hasOut string, predicates ...string) (int, error) {
+	nstr := g.NodeToID(node)
+	if _, err := g.nodeType(nstr); nstr == "" || err != nil {
+		return 0, fmt.Errorf("%s: CountOutNodesOfType: Invalid node reference argument", g.String())
+	}
+
+	members, err := g.readEdges(g.outKey(nstr), predicates)
+	if err != nil {
+		return 0, fmt.Errorf("%s: CountOutNodesOfType: %v", g.String(), err)
+	}
+
+	var count int
+	seen := make(map[string]struct{})
+	for _, m := range members {
+		id := m[1]
+		if _, found := seen[id]; found {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		if t, err := g.nodeType(id); err != nil || t != ntype {
+			continue
+		}
+		if hasOut != "" {
+			if out, err := g.readEdges(g.outKey(id), []string{hasOut}); err != nil || len(out) == 0 {
+				continue
+			}
+		}
+		count++
+	}
+	return count, nil
+}
+
+// DeleteEdge implements the GraphDatabase interface.
+func (g *RedisGraph) DeleteEdge(edge *Edge) error {
+	from := g.NodeToID(edge.From)
+	to := g.NodeToID(edge.To)
+	if from == "" || to == "" {
+		return fmt.Errorf("%s: DeleteEdge: Invalid edge reference argument", g.String())
+	}
+
+	conn := g.pool.Get()
+	defer conn.Close()
+
+	removed, err := redis.Int(conn.Do("SREM", g.outKey(from), joinMember(edge.Predicate, to)))
+	if err != nil {
+		return fmt.Errorf("%s: DeleteEdge: %v", g.String(), err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("%s: DeleteEdge: The edge does not exist", g.String())
+	}
+
+	if _, err := conn.Do("SREM", g.inKey(to), joinMember(edge.Predicate, from)); err != nil {
+		return fmt.Errorf("%s: DeleteEdge: %v", g.String(), err)
+	}
+	return nil
+}
+
+// InsertProperty implements the GraphDatabase interface.
+func (g *RedisGraph) InsertProperty(node Node, predicate, value string) error {
+	nstr := g.NodeToID(node)
+	if _, err := g.nodeType(nstr); nstr == "" || err != nil {
+		return fmt.Errorf("%s: InsertProperty: Invalid node reference argument", g.String())
+	} else if predicate == "" {
+		return fmt.Errorf("%s: InsertProperty: Empty predicate argument", g.String())
+	}
+
+	conn := g.pool.Get()
+	defer conn.Close()
+
+	if _, err := conn.Do("SADD", g.propKey(nstr), joinMember(predicate, value)); err != nil {
+		return fmt.Errorf("%s: InsertProperty: %v", g.String(), err)
+	}
+
+	g.touch(conn, g.propKey(nstr))
+	return nil
+}
+
+// ReadProperties implements the GraphDatabase interface.
+func (g *RedisGraph) ReadProperties(node Node, predicates ...string) ([]*Property, error) {
+	nstr := g.NodeToID(node)
+	ntype, err := g.nodeType(nstr)
+	if nstr == "" || err != nil {
+		return nil, fmt.Errorf("%s: ReadProperties: Invalid node reference argument", g.String())
+	}
+
+	var properties []*Property
+	// The node type is provided as a property, as done by the Cayley quad stores
+	if len(predicates) == 0 || containsString(predicates, "type") {
+		properties = append(properties, &Property{
+			Predicate: "type",
+			Value:     ntype,
+		})
+	}
+
+	members, err := g.readEdges(g.propKey(nstr), predicates)
+	if err != nil {
+		return properties, fmt.Errorf("%s: ReadProperties: %v", g.String(), err)
+	}
+
+	for _, m := range members {
+		properties = append(properties, &Property{
+			Predicate: m[0],
+			Value:     m[1],
+		})
+	}
+	return properties, nil
+}
+
+// CountProperties implements the GraphDatabase interface.
+func (g *RedisGraph) CountProperties(node Node, predicates ...string) (int, error) {
+	properties, err := g.ReadProperties(node, predicates...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: CountProperties: Invalid node reference argument", g.String())
+	}
+	return len(properties), nil
+}
+
+// DeleteProperty implements the GraphDatabase interface.
+func (g *RedisGraph) DeleteProperty(node Node, predicate, value string) error {
+	nstr := g.NodeToID(node)
+	if nstr == "" {
+		return fmt.Errorf("%s: DeleteProperty: Invalid node reference argument", g.String())
+	}
+
+	conn := g.pool.Get()
+	defer conn.Close()
+
+	removed, err := redis.Int(conn.Do("SREM", g.propKey(nstr), joinMember(predicate, value)))
+	if err != nil {
+		return fmt.Errorf("%s: DeleteProperty: %v", g.String(), err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("%s: DeleteProperty: The property does not exist on node: %s", g.String(), nstr)
+	}
+	return nil
+}
+
+func (g *RedisGraph) nodeType(id string) (string, error) {
+	conn := g.pool.Get()
+	defer conn.Close()
+
+	return redis.String(conn.Do("HGET", g.nodesKey(), id))
+}
+
+// readEdges returns the predicate and object of the set members, sorted for consistent results.
+func (g *RedisGraph) readEdges(key string, predicates []string) ([][2]string, error) {
+	conn := g.pool.Get()
+	defer conn.Close()
+
+	members, err := redis.Strings(conn.Do("SMEMBERS", key))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(members)
+
+	var results [][2]string
+	for _, m := range members {
+		pred, obj, ok := splitMember(m)
+		if !ok || (len(predicates) > 0 && !containsString(predicates, pred)) {
+			continue
+		}
+
+		results = append(results, [2]string{pred, obj})
+	}
+	return results, nil
+}
+
+// touch sets the expiration of the keys when the findings are not kept indefinitely.
+func (g *RedisGraph) touch(conn redis.Conn, keys ...string) {
+	if g.expire <= 0 {
+		return
+	}
+
+	for _, key := range keys {
+		conn.Send("EXPIRE", key, g.expire)
+	}
+	conn.Flush()
+	for range keys {
+		conn.Receive()
+	}
+}
+
+func joinMember(predicate, object string) string {
+	return predicate + redisSep + object
+}
+
+func splitMember(member string) (string, string, bool) {
+	parts := strings.SplitN(member, redisSep, 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
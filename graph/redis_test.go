@@ -0,0 +1,80 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRedisMembers(t *testing.T) {
+	member := joinMember("a_record", "192.0.2.1")
+	if pred, obj, ok := splitMember(member); !ok || pred != "a_record" || obj != "192.0.2.1" {
+		t.Errorf("splitMember returned %s, %s, %t", pred, obj, ok)
+	}
+	if _, _, ok := splitMember("a_record"); ok {
+		t.Errorf("splitMember accepted a member without the separator")
+	}
+
+	if _, err := NewRedisGraph("redis://localhost:6379", "", "", "expire=soon"); err == nil {
+		t.Errorf("NewRedisGraph accepted an invalid expire option")
+	}
+}
+
+func TestRedisGraph(t *testing.T) {
+	url := os.Getenv("AMASS_REDIS_URL")
+	if url == "" {
+		t.Skip("AMASS_REDIS_URL is not set")
+	}
+
+	db, err := NewRedisGraph(url, os.Getenv("AMASS_REDIS_USERNAME"), os.Getenv("AMASS_REDIS_PASSWORD"), "prefix=amasstest,expire=5")
+	if err != nil {
+		t.Fatalf("Failed to connect to the Redis server: %v", err)
+	}
+	g := NewGraph(db)
+	defer g.Close()
+
+	if _, err := g.InsertEvent("redistest"); err != nil {
+		t.Fatalf("Failed to insert the event: %v", err)
+	}
+	if err := g.InsertA("www.redistest.owasp.org", "192.0.2.1", "DNS", "dns", "redistest"); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	defer func() {
+		for _, id := range []string{"redistest", "www.redistest.owasp.org", "owasp.org", "192.0.2.1", "DNS"} {
+			if node, err := db.ReadNode(id, ""); err == nil {
+				_ = db.DeleteNode(node)
+			}
+		}
+	}()
+
+	node, err := db.ReadNode("www.redistest.owasp.org", "fqdn")
+	if err != nil {
+		t.Fatalf("Failed to read the FQDN node: %v", err)
+	}
+	if n, err := db.CountOutEdges(node, "a_record"); err != nil || n != 1 {
+		t.Errorf("CountOutEdges returned %d, %v", n, err)
+	}
+	if sources, err := g.NodeSources(node, "redistest"); err != nil || len(sources) != 1 || sources[0] != "DNS" {
+		t.Errorf("NodeSources returned %v, %v", sources, err)
+	}
+
+	if err := db.InsertProperty(node, "open_port", "443"); err != nil {
+		t.Fatalf("Failed to insert the property: %v", err)
+	}
+	if n, err := db.CountProperties(node); err != nil || n != 2 {
+		t.Errorf("CountProperties returned %d, %v instead of the type and port", n, err)
+	}
+	if err := db.DeleteProperty(node, "open_port", "443"); err != nil {
+		t.Errorf("Failed to delete the property: %v", err)
+	}
+
+	addr, _ := db.ReadNode("192.0.2.1", "ipaddr")
+	if err := db.DeleteNode(node); err != nil {
+		t.Fatalf("Failed to delete the FQDN node: %v", err)
+	}
+	if n, err := db.CountInEdges(addr, "a_record"); err != nil || n != 0 {
+		t.Errorf("DeleteNode left %d edges pointing at the address", n)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// InsertReputation records the classification of the address by a reputation provider as a property of the
+// ipaddr node. Only the most recent classification from each provider is kept.
+func (g *Graph) InsertReputation(addr string, info *requests.ReputationInfo) error {
+	if addr == "" {
+		return errors.New("Graph: InsertReputation: Invalid address provided")
+	}
+	if info == nil || info.Source == "" || info.Classification == "" {
+		return errors.New("Graph: InsertReputation: Invalid reputation provided")
+	}
+
+	// The provider can answer before the address has been entered by the DNS record
+	node, err := g.InsertNodeIfNotExist(addr, "ipaddr")
+	if err != nil {
+		return err
+	}
+
+	if properties, err := g.db.ReadProperties(node, "reputation"); err == nil {
+		for _, p := range properties {
+			var prev requests.ReputationInfo
+
+			if err := json.Unmarshal([]byte(p.Value), &prev); err != nil || prev.Source != info.Source {
+				continue
+			}
+			if prev.Checked.After(info.Checked) {
+				return nil
+			}
+			g.db.DeleteProperty(node, p.Predicate, p.Value)
+		}
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return g.db.InsertProperty(node, "reputation", string(data))
+}
+
+// AddressReputation returns the most recent classification of the address by each reputation provider,
+// sorted by the name of the provider.
+func (g *Graph) AddressReputation(addr string) []*requests.ReputationInfo {
+	node, err := g.db.ReadNode(addr, "ipaddr")
+	if err != nil {
+		return nil
+	}
+
+	properties, err := g.db.ReadProperties(node, "reputation")
+	if err != nil {
+		return nil
+	}
+
+	// Merged graphs can hold several classifications from a provider
+	latest := make(map[string]*requests.ReputationInfo)
+	for _, p := range properties {
+		var info requests.ReputationInfo
+
+		if err := json.Unmarshal([]byte(p.Value), &info); err != nil {
+			continue
+		}
+		if prev, found := latest[info.Source]; !found || info.Checked.After(prev.Checked) {
+			latest[info.Source] = &info
+		}
+	}
+
+	var results []*requests.ReputationInfo
+	for _, info := range latest {
+		results = append(results, info)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Source < results[j].Source })
+	return results
+}
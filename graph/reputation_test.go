@@ -0,0 +1,55 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestReputation(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+	defer g.Close()
+
+	addr := "192.0.2.10"
+	uuid := "ef9f9475-34ce-4c9a-9e5f-5aafd2ab4e9b"
+	if err := g.InsertReputation("", &requests.ReputationInfo{Source: "AbuseIPDB", Classification: "benign"}); err == nil {
+		t.Errorf("InsertReputation did not return an error when provided an empty address")
+	}
+	if err := g.InsertReputation(addr, &requests.ReputationInfo{Source: "AbuseIPDB"}); err == nil {
+		t.Errorf("InsertReputation did not return an error when provided an empty classification")
+	}
+	if _, err := g.InsertAddress(addr, "test", "dns", uuid); err != nil {
+		t.Fatalf("Failed to insert the address: %v", err)
+	}
+	if reps := g.AddressReputation(addr); len(reps) != 0 {
+		t.Errorf("AddressReputation returned %d classifications before any were inserted", len(reps))
+	}
+
+	now := time.Now().UTC()
+	for _, info := range []*requests.ReputationInfo{
+		{Source: "GreyNoise", Classification: requests.ReputationBenign, Categories: []string{"riot"}, Checked: now},
+		{Source: "AbuseIPDB", Classification: requests.ReputationSuspicious, Score: 40, Checked: now.Add(-time.Hour)},
+		{Source: "AbuseIPDB", Classification: requests.ReputationMalicious, Score: 90, Checked: now},
+		// Older classifications do not replace the recent ones
+		{Source: "GreyNoise", Classification: requests.ReputationMalicious, Checked: now.Add(-time.Hour)},
+	} {
+		if err := g.InsertReputation(addr, info); err != nil {
+			t.Errorf("InsertReputation returned an error: %v", err)
+		}
+	}
+
+	reps := g.AddressReputation(addr)
+	if len(reps) != 2 {
+		t.Fatalf("AddressReputation returned %d classifications instead of one per provider", len(reps))
+	}
+	if reps[0].Source != "AbuseIPDB" || reps[0].Classification != requests.ReputationMalicious || reps[0].Score != 90 {
+		t.Errorf("AddressReputation returned %+v for AbuseIPDB", reps[0])
+	}
+	if reps[1].Source != "GreyNoise" || reps[1].Classification != requests.ReputationBenign || len(reps[1].Categories) != 1 {
+		t.Errorf("AddressReputation returned %+v for GreyNoise", reps[1])
+	}
+}
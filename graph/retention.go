@@ -0,0 +1,199 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/OWASP/Amass/v3/stringset"
+)
+
+// The node types that only remain in the graph while an event references them.
+var prunedNodeTypes = []string{"fqdn", "ipaddr", "netblock", "as", "email"}
+
+// RetentionPolicy determines which events are removed from the graph by PruneEvents.
+type RetentionPolicy struct {
+	// Events that finished longer ago than MaxAge are removed (zero keeps them all)
+	MaxAge time.Duration
+
+	// Only the most recent MaxEvents events of each root domain are kept (zero keeps them all)
+	MaxEvents int
+}
+
+// Enabled returns true when the policy would remove any events.
+func (p *RetentionPolicy) Enabled() bool {
+	return p != nil && (p.MaxAge > 0 || p.MaxEvents > 0)
+}
+
+// PruneEvents removes the events selected by the retention policy, along with the findings that
+// are no longer referenced by the remaining events. An event spanning several root domains is only
+// removed when it falls outside the MaxEvents most recent events for each of the domains. The UUIDs
+// of the removed events are returned.
+func (g *Graph) PruneEvents(policy *RetentionPolicy, now time.Time) ([]string, error) {
+	if !policy.Enabled() {
+		return nil, nil
+	}
+
+	type event struct {
+		uuid   string
+		finish time.Time
+	}
+
+	var events []*event
+	for _, uuid := range g.EventList() {
+		start, finish := g.EventDateRange(uuid)
+		if finish.IsZero() {
+			finish = start
+		}
+
+		events = append(events, &event{uuid: uuid, finish: finish})
+	}
+	// Put the most recent events first
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].finish.After(events[j].finish)
+	})
+
+	expired := stringset.New()
+	if policy.MaxAge > 0 {
+		cutoff := now.Add(-policy.MaxAge)
+
+		for _, e := range events {
+			if e.finish.Before(cutoff) {
+				expired.Insert(e.uuid)
+			}
+		}
+	}
+
+	if policy.MaxEvents > 0 {
+		kept := stringset.New()
+		counts := make(map[string]int)
+
+		for _, e := range events {
+			if expired.Has(e.uuid) {
+				continue
+			}
+
+			domains := g.EventDomains(e.uuid)
+			for _, d := range domains {
+				if counts[d] < policy.MaxEvents {
+					kept.Insert(e.uuid)
+				}
+				counts[d]++
+			}
+			// Events without a scope are left alone
+			if len(domains) > 0 && !kept.Has(e.uuid) {
+				expired.Insert(e.uuid)
+			}
+		}
+	}
+
+	removed := expired.Slice()
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	for _, uuid := range removed {
+		if err := g.DeleteEvent(uuid); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := g.deleteUnreferencedNodes(); err != nil {
+		return removed, err
+	}
+	return removed, nil
+}
+
+// DeleteEvent removes the event node identified by the uuid parameter. The findings of the event
+// remain in the graph until PruneEvents removes the nodes that are no longer referenced.
+func (g *Graph) DeleteEvent(uuid string) error {
+	event, err := g.db.ReadNode(uuid, "event")
+	if err != nil {
+		return fmt.Errorf("Graph: DeleteEvent: Failed to find the event %s", uuid)
+	}
+
+	g.eventFinishLock.Lock()
+	delete(g.eventFinishes, uuid)
+	g.eventFinishLock.Unlock()
+
+	return g.db.DeleteNode(event)
+}
+
+// RemoveEvent deletes the event identified by the uuid parameter along with the findings that are
+// not referenced by any other event. The number of findings removed from the graph is returned.
+func (g *Graph) RemoveEvent(uuid string) (int, error) {
+	event, err := g.db.ReadNode(uuid, "event")
+	if err != nil {
+		return 0, fmt.Errorf("Graph: RemoveEvent: Failed to find the event %s", uuid)
+	}
+
+	pruned := stringset.New(prunedNodeTypes...)
+	// Collect the findings before the edges from the event are deleted along with it
+	var findings []Node
+	if edges, err := g.db.ReadOutEdges(event); err == nil {
+		seen := stringset.New()
+
+		for _, edge := range edges {
+			id := g.db.NodeToID(edge.To)
+			if seen.Has(id) || !pruned.Has(g.nodeType(edge.To)) {
+				continue
+			}
+
+			seen.Insert(id)
+			findings = append(findings, edge.To)
+		}
+	}
+
+	if err := g.DeleteEvent(uuid); err != nil {
+		return 0, err
+	}
+
+	var removed int
+	events := stringset.New(g.EventList()...)
+	for _, node := range findings {
+		if g.referencedByEvents(node, events) {
+			continue
+		}
+
+		if err := g.db.DeleteNode(node); err != nil {
+			return removed, fmt.Errorf("Graph: RemoveEvent: Failed to delete %s: %v", g.db.NodeToID(node), err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+func (g *Graph) deleteUnreferencedNodes() error {
+	events := stringset.New(g.EventList()...)
+
+	nodes, err := g.db.AllNodesOfType(prunedNodeTypes...)
+	if err != nil {
+		// The graph holds no findings
+		return nil
+	}
+
+	for _, node := range nodes {
+		if !g.referencedByEvents(node, events) {
+			if err := g.db.DeleteNode(node); err != nil {
+				return fmt.Errorf("Graph: PruneEvents: Failed to delete %s: %v", g.db.NodeToID(node), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g *Graph) referencedByEvents(node Node, events stringset.Set) bool {
+	if edges, err := g.db.ReadInEdges(node); err == nil {
+		for _, edge := range edges {
+			if events.Has(g.db.NodeToID(edge.From)) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"testing"
+	"time"
+)
+
+func insertDatedEvent(t *testing.T, g *Graph, uuid, name string, finish time.Time) {
+	if _, err := g.InsertFQDN(name, "DNS", "dns", uuid); err != nil {
+		t.Fatalf("Failed to insert %s into the event %s: %v", name, uuid, err)
+	}
+
+	event, _ := g.db.ReadNode(uuid, "event")
+	for _, pred := range []string{"start", "finish"} {
+		if p, err := g.db.ReadProperties(event, pred); err == nil {
+			for _, prop := range p {
+				_ = g.db.DeleteProperty(event, pred, prop.Value)
+			}
+		}
+		_ = g.db.InsertProperty(event, pred, finish.Format(time.RFC3339))
+	}
+}
+
+func TestPruneEvents(t *testing.T) {
+	now := time.Now()
+	g := NewGraph(NewCayleyGraphMemory())
+
+	insertDatedEvent(t, g, "old", "old.owasp.org", now.Add(-60*24*time.Hour))
+	insertDatedEvent(t, g, "older", "www.owasp.org", now.Add(-10*24*time.Hour))
+	insertDatedEvent(t, g, "recent", "www.owasp.org", now.Add(-24*time.Hour))
+	insertDatedEvent(t, g, "other", "www.example.com", now.Add(-5*24*time.Hour))
+
+	if removed, err := g.PruneEvents(&RetentionPolicy{}, now); err != nil || len(removed) != 0 {
+		t.Errorf("An empty policy removed the events %v: %v", removed, err)
+	}
+
+	removed, err := g.PruneEvents(&RetentionPolicy{MaxAge: 30 * 24 * time.Hour}, now)
+	if err != nil || !checkTestResult([]string{"old"}, removed) {
+		t.Errorf("The maximum age removed the events %v: %v", removed, err)
+	}
+	if _, err := g.db.ReadNode("old.owasp.org", "fqdn"); err == nil {
+		t.Errorf("The name only referenced by the removed event remained in the graph")
+	}
+	if _, err := g.db.ReadNode("owasp.org", "fqdn"); err != nil {
+		t.Errorf("The domain referenced by the remaining events was removed")
+	}
+
+	removed, err = g.PruneEvents(&RetentionPolicy{MaxEvents: 1}, now)
+	if err != nil || !checkTestResult([]string{"older"}, removed) {
+		t.Errorf("The maximum events removed the events %v: %v", removed, err)
+	}
+	if !checkTestResult([]string{"recent", "other"}, g.EventList()) {
+		t.Errorf("The remaining events were %v", g.EventList())
+	}
+	if _, err := g.db.ReadNode("www.owasp.org", "fqdn"); err != nil {
+		t.Errorf("The name referenced by the most recent event was removed")
+	}
+
+	if err := g.DeleteEvent("older"); err == nil {
+		t.Errorf("DeleteEvent did not fail for a missing event")
+	}
+}
+
+func TestRemoveEvent(t *testing.T) {
+	now := time.Now()
+	g := NewGraph(NewCayleyGraphMemory())
+
+	insertDatedEvent(t, g, "bad", "bad.owasp.org", now)
+	insertDatedEvent(t, g, "good", "www.owasp.org", now)
+	if _, err := g.InsertFQDN("www.owasp.org", "DNS", "dns", "bad"); err != nil {
+		t.Fatalf("Failed to insert the shared name: %v", err)
+	}
+
+	// The name bad.owasp.org is the only finding exclusively owned by the event
+	if removed, err := g.RemoveEvent("bad"); err != nil || removed != 1 {
+		t.Errorf("RemoveEvent removed %d findings: %v", removed, err)
+	}
+	if !checkTestResult([]string{"good"}, g.EventList()) {
+		t.Errorf("The remaining events were %v", g.EventList())
+	}
+	if _, err := g.db.ReadNode("bad.owasp.org", "fqdn"); err == nil {
+		t.Errorf("The name only referenced by the removed event remained in the graph")
+	}
+	for _, name := range []string{"www.owasp.org", "owasp.org"} {
+		if _, err := g.db.ReadNode(name, "fqdn"); err != nil {
+			t.Errorf("The name %s referenced by the remaining event was removed", name)
+		}
+	}
+
+	if _, err := g.RemoveEvent("bad"); err == nil {
+		t.Errorf("RemoveEvent did not fail for a missing event")
+	}
+}
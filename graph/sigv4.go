@@ -0,0 +1,99 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+	// The SHA-256 digest of the empty payload sent with the WebSocket handshake
+	sigV4EmptyPayload = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// awsCredentials holds the AWS access keys used to sign the requests with Signature Version 4.
+type awsCredentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// envAWSCredentials returns the AWS access keys provided by the standard environment variables.
+func envAWSCredentials() *awsCredentials {
+	return &awsCredentials{
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// signV4 returns the headers that authenticate a request without a payload using AWS Signature
+// Version 4, which Amazon Neptune requires from the WebSocket handshake when IAM authentication
+// is enabled. The host includes the port when it is not the default for the scheme.
+func signV4(method, host, path, region, service string, creds *awsCredentials, now time.Time) http.Header {
+	amzdate := now.UTC().Format(sigV4TimeFormat)
+	date := amzdate[:8]
+
+	headers := map[string]string{
+		"host":       host,
+		"x-amz-date": amzdate,
+	}
+	if creds.SessionToken != "" {
+		headers["x-amz-security-token"] = creds.SessionToken
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	canonical.WriteString(method + "\n")
+	if path == "" {
+		path = "/"
+	}
+	canonical.WriteString(path + "\n")
+	// The handshake does not carry a query string
+	canonical.WriteString("\n")
+	for _, name := range names {
+		canonical.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signed := strings.Join(names, ";")
+	canonical.WriteString("\n" + signed + "\n" + sigV4EmptyPayload)
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	digest := sha256.Sum256([]byte(canonical.String()))
+	tosign := sigV4Algorithm + "\n" + amzdate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretKey), date)
+	for _, part := range []string{region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, tosign))
+
+	h := make(http.Header)
+	h.Set("X-Amz-Date", amzdate)
+	if creds.SessionToken != "" {
+		h.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+	h.Set("Authorization", sigV4Algorithm+" Credential="+creds.AccessKey+"/"+scope+
+		", SignedHeaders="+signed+", Signature="+signature)
+	return h
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,36 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignV4(t *testing.T) {
+	// The get-vanilla case from the AWS Signature Version 4 test suite
+	creds := &awsCredentials{
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	now := time.Date(2015, time.August, 30, 12, 36, 0, 0, time.UTC)
+
+	h := signV4("GET", "example.amazonaws.com", "/", "us-east-1", "service", creds, now)
+	expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if auth := h.Get("Authorization"); auth != expected {
+		t.Errorf("signV4 returned the Authorization header %s", auth)
+	}
+	if date := h.Get("X-Amz-Date"); date != "20150830T123600Z" {
+		t.Errorf("signV4 returned the X-Amz-Date header %s", date)
+	}
+
+	creds.SessionToken = "token"
+	h = signV4("GET", "example.amazonaws.com", "/gremlin", "us-east-1", "neptune-db", creds, now)
+	if h.Get("X-Amz-Security-Token") != "token" ||
+		!strings.Contains(h.Get("Authorization"), "SignedHeaders=host;x-amz-date;x-amz-security-token,") {
+		t.Errorf("signV4 did not sign the session token: %v", h)
+	}
+}
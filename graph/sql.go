@@ -0,0 +1,627 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The tables are prefixed so they can share a database with the Cayley SQL quad store,
+// which uses a table named nodes of its own.
+var sqlSchema = []string{
+	`CREATE TABLE IF NOT EXISTS amass_nodes (
+		id TEXT NOT NULL PRIMARY KEY,
+		ntype TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS amass_nodes_ntype_idx ON amass_nodes (ntype)`,
+	`CREATE TABLE IF NOT EXISTS amass_edges (
+		from_id TEXT NOT NULL REFERENCES amass_nodes (id),
+		predicate TEXT NOT NULL,
+		to_id TEXT NOT NULL REFERENCES amass_nodes (id),
+		PRIMARY KEY (from_id, predicate, to_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS amass_edges_to_idx ON amass_edges (to_id, predicate)`,
+	`CREATE TABLE IF NOT EXISTS amass_properties (
+		node_id TEXT NOT NULL REFERENCES amass_nodes (id),
+		predicate TEXT NOT NULL,
+		value TEXT NOT NULL,
+		PRIMARY KEY (node_id, predicate, value)
+	)`,
+}
+
+// SQLGraph implements the GraphDatabase interface using nodes, edges and properties tables
+// in a relational database, which can be shared by concurrent Amass processes.
+type SQLGraph struct {
+	db   *sql.DB
+	name string
+}
+
+// NewSQLGraph returns an SQLGraph for the database handle, after creating the tables and
+// indexes when they do not already exist.
+func NewSQLGraph(name string, db *sql.DB) (*SQLGraph, error) {
+	if db == nil {
+		return nil, fmt.Errorf("%s: NewSQLGraph: The database handle was not provided", name)
+	}
+
+	for _, stmt := range sqlSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("%s: NewSQLGraph: Failed to create the schema: %v", name, err)
+		}
+	}
+
+	return &SQLGraph{
+		db:   db,
+		name: name,
+	}, nil
+}
+
+// String implements the GraphDatabase interface.
+func (g *SQLGraph) String() string {
+	return g.name
+}
+
+// Close implements the GraphDatabase interface.
+func (g *SQLGraph) Close() {
+	g.db.Close()
+}
+
+// DumpGraph implements the GraphDatabase interface.
+func (g *SQLGraph) DumpGraph() string {
+	var out strings.Builder
+
+	for _, query := range []string{
+		"SELECT id, 'type', ntype FROM amass_nodes",
+		"SELECT from_id, predicate, to_id FROM amass_edges",
+		"SELECT node_id, predicate, value FROM amass_properties",
+	} {
+		rows, err := g.db.Query(query)
+		if err != nil {
+			continue
+		}
+
+		for rows.Next() {
+			var subject, predicate, object string
+
+			if err := rows.Scan(&subject, &predicate, &object); err == nil {
+				fmt.Fprintf(&out, "%s -> %s -> %s\n", subject, predicate, object)
+			}
+		}
+		rows.Close()
+	}
+
+	return out.String()
+}
+
+// NodeToID implements the GraphDatabase interface.
+func (g *SQLGraph) NodeToID(n Node) string {
+	if id, ok := n.(string); ok {
+		return id
+	}
+	return ""
+}
+
+// AllNodesOfType implements the GraphDatabase interface.
+func (g *SQLGraph) AllNodesOfType(ntypes ...string) ([]Node, error) {
+	query := "SELECT id FROM amass_nodes"
+	args := stringArgs(ntypes)
+	if len(args) > 0 {
+		query += " WHERE ntype IN (" + placeholders(1, len(args)) + ")"
+	}
+
+	nodes, err := g.queryNodes(query, args...)
+	if err != nil {
+		return nodes, fmt.Errorf("%s: AllNodesOfType: %v", g.String(), err)
+	}
+	if len(nodes) == 0 {
+		return nodes, fmt.Errorf("%s: AllNodesOfType: No nodes found", g.String())
+	}
+	return nodes, nil
+}
+
+// AllOutNodes implements the GraphDatabase interface.
+func (g *SQLGraph) AllOutNodes(node Node) ([]Node, error) {
+	nstr := g.NodeToID(node)
+
+	nodes, err := g.queryNodes("SELECT DISTINCT to_id FROM amass_edges WHERE from_id = $1", nstr)
+	if err != nil {
+		return nodes, fmt.Errorf("%s: AllOutNodes: %v", g.String(), err)
+	}
+	if len(nodes) == 0 {
+		return nodes, fmt.Errorf("%s: AllOutNodes: No nodes found that %s has out edges to", g.String(), nstr)
+	}
+	return nodes, nil
+}
+
+// InsertNode implements the GraphDatabase interface.
+func (g *SQLGraph) InsertNode(id, ntype string) (Node, error) {
+	if id == "" || ntype == "" {
+		return nil, fmt.Errorf("%s: InsertNode: Empty required arguments", g.String())
+	}
+
+	if _, err := g.db.Exec("INSERT INTO amass_nodes (id, ntype) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING", id, ntype); err != nil {
+		return nil, fmt.Errorf("%s: InsertNode: %v", g.String(), err)
+	}
+	return id, nil
+}
+
+// ReadNode implements the GraphDatabase interface.
+func (g *SQLGraph) ReadNode(id, ntype string) (Node, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%s: ReadNode: Empty node id provided", g.String())
+	}
+
+	if !g.nodeExists(id, ntype) {
+		return nil, fmt.Errorf("%s: ReadNode: Node %s does not exist", g.String(), id)
+	}
+	return id, nil
+}
+
+// DeleteNode implements the GraphDatabase interface.
+func (g *SQLGraph) DeleteNode(node Node) error {
+	id := g.NodeToID(node)
+	if id == "" {
+		return fmt.Errorf("%s: DeleteNode: Empty node id provided", g.String())
+	}
+	if !g.nodeExists(id, "") {
+		return fmt.Errorf("%s: DeleteNode: Node %s does not exist", g.String(), id)
+	}
+
+	tx, err := g.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: DeleteNode: %v", g.String(), err)
+	}
+
+	for _, stmt := range []string{
+		"DELETE FROM amass_edges WHERE from_id = $1 OR to_id = $1",
+		"DELETE FROM amass_properties WHERE node_id = $1",
+		"DELETE FROM amass_nodes WHERE id = $1",
+	} {
+		if _, err := tx.Exec(stmt, id); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%s: DeleteNode: %v", g.String(), err)
+		}
+	}
+	return tx.Commit()
+}
+
+// insertBatch writes the nodes, properties and edges of a migration batch in a single transaction.
+func (g *SQLGraph) insertBatch(nodes []*migrateNode, edges []*Edge) error {
+	tx, err := g.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: insertBatch: %v", g.String(), err)
+	}
+
+	exec := func(stmt string, args ...interface{}) error {
+		if _, err := tx.Exec(stmt, args...); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%s: insertBatch: %v", g.String(), err)
+		}
+		return nil
+	}
+
+	for _, node := range nodes {
+		if node.id == "" || node.ntype == "" {
+			continue
+		}
+		if err := exec("INSERT INTO amass_nodes (id, ntype) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING",
+			node.id, node.ntype); err != nil {
+			return err
+		}
+
+		for _, p := range node.properties {
+			if err := exec(`INSERT INTO amass_properties (node_id, predicate, value) VALUES ($1, $2, $3)
+				ON CONFLICT (node_id, predicate, value) DO NOTHING`, node.id, p.Predicate, p.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, edge := range edges {
+		if err := exec(`INSERT INTO amass_edges (from_id, predicate, to_id) VALUES ($1, $2, $3)
+			ON CONFLICT (from_id, predicate, to_id) DO NOTHING`,
+			g.NodeToID(edge.From), edge.Predicate, g.NodeToID(edge.To)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// InsertEdge implements the GraphDatabase interface.
+func (g *SQLGraph) InsertEdge(edge *Edge) error {
+	if edge.Predicate == "" {
+		return fmt.Errorf("%s: InsertEdge: Empty edge predicate", g.String())
+	}
+	from := g.NodeToID(edge.From)
+	if from == "" || !g.nodeExists(from, "") {
+		return fmt.Errorf("%s: InsertEdge: Invalid from node", g.String())
+	}
+	to := g.NodeToID(edge.To)
+	if to == "" || !g.nodeExists(to, "") {
+		return fmt.Errorf("%s: InsertEdge: Invalid to node", g.String())
+	}
+
+	_, err := g.db.Exec(`INSERT INTO amass_edges (from_id, predicate, to_id) VALUES ($1, $2, $3)
+		ON CONFLICT (from_id, predicate, to_id) DO NOTHING`, from, edge.Predicate, to)
+	if err != nil {
+		return fmt.Errorf("%s: InsertEdge: %v", g.String(), err)
+	}
+	return nil
+}
+
+// ReadEdges implements the GraphDatabase interface.
+func (g *SQLGraph) ReadEdges(node Node, predicates ...string) ([]*Edge, error) {
+	var edges []*Edge
+
+	if e, err := g.ReadInEdges(node, predicates...); err == nil {
+		edges = append(edges, e...)
+	}
+
+	if e, err := g.ReadOutEdges(node, predicates...); err == nil {
+		edges = append(edges, e...)
+	}
+
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%s: ReadEdges: Failed to discover edges for the node %s", g.String(), g.NodeToID(node))
+	}
+	return edges, nil
+}
+
+// CountEdges implements the GraphDatabase interface.
+func (g *SQLGraph) CountEdges(node Node, predicates ...string) (int, error) {
+	var count int
+
+	if c, err := g.CountInEdges(node, predicates...); err == nil {
+		count += c
+	} else {
+		return 0, fmt.Errorf("%s: CountEdges: %v", g.String(), err)
+	}
+
+	if c, err := g.CountOutEdges(node, predicates...); err == nil {
+		count += c
+	}
+	return count, nil
+}
+
+// ReadInEdges implements the GraphDatabase interface.
+func (g *SQLGraph) ReadInEdges(node Node, predicates ...string) ([]*Edge, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" || !g.nodeExists(nstr, "") {
+		return nil, fmt.Errorf("%s: ReadInEdges: Invalid node reference argument", g.String())
+	}
+
+	edges, err := g.queryEdges("from_id", "to_id", nstr, predicates)
+	if err != nil {
+		return nil, fmt.Errorf("%s: ReadInEdges: %v", g.String(), err)
+	}
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%s: ReadInEdges: Failed to discover edges coming into the node %s", g.String(), nstr)
+	}
+	return edges, nil
+}
+
+// CountInEdges implements the GraphDatabase interface.
+func (g *SQLGraph) CountInEdges(node Node, predicates ...string) (int, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" || !g.nodeExists(nstr, "") {
+		return 0, fmt.Errorf("%s: CountInEdges: Invalid node reference argument", g.String())
+	}
+
+	return g.countEdges("to_id", nstr, predicates)
+}
+
+// ReadOutEdges implements the GraphDatabase interface.
+func (g *SQLGraph) ReadOutEdges(node Node, predicates ...string) ([]*Edge, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" || !g.nodeExists(nstr, "") {
+		return nil, fmt.Errorf("%s: ReadOutEdges: Invalid node reference argument", g.String())
+	}
+
+	edges, err := g.queryEdges("to_id", "from_id", nstr, predicates)
+	if err != nil {
+		return nil, fmt.Errorf("%s: ReadOutEdges: %v", g.String(), err)
+	}
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("%s: ReadOutEdges: Failed to discover edges leaving the node %s", g.String(), nstr)
+	}
+	return edges, nil
+}
+
+// CountOutEdges implements the GraphDatabase interface.
+func (g *SQLGraph) CountOutEdges(node Node, predicates ...string) (int, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" || !g.nodeExists(nstr, "") {
+		return 0, fmt.Errorf("%s: CountOutEdges: Invalid node reference argument", g.String())
+	}
+
+	return g.countEdges("from_id", nstr, predicates)
+}
+
+// CountOutNodesOfType implements the GraphDatabase interface.
+func (g *SQLGraph) CountOutNodesOfType(node Node, ntype, hasOut string, predicates ...string) (int, error) {
+	nstr := g.NodeToID(node)
+	if nstr == "" || !g.nodeExists(nstr, "") {
+		return 0, fmt.Errorf("%s: CountOutNodesOfType: Invalid node reference argument", g.String())
+	}
+
+	query := `SELECT COUNT(DISTINCT e.to_id) FROM amass_edges e
+		JOIN amass_nodes n ON n.id = e.to_id WHERE e.from_id = $1 AND n.ntype = $2`
+	args := []interface{}{nstr, ntype}
+	if preds := stringArgs(predicates); len(preds) > 0 {
+		query += " AND e.predicate IN (" + placeholders(len(args)+1, len(preds)) + ")"
+		args = append(args, preds...)
+	}
+	if hasOut != "" {
+		args = append(args, hasOut)
+		query += " AND EXISTS (SELECT 1 FROM amass_edges h WHERE h.from_id = e.to_id AND h.predicate = $" +
+			strconv.Itoa(len(args)) + ")"
+	}
+
+	var count int
+	if err := g.db.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: CountOutNodesOfType: %v", g.String(), err)
+	}
+	return count, nil
+}
+
+// DeleteEdge implements the GraphDatabase interface.
+func (g *SQLGraph) DeleteEdge(edge *Edge) error {
+	from := g.NodeToID(edge.From)
+	to := g.NodeToID(edge.To)
+	if from == "" || !g.nodeExists(from, "") || to == "" || !g.nodeExists(to, "") {
+		return fmt.Errorf("%s: DeleteEdge: Invalid edge reference argument", g.String())
+	}
+
+	res, err := g.db.Exec("DELETE FROM amass_edges WHERE from_id = $1 AND predicate = $2 AND to_id = $3",
+		from, edge.Predicate, to)
+	if err != nil {
+		return fmt.Errorf("%s: DeleteEdge: %v", g.String(), err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%s: DeleteEdge: The edge does not exist", g.String())
+	}
+	return nil
+}
+
+// InsertProperty implements the GraphDatabase interface.
+func (g *SQLGraph) InsertProperty(node Node, predicate, value string) error {
+	nstr := g.NodeToID(node)
+	if nstr == "" || !g.nodeExists(nstr, "") {
+		return fmt.Errorf("%s: InsertProperty: Invalid node reference argument", g.String())
+	} else if predicate == "" {
+		return fmt.Errorf("%s: InsertProperty: Empty predicate argument", g.String())
+	}
+
+	_, err := g.db.Exec(`INSERT INTO amass_properties (node_id, predicate, value) VALUES ($1, $2, $3)
+		ON CONFLICT (node_id, predicate, value) DO NOTHING`, nstr, predicate, value)
+	if err != nil {
+		return fmt.Errorf("%s: InsertProperty: %v", g.String(), err)
+	}
+	return nil
+}
+
+// ReadProperties implements the GraphDatabase interface.
+func (g *SQLGraph) ReadProperties(node Node, predicates ...string) ([]*Property, error) {
+	var properties []*Property
+
+	nstr := g.NodeToID(node)
+	ntype, err := g.nodeType(nstr)
+	if nstr == "" || err != nil {
+		return properties, fmt.Errorf("%s: ReadProperties: Invalid node reference argument", g.String())
+	}
+
+	query := "SELECT predicate, value FROM amass_properties WHERE node_id = $1"
+	args := []interface{}{nstr}
+	preds := stringArgs(predicates)
+	if len(preds) > 0 {
+		query += " AND predicate IN (" + placeholders(2, len(preds)) + ")"
+		args = append(args, preds...)
+	}
+
+	// The node type is provided as a property, as done by the Cayley quad stores
+	if len(preds) == 0 || containsString(predicates, "type") {
+		properties = append(properties, &Property{
+			Predicate: "type",
+			Value:     ntype,
+		})
+	}
+
+	rows, err := g.db.Query(query, args...)
+	if err != nil {
+		return properties, fmt.Errorf("%s: ReadProperties: %v", g.String(), err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := new(Property)
+
+		if err := rows.Scan(&p.Predicate, &p.Value); err != nil {
+			return properties, fmt.Errorf("%s: ReadProperties: %v", g.String(), err)
+		}
+		properties = append(properties, p)
+	}
+	return properties, rows.Err()
+}
+
+// CountProperties implements the GraphDatabase interface.
+func (g *SQLGraph) CountProperties(node Node, predicates ...string) (int, error) {
+	properties, err := g.ReadProperties(node, predicates...)
+	if err != nil {
+		return 0, fmt.Errorf("%s: CountProperties: Invalid node reference argument", g.String())
+	}
+	return len(properties), nil
+}
+
+// DeleteProperty implements the GraphDatabase interface.
+func (g *SQLGraph) DeleteProperty(node Node, predicate, value string) error {
+	nstr := g.NodeToID(node)
+	if nstr == "" || !g.nodeExists(nstr, "") {
+		return fmt.Errorf("%s: DeleteProperty: Invalid node reference argument", g.String())
+	}
+
+	res, err := g.db.Exec("DELETE FROM amass_properties WHERE node_id = $1 AND predicate = $2 AND value = $3",
+		nstr, predicate, value)
+	if err != nil {
+		return fmt.Errorf("%s: DeleteProperty: %v", g.String(), err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%s: DeleteProperty: The property does not exist on node: %s", g.String(), nstr)
+	}
+	return nil
+}
+
+func (g *SQLGraph) nodeExists(id, ntype string) bool {
+	t, err := g.nodeType(id)
+
+	return err == nil && (ntype == "" || t == ntype)
+}
+
+func (g *SQLGraph) nodeType(id string) (string, error) {
+	var ntype string
+
+	err := g.db.QueryRow("SELECT ntype FROM amass_nodes WHERE id = $1", id).Scan(&ntype)
+	return ntype, err
+}
+
+func (g *SQLGraph) queryNodes(query string, args ...interface{}) ([]Node, error) {
+	var nodes []Node
+
+	rows, err := g.db.Query(query, args...)
+	if err != nil {
+		return nodes, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+
+		if err := rows.Scan(&id); err != nil {
+			return nodes, err
+		}
+		nodes = append(nodes, id)
+	}
+	return nodes, rows.Err()
+}
+
+// queryEdges returns the edges with the node in the column opposite to the other column.
+func (g *SQLGraph) queryEdges(other, column, node string, predicates []string) ([]*Edge, error) {
+	query := "SELECT e.predicate, e." + other + " FROM amass_edges e WHERE e." + column + " = $1"
+	args := []interface{}{node}
+	if preds := stringArgs(predicates); len(preds) > 0 {
+		query += " AND e.predicate IN (" + placeholders(2, len(preds)) + ")"
+		args = append(args, preds...)
+	}
+
+	rows, err := g.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edges []*Edge
+	for rows.Next() {
+		var pred, id string
+
+		if err := rows.Scan(&pred, &id); err != nil {
+			return nil, err
+		}
+
+		edge := &Edge{
+			Predicate: pred,
+			From:      node,
+			To:        id,
+		}
+		if column == "to_id" {
+			edge.From, edge.To = id, node
+		}
+		edges = append(edges, edge)
+	}
+	return edges, rows.Err()
+}
+
+func (g *SQLGraph) countEdges(column, node string, predicates []string) (int, error) {
+	query := "SELECT COUNT(*) FROM amass_edges WHERE " + column + " = $1"
+	args := []interface{}{node}
+	if preds := stringArgs(predicates); len(preds) > 0 {
+		query += " AND predicate IN (" + placeholders(2, len(preds)) + ")"
+		args = append(args, preds...)
+	}
+
+	var count int
+	if err := g.db.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %v", g.String(), err)
+	}
+	return count, nil
+}
+
+// placeholders returns n numbered query parameters, starting with the number provided.
+func placeholders(start, n int) string {
+	params := make([]string, n)
+
+	for i := range params {
+		params[i] = "$" + strconv.Itoa(start+i)
+	}
+	return strings.Join(params, ", ")
+}
+
+// stringArgs returns the unique strings as query arguments.
+func stringArgs(strs []string) []interface{} {
+	var args []interface{}
+
+	seen := make(map[string]struct{})
+	for _, s := range strs {
+		if _, found := seen[s]; !found {
+			seen[s] = struct{}{}
+			args = append(args, s)
+		}
+	}
+	return args
+}
+
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
+
+// compact reclaims the space of the removed rows and rebuilds the indexes of the tables.
+func (g *SQLGraph) compact(ctx context.Context) (*CompactStats, error) {
+	stats := &CompactStats{Before: g.size(ctx)}
+
+	stmts := []string{"VACUUM"}
+	if g.name == "postgresql" {
+		// The full vacuum rewrites the tables and their indexes into new files
+		stmts = []string{"VACUUM FULL amass_nodes", "VACUUM FULL amass_edges", "VACUUM FULL amass_properties"}
+	}
+	for _, stmt := range stmts {
+		if _, err := g.db.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("%s: Compact: %v", g.String(), err)
+		}
+	}
+
+	stats.After = g.size(ctx)
+	return stats, nil
+}
+
+// size returns the number of bytes used by the database, or zero when it cannot be obtained.
+func (g *SQLGraph) size(ctx context.Context) int64 {
+	query := "SELECT pg_database_size(current_database())"
+	if g.name == "sqlite" {
+		// The pages of the write-ahead log are moved into the database file first
+		g.db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)")
+		query = "SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()"
+	}
+
+	var size int64
+	if err := g.db.QueryRowContext(ctx, query).Scan(&size); err != nil {
+		return 0
+	}
+	return size
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// +build cgo,!purego
+
+package graph
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3" // Registers the sqlite3 database/sql driver
+)
+
+// The name of the SQLite database file created when a directory is provided.
+const sqliteFileName = "amass.sqlite"
+
+// The SQLite driver requires cgo, which the pure Go builds go without.
+const sqliteAvailable = true
+
+// NewSQLiteGraph returns an SQLGraph that stores the nodes and edges in an SQLite database file.
+// When the path is a directory, the amass.sqlite file within it is used. The options are comma
+// separated key=value pairs added to the connection string, such as _busy_timeout=10000.
+func NewSQLiteGraph(path, options string) (*SQLGraph, error) {
+	if path == "" {
+		return nil, fmt.Errorf("NewSQLiteGraph: The database file was not provided")
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, sqliteFileName)
+	}
+
+	// The write-ahead log keeps the database consistent when the process is terminated abruptly
+	q := url.Values{}
+	q.Set("_journal_mode", "WAL")
+	q.Set("_busy_timeout", "5000")
+	for _, opt := range strings.Split(options, ",") {
+		s := strings.SplitN(opt, "=", 2)
+		if len(s) != 2 {
+			continue
+		}
+
+		q.Set(strings.TrimSpace(s[0]), strings.TrimSpace(s[1]))
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?"+q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("NewSQLiteGraph: %v", err)
+	}
+	// SQLite allows a single writer, so the operations share one connection
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("NewSQLiteGraph: Failed to open %s: %v", path, err)
+	}
+
+	g, err := NewSQLGraph("sqlite", db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return g, nil
+}
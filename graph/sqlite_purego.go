@@ -0,0 +1,16 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// +build !cgo purego
+
+package graph
+
+import "fmt"
+
+// The SQLite driver is implemented in C, so it cannot be provided by pure Go binaries.
+const sqliteAvailable = false
+
+// NewSQLiteGraph returns an error, since this binary was built without cgo.
+func NewSQLiteGraph(path, options string) (*SQLGraph, error) {
+	return nil, fmt.Errorf("NewSQLiteGraph: The sqlite system requires a binary built with cgo")
+}
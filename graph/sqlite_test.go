@@ -0,0 +1,192 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// +build cgo,!purego
+
+package graph
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteGraph(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := NewSQLiteGraph(dir, "")
+	if err != nil {
+		t.Fatalf("Failed to create the SQLite graph: %v", err)
+	}
+	g := NewGraph(db)
+
+	if _, err := g.InsertEvent("sqlitetest"); err != nil {
+		t.Fatalf("Failed to insert the event: %v", err)
+	}
+	if _, err := g.InsertFQDN("www.owasp.org", "DNS", "dns", "sqlitetest"); err != nil {
+		t.Fatalf("Failed to insert the FQDN: %v", err)
+	}
+	if err := g.InsertA("www.owasp.org", "192.0.2.1", "DNS", "dns", "sqlitetest"); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+
+	node, err := db.ReadNode("www.owasp.org", "fqdn")
+	if err != nil {
+		t.Fatalf("Failed to read the FQDN node: %v", err)
+	}
+	if _, err := db.ReadNode("www.owasp.org", "ipaddr"); err == nil {
+		t.Errorf("ReadNode returned the node for the wrong type")
+	}
+	if n, err := db.CountOutEdges(node, "a_record"); err != nil || n != 1 {
+		t.Errorf("CountOutEdges returned %d, %v", n, err)
+	}
+	if n, err := db.CountOutNodesOfType("sqlitetest", "fqdn", "root"); err != nil || n != 1 {
+		t.Errorf("CountOutNodesOfType returned %d, %v", n, err)
+	}
+
+	if err := db.InsertProperty(node, "open_port", "443"); err != nil {
+		t.Fatalf("Failed to insert the property: %v", err)
+	}
+	if err := db.InsertProperty(node, "open_port", "443"); err != nil {
+		t.Errorf("Inserting a duplicate property failed: %v", err)
+	}
+	if n, err := db.CountProperties(node); err != nil || n != 2 {
+		t.Errorf("CountProperties returned %d, %v instead of the type and port", n, err)
+	}
+	if err := db.DeleteProperty(node, "open_port", "80"); err == nil {
+		t.Errorf("DeleteProperty did not fail for a missing property")
+	}
+	g.Close()
+
+	// The findings remain available after the database file is opened again
+	db, err = NewSQLiteGraph(filepath.Join(dir, sqliteFileName), "")
+	if err != nil {
+		t.Fatalf("Failed to open the SQLite graph again: %v", err)
+	}
+	g = NewGraph(db)
+	defer g.Close()
+
+	if events := g.EventsInScope("owasp.org"); len(events) != 1 || events[0] != "sqlitetest" {
+		t.Errorf("EventsInScope returned %v after opening the database again", events)
+	}
+	if err := db.DeleteNode("www.owasp.org"); err != nil {
+		t.Errorf("Failed to delete the FQDN node: %v", err)
+	}
+	if _, err := db.ReadInEdges("192.0.2.1", "a_record"); err == nil {
+		t.Errorf("The edges of the deleted node remained in the graph")
+	}
+}
+
+func TestSQLiteRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cayley := NewGraph(NewCayleyGraphMemory())
+	if err := cayley.InsertA("www.example.domain", "192.0.2.1", "DNS", "dns", "migration"); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+
+	var dump bytes.Buffer
+	if err := cayley.Dump(&dump); err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+
+	db, err := NewSQLiteGraph(dir, "")
+	if err != nil {
+		t.Fatalf("Failed to create the SQLite graph: %v", err)
+	}
+	g := NewGraph(db)
+	defer g.Close()
+
+	if _, _, err := g.Restore(bytes.NewReader(dump.Bytes())); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	var again bytes.Buffer
+	if err := g.Dump(&again); err != nil {
+		t.Fatalf("Dump of the SQLite graph failed: %v", err)
+	}
+	if again.String() != dump.String() {
+		t.Errorf("The SQLite graph produced a different dump:\n%s\n%s", dump.String(), again.String())
+	}
+}
+
+func TestSQLiteMigrateEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cayley := migrateTestGraph(t, "migration", MigrateBatchSize)
+
+	db, err := NewSQLiteGraph(dir, "")
+	if err != nil {
+		t.Fatalf("Failed to create the SQLite graph: %v", err)
+	}
+	g := NewGraph(db)
+	defer g.Close()
+
+	if err := cayley.MigrateEvents(g, "migration"); err != nil {
+		t.Fatalf("Failed to migrate the event into the SQLite graph: %v", err)
+	}
+	if got, expected := len(g.EventFQDNs("migration")), len(cayley.EventFQDNs("migration")); got != expected {
+		t.Errorf("%d FQDNs were migrated into the SQLite graph, expected %d", got, expected)
+	}
+
+	back := NewGraph(NewCayleyGraphMemory())
+	if err := g.MigrateEvents(back, "migration"); err != nil {
+		t.Fatalf("Failed to migrate the event from the SQLite graph: %v", err)
+	}
+
+	var expected, got bytes.Buffer
+	if err := cayley.Dump(&expected); err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+	if err := back.Dump(&got); err != nil {
+		t.Fatalf("Dump of the migrated graph failed: %v", err)
+	}
+	if got.String() != expected.String() {
+		t.Errorf("The migration through the SQLite graph produced a different dump")
+	}
+}
+
+func TestSQLiteCompact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := NewSQLiteGraph(dir, "")
+	if err != nil {
+		t.Fatalf("Failed to create the SQLite graph: %v", err)
+	}
+	g := NewGraph(db)
+	defer g.Close()
+
+	if err := migrateTestGraph(t, "compact", 500).MigrateEvents(g, "compact"); err != nil {
+		t.Fatalf("Failed to migrate the event into the SQLite graph: %v", err)
+	}
+	if _, err := g.RemoveEvent("compact"); err != nil {
+		t.Fatalf("Failed to remove the event: %v", err)
+	}
+
+	stats, err := g.Compact(context.Background())
+	if err != nil {
+		t.Fatalf("Compact returned an error: %v", err)
+	}
+	if stats.Before == 0 || stats.After >= stats.Before {
+		t.Errorf("Compact reported %d bytes before and %d bytes after", stats.Before, stats.After)
+	}
+}
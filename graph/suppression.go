@@ -0,0 +1,96 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Suppression hides a name from the reports and notifications until it expires.
+type Suppression struct {
+	Name  string
+	Until time.Time
+}
+
+func suppressionID(name string) string {
+	return name + "-suppression"
+}
+
+// InsertSuppression hides the name from the reports and notifications until the time provided.
+// An existing suppression of the name is replaced.
+func (g *Graph) InsertSuppression(name string, until time.Time) error {
+	name = strings.ToLower(strings.Trim(strings.TrimSpace(name), "."))
+	if name == "" {
+		return errors.New("Graph: InsertSuppression: Invalid name provided")
+	}
+
+	node, err := g.InsertNodeIfNotExist(suppressionID(name), "suppression")
+	if err != nil {
+		return err
+	}
+
+	for _, pred := range []string{"name", "until"} {
+		if p, err := g.db.ReadProperties(node, pred); err == nil {
+			for _, prop := range p {
+				if err := g.db.DeleteProperty(node, pred, prop.Value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if err := g.db.InsertProperty(node, "name", name); err != nil {
+		return err
+	}
+	return g.db.InsertProperty(node, "until", until.UTC().Format(time.RFC3339))
+}
+
+// DeleteSuppression removes the suppression of the name, so the name appears in the reports again.
+func (g *Graph) DeleteSuppression(name string) error {
+	name = strings.ToLower(strings.Trim(strings.TrimSpace(name), "."))
+
+	node, err := g.db.ReadNode(suppressionID(name), "suppression")
+	if err != nil {
+		return fmt.Errorf("Graph: DeleteSuppression: The name %s is not suppressed", name)
+	}
+	return g.db.DeleteNode(node)
+}
+
+// Suppressions returns the suppressions that have not expired at the time provided, sorted by name.
+func (g *Graph) Suppressions(now time.Time) []*Suppression {
+	nodes, err := g.db.AllNodesOfType("suppression")
+	if err != nil {
+		return nil
+	}
+
+	var results []*Suppression
+	for _, node := range nodes {
+		properties, err := g.db.ReadProperties(node, "name", "until")
+		if err != nil {
+			continue
+		}
+
+		s := new(Suppression)
+		for _, p := range properties {
+			if p.Predicate == "name" {
+				s.Name = p.Value
+			} else if p.Predicate == "until" {
+				s.Until, _ = time.Parse(time.RFC3339, p.Value)
+			}
+		}
+		// Expired suppressions are kept, but the names reappear in the reports
+		if s.Name != "" && s.Until.After(now) {
+			results = append(results, s)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
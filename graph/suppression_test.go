@@ -0,0 +1,47 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSuppressions(t *testing.T) {
+	now := time.Now()
+	g := NewGraph(NewCayleyGraphMemory())
+
+	if err := g.InsertSuppression("", now); err == nil {
+		t.Errorf("InsertSuppression accepted an empty name")
+	}
+	if err := g.InsertSuppression("WWW.owasp.org.", now.Add(time.Hour)); err != nil {
+		t.Fatalf("Failed to insert the suppression: %v", err)
+	}
+	if err := g.InsertSuppression("old.owasp.org", now.Add(-time.Hour)); err != nil {
+		t.Fatalf("Failed to insert the expired suppression: %v", err)
+	}
+
+	s := g.Suppressions(now)
+	if len(s) != 1 || s[0].Name != "www.owasp.org" {
+		t.Fatalf("Suppressions returned %v instead of the unexpired suppression", s)
+	}
+
+	// The suppression is replaced when the name is suppressed again
+	if err := g.InsertSuppression("www.owasp.org", now.Add(-time.Minute)); err != nil {
+		t.Fatalf("Failed to replace the suppression: %v", err)
+	}
+	if s := g.Suppressions(now); len(s) != 0 {
+		t.Errorf("The replaced suppression did not expire: %v", s)
+	}
+	if s := g.Suppressions(now.Add(-2 * time.Hour)); len(s) != 2 {
+		t.Errorf("Suppressions returned %d entries before both expired", len(s))
+	}
+
+	if err := g.DeleteSuppression("www.owasp.org"); err != nil {
+		t.Errorf("Failed to delete the suppression: %v", err)
+	}
+	if err := g.DeleteSuppression("www.owasp.org"); err == nil {
+		t.Errorf("DeleteSuppression did not fail for a missing suppression")
+	}
+}
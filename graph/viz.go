@@ -5,99 +5,229 @@ package graph
 
 import (
 	"math/rand"
+	"time"
 
 	"github.com/OWASP/Amass/v3/stringset"
 	"github.com/OWASP/Amass/v3/viz"
 )
 
+// The number of goroutines reading the graph while the visualizations are streamed.
+const vizWorkers = 10
+
+// The predicates of the edges included in the visualizations.
+var vizPredicates = []string{"root", "cname_record", "a_record", "aaaa_record",
+	"ptr_record", "service", "srv_record", "ns_record", "mx_record", "contains", "prefix"}
+
+// vizEventRange holds the start and finish of an event included in the visualization.
+type vizEventRange struct {
+	Start  time.Time
+	Finish time.Time
+}
+
+type vizNodeRef struct {
+	ID         string
+	ActualType string
+}
+
 // VizData returns the current state of the Graph as viz package Nodes and Edges.
-func (g *Graph) VizData(uuid string) ([]viz.Node, []viz.Edge) {
-	event, err := g.db.ReadNode(uuid, "event")
-	if err != nil {
-		return nil, nil
-	}
+// When multiple event UUIDs are provided, the data is merged into one visualization
+// and each node is attributed to the first event in the list that discovered it.
+func (g *Graph) VizData(uuids ...string) ([]viz.Node, []viz.Edge) {
+	c := new(vizCollector)
 
-	discovered, err := g.db.ReadOutEdges(event)
-	if err != nil {
+	if err := g.StreamVizData(c, uuids...); err != nil || len(c.nodes) == 0 {
 		return nil, nil
 	}
+	return c.nodes, c.edges
+}
 
-	nodes, nodeIdx := g.vizNodes(uuid, discovered)
-	edges := g.vizEdges(nodes, nodeIdx)
+// StreamVizData sends the same nodes and edges as VizData to the viz Writer while traversing the
+// Graph, so the visualization does not need to be held in memory. The graph is read by a bounded
+// pool of goroutines, and the nodes and edges are written in the same order as VizData returns them.
+// The writer is closed once the last edge has been written.
+func (g *Graph) StreamVizData(w viz.Writer, uuids ...string) error {
+	var refs []vizNodeRef
+	nodeIdx := make(map[string]int)
 
-	return nodes, edges
-}
+	ranges := make(map[string]vizEventRange, len(uuids))
+	for _, uuid := range uuids {
+		start, finish := g.EventDateRange(uuid)
+		ranges[uuid] = vizEventRange{Start: start, Finish: finish}
+	}
 
-// Identify unique nodes that should be included in the visualization.
-func (g *Graph) vizNodes(uuid string, edges []*Edge) ([]viz.Node, map[string]int) {
-	var idx int
-	var nodes []viz.Node
-	ids := stringset.New()
-	nodeToIdx := make(map[string]int)
+	for _, uuid := range uuids {
+		event, err := g.db.ReadNode(uuid, "event")
+		if err != nil {
+			continue
+		}
 
-	for _, d := range edges {
-		if id := g.db.NodeToID(d.To); id != "" && !ids.Has(id) {
-			ids.Insert(id)
-
-			properties, err := g.db.ReadProperties(d.To, "type")
-			// We do not print the source, event or response nodes in the graph visualizations
-			if err != nil || len(properties) == 0 ||
-				properties[0].Value == "source" ||
-				properties[0].Value == "event" ||
-				properties[0].Value == "response" {
-				continue
-			}
+		discovered, err := g.db.ReadOutEdges(event)
+		if err != nil {
+			continue
+		}
 
-			// We do not print the TLD nodes in the graph visualizations
-			if g.IsTLDNode(id) {
-				continue
+		candidates := g.vizCandidates(discovered, nodeIdx)
+		err = orderedWork(len(candidates), func(i int) interface{} {
+			return g.vizNode(candidates[i], uuid, ranges)
+		}, func(v interface{}) error {
+			n := v.(*viz.Node)
+			if n == nil {
+				return nil
 			}
 
-			if n := g.buildVizNode(d.To, properties[0].Value, uuid); n != nil {
-				n.ID = idx
-				// Keep track of which indices nodes were assigned to
-				nodeToIdx[id] = idx
-				idx++
-				nodes = append(nodes, *n)
+			n.ID = len(refs)
+			// Keep track of which indices nodes were assigned to
+			nodeIdx[n.Label] = n.ID
+			refs = append(refs, vizNodeRef{ID: n.Label, ActualType: n.ActualType})
+			return w.WriteNode(n)
+		})
+		if err != nil {
+			w.Close()
+			return err
+		}
+	}
+
+	err := orderedWork(len(refs), func(i int) interface{} {
+		return g.vizEdges(i, refs[i], nodeIdx)
+	}, func(v interface{}) error {
+		for _, e := range v.([]viz.Edge) {
+			if err := w.WriteEdge(&e); err != nil {
+				return err
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		w.Close()
+		return err
 	}
 
-	return nodes, nodeToIdx
+	return w.Close()
 }
 
-// Identify the edges between nodes that should be included in the visualization.
-func (g *Graph) vizEdges(nodes []viz.Node, nodeToIdx map[string]int) []viz.Edge {
-	var edges []viz.Edge
+// Identify the unique nodes discovered by an event that have not been included by an earlier event.
+func (g *Graph) vizCandidates(edges []*Edge, nodeToIdx map[string]int) []Node {
+	var nodes []Node
+	ids := stringset.New()
 
-	for _, n := range nodes {
-		node, err := g.db.ReadNode(n.Label, n.ActualType)
-		if err != nil {
+	for _, d := range edges {
+		id := g.db.NodeToID(d.To)
+		if id == "" || ids.Has(id) {
 			continue
 		}
+		ids.Insert(id)
 
-		e, err := g.db.ReadOutEdges(node, "root", "cname_record",
-			"a_record", "aaaa_record", "ptr_record", "service",
-			"srv_record", "ns_record", "mx_record", "contains", "prefix")
-		if err != nil || len(e) == 0 {
+		// Was this node already included by an earlier event?
+		if _, found := nodeToIdx[id]; found {
 			continue
 		}
 
-		for _, edge := range e {
-			if toID, found := nodeToIdx[g.db.NodeToID(edge.To)]; found {
-				edges = append(edges, viz.Edge{
-					From:  n.ID,
-					To:    toID,
-					Title: edge.Predicate,
-				})
-			}
+		nodes = append(nodes, d.To)
+	}
+
+	return nodes
+}
+
+// Build the visualization node, or return nil when the node should not be included.
+func (g *Graph) vizNode(node Node, uuid string, ranges map[string]vizEventRange) *viz.Node {
+	properties, err := g.db.ReadProperties(node, "type")
+	// We do not print the source, event or response nodes in the graph visualizations
+	if err != nil || len(properties) == 0 ||
+		properties[0].Value == "source" ||
+		properties[0].Value == "event" ||
+		properties[0].Value == "response" {
+		return nil
+	}
+
+	// We do not print the TLD nodes in the graph visualizations
+	if g.IsTLDNode(g.db.NodeToID(node)) {
+		return nil
+	}
+
+	return g.buildVizNode(node, properties[0].Value, uuid, ranges)
+}
+
+// Identify the edges from the node that should be included in the visualization.
+func (g *Graph) vizEdges(idx int, ref vizNodeRef, nodeToIdx map[string]int) []viz.Edge {
+	node, err := g.db.ReadNode(ref.ID, ref.ActualType)
+	if err != nil {
+		return nil
+	}
+
+	e, err := g.db.ReadOutEdges(node, vizPredicates...)
+	if err != nil || len(e) == 0 {
+		return nil
+	}
+
+	var edges []viz.Edge
+	for _, edge := range e {
+		if toID, found := nodeToIdx[g.db.NodeToID(edge.To)]; found {
+			edges = append(edges, viz.Edge{
+				From:  idx,
+				To:    toID,
+				Title: edge.Predicate,
+			})
 		}
 	}
 
 	return edges
 }
 
-func (g *Graph) buildVizNode(node Node, ntype, uuid string) *viz.Node {
+// orderedWork performs the work for each index using a bounded number of goroutines, and
+// provides the results to the emit function in order of the indices. The emit function
+// is always executed by the calling goroutine, and the first error it returns is provided.
+func orderedWork(count int, work func(int) interface{}, emit func(interface{}) error) error {
+	pending := make(chan chan interface{}, vizWorkers)
+
+	go func() {
+		defer close(pending)
+
+		for i := 0; i < count; i++ {
+			result := make(chan interface{}, 1)
+			// Blocks while the maximum number of results are outstanding
+			pending <- result
+
+			go func(i int) {
+				result <- work(i)
+			}(i)
+		}
+	}()
+
+	var err error
+	for result := range pending {
+		v := <-result
+		// Continue draining the results so that no goroutines are left behind
+		if err == nil {
+			err = emit(v)
+		}
+	}
+	return err
+}
+
+// vizCollector is a viz Writer that keeps the nodes and edges in memory.
+type vizCollector struct {
+	nodes []viz.Node
+	edges []viz.Edge
+}
+
+// WriteNode implements the viz Writer interface.
+func (c *vizCollector) WriteNode(n *viz.Node) error {
+	c.nodes = append(c.nodes, *n)
+	return nil
+}
+
+// WriteEdge implements the viz Writer interface.
+func (c *vizCollector) WriteEdge(e *viz.Edge) error {
+	c.edges = append(c.edges, *e)
+	return nil
+}
+
+// Close implements the viz Writer interface.
+func (c *vizCollector) Close() error {
+	return nil
+}
+
+func (g *Graph) buildVizNode(node Node, ntype, uuid string, ranges map[string]vizEventRange) *viz.Node {
 	id := g.db.NodeToID(node)
 
 	edges, err := g.db.ReadInEdges(node)
@@ -106,11 +236,23 @@ func (g *Graph) buildVizNode(node Node, ntype, uuid string) *viz.Node {
 	}
 
 	var sources []string
+	var first, last time.Time
 	// Select one of the data sources to be used in the visualization
 	for _, edge := range edges {
-		if g.db.NodeToID(edge.From) == uuid {
+		from := g.db.NodeToID(edge.From)
+		if from == uuid {
 			sources = append(sources, edge.Predicate)
 		}
+
+		// The node was seen during each of the included events that discovered it
+		if r, found := ranges[from]; found {
+			if !r.Start.IsZero() && (first.IsZero() || r.Start.Before(first)) {
+				first = r.Start
+			}
+			if r.Finish.After(last) {
+				last = r.Finish
+			}
+		}
 	}
 
 	if len(sources) == 0 {
@@ -130,7 +272,11 @@ func (g *Graph) buildVizNode(node Node, ntype, uuid string) *viz.Node {
 		Label:      id,
 		Title:      title,
 		Source:     src,
+		SourceTag:  g.SourceTag(src),
 		ActualType: ntype,
+		Event:      uuid,
+		FirstSeen:  first,
+		LastSeen:   last,
 	}
 }
 
@@ -1,7 +1,12 @@
 package graph
 
 import (
+	"errors"
+	"fmt"
 	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/viz"
 )
 
 func TestViz(t *testing.T) {
@@ -36,3 +41,135 @@ func TestViz(t *testing.T) {
 	}
 
 }
+
+func TestVizDataMultipleEvents(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+
+	if err := g.InsertA("www.example.domain", "127.0.0.1", "test", "foo", "event1"); err != nil {
+		t.Fatalf("Error inserting A record.\n%v", err)
+	}
+	if err := g.InsertA("www.example.domain", "127.0.0.1", "test", "foo", "event2"); err != nil {
+		t.Fatalf("Error inserting A record.\n%v", err)
+	}
+	if err := g.InsertA("dev.example.domain", "127.0.0.2", "test", "foo", "event2"); err != nil {
+		t.Fatalf("Error inserting A record.\n%v", err)
+	}
+
+	single, _ := g.VizData("event1")
+	merged, edges := g.VizData("event1", "event2")
+	if len(merged) <= len(single) || len(edges) == 0 {
+		t.Errorf("VizData failed to merge the nodes from multiple events")
+	}
+
+	labels := make(map[string]string)
+	for _, n := range merged {
+		if _, found := labels[n.Label]; found {
+			t.Errorf("VizData returned the node %s more than once", n.Label)
+		}
+		labels[n.Label] = n.Event
+	}
+	if e := labels["www.example.domain"]; e != "event1" {
+		t.Errorf("VizData attributed www.example.domain to %s instead of the first event", e)
+	}
+	if e := labels["dev.example.domain"]; e != "event2" {
+		t.Errorf("VizData attributed dev.example.domain to %s instead of event2", e)
+	}
+}
+
+func TestVizDataSeenTimes(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+
+	first := time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+	insertDatedEvent(t, g, "earlier", "www.owasp.org", first)
+	insertDatedEvent(t, g, "later", "www.owasp.org", first.Add(48*time.Hour))
+	insertDatedEvent(t, g, "later", "api.owasp.org", first.Add(48*time.Hour))
+
+	nodes, _ := g.VizData("earlier", "later")
+	seen := make(map[string]viz.Node)
+	for _, n := range nodes {
+		seen[n.Label] = n
+	}
+
+	if n := seen["www.owasp.org"]; !n.FirstSeen.Equal(first) || !n.LastSeen.Equal(first.Add(48*time.Hour)) {
+		t.Errorf("VizData provided %s and %s for the name seen by both events", n.FirstSeen, n.LastSeen)
+	}
+	if n := seen["api.owasp.org"]; !n.FirstSeen.Equal(first.Add(48 * time.Hour)) {
+		t.Errorf("VizData provided %s for the name seen by the later event", n.FirstSeen)
+	}
+
+	// Only the events included in the visualization provide the times
+	nodes, _ = g.VizData("later")
+	for _, n := range nodes {
+		if n.Label == "www.owasp.org" && !n.FirstSeen.Equal(first.Add(48*time.Hour)) {
+			t.Errorf("VizData provided %s from an event that was not included", n.FirstSeen)
+		}
+	}
+}
+
+func TestStreamVizData(t *testing.T) {
+	g := NewGraph(NewCayleyGraphMemory())
+
+	for i := 1; i <= 25; i++ {
+		fqdn := fmt.Sprintf("host%d.example.domain", i)
+		addr := fmt.Sprintf("127.0.0.%d", i)
+
+		if err := g.InsertA(fqdn, addr, "test", "foo", "event"); err != nil {
+			t.Fatalf("Error inserting A record.\n%v", err)
+		}
+	}
+
+	nodes, edges := g.VizData("event")
+	for i, n := range nodes {
+		if n.ID != i {
+			t.Errorf("VizData assigned the ID %d to the node at index %d", n.ID, i)
+		}
+	}
+
+	c := new(vizCollector)
+	if err := g.StreamVizData(c, "event"); err != nil {
+		t.Fatalf("StreamVizData failed: %v", err)
+	}
+	if len(c.nodes) != len(nodes) || len(c.edges) != len(edges) {
+		t.Fatalf("StreamVizData provided %d nodes and %d edges, expected %d and %d",
+			len(c.nodes), len(c.edges), len(nodes), len(edges))
+	}
+	for i, n := range c.nodes {
+		if n.Label != nodes[i].Label {
+			t.Errorf("StreamVizData provided %s at index %d instead of %s", n.Label, i, nodes[i].Label)
+		}
+	}
+}
+
+func TestOrderedWork(t *testing.T) {
+	var results []int
+
+	err := orderedWork(100, func(i int) interface{} {
+		return i * 2
+	}, func(v interface{}) error {
+		results = append(results, v.(int))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("orderedWork returned an error: %v", err)
+	}
+
+	for i, v := range results {
+		if v != i*2 {
+			t.Fatalf("orderedWork emitted %d at position %d", v, i)
+		}
+	}
+	if len(results) != 100 {
+		t.Errorf("orderedWork emitted %d results, expected 100", len(results))
+	}
+
+	expected := errors.New("failed")
+	err = orderedWork(50, func(i int) interface{} { return i }, func(v interface{}) error {
+		if v.(int) == 10 {
+			return expected
+		}
+		return nil
+	})
+	if err != expected {
+		t.Errorf("orderedWork did not return the error from the emit function")
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/OWASP/Amass/v3/graph"
+)
+
+// MaxQueryDepth is the largest number of nested selection sets accepted in a query. The types of the
+// schema reference each other, so deeper queries could repeatedly traverse the same parts of the graph.
+const MaxQueryDepth = 10
+
+// Error is a GraphQL error returned in the response.
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Response is the result of executing a GraphQL query.
+type Response struct {
+	Data   interface{} `json:"data"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+type executor struct {
+	graph  *graph.Graph
+	vars   map[string]interface{}
+	errors []*Error
+}
+
+// Execute runs the GraphQL query against the graph and returns the response. The variables
+// are the values decoded from the JSON object provided with the query. Queries nested
+// deeper than MaxQueryDepth are rejected without being executed.
+func Execute(g *graph.Graph, query string, variables map[string]interface{}) *Response {
+	doc, err := parse(query)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	vars, err := resolveVariables(doc.variables, variables)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	e := &executor{
+		graph: g,
+		vars:  vars,
+	}
+	data := e.executeSelections(queryType, queryRoot{}, doc.selections, nil)
+
+	return &Response{
+		Data:   data,
+		Errors: e.errors,
+	}
+}
+
+func resolveVariables(defs []*variableDefinition, provided map[string]interface{}) (map[string]interface{}, error) {
+	vars := make(map[string]interface{})
+
+	for _, def := range defs {
+		value, found := provided[def.name]
+		if !found && def.hasDefault {
+			value, found = def.defaultValue, true
+		}
+		if def.required && (!found || value == nil) {
+			return nil, fmt.Errorf("Variable \"$%s\" of a required type was not provided", def.name)
+		}
+		if found {
+			vars[def.name] = value
+		}
+	}
+	return vars, nil
+}
+
+func (e *executor) executeSelections(t *objectType, obj interface{}, sels []*selection, path []interface{}) *result {
+	res := new(result)
+
+	for _, sel := range sels {
+		key := sel.responseKey()
+		fpath := appendPath(path, key)
+
+		if sel.name == "__typename" {
+			res.set(key, t.name)
+			continue
+		}
+
+		f, found := t.fields[sel.name]
+		if !found {
+			e.addError(fpath, "Cannot query field %q on type %q", sel.name, t.name)
+			res.set(key, nil)
+			continue
+		}
+
+		args, err := e.arguments(f, sel)
+		if err != nil {
+			e.addError(fpath, "%v", err)
+			res.set(key, nil)
+			continue
+		}
+
+		value, err := f.resolve(e.graph, obj, args)
+		if err != nil {
+			e.addError(fpath, "%v", err)
+			res.set(key, nil)
+			continue
+		}
+
+		res.set(key, e.complete(sel, value, fpath))
+	}
+	return res
+}
+
+// complete executes the subselections of objects and checks that scalar values have none.
+func (e *executor) complete(sel *selection, value interface{}, path []interface{}) interface{} {
+	if list, ok := value.([]interface{}); ok {
+		values := make([]interface{}, 0, len(list))
+
+		for i, item := range list {
+			values = append(values, e.complete(sel, item, appendPath(path, i)))
+		}
+		return values
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	t := typeOf(value)
+	if t == nil {
+		if len(sel.selections) > 0 {
+			e.addError(path, "Field %q is a scalar and cannot have a selection of subfields", sel.name)
+			return nil
+		}
+		return value
+	}
+
+	if len(sel.selections) == 0 {
+		e.addError(path, "Field %q of type %q must have a selection of subfields", sel.name, t.name)
+		return nil
+	}
+	return e.executeSelections(t, value, sel.selections, path)
+}
+
+func (e *executor) arguments(f *field, sel *selection) (map[string]interface{}, error) {
+	args := make(map[string]interface{})
+
+	for _, arg := range sel.arguments {
+		if !f.hasArgument(arg.name) {
+			return nil, fmt.Errorf("Unknown argument %q on field %q", arg.name, sel.name)
+		}
+
+		value := arg.value
+		if ref, ok := value.(variableRef); ok {
+			v, found := e.vars[string(ref)]
+			if !found {
+				continue
+			}
+			value = v
+		}
+		args[arg.name] = value
+	}
+	return args, nil
+}
+
+func (e *executor) addError(path []interface{}, format string, a ...interface{}) {
+	e.errors = append(e.errors, &Error{
+		Message: fmt.Sprintf(format, a...),
+		Path:    path,
+	})
+}
+
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	p := make([]interface{}, len(path), len(path)+1)
+
+	copy(p, path)
+	return append(p, elem)
+}
+
+// result maintains the order of the selected fields in the JSON response.
+type result struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (r *result) set(key string, value interface{}) {
+	if r.values == nil {
+		r.values = make(map[string]interface{})
+	}
+	if _, found := r.values[key]; !found {
+		r.keys = append(r.keys, key)
+	}
+	r.values[key] = value
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (r *result) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	for i, key := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(r.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/graph"
+)
+
+const testEventID = "ef9f9475-34ff-4e15-a8e7-3d6b7c8e6f2a"
+
+func testGraph(t *testing.T) *graph.Graph {
+	g := graph.NewGraph(graph.NewCayleyGraphMemory())
+
+	if err := g.InsertA("www.owasp.org", "192.168.1.1", "DNS", "dns", testEventID); err != nil {
+		t.Fatalf("Failed to insert the A record: %v", err)
+	}
+	if err := g.InsertInfrastructure(26808, "Test AS", "192.168.1.1", "192.168.1.0/24",
+		"RIR", "api", testEventID); err != nil {
+		t.Fatalf("Failed to insert the infrastructure: %v", err)
+	}
+	return g
+}
+
+func responseJSON(t *testing.T, resp *Response) string {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("Failed to marshal the response: %v", err)
+	}
+	return string(data)
+}
+
+func TestExecuteNestedQuery(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	resp := Execute(g, `query ($name: String!) {
+		name(name: $name) {
+			name
+			domain
+			sources { name tag }
+			addresses { ip netblock { cidr asn { number description } } }
+		}
+		missing: name(name: "ftp.owasp.org") { name }
+	}`, map[string]interface{}{"name": "WWW.owasp.org"})
+
+	expected := `{"data":{"name":{"name":"www.owasp.org","domain":"owasp.org",` +
+		`"sources":[{"name":"DNS","tag":"dns"}],"addresses":[{"ip":"192.168.1.1",` +
+		`"netblock":{"cidr":"192.168.1.0/24","asn":{"number":26808,"description":"Test AS"}}}]},` +
+		`"missing":null}}`
+	if got := responseJSON(t, resp); got != expected {
+		t.Errorf("Unexpected response:\ngot:  %s\nwant: %s", got, expected)
+	}
+}
+
+func TestExecuteQueryDepth(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	nested := func(depth int) string {
+		return "{ " + strings.Repeat("name { ", depth-1) + "name" + strings.Repeat(" }", depth)
+	}
+
+	if resp := Execute(g, nested(MaxQueryDepth), nil); resp.Data == nil {
+		t.Errorf("The query at the maximum depth was not executed: %s", responseJSON(t, resp))
+	}
+	if resp := Execute(g, nested(MaxQueryDepth+1), nil); resp.Data != nil || len(resp.Errors) != 1 {
+		t.Errorf("The query exceeding the maximum depth was executed: %s", responseJSON(t, resp))
+	}
+}
+
+func TestExecuteEventQuery(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	resp := Execute(g, `{
+		events(domain: "owasp.org") {
+			__typename
+			uuid
+			domains
+			names(domain: "owasp.org") { name }
+			sources { name }
+		}
+		asn(number: 26808) { netblocks { addresses { names { events { uuid } } } } }
+	}`, nil)
+
+	expected := `{"data":{"events":[{"__typename":"Event","uuid":"` + testEventID + `",` +
+		`"domains":["owasp.org"],"names":[{"name":"owasp.org"},{"name":"www.owasp.org"}],` +
+		`"sources":[{"name":"DNS"},{"name":"RIR"}]}],"asn":{"netblocks":[{"addresses":` +
+		`[{"names":[{"events":[{"uuid":"` + testEventID + `"}]}]}]}]}}}`
+	if got := responseJSON(t, resp); got != expected {
+		t.Errorf("Unexpected response:\ngot:  %s\nwant: %s", got, expected)
+	}
+}
+
+func TestExecuteFieldErrors(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	resp := Execute(g, `{
+		name(name: "www.owasp.org") { name unknown }
+		address(ip: "192.168.1.1")
+		asn(number: "26808") { number }
+	}`, nil)
+	if len(resp.Errors) != 3 {
+		t.Fatalf("Expected 3 errors, found %d: %s", len(resp.Errors), responseJSON(t, resp))
+	}
+	if p := resp.Errors[0].Path; len(p) != 2 || p[0] != "name" || p[1] != "unknown" {
+		t.Errorf("The error path was %v", p)
+	}
+	// The valid fields are still resolved
+	if !strings.Contains(responseJSON(t, resp), `"name":{"name":"www.owasp.org","unknown":null}`) {
+		t.Errorf("The valid fields were not part of the response")
+	}
+
+	if resp := Execute(g, `query ($d: String!) { names(domain: $d) { name } }`, nil); resp.Data != nil {
+		t.Errorf("The query was executed without the required variable")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	g := testGraph(t)
+	defer g.Close()
+
+	srv := httptest.NewServer(NewHandler(g))
+	defer srv.Close()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"query":     `query ($ip: String!) { address(ip: $ip) { names { name } } }`,
+		"variables": map[string]interface{}{"ip": "192.168.1.1"},
+	})
+	resp, err := http.Post(srv.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("The POST request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Data struct {
+			Address struct {
+				Names []struct {
+					Name string `json:"name"`
+				} `json:"names"`
+			} `json:"address"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("Failed to decode the response: %v", err)
+	}
+	if names := out.Data.Address.Names; len(names) != 1 || names[0].Name != "www.owasp.org" {
+		t.Errorf("Unexpected names in the response: %+v", names)
+	}
+
+	resp, err = http.Get(srv.URL + "?query=" + url.QueryEscape("{ names { "))
+	if err != nil {
+		t.Fatalf("The GET request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("The invalid query returned status %d", resp.StatusCode)
+	}
+}
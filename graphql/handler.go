@@ -0,0 +1,88 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/OWASP/Amass/v3/graph"
+)
+
+// The largest request body accepted by the handler
+const maxRequestSize = 1 << 20
+
+type request struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// Handler serves GraphQL queries against the graph over HTTP.
+type Handler struct {
+	graph *graph.Graph
+}
+
+// NewHandler returns an HTTP handler that executes queries against the graph. Queries are
+// accepted as a JSON body with POST requests or with the query parameter of GET requests.
+// A GET request without a query returns the schema.
+func NewHandler(g *graph.Graph) *Handler {
+	return &Handler{graph: g}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		if req.Query == "" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			io.WriteString(w, Schema)
+			return
+		}
+		if vars := r.URL.Query().Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("Failed to parse the variables: %v", err))
+				return
+			}
+		}
+	case http.MethodPost:
+		dec := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize))
+		if err := dec.Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Failed to parse the request: %v", err))
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, "Only GET and POST requests are supported")
+		return
+	}
+
+	if req.Query == "" {
+		writeError(w, http.StatusBadRequest, "The request did not provide a query")
+		return
+	}
+
+	resp := Execute(h.graph, req.Query, req.Variables)
+
+	status := http.StatusOK
+	// Requests that could not be executed do not have data
+	if resp.Data == nil {
+		status = http.StatusBadRequest
+	}
+	writeResponse(w, status, resp)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeResponse(w, status, &Response{Errors: []*Error{{Message: msg}}})
+}
+
+func writeResponse(w http.ResponseWriter, status int, resp *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
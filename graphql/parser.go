@@ -0,0 +1,441 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The parser supports the subset of the GraphQL query language needed to navigate the
+// graph: a single query operation with aliases, arguments and variables. Fragments,
+// directives and mutations are not supported.
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+type document struct {
+	variables  []*variableDefinition
+	selections []*selection
+}
+
+type variableDefinition struct {
+	name         string
+	required     bool
+	hasDefault   bool
+	defaultValue interface{}
+}
+
+type selection struct {
+	alias      string
+	name       string
+	arguments  []*argument
+	selections []*selection
+}
+
+// responseKey returns the key used for the field in the response.
+func (s *selection) responseKey() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.name
+}
+
+type argument struct {
+	name  string
+	value interface{}
+}
+
+// The argument value is obtained from the variables provided with the query.
+type variableRef string
+
+type parser struct {
+	input string
+	pos   int
+	tok   token
+	// The number of selection sets enclosing the current position
+	depth int
+}
+
+func parse(query string) (*document, error) {
+	p := &parser{input: query}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := new(document)
+	if p.tok.kind == tokenName {
+		switch p.tok.value {
+		case "query":
+		case "mutation", "subscription":
+			return nil, p.errorf("Only query operations are supported")
+		default:
+			return nil, p.errorf("Unexpected name %q", p.tok.value)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		// The operation name is optional
+		if p.tok.kind == tokenName {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if p.isPunct("(") {
+			vars, err := p.parseVariableDefinitions()
+			if err != nil {
+				return nil, err
+			}
+			doc.variables = vars
+		}
+	}
+
+	sels, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	doc.selections = sels
+
+	if p.tok.kind != tokenEOF {
+		return nil, p.errorf("Only a single operation is supported per request")
+	}
+	return doc, nil
+}
+
+func (p *parser) parseVariableDefinitions() ([]*variableDefinition, error) {
+	var vars []*variableDefinition
+
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	for !p.isPunct(")") {
+		if err := p.expectPunct("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+
+		v := &variableDefinition{name: name}
+		if v.required, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		if p.isPunct("=") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if v.defaultValue, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+			v.hasDefault = true
+		}
+		vars = append(vars, v)
+	}
+	return vars, p.advance()
+}
+
+// parseType returns true when the outermost type is non-null.
+func (p *parser) parseType() (bool, error) {
+	if p.isPunct("[") {
+		if err := p.advance(); err != nil {
+			return false, err
+		}
+		if _, err := p.parseType(); err != nil {
+			return false, err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return false, err
+		}
+	} else if _, err := p.expectName(); err != nil {
+		return false, err
+	}
+
+	if p.isPunct("!") {
+		return true, p.advance()
+	}
+	return false, nil
+}
+
+func (p *parser) parseSelectionSet() ([]*selection, error) {
+	var sels []*selection
+
+	if p.depth++; p.depth > MaxQueryDepth {
+		return nil, fmt.Errorf("The query exceeds the maximum depth of %d", MaxQueryDepth)
+	}
+	defer func() { p.depth-- }()
+
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	for !p.isPunct("}") {
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+	}
+	if len(sels) == 0 {
+		return nil, p.errorf("Selection sets cannot be empty")
+	}
+	return sels, p.advance()
+}
+
+func (p *parser) parseSelection() (*selection, error) {
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	sel := &selection{name: name}
+	if p.isPunct(":") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		sel.alias = name
+		if sel.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isPunct("(") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for !p.isPunct(")") {
+			aname, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct(":"); err != nil {
+				return nil, err
+			}
+			value, err := p.parseValue(false)
+			if err != nil {
+				return nil, err
+			}
+			sel.arguments = append(sel.arguments, &argument{name: aname, value: value})
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isPunct("{") {
+		if sel.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return sel, nil
+}
+
+// parseValue reads a literal or variable value. Default values cannot reference variables.
+func (p *parser) parseValue(constant bool) (interface{}, error) {
+	var value interface{}
+	tok := p.tok
+
+	switch {
+	case tok.kind == tokenString:
+		value = tok.value
+	case tok.kind == tokenInt:
+		n, err := strconv.Atoi(tok.value)
+		if err != nil {
+			return nil, p.errorf("Invalid integer %s", tok.value)
+		}
+		value = n
+	case tok.kind == tokenName && (tok.value == "true" || tok.value == "false"):
+		value = tok.value == "true"
+	case tok.kind == tokenName && tok.value == "null":
+		value = nil
+	case !constant && p.isPunct("$"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		return variableRef(name), nil
+	default:
+		return nil, p.errorf("Unexpected %q where a value was expected", tok.value)
+	}
+
+	return value, p.advance()
+}
+
+func (p *parser) isPunct(value string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == value
+}
+
+func (p *parser) expectPunct(value string) error {
+	if !p.isPunct(value) {
+		return p.errorf("Expected %q, found %s", value, p.describe())
+	}
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.errorf("Expected a name, found %s", p.describe())
+	}
+
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) describe() string {
+	if p.tok.kind == tokenEOF {
+		return "the end of the query"
+	}
+	return strconv.Quote(p.tok.value)
+}
+
+func (p *parser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("Syntax error at position %d: %s", p.tok.pos, fmt.Sprintf(format, a...))
+}
+
+// advance reads the next token from the input.
+func (p *parser) advance() error {
+	p.skipIgnored()
+
+	start := p.pos
+	if start >= len(p.input) {
+		p.tok = token{kind: tokenEOF, pos: start}
+		return nil
+	}
+
+	c := p.input[start]
+	switch {
+	case strings.IndexByte("{}():$![]=", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokenPunct, value: string(c), pos: start}
+	case c == '"':
+		value, err := p.readString()
+		if err != nil {
+			return err
+		}
+		p.tok = token{kind: tokenString, value: value, pos: start}
+	case c == '-' || isDigit(c):
+		p.pos++
+		for p.pos < len(p.input) && isDigit(p.input[p.pos]) {
+			p.pos++
+		}
+		if p.pos < len(p.input) && strings.IndexByte(".eE", p.input[p.pos]) >= 0 {
+			return fmt.Errorf("Syntax error at position %d: Float values are not supported", start)
+		}
+		p.tok = token{kind: tokenInt, value: p.input[start:p.pos], pos: start}
+	case isNameStart(c):
+		p.pos++
+		for p.pos < len(p.input) && (isNameStart(p.input[p.pos]) || isDigit(p.input[p.pos])) {
+			p.pos++
+		}
+		p.tok = token{kind: tokenName, value: p.input[start:p.pos], pos: start}
+	case c == '.':
+		return fmt.Errorf("Syntax error at position %d: Fragments are not supported", start)
+	case c == '@':
+		return fmt.Errorf("Syntax error at position %d: Directives are not supported", start)
+	default:
+		return fmt.Errorf("Syntax error at position %d: Unexpected character %q", start, c)
+	}
+	return nil
+}
+
+// skipIgnored moves past whitespace, commas and comments.
+func (p *parser) skipIgnored() {
+	for p.pos < len(p.input) {
+		switch c := p.input[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.input) && p.input[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) readString() (string, error) {
+	var b strings.Builder
+	start := p.pos
+
+	// Move past the opening quote
+	p.pos++
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+
+		switch c {
+		case '"':
+			p.pos++
+			return b.String(), nil
+		case '\n', '\r':
+			return "", fmt.Errorf("Syntax error at position %d: Unterminated string", start)
+		case '\\':
+			if p.pos+1 >= len(p.input) {
+				return "", fmt.Errorf("Syntax error at position %d: Unterminated string", start)
+			}
+
+			esc := p.input[p.pos+1]
+			p.pos += 2
+			switch esc {
+			case '"', '\\', '/':
+				b.WriteByte(esc)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if p.pos+4 > len(p.input) {
+					return "", fmt.Errorf("Syntax error at position %d: Invalid unicode escape", p.pos)
+				}
+				r, err := strconv.ParseUint(p.input[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					return "", fmt.Errorf("Syntax error at position %d: Invalid unicode escape", p.pos)
+				}
+				b.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				return "", fmt.Errorf("Syntax error at position %d: Invalid escape sequence", p.pos-1)
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(p.input[p.pos:])
+			b.WriteRune(r)
+			p.pos += size
+		}
+	}
+	return "", fmt.Errorf("Syntax error at position %d: Unterminated string", start)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,63 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graphql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := parse(`query Names($domain: String! = "owasp.org") {
+		# Comments and commas are ignored
+		found: names(domain: $domain, event: "abcA") { name, addresses { ip } }
+		asn(number: -1) { number }
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse the query: %v", err)
+	}
+
+	if len(doc.variables) != 1 || !doc.variables[0].required ||
+		!doc.variables[0].hasDefault || doc.variables[0].defaultValue != "owasp.org" {
+		t.Errorf("The variable definition was not parsed correctly: %+v", doc.variables)
+	}
+	if len(doc.selections) != 2 {
+		t.Fatalf("Expected 2 selections, found %d", len(doc.selections))
+	}
+
+	names := doc.selections[0]
+	if names.responseKey() != "found" || names.name != "names" {
+		t.Errorf("The alias was not parsed correctly: %+v", names)
+	}
+	if len(names.arguments) != 2 || names.arguments[0].value != variableRef("domain") ||
+		names.arguments[1].value != "abcA" {
+		t.Errorf("The arguments were not parsed correctly")
+	}
+	if len(names.selections) != 2 || names.selections[1].selections[0].name != "ip" {
+		t.Errorf("The nested selections were not parsed correctly")
+	}
+	if v := doc.selections[1].arguments[0].value; v != -1 {
+		t.Errorf("The integer argument was parsed as %v", v)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tt := range []struct {
+		query string
+		err   string
+	}{
+		{`{ names { name }`, "end of the query"},
+		{`{ }`, "cannot be empty"},
+		{`mutation { names { name } }`, "Only query operations"},
+		{`{ names { ...Name } }`, "Fragments"},
+		{`{ names(domain: "owasp) { name } }`, "Unterminated string"},
+		{`{ asn(number: 1.5) { number } }`, "Float"},
+		{`{ names { name } } { names { name } }`, "single operation"},
+		{`query ($d: String = $e) { names { name } }`, "where a value was expected"},
+	} {
+		if _, err := parse(tt.query); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Parsing %q returned the error %v, expected %q", tt.query, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,537 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/graph"
+	"github.com/OWASP/Amass/v3/stringset"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Schema is the GraphQL schema, in the type definition language, served by the package.
+const Schema = `type Query {
+  events(domain: String): [Event]
+  event(uuid: String!): Event
+  names(domain: String, event: String): [Name]
+  name(name: String!): Name
+  address(ip: String!): Address
+  netblock(cidr: String!): Netblock
+  asn(number: Int!): ASN
+  sources: [Source]
+}
+
+type Event {
+  uuid: String
+  start: String
+  finish: String
+  domains: [String]
+  names(domain: String): [Name]
+  sources: [Source]
+}
+
+type Name {
+  name: String
+  domain: String
+  sources: [Source]
+  addresses: [Address]
+  events: [Event]
+}
+
+type Address {
+  ip: String
+  netblock: Netblock
+  names: [Name]
+}
+
+type Netblock {
+  cidr: String
+  asn: ASN
+  addresses: [Address]
+}
+
+type ASN {
+  number: Int
+  description: String
+  netblocks: [Netblock]
+}
+
+type Source {
+  name: String
+  tag: String
+}
+`
+
+type resolveFunc func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error)
+
+type field struct {
+	args    []string
+	resolve resolveFunc
+}
+
+func (f *field) hasArgument(name string) bool {
+	for _, arg := range f.args {
+		if arg == name {
+			return true
+		}
+	}
+	return false
+}
+
+type objectType struct {
+	name   string
+	fields map[string]*field
+}
+
+// The objects are identified by the graph node they represent. Names reached through
+// an event only include the data sources from that event.
+type (
+	queryRoot      struct{}
+	eventObject    string
+	nameObject     struct{ name, event string }
+	addressObject  string
+	netblockObject string
+	asnObject      int
+	sourceObject   string
+)
+
+var queryType = &objectType{
+	name: "Query",
+	fields: map[string]*field{
+		"events": {
+			args: []string{"domain"},
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				domain, err := stringArg(args, "domain", false)
+				if err != nil {
+					return nil, err
+				}
+
+				uuids := g.EventList()
+				if domain != "" {
+					uuids = g.EventsInScope(domain)
+				}
+				return eventObjects(g, uuids), nil
+			},
+		},
+		"event": {
+			args: []string{"uuid"},
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				uuid, err := stringArg(args, "uuid", true)
+				if err != nil {
+					return nil, err
+				}
+
+				if _, err := g.ReadNode(uuid, "event"); err != nil {
+					return nil, nil
+				}
+				return eventObject(uuid), nil
+			},
+		},
+		"names": {
+			args: []string{"domain", "event"},
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				domain, err := stringArg(args, "domain", false)
+				if err != nil {
+					return nil, err
+				}
+				uuid, err := stringArg(args, "event", false)
+				if err != nil {
+					return nil, err
+				}
+
+				if uuid != "" {
+					return eventNames(g, uuid, domain), nil
+				}
+
+				names := stringset.New()
+				for _, uuid := range g.EventList() {
+					names.InsertMany(g.EventFQDNs(uuid)...)
+				}
+				return nameObjects(names.Slice(), domain, ""), nil
+			},
+		},
+		"name": {
+			args: []string{"name"},
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				name, err := stringArg(args, "name", true)
+				if err != nil {
+					return nil, err
+				}
+
+				name = strings.ToLower(name)
+				if _, err := g.ReadNode(name, "fqdn"); err != nil {
+					return nil, nil
+				}
+				return nameObject{name: name}, nil
+			},
+		},
+		"address": {
+			args: []string{"ip"},
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				ip, err := stringArg(args, "ip", true)
+				if err != nil {
+					return nil, err
+				}
+
+				if _, err := g.ReadNode(ip, "ipaddr"); err != nil {
+					return nil, nil
+				}
+				return addressObject(ip), nil
+			},
+		},
+		"netblock": {
+			args: []string{"cidr"},
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				cidr, err := stringArg(args, "cidr", true)
+				if err != nil {
+					return nil, err
+				}
+
+				if _, err := g.ReadNode(cidr, "netblock"); err != nil {
+					return nil, nil
+				}
+				return netblockObject(cidr), nil
+			},
+		},
+		"asn": {
+			args: []string{"number"},
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				asn, err := intArg(args, "number")
+				if err != nil {
+					return nil, err
+				}
+
+				if _, err := g.ReadNode(strconv.Itoa(asn), "as"); err != nil {
+					return nil, nil
+				}
+				return asnObject(asn), nil
+			},
+		},
+		"sources": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return sourceObjects(g.SourceNames()), nil
+			},
+		},
+	},
+}
+
+var eventType = &objectType{
+	name: "Event",
+	fields: map[string]*field{
+		"uuid": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return string(obj.(eventObject)), nil
+			},
+		},
+		"start": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				start, _ := g.EventDateRange(string(obj.(eventObject)))
+				return formatTime(start), nil
+			},
+		},
+		"finish": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				_, finish := g.EventDateRange(string(obj.(eventObject)))
+				return formatTime(finish), nil
+			},
+		},
+		"domains": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				domains := g.EventDomains(string(obj.(eventObject)))
+
+				sort.Strings(domains)
+				return strings2Values(domains), nil
+			},
+		},
+		"names": {
+			args: []string{"domain"},
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				domain, err := stringArg(args, "domain", false)
+				if err != nil {
+					return nil, err
+				}
+				return eventNames(g, string(obj.(eventObject)), domain), nil
+			},
+		},
+		"sources": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return sourceObjects(g.EventSources(string(obj.(eventObject)))), nil
+			},
+		},
+	},
+}
+
+var nameType = &objectType{
+	name: "Name",
+	fields: map[string]*field{
+		"name": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return obj.(nameObject).name, nil
+			},
+		},
+		"domain": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				domain, err := publicsuffix.EffectiveTLDPlusOne(obj.(nameObject).name)
+				if err != nil {
+					return nil, nil
+				}
+				return domain, nil
+			},
+		},
+		"sources": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				n := obj.(nameObject)
+
+				node, err := g.ReadNode(n.name, "fqdn")
+				if err != nil {
+					return []interface{}{}, nil
+				}
+
+				var events []string
+				if n.event != "" {
+					events = append(events, n.event)
+				}
+
+				sources, err := g.NodeSources(node, events...)
+				if err != nil {
+					return []interface{}{}, nil
+				}
+				return sourceObjects(sources), nil
+			},
+		},
+		"addresses": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return addressObjects(g.NameAddresses(obj.(nameObject).name)), nil
+			},
+		},
+		"events": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return eventObjects(g, g.NameEvents(obj.(nameObject).name)), nil
+			},
+		},
+	},
+}
+
+var addressType = &objectType{
+	name: "Address",
+	fields: map[string]*field{
+		"ip": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return string(obj.(addressObject)), nil
+			},
+		},
+		"netblock": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				if cidr := g.AddressNetblock(string(obj.(addressObject))); cidr != "" {
+					return netblockObject(cidr), nil
+				}
+				return nil, nil
+			},
+		},
+		"names": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return nameObjects(g.AddressNames(string(obj.(addressObject))), "", ""), nil
+			},
+		},
+	},
+}
+
+var netblockType = &objectType{
+	name: "Netblock",
+	fields: map[string]*field{
+		"cidr": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return string(obj.(netblockObject)), nil
+			},
+		},
+		"asn": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				if asn := g.NetblockASN(string(obj.(netblockObject))); asn != 0 {
+					return asnObject(asn), nil
+				}
+				return nil, nil
+			},
+		},
+		"addresses": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return addressObjects(g.NetblockAddresses(string(obj.(netblockObject)))), nil
+			},
+		},
+	},
+}
+
+var asnType = &objectType{
+	name: "ASN",
+	fields: map[string]*field{
+		"number": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return int(obj.(asnObject)), nil
+			},
+		},
+		"description": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return g.ReadASDescription(strconv.Itoa(int(obj.(asnObject)))), nil
+			},
+		},
+		"netblocks": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				cidrs := g.ASNNetblocks(int(obj.(asnObject)))
+
+				sort.Strings(cidrs)
+				values := make([]interface{}, 0, len(cidrs))
+				for _, cidr := range cidrs {
+					values = append(values, netblockObject(cidr))
+				}
+				return values, nil
+			},
+		},
+	},
+}
+
+var sourceType = &objectType{
+	name: "Source",
+	fields: map[string]*field{
+		"name": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return string(obj.(sourceObject)), nil
+			},
+		},
+		"tag": {
+			resolve: func(g *graph.Graph, obj interface{}, args map[string]interface{}) (interface{}, error) {
+				return g.SourceTag(string(obj.(sourceObject))), nil
+			},
+		},
+	},
+}
+
+// typeOf returns the object type of the value, or nil for scalar values.
+func typeOf(value interface{}) *objectType {
+	switch value.(type) {
+	case queryRoot:
+		return queryType
+	case eventObject:
+		return eventType
+	case nameObject:
+		return nameType
+	case addressObject:
+		return addressType
+	case netblockObject:
+		return netblockType
+	case asnObject:
+		return asnType
+	case sourceObject:
+		return sourceType
+	}
+	return nil
+}
+
+func stringArg(args map[string]interface{}, name string, required bool) (string, error) {
+	value, found := args[name]
+	if !found || value == nil {
+		if required {
+			return "", fmt.Errorf("Argument %q is required", name)
+		}
+		return "", nil
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("Argument %q must be a string", name)
+	}
+	return str, nil
+}
+
+func intArg(args map[string]interface{}, name string) (int, error) {
+	value, found := args[name]
+	if !found || value == nil {
+		return 0, fmt.Errorf("Argument %q is required", name)
+	}
+
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case float64:
+		// Numbers decoded from the JSON variables
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	}
+	return 0, fmt.Errorf("Argument %q must be an integer", name)
+}
+
+func eventNames(g *graph.Graph, uuid, domain string) []interface{} {
+	return nameObjects(g.EventFQDNs(uuid), domain, uuid)
+}
+
+func eventObjects(g *graph.Graph, uuids []string) []interface{} {
+	starts := make(map[string]time.Time)
+	for _, uuid := range uuids {
+		starts[uuid], _ = g.EventDateRange(uuid)
+	}
+	// Put the events in chronological order
+	sort.Slice(uuids, func(i, j int) bool {
+		return starts[uuids[i]].Before(starts[uuids[j]])
+	})
+
+	values := make([]interface{}, 0, len(uuids))
+	for _, uuid := range uuids {
+		values = append(values, eventObject(uuid))
+	}
+	return values
+}
+
+func nameObjects(names []string, domain, uuid string) []interface{} {
+	domain = strings.ToLower(domain)
+
+	sort.Strings(names)
+	values := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		if domain != "" && name != domain && !strings.HasSuffix(name, "."+domain) {
+			continue
+		}
+		values = append(values, nameObject{name: name, event: uuid})
+	}
+	return values
+}
+
+func addressObjects(addrs []string) []interface{} {
+	sort.Strings(addrs)
+
+	values := make([]interface{}, 0, len(addrs))
+	for _, addr := range addrs {
+		values = append(values, addressObject(addr))
+	}
+	return values
+}
+
+func sourceObjects(sources []string) []interface{} {
+	sort.Strings(sources)
+
+	values := make([]interface{}, 0, len(sources))
+	for _, source := range sources {
+		values = append(values, sourceObject(source))
+	}
+	return values
+}
+
+func strings2Values(strs []string) []interface{} {
+	values := make([]interface{}, 0, len(strs))
+
+	for _, s := range strs {
+		values = append(values, s)
+	}
+	return values
+}
+
+func formatTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
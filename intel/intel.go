@@ -14,6 +14,7 @@ import (
 	"github.com/OWASP/Amass/v3/config"
 	eb "github.com/OWASP/Amass/v3/eventbus"
 	amassnet "github.com/OWASP/Amass/v3/net"
+	amassdns "github.com/OWASP/Amass/v3/net/dns"
 	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/resolvers"
@@ -38,15 +39,25 @@ type Collection struct {
 	// The channel that will receive the results
 	Output chan *requests.Output
 
+	// When not nil, receives the ASNs investigated by HostedDomains with the netblocks kept for each,
+	// and is closed before the root domains are discovered within the netblocks
+	ASNOutput chan *requests.ASNRequest
+
 	// Broadcast channel that indicates no further writes to the output channel
 	done              chan struct{}
 	doneAlreadyClosed bool
 
-	wg     sync.WaitGroup
-	filter stringfilter.Filter
+	wg sync.WaitGroup
+
+	// The root domains referenced within the netblocks, waiting for corroborating observations
+	candidatesLock sync.Mutex
+	candidates     map[string]*domainCandidate
 
 	lastLock sync.Mutex
 	last     time.Time
+
+	// The registry delegations used when the discovery is restricted to regions
+	delegations *amassnet.Delegations
 }
 
 // NewCollection returns an initialized Collection object that has not been started yet.
@@ -114,7 +125,15 @@ func (c *Collection) HostedDomains() error {
 		})
 	}
 
-	c.filter = stringfilter.NewStringFilter()
+	if len(c.Config.Regions) > 0 {
+		d, err := LoadDelegations(c.Config)
+		if err != nil {
+			return err
+		}
+		c.delegations = d
+	}
+
+	c.candidates = make(map[string]*domainCandidate)
 	// Start the address ranges
 	for _, addr := range c.Config.Addresses {
 		if c.Sys.PerformDNSQuery(ctx) == nil {
@@ -123,7 +142,12 @@ func (c *Collection) HostedDomains() error {
 		}
 	}
 
-	for _, cidr := range append(c.Config.CIDRs, c.asnsToCIDRs()...) {
+	asncidrs := c.asnsToCIDRs()
+	if c.ASNOutput != nil {
+		close(c.ASNOutput)
+	}
+
+	for _, cidr := range append(c.Config.CIDRs, asncidrs...) {
 		// Skip IPv6 netblocks, since they are simply too large
 		if ip := cidr.IP.Mask(cidr.Mask); amassnet.IsIPv6(ip) {
 			continue
@@ -138,6 +162,9 @@ func (c *Collection) HostedDomains() error {
 	}
 
 	c.wg.Wait()
+	if n := c.uncorroborated(); n > 0 {
+		c.Config.Log.Printf("%d root domains lacked the %d observations required for the output", n, c.Config.MinDomainObservations)
+	}
 	time.Sleep(5 * time.Second)
 	close(c.Output)
 	return nil
@@ -180,17 +207,15 @@ func (c *Collection) investigateAddr(addr string) {
 	}
 
 	addrinfo := requests.AddressInfo{Address: ip}
-	if _, answer, err := c.Sys.Pool().Reverse(c.ctx, addr, resolvers.PriorityLow); err == nil {
+	if _, answer, err := c.Sys.Pool().Reverse(c.ctx, addr, resolvers.PriorityLow); err == nil && !c.genericRDNS(answer, ip) {
 		if d := strings.TrimSpace(c.Sys.Pool().SubdomainToDomain(answer)); d != "" {
-			if !c.filter.Duplicate(d) {
-				c.Output <- &requests.Output{
-					Name:      d,
-					Domain:    d,
-					Addresses: []requests.AddressInfo{addrinfo},
-					Tag:       requests.DNS,
-					Sources:   []string{"Reverse DNS"},
-				}
-			}
+			c.observeDomain(&requests.Output{
+				Name:      d,
+				Domain:    d,
+				Addresses: []requests.AddressInfo{addrinfo},
+				Tag:       requests.DNS,
+				Sources:   []string{"Reverse DNS"},
+			})
 		}
 	}
 
@@ -200,19 +225,102 @@ func (c *Collection) investigateAddr(addr string) {
 
 	for _, name := range http.PullCertificateNames(addr, c.Config.Ports) {
 		if n := strings.TrimSpace(name); n != "" {
-			d := c.Sys.Pool().SubdomainToDomain(n)
+			c.observeDomain(&requests.Output{
+				Name:      n,
+				Domain:    c.Sys.Pool().SubdomainToDomain(n),
+				Addresses: []requests.AddressInfo{addrinfo},
+				Tag:       requests.CERT,
+				Sources:   []string{"Active Cert"},
+			})
+		}
+	}
+}
 
-			if !c.filter.Duplicate(d) {
-				c.Output <- &requests.Output{
-					Name:      n,
-					Domain:    d,
-					Addresses: []requests.AddressInfo{addrinfo},
-					Tag:       requests.CERT,
-					Sources:   []string{"Active Cert"},
-				}
+type domainCandidate struct {
+	Output   *requests.Output
+	Addrs    stringset.Set
+	Reported bool
+}
+
+// genericRDNS returns true when the reverse DNS name should not be used to discover root domains.
+func (c *Collection) genericRDNS(name string, addr net.IP) bool {
+	if !c.Config.ExcludeGenericRDNS {
+		return false
+	}
+	if amassdns.IsGenericPTR(name, addr) {
+		return true
+	}
+
+	name = strings.ToLower(strings.Trim(name, "."))
+	for _, re := range c.Config.GenericRDNSPatterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// observeDomain sends the output for the root domain once enough addresses within the netblocks reference it.
+func (c *Collection) observeDomain(out *requests.Output) {
+	if out.Domain == "" {
+		return
+	}
+
+	c.candidatesLock.Lock()
+	cand, found := c.candidates[out.Domain]
+	if !found {
+		cand = &domainCandidate{
+			Output: out,
+			Addrs:  stringset.New(),
+		}
+		c.candidates[out.Domain] = cand
+	}
+	if cand.Reported {
+		c.candidatesLock.Unlock()
+		return
+	}
+
+	for _, a := range out.Addresses {
+		if addr := a.Address.String(); !cand.Addrs.Has(addr) {
+			cand.Addrs.Insert(addr)
+			if found {
+				cand.Output.Addresses = append(cand.Output.Addresses, a)
+			}
+		}
+	}
+	if found {
+		srcs := stringset.New(cand.Output.Sources...)
+		for _, src := range out.Sources {
+			if !srcs.Has(src) {
+				cand.Output.Sources = append(cand.Output.Sources, src)
 			}
 		}
 	}
+
+	var report *requests.Output
+	if cand.Addrs.Len() >= c.Config.MinDomainObservations {
+		cand.Reported = true
+		report = cand.Output
+	}
+	c.candidatesLock.Unlock()
+
+	if report != nil {
+		c.Output <- report
+	}
+}
+
+// uncorroborated returns the number of root domains that were observed without being reported.
+func (c *Collection) uncorroborated() int {
+	c.candidatesLock.Lock()
+	defer c.candidatesLock.Unlock()
+
+	var count int
+	for _, cand := range c.candidates {
+		if !cand.Reported {
+			count++
+		}
+	}
+	return count
 }
 
 func (c *Collection) asnsToCIDRs() []*net.IPNet {
@@ -227,6 +335,7 @@ func (c *Collection) asnsToCIDRs() []*net.IPNet {
 
 	var setLock sync.Mutex
 	cidrSet := stringset.New()
+	asnInfo := make(map[int]*requests.ASNRequest)
 	fn := func(req *requests.ASNRequest) {
 		lastLock.Lock()
 		last = time.Now()
@@ -234,6 +343,7 @@ func (c *Collection) asnsToCIDRs() []*net.IPNet {
 
 		setLock.Lock()
 		cidrSet.Union(req.Netblocks)
+		c.mergeASNInfo(asnInfo, req.ASN, req.Description, req.Netblocks.Slice()...)
 		setLock.Unlock()
 	}
 
@@ -248,6 +358,10 @@ func (c *Collection) asnsToCIDRs() []*net.IPNet {
 		}
 
 		for _, asn := range c.Config.ASNs {
+			if c.delegations != nil && !c.delegations.ASNInRegion(asn, c.Config.Regions) {
+				continue
+			}
+
 			src.ASNRequest(c.ctx, &requests.ASNRequest{ASN: asn})
 		}
 	}
@@ -272,6 +386,18 @@ loop:
 		}
 	}
 
+	// Include the netblocks assigned to the ASNs by the infrastructure provided by the user
+	for _, g := range c.Sys.GraphDatabases() {
+		for _, r := range g.GroundTruth() {
+			for _, asn := range c.Config.ASNs {
+				if r.ASN == asn {
+					cidrSet.Insert(r.Prefix)
+					c.mergeASNInfo(asnInfo, r.ASN, r.Description, r.Prefix)
+				}
+			}
+		}
+	}
+
 	filter := stringfilter.NewStringFilter()
 	// Do not return CIDRs that are already in the config
 	for _, cidr := range c.Config.CIDRs {
@@ -281,15 +407,69 @@ loop:
 	for _, netblock := range cidrSet.Slice() {
 		_, ipnet, err := net.ParseCIDR(netblock)
 
-		if err == nil && !filter.Duplicate(ipnet.String()) {
-			cidrs = append(cidrs, ipnet)
+		if err != nil || filter.Duplicate(ipnet.String()) {
+			continue
+		}
+		// Multinational organizations announce netblocks delegated within other regions
+		if c.delegations != nil && !c.delegations.NetblockInRegion(ipnet, c.Config.Regions) {
+			continue
 		}
+
+		cidrs = append(cidrs, ipnet)
 	}
 
+	c.sendASNInfo(asnInfo)
 	return cidrs
 }
 
-// ReverseWhois returns domain names that are related to the domains provided
+// must be called while holding the lock protecting the info map
+func (c *Collection) mergeASNInfo(info map[int]*requests.ASNRequest, asn int, desc string, netblocks ...string) {
+	if c.ASNOutput == nil {
+		return
+	}
+
+	req, found := info[asn]
+	if !found {
+		req = &requests.ASNRequest{ASN: asn, Netblocks: stringset.New()}
+		info[asn] = req
+	}
+	if req.Description == "" {
+		req.Description = desc
+	}
+	req.Netblocks.InsertMany(netblocks...)
+}
+
+func (c *Collection) sendASNInfo(info map[int]*requests.ASNRequest) {
+	if c.ASNOutput == nil {
+		return
+	}
+
+	for _, asn := range c.Config.ASNs {
+		req, found := info[asn]
+		if !found {
+			continue
+		}
+
+		netblocks := stringset.New()
+		for _, netblock := range req.Netblocks.Slice() {
+			_, ipnet, err := net.ParseCIDR(netblock)
+			if err != nil {
+				continue
+			}
+			if c.delegations != nil && !c.delegations.NetblockInRegion(ipnet, c.Config.Regions) {
+				continue
+			}
+
+			netblocks.Insert(ipnet.String())
+		}
+
+		req.Netblocks = netblocks
+		c.ASNOutput <- req
+	}
+}
+
+// ReverseWhois returns domain names that are related to the domains provided, or registered
+// by the organizations and email addresses in the configuration
 func (c *Collection) ReverseWhois() error {
 	if err := c.Config.CheckSettings(); err != nil {
 		return err
@@ -343,6 +523,12 @@ func (c *Collection) ReverseWhois() error {
 		for _, domain := range c.Config.Domains() {
 			src.WhoisRequest(c.ctx, &requests.WhoisRequest{Domain: domain})
 		}
+		for _, org := range c.Config.WhoisOrgs {
+			src.WhoisRequest(c.ctx, &requests.WhoisRequest{Company: org})
+		}
+		for _, email := range c.Config.WhoisEmails {
+			src.WhoisRequest(c.ctx, &requests.WhoisRequest{Email: email})
+		}
 	}
 	c.srcsLock.Unlock()
 
@@ -0,0 +1,192 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package intel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	amassnet "github.com/OWASP/Amass/v3/net"
+	amassdns "github.com/OWASP/Amass/v3/net/dns"
+	"github.com/OWASP/Amass/v3/net/http"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+)
+
+// The source name used for the lookalike domains in the output and the graph
+const lookalikeSource = "Lookalike"
+
+// The RDAP bootstrap service redirects the queries to the registry of each public suffix
+const rdapDomainURL = "https://rdap.org/domain/"
+
+// Lookalikes returns the permutations of the root domain names provided that have been
+// registered and are live, along with the registrar and creation date from RDAP.
+func (c *Collection) Lookalikes() error {
+	if c.Output == nil {
+		return errors.New("The intelligence collection did not have an output channel")
+	} else if err := c.Config.CheckSettings(); err != nil {
+		return err
+	}
+
+	// Setup the context used throughout the collection
+	ctx := context.WithValue(context.Background(), requests.ContextConfig, c.Config)
+	c.ctx = context.WithValue(ctx, requests.ContextEventBus, c.Bus)
+
+	eventID := c.Config.UUID.String()
+	for _, g := range c.Sys.GraphDatabases() {
+		if _, err := g.InsertEvent(eventID); err != nil {
+			c.Config.Log.Printf("Failed to insert the event into %s: %v", g, err)
+		}
+	}
+
+	for _, domain := range c.Config.Domains() {
+		for _, name := range amassdns.LookalikeNames(domain) {
+			select {
+			case <-c.done:
+				c.wg.Wait()
+				close(c.Output)
+				return nil
+			default:
+			}
+
+			if c.Sys.PerformDNSQuery(c.ctx) == nil {
+				c.wg.Add(1)
+				go c.investigateLookalike(name, domain, eventID)
+			}
+		}
+	}
+
+	c.wg.Wait()
+	close(c.Output)
+	return nil
+}
+
+func (c *Collection) investigateLookalike(name, domain, eventID string) {
+	defer c.wg.Done()
+	defer c.Sys.FinishedDNSQuery()
+
+	// The domain is considered registered when delegated or resolving
+	_, _, nserr := c.Sys.Pool().Resolve(c.ctx, name, "NS", resolvers.PriorityLow)
+
+	var addrs []requests.AddressInfo
+	for _, qtype := range []string{"A", "AAAA"} {
+		ans, _, err := c.Sys.Pool().Resolve(c.ctx, name, qtype, resolvers.PriorityLow)
+		if err != nil {
+			continue
+		}
+
+		for _, a := range ans {
+			if ip := net.ParseIP(strings.TrimSpace(a.Data)); ip != nil {
+				addrs = append(addrs, requests.AddressInfo{Address: ip})
+			}
+		}
+	}
+	if nserr != nil && len(addrs) == 0 {
+		return
+	}
+
+	info := &requests.LookalikeInfo{Of: domain}
+	if registrar, created, err := c.rdapRegistration(name); err == nil {
+		info.Registrar = registrar
+		info.Created = created
+	} else {
+		c.Config.Log.Printf("Lookalike: RDAP lookup of %s: %v", name, err)
+	}
+
+	for _, g := range c.Sys.GraphDatabases() {
+		if err := g.InsertLookalike(name, info, lookalikeSource, eventID); err != nil {
+			c.Config.Log.Printf("Failed to insert the lookalike %s into %s: %v", name, g, err)
+			continue
+		}
+
+		for _, addr := range addrs {
+			if amassnet.IsIPv4(addr.Address) {
+				g.InsertA(name, addr.Address.String(), lookalikeSource, requests.LOOKALIKE, eventID)
+			} else {
+				g.InsertAAAA(name, addr.Address.String(), lookalikeSource, requests.LOOKALIKE, eventID)
+			}
+		}
+	}
+
+	c.Output <- &requests.Output{
+		Name:      name,
+		Domain:    name,
+		Addresses: addrs,
+		Tag:       requests.LOOKALIKE,
+		Sources:   []string{lookalikeSource},
+		Lookalike: info,
+	}
+}
+
+type rdapDomain struct {
+	Events []struct {
+		Action string `json:"eventAction"`
+		Date   string `json:"eventDate"`
+	} `json:"events"`
+	Entities []struct {
+		Roles []string          `json:"roles"`
+		VCard []json.RawMessage `json:"vcardArray"`
+	} `json:"entities"`
+}
+
+// rdapRegistration returns the registrar and creation date of the domain name obtained using RDAP.
+func (c *Collection) rdapRegistration(name string) (string, time.Time, error) {
+	var created time.Time
+
+	ctx, cancel := context.WithTimeout(c.ctx, 30*time.Second)
+	defer cancel()
+
+	page, err := http.RequestWebPageWithContext(ctx, http.DefaultClient, rdapDomainURL+name,
+		nil, map[string]string{"Accept": "application/rdap+json"}, "", "")
+	if err != nil {
+		return "", created, err
+	}
+
+	var d rdapDomain
+	if err := json.Unmarshal([]byte(page), &d); err != nil {
+		return "", created, err
+	}
+
+	for _, e := range d.Events {
+		if e.Action == "registration" {
+			created, _ = time.Parse(time.RFC3339, e.Date)
+			break
+		}
+	}
+
+	var registrar string
+	for _, e := range d.Entities {
+		for _, role := range e.Roles {
+			if role == "registrar" && len(e.VCard) == 2 {
+				registrar = vcardFullName(e.VCard[1])
+			}
+		}
+	}
+	return registrar, created, nil
+}
+
+// vcardFullName returns the value of the fn property in the jCard properties.
+func vcardFullName(props json.RawMessage) string {
+	var list [][]interface{}
+
+	if err := json.Unmarshal(props, &list); err != nil {
+		return ""
+	}
+
+	for _, p := range list {
+		if len(p) < 4 {
+			continue
+		}
+		if key, ok := p[0].(string); ok && key == "fn" {
+			if value, ok := p[3].(string); ok {
+				return value
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,167 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package intel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/net/http"
+)
+
+// The lowest score of the candidate autonomous systems returned for an organization name
+const orgMinScore = 0.5
+
+// How long each registry or data source is given to answer the organization search
+const orgSearchTimeout = 30 * time.Second
+
+type orgSearch func(ctx context.Context, org string) ([]*amassnet.OrgCandidate, error)
+
+var orgSearches = map[string]orgSearch{
+	"ASN list":  embeddedOrgSearch,
+	"PeeringDB": peeringDBOrgSearch,
+	"RIPEstat":  ripeStatOrgSearch,
+}
+
+// OrganizationASNs returns the autonomous systems that could belong to the organization, ranked by how
+// closely their descriptions match the name once the legal suffixes and punctuation are removed. The ASN
+// list embedded into the binary, RIPEstat and PeeringDB are searched. The candidates are still returned
+// when some of them fail, and the error names the searches that failed.
+func OrganizationASNs(ctx context.Context, org string) ([]*amassnet.OrgCandidate, error) {
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	var candidates []*amassnet.OrgCandidate
+	var failures []string
+
+	for name, search := range orgSearches {
+		wg.Add(1)
+
+		go func(name string, search orgSearch) {
+			defer wg.Done()
+
+			sctx, cancel := context.WithTimeout(ctx, orgSearchTimeout)
+			defer cancel()
+
+			results, err := search(sctx, org)
+			lock.Lock()
+			defer lock.Unlock()
+
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+				return
+			}
+			for _, c := range results {
+				c.Sources = []string{name}
+			}
+			candidates = append(candidates, results...)
+		}(name, search)
+	}
+	wg.Wait()
+
+	ranked := amassnet.RankOrgCandidates(org, candidates, orgMinScore)
+	if len(failures) > 0 {
+		return ranked, fmt.Errorf("The organization search failed for %s", strings.Join(failures, ", "))
+	}
+	return ranked, nil
+}
+
+// orgSearchTerm returns the name sent to the registries and data sources, which match substrings.
+func orgSearchTerm(org string) string {
+	if term := amassnet.NormalizeOrgName(org); term != "" {
+		return term
+	}
+	return strings.TrimSpace(org)
+}
+
+func embeddedOrgSearch(ctx context.Context, org string) ([]*amassnet.OrgCandidate, error) {
+	asns, descs, err := config.EmbeddedASNs()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*amassnet.OrgCandidate
+	for i, asn := range asns {
+		// Most of the descriptions share no words with the name, so they are skipped before ranking
+		if amassnet.OrgNameScore(org, descs[i]) > 0 {
+			results = append(results, &amassnet.OrgCandidate{ASN: asn, Description: descs[i]})
+		}
+	}
+	return results, nil
+}
+
+func ripeStatOrgSearch(ctx context.Context, org string) ([]*amassnet.OrgCandidate, error) {
+	u := "https://stat.ripe.net/data/searchcomplete/data.json?resource=" + url.QueryEscape(orgSearchTerm(org))
+
+	page, err := http.RequestWebPageWithContext(ctx, http.DefaultClient, u, nil, nil, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data struct {
+			Categories []struct {
+				Category    string `json:"category"`
+				Suggestions []struct {
+					Value       string `json:"value"`
+					Description string `json:"description"`
+				} `json:"suggestions"`
+			} `json:"categories"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(page), &resp); err != nil {
+		return nil, err
+	}
+
+	var results []*amassnet.OrgCandidate
+	for _, cat := range resp.Data.Categories {
+		if !strings.EqualFold(cat.Category, "ASNs") {
+			continue
+		}
+
+		for _, s := range cat.Suggestions {
+			asn, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(s.Value), "AS"))
+			if err != nil {
+				continue
+			}
+
+			results = append(results, &amassnet.OrgCandidate{ASN: asn, Description: s.Description})
+		}
+	}
+	return results, nil
+}
+
+func peeringDBOrgSearch(ctx context.Context, org string) ([]*amassnet.OrgCandidate, error) {
+	u := "https://www.peeringdb.com/api/net?depth=0&name__contains=" + url.QueryEscape(orgSearchTerm(org))
+
+	page, err := http.RequestWebPageWithContext(ctx, http.DefaultClient, u, nil, nil, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data []struct {
+			ASN  int    `json:"asn"`
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(page), &resp); err != nil {
+		return nil, err
+	}
+
+	var results []*amassnet.OrgCandidate
+	for _, n := range resp.Data {
+		if n.ASN > 0 {
+			results = append(results, &amassnet.OrgCandidate{ASN: n.ASN, Description: n.Name})
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package intel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/net/http"
+)
+
+// The registries publish new delegation files daily
+const delegationsTTL = 24 * time.Hour
+
+// The name of the directory used for registry delegation files within the output directory
+const delegationsDirName = "delegations"
+
+var delegationURLs = map[string]string{
+	"afrinic": "https://ftp.afrinic.net/pub/stats/afrinic/delegated-afrinic-extended-latest",
+	"apnic":   "https://ftp.apnic.net/stats/apnic/delegated-apnic-extended-latest",
+	"arin":    "https://ftp.arin.net/pub/stats/arin/delegated-arin-extended-latest",
+	"lacnic":  "https://ftp.lacnic.net/pub/stats/lacnic/delegated-lacnic-extended-latest",
+	"ripencc": "https://ftp.ripe.net/pub/stats/ripencc/delegated-ripencc-extended-latest",
+}
+
+// LoadDelegations returns the registry delegations needed to check the regions in the configuration.
+// The delegation files are fetched when the copies in the output directory are missing or out of date.
+func LoadDelegations(cfg *config.Config) (*amassnet.Delegations, error) {
+	dir := config.OutputDirectory(cfg.Dir)
+	if dir == "" {
+		return nil, fmt.Errorf("Failed to obtain the output directory")
+	}
+	dir = filepath.Join(dir, delegationsDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("Failed to create the delegations directory: %v", err)
+	}
+
+	// Country codes can be delegated by any of the registries
+	registries := amassnet.RegistryNames
+	if regs := regionRegistries(cfg.Regions); len(regs) == len(cfg.Regions) {
+		registries = regs
+	}
+
+	d := amassnet.NewDelegations()
+	for _, reg := range registries {
+		path := filepath.Join(dir, "delegated-"+reg+"-extended-latest")
+
+		if err := fetchDelegations(delegationURLs[reg], path); err != nil {
+			return nil, fmt.Errorf("Failed to obtain the %s delegations: %v", reg, err)
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		err = d.Parse(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse the %s delegations: %v", reg, err)
+		}
+	}
+
+	return d, nil
+}
+
+func regionRegistries(regions []string) []string {
+	var registries []string
+
+	for _, region := range regions {
+		if reg := amassnet.RegistryForRegion(region); reg != "" {
+			registries = append(registries, reg)
+		}
+	}
+	return registries
+}
+
+func fetchDelegations(u, path string) error {
+	if finfo, err := os.Stat(path); err == nil && time.Since(finfo.ModTime()) < delegationsTTL {
+		return nil
+	}
+
+	page, err := http.RequestWebPage(u, nil, nil, "", "")
+	if err != nil {
+		// Continue with an older copy of the file when one is available
+		if _, serr := os.Stat(path); serr == nil {
+			return nil
+		}
+		return err
+	}
+	if !strings.Contains(page, "|") {
+		return fmt.Errorf("The response from %s was not a delegation file", u)
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(page), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -4,8 +4,15 @@
 package net
 
 import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/stringset"
@@ -15,11 +22,36 @@ import (
 type ASNCache struct {
 	sync.RWMutex
 	cache map[int]*requests.ASNRequest
+	// When the information of each ASN was last provided, which determines when persisted entries expire
+	updated map[int]time.Time
+	// The netblocks of the cached ASNs, with the ASN as the value
+	index *PrefixIndex
+	// Ground truth provided by the user, which takes precedence over the data sources
+	overrides *PrefixIndex
 }
 
 // NewASNCache returns an empty ASNCache for saving and search ASN and netblock information.
 func NewASNCache() *ASNCache {
-	return &ASNCache{cache: make(map[int]*requests.ASNRequest)}
+	return &ASNCache{
+		cache:     make(map[int]*requests.ASNRequest),
+		updated:   make(map[int]time.Time),
+		index:     NewPrefixIndex(),
+		overrides: NewPrefixIndex(),
+	}
+}
+
+// Override saves the ASN and netblock information in ASNRequest as ground truth, which is
+// returned by AddrSearch for addresses in the prefix instead of the data source results.
+func (c *ASNCache) Override(req *requests.ASNRequest) {
+	_, ipnet, err := net.ParseCIDR(req.Prefix)
+	if err != nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.overrides.Insert(ipnet, req)
 }
 
 // Update uses the saves the information in ASNRequest into the ASNCache.
@@ -27,11 +59,21 @@ func (c *ASNCache) Update(req *requests.ASNRequest) {
 	c.Lock()
 	defer c.Unlock()
 
+	c.update(req, time.Now())
+}
+
+// update must be called while holding the lock.
+func (c *ASNCache) update(req *requests.ASNRequest, now time.Time) {
+	if now.After(c.updated[req.ASN]) {
+		c.updated[req.ASN] = now
+	}
+
 	if _, found := c.cache[req.ASN]; !found {
 		c.cache[req.ASN] = req
 		if req.Netblocks == nil {
 			req.Netblocks = stringset.New(req.Prefix)
 		}
+		c.indexNetblocks(req.ASN, req.Netblocks)
 		return
 	}
 
@@ -52,10 +94,20 @@ func (c *ASNCache) Update(req *requests.ASNRequest) {
 	if as.Description == "" && req.Description != "" {
 		as.Description = req.Description
 	}
-	if req.Netblocks == nil {
-		as.Netblocks.Union(stringset.New(req.Prefix))
-	} else {
-		as.Netblocks.Union(req.Netblocks)
+
+	netblocks := req.Netblocks
+	if netblocks == nil {
+		netblocks = stringset.New(req.Prefix)
+	}
+	as.Netblocks.Union(netblocks)
+	c.indexNetblocks(req.ASN, netblocks)
+}
+
+func (c *ASNCache) indexNetblocks(asn int, netblocks stringset.Set) {
+	for netblock := range netblocks {
+		if _, ipnet, err := net.ParseCIDR(netblock); err == nil {
+			c.index.Insert(ipnet, asn)
+		}
 	}
 }
 
@@ -77,31 +129,26 @@ func (c *ASNCache) AddrSearch(addr string) *requests.ASNRequest {
 	c.RLock()
 	defer c.RUnlock()
 
-	var a int
-	var cidr *net.IPNet
-	var desc string
+	if r := c.overrideSearch(addr); r != nil {
+		return r
+	}
+
 	ip := net.ParseIP(addr)
-	for asn, record := range c.cache {
-		for netblock := range record.Netblocks {
-			_, ipnet, err := net.ParseCIDR(netblock)
-			if err != nil {
-				continue
-			}
-
-			if ipnet.Contains(ip) {
-				// Select the smallest CIDR
-				if cidr != nil && compareCIDRSizes(cidr, ipnet) == 1 {
-					continue
-				}
-				a = asn
-				cidr = ipnet
-				desc = record.Description
-			}
-		}
+	if ip == nil {
+		return nil
 	}
+
+	// The index provides the smallest CIDR containing the address
+	cidr, value := c.index.Lookup(ip)
 	if cidr == nil {
 		return nil
 	}
+
+	a := value.(int)
+	var desc string
+	if record, found := c.cache[a]; found {
+		desc = record.Description
+	}
 	return &requests.ASNRequest{
 		Address:     addr,
 		ASN:         a,
@@ -112,15 +159,132 @@ func (c *ASNCache) AddrSearch(addr string) *requests.ASNRequest {
 	}
 }
 
-func compareCIDRSizes(first, second *net.IPNet) int {
-	var result int
+func (c *ASNCache) overrideSearch(addr string) *requests.ASNRequest {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil
+	}
 
-	s1, _ := first.Mask.Size()
-	s2, _ := second.Mask.Size()
-	if s1 > s2 {
-		result = 1
-	} else if s2 > s1 {
-		result = -1
+	cidr, value := c.overrides.Lookup(ip)
+	if cidr == nil {
+		return nil
+	}
+
+	override := value.(*requests.ASNRequest)
+	return &requests.ASNRequest{
+		Address:     addr,
+		ASN:         override.ASN,
+		Prefix:      cidr.String(),
+		Description: override.Description,
+		Tag:         override.Tag,
+		Source:      override.Source,
+	}
+}
+
+// The entry written for each ASN in the file of a persisted ASNCache
+type asnCacheEntry struct {
+	ASN            int       `json:"asn"`
+	Prefix         string    `json:"prefix"`
+	CC             string    `json:"cc,omitempty"`
+	Registry       string    `json:"registry,omitempty"`
+	AllocationDate time.Time `json:"allocation_date"`
+	Description    string    `json:"description,omitempty"`
+	Netblocks      []string  `json:"netblocks"`
+	Tag            string    `json:"tag,omitempty"`
+	Source         string    `json:"source,omitempty"`
+	Updated        time.Time `json:"updated"`
+}
+
+// Load adds the ASN and netblock information saved in the file at path to the cache. Entries
+// that were last updated more than ttl ago are skipped, and a missing file is not an error.
+func (c *ASNCache) Load(path string, ttl time.Duration) (int, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("Failed to open the ASN cache file %s: %v", path, err)
+	}
+	defer f.Close()
+
+	c.Lock()
+	defer c.Unlock()
+
+	var loaded int
+	now := time.Now()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry asnCacheEntry
+
+		// Damaged lines are skipped, since the information can be requested again
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || (entry.Prefix == "" && len(entry.Netblocks) == 0) {
+			continue
+		}
+		if ttl > 0 && now.Sub(entry.Updated) > ttl {
+			continue
+		}
+
+		c.update(&requests.ASNRequest{
+			ASN:            entry.ASN,
+			Prefix:         entry.Prefix,
+			CC:             entry.CC,
+			Registry:       entry.Registry,
+			AllocationDate: entry.AllocationDate,
+			Description:    entry.Description,
+			Netblocks:      stringset.New(entry.Netblocks...),
+			Tag:            entry.Tag,
+			Source:         entry.Source,
+		}, entry.Updated)
+		loaded++
+	}
+	if err := scanner.Err(); err != nil {
+		return loaded, fmt.Errorf("Failed to read the ASN cache file %s: %v", path, err)
+	}
+	return loaded, nil
+}
+
+// Save writes the ASN and netblock information in the cache to the file at path, one JSON
+// object per line. The ground truth provided by the user is not saved.
+func (c *ASNCache) Save(path string) error {
+	c.RLock()
+	var entries []*asnCacheEntry
+	for asn, req := range c.cache {
+		entries = append(entries, &asnCacheEntry{
+			ASN:            asn,
+			Prefix:         req.Prefix,
+			CC:             req.CC,
+			Registry:       req.Registry,
+			AllocationDate: req.AllocationDate,
+			Description:    req.Description,
+			Netblocks:      req.Netblocks.Slice(),
+			Tag:            req.Tag,
+			Source:         req.Source,
+			Updated:        c.updated[asn],
+		})
+	}
+	c.RUnlock()
+
+	// The file is replaced at once, so an interrupted write does not damage the previous cache
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".")
+	if err != nil {
+		return fmt.Errorf("Failed to create the ASN cache file %s: %v", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			tmp.Close()
+			return fmt.Errorf("Failed to write the ASN cache file %s: %v", path, err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Failed to write the ASN cache file %s: %v", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Failed to write the ASN cache file %s: %v", path, err)
 	}
-	return result
+	return os.Rename(tmp.Name(), path)
 }
@@ -0,0 +1,102 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package net
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/stringset"
+)
+
+func TestASNCacheOverride(t *testing.T) {
+	c := NewASNCache()
+
+	c.Update(&requests.ASNRequest{
+		ASN:         15169,
+		Prefix:      "8.8.8.0/24",
+		Description: "GOOGLE",
+		Tag:         requests.RIR,
+		Source:      "RIR",
+	})
+	c.Override(&requests.ASNRequest{
+		ASN:         64512,
+		Prefix:      "8.8.0.0/16",
+		Description: "Example Corp",
+		Tag:         requests.RIR,
+		Source:      "Ground Truth",
+	})
+
+	if r := c.AddrSearch("8.8.8.8"); r == nil || r.ASN != 64512 ||
+		r.Prefix != "8.8.0.0/16" || r.Source != "Ground Truth" {
+		t.Errorf("The ground truth did not take precedence over the data source results: %+v", r)
+	}
+	if r := c.AddrSearch("9.9.9.9"); r != nil {
+		t.Errorf("An address outside the cached netblocks returned %+v", r)
+	}
+}
+
+func TestASNCachePersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "asncache")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "asn_cache.json")
+
+	c := NewASNCache()
+	if n, err := c.Load(path, time.Hour); err != nil || n != 0 {
+		t.Errorf("The missing cache file returned %d entries and the error %v", n, err)
+	}
+
+	c.Update(&requests.ASNRequest{
+		ASN:         15169,
+		Prefix:      "8.8.8.0/24",
+		CC:          "US",
+		Registry:    "ARIN",
+		Description: "GOOGLE",
+		Netblocks:   stringset.New("8.8.8.0/24", "8.8.4.0/24"),
+		Tag:         requests.RIR,
+		Source:      "RIR",
+	})
+	// The ground truth is not persisted
+	c.Override(&requests.ASNRequest{
+		ASN:         64512,
+		Prefix:      "10.0.0.0/8",
+		Description: "Example Corp",
+		Source:      "Ground Truth",
+	})
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Failed to save the cache: %v", err)
+	}
+
+	loaded := NewASNCache()
+	if n, err := loaded.Load(path, time.Hour); err != nil || n != 1 {
+		t.Fatalf("The cache file returned %d entries and the error %v", n, err)
+	}
+	if r := loaded.AddrSearch("8.8.4.4"); r == nil || r.ASN != 15169 || r.Prefix != "8.8.4.0/24" || r.Description != "GOOGLE" {
+		t.Errorf("The persisted netblocks were not loaded: %+v", r)
+	}
+	if r := loaded.AddrSearch("10.1.1.1"); r != nil && r.Source == "Ground Truth" {
+		t.Errorf("The ground truth was persisted: %+v", r)
+	}
+
+	// Entries are expired by the time that has passed since the information was provided
+	loaded.Lock()
+	loaded.updated[15169] = time.Now().Add(-2 * time.Hour)
+	loaded.Unlock()
+	if err := loaded.Save(path); err != nil {
+		t.Fatalf("Failed to save the cache: %v", err)
+	}
+	if n, _ := NewASNCache().Load(path, time.Hour); n != 0 {
+		t.Errorf("The expired entries were loaded")
+	}
+	if n, _ := NewASNCache().Load(path, 3*time.Hour); n != 1 {
+		t.Errorf("The entries within the time to live were not loaded")
+	}
+}
@@ -81,6 +81,21 @@ func ReverseIP(ip string) string {
 	return strings.Join(reversed, ".")
 }
 
+// ReverseAddrName returns the in-addr.arpa or ip6.arpa name used to perform reverse DNS
+// queries for the IP address, or an empty string when the address is not valid.
+func ReverseAddrName(addr string) string {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return ""
+	}
+
+	// IPv4-mapped IPv6 addresses are reversed as IPv4 addresses
+	if ip4 := ip.To4(); ip4 != nil {
+		return ReverseIP(ip4.String()) + ".in-addr.arpa"
+	}
+	return IPv6NibbleFormat(ip.String()) + ".ip6.arpa"
+}
+
 // IPv6NibbleFormat expects an IPv6 address in the ip parameter and
 // returns the address in nibble format.
 func IPv6NibbleFormat(ip string) string {
@@ -141,6 +141,24 @@ func TestIPv6NibbleFormat(t *testing.T) {
 	}
 }
 
+func TestReverseAddrName(t *testing.T) {
+	tests := []struct {
+		Address  string
+		Expected string
+	}{
+		{"72.237.4.1", "1.4.237.72.in-addr.arpa"},
+		{"::ffff:192.168.1.1", "1.1.168.192.in-addr.arpa"},
+		{"2001:db8::567:89ab", "b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa"},
+		{"invalid", ""},
+	}
+
+	for _, test := range tests {
+		if r := ReverseAddrName(test.Address); r != test.Expected {
+			t.Errorf("%s caused %s to be returned instead of %s", test.Address, r, test.Expected)
+		}
+	}
+}
+
 func TestExpandIPv6Addr(t *testing.T) {
 	tests := []struct {
 		Address  string
@@ -0,0 +1,76 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package dns
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// genericPTRWord matches the words that hosting and access providers use in the names assigned to whole address ranges.
+var genericPTRWord = regexp.MustCompile(`^(static|dynamic|dyn|dhcp|dsl|adsl|xdsl|pool|cust|customer|broadband|cable|` +
+	`dialup|ppp|pppoe|unassigned|unknown|unused|reverse|ip|ipv4|host|vps|client)[0-9a-f]*$`)
+
+// IsGenericPTR returns true when the reverse DNS name of the address was generated for a range of
+// addresses, such as ec2-192-0-2-1.compute-1.amazonaws.com or 1.2.0.192.static.provider.net,
+// instead of belonging to a host of the organization that the address is delegated to.
+func IsGenericPTR(name string, addr net.IP) bool {
+	name = strings.ToLower(strings.Trim(name, "."))
+	if name == "" {
+		return false
+	}
+
+	for _, form := range addressForms(addr) {
+		if strings.Contains(name, form) {
+			return true
+		}
+	}
+
+	labels := strings.Split(name, ".")
+	if len(labels) < 3 {
+		return false
+	}
+	// Only the labels before the registered domain are assigned per address, and they are
+	// numbered, which distinguishes dsl-17.provider.net from static.example.com
+	prefix := strings.Join(labels[:len(labels)-2], ".")
+	if !strings.ContainsAny(prefix, "0123456789") {
+		return false
+	}
+	for _, word := range strings.FieldsFunc(prefix, func(r rune) bool {
+		return r == '.' || r == '-' || r == '_'
+	}) {
+		if genericPTRWord.MatchString(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// addressForms returns the ways the address commonly appears within generated reverse DNS names.
+func addressForms(addr net.IP) []string {
+	ip4 := addr.To4()
+	if ip4 == nil {
+		if addr.To16() == nil {
+			return nil
+		}
+
+		hex := fmt.Sprintf("%x", []byte(addr.To16()))
+		return []string{hex, hex[16:]}
+	}
+
+	o := []string{
+		fmt.Sprint(ip4[0]), fmt.Sprint(ip4[1]), fmt.Sprint(ip4[2]), fmt.Sprint(ip4[3]),
+	}
+	forms := []string{fmt.Sprintf("%02x%02x%02x%02x", ip4[0], ip4[1], ip4[2], ip4[3]),
+		fmt.Sprintf("%03d%03d%03d%03d", ip4[0], ip4[1], ip4[2], ip4[3])}
+	for _, sep := range []string{"-", ".", "_"} {
+		forms = append(forms,
+			strings.Join(o, sep),
+			strings.Join([]string{o[3], o[2], o[1], o[0]}, sep),
+		)
+	}
+	return forms
+}
@@ -0,0 +1,35 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package dns
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIsGenericPTR(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		addr     string
+		expected bool
+	}{
+		{"ec2-54-12-3-4.compute-1.amazonaws.com", "54.12.3.4", true},
+		{"4.3.12.54.static.provider.net.", "54.12.3.4", true},
+		{"c0000201.provider.net", "192.0.2.1", true},
+		{"dsl-pool-17.provider.net", "192.0.2.1", true},
+		{"dsl-pool.provider.net", "192.0.2.1", false},
+		{"web1.owasp.org", "192.0.2.1", false},
+		{"host123.provider.net", "192.0.2.1", true},
+		{"2001-db8--1.v6.provider.net", "2001:db8::1", false},
+		{"20010db8000000000000000000000001.v6.provider.net", "2001:db8::1", true},
+		{"mail.owasp.org", "192.0.2.1", false},
+		{"server.owasp.org", "192.0.2.1", false},
+		{"static.owasp.org", "192.0.2.1", false},
+		{"", "192.0.2.1", false},
+	} {
+		if got := IsGenericPTR(test.name, net.ParseIP(test.addr)); got != test.expected {
+			t.Errorf("IsGenericPTR(%q, %s) returned %t", test.name, test.addr, got)
+		}
+	}
+}
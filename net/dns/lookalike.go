@@ -0,0 +1,211 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package dns
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// LookalikeSuffixes are the public suffixes commonly registered by squatters in place of the original.
+var LookalikeSuffixes = []string{"com", "net", "org", "info", "biz", "co", "io"}
+
+// The neighbours of each character on a QWERTY keyboard
+var keyboardAdjacent = map[rune]string{
+	'1': "2q", '2': "13wq", '3': "24ew", '4': "35re", '5': "46tr", '6': "57yt",
+	'7': "68uy", '8': "79iu", '9': "80oi", '0': "9po", 'q': "12wa", 'w': "23eqas",
+	'e': "34rwsd", 'r': "45etdf", 't': "56ryfg", 'y': "67tugh", 'u': "78yihj",
+	'i': "89uojk", 'o': "90ipkl", 'p': "0ol", 'a': "qwsz", 's': "weadzx",
+	'd': "erfsxc", 'f': "rtgdcv", 'g': "tyhfvb", 'h': "yujgbn", 'j': "uikhnm",
+	'k': "iojlm", 'l': "opk", 'z': "asx", 'x': "zsdc", 'c': "xdfv", 'v': "cfgb",
+	'b': "vghn", 'n': "bhjm", 'm': "njk",
+}
+
+// The character sequences that are easily mistaken for each other
+var homoglyphs = map[string][]string{
+	"a": {"4"}, "b": {"d", "lb"}, "d": {"b", "cl"}, "e": {"3"}, "g": {"q", "9"},
+	"i": {"1", "l"}, "l": {"1", "i"}, "m": {"rn", "nn"}, "n": {"m", "r"}, "o": {"0"},
+	"q": {"g"}, "s": {"5"}, "u": {"v"}, "v": {"u"}, "w": {"vv"}, "z": {"2"},
+	"0": {"o"}, "1": {"l", "i"}, "rn": {"m"}, "vv": {"w"}, "cl": {"d"},
+}
+
+// LookalikeNames returns the permutations of the registered domain name that could be
+// mistaken for it, such as typos, homoglyphs and bit flips of the label preceding the
+// public suffix. The permutations are also provided using the LookalikeSuffixes.
+func LookalikeNames(domain string) []string {
+	domain = strings.ToLower(strings.Trim(strings.TrimSpace(domain), "."))
+
+	registered, err := publicsuffix.EffectiveTLDPlusOne(domain)
+	if err != nil {
+		return []string{}
+	}
+	suffix := strings.TrimPrefix(registered, strings.SplitN(registered, ".", 2)[0]+".")
+	label := strings.TrimSuffix(registered, "."+suffix)
+
+	labels := make(map[string]struct{})
+	add := func(l string) {
+		if l != label && validLabel(l) {
+			labels[l] = struct{}{}
+		}
+	}
+
+	for _, fn := range []func(string) []string{
+		omissions, repetitions, transpositions, replacements,
+		insertions, homoglyphSwaps, bitFlips, hyphenations, vowelSwaps,
+	} {
+		for _, l := range fn(label) {
+			add(l)
+		}
+	}
+
+	names := make(map[string]struct{})
+	for l := range labels {
+		names[l+"."+suffix] = struct{}{}
+	}
+	for _, s := range LookalikeSuffixes {
+		if s != suffix {
+			names[label+"."+s] = struct{}{}
+		}
+	}
+
+	results := make([]string, 0, len(names))
+	for n := range names {
+		results = append(results, n)
+	}
+	sort.Strings(results)
+	return results
+}
+
+func validLabel(label string) bool {
+	if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	// Labels starting with the second and third characters hyphenated are reserved for IDNs
+	if len(label) > 3 && label[2:4] == "--" {
+		return false
+	}
+
+	for _, c := range label {
+		if !validLabelChar(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func validLabelChar(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-'
+}
+
+func omissions(label string) []string {
+	var results []string
+
+	for i := range label {
+		results = append(results, label[:i]+label[i+1:])
+	}
+	return results
+}
+
+func repetitions(label string) []string {
+	var results []string
+
+	for i := range label {
+		results = append(results, label[:i+1]+label[i:])
+	}
+	return results
+}
+
+func transpositions(label string) []string {
+	var results []string
+
+	for i := 0; i < len(label)-1; i++ {
+		if label[i] != label[i+1] {
+			results = append(results, label[:i]+string(label[i+1])+string(label[i])+label[i+2:])
+		}
+	}
+	return results
+}
+
+func replacements(label string) []string {
+	var results []string
+
+	for i, c := range label {
+		for _, k := range keyboardAdjacent[c] {
+			results = append(results, label[:i]+string(k)+label[i+1:])
+		}
+	}
+	return results
+}
+
+func insertions(label string) []string {
+	var results []string
+
+	for i, c := range label {
+		for _, k := range keyboardAdjacent[c] {
+			results = append(results, label[:i]+string(k)+label[i:], label[:i+1]+string(k)+label[i+1:])
+		}
+	}
+	return results
+}
+
+func homoglyphSwaps(label string) []string {
+	var results []string
+
+	for seq, glyphs := range homoglyphs {
+		for i := strings.Index(label, seq); i != -1; {
+			for _, g := range glyphs {
+				results = append(results, label[:i]+g+label[i+len(seq):])
+			}
+
+			next := strings.Index(label[i+1:], seq)
+			if next == -1 {
+				break
+			}
+			i += next + 1
+		}
+	}
+	return results
+}
+
+func bitFlips(label string) []string {
+	var results []string
+
+	for i := 0; i < len(label); i++ {
+		for bit := uint(0); bit < 8; bit++ {
+			if c := rune(label[i] ^ (1 << bit)); validLabelChar(c) {
+				results = append(results, label[:i]+string(c)+label[i+1:])
+			}
+		}
+	}
+	return results
+}
+
+func hyphenations(label string) []string {
+	var results []string
+
+	for i := 1; i < len(label); i++ {
+		results = append(results, label[:i]+"-"+label[i:])
+	}
+	return results
+}
+
+func vowelSwaps(label string) []string {
+	var results []string
+	vowels := "aeiou"
+
+	for i, c := range label {
+		if !strings.ContainsRune(vowels, c) {
+			continue
+		}
+
+		for _, v := range vowels {
+			if v != c {
+				results = append(results, label[:i]+string(v)+label[i+1:])
+			}
+		}
+	}
+	return results
+}
@@ -0,0 +1,51 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package dns
+
+import (
+	"testing"
+)
+
+func TestLookalikeNames(t *testing.T) {
+	names := make(map[string]struct{})
+	for _, n := range LookalikeNames("www.Example.co.uk") {
+		names[n] = struct{}{}
+	}
+
+	for _, expected := range []string{
+		"exmple.co.uk",   // omission
+		"exaample.co.uk", // repetition
+		"examlpe.co.uk",  // transposition
+		"wxample.co.uk",  // adjacent key replacement
+		"exaqmple.co.uk", // insertion
+		"examp1e.co.uk",  // homoglyph
+		"dxample.co.uk",  // bit flip
+		"ex-ample.co.uk", // hyphenation
+		"exomple.co.uk",  // vowel swap
+		"example.com",    // public suffix swap
+	} {
+		if _, found := names[expected]; !found {
+			t.Errorf("LookalikeNames did not return %s", expected)
+		}
+	}
+
+	for _, unexpected := range []string{"example.co.uk", "www.example.co.uk", "-xample.co.uk", "example-.co.uk"} {
+		if _, found := names[unexpected]; found {
+			t.Errorf("LookalikeNames returned %s", unexpected)
+		}
+	}
+
+	for n := range names {
+		for _, c := range n {
+			if !validLabelChar(c) && c != '.' {
+				t.Errorf("LookalikeNames returned %s containing an invalid character", n)
+				break
+			}
+		}
+	}
+
+	if names := LookalikeNames("co.uk"); len(names) != 0 {
+		t.Errorf("LookalikeNames returned %d names for a public suffix", len(names))
+	}
+}
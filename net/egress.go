@@ -0,0 +1,176 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package net
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Egress is the global option for recording and restricting the remote endpoints contacted.
+var Egress *EgressMonitor
+
+// EgressEndpoint is a remote endpoint contacted, or refused by the allow-list.
+type EgressEndpoint struct {
+	Protocol    string
+	Host        string
+	Port        int
+	Connections int
+	Denied      int
+}
+
+// EgressMonitor keeps track of the remote endpoints contacted and enforces the optional allow-list.
+type EgressMonitor struct {
+	sync.Mutex
+	endpoints map[string]*EgressEndpoint
+	strict    bool
+	names     []string
+	addrs     []net.IP
+	cidrs     []*net.IPNet
+}
+
+// NewEgressMonitor returns an EgressMonitor that records the endpoints without restricting them.
+func NewEgressMonitor() *EgressMonitor {
+	return &EgressMonitor{endpoints: make(map[string]*EgressEndpoint)}
+}
+
+// Allow enables the strict mode and adds the domain names, IP addresses and CIDRs provided
+// to the allow-list. A domain name also permits the subdomains of the name.
+func (e *EgressMonitor) Allow(entries ...string) error {
+	e.Lock()
+	defer e.Unlock()
+
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			_, ipnet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return fmt.Errorf("Egress: Failed to parse the CIDR %s: %v", entry, err)
+			}
+
+			e.cidrs = append(e.cidrs, ipnet)
+		} else if ip := net.ParseIP(entry); ip != nil {
+			e.addrs = append(e.addrs, ip)
+		} else {
+			e.names = append(e.names, strings.Trim(entry, "."))
+		}
+	}
+
+	e.strict = true
+	return nil
+}
+
+// Check records the attempt to contact the address using the network protocol,
+// and returns an error when the allow-list does not permit the remote host.
+func (e *EgressMonitor) Check(network, address string) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	host = strings.ToLower(strings.Trim(host, "[]."))
+
+	e.Lock()
+	defer e.Unlock()
+
+	allowed := !e.strict || e.allowed(host)
+
+	protocol := strings.TrimRight(network, "46")
+	key := protocol + "|" + host + "|" + port
+	ep, found := e.endpoints[key]
+	if !found {
+		p, _ := strconv.Atoi(port)
+
+		ep = &EgressEndpoint{
+			Protocol: protocol,
+			Host:     host,
+			Port:     p,
+		}
+		e.endpoints[key] = ep
+	}
+
+	if !allowed {
+		ep.Denied++
+		return fmt.Errorf("Egress: %s is not permitted by the allow-list", address)
+	}
+	ep.Connections++
+	return nil
+}
+
+func (e *EgressMonitor) allowed(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		for _, addr := range e.addrs {
+			if addr.Equal(ip) {
+				return true
+			}
+		}
+		for _, ipnet := range e.cidrs {
+			if ipnet.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, name := range e.names {
+		if host == name || strings.HasSuffix(host, "."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Endpoints returns the remote endpoints recorded, ordered by host, port and protocol.
+func (e *EgressMonitor) Endpoints() []EgressEndpoint {
+	e.Lock()
+	defer e.Unlock()
+
+	var endpoints []EgressEndpoint
+	for _, ep := range e.endpoints {
+		endpoints = append(endpoints, *ep)
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Host != endpoints[j].Host {
+			return endpoints[i].Host < endpoints[j].Host
+		}
+		if endpoints[i].Port != endpoints[j].Port {
+			return endpoints[i].Port < endpoints[j].Port
+		}
+		return endpoints[i].Protocol < endpoints[j].Protocol
+	})
+	return endpoints
+}
+
+// WriteReport writes the remote endpoints recorded to w as CSV.
+func (e *EgressMonitor) WriteReport(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"protocol", "host", "port", "connections", "denied"}); err != nil {
+		return err
+	}
+	for _, ep := range e.Endpoints() {
+		if err := cw.Write([]string{
+			ep.Protocol,
+			ep.Host,
+			strconv.Itoa(ep.Port),
+			strconv.Itoa(ep.Connections),
+			strconv.Itoa(ep.Denied),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package net
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEgressMonitorReport(t *testing.T) {
+	e := NewEgressMonitor()
+
+	for _, addr := range []string{"api.example.com:443", "API.example.com:443", "8.8.8.8:53"} {
+		if err := e.Check("tcp4", addr); err != nil {
+			t.Errorf("The monitor refused %s without an allow-list: %v", addr, err)
+		}
+	}
+	e.Check("udp", "8.8.8.8:53")
+
+	endpoints := e.Endpoints()
+	if len(endpoints) != 3 {
+		t.Fatalf("Expected 3 distinct endpoints and received %d", len(endpoints))
+	}
+	if ep := endpoints[2]; ep.Host != "api.example.com" || ep.Port != 443 ||
+		ep.Protocol != "tcp" || ep.Connections != 2 {
+		t.Errorf("Unexpected endpoint was recorded: %+v", ep)
+	}
+
+	var buf bytes.Buffer
+	if err := e.WriteReport(&buf); err != nil {
+		t.Fatalf("Failed to write the report: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 4 ||
+		lines[1] != "tcp,8.8.8.8,53,1,0" {
+		t.Errorf("Unexpected report was written: %v", lines)
+	}
+}
+
+func TestEgressMonitorAllow(t *testing.T) {
+	e := NewEgressMonitor()
+
+	if err := e.Allow("10.0.0.0/33"); err == nil {
+		t.Errorf("Allow did not return an error for an invalid CIDR")
+	}
+	if err := e.Allow("example.com", "192.0.2.0/24", "2001:db8::1"); err != nil {
+		t.Fatalf("Allow returned an error for valid entries: %v", err)
+	}
+
+	tests := []struct {
+		Network string
+		Addr    string
+		Allowed bool
+	}{
+		{"tcp", "www.example.com:443", true},
+		{"tcp", "example.com:80", true},
+		{"tcp", "badexample.com:443", false},
+		{"udp", "192.0.2.53:53", true},
+		{"udp", "[2001:db8::1]:53", true},
+		{"udp", "8.8.8.8:53", false},
+	}
+
+	for _, test := range tests {
+		if err := e.Check(test.Network, test.Addr); (err == nil) != test.Allowed {
+			t.Errorf("%s was allowed: %t, expected: %t", test.Addr, err == nil, test.Allowed)
+		}
+	}
+
+	for _, ep := range e.Endpoints() {
+		if ep.Host == "8.8.8.8" && (ep.Denied != 1 || ep.Connections != 0) {
+			t.Errorf("The refused connection was not recorded: %+v", ep)
+		}
+	}
+}
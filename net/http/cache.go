@@ -0,0 +1,145 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ResponseCache is an on-disk cache of HTTP response bodies that can be reused across executions.
+type ResponseCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewResponseCache returns a ResponseCache that stores the responses in dir and considers
+// them valid for the ttl duration.
+func NewResponseCache(dir string, ttl time.Duration) (*ResponseCache, error) {
+	if dir == "" {
+		return nil, errors.New("NewResponseCache: The cache directory was not provided")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("NewResponseCache: The time-to-live must be greater than zero")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("NewResponseCache: Failed to create the directory %s: %v", dir, err)
+	}
+
+	return &ResponseCache{
+		dir: dir,
+		ttl: ttl,
+	}, nil
+}
+
+// RequestKey returns the cache key for a request to urlstring using the provided body, header values and user ID.
+// The Authorization header is left out, since access tokens are replaced while the responses remain valid.
+func RequestKey(urlstring string, body []byte, hvals map[string]string, uid string) string {
+	keys := make([]string, 0, len(hvals))
+	for k := range hvals {
+		if !strings.EqualFold(k, "Authorization") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	io.WriteString(h, urlstring+"\n"+uid+"\n")
+	for _, k := range keys {
+		io.WriteString(h, k+": "+hvals[k]+"\n")
+	}
+	// The body follows an empty line, as within the request itself
+	io.WriteString(h, "\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the cached response for the key when it has not yet expired.
+func (rc *ResponseCache) Get(key string) (string, error) {
+	path := rc.path(key)
+
+	finfo, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if time.Since(finfo.ModTime()) > rc.ttl {
+		os.Remove(path)
+		return "", fmt.Errorf("The cached response %s has expired", key)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Set stores the response in the cache using the provided key.
+func (rc *ResponseCache) Set(key, page string) error {
+	f, err := ioutil.TempFile(rc.dir, key+".tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.WriteString(page)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	// The rename prevents readers from obtaining a partially written response
+	return os.Rename(f.Name(), rc.path(key))
+}
+
+func (rc *ResponseCache) path(key string) string {
+	return filepath.Join(rc.dir, key)
+}
+
+// RequestWebPageWithCache returns the cached response for the request when available.
+// Otherwise, the request is sent using the HTTP client and successful responses are cached.
+// Requests that include a body, such as the POST queries of GraphQL APIs, are cached by the
+// content of the body. Failed requests are sent again according to the retry policy, which can be nil.
+func RequestWebPageWithCache(ctx context.Context, rc *ResponseCache, client *http.Client, policy *RetryPolicy,
+	urlstring string, body io.Reader, hvals map[string]string, uid, secret string) (string, error) {
+	if rc == nil {
+		return RequestWebPageWithRetry(ctx, client, policy, urlstring, body, hvals, uid, secret)
+	}
+
+	var data []byte
+	if body != nil {
+		var err error
+
+		data, err = ioutil.ReadAll(body)
+		if err != nil {
+			return "", err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	key := RequestKey(urlstring, data, hvals, uid)
+	if page, err := rc.Get(key); err == nil {
+		return page, nil
+	}
+
+	page, err := RequestWebPageWithRetry(ctx, client, policy, urlstring, body, hvals, uid, secret)
+	if err == nil {
+		rc.Set(key, page)
+	}
+	return page, err
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestKey(t *testing.T) {
+	key := RequestKey("https://example.com/?q=test", nil, map[string]string{"A": "1", "B": "2"}, "user")
+
+	if k := RequestKey("https://example.com/?q=test", nil, map[string]string{"B": "2", "A": "1"}, "user"); k != key {
+		t.Errorf("RequestKey returned different keys for the same header values")
+	}
+	if k := RequestKey("https://example.com/?q=other", nil, map[string]string{"A": "1", "B": "2"}, "user"); k == key {
+		t.Errorf("RequestKey returned the same key for different URLs")
+	}
+	if k := RequestKey("https://example.com/?q=test", nil, map[string]string{"A": "1", "B": "2"}, "other"); k == key {
+		t.Errorf("RequestKey returned the same key for different user IDs")
+	}
+	if RequestKey("https://example.com/", nil, map[string]string{"Authorization": "Bearer first"}, "") !=
+		RequestKey("https://example.com/", nil, map[string]string{"authorization": "Bearer second"}, "") {
+		t.Errorf("RequestKey returned different keys for requests with different access tokens")
+	}
+	if k := RequestKey("https://example.com/?q=test", []byte(`{"query":"a"}`), map[string]string{"A": "1", "B": "2"}, "user"); k == key {
+		t.Errorf("RequestKey returned the same key for a request with a body")
+	}
+	if k := RequestKey("https://example.com/?q=test", []byte(`{"query":"a"}`), nil, ""); k == RequestKey("https://example.com/?q=test", []byte(`{"query":"b"}`), nil, "") {
+		t.Errorf("RequestKey returned the same key for different bodies")
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "amass-cache")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := NewResponseCache(dir, 0); err == nil {
+		t.Errorf("NewResponseCache did not return an error when provided an invalid time-to-live")
+	}
+
+	rc, err := NewResponseCache(dir, time.Minute)
+	if err != nil {
+		t.Fatalf("NewResponseCache returned an error: %v", err)
+	}
+
+	key := RequestKey("https://example.com/", nil, nil, "")
+	if _, err := rc.Get(key); err == nil {
+		t.Errorf("Get did not return an error for a response that was never cached")
+	}
+	if err := rc.Set(key, "response"); err != nil {
+		t.Errorf("Set returned an error: %v", err)
+	}
+	if page, err := rc.Get(key); err != nil || page != "response" {
+		t.Errorf("Get failed to return the cached response")
+	}
+
+	// Make the cached response appear older than the time-to-live
+	old := time.Now().Add(-2 * time.Minute)
+	os.Chtimes(rc.path(key), old, old)
+	if _, err := rc.Get(key); err == nil {
+		t.Errorf("Get returned a response that had expired")
+	}
+}
+
+func TestRequestWebPageWithCachePost(t *testing.T) {
+	dir, err := ioutil.TempDir("", "amass-cache")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	rc, err := NewResponseCache(dir, time.Minute)
+	if err != nil {
+		t.Fatalf("NewResponseCache returned an error: %v", err)
+	}
+
+	var count int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		data, _ := ioutil.ReadAll(r.Body)
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	for _, query := range []string{"a", "a", "b"} {
+		page, err := RequestWebPageWithCache(context.Background(), rc, srv.Client(),
+			nil, srv.URL, strings.NewReader(query), nil, "", "")
+		if err != nil || page != query {
+			t.Errorf("The request for %s returned %q with error: %v", query, page, err)
+		}
+	}
+	if count != 2 {
+		t.Errorf("The server received %d requests instead of 2", count)
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// The modes supported by cassettes.
+const (
+	// CassetteRecord sends the requests and saves the responses in the cassette file
+	CassetteRecord = "record"
+	// CassetteReplay returns the responses saved in the cassette file without accessing the network
+	CassetteReplay = "replay"
+)
+
+// Interaction is an HTTP request and the response received, as saved in a cassette file.
+type Interaction struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"request_body,omitempty"`
+	StatusCode  int         `json:"status_code"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body"`
+}
+
+// Cassette records the HTTP interactions of the package clients to a file, or replays
+// them, so data source parsers can be developed and tested against captured responses.
+type Cassette struct {
+	sync.Mutex
+	path         string
+	mode         string
+	interactions []*Interaction
+	used         map[int]struct{}
+}
+
+var (
+	cassetteLock sync.Mutex
+	cassette     *Cassette
+)
+
+// NewCassette returns a Cassette for the file at path. The file must exist in replay mode,
+// and is replaced by the interactions sent in record mode.
+func NewCassette(path, mode string) (*Cassette, error) {
+	if path == "" {
+		return nil, fmt.Errorf("NewCassette: The cassette file was not provided")
+	}
+
+	c := &Cassette{
+		path: path,
+		mode: strings.ToLower(strings.TrimSpace(mode)),
+		used: make(map[int]struct{}),
+	}
+	if c.mode == "" {
+		c.mode = CassetteReplay
+	}
+
+	switch c.mode {
+	case CassetteRecord:
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("NewCassette: Failed to create the directory for %s: %v", path, err)
+		}
+	case CassetteReplay:
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("NewCassette: Failed to read the cassette file %s: %v", path, err)
+		}
+		if err := json.Unmarshal(data, &c.interactions); err != nil {
+			return nil, fmt.Errorf("NewCassette: Failed to parse the cassette file %s: %v", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("NewCassette: The cassette mode must be %s or %s", CassetteRecord, CassetteReplay)
+	}
+	return c, nil
+}
+
+// Mode returns the mode of the cassette.
+func (c *Cassette) Mode() string {
+	return c.mode
+}
+
+// Interactions returns the HTTP interactions recorded or loaded by the cassette.
+func (c *Cassette) Interactions() []*Interaction {
+	c.Lock()
+	defer c.Unlock()
+
+	return append([]*Interaction(nil), c.interactions...)
+}
+
+// Transport returns an http.RoundTripper that uses the cassette for the requests, and next
+// for sending the requests in record mode.
+func (c *Cassette) Transport(next http.RoundTripper) http.RoundTripper {
+	return &cassetteTransport{
+		cassette: c,
+		next:     next,
+	}
+}
+
+// UseCassette installs the cassette on the clients of the package, or removes the active
+// cassette when c is nil.
+func UseCassette(c *Cassette) {
+	cassetteLock.Lock()
+	cassette = c
+	cassetteLock.Unlock()
+
+	setClientCassette(DefaultClient, c)
+
+	proxyClientsLock.Lock()
+	defer proxyClientsLock.Unlock()
+
+	for _, client := range proxyClients {
+		setClientCassette(client, c)
+	}
+}
+
+// ActiveCassette returns the cassette installed by UseCassette, or nil.
+func ActiveCassette() *Cassette {
+	cassetteLock.Lock()
+	defer cassetteLock.Unlock()
+
+	return cassette
+}
+
+func setClientCassette(client *http.Client, c *Cassette) {
+	next := client.Transport
+	if t, ok := next.(*cassetteTransport); ok {
+		next = t.next
+	}
+
+	if c == nil {
+		client.Transport = next
+		return
+	}
+	client.Transport = c.Transport(next)
+}
+
+type cassetteTransport struct {
+	cassette *Cassette
+	next     http.RoundTripper
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	if t.cassette.mode == CassetteReplay {
+		in := t.cassette.find(req.Method, req.URL.String(), string(body))
+		if in == nil {
+			return nil, fmt.Errorf("Cassette: No response was recorded for %s %s", req.Method, req.URL)
+		}
+		return in.response(req), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	if err := t.cassette.add(&Interaction{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		RequestBody: string(body),
+		StatusCode:  resp.StatusCode,
+		Header:      resp.Header,
+		Body:        string(data),
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// find returns the first matching interaction not yet replayed, or the last one
+// matching when all of them have been replayed.
+func (c *Cassette) find(method, urlstring, body string) *Interaction {
+	c.Lock()
+	defer c.Unlock()
+
+	var last *Interaction
+	for i, in := range c.interactions {
+		if in.Method != method || in.URL != urlstring || in.RequestBody != body {
+			continue
+		}
+		if _, found := c.used[i]; !found {
+			c.used[i] = struct{}{}
+			return in
+		}
+		last = in
+	}
+	return last
+}
+
+// add saves the interaction, and writes all of them to the cassette file.
+func (c *Cassette) add(in *Interaction) error {
+	c.Lock()
+	defer c.Unlock()
+
+	c.interactions = append(c.interactions, in)
+
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(c.path), filepath.Base(c.path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), c.path)
+}
+
+func (in *Interaction) response(req *http.Request) *http.Response {
+	header := make(http.Header)
+	for k, v := range in.Header {
+		header[k] = append([]string(nil), v...)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.StatusCode, http.StatusText(in.StatusCode)),
+		StatusCode:    in.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(in.Body)),
+		ContentLength: int64(len(in.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCassette(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&hits, 1)
+
+		switch r.URL.Path {
+		case "/post":
+			b, _ := ioutil.ReadAll(r.Body)
+			fmt.Fprintf(w, "posted %s", b)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			fmt.Fprintf(w, "page %d", n)
+		}
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sources", "test.json")
+
+	if _, err := NewCassette(path, CassetteReplay); err == nil {
+		t.Errorf("NewCassette did not fail to replay a missing cassette file")
+	}
+	if _, err := NewCassette(path, "rewind"); err == nil {
+		t.Errorf("NewCassette accepted an invalid mode")
+	}
+
+	rec, err := NewCassette(path, CassetteRecord)
+	if err != nil {
+		t.Fatalf("Failed to create the recording cassette: %v", err)
+	}
+	UseCassette(rec)
+	defer UseCassette(nil)
+
+	ctx := context.Background()
+	for _, expected := range []string{"page 1", "page 2"} {
+		if page, err := RequestWebPageWithContext(ctx, DefaultClient, srv.URL+"/page", nil, nil, "", ""); err != nil || page != expected {
+			t.Errorf("Recording returned %q, %v instead of %q", page, err, expected)
+		}
+	}
+	if page, err := RequestWebPageWithContext(ctx, DefaultClient, srv.URL+"/post",
+		strings.NewReader("data"), nil, "", ""); err != nil || page != "posted data" {
+		t.Errorf("Recording the POST request returned %q, %v", page, err)
+	}
+	// Clients created after the cassette was installed also use it
+	client, err := ProxyClient("http://127.0.0.1:1")
+	if err != nil {
+		t.Fatalf("Failed to create the proxy client: %v", err)
+	}
+	if _, ok := client.Transport.(*cassetteTransport); !ok {
+		t.Errorf("The proxy client did not use the cassette")
+	}
+	if _, err := RequestWebPageWithContext(ctx, DefaultClient, srv.URL+"/missing", nil, nil, "", ""); err == nil {
+		t.Errorf("Recording did not return the status error")
+	}
+
+	if n := len(rec.Interactions()); n != 4 {
+		t.Errorf("The cassette recorded %d interactions instead of 4", n)
+	}
+
+	srv.Close()
+	play, err := NewCassette(path, CassetteReplay)
+	if err != nil {
+		t.Fatalf("Failed to load the recorded cassette: %v", err)
+	}
+	UseCassette(play)
+
+	// The recorded interactions are returned in order, and the last one is repeated
+	for _, expected := range []string{"page 1", "page 2", "page 2"} {
+		if page, err := RequestWebPageWithContext(ctx, DefaultClient, srv.URL+"/page", nil, nil, "", ""); err != nil || page != expected {
+			t.Errorf("Replaying returned %q, %v instead of %q", page, err, expected)
+		}
+	}
+	if page, err := RequestWebPageWithContext(ctx, DefaultClient, srv.URL+"/post",
+		strings.NewReader("data"), nil, "", ""); err != nil || page != "posted data" {
+		t.Errorf("Replaying the POST request returned %q, %v", page, err)
+	}
+	if _, err := RequestWebPageWithContext(ctx, DefaultClient, srv.URL+"/post",
+		strings.NewReader("other"), nil, "", ""); err == nil {
+		t.Errorf("Replaying a POST request with a different body did not fail")
+	}
+
+	var se *StatusError
+	if _, err := RequestWebPageWithContext(ctx, DefaultClient, srv.URL+"/missing", nil, nil, "", ""); !errors.As(err, &se) || se.StatusCode != http.StatusNotFound {
+		t.Errorf("Replaying did not return the recorded status code: %v", err)
+	}
+	if _, err := RequestWebPageWithContext(ctx, DefaultClient, srv.URL+"/unknown", nil, nil, "", ""); err == nil {
+		t.Errorf("Replaying a request missing from the cassette did not fail")
+	}
+
+	UseCassette(nil)
+	if _, ok := DefaultClient.Transport.(*cassetteTransport); ok {
+		t.Errorf("The cassette was not removed from the default client")
+	}
+}
@@ -5,13 +5,16 @@ package http
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
@@ -53,12 +56,25 @@ var (
 // DefaultClient is the same HTTP client used by the package methods.
 var DefaultClient *http.Client
 
+// The cookie jar shared by all the clients, so cookies copied between domains
+// are sent regardless of the proxy used by the data source
+var cookieJar, _ = cookiejar.New(nil)
+
+// Clients that send requests through a specific proxy, keyed by the proxy URL
+var (
+	proxyClientsLock sync.Mutex
+	proxyClients     = make(map[string]*http.Client)
+)
+
 func init() {
-	jar, _ := cookiejar.New(nil)
-	DefaultClient = &http.Client{
+	DefaultClient = newClient(http.ProxyFromEnvironment)
+}
+
+func newClient(proxy func(*http.Request) (*url.URL, error)) *http.Client {
+	client := &http.Client{
 		Timeout: time.Second * 180, // Google's timeout
 		Transport: &http.Transport{
-			Proxy:                 http.ProxyFromEnvironment,
+			Proxy:                 proxy,
 			DialContext:           amassnet.DialContext,
 			MaxIdleConns:          200,
 			MaxConnsPerHost:       50,
@@ -67,8 +83,37 @@ func init() {
 			ExpectContinueTimeout: 20 * time.Second,
 			TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
 		},
-		Jar: jar,
+		Jar: cookieJar,
 	}
+
+	if c := ActiveCassette(); c != nil {
+		setClientCassette(client, c)
+	}
+	return client
+}
+
+// ProxyClient returns an HTTP client that sends all requests through the provided proxy URL.
+// The DefaultClient is returned when the proxy argument is empty.
+func ProxyClient(proxy string) (*http.Client, error) {
+	if proxy == "" {
+		return DefaultClient, nil
+	}
+
+	proxyClientsLock.Lock()
+	defer proxyClientsLock.Unlock()
+
+	if client, found := proxyClients[proxy]; found {
+		return client, nil
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the proxy URL %s: %v", proxy, err)
+	}
+
+	client := newClient(http.ProxyURL(u))
+	proxyClients[proxy] = client
+	return client, nil
 }
 
 // CopyCookies copies cookies from one domain to another. Some of our data
@@ -96,11 +141,24 @@ func CheckCookie(urlString string, cookieName string) bool {
 // RequestWebPage returns a string containing the entire response for
 // the urlstring parameter when successful.
 func RequestWebPage(urlstring string, body io.Reader, hvals map[string]string, uid, secret string) (string, error) {
+	return RequestWebPageWithClient(DefaultClient, urlstring, body, hvals, uid, secret)
+}
+
+// RequestWebPageWithClient returns a string containing the entire response for
+// the urlstring parameter when successful, using the provided HTTP client.
+func RequestWebPageWithClient(client *http.Client, urlstring string, body io.Reader, hvals map[string]string, uid, secret string) (string, error) {
+	return RequestWebPageWithContext(context.Background(), client, urlstring, body, hvals, uid, secret)
+}
+
+// RequestWebPageWithContext returns a string containing the entire response for the urlstring
+// parameter when successful, and gives up on the request once the context is done.
+func RequestWebPageWithContext(ctx context.Context, client *http.Client, urlstring string,
+	body io.Reader, hvals map[string]string, uid, secret string) (string, error) {
 	method := "GET"
 	if body != nil {
 		method = "POST"
 	}
-	req, err := http.NewRequest(method, urlstring, body)
+	req, err := http.NewRequestWithContext(ctx, method, urlstring, body)
 	if err != nil {
 		return "", err
 	}
@@ -115,11 +173,13 @@ func RequestWebPage(urlstring string, body io.Reader, hvals map[string]string, u
 		req.Header.Set(k, v)
 	}
 
-	resp, err := DefaultClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return "", err
 	} else if resp.StatusCode < 200 || resp.StatusCode >= 400 {
-		return "", errors.New(resp.Status)
+		err := checkRateLimited(resp)
+		resp.Body.Close()
+		return "", err
 	}
 
 	in, err := ioutil.ReadAll(resp.Body)
@@ -202,47 +262,99 @@ func whichDomain(name string, scope []string) string {
 	return ""
 }
 
+// CertificateNames contains the names extracted from a certificate, along
+// with the port and the SNI value that caused the server to provide it.
+type CertificateNames struct {
+	Port       int
+	ServerName string
+	Names      []string
+}
+
 // PullCertificateNames attempts to pull a cert from one or more ports on an IP.
 func PullCertificateNames(addr string, ports []int) []string {
 	var names []string
 
+	for _, c := range PullCertificatesWithSNI(addr, ports, nil) {
+		names = append(names, c.Names...)
+	}
+	return names
+}
+
+// PullCertificatesWithSNI attempts to pull certs from one or more ports on an IP, first without
+// an SNI value and then using each of the candidate server names. Only certificates that were not
+// already obtained on the port are returned, allowing the caller to know which SNI produced them.
+func PullCertificatesWithSNI(addr string, ports []int, servernames []string) []*CertificateNames {
+	var results []*CertificateNames
+
 	// Check hosts for certificates that contain subdomain names
 	for _, port := range ports {
-		cfg := &tls.Config{InsecureSkipVerify: true}
-		// Set the maximum time allowed for making the connection
-		ctx, cancel := context.WithTimeout(context.Background(), defaultTLSConnectTimeout)
-		defer cancel()
-		// Obtain the connection
-		conn, err := amassnet.DialContext(ctx, "tcp", addr+":"+strconv.Itoa(port))
-		if err != nil {
-			continue
-		}
-		defer conn.Close()
-
-		c := tls.Client(conn, cfg)
-		// Attempt to acquire the certificate chain
-		errChan := make(chan error, 2)
-		// This goroutine will break us out of the handshake
-		time.AfterFunc(defaultHandshakeDeadline, func() {
-			errChan <- errors.New("Handshake timeout")
-		})
-		// Be sure we do not wait too long in this attempt
-		c.SetDeadline(time.Now().Add(defaultHandshakeDeadline))
-		// The handshake is performed in the goroutine
-		go func() {
-			errChan <- c.Handshake()
-		}()
-		// The error channel returns handshake or timeout error
-		if err = <-errChan; err != nil {
-			continue
+		seen := stringset.New()
+
+		for _, sni := range append([]string{""}, servernames...) {
+			cert, err := pullCertificate(addr, port, sni)
+			if err != nil {
+				// Do not try the other server names if the port did not respond
+				if sni == "" {
+					break
+				}
+				continue
+			}
+
+			sum := sha256.Sum256(cert.Raw)
+			fingerprint := hex.EncodeToString(sum[:])
+			if seen.Has(fingerprint) {
+				continue
+			}
+			seen.Insert(fingerprint)
+
+			results = append(results, &CertificateNames{
+				Port:       port,
+				ServerName: sni,
+				Names:      namesFromCert(cert),
+			})
 		}
-		// Get the correct certificate in the chain
-		certChain := c.ConnectionState().PeerCertificates
-		cert := certChain[0]
-		// Create the new requests from names found within the cert
-		names = append(names, namesFromCert(cert)...)
 	}
-	return names
+	return results
+}
+
+func pullCertificate(addr string, port int, sni string) (*x509.Certificate, error) {
+	cfg := &tls.Config{
+		ServerName:         sni,
+		InsecureSkipVerify: true,
+	}
+	// Set the maximum time allowed for making the connection
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTLSConnectTimeout)
+	defer cancel()
+	// Obtain the connection, which works for both IPv4 and IPv6 addresses
+	conn, err := amassnet.DialContext(ctx, "tcp", net.JoinHostPort(addr, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	c := tls.Client(conn, cfg)
+	// Attempt to acquire the certificate chain
+	errChan := make(chan error, 2)
+	// This goroutine will break us out of the handshake
+	time.AfterFunc(defaultHandshakeDeadline, func() {
+		errChan <- errors.New("Handshake timeout")
+	})
+	// Be sure we do not wait too long in this attempt
+	c.SetDeadline(time.Now().Add(defaultHandshakeDeadline))
+	// The handshake is performed in the goroutine
+	go func() {
+		errChan <- c.Handshake()
+	}()
+	// The error channel returns handshake or timeout error
+	if err = <-errChan; err != nil {
+		return nil, err
+	}
+	// Get the correct certificate in the chain
+	certChain := c.ConnectionState().PeerCertificates
+	if len(certChain) == 0 {
+		return nil, errors.New("No certificates were provided")
+	}
+	return certChain[0], nil
 }
 
 func namesFromCert(cert *x509.Certificate) []string {
@@ -0,0 +1,60 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRequestWebPageWithContext(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	page, err := RequestWebPageWithContext(context.Background(), srv.Client(), srv.URL+"/fast", nil, nil, "", "")
+	if err != nil || page != "ok" {
+		t.Errorf("The request returned %q with error: %v", page, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := RequestWebPageWithContext(ctx, srv.Client(), srv.URL+"/slow", nil, nil, "", ""); err == nil {
+		t.Errorf("The request did not fail once the context was done")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("The request was abandoned after %v", elapsed)
+	}
+}
+
+func TestProxyClientCookies(t *testing.T) {
+	client, err := ProxyClient("http://127.0.0.1:8080")
+	if err != nil {
+		t.Fatalf("ProxyClient failed: %v", err)
+	}
+
+	src, _ := url.Parse("https://src.example.com")
+	DefaultClient.Jar.SetCookies(src, []*http.Cookie{{Name: "session", Value: "1"}})
+	CopyCookies("https://src.example.com", "https://dest.example.com")
+
+	dest, _ := url.Parse("https://dest.example.com")
+	if cookies := client.Jar.Cookies(dest); len(cookies) != 1 || cookies[0].Name != "session" {
+		t.Errorf("The proxy client did not receive the copied cookies: %v", cookies)
+	}
+}
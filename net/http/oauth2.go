@@ -0,0 +1,75 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// ClientCredentials contains the values used to obtain access tokens with the OAuth2 client credentials grant.
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// The access tokens obtained, keyed by the token URL, client ID and scopes
+var (
+	oauth2TokensLock sync.Mutex
+	oauth2Tokens     = make(map[string]*oauth2.Token)
+)
+
+func (cc *ClientCredentials) key() string {
+	return cc.TokenURL + "\n" + cc.ClientID + "\n" + strings.Join(cc.Scopes, " ")
+}
+
+// OAuth2Token returns an access token for the client credentials. The token is reused
+// until shortly before it expires, and a new token is then requested using the HTTP client.
+func OAuth2Token(ctx context.Context, client *http.Client, cc *ClientCredentials) (string, error) {
+	key := cc.key()
+
+	oauth2TokensLock.Lock()
+	tok, found := oauth2Tokens[key]
+	oauth2TokensLock.Unlock()
+	if found && tok.Valid() {
+		return tok.AccessToken, nil
+	}
+
+	conf := &clientcredentials.Config{
+		ClientID:     cc.ClientID,
+		ClientSecret: cc.ClientSecret,
+		TokenURL:     cc.TokenURL,
+		Scopes:       cc.Scopes,
+		AuthStyle:    oauth2.AuthStyleAutoDetect,
+	}
+	if client == nil {
+		client = DefaultClient
+	}
+
+	tok, err := conf.Token(context.WithValue(ctx, oauth2.HTTPClient, client))
+	if err != nil {
+		return "", err
+	}
+
+	oauth2TokensLock.Lock()
+	oauth2Tokens[key] = tok
+	oauth2TokensLock.Unlock()
+	return tok.AccessToken, nil
+}
+
+// InvalidateOAuth2Token discards the access token of the client credentials, so the next
+// call to OAuth2Token requests a new one, such as after the server rejected the token.
+func InvalidateOAuth2Token(cc *ClientCredentials) {
+	oauth2TokensLock.Lock()
+	defer oauth2TokensLock.Unlock()
+
+	delete(oauth2Tokens, cc.key())
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOAuth2Token(t *testing.T) {
+	var issued int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if id, secret, ok := r.BasicAuth(); !ok || id != "client" || secret != "secret" {
+			if r.PostForm.Get("client_id") != "client" || r.PostForm.Get("client_secret") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("scope") != "read dns" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		n := atomic.AddInt32(&issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "token%d", "token_type": "bearer", "expires_in": 3600}`, n)
+	}))
+	defer srv.Close()
+
+	cc := &ClientCredentials{
+		TokenURL:     srv.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"read", "dns"},
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if token, err := OAuth2Token(ctx, srv.Client(), cc); err != nil || token != "token1" {
+			t.Errorf("OAuth2Token returned %s with the error: %v", token, err)
+		}
+	}
+
+	InvalidateOAuth2Token(cc)
+	if token, err := OAuth2Token(ctx, srv.Client(), cc); err != nil || token != "token2" {
+		t.Errorf("OAuth2Token returned %s after the token was invalidated: %v", token, err)
+	}
+
+	bad := &ClientCredentials{TokenURL: srv.URL, ClientID: "client", ClientSecret: "wrong"}
+	if _, err := OAuth2Token(ctx, srv.Client(), bad); err == nil {
+		t.Errorf("OAuth2Token did not fail for the invalid client credentials")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	amassnet "github.com/OWASP/Amass/v3/net"
+)
+
+// OwnershipPath is the well-known location of the file serving an ownership verification token.
+const OwnershipPath = "/.well-known/amass-verification.txt"
+
+const (
+	ownershipRequestTimeout = 10 * time.Second
+	maxOwnershipBodyLength  = 4 * 1024
+	maxOwnershipRedirects   = 3
+)
+
+// OwnershipTokenServed returns true when the web server of the domain provides the token as a line of
+// the file at the OwnershipPath. HTTPS is attempted first, and plain HTTP is used when it fails.
+func OwnershipTokenServed(ctx context.Context, domain, token string) bool {
+	domain = strings.ToLower(strings.Trim(domain, "."))
+	if domain == "" || token == "" {
+		return false
+	}
+
+	for _, scheme := range []string{"https", "http"} {
+		if ownershipTokenAt(ctx, scheme+"://"+domain+OwnershipPath, token) {
+			return true
+		}
+	}
+	return false
+}
+
+func ownershipTokenAt(ctx context.Context, u, token string) bool {
+	client := &http.Client{
+		Timeout: ownershipRequestTimeout,
+		Transport: &http.Transport{
+			DialContext:       amassnet.DialContext,
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxOwnershipRedirects {
+				return errors.New("Too many redirects")
+			}
+			return nil
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxOwnershipBodyLength))
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == token {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOwnershipTokenAt(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case OwnershipPath:
+			w.Write([]byte("# Amass ownership verification\n  abc123  \n"))
+		case "/moved":
+			http.Redirect(w, r, OwnershipPath, http.StatusFound)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("abc123\n"))
+		default:
+			w.Write([]byte("abc1234\n"))
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		path     string
+		token    string
+		expected bool
+	}{
+		{OwnershipPath, "abc123", true},
+		{OwnershipPath, "def456", false},
+		{"/moved", "abc123", true},
+		{"/missing", "abc123", false},
+		{"/other", "abc123", false},
+	}
+
+	for _, test := range tests {
+		if got := ownershipTokenAt(context.Background(), srv.URL+test.path, test.token); got != test.expected {
+			t.Errorf("ownershipTokenAt(%s, %s) returned %t, expected %t", test.path, test.token, got, test.expected)
+		}
+	}
+}
+
+func TestOwnershipTokenServedEmpty(t *testing.T) {
+	if OwnershipTokenServed(context.Background(), "", "abc123") ||
+		OwnershipTokenServed(context.Background(), "owasp.org", "") {
+		t.Errorf("OwnershipTokenServed returned true without a domain or token")
+	}
+}
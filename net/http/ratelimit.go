@@ -0,0 +1,115 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The amount of an error response body that is searched for quota messages
+const maxQuotaBodySize = 4096
+
+var quotaRE = regexp.MustCompile(`(?i)(quota|rate.?limit|too many requests|limit (has been )?(exceeded|reached))`)
+
+// RateLimitError is returned when a server rejects a request due to rate limiting or an exhausted quota.
+type RateLimitError struct {
+	StatusCode int
+	Status     string
+	// The wait requested by the server, or zero when not provided
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return e.Status
+}
+
+// IsRateLimited returns the RateLimitError when err was caused by the server limiting requests.
+func IsRateLimited(err error) (*RateLimitError, bool) {
+	var rle *RateLimitError
+
+	if errors.As(err, &rle) {
+		return rle, true
+	}
+	return nil, false
+}
+
+// ParseRetryAfter returns the wait specified by a Retry-After header value,
+// which can be provided in seconds or as an HTTP date.
+func ParseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// parseRateLimitReset returns the wait until the time, in seconds since the epoch,
+// provided by an X-RateLimit-Reset header value.
+func parseRateLimitReset(value string) time.Duration {
+	secs, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	if d := time.Until(time.Unix(secs, 0)); d > 0 {
+		return d
+	}
+	return 0
+}
+
+func checkRateLimited(resp *http.Response) error {
+	rle := &RateLimitError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	// APIs such as GitHub identify an exhausted limit using the remaining requests
+	exhausted := strings.TrimSpace(resp.Header.Get("X-RateLimit-Remaining")) == "0"
+	if rle.RetryAfter == 0 && exhausted {
+		rle.RetryAfter = parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"))
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return rle
+	case http.StatusServiceUnavailable:
+		if rle.RetryAfter > 0 {
+			return rle
+		}
+	case http.StatusPaymentRequired, http.StatusUnauthorized, http.StatusForbidden:
+		if exhausted {
+			return rle
+		}
+		// Several APIs report exhausted quotas using these status codes and a message
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxQuotaBodySize))
+		if quotaRE.Match(body) {
+			return rle
+		}
+	}
+
+	return &StatusError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+}
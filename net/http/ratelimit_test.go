@@ -0,0 +1,89 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		Value    string
+		Expected time.Duration
+	}{
+		{"", 0},
+		{"30", 30 * time.Second},
+		{"-5", 0},
+		{"soon", 0},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0},
+	}
+
+	for _, test := range tests {
+		if d := ParseRetryAfter(test.Value); d != test.Expected {
+			t.Errorf("ParseRetryAfter(%q) returned %v instead of %v", test.Value, d, test.Expected)
+		}
+	}
+
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if d := ParseRetryAfter(future); d <= 58*time.Minute || d > time.Hour {
+		t.Errorf("ParseRetryAfter(%q) returned %v instead of approximately one hour", future, d)
+	}
+}
+
+func TestRateLimitedResponses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/limited":
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/quota":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": "Monthly quota exceeded"}`))
+		case "/exhausted":
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+		case "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": "Invalid API key"}`))
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer ts.Close()
+
+	_, err := RequestWebPageWithClient(ts.Client(), ts.URL+"/limited", nil, nil, "", "")
+	if rle, ok := IsRateLimited(err); !ok {
+		t.Errorf("The 429 response was not identified as rate limiting: %v", err)
+	} else if rle.RetryAfter != 2*time.Minute {
+		t.Errorf("The Retry-After value was parsed as %v instead of two minutes", rle.RetryAfter)
+	}
+
+	if _, err := RequestWebPageWithClient(ts.Client(), ts.URL+"/quota", nil, nil, "", ""); err == nil {
+		t.Errorf("The quota response did not return an error")
+	} else if _, ok := IsRateLimited(err); !ok {
+		t.Errorf("The quota response was not identified as rate limiting: %v", err)
+	}
+
+	_, err = RequestWebPageWithClient(ts.Client(), ts.URL+"/exhausted", nil, nil, "", "")
+	if rle, ok := IsRateLimited(err); !ok {
+		t.Errorf("The exhausted rate limit was not identified: %v", err)
+	} else if rle.RetryAfter <= 58*time.Second || rle.RetryAfter > time.Minute {
+		t.Errorf("The X-RateLimit-Reset value was parsed as %v instead of approximately one minute", rle.RetryAfter)
+	}
+
+	if _, err := RequestWebPageWithClient(ts.Client(), ts.URL+"/forbidden", nil, nil, "", ""); err == nil {
+		t.Errorf("The forbidden response did not return an error")
+	} else if _, ok := IsRateLimited(err); ok {
+		t.Errorf("The forbidden response was identified as rate limiting")
+	}
+
+	if page, err := RequestWebPageWithClient(ts.Client(), ts.URL, nil, nil, "", ""); err != nil || page != "ok" {
+		t.Errorf("The successful request returned an error: %v", err)
+	}
+}
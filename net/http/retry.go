@@ -0,0 +1,139 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// DefaultRetryStatusCodes are the HTTP status codes that indicate a transient server failure.
+var DefaultRetryStatusCodes = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy describes how HTTP requests that fail for transient reasons are sent again.
+type RetryPolicy struct {
+	// The number of times a failed request is sent again
+	Retries int
+	// The wait before the first retry, which is doubled for each of the following retries
+	Backoff time.Duration
+	// The longest wait between two attempts, or zero for no limit
+	MaxBackoff time.Duration
+	// The fraction (0 to 1) of each wait that is randomly added or removed
+	Jitter float64
+	// The response status codes that cause the request to be retried
+	StatusCodes []int
+}
+
+// StatusError is returned when the server responds with an unsuccessful status code.
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return e.Status
+}
+
+// RequestWebPageWithRetry returns a string containing the entire response for the urlstring
+// parameter when successful, and sends the request again according to the policy when the
+// server fails with a retryable status code or the connection fails. Requests that were
+// rate limited are not retried, since the data source is expected to slow down instead.
+func RequestWebPageWithRetry(ctx context.Context, client *http.Client, policy *RetryPolicy,
+	urlstring string, body io.Reader, hvals map[string]string, uid, secret string) (string, error) {
+	if policy == nil || policy.Retries <= 0 {
+		return RequestWebPageWithContext(ctx, client, urlstring, body, hvals, uid, secret)
+	}
+
+	// The body needs to be provided again for each attempt
+	var data []byte
+	if body != nil {
+		var err error
+
+		data, err = ioutil.ReadAll(body)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var page string
+	var err error
+	for attempt := 0; ; attempt++ {
+		var b io.Reader
+		if data != nil {
+			b = bytes.NewReader(data)
+		}
+
+		page, err = RequestWebPageWithContext(ctx, client, urlstring, b, hvals, uid, secret)
+		if err == nil || attempt >= policy.Retries || !policy.Retryable(ctx, err) {
+			break
+		}
+
+		t := time.NewTimer(policy.Wait(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return page, err
+		case <-t.C:
+		}
+	}
+	return page, err
+}
+
+// Retryable returns true when the request that failed with err can be sent again according to
+// the policy. Requests that were rate limited are not considered retryable.
+func (p *RetryPolicy) Retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if _, ok := IsRateLimited(err); ok {
+		return false
+	}
+
+	var se *StatusError
+	if errors.As(err, &se) {
+		for _, code := range p.StatusCodes {
+			if se.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+	// The remaining errors were returned by the client, such as refused or reset connections
+	return true
+}
+
+// Wait returns the amount of time to wait after the failed attempt, which is counted from zero.
+func (p *RetryPolicy) Wait(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 0; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 && d > 0 {
+		j := p.Jitter
+		if j > 1 {
+			j = 1
+		}
+
+		d += time.Duration((2*rand.Float64() - 1) * j * float64(d))
+	}
+	if d < 0 {
+		d = 0
+	}
+	return d
+}
@@ -0,0 +1,109 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestWebPageWithRetry(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+
+		switch r.URL.Path {
+		case "/flaky":
+			if n < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+		case "/post":
+			if b, _ := ioutil.ReadAll(r.Body); string(b) != "data" || n < 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	policy := &RetryPolicy{
+		Retries:     3,
+		Backoff:     10 * time.Millisecond,
+		Jitter:      0.5,
+		StatusCodes: DefaultRetryStatusCodes,
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		Path     string
+		Body     string
+		Success  bool
+		Attempts int32
+	}{
+		{"/flaky", "", true, 3},
+		{"/post", "data", true, 2},
+		{"/missing", "", false, 1},
+		{"/limited", "", false, 1},
+	}
+
+	for _, test := range tests {
+		atomic.StoreInt32(&attempts, 0)
+
+		var body io.Reader
+		if test.Body != "" {
+			body = strings.NewReader(test.Body)
+		}
+
+		_, err := RequestWebPageWithRetry(ctx, srv.Client(), policy, srv.URL+test.Path, body, nil, "", "")
+		if (err == nil) != test.Success {
+			t.Errorf("The request for %s returned the error: %v", test.Path, err)
+		}
+		if n := atomic.LoadInt32(&attempts); n != test.Attempts {
+			t.Errorf("The request for %s was sent %d times instead of %d", test.Path, n, test.Attempts)
+		}
+	}
+
+	atomic.StoreInt32(&attempts, 0)
+	if _, err := RequestWebPageWithRetry(ctx, srv.Client(), nil, srv.URL+"/flaky", nil, nil, "", ""); err == nil {
+		t.Errorf("The request without a retry policy did not fail")
+	}
+	if n := atomic.LoadInt32(&attempts); n != 1 {
+		t.Errorf("The request without a retry policy was sent %d times", n)
+	}
+}
+
+func TestRetryPolicyWait(t *testing.T) {
+	p := &RetryPolicy{
+		Backoff:    time.Second,
+		MaxBackoff: 5 * time.Second,
+	}
+
+	for attempt, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if d := p.Wait(attempt); d != expected {
+			t.Errorf("The wait after attempt %d was %v instead of %v", attempt, d, expected)
+		}
+	}
+
+	p.Jitter = 0.25
+	for i := 0; i < 100; i++ {
+		if d := p.Wait(1); d < 1500*time.Millisecond || d > 2500*time.Millisecond {
+			t.Errorf("The wait with jitter was %v", d)
+		}
+	}
+}
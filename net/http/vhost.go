@@ -0,0 +1,170 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	amassnet "github.com/OWASP/Amass/v3/net"
+)
+
+const (
+	vhostRequestTimeout = 5 * time.Second
+	maxVHostBodyLength  = 64 * 1024
+	minVHostTolerance   = 32
+)
+
+// vhostResponse contains the parts of a web server response compared while probing virtual hosts.
+type vhostResponse struct {
+	StatusCode int
+	Length     int
+	Location   string
+}
+
+// VirtualHosts sends requests to the web server at the address and port using each of the candidate
+// host names in the Host header, and returns the hosts that obtained a response distinct from the one
+// provided for names that cannot exist within the domain. TLS is used for the ports 443 and 8443.
+func VirtualHosts(ctx context.Context, addr string, port int, domain string, hosts []string) []string {
+	scheme := "http"
+	if port == 443 || port == 8443 {
+		scheme = "https"
+	}
+
+	return probeVirtualHosts(ctx, scheme, addr, port, domain, hosts)
+}
+
+func probeVirtualHosts(ctx context.Context, scheme, addr string, port int, domain string, hosts []string) []string {
+	// The web server responses for two names that cannot exist are the baseline,
+	// and the differences between them reveal how much dynamic content is served
+	var baselines []*vhostResponse
+	for i := 0; i < 2; i++ {
+		host := randomVHostLabel() + "." + domain
+
+		resp, err := requestVirtualHost(ctx, scheme, addr, port, host)
+		if err != nil {
+			return nil
+		}
+		baselines = append(baselines, resp)
+	}
+	if baselines[0].StatusCode != baselines[1].StatusCode || baselines[0].Location != baselines[1].Location {
+		// The responses are too inconsistent to be compared
+		return nil
+	}
+
+	tolerance := baselines[0].Length - baselines[1].Length
+	if tolerance < 0 {
+		tolerance = -tolerance
+	}
+	if t := baselines[0].Length / 20; tolerance < t {
+		tolerance = t
+	}
+	if tolerance < minVHostTolerance {
+		tolerance = minVHostTolerance
+	}
+
+	var results []string
+	for _, host := range hosts {
+		select {
+		case <-ctx.Done():
+			return results
+		default:
+		}
+
+		resp, err := requestVirtualHost(ctx, scheme, addr, port, host)
+		if err == nil && resp.distinct(baselines[0], tolerance) {
+			results = append(results, host)
+		}
+	}
+	return results
+}
+
+// distinct returns true when the response indicates that the web server has a virtual host for the name.
+func (r *vhostResponse) distinct(baseline *vhostResponse, tolerance int) bool {
+	// These status codes reveal that the server does not know the name
+	if r.StatusCode == http.StatusBadRequest || r.StatusCode == http.StatusNotFound ||
+		r.StatusCode == http.StatusMisdirectedRequest || r.StatusCode >= 500 {
+		return false
+	}
+
+	if r.StatusCode != baseline.StatusCode || r.Location != baseline.Location {
+		return true
+	}
+
+	diff := r.Length - baseline.Length
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff > tolerance
+}
+
+func requestVirtualHost(ctx context.Context, scheme, addr string, port int, host string) (*vhostResponse, error) {
+	client := &http.Client{
+		Timeout: vhostRequestTimeout,
+		Transport: &http.Transport{
+			DialContext:       amassnet.DialContext,
+			DisableKeepAlives: true,
+			// The server name is sent so that TLS virtual hosts can be reached as well
+			TLSClientConfig: &tls.Config{
+				ServerName:         host,
+				InsecureSkipVerify: true,
+			},
+		},
+		// The redirects are compared with the baseline instead of being followed
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	u := scheme + "://" + net.JoinHostPort(addr, strconv.Itoa(port)) + "/"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Host = host
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", Accept)
+	req.Header.Set("Accept-Language", AcceptLang)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxVHostBodyLength))
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the requested name so that redirects to the same path on each host compare as equal
+	location := strings.Replace(strings.ToLower(resp.Header.Get("Location")), strings.ToLower(host), "", -1)
+	// The name is also removed from the page content, since many servers include it
+	length := len(body) - strings.Count(strings.ToLower(string(body)), strings.ToLower(host))*len(host)
+
+	return &vhostResponse{
+		StatusCode: resp.StatusCode,
+		Length:     length,
+		Location:   location,
+	}, nil
+}
+
+func randomVHostLabel() string {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+	label := make([]byte, 16)
+	for i := range label {
+		label[i] = chars[rand.Intn(len(chars))]
+	}
+	return string(label)
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestProbeVirtualHosts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Host {
+		case "admin.example.com":
+			w.Write([]byte(strings.Repeat("administration console ", 20)))
+		case "old.example.com":
+			http.Redirect(w, r, "https://portal.example.com/", http.StatusFound)
+		case "missing.example.com":
+			http.NotFound(w, r)
+		default:
+			// The default site includes the requested name in the page
+			w.Write([]byte("Welcome to " + r.Host))
+		}
+	}))
+	defer srv.Close()
+
+	host, p, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(p)
+
+	candidates := []string{"www.example.com", "admin.example.com", "old.example.com", "missing.example.com"}
+	got := probeVirtualHosts(context.Background(), "http", host, port, "example.com", candidates)
+	if len(got) != 2 || got[0] != "admin.example.com" || got[1] != "old.example.com" {
+		t.Errorf("probeVirtualHosts returned %v", got)
+	}
+}
+
+func TestProbeVirtualHostsUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	addr := srv.Listener.Addr().String()
+	srv.Close()
+
+	host, p, _ := net.SplitHostPort(addr)
+	port, _ := strconv.Atoi(p)
+
+	if got := probeVirtualHosts(context.Background(), "http", host, port, "example.com", []string{"www.example.com"}); len(got) != 0 {
+		t.Errorf("probeVirtualHosts returned %v for a server that was not listening", got)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package net
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// LivenessResult describes how an address responded to the liveness probes.
+type LivenessResult struct {
+	Address   string
+	ICMP      bool
+	OpenPorts []int
+	// Set when a TCP connection was actively refused, which still shows the host is up
+	Refused bool
+}
+
+// Alive returns true when the address responded to any of the probes.
+func (r *LivenessResult) Alive() bool {
+	return r.ICMP || r.Refused || len(r.OpenPorts) > 0
+}
+
+// ProbeLiveness sends an ICMP echo request, where the operating system permits it, and
+// attempts TCP connections to the ports in order to learn if the address is responding.
+func ProbeLiveness(ctx context.Context, addr string, ports []int, timeout time.Duration) *LivenessResult {
+	result := &LivenessResult{Address: addr}
+
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return result
+	}
+
+	result.ICMP = pingAddress(ip, timeout)
+	for _, port := range ports {
+		select {
+		case <-ctx.Done():
+			return result
+		default:
+		}
+
+		dctx, cancel := context.WithTimeout(ctx, timeout)
+		conn, err := DialContext(dctx, "tcp", net.JoinHostPort(addr, strconv.Itoa(port)))
+		cancel()
+		if err == nil {
+			conn.Close()
+			result.OpenPorts = append(result.OpenPorts, port)
+		} else if errors.Is(err, syscall.ECONNREFUSED) {
+			result.Refused = true
+		}
+	}
+
+	return result
+}
+
+func pingAddress(ip net.IP, timeout time.Duration) bool {
+	var network, listen string
+	var req, reply icmp.Type
+	var proto int
+
+	if IsIPv4(ip) {
+		network, listen = "udp4", "0.0.0.0"
+		req, reply, proto = ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply, 1
+	} else {
+		network, listen = "udp6", "::"
+		req, reply, proto = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply, 58
+	}
+
+	// Unprivileged ICMP sockets are only available when the operating system permits them
+	conn, err := icmp.ListenPacket(network, listen)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	id := os.Getpid() & 0xffff
+	msg := icmp.Message{
+		Type: req,
+		Body: &icmp.Echo{
+			ID:   id,
+			Seq:  1,
+			Data: []byte("amass"),
+		},
+	}
+	data, err := msg.Marshal(nil)
+	if err != nil {
+		return false
+	}
+	if _, err := conn.WriteTo(data, &net.UDPAddr{IP: ip}); err != nil {
+		return false
+	}
+
+	buf := make([]byte, 1500)
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		conn.SetReadDeadline(deadline)
+
+		n, peer, err := conn.ReadFrom(buf)
+		if err != nil {
+			return false
+		}
+		if u, ok := peer.(*net.UDPAddr); !ok || !u.IP.Equal(ip) {
+			continue
+		}
+
+		if m, err := icmp.ParseMessage(proto, buf[:n]); err == nil && m.Type == reply {
+			return true
+		}
+	}
+
+	return false
+}
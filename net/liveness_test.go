@@ -0,0 +1,43 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package net
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestProbeLiveness(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start the TCP listener: %v", err)
+	}
+	open := ln.Addr().(*net.TCPAddr).Port
+
+	// Obtain a port that is no longer being listened on
+	ln2, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start the TCP listener: %v", err)
+	}
+	closed := ln2.Addr().(*net.TCPAddr).Port
+	ln2.Close()
+	defer ln.Close()
+
+	result := ProbeLiveness(context.Background(), "127.0.0.1", []int{open, closed}, time.Second)
+	if !result.Alive() {
+		t.Errorf("ProbeLiveness failed to identify the address as alive")
+	}
+	if len(result.OpenPorts) != 1 || result.OpenPorts[0] != open {
+		t.Errorf("ProbeLiveness returned %v instead of the open port %d", result.OpenPorts, open)
+	}
+	if !result.Refused {
+		t.Errorf("ProbeLiveness failed to identify the refused connection on port %d", closed)
+	}
+
+	if result := ProbeLiveness(context.Background(), "not an address", []int{open}, time.Second); result.Alive() {
+		t.Errorf("ProbeLiveness identified an invalid address as alive")
+	}
+}
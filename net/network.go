@@ -6,10 +6,15 @@ package net
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
 	"math/big"
+	"math/rand"
 	"net"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 )
 
@@ -22,6 +27,9 @@ const ReservedCIDRDescription = "Reserved Network Address Blocks"
 // LocalAddr is the global option for specifying the network interface.
 var LocalAddr net.Addr
 
+// DNSPortRange is the global option for the first and last local ports used by DNS queries.
+var DNSPortRange [2]int
+
 // ReservedCIDRs includes all the networks that are reserved for special use.
 var ReservedCIDRs = []string{
 	"192.168.0.0/16",
@@ -55,28 +63,116 @@ func init() {
 	}
 }
 
-// DialContext performs the dial using global variables (e.g. LocalAddr).
+// DialContext performs the dial using global variables (e.g. LocalAddr and Egress).
 func DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return dial(ctx, network, addr, 0)
+}
+
+// The most local ports from DNSPortRange tried by each dial
+const maxDNSPortAttempts = 10
+
+// DialDNSContext performs the dial for DNS queries using global variables, which includes
+// selecting the local port from DNSPortRange when the range has been set. Ports that are
+// already in use cause the following ports of the range to be tried.
+func DialDNSContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	min, max := DNSPortRange[0], DNSPortRange[1]
+	if min <= 0 || max < min {
+		return dial(ctx, network, addr, 0)
+	}
+
+	size := max - min + 1
+	attempts := maxDNSPortAttempts
+	if size < attempts {
+		attempts = size
+	}
+
+	var err error
+	var conn net.Conn
+	offset := rand.Intn(size)
+	for i := 0; i < attempts; i++ {
+		conn, err = dial(ctx, network, addr, min+(offset+i)%size)
+		if err == nil || !portInUse(err) || ctx.Err() != nil {
+			break
+		}
+	}
+	return conn, err
+}
+
+// portInUse returns true when the error was caused by a local address that cannot be used.
+func portInUse(err error) bool {
+	return errors.Is(err, syscall.EADDRINUSE) || errors.Is(err, syscall.EADDRNOTAVAIL)
+}
+
+func dial(ctx context.Context, network, addr string, port int) (net.Conn, error) {
+	if Egress != nil {
+		if err := Egress.Check(network, addr); err != nil {
+			return nil, err
+		}
+	}
+
 	d := &net.Dialer{DualStack: true}
 
+	var ip net.IP
 	if LocalAddr != nil {
-		addr, _, err := net.ParseCIDR(LocalAddr.String())
+		if a, _, err := net.ParseCIDR(LocalAddr.String()); err == nil {
+			ip = a
+		}
+	}
+
+	if strings.HasPrefix(network, "tcp") && (ip != nil || port > 0) {
+		d.Timeout = 30 * time.Second
+		d.LocalAddr = &net.TCPAddr{
+			IP:   ip,
+			Port: port,
+		}
+	} else if strings.HasPrefix(network, "udp") && (ip != nil || port > 0) {
+		d.LocalAddr = &net.UDPAddr{
+			IP:   ip,
+			Port: port,
+		}
+	}
+
+	return d.DialContext(ctx, network, addr)
+}
 
-		if err == nil && strings.HasPrefix(network, "tcp") {
-			d.Timeout = 30 * time.Second
-			d.LocalAddr = &net.TCPAddr{
-				IP:   addr,
-				Port: 0,
+// InterfaceAddr returns the address assigned to the network interface that should be used
+// as LocalAddr, which prefers IPv4 addresses.
+func InterfaceAddr(iface *net.Interface) (net.Addr, error) {
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("Network interface '%s' has no assigned addresses", iface.Name)
+	}
+
+	var best net.Addr
+	for _, addr := range addrs {
+		if a, ok := addr.(*net.IPNet); ok {
+			if best == nil {
+				best = a
 			}
-		} else if err == nil && strings.HasPrefix(network, "udp") {
-			d.LocalAddr = &net.UDPAddr{
-				IP:   addr,
-				Port: 0,
+			if IsIPv4(a.IP) {
+				best = a
+				break
 			}
 		}
 	}
 
-	return d.DialContext(ctx, network, addr)
+	if best == nil {
+		return nil, fmt.Errorf("Network interface '%s' does not have assigned IP addresses", iface.Name)
+	}
+	return best, nil
+}
+
+// SourceAddr returns the IP address as a value that can be used as LocalAddr.
+func SourceAddr(ip net.IP) net.Addr {
+	bits := 128
+	if IsIPv4(ip) {
+		bits = 32
+	}
+
+	return &net.IPNet{
+		IP:   ip,
+		Mask: net.CIDRMask(bits, bits),
+	}
 }
 
 // IsIPv4 returns true when the provided net.IP address is an IPv4 address.
@@ -257,6 +353,53 @@ func CIDRSubset(cidr *net.IPNet, addr string, num int) []net.IP {
 	return RangeHosts(first, last)
 }
 
+// IPv6Sample returns up to num addresses from the IPv6 cidr parameter that are likely to have
+// PTR records, since sweeping a contiguous range of a huge IPv6 prefix rarely finds any. The
+// sample includes the addresses around addr, the low host addresses that are often assigned
+// by hand within the /64 subnet of addr, and the interface identifier of addr in the
+// neighboring /64 subnets.
+func IPv6Sample(cidr *net.IPNet, addr string, num int) []net.IP {
+	ip := net.ParseIP(addr)
+	if ip == nil || !cidr.Contains(ip) {
+		return []net.IP{ip}
+	}
+
+	var ips []net.IP
+	filter := make(map[string]struct{})
+	add := func(candidate net.IP) {
+		if len(ips) >= num || !cidr.Contains(candidate) {
+			return
+		}
+		if _, found := filter[candidate.String()]; !found {
+			filter[candidate.String()] = struct{}{}
+			ips = append(ips, candidate)
+		}
+	}
+
+	for _, near := range CIDRSubset(cidr, addr, num/2) {
+		add(near)
+	}
+
+	subnet := ip.Mask(net.CIDRMask(64, 128))
+	for i := 1; i <= num/4 && i <= 0xffff; i++ {
+		low := make(net.IP, net.IPv6len)
+		copy(low, subnet)
+		binary.BigEndian.PutUint16(low[14:], uint16(i))
+		add(low)
+	}
+
+	prefix := binary.BigEndian.Uint64(ip.To16()[:8])
+	for i := uint64(1); len(ips) < num && i <= uint64(num); i++ {
+		for _, p := range []uint64{prefix + i, prefix - i} {
+			neighbor := make(net.IP, net.IPv6len)
+			copy(neighbor, ip.To16())
+			binary.BigEndian.PutUint64(neighbor[:8], p)
+			add(neighbor)
+		}
+	}
+	return ips
+}
+
 // IPInc increments the IP address provided.
 func IPInc(ip net.IP) {
 	for j := len(ip) - 1; j >= 0; j-- {
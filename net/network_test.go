@@ -4,6 +4,7 @@
 package net
 
 import (
+	"context"
 	"net"
 	"strconv"
 	"testing"
@@ -211,6 +212,32 @@ func TestCIDRSubset(t *testing.T) {
 	}
 }
 
+func TestIPv6Sample(t *testing.T) {
+	_, ipnet, _ := net.ParseCIDR("2001:db8::/48")
+
+	sample := IPv6Sample(ipnet, "2001:db8:0:5::abcd", 40)
+	if l := len(sample); l != 40 {
+		t.Fatalf("The returned sample had %d elements instead of 40", l)
+	}
+
+	found := make(map[string]bool)
+	for _, ip := range sample {
+		if !ipnet.Contains(ip) {
+			t.Errorf("IP address %s is not within %s", ip, ipnet)
+		}
+		if found[ip.String()] {
+			t.Errorf("IP address %s was returned more than once", ip)
+		}
+		found[ip.String()] = true
+	}
+
+	for _, expected := range []string{"2001:db8:0:5::abc3", "2001:db8:0:5::1", "2001:db8:0:4::abcd", "2001:db8:0:6::abcd"} {
+		if !found[expected] {
+			t.Errorf("IP address %s was missing from the sample", expected)
+		}
+	}
+}
+
 func TestIPInc(t *testing.T) {
 	tests := []struct {
 		Address  string
@@ -252,3 +279,61 @@ func TestIPDec(t *testing.T) {
 		}
 	}
 }
+
+func TestDialDNSContextPortRange(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to open the UDP listener: %v", err)
+	}
+	defer pc.Close()
+
+	DNSPortRange = [2]int{45000, 45099}
+	defer func() { DNSPortRange = [2]int{} }()
+
+	conn, err := DialDNSContext(context.Background(), "udp", pc.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Failed to dial the UDP listener: %v", err)
+	}
+	defer conn.Close()
+
+	if port := conn.LocalAddr().(*net.UDPAddr).Port; port < 45000 || port > 45099 {
+		t.Errorf("The local port %d was not selected from the DNS port range", port)
+	}
+}
+
+func TestDialDNSContextPortInUse(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to open the UDP listener: %v", err)
+	}
+	defer pc.Close()
+
+	// Occupy the first port of the range
+	used, err := net.ListenPacket("udp", ":0")
+	if err != nil {
+		t.Fatalf("Failed to open the UDP listener: %v", err)
+	}
+	defer used.Close()
+	port := used.LocalAddr().(*net.UDPAddr).Port
+
+	DNSPortRange = [2]int{port, port + 1}
+	defer func() { DNSPortRange = [2]int{} }()
+
+	for i := 0; i < 5; i++ {
+		conn, err := DialDNSContext(context.Background(), "udp", pc.LocalAddr().String())
+		if err != nil {
+			t.Fatalf("The dial failed instead of selecting the unused port: %v", err)
+		}
+
+		if p := conn.LocalAddr().(*net.UDPAddr).Port; p != port+1 {
+			t.Errorf("The local port %d was selected instead of %d", p, port+1)
+		}
+		conn.Close()
+	}
+
+	DNSPortRange = [2]int{port, port}
+	if conn, err := DialDNSContext(context.Background(), "udp", pc.LocalAddr().String()); err == nil {
+		conn.Close()
+		t.Errorf("The dial did not fail when all the ports of the range were in use")
+	}
+}
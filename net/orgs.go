@@ -0,0 +1,220 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package net
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The words left out when organization names are compared, such as the legal suffixes
+var orgNoiseWords = map[string]struct{}{
+	"ab": {}, "ag": {}, "and": {}, "as": {}, "asa": {}, "asn": {}, "bv": {}, "co": {}, "company": {},
+	"corp": {}, "corporation": {}, "gmbh": {}, "inc": {}, "incorporated": {}, "kg": {}, "kk": {},
+	"limited": {}, "llc": {}, "llp": {}, "lp": {}, "ltd": {}, "ltda": {}, "mbh": {}, "nv": {}, "of": {},
+	"oy": {}, "oyj": {}, "plc": {}, "pte": {}, "pty": {}, "sa": {}, "sarl": {}, "sas": {}, "se": {},
+	"spa": {}, "srl": {}, "the": {},
+}
+
+// OrgCandidate is an autonomous system that could belong to the organization searched for.
+type OrgCandidate struct {
+	ASN         int
+	Description string
+	// The registries and data sources that provided the autonomous system
+	Sources []string
+	// How closely the description matches the organization name, from 0 to 1
+	Score float64
+}
+
+// NormalizeOrgName returns the organization name in lowercase without the punctuation, legal suffixes
+// and the country code that ends the autonomous system descriptions, so "Example, Inc., US" and
+// "EXAMPLE INC" are both normalized to "example".
+func NormalizeOrgName(name string) string {
+	return strings.Join(orgTokens(name), " ")
+}
+
+func orgTokens(name string) []string {
+	name = strings.TrimSpace(name)
+	// Descriptions such as "EXAMPLE-AS - Example, Inc., US" end with the country code
+	if i := strings.LastIndex(name, ","); i != -1 {
+		if cc := strings.TrimSpace(name[i+1:]); len(cc) == 2 && strings.ToUpper(cc) == cc {
+			name = name[:i]
+		}
+	}
+
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	var tokens []string
+	for _, f := range fields {
+		if _, noise := orgNoiseWords[f]; !noise {
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
+
+// OrgNameScore returns how closely the description matches the organization name, from 0 for no
+// similarity to 1 when the names are the same once normalized. Misspelled and abbreviated words,
+// along with names written with or without spaces, are still matched with lower scores.
+func OrgNameScore(org, desc string) float64 {
+	query := orgTokens(org)
+	cand := uniqueTokens(orgTokens(desc))
+	if len(query) == 0 || len(cand) == 0 {
+		return 0
+	}
+
+	var matched float64
+	used := make(map[int]struct{})
+	for _, q := range query {
+		best, idx := 0.0, -1
+
+		for i, c := range cand {
+			if s := tokenSimilarity(q, c); s > best {
+				best, idx = s, i
+			}
+		}
+		if idx != -1 {
+			matched += best
+			used[idx] = struct{}{}
+		}
+	}
+
+	coverage := matched / float64(len(query))
+	// Names like "Face Book" are matched against "FACEBOOK"
+	if compact := strings.Join(query, ""); coverage < 0.95 && len(query) > 1 && len(compact) >= 4 {
+		for i, c := range cand {
+			if c == compact {
+				coverage = 0.95
+				used = map[int]struct{}{i: {}}
+				break
+			}
+		}
+	}
+	if coverage == 0 {
+		return 0
+	}
+
+	// The descriptions with many other words are less likely to be the organization
+	precision := float64(len(used)) / float64(len(cand))
+	if coverage == 1 && precision == 1 {
+		return 1
+	}
+	return 0.8*coverage + 0.2*precision
+}
+
+func uniqueTokens(tokens []string) []string {
+	var results []string
+	seen := make(map[string]struct{})
+
+	for _, t := range tokens {
+		if _, found := seen[t]; !found {
+			seen[t] = struct{}{}
+			results = append(results, t)
+		}
+	}
+	return results
+}
+
+func tokenSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	shorter := len(a)
+	if len(b) < shorter {
+		shorter = len(b)
+	}
+	if shorter >= 5 {
+		if d := editDistance(a, b); d == 1 || (d == 2 && shorter >= 8) {
+			return 0.9
+		}
+	}
+	if shorter >= 4 && (strings.HasPrefix(a, b) || strings.HasPrefix(b, a)) {
+		return 0.8
+	}
+	return 0
+}
+
+// editDistance returns the Levenshtein distance between the words.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// RankOrgCandidates merges the candidates provided for the same ASN, scores the descriptions against
+// the organization name, and returns the candidates scoring at least min from the best match down.
+func RankOrgCandidates(org string, candidates []*OrgCandidate, min float64) []*OrgCandidate {
+	byASN := make(map[int]*OrgCandidate)
+
+	for _, c := range candidates {
+		score := OrgNameScore(org, c.Description)
+
+		cur, found := byASN[c.ASN]
+		if !found {
+			cur = &OrgCandidate{ASN: c.ASN}
+			byASN[c.ASN] = cur
+		}
+		if score > cur.Score || cur.Description == "" {
+			cur.Description = c.Description
+			if score > cur.Score {
+				cur.Score = score
+			}
+		}
+		for _, src := range c.Sources {
+			if !containsString(cur.Sources, src) {
+				cur.Sources = append(cur.Sources, src)
+			}
+		}
+	}
+
+	var ranked []*OrgCandidate
+	for _, c := range byASN {
+		if c.Score >= min {
+			sort.Strings(c.Sources)
+			ranked = append(ranked, c)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		// Autonomous systems known to more sources are more likely to be in use
+		if len(ranked[i].Sources) != len(ranked[j].Sources) {
+			return len(ranked[i].Sources) > len(ranked[j].Sources)
+		}
+		return ranked[i].ASN < ranked[j].ASN
+	})
+	return ranked
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package net
+
+import "testing"
+
+func TestNormalizeOrgName(t *testing.T) {
+	tests := map[string]string{
+		"Example, Inc.":                     "example",
+		"EXAMPLE INC":                       "example",
+		"FACEBOOK - Facebook, Inc., US":     "facebook facebook",
+		"Deutsche Telekom AG":               "deutsche telekom",
+		"The Bank of Example Holdings Ltd.": "bank example holdings",
+		"  ":                                "",
+	}
+
+	for name, want := range tests {
+		if got := NormalizeOrgName(name); got != want {
+			t.Errorf("%q was normalized to %q instead of %q", name, got, want)
+		}
+	}
+}
+
+func TestOrgNameScore(t *testing.T) {
+	exact := OrgNameScore("Facebook, Inc.", "FACEBOOK - Facebook, Inc., US")
+	if exact != 1 {
+		t.Errorf("The same organization scored %f", exact)
+	}
+
+	extra := OrgNameScore("Facebook", "FACEBOOK-OFFNET - Facebook, Inc., US")
+	typo := OrgNameScore("Facebok", "FACEBOOK - Facebook, Inc., US")
+	spaced := OrgNameScore("Face Book", "FACEBOOK - Facebook, Inc., US")
+	for _, score := range []float64{extra, typo, spaced} {
+		if score >= exact || score < 0.7 {
+			t.Errorf("The similar organization scored %f", score)
+		}
+	}
+
+	if score := OrgNameScore("Facebook", "GOOGLE - Google LLC, US"); score != 0 {
+		t.Errorf("The unrelated organization scored %f", score)
+	}
+	if score := OrgNameScore("Inc.", "EXAMPLE - Example, Inc., US"); score != 0 {
+		t.Errorf("The legal suffix alone scored %f", score)
+	}
+}
+
+func TestRankOrgCandidates(t *testing.T) {
+	candidates := []*OrgCandidate{
+		{ASN: 63293, Description: "FACEBOOK-OFFNET - Facebook, Inc., US", Sources: []string{"ASN list"}},
+		{ASN: 32934, Description: "FACEBOOK - Facebook, Inc., US", Sources: []string{"ASN list"}},
+		{ASN: 32934, Description: "Facebook Inc", Sources: []string{"PeeringDB"}},
+		{ASN: 15169, Description: "GOOGLE - Google LLC, US", Sources: []string{"ASN list"}},
+	}
+
+	ranked := RankOrgCandidates("Facebook, Inc.", candidates, 0.5)
+	if len(ranked) != 2 {
+		t.Fatalf("%d candidates were ranked instead of 2", len(ranked))
+	}
+	if ranked[0].ASN != 32934 || len(ranked[0].Sources) != 2 || ranked[0].Score != 1 {
+		t.Errorf("The best candidate was AS%d with the sources %v and score %f",
+			ranked[0].ASN, ranked[0].Sources, ranked[0].Score)
+	}
+	if ranked[1].ASN != 63293 {
+		t.Errorf("The second candidate was AS%d", ranked[1].ASN)
+	}
+}
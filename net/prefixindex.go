@@ -0,0 +1,169 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package net
+
+import (
+	"net"
+)
+
+// PrefixIndex is a path-compressed radix tree of netblocks that finds the most specific
+// netblock containing an IP address without examining each of the netblocks.
+// IPv4 netblocks are stored within the IPv4-mapped IPv6 address space.
+type PrefixIndex struct {
+	root *prefixNode
+	size int
+}
+
+type prefixNode struct {
+	key      net.IP
+	bits     int
+	entry    *prefixEntry
+	children [2]*prefixNode
+}
+
+type prefixEntry struct {
+	ipnet *net.IPNet
+	value interface{}
+}
+
+// NewPrefixIndex returns an empty PrefixIndex.
+func NewPrefixIndex() *PrefixIndex {
+	return new(PrefixIndex)
+}
+
+// Len returns the number of netblocks in the index.
+func (p *PrefixIndex) Len() int {
+	return p.size
+}
+
+// Insert adds the netblock to the index with the value provided, and replaces
+// the value when the netblock is already in the index.
+func (p *PrefixIndex) Insert(ipnet *net.IPNet, value interface{}) {
+	key, bits := prefixKey(ipnet)
+	if key == nil {
+		return
+	}
+
+	entry := &prefixEntry{
+		ipnet: &net.IPNet{IP: ipnet.IP.Mask(ipnet.Mask), Mask: ipnet.Mask},
+		value: value,
+	}
+	leaf := &prefixNode{key: key, bits: bits, entry: entry}
+
+	for np := &p.root; ; {
+		n := *np
+		if n == nil {
+			*np = leaf
+			p.size++
+			return
+		}
+
+		common := commonPrefixLen(n.key, key, minInt(n.bits, bits))
+		switch {
+		case common == n.bits && common == bits:
+			if n.entry == nil {
+				p.size++
+			}
+			n.entry = entry
+			return
+		case common == n.bits:
+			// The node is a supernet of the netblock
+			np = &n.children[bitAt(key, n.bits)]
+			continue
+		case common == bits:
+			// The netblock is a supernet of the node
+			leaf.children[bitAt(n.key, bits)] = n
+		default:
+			branch := &prefixNode{key: maskKey(key, common), bits: common}
+			branch.children[bitAt(n.key, common)] = n
+			branch.children[bitAt(key, common)] = leaf
+			leaf = branch
+		}
+
+		*np = leaf
+		p.size++
+		return
+	}
+}
+
+// Lookup returns the most specific netblock in the index containing the IP address,
+// along with the value of the netblock. A nil netblock is returned when none was found.
+func (p *PrefixIndex) Lookup(ip net.IP) (*net.IPNet, interface{}) {
+	key := ip.To16()
+	if key == nil {
+		return nil, nil
+	}
+
+	var best *prefixEntry
+	for n := p.root; n != nil; n = n.children[bitAt(key, n.bits)] {
+		if commonPrefixLen(n.key, key, n.bits) < n.bits {
+			break
+		}
+		if n.entry != nil {
+			best = n.entry
+		}
+		if n.bits == 8*net.IPv6len {
+			break
+		}
+	}
+
+	if best == nil {
+		return nil, nil
+	}
+	return best.ipnet, best.value
+}
+
+func prefixKey(ipnet *net.IPNet) (net.IP, int) {
+	if ipnet == nil {
+		return nil, 0
+	}
+
+	ones, bits := ipnet.Mask.Size()
+	if bits == 0 {
+		return nil, 0
+	}
+	if bits == 8*net.IPv4len {
+		ones += 8 * (net.IPv6len - net.IPv4len)
+	}
+
+	key := ipnet.IP.To16()
+	if key == nil {
+		return nil, 0
+	}
+	return maskKey(key, ones), ones
+}
+
+func maskKey(key net.IP, bits int) net.IP {
+	return key.Mask(net.CIDRMask(bits, 8*net.IPv6len))
+}
+
+func bitAt(key net.IP, i int) int {
+	return int(key[i/8]>>(7-uint(i%8))) & 1
+}
+
+func commonPrefixLen(a, b net.IP, max int) int {
+	var n int
+
+	for i := 0; i < len(a) && n < max; i++ {
+		if x := a[i] ^ b[i]; x != 0 {
+			for mask := byte(0x80); mask != 0 && x&mask == 0; mask >>= 1 {
+				n++
+			}
+			break
+		}
+		n += 8
+	}
+
+	if n > max {
+		n = max
+	}
+	return n
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package net
+
+import (
+	"math/rand"
+	"net"
+	"testing"
+)
+
+func TestPrefixIndexLookup(t *testing.T) {
+	idx := NewPrefixIndex()
+
+	for i, cidr := range []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24", "192.0.2.0/24", "2001:db8::/32", "2001:db8:1::/48"} {
+		_, ipnet, _ := net.ParseCIDR(cidr)
+		idx.Insert(ipnet, i)
+	}
+	// Inserting a netblock again replaces the value
+	_, ipnet, _ := net.ParseCIDR("192.0.2.0/24")
+	idx.Insert(ipnet, 10)
+
+	if idx.Len() != 6 {
+		t.Errorf("The index holds %d netblocks instead of 6", idx.Len())
+	}
+
+	tests := []struct {
+		Addr     string
+		Expected string
+		Value    int
+	}{
+		{"10.1.2.3", "10.1.2.0/24", 2},
+		{"10.1.3.3", "10.1.0.0/16", 1},
+		{"10.200.0.1", "10.0.0.0/8", 0},
+		{"192.0.2.55", "192.0.2.0/24", 10},
+		{"2001:db8:1::1", "2001:db8:1::/48", 5},
+		{"2001:db8:2::1", "2001:db8::/32", 4},
+		{"11.0.0.1", "", 0},
+		{"::ffff:10.1.2.3", "10.1.2.0/24", 2},
+	}
+
+	for _, test := range tests {
+		cidr, value := idx.Lookup(net.ParseIP(test.Addr))
+
+		if test.Expected == "" {
+			if cidr != nil {
+				t.Errorf("%s was found in %s", test.Addr, cidr)
+			}
+			continue
+		}
+		if cidr == nil || cidr.String() != test.Expected || value.(int) != test.Value {
+			t.Errorf("%s was found in %v with value %v, expected %s with value %d",
+				test.Addr, cidr, value, test.Expected, test.Value)
+		}
+	}
+}
+
+func TestPrefixIndexMatchesLinearSearch(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	idx := NewPrefixIndex()
+
+	var netblocks []*net.IPNet
+	for i := 0; i < 2000; i++ {
+		ip := net.IPv4(10, byte(r.Intn(4)), byte(r.Intn(256)), byte(r.Intn(256)))
+		mask := net.CIDRMask(8+r.Intn(25), 32)
+		ipnet := &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+
+		netblocks = append(netblocks, ipnet)
+		idx.Insert(ipnet, ipnet.String())
+	}
+
+	for i := 0; i < 2000; i++ {
+		ip := net.IPv4(10, byte(r.Intn(4)), byte(r.Intn(256)), byte(r.Intn(256)))
+
+		var best *net.IPNet
+		var bestLen int
+		for _, ipnet := range netblocks {
+			if ones, _ := ipnet.Mask.Size(); ipnet.Contains(ip) && (best == nil || ones > bestLen) {
+				best = ipnet
+				bestLen = ones
+			}
+		}
+
+		cidr, _ := idx.Lookup(ip)
+		if (best == nil) != (cidr == nil) || (best != nil && best.String() != cidr.String()) {
+			t.Fatalf("%s was found in %v instead of %v", ip, cidr, best)
+		}
+	}
+}
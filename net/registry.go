@@ -0,0 +1,187 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package net
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RegistryNames contains the regional Internet registries that publish delegation files.
+var RegistryNames = []string{"afrinic", "apnic", "arin", "lacnic", "ripencc"}
+
+type delegatedRange struct {
+	First    uint32
+	Last     uint32
+	Country  string
+	Registry string
+}
+
+type delegatedIPv6 struct {
+	Net      *net.IPNet
+	Country  string
+	Registry string
+}
+
+// Delegations holds the ASN and address space delegations published by the regional Internet registries.
+type Delegations struct {
+	asns []delegatedRange
+	ipv4 []delegatedRange
+	ipv6 []delegatedIPv6
+}
+
+// NewDelegations returns an empty Delegations object.
+func NewDelegations() *Delegations {
+	return new(Delegations)
+}
+
+// Parse reads records in the RIR statistics exchange (delegated-extended) format.
+func (d *Delegations) Parse(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// registry|cc|type|start|value|date|status[|opaque-id]
+		parts := strings.Split(line, "|")
+		if len(parts) < 7 || parts[1] == "*" || parts[1] == "" {
+			continue
+		}
+		if status := parts[6]; status != "allocated" && status != "assigned" {
+			continue
+		}
+
+		registry := strings.ToLower(parts[0])
+		cc := strings.ToUpper(parts[1])
+		value, err := strconv.ParseUint(parts[4], 10, 32)
+		if err != nil || value == 0 {
+			continue
+		}
+
+		switch parts[2] {
+		case "asn":
+			first, err := strconv.ParseUint(parts[3], 10, 32)
+			if err != nil {
+				continue
+			}
+
+			d.asns = append(d.asns, delegatedRange{
+				First:    uint32(first),
+				Last:     uint32(first + value - 1),
+				Country:  cc,
+				Registry: registry,
+			})
+		case "ipv4":
+			ip := net.ParseIP(parts[3]).To4()
+			if ip == nil {
+				continue
+			}
+
+			first := binary.BigEndian.Uint32(ip)
+			d.ipv4 = append(d.ipv4, delegatedRange{
+				First:    first,
+				Last:     first + uint32(value-1),
+				Country:  cc,
+				Registry: registry,
+			})
+		case "ipv6":
+			_, ipnet, err := net.ParseCIDR(parts[3] + "/" + parts[4])
+			if err != nil {
+				continue
+			}
+
+			d.ipv6 = append(d.ipv6, delegatedIPv6{
+				Net:      ipnet,
+				Country:  cc,
+				Registry: registry,
+			})
+		}
+	}
+
+	sort.Slice(d.asns, func(i, j int) bool { return d.asns[i].First < d.asns[j].First })
+	sort.Slice(d.ipv4, func(i, j int) bool { return d.ipv4[i].First < d.ipv4[j].First })
+	return scanner.Err()
+}
+
+// ASNInRegion returns true when the ASN was delegated to one of the country codes or registries in regions.
+func (d *Delegations) ASNInRegion(asn int, regions []string) bool {
+	if asn < 0 {
+		return false
+	}
+
+	if r := searchRanges(d.asns, uint32(asn)); r != nil {
+		return inRegion(r.Country, r.Registry, regions)
+	}
+	return false
+}
+
+// NetblockInRegion returns true when the first address of the netblock was delegated
+// to one of the country codes or registries in regions.
+func (d *Delegations) NetblockInRegion(cidr *net.IPNet, regions []string) bool {
+	ip := cidr.IP.Mask(cidr.Mask)
+
+	if ip4 := ip.To4(); ip4 != nil {
+		if r := searchRanges(d.ipv4, binary.BigEndian.Uint32(ip4)); r != nil {
+			return inRegion(r.Country, r.Registry, regions)
+		}
+		return false
+	}
+
+	for _, r := range d.ipv6 {
+		if r.Net.Contains(ip) {
+			return inRegion(r.Country, r.Registry, regions)
+		}
+	}
+	return false
+}
+
+func searchRanges(ranges []delegatedRange, value uint32) *delegatedRange {
+	// Find the last range starting at or before the value
+	idx := sort.Search(len(ranges), func(i int) bool { return ranges[i].First > value }) - 1
+	if idx < 0 || value > ranges[idx].Last {
+		return nil
+	}
+	return &ranges[idx]
+}
+
+func inRegion(cc, registry string, regions []string) bool {
+	for _, region := range regions {
+		region = strings.TrimSpace(region)
+
+		if strings.EqualFold(region, cc) || strings.EqualFold(registryName(region), registry) {
+			return true
+		}
+	}
+	return false
+}
+
+// RegistryForRegion returns the registry name when the region identifies a registry
+// instead of a country code, and an empty string otherwise.
+func RegistryForRegion(region string) string {
+	name := registryName(region)
+
+	for _, reg := range RegistryNames {
+		if name == reg {
+			return reg
+		}
+	}
+	return ""
+}
+
+func registryName(region string) string {
+	name := strings.ToLower(strings.TrimSpace(region))
+	// RIPE NCC is often referred to without the suffix
+	if name == "ripe" {
+		name = "ripencc"
+	}
+	return name
+}
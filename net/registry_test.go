@@ -0,0 +1,73 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package net
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+const testDelegations = `2|ripencc|1598914799|142813|19830705|20200831|+0200
+ripencc|*|asn|*|35338|summary
+ripencc|DE|asn|3209|1|19940824|allocated|a1b2c3
+ripencc|FR|asn|3215|2|19940824|allocated|d4e5f6
+ripencc|DE|ipv4|80.128.0.0|1048576|20020115|allocated|a1b2c3
+ripencc||ipv4|81.0.0.0|256||available|
+ripencc|DE|ipv6|2003::|19|20050224|allocated|a1b2c3
+arin|US|asn|15169|1|20000330|assigned|g7h8i9
+`
+
+func TestDelegations(t *testing.T) {
+	d := NewDelegations()
+	if err := d.Parse(strings.NewReader(testDelegations)); err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+
+	asns := []struct {
+		ASN      int
+		Regions  []string
+		Expected bool
+	}{
+		{3209, []string{"DE"}, true},
+		{3209, []string{"de"}, true},
+		{3209, []string{"FR"}, false},
+		{3216, []string{"FR"}, true},
+		{3217, []string{"FR"}, false},
+		{3209, []string{"ripe"}, true},
+		{15169, []string{"ripencc"}, false},
+		{15169, []string{"DE", "arin"}, true},
+	}
+	for _, test := range asns {
+		if got := d.ASNInRegion(test.ASN, test.Regions); got != test.Expected {
+			t.Errorf("ASNInRegion(%d, %v) returned %t", test.ASN, test.Regions, got)
+		}
+	}
+
+	netblocks := []struct {
+		CIDR     string
+		Regions  []string
+		Expected bool
+	}{
+		{"80.130.0.0/16", []string{"DE"}, true},
+		{"80.130.0.0/16", []string{"FR"}, false},
+		{"81.0.0.0/24", []string{"DE"}, false},
+		{"2003:40::/32", []string{"DE"}, true},
+		{"2a00::/32", []string{"DE"}, false},
+	}
+	for _, test := range netblocks {
+		_, ipnet, _ := net.ParseCIDR(test.CIDR)
+
+		if got := d.NetblockInRegion(ipnet, test.Regions); got != test.Expected {
+			t.Errorf("NetblockInRegion(%s, %v) returned %t", test.CIDR, test.Regions, got)
+		}
+	}
+
+	if reg := RegistryForRegion("RIPE"); reg != "ripencc" {
+		t.Errorf("RegistryForRegion returned %s instead of ripencc", reg)
+	}
+	if reg := RegistryForRegion("DE"); reg != "" {
+		t.Errorf("RegistryForRegion returned %s for a country code", reg)
+	}
+}
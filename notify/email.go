@@ -0,0 +1,115 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	amassnet "github.com/OWASP/Amass/v3/net"
+)
+
+// The amount of time allowed for the exchange with the SMTP server
+const smtpTimeout = 2 * time.Minute
+
+// SendEmail delivers the plain text body to the recipients of the email report configuration.
+func SendEmail(cfg *config.EmailReportConfig, subject, body string) error {
+	if cfg == nil {
+		return fmt.Errorf("SendEmail: No email report configuration was provided")
+	}
+
+	host, _, err := net.SplitHostPort(cfg.Server)
+	if err != nil {
+		return fmt.Errorf("SendEmail: Failed to parse the server %s: %v", cfg.Server, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), smtpTimeout)
+	defer cancel()
+
+	conn, err := amassnet.DialContext(ctx, "tcp", cfg.Server)
+	if err != nil {
+		return fmt.Errorf("SendEmail: Failed to connect with %s: %v", cfg.Server, err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	tlsConfig := &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: cfg.SkipVerify,
+	}
+	if cfg.TLS == config.EmailTLSImplicit {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SendEmail: %v", err)
+	}
+	defer c.Close()
+
+	if cfg.TLS == config.EmailTLSStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SendEmail: The server %s does not support STARTTLS", cfg.Server)
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("SendEmail: STARTTLS failed: %v", err)
+		}
+	}
+
+	if cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, host)); err != nil {
+			return fmt.Errorf("SendEmail: Authentication failed: %v", err)
+		}
+	}
+
+	if err := c.Mail(cfg.From); err != nil {
+		return fmt.Errorf("SendEmail: The server rejected the sender %s: %v", cfg.From, err)
+	}
+	for _, to := range cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("SendEmail: The server rejected the recipient %s: %v", to, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("SendEmail: %v", err)
+	}
+	if _, err := w.Write(buildMessage(cfg, subject, body, time.Now())); err != nil {
+		w.Close()
+		return fmt.Errorf("SendEmail: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SendEmail: %v", err)
+	}
+	return c.Quit()
+}
+
+func buildMessage(cfg *config.EmailReportConfig, subject, body string, now time.Time) []byte {
+	var msg bytes.Buffer
+
+	fmt.Fprintf(&msg, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+
+	// SMTP requires CRLF line endings
+	body = strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n")
+	msg.WriteString(body)
+	if !strings.HasSuffix(body, "\r\n") {
+		msg.WriteString("\r\n")
+	}
+	return msg.Bytes()
+}
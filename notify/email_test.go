@@ -0,0 +1,88 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+)
+
+// serveSMTP accepts a single session and returns the commands and message data received.
+func serveSMTP(t *testing.T, l net.Listener, received chan<- []string) {
+	conn, err := l.Accept()
+	if err != nil {
+		t.Errorf("Failed to accept the connection: %v", err)
+		close(received)
+		return
+	}
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	var lines []string
+	tp.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			break
+		}
+		lines = append(lines, line)
+
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250 localhost")
+		case "DATA":
+			tp.PrintfLine("354 Send the message")
+			data, _ := tp.ReadDotLines()
+			lines = append(lines, data...)
+			tp.PrintfLine("250 Accepted")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			received <- lines
+			return
+		default:
+			tp.PrintfLine("250 OK")
+		}
+	}
+	received <- lines
+}
+
+func TestSendEmail(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer l.Close()
+
+	received := make(chan []string, 1)
+	go serveSMTP(t, l, received)
+
+	cfg := &config.EmailReportConfig{
+		Server: l.Addr().String(),
+		TLS:    config.EmailTLSNone,
+		From:   "amass@example.com",
+		To:     []string{"team@example.com", "oncall@example.com"},
+	}
+	if err := SendEmail(cfg, "Amass report", "1 found\n.hidden line\n"); err != nil {
+		t.Fatalf("SendEmail failed: %v", err)
+	}
+
+	session := strings.Join(<-received, "\n")
+	for _, expected := range []string{
+		"MAIL FROM:<amass@example.com>",
+		"RCPT TO:<team@example.com>",
+		"RCPT TO:<oncall@example.com>",
+		"Subject: Amass report",
+		"1 found",
+		".hidden line",
+	} {
+		if !strings.Contains(session, expected) {
+			t.Errorf("The session did not include %q:\n%s", expected, session)
+		}
+	}
+}
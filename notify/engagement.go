@@ -0,0 +1,369 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+)
+
+// The number of autonomous systems and data sources shown in the charts
+const engagementChartSize = 15
+
+// Count associates a name, such as a discovery technique, with the number of findings.
+type Count struct {
+	Name  string
+	Count int
+}
+
+// ASNSummary describes the addresses discovered within an autonomous system.
+type ASNSummary struct {
+	ASN         int
+	Description string
+	Addresses   int
+	Netblocks   []Count
+}
+
+// EngagementReport presents the findings of an enumeration, along with the changes since the
+// previous enumeration of the domains, as a standalone HTML page.
+type EngagementReport struct {
+	Title     string
+	Domains   []string
+	Generated time.Time
+	// The period of the enumeration, and of the previous enumeration when one was available
+	Start          time.Time
+	Finish         time.Time
+	PreviousStart  time.Time
+	PreviousFinish time.Time
+	Assets         []*requests.Output
+	Addresses      int
+	Netblocks      int
+	Tags           []Count
+	ASNs           []ASNSummary
+	// The names provided by each data source, and the names that no other data source provided
+	Sources []systems.SourceStats
+	// The names found, moved and removed since the previous enumeration, or nil without one
+	Changes *Report
+}
+
+// NewEngagementReport returns an EngagementReport for the current findings. The previous findings are
+// compared with the current findings unless nil, and the assets on the ignore list, which can be nil,
+// are left out of the report.
+func NewEngagementReport(domains []string, current, previous []*requests.Output, il *format.IgnoreList) *EngagementReport {
+	r := &EngagementReport{
+		Domains:   domains,
+		Generated: time.Now(),
+	}
+
+	tags := make(map[string]int)
+	asns := make(map[int]*format.ASNSummaryData)
+	addrs := make(map[string]struct{})
+	sources := make(map[string]*systems.SourceStats)
+	for _, o := range current {
+		if il.Ignored(o) {
+			continue
+		}
+
+		r.Assets = append(r.Assets, o)
+		format.UpdateSummaryData(o, tags, asns)
+		for _, a := range o.Addresses {
+			if a.Address != nil {
+				addrs[a.Address.String()] = struct{}{}
+			}
+		}
+
+		for _, src := range o.Sources {
+			s, found := sources[src]
+			if !found {
+				s = &systems.SourceStats{Source: src}
+				sources[src] = s
+			}
+
+			s.Names++
+			if len(o.Sources) == 1 {
+				s.Unique++
+			}
+		}
+	}
+	sort.Slice(r.Assets, func(i, j int) bool { return r.Assets[i].Name < r.Assets[j].Name })
+	r.Addresses = len(addrs)
+
+	r.Tags = sortedCounts(tags)
+	for asn, data := range asns {
+		summary := ASNSummary{
+			ASN:         asn,
+			Description: data.Name,
+			Netblocks:   sortedCounts(data.Netblocks),
+		}
+
+		for _, nb := range summary.Netblocks {
+			summary.Addresses += nb.Count
+		}
+		r.Netblocks += len(summary.Netblocks)
+		r.ASNs = append(r.ASNs, summary)
+	}
+	sort.Slice(r.ASNs, func(i, j int) bool {
+		if r.ASNs[i].Addresses != r.ASNs[j].Addresses {
+			return r.ASNs[i].Addresses > r.ASNs[j].Addresses
+		}
+		return r.ASNs[i].ASN < r.ASNs[j].ASN
+	})
+
+	for _, s := range sources {
+		r.Sources = append(r.Sources, *s)
+	}
+	sort.Slice(r.Sources, func(i, j int) bool {
+		if r.Sources[i].Names != r.Sources[j].Names {
+			return r.Sources[i].Names > r.Sources[j].Names
+		}
+		return r.Sources[i].Source < r.Sources[j].Source
+	})
+
+	if previous != nil {
+		r.Changes = NewReport(domains, previous, current, il)
+	}
+	return r
+}
+
+// sortedCounts returns the counts from the largest to the smallest.
+func sortedCounts(counts map[string]int) []Count {
+	var results []Count
+
+	for name, count := range counts {
+		results = append(results, Count{Name: name, Count: count})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Count != results[j].Count {
+			return results[i].Count > results[j].Count
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// WriteHTML renders the report as a standalone HTML page.
+func (r *EngagementReport) WriteHTML(w io.Writer) error {
+	return engagementTemplate.Execute(w, r)
+}
+
+var engagementTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"addresses": addressLine,
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return "unknown"
+		}
+		return t.Format(time.RFC1123)
+	},
+	"join": strings.Join,
+	// The width of a chart bar as a percentage of the largest value
+	"percent": func(count, max int) float64 {
+		if max <= 0 {
+			return 0
+		}
+		return float64(count) * 100 / float64(max)
+	},
+	"topASNs": func(asns []ASNSummary) []ASNSummary {
+		if len(asns) > engagementChartSize {
+			return asns[:engagementChartSize]
+		}
+		return asns
+	},
+	"topSources": func(srcs []systems.SourceStats) []systems.SourceStats {
+		if len(srcs) > engagementChartSize {
+			return srcs[:engagementChartSize]
+		}
+		return srcs
+	},
+}).Parse(engagementHTML))
+
+const engagementHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="utf-8">
+    <title>{{if .Title}}{{.Title}}{{else}}OWASP Amass Report{{end}}</title>
+    <style>
+        body {
+            margin: 0 auto;
+            max-width: 1100px;
+            padding: 20px;
+            color: #222;
+            font-family: 'Open Sans', sans-serif;
+            font-size: 14px;
+        }
+        h1 {
+            margin-bottom: 4px;
+        }
+        h2 {
+            margin-top: 36px;
+            padding-bottom: 4px;
+            border-bottom: 2px solid #1a45f3;
+        }
+        p.meta {
+            margin-top: 0;
+            color: #666;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+            margin-bottom: 12px;
+        }
+        th, td {
+            padding: 6px 8px;
+            text-align: left;
+            vertical-align: top;
+            border-bottom: 1px solid #ddd;
+        }
+        th {
+            background-color: #f3f3f3;
+        }
+        td.num, th.num {
+            text-align: right;
+        }
+        div.cards {
+            display: flex;
+            flex-wrap: wrap;
+            gap: 12px;
+        }
+        div.card {
+            flex: 1;
+            min-width: 130px;
+            padding: 12px;
+            border: 1px solid #ddd;
+            border-radius: 4px;
+        }
+        div.card span {
+            display: block;
+            font-size: 26px;
+            font-weight: bold;
+        }
+        table.chart td.label {
+            width: 30%;
+        }
+        div.bar {
+            height: 14px;
+            min-width: 2px;
+            background-color: #1a45f3;
+        }
+        tr.found td:first-child {
+            border-left: 4px solid #229954;
+        }
+        tr.moved td:first-child {
+            border-left: 4px solid #f39c12;
+        }
+        tr.removed td:first-child {
+            border-left: 4px solid #f22c0d;
+        }
+    </style>
+</head>
+<body>
+    <h1>{{if .Title}}{{.Title}}{{else}}OWASP Amass Report{{end}}</h1>
+    <p class="meta">Domains: {{join .Domains ", "}}<br>
+        Enumeration: {{date .Start}} to {{date .Finish}}<br>
+        {{- if .Changes}}
+        Compared with the enumeration from {{date .PreviousStart}} to {{date .PreviousFinish}}<br>
+        {{- end}}
+        Generated: {{date .Generated}}</p>
+
+    <h2>Summary</h2>
+    <div class="cards">
+        <div class="card"><span>{{len .Assets}}</span>Names</div>
+        <div class="card"><span>{{.Addresses}}</span>Addresses</div>
+        <div class="card"><span>{{.Netblocks}}</span>Netblocks</div>
+        <div class="card"><span>{{len .ASNs}}</span>Autonomous Systems</div>
+        <div class="card"><span>{{len .Sources}}</span>Data Sources</div>
+        {{- if .Changes}}
+        <div class="card"><span>{{len .Changes.Found}}</span>New Names</div>
+        <div class="card"><span>{{len .Changes.Moved}}</span>Changed Addresses</div>
+        <div class="card"><span>{{len .Changes.Removed}}</span>Removed Names</div>
+        {{- end}}
+    </div>
+
+    {{- if .Tags}}
+    <h3>Names by Discovery Technique</h3>
+    <table class="chart">
+        {{- $max := (index .Tags 0).Count}}
+        {{- range .Tags}}
+        <tr><td class="label">{{.Name}}</td><td><div class="bar" style="width: {{percent .Count $max}}%"></div></td><td class="num">{{.Count}}</td></tr>
+        {{- end}}
+    </table>
+    {{- end}}
+
+    <h2>Changes Since the Previous Enumeration</h2>
+    {{- if not .Changes}}
+    <p>No previous enumeration of the domains was available for comparison.</p>
+    {{- else if .Changes.Empty}}
+    <p>The names and addresses did not change since the previous enumeration.</p>
+    {{- else}}
+    <table>
+        <tr><th>Change</th><th>Name</th><th>Addresses</th></tr>
+        {{- range .Changes.Found}}
+        <tr class="found"><td>New</td><td>{{.Name}}</td><td>{{addresses .Addresses}}</td></tr>
+        {{- end}}
+        {{- range .Changes.Moved}}
+        <tr class="moved"><td>Changed</td><td>{{.Name}}</td><td>{{addresses .From}} &rarr; {{addresses .To}}</td></tr>
+        {{- end}}
+        {{- range .Changes.Removed}}
+        <tr class="removed"><td>Removed</td><td>{{.Name}}</td><td>{{addresses .Addresses}}</td></tr>
+        {{- end}}
+    </table>
+    {{- end}}
+
+    <h2>Autonomous Systems</h2>
+    {{- if not .ASNs}}
+    <p>The infrastructure of the addresses was not available.</p>
+    {{- else}}
+    <h3>Addresses by Autonomous System</h3>
+    <table class="chart">
+        {{- $max := (index .ASNs 0).Addresses}}
+        {{- range topASNs .ASNs}}
+        <tr><td class="label">AS{{.ASN}} {{.Description}}</td><td><div class="bar" style="width: {{percent .Addresses $max}}%"></div></td><td class="num">{{.Addresses}}</td></tr>
+        {{- end}}
+    </table>
+    <table>
+        <tr><th>ASN</th><th>Description</th><th>Netblock</th><th class="num">Addresses</th></tr>
+        {{- range .ASNs}}
+        {{- $asn := .}}
+        {{- range $i, $nb := .Netblocks}}
+        <tr>{{if eq $i 0}}<td>{{$asn.ASN}}</td><td>{{$asn.Description}}</td>{{else}}<td></td><td></td>{{end}}<td>{{$nb.Name}}</td><td class="num">{{$nb.Count}}</td></tr>
+        {{- end}}
+        {{- end}}
+    </table>
+    {{- end}}
+
+    <h2>Data Sources</h2>
+    {{- if not .Sources}}
+    <p>The data sources of the names were not available.</p>
+    {{- else}}
+    <h3>Names by Data Source</h3>
+    <table class="chart">
+        {{- $max := (index .Sources 0).Names}}
+        {{- range topSources .Sources}}
+        <tr><td class="label">{{.Source}}</td><td><div class="bar" style="width: {{percent .Names $max}}%"></div></td><td class="num">{{.Names}}</td></tr>
+        {{- end}}
+    </table>
+    <table>
+        <tr><th>Source</th><th class="num">Names</th><th class="num">Only Found by the Source</th></tr>
+        {{- range .Sources}}
+        <tr><td>{{.Source}}</td><td class="num">{{.Names}}</td><td class="num">{{.Unique}}</td></tr>
+        {{- end}}
+    </table>
+    {{- end}}
+
+    <h2>Discovered Names</h2>
+    <table>
+        <tr><th>Name</th><th>Addresses</th><th>Technique</th><th>Sources</th></tr>
+        {{- range .Assets}}
+        <tr><td>{{.Name}}</td><td>{{addresses .Addresses}}</td><td>{{.Tag}}</td><td>{{join .Sources ", "}}</td></tr>
+        {{- end}}
+    </table>
+</body>
+</html>
+`
@@ -0,0 +1,79 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func testEngagementOutput(name, tag, cidr string, asn int, addr string, srcs ...string) *requests.Output {
+	_, ipnet, _ := net.ParseCIDR(cidr)
+
+	return &requests.Output{
+		Name:    name,
+		Tag:     tag,
+		Sources: srcs,
+		Addresses: []requests.AddressInfo{{
+			Address:     net.ParseIP(addr),
+			Netblock:    ipnet,
+			CIDRStr:     cidr,
+			ASN:         asn,
+			Description: "EXAMPLE-NET",
+		}},
+	}
+}
+
+func TestNewEngagementReport(t *testing.T) {
+	previous := []*requests.Output{
+		testEngagementOutput("www.example.com", "cert", "192.0.2.0/24", 64500, "192.0.2.1", "Crtsh"),
+		testEngagementOutput("old.example.com", "api", "192.0.2.0/24", 64500, "192.0.2.9", "Crtsh"),
+	}
+	current := []*requests.Output{
+		testEngagementOutput("www.example.com", "cert", "192.0.2.0/24", 64500, "192.0.2.1", "Crtsh", "DNS"),
+		testEngagementOutput("api.example.com", "cert", "192.0.2.0/24", 64500, "192.0.2.2", "Crtsh"),
+		testEngagementOutput("<b>x</b>.example.com", "dns", "198.51.100.0/24", 64501, "198.51.100.1", "DNS"),
+	}
+
+	r := NewEngagementReport([]string{"example.com"}, current, previous, nil)
+	if len(r.Assets) != 3 || r.Addresses != 3 || r.Netblocks != 2 {
+		t.Errorf("Unexpected totals: %d names, %d addresses and %d netblocks", len(r.Assets), r.Addresses, r.Netblocks)
+	}
+	if len(r.Tags) != 2 || r.Tags[0].Name != "cert" || r.Tags[0].Count != 2 {
+		t.Errorf("Unexpected discovery techniques: %v", r.Tags)
+	}
+	if len(r.ASNs) != 2 || r.ASNs[0].ASN != 64500 || r.ASNs[0].Addresses != 2 {
+		t.Errorf("Unexpected autonomous systems: %v", r.ASNs)
+	}
+	if len(r.Sources) != 2 || r.Sources[0].Source != "Crtsh" || r.Sources[0].Names != 2 || r.Sources[0].Unique != 1 {
+		t.Errorf("Unexpected data sources: %v", r.Sources)
+	}
+	if r.Changes == nil || len(r.Changes.Found) != 2 || len(r.Changes.Removed) != 1 {
+		t.Errorf("Unexpected changes since the previous enumeration: %v", r.Changes)
+	}
+
+	r.Title = "Example Engagement"
+	var buf bytes.Buffer
+	if err := r.WriteHTML(&buf); err != nil {
+		t.Fatalf("Failed to write the report: %v", err)
+	}
+
+	page := buf.String()
+	for _, want := range []string{"<title>Example Engagement</title>", "old.example.com", "AS64500", "198.51.100.0/24"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("The report is missing %q", want)
+		}
+	}
+	if strings.Contains(page, "<b>x</b>") {
+		t.Error("The report did not escape the discovered names")
+	}
+
+	if r := NewEngagementReport([]string{"example.com"}, current, nil, nil); r.Changes != nil {
+		t.Error("The report compared the findings without a previous enumeration")
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+)
+
+// The name of the file within the output directory that stores when the previous report was sent
+const reportStateFileName = "email_report.json"
+
+// Move describes a DNS name that resolved to different addresses than before.
+type Move struct {
+	Name string
+	From []requests.AddressInfo
+	To   []requests.AddressInfo
+}
+
+// Report summarizes the changes discovered since the previous report and the health of the data sources.
+type Report struct {
+	Domains []string
+	Since   time.Time
+	Until   time.Time
+	Found   []*requests.Output
+	Moved   []*Move
+	Removed []*requests.Output
+	Sources []systems.SourceStats
+	// The circuit breakers that opened during the enumeration
+	Breakers []systems.BreakerStatus
+}
+
+// NewReport returns a Report with the differences between the older and newer findings.
+// The assets on the ignore list, which can be nil, are left out of the report.
+func NewReport(domains []string, older, newer []*requests.Output, il *format.IgnoreList) *Report {
+	r := &Report{Domains: domains}
+	oldmap := make(map[string]*requests.Output)
+	newmap := make(map[string]*requests.Output)
+
+	for _, o := range older {
+		if !il.Ignored(o) {
+			oldmap[o.Name] = o
+		}
+	}
+	for _, o := range newer {
+		if !il.Ignored(o) {
+			newmap[o.Name] = o
+		}
+	}
+
+	for name, o := range newmap {
+		prev, found := oldmap[name]
+		if !found {
+			r.Found = append(r.Found, o)
+		} else if !sameAddresses(prev.Addresses, o.Addresses) {
+			r.Moved = append(r.Moved, &Move{
+				Name: name,
+				From: prev.Addresses,
+				To:   o.Addresses,
+			})
+		}
+	}
+	for name, o := range oldmap {
+		if _, found := newmap[name]; !found {
+			r.Removed = append(r.Removed, o)
+		}
+	}
+
+	sort.Slice(r.Found, func(i, j int) bool { return r.Found[i].Name < r.Found[j].Name })
+	sort.Slice(r.Moved, func(i, j int) bool { return r.Moved[i].Name < r.Moved[j].Name })
+	sort.Slice(r.Removed, func(i, j int) bool { return r.Removed[i].Name < r.Removed[j].Name })
+	return r
+}
+
+// Empty returns true when the report does not describe any changes.
+func (r *Report) Empty() bool {
+	return len(r.Found) == 0 && len(r.Moved) == 0 && len(r.Removed) == 0
+}
+
+// Write renders the report as plain text.
+func (r *Report) Write(w io.Writer) error {
+	fmt.Fprintf(w, "Domains: %s\n", strings.Join(r.Domains, ", "))
+	if !r.Since.IsZero() {
+		fmt.Fprintf(w, "Period: %s -> %s\n", r.Since.Format(time.RFC1123), r.Until.Format(time.RFC1123))
+	}
+	fmt.Fprintf(w, "\n%d found, %d moved, %d removed\n", len(r.Found), len(r.Moved), len(r.Removed))
+
+	if len(r.Found) > 0 {
+		fmt.Fprintln(w, "\nNew assets")
+		for _, o := range r.Found {
+			fmt.Fprintf(w, "  %s %s\n", o.Name, addressLine(o.Addresses))
+		}
+	}
+	if len(r.Moved) > 0 {
+		fmt.Fprintln(w, "\nChanged addresses")
+		for _, m := range r.Moved {
+			fmt.Fprintf(w, "  %s %s -> %s\n", m.Name, addressLine(m.From), addressLine(m.To))
+		}
+	}
+	if len(r.Removed) > 0 {
+		fmt.Fprintln(w, "\nRemoved assets")
+		for _, o := range r.Removed {
+			fmt.Fprintf(w, "  %s %s\n", o.Name, addressLine(o.Addresses))
+		}
+	}
+
+	if len(r.Sources) > 0 {
+		fmt.Fprintln(w, "\nData source health")
+
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "  Source\tRequests\tErrors\tNames\tUnique\tElapsed")
+		for _, s := range r.Sources {
+			fmt.Fprintf(tw, "  %s\t%d\t%d\t%d\t%d\t%s\n", s.Source, s.Requests,
+				s.Errors, s.Names, s.Unique, s.Elapsed.Round(time.Millisecond))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Breakers) > 0 {
+		fmt.Fprintln(w, "\nCircuit breakers")
+		for _, b := range r.Breakers {
+			fmt.Fprintf(w, "  %s\n", b)
+		}
+	}
+	return nil
+}
+
+// String returns the report rendered as plain text.
+func (r *Report) String() string {
+	var b strings.Builder
+
+	r.Write(&b)
+	return b.String()
+}
+
+type reportState struct {
+	LastSent time.Time `json:"last_sent"`
+}
+
+// LastReport returns when the previous report was sent from the output directory,
+// or the zero time when a report has never been sent.
+func LastReport(dir string) time.Time {
+	data, err := ioutil.ReadFile(filepath.Join(dir, reportStateFileName))
+	if err != nil {
+		return time.Time{}
+	}
+
+	var state reportState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return time.Time{}
+	}
+	return state.LastSent
+}
+
+// SaveLastReport records in the output directory that a report was sent at the time provided.
+func SaveLastReport(dir string, sent time.Time) error {
+	data, err := json.Marshal(&reportState{LastSent: sent})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, reportStateFileName), data, 0644)
+}
+
+// ReportDue returns true when the interval has elapsed since the previous report.
+func ReportDue(last time.Time, interval time.Duration, now time.Time) bool {
+	return last.IsZero() || !now.Before(last.Add(interval))
+}
+
+func addressLine(addrs []requests.AddressInfo) string {
+	var list []string
+
+	for _, addr := range addrs {
+		if addr.Address != nil {
+			list = append(list, addr.Address.String())
+		}
+	}
+	return strings.Join(list, ",")
+}
+
+func sameAddresses(a, b []requests.AddressInfo) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, a1 := range a {
+		var found bool
+
+		for _, a2 := range b {
+			if a1.Address.Equal(a2.Address) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
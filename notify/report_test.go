@@ -0,0 +1,107 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+)
+
+func testOutput(name string, addrs ...string) *requests.Output {
+	out := &requests.Output{Name: name}
+
+	for _, addr := range addrs {
+		out.Addresses = append(out.Addresses, requests.AddressInfo{Address: net.ParseIP(addr)})
+	}
+	return out
+}
+
+func TestNewReport(t *testing.T) {
+	older := []*requests.Output{
+		testOutput("www.example.com", "192.0.2.1"),
+		testOutput("mail.example.com", "192.0.2.2"),
+		testOutput("old.example.com", "192.0.2.3"),
+		testOutput("cdn.example.com", "198.51.100.1"),
+	}
+	newer := []*requests.Output{
+		testOutput("www.example.com", "192.0.2.1"),
+		testOutput("mail.example.com", "192.0.2.20"),
+		testOutput("vpn.example.com", "192.0.2.4"),
+		testOutput("cdn.example.com", "198.51.100.2"),
+	}
+
+	il, err := format.NewIgnoreList("cdn.example.com")
+	if err != nil {
+		t.Fatalf("Failed to create the ignore list: %v", err)
+	}
+	r := NewReport([]string{"example.com"}, older, newer, il)
+
+	if len(r.Found) != 1 || r.Found[0].Name != "vpn.example.com" {
+		t.Errorf("Unexpected new assets: %v", r.Found)
+	}
+	if len(r.Moved) != 1 || r.Moved[0].Name != "mail.example.com" {
+		t.Errorf("Unexpected changed assets: %v", r.Moved)
+	}
+	if len(r.Removed) != 1 || r.Removed[0].Name != "old.example.com" {
+		t.Errorf("Unexpected removed assets: %v", r.Removed)
+	}
+
+	r.Sources = []systems.SourceStats{{Source: "Crtsh", Requests: 2, Names: 10}}
+	r.Breakers = []systems.BreakerStatus{{Source: "NetworksDB", Trips: 2, LastError: "403"}}
+	text := r.String()
+	for _, expected := range []string{
+		"1 found, 1 moved, 1 removed",
+		"vpn.example.com 192.0.2.4",
+		"mail.example.com 192.0.2.2 -> 192.0.2.20",
+		"Crtsh",
+		"NetworksDB: CLOSED (opened 2 times, last error 403)",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("The report did not include %q:\n%s", expected, text)
+		}
+	}
+
+	if !NewReport(nil, older, older, nil).Empty() {
+		t.Errorf("The report of identical findings was not empty")
+	}
+}
+
+func TestReportSchedule(t *testing.T) {
+	dir, err := ioutil.TempDir("", "amass-report")
+	if err != nil {
+		t.Fatalf("Failed to create the temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if last := LastReport(dir); !last.IsZero() {
+		t.Errorf("LastReport returned %v before a report was sent", last)
+	}
+
+	sent := time.Now().Truncate(time.Second)
+	if err := SaveLastReport(dir, sent); err != nil {
+		t.Fatalf("Failed to save the time of the report: %v", err)
+	}
+	last := LastReport(dir)
+	if !last.Equal(sent) {
+		t.Errorf("LastReport returned %v instead of %v", last, sent)
+	}
+
+	if ReportDue(last, 24*time.Hour, sent.Add(time.Hour)) {
+		t.Errorf("The report was due before the interval elapsed")
+	}
+	if !ReportDue(last, 24*time.Hour, sent.Add(25*time.Hour)) {
+		t.Errorf("The report was not due after the interval elapsed")
+	}
+	if !ReportDue(time.Time{}, 24*time.Hour, sent) {
+		t.Errorf("The first report was not due")
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	amasshttp "github.com/OWASP/Amass/v3/net/http"
+)
+
+// The amount of time allowed for delivering a notification to one URL, including the retries
+const webhookTimeout = 2 * time.Minute
+
+// The longest wait between two attempts to deliver a notification
+const maxWebhookBackoff = 30 * time.Second
+
+// PostWebhooks sends the JSON encoding of the event to each URL of the webhook configuration.
+// Failed notifications are sent again after transient failures and rate limiting, and the URLs
+// that could not be notified are reported by the returned error.
+func PostWebhooks(ctx context.Context, cfg *config.WebhookConfig, event interface{}) error {
+	if cfg == nil {
+		return fmt.Errorf("PostWebhooks: No webhook configuration was provided")
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("PostWebhooks: Failed to encode the event: %v", err)
+	}
+
+	hvals := map[string]string{"Content-Type": "application/json"}
+	for k, v := range cfg.Headers {
+		hvals[k] = v
+	}
+	policy := &amasshttp.RetryPolicy{
+		Retries:     cfg.Retries,
+		Backoff:     cfg.Backoff,
+		MaxBackoff:  maxWebhookBackoff,
+		Jitter:      0.2,
+		StatusCodes: append([]int{http.StatusRequestTimeout}, amasshttp.DefaultRetryStatusCodes...),
+	}
+
+	var failed []string
+	for _, u := range cfg.URLs {
+		if err := postWebhook(ctx, policy, u, data, hvals); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", u, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("PostWebhooks: Failed to notify %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func postWebhook(ctx context.Context, policy *amasshttp.RetryPolicy, u string, data []byte, hvals map[string]string) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	for attempt := 0; ; attempt++ {
+		_, err := amasshttp.RequestWebPageWithContext(ctx, amasshttp.DefaultClient, u, bytes.NewReader(data), hvals, "", "")
+		if err == nil || attempt >= policy.Retries {
+			return err
+		}
+
+		wait := policy.Wait(attempt)
+		// Unlike the data sources, a rate limited URL is notified again, since the change would be lost
+		if rle, ok := amasshttp.IsRateLimited(err); ok {
+			if rle.RetryAfter > 0 {
+				wait = rle.RetryAfter
+			}
+		} else if !policy.Retryable(ctx, err) {
+			return err
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
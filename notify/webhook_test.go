@@ -0,0 +1,104 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+)
+
+func TestPostWebhooks(t *testing.T) {
+	var lock sync.Mutex
+	var attempts int
+	var received []map[string]string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		attempts++
+		// The first attempt fails for a transient reason, and the second is rate limited
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if attempts == 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if r.Method != "POST" || r.Header.Get("Content-Type") != "application/json" || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Unexpected request: %s %v", r.Method, r.Header)
+		}
+
+		var ev map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			t.Errorf("Failed to decode the event: %v", err)
+		}
+		received = append(received, ev)
+	}))
+	defer ts.Close()
+
+	cfg := &config.WebhookConfig{
+		URLs:    []string{ts.URL + "/amass"},
+		Retries: 2,
+		Backoff: 10 * time.Millisecond,
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+	}
+	event := map[string]string{"event": "asset-found", "name": "www.example.com"}
+	if err := PostWebhooks(context.Background(), cfg, event); err != nil {
+		t.Fatalf("Failed to deliver the notification: %v", err)
+	}
+	if attempts != 3 || len(received) != 1 || received[0]["name"] != "www.example.com" {
+		t.Errorf("The server received %d attempts and the events %v", attempts, received)
+	}
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+
+	cfg.URLs = []string{missing.URL, ts.URL}
+	if err := PostWebhooks(context.Background(), cfg, event); err == nil {
+		t.Errorf("Failed to report the URL that could not be notified")
+	}
+	if len(received) != 2 {
+		t.Errorf("The remaining URLs were not notified after a failure")
+	}
+}
+
+func TestPostWebhooksRetryAfter(t *testing.T) {
+	var lock sync.Mutex
+	var attempts int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer ts.Close()
+
+	cfg := &config.WebhookConfig{
+		URLs:    []string{ts.URL},
+		Retries: 1,
+		Backoff: 10 * time.Millisecond,
+	}
+
+	start := time.Now()
+	if err := PostWebhooks(context.Background(), cfg, map[string]string{"event": "asset-removed"}); err != nil {
+		t.Fatalf("Failed to deliver the notification after the rate limit: %v", err)
+	}
+	if elapsed := time.Since(start); attempts != 2 || elapsed < time.Second {
+		t.Errorf("The notification was sent %d times within %v of the rate limit", attempts, elapsed)
+	}
+}
@@ -14,18 +14,20 @@ import (
 
 // Request tag types.
 const (
-	NONE     = "none"
-	ALT      = "alt"
-	GUESS    = "guess"
-	ARCHIVE  = "archive"
-	API      = "api"
-	AXFR     = "axfr"
-	BRUTE    = "brute"
-	CERT     = "cert"
-	DNS      = "dns"
-	RIR      = "rir"
-	EXTERNAL = "ext"
-	SCRAPE   = "scrape"
+	NONE      = "none"
+	ALT       = "alt"
+	GUESS     = "guess"
+	ARCHIVE   = "archive"
+	API       = "api"
+	AXFR      = "axfr"
+	BRUTE     = "brute"
+	CERT      = "cert"
+	DNS       = "dns"
+	RIR       = "rir"
+	EXTERNAL  = "ext"
+	SCRAPE    = "scrape"
+	LOOKALIKE = "lookalike"
+	VHOST     = "vhost"
 )
 
 // ContextKey is the type used for context value keys.
@@ -39,21 +41,25 @@ const (
 
 // Request Pub/Sub topics used across Amass.
 const (
-	NameRequestTopic   = "amass:namereq"
-	NewNameTopic       = "amass:newname"
-	AddrRequestTopic   = "amass:addrreq"
-	NewAddrTopic       = "amass:newaddr"
-	SubDiscoveredTopic = "amass:newsub"
-	ResolveNameTopic   = "amass:resolve"
-	NameResolvedTopic  = "amass:resolved"
-	ASNRequestTopic    = "amass:asnreq"
-	NewASNTopic        = "amass:newasn"
-	WhoisRequestTopic  = "amass:whoisreq"
-	NewWhoisTopic      = "amass:whoisinfo"
-	LogTopic           = "amass:log"
-	OutputTopic        = "amass:output"
-	SetActiveTopic     = "amass:setactive"
-	ResolveCompleted   = "amass:resolvecomp"
+	NameRequestTopic     = "amass:namereq"
+	NewNameTopic         = "amass:newname"
+	AddrRequestTopic     = "amass:addrreq"
+	NewAddrTopic         = "amass:newaddr"
+	SubDiscoveredTopic   = "amass:newsub"
+	ResolveNameTopic     = "amass:resolve"
+	NameResolvedTopic    = "amass:resolved"
+	ASNRequestTopic      = "amass:asnreq"
+	NewASNTopic          = "amass:newasn"
+	WhoisRequestTopic    = "amass:whoisreq"
+	NewWhoisTopic        = "amass:whoisinfo"
+	NewEmailTopic        = "amass:newemail"
+	NewReputationTopic   = "amass:reputation"
+	LogTopic             = "amass:log"
+	OutputTopic          = "amass:output"
+	SetActiveTopic       = "amass:setactive"
+	ResolveCompleted     = "amass:resolvecomp"
+	MigrateProgressTopic = "amass:migrate"
+	EnumProgressTopic    = "amass:progress"
 )
 
 // DNSAnswer is the type used by Amass to represent a DNS record.
@@ -105,6 +111,42 @@ type WhoisRequest struct {
 	Source     string
 }
 
+// EmailRequest handles data needed throughout Service processing of an email address.
+type EmailRequest struct {
+	Email  string
+	Domain string
+	Tag    string
+	Source string
+}
+
+// The classifications assigned to IP addresses by the reputation providers.
+const (
+	ReputationMalicious  = "malicious"
+	ReputationSuspicious = "suspicious"
+	ReputationBenign     = "benign"
+	ReputationUnknown    = "unknown"
+)
+
+// ReputationRequest handles the classification of an IP address by a reputation provider.
+type ReputationRequest struct {
+	Address        string
+	Classification string
+	// Score is the confidence of the provider that the address is malicious, from 0 to 100 when reported
+	Score      int
+	Categories []string
+	Tag        string
+	Source     string
+}
+
+// ReputationInfo contains the classification of an IP address by a reputation provider.
+type ReputationInfo struct {
+	Source         string    `json:"source"`
+	Classification string    `json:"classification"`
+	Score          int       `json:"score"`
+	Categories     []string  `json:"categories,omitempty"`
+	Checked        time.Time `json:"checked"`
+}
+
 // Output contains all the output data for an enumerated DNS name.
 type Output struct {
 	Name      string        `json:"name"`
@@ -112,6 +154,28 @@ type Output struct {
 	Addresses []AddressInfo `json:"addresses"`
 	Tag       string        `json:"tag"`
 	Sources   []string      `json:"sources"`
+	// Set when the name was not discovered by any previous enumeration
+	New bool `json:"new"`
+	// The organizational context provided by the user for the name or its addresses
+	Context *AssetContext `json:"context,omitempty"`
+	// The registration details when the name resembles a domain in scope
+	Lookalike *LookalikeInfo `json:"lookalike,omitempty"`
+	// Set when ownership of the root domain was verified with a token
+	Verified bool `json:"verified"`
+}
+
+// AssetContext contains the organizational information provided by the user for an asset.
+type AssetContext struct {
+	Owner       string `json:"owner,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	Criticality string `json:"criticality,omitempty"`
+}
+
+// LookalikeInfo contains the registration details of a domain name resembling a domain in scope.
+type LookalikeInfo struct {
+	Of        string    `json:"of"`
+	Registrar string    `json:"registrar,omitempty"`
+	Created   time.Time `json:"created,omitempty"`
 }
 
 // AddressInfo stores all network addressing info for the Output type.
@@ -121,6 +185,8 @@ type AddressInfo struct {
 	CIDRStr     string     `json:"cidr"`
 	ASN         int        `json:"asn"`
 	Description string     `json:"desc"`
+	// The classifications of the address by the IP reputation providers
+	Reputation []*ReputationInfo `json:"reputation,omitempty"`
 }
 
 // TrustedTag returns true when the tag parameter is of a type that should be trusted even
@@ -23,6 +23,7 @@ func TestTrustedTag(t *testing.T) {
 		{DNS, true},
 		{EXTERNAL, false},
 		{SCRAPE, false},
+		{LOOKALIKE, false},
 	}
 
 	for _, test := range tests {
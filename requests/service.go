@@ -100,6 +100,7 @@ type BaseService struct {
 	setRateChan   chan time.Duration
 	checkRateChan chan chan struct{}
 	clearRateChan chan struct{}
+	backoffChan   chan time.Duration
 
 	// The specific service embedding BaseAmassService
 	service Service
@@ -114,6 +115,7 @@ func NewBaseService(srv Service, name string) *BaseService {
 		setRateChan:   make(chan time.Duration, 10),
 		checkRateChan: make(chan chan struct{}, 10),
 		clearRateChan: make(chan struct{}, 10),
+		backoffChan:   make(chan time.Duration, 10),
 		service:       srv,
 	}
 }
@@ -242,8 +244,11 @@ func (bas *BaseService) SetRateLimit(min time.Duration) {
 func (bas *BaseService) CheckRateLimit() {
 	ch := make(chan struct{}, 2)
 
-	bas.checkRateChan <- ch
-	<-ch
+	select {
+	case bas.checkRateChan <- ch:
+		<-ch
+	case <-bas.quit:
+	}
 }
 
 // ClearLast resets the last value so the service can be utilized immediately.
@@ -251,26 +256,51 @@ func (bas *BaseService) ClearLast() {
 	bas.clearRateChan <- struct{}{}
 }
 
+// Backoff informs the service that the data source rejected a request due to rate limiting or
+// an exhausted quota. The following checks will block for the duration provided by the data source,
+// or an interval that doubles with each consecutive rejection when the duration is zero.
+func (bas *BaseService) Backoff(d time.Duration) {
+	select {
+	case bas.backoffChan <- d:
+	default:
+		// Enough rejections are already waiting to lengthen the penalty
+	}
+}
+
 func (bas *BaseService) manageRateLimit() {
-	var rateLimit time.Duration
+	var rateLimit, penalty time.Duration
+	var until time.Time
 	last := time.Now().Truncate(10 * time.Minute)
-loop:
 	for {
 		select {
 		case <-bas.quit:
 			return
 		case d := <-bas.setRateChan:
 			rateLimit = d
+		case d := <-bas.backoffChan:
+			penalty = nextBackoff(penalty, rateLimit, d)
+			until = time.Now().Add(penalty)
 		case ch := <-bas.checkRateChan:
-			if rateLimit == time.Duration(0) {
-				ch <- struct{}{}
-				continue loop
+			deadline := last.Add(rateLimit)
+			if until.After(time.Now()) {
+				if until.After(deadline) {
+					deadline = until
+				}
+			} else if penalty > 0 {
+				// The data source has been accepting requests again, so the penalty decays
+				if penalty /= 2; penalty < time.Second {
+					penalty = 0
+				}
 			}
 
-			if delta := time.Since(last); rateLimit > delta {
-				time.Sleep(rateLimit - delta)
+			// Release the caller when the service is stopped during the wait
+			if !bas.waitUntil(deadline) {
+				ch <- struct{}{}
+				return
+			}
+			if rateLimit > 0 {
+				last = time.Now()
 			}
-			last = time.Now()
 			ch <- struct{}{}
 		case <-bas.clearRateChan:
 			last.Truncate(time.Hour)
@@ -278,6 +308,45 @@ loop:
 	}
 }
 
+// waitUntil blocks until the deadline and returns false when the service is stopped before then.
+func (bas *BaseService) waitUntil(deadline time.Time) bool {
+	wait := time.Until(deadline)
+	if wait <= 0 {
+		return true
+	}
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+
+	select {
+	case <-bas.quit:
+		return false
+	case <-t.C:
+	}
+	return true
+}
+
+// The longest that a service will wait after being rejected by a data source
+const maxBackoff = 10 * time.Minute
+
+func nextBackoff(penalty, rateLimit, requested time.Duration) time.Duration {
+	if requested > 0 {
+		penalty = requested
+	} else if penalty == 0 {
+		penalty = 2 * rateLimit
+		if penalty < 5*time.Second {
+			penalty = 5 * time.Second
+		}
+	} else {
+		penalty *= 2
+	}
+
+	if penalty > maxBackoff {
+		penalty = maxBackoff
+	}
+	return penalty
+}
+
 type queuedCall struct {
 	Func reflect.Value
 	Args []reflect.Value
@@ -0,0 +1,84 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package requests
+
+import (
+	"testing"
+	"time"
+)
+
+type testService struct {
+	BaseService
+}
+
+func TestNextBackoff(t *testing.T) {
+	tests := []struct {
+		Penalty   time.Duration
+		RateLimit time.Duration
+		Requested time.Duration
+		Expected  time.Duration
+	}{
+		{0, time.Second, 0, 5 * time.Second},
+		{0, 10 * time.Second, 0, 20 * time.Second},
+		{5 * time.Second, time.Second, 0, 10 * time.Second},
+		{5 * time.Second, time.Second, 30 * time.Second, 30 * time.Second},
+		{8 * time.Minute, time.Second, 0, maxBackoff},
+		{0, time.Second, time.Hour, maxBackoff},
+	}
+
+	for _, test := range tests {
+		if d := nextBackoff(test.Penalty, test.RateLimit, test.Requested); d != test.Expected {
+			t.Errorf("nextBackoff(%v, %v, %v) returned %v instead of %v",
+				test.Penalty, test.RateLimit, test.Requested, d, test.Expected)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	srv := new(testService)
+	srv.BaseService = *NewBaseService(srv, "Test")
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Failed to start the service: %v", err)
+	}
+	defer srv.Stop()
+
+	wait := 200 * time.Millisecond
+	srv.Backoff(wait)
+	// Allow the backoff to be received before the check
+	time.Sleep(20 * time.Millisecond)
+
+	start := time.Now()
+	srv.CheckRateLimit()
+	if elapsed := time.Since(start); elapsed < wait/2 {
+		t.Errorf("CheckRateLimit returned after %v instead of waiting for the backoff", elapsed)
+	}
+}
+
+func TestBackoffStop(t *testing.T) {
+	srv := new(testService)
+	srv.BaseService = *NewBaseService(srv, "Test")
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Failed to start the service: %v", err)
+	}
+
+	// The backoff requests must not block once the channel is full
+	for i := 0; i < 20; i++ {
+		srv.Backoff(time.Hour)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		srv.CheckRateLimit()
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	srv.Stop()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Errorf("CheckRateLimit did not return after the service was stopped")
+	}
+}
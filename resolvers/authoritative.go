@@ -0,0 +1,217 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/miekg/dns"
+)
+
+// AuthoritativeResolvers sends the queries for names within the known zones directly to the
+// servers authoritative for each zone, while limiting the rate of queries received by each server.
+// Names outside the known zones, and queries the servers fail to answer, are resolved by the pool.
+type AuthoritativeResolvers struct {
+	sync.Mutex
+	pool     Resolver
+	interval time.Duration
+	zones    map[string][]*authoritativeServer
+	servers  map[string]*authoritativeServer
+	stopped  bool
+}
+
+// NewAuthoritativeResolvers returns AuthoritativeResolvers that send at most rate queries per
+// second to each server, and fall back to the provided pool.
+func NewAuthoritativeResolvers(pool Resolver, rate int) *AuthoritativeResolvers {
+	if rate <= 0 {
+		rate = 1
+	}
+
+	return &AuthoritativeResolvers{
+		pool:     pool,
+		interval: time.Second / time.Duration(rate),
+		zones:    make(map[string][]*authoritativeServer),
+		servers:  make(map[string]*authoritativeServer),
+	}
+}
+
+type authoritativeServer struct {
+	sync.Mutex
+	addr     string
+	resolver Resolver
+	interval time.Duration
+	next     time.Time
+}
+
+// wait blocks until the server can receive another query without exceeding the rate limit.
+func (s *authoritativeServer) wait(ctx context.Context) error {
+	s.Lock()
+	now := time.Now()
+	slot := s.next
+	if slot.Before(now) {
+		slot = now
+	}
+	s.next = slot.Add(s.interval)
+	s.Unlock()
+
+	d := time.Until(slot)
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+	}
+	return nil
+}
+
+func zoneKey(name string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(name), "."))
+}
+
+// AddZoneServer records the address of a server authoritative for the zone. The server is
+// shared by all the zones it is authoritative for, so the rate limit applies across them.
+func (a *AuthoritativeResolvers) AddZoneServer(zone, addr string) error {
+	zone = zoneKey(zone)
+	addr = strings.TrimSpace(addr)
+	if zone == "" || addr == "" {
+		return fmt.Errorf("AuthoritativeResolvers: Invalid zone or server address provided")
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	if a.stopped {
+		return fmt.Errorf("AuthoritativeResolvers: The resolvers have been stopped")
+	}
+
+	srv, found := a.servers[addr]
+	if !found {
+		r := NewBaseResolver(addr)
+		if r == nil {
+			return fmt.Errorf("AuthoritativeResolvers: Failed to setup the resolver for %s", addr)
+		}
+
+		srv = &authoritativeServer{
+			addr:     addr,
+			resolver: r,
+			interval: a.interval,
+		}
+		a.servers[addr] = srv
+	}
+
+	for _, s := range a.zones[zone] {
+		if s == srv {
+			return nil
+		}
+	}
+	a.zones[zone] = append(a.zones[zone], srv)
+	return nil
+}
+
+// ZoneServers returns the addresses of the servers known to be authoritative for each zone.
+func (a *AuthoritativeResolvers) ZoneServers() map[string][]string {
+	a.Lock()
+	defer a.Unlock()
+
+	zones := make(map[string][]string, len(a.zones))
+	for zone, servers := range a.zones {
+		var addrs []string
+
+		for _, s := range servers {
+			addrs = append(addrs, s.addr)
+		}
+		sort.Strings(addrs)
+		zones[zone] = addrs
+	}
+	return zones
+}
+
+// serversFor returns the servers of the most specific known zone that contains the name.
+func (a *AuthoritativeResolvers) serversFor(name string) []*authoritativeServer {
+	labels := strings.Split(zoneKey(name), ".")
+
+	a.Lock()
+	defer a.Unlock()
+
+	for i := range labels {
+		if servers, found := a.zones[strings.Join(labels[i:], ".")]; found {
+			return append([]*authoritativeServer(nil), servers...)
+		}
+	}
+	return nil
+}
+
+// Resolve performs the DNS query using the servers authoritative for the zone containing the name.
+// Missing address records are requested again from the pool, since the name can be an alias
+// of a name in another zone that the authoritative servers will not resolve.
+func (a *AuthoritativeResolvers) Resolve(ctx context.Context, name, qtype string, priority int) ([]requests.DNSAnswer, bool, error) {
+	servers := a.serversFor(name)
+	if len(servers) == 0 {
+		return a.pool.Resolve(ctx, name, qtype, priority)
+	}
+
+	start := rand.Intn(len(servers))
+	for i := range servers {
+		srv := servers[(start+i)%len(servers)]
+
+		if err := srv.wait(ctx); err != nil {
+			return nil, false, &ResolveError{
+				Err:   fmt.Sprintf("DNS query for %s type %s was cancelled: %v", name, qtype, err),
+				Rcode: TimeoutRcode,
+			}
+		}
+
+		ans, again, err := srv.resolver.Resolve(ctx, name, qtype, priority)
+		if err == nil {
+			return ans, false, nil
+		}
+
+		rerr, ok := err.(*ResolveError)
+		if again || !ok {
+			continue
+		}
+		if rerr.Rcode == dns.RcodeNameError {
+			return ans, false, err
+		}
+		if rerr.Rcode == dns.RcodeSuccess {
+			if qtype == "A" || qtype == "AAAA" {
+				break
+			}
+			return ans, false, err
+		}
+	}
+
+	return a.pool.Resolve(ctx, name, qtype, priority)
+}
+
+// Stop releases the resolvers used to reach the authoritative servers. The pool is not stopped.
+func (a *AuthoritativeResolvers) Stop() error {
+	a.Lock()
+	defer a.Unlock()
+
+	if a.stopped {
+		return nil
+	}
+	a.stopped = true
+
+	for _, srv := range a.servers {
+		srv.resolver.Stop()
+	}
+	a.zones = make(map[string][]*authoritativeServer)
+	a.servers = make(map[string]*authoritativeServer)
+	return nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package resolvers
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func startAuthoritativeServer(t *testing.T, queries *int64) (string, func()) {
+	return startProbeServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		atomic.AddInt64(queries, 1)
+
+		m := new(dns.Msg)
+		m.SetReply(req)
+		m.Authoritative = true
+
+		q := req.Question[0]
+		switch {
+		case q.Name == "www.example.com." && q.Qtype == dns.TypeA:
+			m.Answer = append(m.Answer, &dns.A{
+				Hdr: dns.RR_Header{Name: q.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+				A:   net.ParseIP("192.0.2.1"),
+			})
+		case q.Name == "cdn.example.com.":
+			m.Answer = append(m.Answer, &dns.CNAME{
+				Hdr:    dns.RR_Header{Name: q.Name, Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: 60},
+				Target: "edge.example.net.",
+			})
+		default:
+			m.Rcode = dns.RcodeNameError
+		}
+		w.WriteMsg(m)
+	})
+}
+
+func TestAuthoritativeResolvers(t *testing.T) {
+	var queries int64
+	addr, stop := startAuthoritativeServer(t, &queries)
+	defer stop()
+
+	pool := NewMockResolver()
+	pool.AddAnswer("www.example.com", "A", "198.51.100.1")
+	pool.AddAnswer("cdn.example.com", "A", "203.0.113.1")
+	pool.AddAnswer("www.owasp.org", "A", "192.0.2.100")
+
+	auth := NewAuthoritativeResolvers(pool, 100)
+	defer auth.Stop()
+
+	if err := auth.AddZoneServer("", addr); err == nil {
+		t.Errorf("A server was accepted without a zone")
+	}
+	if err := auth.AddZoneServer("Example.com.", addr); err != nil {
+		t.Fatalf("Failed to add the server for the zone: %v", err)
+	}
+	auth.AddZoneServer("example.com", addr)
+
+	if zones := auth.ZoneServers(); len(zones) != 1 || len(zones["example.com"]) != 1 || zones["example.com"][0] != addr {
+		t.Errorf("The zone servers were returned as %v", zones)
+	}
+
+	ctx := context.Background()
+	ans, _, err := auth.Resolve(ctx, "www.example.com", "A", PriorityLow)
+	if err != nil || len(ans) != 1 || ans[0].Data != "192.0.2.1" {
+		t.Errorf("The authoritative server did not answer the query: %v, %v", ans, err)
+	}
+
+	// The authoritative answer that the name does not exist is not sent to the pool
+	if _, _, err := auth.Resolve(ctx, "missing.example.com", "A", PriorityLow); err == nil {
+		t.Errorf("The name missing from the zone was resolved")
+	} else if rerr, ok := err.(*ResolveError); !ok || rerr.Rcode != dns.RcodeNameError {
+		t.Errorf("The name missing from the zone returned the error %v", err)
+	}
+
+	// Aliases to names in other zones are resolved by the pool
+	if ans, _, err := auth.Resolve(ctx, "cdn.example.com", "A", PriorityLow); err != nil || len(ans) != 1 || ans[0].Data != "203.0.113.1" {
+		t.Errorf("The alias to another zone was not resolved by the pool: %v, %v", ans, err)
+	}
+
+	// Names outside the known zones are resolved by the pool
+	if ans, _, err := auth.Resolve(ctx, "www.owasp.org", "A", PriorityLow); err != nil || len(ans) != 1 {
+		t.Errorf("The name outside the known zones was not resolved by the pool: %v, %v", ans, err)
+	}
+
+	if got := atomic.LoadInt64(&queries); got != 3 {
+		t.Errorf("The authoritative server received %d queries, expected 3", got)
+	}
+	if q := pool.Queries(); len(q) != 2 {
+		t.Errorf("The pool received the queries %v", q)
+	}
+}
+
+func TestAuthoritativeResolversRate(t *testing.T) {
+	var queries int64
+	addr, stop := startAuthoritativeServer(t, &queries)
+	defer stop()
+
+	auth := NewAuthoritativeResolvers(NewMockResolver(), 20)
+	defer auth.Stop()
+	auth.AddZoneServer("example.com", addr)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		auth.Resolve(context.Background(), "www.example.com", "A", PriorityLow)
+	}
+	// Five queries at twenty per second require at least four intervals of 50ms
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("The queries were sent in %s, which exceeds the rate limit", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	auth.Resolve(ctx, "www.example.com", "A", PriorityLow)
+	if _, _, err := auth.Resolve(ctx, "www.example.com", "A", PriorityLow); err == nil {
+		t.Errorf("The query was sent after the context was cancelled")
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/miekg/dns"
+)
+
+const (
+	// The zone is signed, so validating resolvers set the AD bit for the answers
+	capabilityProbeName = "example.com."
+	capabilityTimeout   = 2 * time.Second
+	// The number of simultaneous queries used to estimate the response rate
+	capabilityBurst = 10
+	// The UDP message size advertised in the EDNS0 probe and normal queries
+	maxEDNS0Size uint16 = 1232
+)
+
+// Capabilities describes the features a resolver demonstrated while it was probed.
+type Capabilities struct {
+	// Set when the probes were performed and the values below are known
+	Probed bool
+	// The UDP message size accepted by the resolver, or zero when EDNS0 is not supported
+	EDNS0Size uint16
+	TCP       bool
+	// Set when the resolver validates DNSSEC signatures
+	DNSSEC bool
+	// Set when the resolver preserves the case of query names (0x20 encoding)
+	CaseRandomization bool
+	// The fraction of a burst of simultaneous queries that were answered
+	ResponseRate float64
+	RTT          time.Duration
+}
+
+// CapabilitiesResolver is implemented by the Resolvers that learn the features of the server by probing it.
+type CapabilitiesResolver interface {
+	// Capabilities returns the features learned by probing the resolver, or nil when unknown
+	Capabilities() *Capabilities
+}
+
+// ResolverCapabilities returns the features learned by probing the resolver, or nil when
+// unknown or the Resolver does not implement the CapabilitiesResolver interface.
+func ResolverCapabilities(r Resolver) *Capabilities {
+	if c, ok := r.(CapabilitiesResolver); ok {
+		return c.Capabilities()
+	}
+	return nil
+}
+
+// Responsive returns true when the resolver answered any of the probes.
+func (c *Capabilities) Responsive() bool {
+	return c.ResponseRate > 0 || c.TCP
+}
+
+// String implements the Stringer interface.
+func (c *Capabilities) String() string {
+	if !c.Probed {
+		return "not probed"
+	}
+
+	return fmt.Sprintf("EDNS0: %d, TCP: %t, DNSSEC: %t, 0x20: %t, Response rate: %.0f%%, RTT: %s",
+		c.EDNS0Size, c.TCP, c.DNSSEC, c.CaseRandomization, c.ResponseRate*100, c.RTT.Round(time.Millisecond))
+}
+
+// ProbeCapabilities sends test queries to the resolver at addr, which can include the
+// port number, in order to learn the features it supports. The probes are performed
+// concurrently and complete within a few seconds.
+func ProbeCapabilities(addr string) *Capabilities {
+	host, port := addr, "53"
+	if h, p, err := net.SplitHostPort(addr); err == nil {
+		host, port = h, p
+	}
+	server := net.JoinHostPort(host, port)
+
+	caps := &Capabilities{Probed: true}
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+
+	wg.Add(5)
+	go func() {
+		defer wg.Done()
+
+		rate, rtt := probeResponseRate(server)
+		lock.Lock()
+		caps.ResponseRate, caps.RTT = rate, rtt
+		lock.Unlock()
+	}()
+	go func() {
+		defer wg.Done()
+
+		size := probeEDNS0(server)
+		lock.Lock()
+		caps.EDNS0Size = size
+		lock.Unlock()
+	}()
+	go func() {
+		defer wg.Done()
+
+		tcp := probeTCP(server)
+		lock.Lock()
+		caps.TCP = tcp
+		lock.Unlock()
+	}()
+	go func() {
+		defer wg.Done()
+
+		sec := probeDNSSEC(server)
+		lock.Lock()
+		caps.DNSSEC = sec
+		lock.Unlock()
+	}()
+	go func() {
+		defer wg.Done()
+
+		tolerant := probeCaseRandomization(server)
+		lock.Lock()
+		caps.CaseRandomization = tolerant
+		lock.Unlock()
+	}()
+	wg.Wait()
+
+	return caps
+}
+
+func probeMessage(name string) *dns.Msg {
+	m := new(dns.Msg)
+	// The name is provided as is, since SetQuestion would not alter the case
+	m.SetQuestion(name, dns.TypeA)
+	return m
+}
+
+func probeExchange(server, network string, m *dns.Msg) (*dns.Msg, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), capabilityTimeout)
+	defer cancel()
+
+	conn, err := amassnet.DialDNSContext(ctx, network, server)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+
+	c := &dns.Client{
+		Net:     network,
+		Timeout: capabilityTimeout,
+	}
+
+	r, rtt, err := c.ExchangeWithConn(m, &dns.Conn{Conn: conn})
+	if err == nil && r == nil {
+		err = fmt.Errorf("No reply from %s", server)
+	}
+	return r, rtt, err
+}
+
+func probeResponseRate(server string) (float64, time.Duration) {
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	var answered int
+	var total time.Duration
+
+	wg.Add(capabilityBurst)
+	for i := 0; i < capabilityBurst; i++ {
+		go func() {
+			defer wg.Done()
+
+			r, rtt, err := probeExchange(server, "udp", probeMessage(capabilityProbeName))
+			if err != nil || r.Rcode != dns.RcodeSuccess {
+				return
+			}
+
+			lock.Lock()
+			answered++
+			total += rtt
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if answered == 0 {
+		return 0, 0
+	}
+	return float64(answered) / capabilityBurst, total / time.Duration(answered)
+}
+
+func probeEDNS0(server string) uint16 {
+	m := probeMessage(capabilityProbeName)
+	m.SetEdns0(maxEDNS0Size, false)
+
+	r, _, err := probeExchange(server, "udp", m)
+	if err != nil || r.Rcode != dns.RcodeSuccess {
+		return 0
+	}
+
+	opt := r.IsEdns0()
+	if opt == nil {
+		return 0
+	}
+	if size := opt.UDPSize(); size < maxEDNS0Size {
+		if size < dns.MinMsgSize {
+			return dns.MinMsgSize
+		}
+		return size
+	}
+	return maxEDNS0Size
+}
+
+func probeTCP(server string) bool {
+	r, _, err := probeExchange(server, "tcp", probeMessage(capabilityProbeName))
+
+	return err == nil && r.Rcode == dns.RcodeSuccess
+}
+
+func probeDNSSEC(server string) bool {
+	m := probeMessage(capabilityProbeName)
+	m.SetEdns0(maxEDNS0Size, true)
+
+	r, _, err := probeExchange(server, "udp", m)
+	if err != nil || r.Rcode != dns.RcodeSuccess {
+		return false
+	}
+	return r.AuthenticatedData
+}
+
+func probeCaseRandomization(server string) bool {
+	// A fixed mixture of cases ensures that modified names are detected
+	name := "eXaMpLe.CoM."
+
+	r, _, err := probeExchange(server, "udp", probeMessage(name))
+	if err != nil || r.Rcode != dns.RcodeSuccess || len(r.Question) == 0 {
+		return false
+	}
+	return r.Question[0].Name == name
+}
+
+// randomCase returns the name with the case of each letter randomly selected, as
+// performed by the 0x20 encoding that protects against spoofed responses.
+func randomCase(name string) string {
+	var b strings.Builder
+
+	for _, c := range name {
+		if randomInt(0, 1) == 1 {
+			b.WriteString(strings.ToUpper(string(c)))
+		} else {
+			b.WriteString(strings.ToLower(string(c)))
+		}
+	}
+	return b.String()
+}
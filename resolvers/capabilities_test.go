@@ -0,0 +1,107 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package resolvers
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func startProbeServer(t *testing.T, handler dns.HandlerFunc) (string, func()) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen on UDP: %v", err)
+	}
+	addr := pc.LocalAddr().String()
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		pc.Close()
+		t.Fatalf("Failed to listen on TCP: %v", err)
+	}
+
+	udp := &dns.Server{PacketConn: pc, Handler: handler}
+	tcp := &dns.Server{Listener: l, Handler: handler}
+	go udp.ActivateAndServe()
+	go tcp.ActivateAndServe()
+
+	return addr, func() {
+		udp.Shutdown()
+		tcp.Shutdown()
+	}
+}
+
+func TestProbeCapabilities(t *testing.T) {
+	addr, stop := startProbeServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+		m.AuthenticatedData = true
+		if opt := req.IsEdns0(); opt != nil {
+			m.SetEdns0(4096, opt.Do())
+		}
+		w.WriteMsg(m)
+	})
+	defer stop()
+
+	caps := ProbeCapabilities(addr)
+	if !caps.Probed || !caps.Responsive() {
+		t.Fatalf("The resolver was not found to be responsive: %s", caps)
+	}
+	if caps.ResponseRate != 1 {
+		t.Errorf("Expected a response rate of 1, got %f", caps.ResponseRate)
+	}
+	if caps.EDNS0Size != maxEDNS0Size {
+		t.Errorf("Expected an EDNS0 size of %d, got %d", maxEDNS0Size, caps.EDNS0Size)
+	}
+	if !caps.TCP || !caps.DNSSEC || !caps.CaseRandomization {
+		t.Errorf("Capabilities were not detected: %s", caps)
+	}
+}
+
+func TestProbeCapabilitiesLimited(t *testing.T) {
+	addr, stop := startProbeServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		// The server lowers the case of names and does not support EDNS0
+		m.SetReply(req)
+		m.Question[0].Name = strings.ToLower(m.Question[0].Name)
+		if req.IsEdns0() != nil {
+			m.Rcode = dns.RcodeFormatError
+		}
+		w.WriteMsg(m)
+	})
+	defer stop()
+
+	caps := ProbeCapabilities(addr)
+	if caps.EDNS0Size != 0 || caps.DNSSEC || caps.CaseRandomization {
+		t.Errorf("Unsupported capabilities were detected: %s", caps)
+	}
+
+	r := NewBaseResolver(addr)
+	defer r.Stop()
+	r.SetCapabilities(caps)
+
+	msg := r.newQueryMessage("www.example.com", dns.TypeA)
+	if msg.IsEdns0() != nil {
+		t.Errorf("The query contained an OPT record for a resolver without EDNS0 support")
+	}
+}
+
+func TestResolverCapabilities(t *testing.T) {
+	if caps := ResolverCapabilities(NewMockResolver()); caps != nil {
+		t.Errorf("ResolverCapabilities returned %s for a resolver that is never probed", caps)
+	}
+
+	r := NewBaseResolver("127.0.0.1")
+	caps := &Capabilities{Probed: true}
+	r.SetCapabilities(caps)
+
+	rm := NewRateMonitoredResolver(r)
+	defer rm.Stop()
+	if got := ResolverCapabilities(rm); got != caps {
+		t.Errorf("ResolverCapabilities returned %s instead of the capabilities of the wrapped resolver", got)
+	}
+}
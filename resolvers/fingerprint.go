@@ -0,0 +1,167 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package resolvers
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/miekg/dns"
+	"golang.org/x/net/publicsuffix"
+)
+
+// NameserverFingerprint describes the DNS provider and software of an authoritative name server.
+type NameserverFingerprint struct {
+	Provider string
+	Software string
+	// The version string disclosed by the server, which can be modified by the operator
+	Version string
+	// Response behaviors that help distinguish the software when the version is hidden
+	Quirks []string
+}
+
+// The hosted DNS providers identified by the label of the registered domain of the name server
+var nsProviders = map[string]string{
+	"akam":              "Akamai",
+	"alidns":            "Alibaba Cloud DNS",
+	"awsdns":            "Amazon Route 53",
+	"azure-dns":         "Azure DNS",
+	"bluehost":          "Bluehost",
+	"cloudflare":        "Cloudflare",
+	"cloudns":           "ClouDNS",
+	"constellix":        "Constellix",
+	"digitalocean":      "DigitalOcean",
+	"dnsimple":          "DNSimple",
+	"dnsmadeeasy":       "DNS Made Easy",
+	"dnspod":            "DNSPod",
+	"domaincontrol":     "GoDaddy",
+	"dynect":            "Dyn",
+	"easydns":           "easyDNS",
+	"gandi":             "Gandi",
+	"googledomains":     "Google Cloud DNS",
+	"he":                "Hurricane Electric",
+	"hetzner":           "Hetzner",
+	"hostgator":         "HostGator",
+	"linode":            "Linode",
+	"name-services":     "Enom",
+	"nsone":             "NS1",
+	"ovh":               "OVH",
+	"rackspace":         "Rackspace",
+	"registrar-servers": "Namecheap",
+	"ultradns":          "UltraDNS",
+	"vercel-dns":        "Vercel",
+	"wixdns":            "Wix",
+	"worldnic":          "Network Solutions",
+	"zoneedit":          "ZoneEdit",
+}
+
+// The software identified by the version strings disclosed in CHAOS class queries
+var nsSoftware = []struct {
+	re   *regexp.Regexp
+	name string
+}{
+	{regexp.MustCompile(`(?i)powerdns`), "PowerDNS"},
+	{regexp.MustCompile(`(?i)\bnsd\b`), "NSD"},
+	{regexp.MustCompile(`(?i)knot`), "Knot DNS"},
+	{regexp.MustCompile(`(?i)microsoft|windows`), "Microsoft DNS"},
+	{regexp.MustCompile(`(?i)unbound`), "Unbound"},
+	{regexp.MustCompile(`(?i)dnsmasq`), "dnsmasq"},
+	{regexp.MustCompile(`(?i)coredns`), "CoreDNS"},
+	{regexp.MustCompile(`(?i)yadifa`), "YADIFA"},
+	{regexp.MustCompile(`(?i)bind|^9\.\d+`), "BIND"},
+}
+
+// NameserverProvider returns the hosted DNS provider operating the name server,
+// or an empty string when the pattern of the name is not recognized.
+func NameserverProvider(ns string) string {
+	domain, err := publicsuffix.EffectiveTLDPlusOne(strings.Trim(strings.ToLower(ns), "."))
+	if err != nil {
+		return ""
+	}
+
+	label := strings.SplitN(domain, ".", 2)[0]
+	// Route 53 uses many domains, such as awsdns-12.com and awsdns-34.net
+	if strings.HasPrefix(label, "awsdns-") {
+		label = "awsdns"
+	}
+	return nsProviders[label]
+}
+
+// NameserverSoftware returns the DNS software identified by the version string.
+func NameserverSoftware(version string) string {
+	for _, s := range nsSoftware {
+		if s.re.MatchString(version) {
+			return s.name
+		}
+	}
+	return ""
+}
+
+// FingerprintNameserver sends queries to the authoritative name server at addr, which can
+// include the port number, in order to identify the software answering for the zone.
+func FingerprintNameserver(zone, ns, addr string) *NameserverFingerprint {
+	host, port := addr, "53"
+	if h, p, err := net.SplitHostPort(addr); err == nil {
+		host, port = h, p
+	}
+	server := net.JoinHostPort(host, port)
+
+	fp := &NameserverFingerprint{Provider: NameserverProvider(ns)}
+	for _, name := range []string{"version.bind.", "version.server."} {
+		version, rcode := chaosTXT(server, name)
+
+		if version != "" {
+			fp.Version = version
+			fp.Software = NameserverSoftware(version)
+			break
+		}
+		if rcode != "" {
+			fp.Quirks = append(fp.Quirks, name+" "+rcode)
+		}
+	}
+
+	m := probeMessage(dns.Fqdn(zone))
+	m.Question[0].Qtype = dns.TypeSOA
+	m.RecursionDesired = false
+	m.SetEdns0(maxEDNS0Size, false)
+
+	r, _, err := probeExchange(server, "udp", m)
+	if err != nil {
+		fp.Quirks = append(fp.Quirks, "no response for the zone")
+		return fp
+	}
+	if !r.Authoritative {
+		fp.Quirks = append(fp.Quirks, "not authoritative")
+	}
+	if r.RecursionAvailable {
+		fp.Quirks = append(fp.Quirks, "recursion available")
+	}
+	if r.IsEdns0() == nil {
+		fp.Quirks = append(fp.Quirks, "no EDNS0")
+	}
+	return fp
+}
+
+// chaosTXT returns the text of the CHAOS class TXT record, or the response code when unavailable.
+func chaosTXT(server, name string) (string, string) {
+	m := new(dns.Msg)
+	m.SetQuestion(name, dns.TypeTXT)
+	m.Question[0].Qclass = dns.ClassCHAOS
+	m.RecursionDesired = false
+
+	r, _, err := probeExchange(server, "udp", m)
+	if err != nil {
+		return "", "timeout"
+	}
+
+	for _, rr := range r.Answer {
+		if txt, ok := rr.(*dns.TXT); ok && len(txt.Txt) > 0 {
+			if v := strings.TrimSpace(strings.Join(txt.Txt, " ")); v != "" {
+				return v, ""
+			}
+		}
+	}
+	return "", dns.RcodeToString[r.Rcode]
+}
@@ -0,0 +1,77 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func TestNameserverProvider(t *testing.T) {
+	tests := []struct {
+		NS       string
+		Expected string
+	}{
+		{"ns-1234.awsdns-12.org.", "Amazon Route 53"},
+		{"kate.ns.cloudflare.com", "Cloudflare"},
+		{"ns1-05.azure-dns.com", "Azure DNS"},
+		{"ns-cloud-a1.googledomains.com", "Google Cloud DNS"},
+		{"NS1.example.com", ""},
+	}
+
+	for _, test := range tests {
+		if p := NameserverProvider(test.NS); p != test.Expected {
+			t.Errorf("%s was identified as %q instead of %q", test.NS, p, test.Expected)
+		}
+	}
+}
+
+func TestNameserverSoftware(t *testing.T) {
+	tests := []struct {
+		Version  string
+		Expected string
+	}{
+		{"9.16.1-Ubuntu", "BIND"},
+		{"PowerDNS Authoritative Server 4.3.0", "PowerDNS"},
+		{"NSD 4.3.5", "NSD"},
+		{"Knot DNS 3.0.2", "Knot DNS"},
+		{"none of your business", ""},
+	}
+
+	for _, test := range tests {
+		if s := NameserverSoftware(test.Version); s != test.Expected {
+			t.Errorf("%s was identified as %q instead of %q", test.Version, s, test.Expected)
+		}
+	}
+}
+
+func TestFingerprintNameserver(t *testing.T) {
+	addr, stop := startProbeServer(t, func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+
+		if req.Question[0].Qclass == dns.ClassCHAOS {
+			if req.Question[0].Name == "version.bind." {
+				m.Answer = append(m.Answer, &dns.TXT{
+					Hdr: dns.RR_Header{Name: req.Question[0].Name, Rrtype: dns.TypeTXT, Class: dns.ClassCHAOS},
+					Txt: []string{"Knot DNS 3.0.2"},
+				})
+			}
+		} else {
+			// The server is authoritative, but does not support EDNS0
+			m.Authoritative = true
+		}
+		w.WriteMsg(m)
+	})
+	defer stop()
+
+	fp := FingerprintNameserver("example.com", "ns1.example.com", addr)
+	if fp.Software != "Knot DNS" || fp.Version != "Knot DNS 3.0.2" {
+		t.Errorf("The software was identified as %q with version %q", fp.Software, fp.Version)
+	}
+	if len(fp.Quirks) != 1 || fp.Quirks[0] != "no EDNS0" {
+		t.Errorf("Unexpected quirks were identified: %v", fp.Quirks)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package resolvers
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/miekg/dns"
+)
+
+// MockResolver implements the Resolver interface using answers provided in advance,
+// which permits code depending on DNS resolution to be tested without network access.
+type MockResolver struct {
+	sync.Mutex
+	answers map[string][]requests.DNSAnswer
+	rcodes  map[string]int
+	reverse map[string]string
+	stats   map[int]int64
+	queries []string
+	stopped bool
+}
+
+// NewMockResolver returns a MockResolver without any answers.
+func NewMockResolver() *MockResolver {
+	return &MockResolver{
+		answers: make(map[string][]requests.DNSAnswer),
+		rcodes:  make(map[string]int),
+		reverse: make(map[string]string),
+		stats:   make(map[int]int64),
+	}
+}
+
+func mockKey(name, qtype string) string {
+	return strings.ToLower(strings.Trim(name, ".")) + "|" + strings.ToUpper(qtype)
+}
+
+// AddAnswer provides a record that will be returned for the name and query type.
+func (r *MockResolver) AddAnswer(name, qtype, data string) {
+	r.Lock()
+	defer r.Unlock()
+
+	var t int
+	if num, err := textToTypeNum(strings.ToUpper(qtype)); err == nil {
+		t = int(num)
+	}
+
+	key := mockKey(name, qtype)
+	r.answers[key] = append(r.answers[key], requests.DNSAnswer{
+		Name: strings.ToLower(strings.Trim(name, ".")),
+		Type: t,
+		Data: data,
+	})
+}
+
+// AddRcode causes the queries for the name and type to fail with the response code, such as
+// dns.RcodeServerFailure, instead of reporting that the name does not exist.
+func (r *MockResolver) AddRcode(name, qtype string, rcode int) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.rcodes[mockKey(name, qtype)] = rcode
+}
+
+// AddReverse provides the name that will be returned for the reverse DNS query of addr.
+func (r *MockResolver) AddReverse(addr, name string) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.reverse[addr] = strings.ToLower(strings.Trim(name, "."))
+}
+
+// Queries returns the names and query types requested, in the order received.
+func (r *MockResolver) Queries() []string {
+	r.Lock()
+	defer r.Unlock()
+
+	return append([]string(nil), r.queries...)
+}
+
+// String implements the Resolver interface.
+func (r *MockResolver) String() string {
+	return "mock"
+}
+
+// Address implements the Resolver interface.
+func (r *MockResolver) Address() string {
+	return "127.0.0.1"
+}
+
+// Port implements the Resolver interface.
+func (r *MockResolver) Port() int {
+	return 53
+}
+
+// Resolve implements the Resolver interface.
+func (r *MockResolver) Resolve(ctx context.Context, name, qtype string, priority int) ([]requests.DNSAnswer, bool, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	key := mockKey(name, qtype)
+	r.queries = append(r.queries, key)
+	r.stats[QueryAttempts]++
+
+	if ans, found := r.answers[key]; found {
+		r.stats[QueryCompletions]++
+		return append([]requests.DNSAnswer(nil), ans...), false, nil
+	}
+
+	rcode := dns.RcodeNameError
+	if code, found := r.rcodes[key]; found {
+		rcode = code
+	}
+	return nil, false, &ResolveError{
+		Err:   "Mock: No answer was provided for " + key,
+		Rcode: rcode,
+	}
+}
+
+// Reverse implements the Resolver interface.
+func (r *MockResolver) Reverse(ctx context.Context, addr string, priority int) (string, string, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if name, found := r.reverse[addr]; found {
+		return addr, name, nil
+	}
+
+	return "", "", &ResolveError{
+		Err:   "Mock: No reverse answer was provided for " + addr,
+		Rcode: dns.RcodeNameError,
+	}
+}
+
+// NsecTraversal implements the Resolver interface.
+func (r *MockResolver) NsecTraversal(ctx context.Context, domain string, priority int) ([]string, bool, error) {
+	return nil, false, &ResolveError{
+		Err:   "Mock: NSEC traversal is not supported",
+		Rcode: ResolverErrRcode,
+	}
+}
+
+// Available implements the Resolver interface.
+func (r *MockResolver) Available() (bool, error) {
+	return !r.IsStopped(), nil
+}
+
+// Stats implements the Resolver interface.
+func (r *MockResolver) Stats() map[int]int64 {
+	r.Lock()
+	defer r.Unlock()
+
+	stats := make(map[int]int64)
+	for k, v := range r.stats {
+		stats[k] = v
+	}
+	return stats
+}
+
+// WipeStats implements the Resolver interface.
+func (r *MockResolver) WipeStats() {
+	r.Lock()
+	defer r.Unlock()
+
+	r.stats = make(map[int]int64)
+}
+
+// ReportError implements the Resolver interface.
+func (r *MockResolver) ReportError() {}
+
+// MatchesWildcard implements the Resolver interface.
+func (r *MockResolver) MatchesWildcard(ctx context.Context, req *requests.DNSRequest) bool {
+	return false
+}
+
+// GetWildcardType implements the Resolver interface.
+func (r *MockResolver) GetWildcardType(ctx context.Context, req *requests.DNSRequest) int {
+	return WildcardTypeNone
+}
+
+// SubdomainToDomain implements the Resolver interface.
+func (r *MockResolver) SubdomainToDomain(name string) string {
+	r.Lock()
+	defer r.Unlock()
+
+	labels := strings.Split(strings.ToLower(strings.Trim(name, ".")), ".")
+	for i := range labels {
+		sub := strings.Join(labels[i:], ".")
+
+		if _, found := r.answers[mockKey(sub, "NS")]; found {
+			return sub
+		}
+	}
+	return name
+}
+
+// Stop implements the Resolver interface.
+func (r *MockResolver) Stop() error {
+	r.Lock()
+	defer r.Unlock()
+
+	r.stopped = true
+	return nil
+}
+
+// IsStopped implements the Resolver interface.
+func (r *MockResolver) IsStopped() bool {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.stopped
+}
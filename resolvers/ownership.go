@@ -0,0 +1,41 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package resolvers
+
+import (
+	"context"
+	"strings"
+)
+
+// OwnershipTXTPrefix begins the TXT record value that publishes an ownership verification token.
+const OwnershipTXTPrefix = "amass-verification="
+
+// OwnershipTXTLabel is prepended to the domain name for the alternative location of the TXT record.
+const OwnershipTXTLabel = "_amass-verification"
+
+// OwnershipTokenPublished returns true when a TXT record of the domain, or of the name formed
+// by prepending the OwnershipTXTLabel, provides the token following the OwnershipTXTPrefix.
+func OwnershipTokenPublished(ctx context.Context, r Resolver, domain, token string) bool {
+	domain = strings.ToLower(strings.Trim(domain, "."))
+	if domain == "" || token == "" {
+		return false
+	}
+
+	for _, name := range []string{domain, OwnershipTXTLabel + "." + domain} {
+		answers, _, err := r.Resolve(ctx, name, "TXT", PriorityHigh)
+		if err != nil {
+			continue
+		}
+
+		for _, a := range answers {
+			value := strings.TrimSpace(strings.Trim(a.Data, "\""))
+
+			if strings.HasPrefix(value, OwnershipTXTPrefix) &&
+				strings.TrimSpace(strings.TrimPrefix(value, OwnershipTXTPrefix)) == token {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package resolvers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOwnershipTokenPublished(t *testing.T) {
+	r := NewMockResolver()
+	r.AddAnswer("owasp.org", "TXT", "v=spf1 -all")
+	r.AddAnswer("owasp.org", "TXT", OwnershipTXTPrefix+"abc123")
+	r.AddAnswer("_amass-verification.example.com", "TXT", OwnershipTXTPrefix+"def456")
+	r.AddAnswer("example.org", "TXT", OwnershipTXTPrefix+"abc1234")
+
+	tests := []struct {
+		domain   string
+		token    string
+		expected bool
+	}{
+		{"owasp.org", "abc123", true},
+		{"OWASP.org.", "abc123", true},
+		{"owasp.org", "def456", false},
+		{"example.com", "def456", true},
+		{"example.org", "abc123", false},
+		{"example.net", "abc123", false},
+		{"owasp.org", "", false},
+	}
+
+	for _, test := range tests {
+		if got := OwnershipTokenPublished(context.Background(), r, test.domain, test.token); got != test.expected {
+			t.Errorf("OwnershipTokenPublished(%s, %s) returned %t, expected %t", test.domain, test.token, got, test.expected)
+		}
+	}
+}
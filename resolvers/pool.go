@@ -9,12 +9,11 @@ import (
 	"io/ioutil"
 	"log"
 	"math/rand"
-	"net"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/OWASP/Amass/v3/limits"
-	amassnet "github.com/OWASP/Amass/v3/net"
 	amassdns "github.com/OWASP/Amass/v3/net/dns"
 	"github.com/OWASP/Amass/v3/queue"
 	"github.com/OWASP/Amass/v3/requests"
@@ -32,6 +31,8 @@ type ResolverPool struct {
 	hasBeenStopped  bool
 	wildcardQueue   *queue.Queue
 	semMaxWildcard  *semaphore.Weighted
+	staticLock      sync.RWMutex
+	staticWildcards map[string]*StaticWildcard
 }
 
 // SetupResolverPool initializes a ResolverPool with the type of resolvers indicated by the parameters.
@@ -55,6 +56,8 @@ func SetupResolverPool(addrs []string, maxQueries int, ratemon bool, log *log.Lo
 	for _, addr := range addrs {
 		go func(ip string, ch chan Resolver) {
 			if n := NewBaseResolver(ip); n != nil {
+				// Warm up the resolver by learning the features it supports
+				n.SetCapabilities(ProbeCapabilities(n.String()))
 				ch <- n
 				return
 			}
@@ -64,7 +67,7 @@ func SetupResolverPool(addrs []string, maxQueries int, ratemon bool, log *log.Lo
 
 	l := len(addrs)
 	var resolvers []Resolver
-	t := time.NewTimer(5 * time.Second)
+	t := time.NewTimer(10 * time.Second)
 	defer t.Stop()
 loop:
 	for i := 0; i < l; i++ {
@@ -88,7 +91,36 @@ loop:
 		return nil
 	}
 
-	return NewResolverPool(resolvers, maxQueries, log)
+	pool := NewResolverPool(removeUnresponsive(resolvers, log), maxQueries, log)
+	for _, r := range pool.Resolvers {
+		pool.Log.Printf("Resolver %s capabilities: %s", r.String(), ResolverCapabilities(r))
+	}
+	return pool
+}
+
+// removeUnresponsive returns the resolvers that answered the capability probes. When none
+// of them answered, the network is likely unavailable and all the resolvers are kept.
+func removeUnresponsive(resolvers []Resolver, logger *log.Logger) []Resolver {
+	var responsive []Resolver
+
+	for _, r := range resolvers {
+		if caps := ResolverCapabilities(r); caps == nil || caps.Responsive() {
+			responsive = append(responsive, r)
+		}
+	}
+	if len(responsive) == 0 {
+		return resolvers
+	}
+
+	for _, r := range resolvers {
+		if caps := ResolverCapabilities(r); caps != nil && !caps.Responsive() {
+			if logger != nil {
+				logger.Printf("Resolver %s was removed for not responding to the probes", r.String())
+			}
+			r.Stop()
+		}
+	}
+	return responsive
 }
 
 // NewResolverPool initializes a ResolverPool that uses the provided Resolvers.
@@ -268,13 +300,10 @@ func (rp *ResolverPool) NextResolver() Resolver {
 
 // Reverse is performs reverse DNS queries using available Resolvers in the pool.
 func (rp *ResolverPool) Reverse(ctx context.Context, addr string, priority int) (string, string, error) {
-	var name, ptr string
+	var name string
 
-	if ip := net.ParseIP(addr); amassnet.IsIPv4(ip) {
-		ptr = amassdns.ReverseIP(addr) + ".in-addr.arpa"
-	} else if amassnet.IsIPv6(ip) {
-		ptr = amassdns.IPv6NibbleFormat(ip.String()) + ".ip6.arpa"
-	} else {
+	ptr := amassdns.ReverseAddrName(addr)
+	if ptr == "" {
 		return ptr, "", &ResolveError{
 			Err:   fmt.Sprintf("Invalid IP address parameter: %s", addr),
 			Rcode: ResolverErrRcode,
@@ -369,7 +398,7 @@ func (rp *ResolverPool) NsecTraversal(ctx context.Context, domain string, priori
 	var ans []string
 	// This loop ensures the correct number of attempts of the DNS query
 	for count := 0; count < attempts; {
-		r := rp.NextResolver()
+		r := rp.nextDNSSECResolver()
 		if r == nil {
 			// Give the system a chance to breathe before trying again
 			time.Sleep(time.Duration(randomInt(100, 200)) * time.Millisecond)
@@ -386,8 +415,29 @@ func (rp *ResolverPool) NsecTraversal(ctx context.Context, domain string, priori
 	return []string{}, false, err
 }
 
+// nextDNSSECResolver prefers resolvers known to support DNSSEC, since they are
+// more likely to return the NSEC records.
+func (rp *ResolverPool) nextDNSSECResolver() Resolver {
+	var r Resolver
+
+	for i := 0; i < 3; i++ {
+		r = rp.NextResolver()
+		if r == nil {
+			return nil
+		}
+		if caps := ResolverCapabilities(r); caps == nil || !caps.Probed || caps.DNSSEC {
+			break
+		}
+	}
+	return r
+}
+
 // MatchesWildcard returns true if the request provided resolved to a DNS wildcard.
 func (rp *ResolverPool) MatchesWildcard(ctx context.Context, req *requests.DNSRequest) bool {
+	if wtype, found := rp.staticWildcardType(req); found {
+		return wtype != WildcardTypeNone
+	}
+
 	ch := make(chan int, 2)
 
 	rp.wildcardQueue.Append(&wildcardQueueMsg{
@@ -406,6 +456,10 @@ func (rp *ResolverPool) MatchesWildcard(ctx context.Context, req *requests.DNSRe
 
 // GetWildcardType returns the DNS wildcard type for the provided subdomain name.
 func (rp *ResolverPool) GetWildcardType(ctx context.Context, req *requests.DNSRequest) int {
+	if wtype, found := rp.staticWildcardType(req); found {
+		return wtype
+	}
+
 	ch := make(chan int, 2)
 
 	rp.wildcardQueue.Append(&wildcardQueueMsg{
@@ -46,6 +46,10 @@ func NewRateMonitoredResolver(res Resolver) *RateMonitoredResolver {
 	}
 
 	go r.manageRateState()
+	// Resolvers that dropped queries while being probed start at the slowest rate
+	if caps := ResolverCapabilities(res); caps != nil && caps.Probed && caps.ResponseRate < 1 {
+		r.setRate(defaultSlowestRate)
+	}
 	return r
 }
 
@@ -121,6 +125,11 @@ func (r *RateMonitoredResolver) ReportError() {
 	r.resolver.ReportError()
 }
 
+// Capabilities implements the CapabilitiesResolver interface.
+func (r *RateMonitoredResolver) Capabilities() *Capabilities {
+	return ResolverCapabilities(r.resolver)
+}
+
 // MatchesWildcard returns true if the request provided resolved to a DNS wildcard.
 func (r *RateMonitoredResolver) MatchesWildcard(ctx context.Context, req *requests.DNSRequest) bool {
 	return r.resolver.MatchesWildcard(ctx, req)
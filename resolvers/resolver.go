@@ -6,9 +6,9 @@ package resolvers
 import (
 	"context"
 	"fmt"
-	"net"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/OWASP/Amass/v3/eventbus"
@@ -136,6 +136,8 @@ type BaseResolver struct {
 	readMsgs         *queue.Queue
 	address          string
 	port             string
+	capsLock         sync.Mutex
+	caps             *Capabilities
 }
 
 // NewBaseResolver initializes a Resolver that send DNS queries to the provided IP address.
@@ -207,6 +209,23 @@ func (r *BaseResolver) Available() (bool, error) {
 // ReportError indicates to the Resolver that it delivered an erroneous response.
 func (r *BaseResolver) ReportError() {}
 
+// Capabilities implements the CapabilitiesResolver interface.
+func (r *BaseResolver) Capabilities() *Capabilities {
+	r.capsLock.Lock()
+	defer r.capsLock.Unlock()
+
+	return r.caps
+}
+
+// SetCapabilities assigns the features learned by probing the resolver, which
+// shape the queries sent to it.
+func (r *BaseResolver) SetCapabilities(caps *Capabilities) {
+	r.capsLock.Lock()
+	defer r.capsLock.Unlock()
+
+	r.caps = caps
+}
+
 // SubdomainToDomain returns the first subdomain name of the provided
 // parameter that responds to a DNS query for the NS record type.
 func (r *BaseResolver) SubdomainToDomain(name string) string {
@@ -243,7 +262,7 @@ func (r *BaseResolver) Resolve(ctx context.Context, name, qtype string, priority
 		bus = b.(*eventbus.EventBus)
 	}
 
-	result := r.queueQuery(queryMessage(r.getID(), name, qt), name, qt, priority)
+	result := r.queueQuery(r.newQueryMessage(name, qt), name, qt, priority)
 	// Report the completion of the DNS query
 	if bus != nil {
 		rcode := dns.RcodeSuccess
@@ -259,13 +278,10 @@ func (r *BaseResolver) Resolve(ctx context.Context, name, qtype string, priority
 
 // Reverse is performs reverse DNS queries using the Resolver.
 func (r *BaseResolver) Reverse(ctx context.Context, addr string, priority int) (string, string, error) {
-	var name, ptr string
+	var name string
 
-	if ip := net.ParseIP(addr); amassnet.IsIPv4(ip) {
-		ptr = amassdns.ReverseIP(addr) + ".in-addr.arpa"
-	} else if amassnet.IsIPv6(ip) {
-		ptr = amassdns.IPv6NibbleFormat(ip.String()) + ".ip6.arpa"
-	} else {
+	ptr := amassdns.ReverseAddrName(addr)
+	if ptr == "" {
 		return ptr, "", &ResolveError{
 			Err:   fmt.Sprintf("Invalid IP address parameter: %s", addr),
 			Rcode: ResolverErrRcode,
@@ -298,6 +314,27 @@ func (r *BaseResolver) Reverse(ctx context.Context, addr string, priority int) (
 	return ptr, name, err
 }
 
+// newQueryMessage builds the query according to the capabilities of the resolver.
+func (r *BaseResolver) newQueryMessage(name string, qt uint16) *dns.Msg {
+	msg := queryMessage(r.getID(), name, qt)
+
+	caps := r.Capabilities()
+	if caps == nil || !caps.Probed {
+		return msg
+	}
+
+	if caps.EDNS0Size == 0 {
+		// Some resolvers return errors for queries containing the OPT record
+		msg.Extra = nil
+	} else if opt := msg.IsEdns0(); opt != nil {
+		opt.SetUDPSize(caps.EDNS0Size)
+	}
+	if caps.CaseRandomization {
+		msg.Question[0].Name = randomCase(msg.Question[0].Name)
+	}
+	return msg
+}
+
 func (r *BaseResolver) checkForTimeouts() {
 	t := time.NewTicker(time.Second)
 	defer t.Stop()
@@ -442,7 +479,24 @@ func (r *BaseResolver) processMessage(m *dns.Msg) {
 		return
 	}
 
+	caps := r.Capabilities()
+	// With 0x20 encoding, a reply that does not match the case of the query could be spoofed
+	if caps != nil && caps.CaseRandomization && len(m.Question) > 0 &&
+		len(req.Msg.Question) > 0 && m.Question[0].Name != req.Msg.Question[0].Name {
+		estr := fmt.Sprintf("DNS query on resolver %s, for %s type %d returned a reply for %s",
+			r.address, req.Name, req.Qtype, m.Question[0].Name)
+		r.returnRequest(req, makeResolveResult(nil, nil, true, estr, ResolverErrRcode))
+		return
+	}
+
 	if m.Truncated {
+		if caps != nil && caps.Probed && !caps.TCP {
+			estr := fmt.Sprintf("DNS query on resolver %s, for %s type %d was truncated and TCP is not supported",
+				r.address, req.Name, req.Qtype)
+			r.returnRequest(req, makeResolveResult(nil, nil, true, estr, NotAvailableRcode))
+			return
+		}
+
 		go r.tcpExchange(m.MsgHdr.Id, req)
 		return
 	}
@@ -455,7 +509,8 @@ func (r *BaseResolver) finishProcessing(m *dns.Msg, req *resolveRequest) {
 
 	for _, a := range extractRawData(m, req.Qtype) {
 		answers = append(answers, requests.DNSAnswer{
-			Name: a.Name,
+			// The case of the name can be randomized by the 0x20 encoding
+			Name: strings.ToLower(a.Name),
 			Type: int(req.Qtype),
 			TTL:  0,
 			Data: strings.TrimSpace(a.Value),
@@ -487,7 +542,7 @@ func (r *BaseResolver) tcpExchange(id uint16, req *resolveRequest) {
 	defer cancel()
 
 	req.Msg = msg
-	conn, err := amassnet.DialContext(ctx, "tcp", r.address+":"+r.port)
+	conn, err := amassnet.DialDNSContext(ctx, "tcp", r.address+":"+r.port)
 	if err != nil {
 		estr := fmt.Sprintf("DNS: Failed to obtain TCP connection to %s:%s: %v", r.address, r.port, err)
 		r.returnRequest(req, makeResolveResult(nil, nil, true, estr, NotAvailableRcode))
@@ -386,7 +386,7 @@ func (r *BaseResolver) periodicRotations(chs *rotationChans) {
 
 			var err error
 			for {
-				current, err = amassnet.DialContext(context.TODO(), "udp", r.address+":"+r.port)
+				current, err = amassnet.DialDNSContext(context.TODO(), "udp", r.address+":"+r.port)
 				if err == nil {
 					break
 				}
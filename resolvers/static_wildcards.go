@@ -0,0 +1,83 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package resolvers
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+// StaticWildcard declares the answers already known to be returned by the DNS wildcard of a zone.
+// Names within a declared zone are not probed for wildcards, and answers matching one of the
+// netblocks or patterns are treated as wildcard answers.
+type StaticWildcard struct {
+	Zone      string
+	Netblocks []*net.IPNet
+	// The regular expressions matched against the data of other records, such as CNAME targets
+	Patterns []*regexp.Regexp
+}
+
+// Matches returns true if the record data is one of the known wildcard answers.
+func (w *StaticWildcard) Matches(data string) bool {
+	data = strings.ToLower(strings.Trim(data, "."))
+
+	if ip := net.ParseIP(data); ip != nil {
+		for _, cidr := range w.Netblocks {
+			if cidr.Contains(ip) {
+				return true
+			}
+		}
+	}
+
+	for _, re := range w.Patterns {
+		if re.MatchString(data) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetStaticWildcards assigns the wildcard declarations used by the pool instead of the DNS wildcard tests.
+func (rp *ResolverPool) SetStaticWildcards(wildcards []*StaticWildcard) {
+	rp.staticLock.Lock()
+	defer rp.staticLock.Unlock()
+
+	rp.staticWildcards = make(map[string]*StaticWildcard)
+	for _, w := range wildcards {
+		if zone := strings.ToLower(strings.Trim(w.Zone, ".")); zone != "" {
+			rp.staticWildcards[zone] = w
+		}
+	}
+}
+
+// staticWildcardType returns the wildcard type for the request according to the declaration of the
+// closest enclosing zone, and false when the name does not belong to a declared zone.
+func (rp *ResolverPool) staticWildcardType(req *requests.DNSRequest) (int, bool) {
+	rp.staticLock.RLock()
+	defer rp.staticLock.RUnlock()
+
+	if len(rp.staticWildcards) == 0 {
+		return WildcardTypeNone, false
+	}
+
+	// The zone apex is not answered by the wildcard
+	labels := strings.Split(strings.ToLower(strings.Trim(req.Name, ".")), ".")
+	for i := 1; i < len(labels); i++ {
+		w, found := rp.staticWildcards[strings.Join(labels[i:], ".")]
+		if !found {
+			continue
+		}
+
+		for _, rr := range req.Records {
+			if w.Matches(rr.Data) {
+				return WildcardTypeStatic, true
+			}
+		}
+		return WildcardTypeNone, true
+	}
+	return WildcardTypeNone, false
+}
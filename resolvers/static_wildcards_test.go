@@ -0,0 +1,57 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package resolvers
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestStaticWildcards(t *testing.T) {
+	_, ipnet, _ := net.ParseCIDR("192.0.2.0/28")
+
+	pool := NewResolverPool([]Resolver{NewMockResolver()}, 10, nil)
+	defer pool.Stop()
+
+	pool.SetStaticWildcards([]*StaticWildcard{{
+		Zone:      "Apps.OWASP.org.",
+		Netblocks: []*net.IPNet{ipnet},
+		Patterns:  []*regexp.Regexp{regexp.MustCompile(`\.cdn\.example\.net$`)},
+	}})
+
+	ctx := context.Background()
+	for _, test := range []struct {
+		name     string
+		data     string
+		expected int
+	}{
+		{"www.apps.owasp.org", "192.0.2.5", WildcardTypeStatic},
+		{"a.b.apps.owasp.org", "edge.cdn.example.net.", WildcardTypeStatic},
+		{"www.apps.owasp.org", "198.51.100.1", WildcardTypeNone},
+	} {
+		req := &requests.DNSRequest{
+			Name:    test.name,
+			Domain:  "owasp.org",
+			Records: []requests.DNSAnswer{{Name: test.name, Data: test.data}},
+		}
+
+		if wtype := pool.GetWildcardType(ctx, req); wtype != test.expected {
+			t.Errorf("%s resolving to %s returned wildcard type %d instead of %d", test.name, test.data, wtype, test.expected)
+		}
+		if matched := pool.MatchesWildcard(ctx, req); matched != (test.expected != WildcardTypeNone) {
+			t.Errorf("MatchesWildcard returned %t for %s resolving to %s", matched, test.name, test.data)
+		}
+	}
+
+	if _, found := pool.staticWildcardType(&requests.DNSRequest{Name: "apps.owasp.org"}); found {
+		t.Errorf("The zone apex was treated as a name within the declared zone")
+	}
+	if _, found := pool.staticWildcardType(&requests.DNSRequest{Name: "www.owasp.org"}); found {
+		t.Errorf("A name outside of the declared zone was covered by the declaration")
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package resolvers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/stringset"
+	"github.com/miekg/dns"
+)
+
+// NameAddresses resolves the IPv4 and IPv6 addresses of the name. Confirmed is true when the
+// result is conclusive, which requires that each query was answered or that the servers reported
+// the name or its address records do not exist. Failed queries, such as timeouts and server
+// failures, leave an empty result unconfirmed, since the name could still be resolving.
+func NameAddresses(ctx context.Context, r Resolver, name string) (addrs []string, confirmed bool) {
+	name = strings.ToLower(strings.Trim(strings.TrimSpace(name), "."))
+	if name == "" {
+		return nil, false
+	}
+
+	confirmed = true
+	set := stringset.New()
+	for _, qtype := range []string{"A", "AAAA"} {
+		answers, _, err := r.Resolve(ctx, name, qtype, PriorityHigh)
+		if err != nil {
+			if rerr, ok := err.(*ResolveError); !ok ||
+				(rerr.Rcode != dns.RcodeNameError && rerr.Rcode != dns.RcodeSuccess) {
+				confirmed = false
+			}
+			continue
+		}
+
+		for _, a := range answers {
+			if a.Type == int(dns.TypeA) || a.Type == int(dns.TypeAAAA) {
+				set.Insert(strings.TrimSpace(a.Data))
+			}
+		}
+	}
+
+	// Any address shows the name is still resolving
+	if set.Len() > 0 {
+		confirmed = true
+	}
+	return set.Slice(), confirmed
+}
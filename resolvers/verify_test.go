@@ -0,0 +1,51 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package resolvers
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+func TestNameAddresses(t *testing.T) {
+	r := NewMockResolver()
+	r.AddAnswer("www.owasp.org", "A", "192.168.1.1")
+	r.AddAnswer("www.owasp.org", "AAAA", "2001:db8::1")
+	r.AddAnswer("api.owasp.org", "A", "192.168.1.2")
+	r.AddRcode("api.owasp.org", "AAAA", dns.RcodeServerFailure)
+	r.AddRcode("mail.owasp.org", "A", dns.RcodeServerFailure)
+
+	tests := []struct {
+		name      string
+		addrs     []string
+		confirmed bool
+	}{
+		{"www.owasp.org", []string{"192.168.1.1", "2001:db8::1"}, true},
+		{"WWW.owasp.org.", []string{"192.168.1.1", "2001:db8::1"}, true},
+		{"api.owasp.org", []string{"192.168.1.2"}, true},
+		{"mail.owasp.org", nil, false},
+		{"old.owasp.org", nil, true},
+		{"", nil, false},
+	}
+
+	for _, test := range tests {
+		addrs, confirmed := NameAddresses(context.Background(), r, test.name)
+		sort.Strings(addrs)
+
+		if confirmed != test.confirmed || len(addrs) != len(test.addrs) {
+			t.Errorf("NameAddresses(%s) returned %v and %t, expected %v and %t",
+				test.name, addrs, confirmed, test.addrs, test.confirmed)
+			continue
+		}
+		for i := range addrs {
+			if addrs[i] != test.addrs[i] {
+				t.Errorf("NameAddresses(%s) returned %v, expected %v", test.name, addrs, test.addrs)
+				break
+			}
+		}
+	}
+}
@@ -26,7 +26,7 @@ func ZoneTransfer(sub, domain, server string) ([]*requests.DNSRequest, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
 
-	conn, err := amassnet.DialContext(ctx, "tcp", server+":53")
+	conn, err := amassnet.DialDNSContext(ctx, "tcp", server+":53")
 	if err != nil {
 		return results, fmt.Errorf("Zone xfr error: Failed to obtain TCP connection to %s: %v", server+":53", err)
 	}
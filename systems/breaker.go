@@ -0,0 +1,302 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package systems
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// BreakerThreshold is the number of consecutive failures that opens the breaker of a data source.
+	BreakerThreshold = 5
+
+	// BreakerCooldown is the time a data source breaker stays open before a trial request is permitted.
+	// The cooldown is doubled each time the trial request fails, up to BreakerMaxCooldown.
+	BreakerCooldown = time.Minute
+
+	// BreakerMaxCooldown is the longest time a data source breaker stays open before another trial.
+	BreakerMaxCooldown = 16 * time.Minute
+
+	// GlobalBreakerThreshold is the number of consecutive failures across all data sources that
+	// opens the global breaker, which indicates that the network or proxy is failing.
+	GlobalBreakerThreshold = 25
+
+	// GlobalBreakerCooldown is the time the global breaker stays open before a trial request is permitted.
+	GlobalBreakerCooldown = 30 * time.Second
+
+	// GlobalBreakerMaxCooldown is the longest time the global breaker stays open before another trial.
+	GlobalBreakerMaxCooldown = 4 * time.Minute
+
+	// GlobalBreakerName identifies the global breaker in the BreakerStatus values.
+	GlobalBreakerName = "Global"
+)
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+// The states of a circuit breaker.
+const (
+	// BreakerClosed permits the requests
+	BreakerClosed BreakerState = iota
+	// BreakerOpen refuses the requests until the cooldown has passed
+	BreakerOpen
+	// BreakerHalfOpen permits a single trial request that decides whether the breaker closes again
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "OPEN"
+	case BreakerHalfOpen:
+		return "HALF-OPEN"
+	}
+	return "CLOSED"
+}
+
+// BreakerStatus describes a circuit breaker at a point in time.
+type BreakerStatus struct {
+	Source string
+	State  BreakerState
+	// Since is when the breaker entered the current state
+	Since time.Time
+	// Opened is when the breaker last opened after being closed, or the zero time
+	Opened time.Time
+	// Trips counts the times the breaker opened after being closed
+	Trips int
+	// Failures is the number of consecutive failed requests
+	Failures int
+	// LastError describes the most recent failure
+	LastError string
+}
+
+// Tripped returns true when the breaker has opened at some point.
+func (s BreakerStatus) Tripped() bool {
+	return s.Trips > 0
+}
+
+// String describes the breaker, such as "NetworksDB: OPEN (12m, last error 403)".
+func (s BreakerStatus) String() string {
+	if s.State == BreakerClosed {
+		if s.Trips == 0 {
+			return fmt.Sprintf("%s: %s", s.Source, s.State)
+		}
+		return fmt.Sprintf("%s: %s (opened %d times, last error %s)", s.Source, s.State, s.Trips, s.LastError)
+	}
+
+	open := time.Since(s.Opened)
+	if open < time.Minute {
+		open = open.Round(time.Second)
+	} else {
+		open = open.Round(time.Minute)
+	}
+	return fmt.Sprintf("%s: %s (%s, last error %s)", s.Source, s.State, shortDuration(open), s.LastError)
+}
+
+func shortDuration(d time.Duration) string {
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	} else if d >= time.Minute {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
+
+// BreakerOpenError is returned when a circuit breaker refuses a request.
+type BreakerOpenError struct {
+	Source string
+	Until  time.Time
+}
+
+func (e *BreakerOpenError) Error() string {
+	return fmt.Sprintf("The circuit breaker for %s is open", e.Source)
+}
+
+type breaker struct {
+	status      BreakerStatus
+	threshold   int
+	cooldown    time.Duration
+	base        time.Duration
+	maxCooldown time.Duration
+	trial       bool
+}
+
+func newBreaker(name string, threshold int, cooldown, max time.Duration) *breaker {
+	return &breaker{
+		status:      BreakerStatus{Source: name},
+		threshold:   threshold,
+		cooldown:    cooldown,
+		base:        cooldown,
+		maxCooldown: max,
+	}
+}
+
+// allow must be called while holding the lock of the CircuitBreakers.
+func (b *breaker) allow(now time.Time) error {
+	switch b.status.State {
+	case BreakerOpen:
+		until := b.status.Since.Add(b.cooldown)
+		if now.Before(until) {
+			return &BreakerOpenError{Source: b.status.Source, Until: until}
+		}
+
+		b.setState(BreakerHalfOpen, now)
+		b.trial = true
+		return nil
+	case BreakerHalfOpen:
+		// Only the trial request is sent until it completes
+		if b.trial {
+			return &BreakerOpenError{Source: b.status.Source, Until: now.Add(b.cooldown)}
+		}
+
+		b.trial = true
+	}
+	return nil
+}
+
+// record must be called while holding the lock of the CircuitBreakers.
+func (b *breaker) record(now time.Time, reason string) {
+	if reason == "" {
+		b.status.Failures = 0
+		b.cooldown = b.base
+		b.trial = false
+		if b.status.State != BreakerClosed {
+			b.setState(BreakerClosed, now)
+		}
+		return
+	}
+
+	b.status.Failures++
+	b.status.LastError = reason
+	switch b.status.State {
+	case BreakerHalfOpen:
+		// The trial failed, so the breaker waits longer before the next one
+		b.trial = false
+		if b.cooldown *= 2; b.cooldown > b.maxCooldown {
+			b.cooldown = b.maxCooldown
+		}
+		b.setState(BreakerOpen, now)
+	case BreakerClosed:
+		if b.status.Failures >= b.threshold {
+			b.status.Trips++
+			b.status.Opened = now
+			b.setState(BreakerOpen, now)
+		}
+	}
+}
+
+func (b *breaker) setState(state BreakerState, now time.Time) {
+	b.status.State = state
+	b.status.Since = now
+}
+
+// CircuitBreakers stop the requests to data sources that keep failing, and to all the data sources
+// when the failures span all of them. After the cooldown, a single trial request is permitted to
+// find out if the data source has recovered, which is known as the half-open state.
+type CircuitBreakers struct {
+	sync.Mutex
+	global   *breaker
+	breakers map[string]*breaker
+	now      func() time.Time
+}
+
+// NewCircuitBreakers returns CircuitBreakers that are all closed.
+func NewCircuitBreakers() *CircuitBreakers {
+	return &CircuitBreakers{
+		global:   newBreaker(GlobalBreakerName, GlobalBreakerThreshold, GlobalBreakerCooldown, GlobalBreakerMaxCooldown),
+		breakers: make(map[string]*breaker),
+		now:      time.Now,
+	}
+}
+
+// must be called while holding the lock
+func (c *CircuitBreakers) entry(source string) *breaker {
+	b, found := c.breakers[source]
+	if !found {
+		b = newBreaker(source, BreakerThreshold, BreakerCooldown, BreakerMaxCooldown)
+		c.breakers[source] = b
+	}
+	return b
+}
+
+// Allow returns a BreakerOpenError when the request of the data source should not be sent.
+// Each permitted request must be followed by a call to Record with the outcome.
+func (c *CircuitBreakers) Allow(source string) error {
+	if source == "" {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	now := c.now()
+	if err := c.global.allow(now); err != nil {
+		return err
+	}
+
+	b := c.entry(source)
+	if err := b.allow(now); err != nil {
+		// The global trial is released, since the request was not sent
+		c.global.trial = false
+		return err
+	}
+	return nil
+}
+
+// Record provides the outcome of a request permitted by Allow. The reason describes the failure,
+// such as the response status code, and is empty when the request succeeded. The breakers that
+// changed state as a result are returned.
+func (c *CircuitBreakers) Record(source, reason string) []BreakerStatus {
+	if source == "" {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	var changed []BreakerStatus
+	now := c.now()
+	for _, b := range []*breaker{c.global, c.entry(source)} {
+		state := b.status.State
+
+		if b.record(now, reason); b.status.State != state {
+			changed = append(changed, b.status)
+		}
+	}
+	return changed
+}
+
+// Status returns the state of the global breaker, followed by the data source breakers sorted by name.
+func (c *CircuitBreakers) Status() []BreakerStatus {
+	if c == nil {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	status := []BreakerStatus{c.global.status}
+	var sources []BreakerStatus
+	for _, b := range c.breakers {
+		sources = append(sources, b.status)
+	}
+
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Source < sources[j].Source })
+	return append(status, sources...)
+}
+
+// Tripped returns the breakers that have opened at some point, including the global breaker.
+func (c *CircuitBreakers) Tripped() []BreakerStatus {
+	var tripped []BreakerStatus
+
+	for _, s := range c.Status() {
+		if s.Tripped() {
+			tripped = append(tripped, s)
+		}
+	}
+	return tripped
+}
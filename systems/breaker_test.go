@@ -0,0 +1,121 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package systems
+
+import (
+	"testing"
+	"time"
+)
+
+// The Systems provided by the package must keep offering the optional interfaces
+var (
+	_ BreakerSystem     = (*LocalSystem)(nil)
+	_ BreakerSystem     = (*MockSystem)(nil)
+	_ SourceStatsSystem = (*LocalSystem)(nil)
+	_ SourceStatsSystem = (*MockSystem)(nil)
+)
+
+func TestCircuitBreakers(t *testing.T) {
+	now := time.Now()
+	c := NewCircuitBreakers()
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < BreakerThreshold-1; i++ {
+		if err := c.Allow("NetworksDB"); err != nil {
+			t.Fatalf("The closed breaker refused request %d: %v", i+1, err)
+		}
+		c.Record("NetworksDB", "403")
+	}
+	if err := c.Allow("NetworksDB"); err != nil {
+		t.Fatalf("The breaker opened before the threshold: %v", err)
+	}
+	if changed := c.Record("NetworksDB", "403"); len(changed) != 1 || changed[0].State != BreakerOpen {
+		t.Errorf("The breaker did not report that it opened: %v", changed)
+	}
+
+	if err := c.Allow("NetworksDB"); err == nil {
+		t.Errorf("The open breaker permitted a request")
+	}
+	if err := c.Allow("Crtsh"); err != nil {
+		t.Errorf("The breaker of another data source refused a request: %v", err)
+	}
+	c.Record("Crtsh", "")
+
+	// After the cooldown, only a single trial request is permitted
+	now = now.Add(BreakerCooldown)
+	if err := c.Allow("NetworksDB"); err != nil {
+		t.Fatalf("The breaker did not permit the trial request: %v", err)
+	}
+	if err := c.Allow("NetworksDB"); err == nil {
+		t.Errorf("The half-open breaker permitted a second request")
+	}
+
+	// The failed trial doubles the cooldown
+	c.Record("NetworksDB", "403")
+	now = now.Add(BreakerCooldown)
+	if err := c.Allow("NetworksDB"); err == nil {
+		t.Errorf("The breaker permitted a request before the doubled cooldown")
+	}
+	now = now.Add(BreakerCooldown)
+	if err := c.Allow("NetworksDB"); err != nil {
+		t.Fatalf("The breaker did not permit the trial after the doubled cooldown: %v", err)
+	}
+	c.Record("NetworksDB", "")
+
+	status := c.Status()
+	if len(status) != 3 || status[0].Source != GlobalBreakerName || status[1].Source != "Crtsh" {
+		t.Fatalf("Unexpected breaker status: %v", status)
+	}
+	if n := status[2]; n.State != BreakerClosed || n.Trips != 1 || n.LastError != "403" {
+		t.Errorf("Unexpected status for NetworksDB: %+v", n)
+	}
+	if tripped := c.Tripped(); len(tripped) != 1 || tripped[0].Source != "NetworksDB" {
+		t.Errorf("Unexpected tripped breakers: %v", tripped)
+	}
+}
+
+func TestGlobalCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	c := NewCircuitBreakers()
+	c.now = func() time.Time { return now }
+
+	// Failures spread across the data sources never reach the threshold of a single one
+	sources := []string{"A", "B", "C", "D", "E", "F", "G"}
+	for i := 0; i < GlobalBreakerThreshold; i++ {
+		src := sources[i%len(sources)]
+
+		if err := c.Allow(src); err != nil {
+			t.Fatalf("Request %d was refused: %v", i+1, err)
+		}
+		c.Record(src, "connection failed")
+	}
+
+	if err := c.Allow("H"); err == nil {
+		t.Errorf("The open global breaker permitted a request")
+	}
+
+	now = now.Add(GlobalBreakerCooldown)
+	if err := c.Allow("H"); err != nil {
+		t.Fatalf("The global breaker did not permit the trial request: %v", err)
+	}
+	c.Record("H", "")
+	if err := c.Allow("A"); err != nil {
+		t.Errorf("The global breaker did not close after the successful trial: %v", err)
+	}
+
+	if s := c.Status()[0]; s.State != BreakerClosed || s.Trips != 1 {
+		t.Errorf("Unexpected status for the global breaker: %+v", s)
+	}
+}
+
+func TestBreakerStatusString(t *testing.T) {
+	s := BreakerStatus{Source: "NetworksDB", State: BreakerOpen, Opened: time.Now().Add(-12 * time.Minute), LastError: "403"}
+
+	if got := s.String(); got != "NetworksDB: OPEN (12m, last error 403)" {
+		t.Errorf("The status was described as %q", got)
+	}
+	if got := (BreakerStatus{Source: "Crtsh"}).String(); got != "Crtsh: CLOSED" {
+		t.Errorf("The closed status was described as %q", got)
+	}
+}
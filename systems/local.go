@@ -7,11 +7,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"runtime"
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/graph"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	amasshttp "github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/resolvers"
 	"golang.org/x/sync/semaphore"
@@ -19,9 +22,11 @@ import (
 
 // LocalSystem implements a System to be executed within a single process.
 type LocalSystem struct {
-	cfg    *config.Config
-	pool   resolvers.Resolver
-	graphs []*graph.Graph
+	cfg      *config.Config
+	pool     resolvers.Resolver
+	graphs   []*graph.Graph
+	stats    *SourceStatsTracker
+	breakers *CircuitBreakers
 
 	// Semaphore to enforce the maximum DNS queries
 	semMaxDNSQueries *semaphore.Weighted
@@ -39,15 +44,25 @@ func NewLocalSystem(c *config.Config) (*LocalSystem, error) {
 	if err := c.CheckSettings(); err != nil {
 		return nil, err
 	}
+	// The traffic needs to be bound before the resolvers establish connections
+	if err := setupNetworkBinding(c); err != nil {
+		return nil, err
+	}
+	if err := setupCassette(c); err != nil {
+		return nil, err
+	}
 
 	pool := resolvers.SetupResolverPool(c.Resolvers, c.MaxDNSQueries, c.MonitorResolverRate, c.Log)
 	if pool == nil {
 		return nil, errors.New("The system was unable to build the pool of resolvers")
 	}
+	setupStaticWildcards(pool, c)
 
 	sys := &LocalSystem{
 		cfg:              c,
 		pool:             pool,
+		stats:            NewSourceStatsTracker(),
+		breakers:         NewCircuitBreakers(),
 		done:             make(chan struct{}, 2),
 		addSource:        make(chan requests.Service, 10),
 		allSources:       make(chan chan []requests.Service, 10),
@@ -70,6 +85,63 @@ func NewLocalSystem(c *config.Config) (*LocalSystem, error) {
 	return sys, nil
 }
 
+func setupNetworkBinding(c *config.Config) error {
+	// The command-line options take precedence over the configuration file
+	if amassnet.LocalAddr == nil {
+		if c.SourceAddress != nil {
+			amassnet.LocalAddr = amassnet.SourceAddr(c.SourceAddress)
+		} else if c.Interface != "" {
+			iface, err := net.InterfaceByName(c.Interface)
+			if err != nil {
+				return fmt.Errorf("Failed to find the network interface '%s': %v", c.Interface, err)
+			}
+
+			addr, err := amassnet.InterfaceAddr(iface)
+			if err != nil {
+				return err
+			}
+			amassnet.LocalAddr = addr
+		}
+	}
+
+	if c.DNSPortMin > 0 {
+		amassnet.DNSPortRange = [2]int{c.DNSPortMin, c.DNSPortMax}
+	}
+	return nil
+}
+
+func setupCassette(c *config.Config) error {
+	// A cassette that was already installed, such as from the environment variables, takes precedence
+	if c.HTTPCassette == "" || amasshttp.ActiveCassette() != nil {
+		return nil
+	}
+
+	cassette, err := amasshttp.NewCassette(c.HTTPCassette, c.HTTPCassetteMode)
+	if err != nil {
+		return err
+	}
+
+	amasshttp.UseCassette(cassette)
+	return nil
+}
+
+func setupStaticWildcards(pool *resolvers.ResolverPool, c *config.Config) {
+	if len(c.Wildcards) == 0 {
+		return
+	}
+
+	var wildcards []*resolvers.StaticWildcard
+	for _, w := range c.Wildcards {
+		wildcards = append(wildcards, &resolvers.StaticWildcard{
+			Zone:      w.Zone,
+			Netblocks: w.Netblocks,
+			Patterns:  w.Patterns,
+		})
+		c.Log.Printf("DNS wildcard declared for %s: %d netblocks and %d patterns", "*."+w.Zone, len(w.Netblocks), len(w.Patterns))
+	}
+	pool.SetStaticWildcards(wildcards)
+}
+
 // Config implements the System interface.
 func (l *LocalSystem) Config() *config.Config {
 	return l.cfg
@@ -117,6 +189,16 @@ func (l *LocalSystem) GraphDatabases() []*graph.Graph {
 	return l.graphs
 }
 
+// SourceStats implements the SourceStatsSystem interface.
+func (l *LocalSystem) SourceStats() *SourceStatsTracker {
+	return l.stats
+}
+
+// CircuitBreakers implements the BreakerSystem interface.
+func (l *LocalSystem) CircuitBreakers() *CircuitBreakers {
+	return l.breakers
+}
+
 // Shutdown implements the System interface.
 func (l *LocalSystem) Shutdown() error {
 	if l.doneAlreadyClosed {
@@ -173,12 +255,12 @@ func (l *LocalSystem) setupGraphDBs() error {
 	dbs = append(dbs, cfg.GraphDBs...)
 
 	for _, db := range dbs {
-		cayley := graph.NewCayleyGraph(db.System, db.URL, db.Options)
-		if cayley == nil {
-			return fmt.Errorf("System: Failed to create the %s graph", db.System)
+		gdb, err := graph.NewGraphDatabase(db.System, db.URL, db.Username, db.Password, db.DBName, db.Options)
+		if err != nil {
+			return fmt.Errorf("System: Failed to create the %s graph: %v", db.System, err)
 		}
 
-		g := graph.NewGraph(cayley)
+		g := graph.NewGraph(gdb)
 		if g == nil {
 			return fmt.Errorf("System: Failed to create the %s graph", g.String())
 		}
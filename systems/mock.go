@@ -0,0 +1,250 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package systems
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/graph"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/resolvers"
+	"golang.org/x/sync/semaphore"
+)
+
+// MockSystem implements a System that does not access the network, for the unit tests
+// of projects embedding Amass. The DNS answers are provided by a MockResolver, the
+// findings are stored in an in-memory graph, and a MockDataSource is already added.
+type MockSystem struct {
+	sync.Mutex
+	cfg      *config.Config
+	resolver *resolvers.MockResolver
+	source   *MockDataSource
+	graphs   []*graph.Graph
+	stats    *SourceStatsTracker
+	breakers *CircuitBreakers
+	sources  []requests.Service
+	sem      *semaphore.Weighted
+	shutdown bool
+}
+
+// NewMockSystem returns an initialized MockSystem. A default configuration is used when c is nil.
+func NewMockSystem(c *config.Config) (*MockSystem, error) {
+	if c == nil {
+		c = config.NewConfig()
+	}
+
+	max := c.MaxDNSQueries
+	if max <= 0 {
+		max = 1
+	}
+
+	sys := &MockSystem{
+		cfg:      c,
+		resolver: resolvers.NewMockResolver(),
+		graphs:   []*graph.Graph{graph.NewGraph(graph.NewCayleyGraphMemory())},
+		stats:    NewSourceStatsTracker(),
+		breakers: NewCircuitBreakers(),
+		sem:      semaphore.NewWeighted(int64(max)),
+	}
+
+	sys.source = NewMockDataSource("Mock")
+	if err := sys.AddAndStart(sys.source); err != nil {
+		sys.Shutdown()
+		return nil, err
+	}
+	return sys, nil
+}
+
+// Resolver returns the MockResolver that answers the DNS queries of the system.
+func (m *MockSystem) Resolver() *resolvers.MockResolver {
+	return m.resolver
+}
+
+// Source returns the MockDataSource added to the system by NewMockSystem.
+func (m *MockSystem) Source() *MockDataSource {
+	return m.source
+}
+
+// Graph returns the in-memory graph that stores the findings of the system.
+func (m *MockSystem) Graph() *graph.Graph {
+	return m.graphs[0]
+}
+
+// Config implements the System interface.
+func (m *MockSystem) Config() *config.Config {
+	return m.cfg
+}
+
+// Pool implements the System interface.
+func (m *MockSystem) Pool() resolvers.Resolver {
+	return m.resolver
+}
+
+// AddSource implements the System interface.
+func (m *MockSystem) AddSource(srv requests.Service) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.sources = append(m.sources, srv)
+	return nil
+}
+
+// AddAndStart implements the System interface.
+func (m *MockSystem) AddAndStart(srv requests.Service) error {
+	err := srv.Start()
+
+	if err == nil {
+		return m.AddSource(srv)
+	}
+	return err
+}
+
+// DataSources implements the System interface.
+func (m *MockSystem) DataSources() []requests.Service {
+	m.Lock()
+	defer m.Unlock()
+
+	return append([]requests.Service(nil), m.sources...)
+}
+
+// SetDataSources implements the System interface.
+func (m *MockSystem) SetDataSources(sources []requests.Service) {
+	for _, src := range sources {
+		m.AddAndStart(src)
+	}
+}
+
+// GraphDatabases implements the System interface.
+func (m *MockSystem) GraphDatabases() []*graph.Graph {
+	return m.graphs
+}
+
+// SourceStats implements the SourceStatsSystem interface.
+func (m *MockSystem) SourceStats() *SourceStatsTracker {
+	return m.stats
+}
+
+// CircuitBreakers implements the BreakerSystem interface.
+func (m *MockSystem) CircuitBreakers() *CircuitBreakers {
+	return m.breakers
+}
+
+// GetMemoryUsage implements the System interface.
+func (m *MockSystem) GetMemoryUsage() uint64 {
+	var mem runtime.MemStats
+
+	runtime.ReadMemStats(&mem)
+	return mem.Alloc
+}
+
+// PerformDNSQuery implements the System interface.
+func (m *MockSystem) PerformDNSQuery(ctx context.Context) error {
+	return m.sem.Acquire(ctx, 1)
+}
+
+// FinishedDNSQuery implements the System interface.
+func (m *MockSystem) FinishedDNSQuery() {
+	m.sem.Release(1)
+}
+
+// Shutdown implements the System interface.
+func (m *MockSystem) Shutdown() error {
+	m.Lock()
+	if m.shutdown {
+		m.Unlock()
+		return nil
+	}
+	m.shutdown = true
+	m.Unlock()
+
+	for _, src := range m.DataSources() {
+		src.Stop()
+	}
+	for _, g := range m.graphs {
+		g.Close()
+	}
+	return m.resolver.Stop()
+}
+
+// MockDataSource is a data source that returns the names provided in advance
+// instead of querying a third-party service.
+type MockDataSource struct {
+	requests.BaseService
+
+	sync.Mutex
+	names    []string
+	requests []*requests.DNSRequest
+}
+
+// NewMockDataSource returns a MockDataSource with the name and subdomain names provided.
+func NewMockDataSource(name string, names ...string) *MockDataSource {
+	m := &MockDataSource{}
+
+	m.BaseService = *requests.NewBaseService(m, name)
+	m.AddNames(names...)
+	return m
+}
+
+// Type implements the Service interface.
+func (m *MockDataSource) Type() string {
+	return requests.API
+}
+
+// AddNames provides subdomain names that the data source will return for the matching domains.
+func (m *MockDataSource) AddNames(names ...string) {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, name := range names {
+		if n := strings.ToLower(strings.Trim(strings.TrimSpace(name), ".")); n != "" {
+			m.names = append(m.names, n)
+		}
+	}
+}
+
+// Requests returns the DNS requests received by the data source, in the order received.
+func (m *MockDataSource) Requests() []*requests.DNSRequest {
+	m.Lock()
+	defer m.Unlock()
+
+	return append([]*requests.DNSRequest(nil), m.requests...)
+}
+
+// OnDNSRequest implements the Service interface.
+func (m *MockDataSource) OnDNSRequest(ctx context.Context, req *requests.DNSRequest) {
+	if req == nil || req.Domain == "" {
+		return
+	}
+
+	var bus *eventbus.EventBus
+	if b := ctx.Value(requests.ContextEventBus); b != nil {
+		bus, _ = b.(*eventbus.EventBus)
+	}
+
+	m.Lock()
+	m.requests = append(m.requests, req)
+	names := append([]string(nil), m.names...)
+	m.Unlock()
+
+	if bus == nil {
+		return
+	}
+
+	domain := strings.ToLower(req.Domain)
+	for _, name := range names {
+		if name == domain || strings.HasSuffix(name, "."+domain) {
+			bus.Publish(requests.NewNameTopic, eventbus.PriorityHigh, &requests.DNSRequest{
+				Name:   name,
+				Domain: domain,
+				Tag:    m.Type(),
+				Source: m.String(),
+			})
+		}
+	}
+}
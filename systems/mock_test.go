@@ -0,0 +1,68 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package systems
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/eventbus"
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestMockSystem(t *testing.T) {
+	var _ System = (*MockSystem)(nil)
+
+	sys, err := NewMockSystem(nil)
+	if err != nil {
+		t.Fatalf("Failed to create the mock system: %v", err)
+	}
+	defer sys.Shutdown()
+
+	if srcs := sys.DataSources(); len(srcs) != 1 || srcs[0] != sys.Source() {
+		t.Errorf("The mock data source was not added to the system")
+	}
+	if dbs := sys.GraphDatabases(); len(dbs) != 1 || dbs[0] != sys.Graph() {
+		t.Errorf("The in-memory graph was not provided by the system")
+	}
+
+	sys.Resolver().AddAnswer("www.example.com", "A", "192.0.2.1")
+	ans, _, err := sys.Pool().Resolve(context.Background(), "www.example.com.", "A", 0)
+	if err != nil || len(ans) != 1 || ans[0].Data != "192.0.2.1" {
+		t.Errorf("Resolve returned %v, %v for the provided answer", ans, err)
+	}
+	if _, _, err := sys.Pool().Resolve(context.Background(), "ftp.example.com", "A", 0); err == nil {
+		t.Errorf("Resolve did not return an error for a name without an answer")
+	}
+
+	bus := eventbus.NewEventBus()
+	defer bus.Stop()
+	rec := eventbus.NewRecorder(bus, requests.NewNameTopic)
+	time.Sleep(100 * time.Millisecond)
+
+	ctx := context.WithValue(context.Background(), requests.ContextConfig, sys.Config())
+	ctx = context.WithValue(ctx, requests.ContextEventBus, bus)
+
+	sys.Source().AddNames("www.example.com", "mail.example.com", "www.owasp.org")
+	sys.Source().DNSRequest(ctx, &requests.DNSRequest{Name: "example.com", Domain: "example.com"})
+
+	if !rec.Wait(requests.NewNameTopic, 2, 5*time.Second) {
+		t.Fatalf("The mock data source published %d names instead of 2", rec.Len(requests.NewNameTopic))
+	}
+	for _, msg := range rec.Messages(requests.NewNameTopic) {
+		req := msg[0].(*requests.DNSRequest)
+
+		if req.Domain != "example.com" || req.Source != "Mock" || req.Tag != requests.API {
+			t.Errorf("Unexpected request published by the mock data source: %+v", req)
+		}
+	}
+	if reqs := sys.Source().Requests(); len(reqs) != 1 {
+		t.Errorf("The mock data source recorded %d requests instead of 1", len(reqs))
+	}
+
+	if _, err := sys.Graph().InsertFQDN("www.example.com", "Mock", requests.API, "test"); err != nil {
+		t.Errorf("Failed to insert into the in-memory graph: %v", err)
+	}
+}
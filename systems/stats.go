@@ -0,0 +1,98 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package systems
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// SourceStats contains the activity of a data source during an enumeration.
+type SourceStats struct {
+	Source   string
+	Requests int
+	Errors   int
+	// Names counts every name the data source provided, including duplicates
+	Names int
+	// Unique counts the names that no other data source provided
+	Unique  int
+	Elapsed time.Duration
+}
+
+// SourceStatsTracker collects the SourceStats for the data sources managed by a System.
+type SourceStatsTracker struct {
+	sync.Mutex
+	stats map[string]*SourceStats
+}
+
+// NewSourceStatsTracker returns an empty SourceStatsTracker.
+func NewSourceStatsTracker() *SourceStatsTracker {
+	return &SourceStatsTracker{stats: make(map[string]*SourceStats)}
+}
+
+// must be called while holding the lock
+func (t *SourceStatsTracker) entry(source string) *SourceStats {
+	s, found := t.stats[source]
+	if !found {
+		s = &SourceStats{Source: source}
+		t.stats[source] = s
+	}
+	return s
+}
+
+// AddRequest records a request made by the data source, how long it took and whether it failed.
+func (t *SourceStatsTracker) AddRequest(source string, elapsed time.Duration, err error) {
+	if source == "" {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	s := t.entry(source)
+	s.Requests++
+	s.Elapsed += elapsed
+	if err != nil {
+		s.Errors++
+	}
+}
+
+// AddName records a name provided by the data source.
+func (t *SourceStatsTracker) AddName(source string) {
+	if source == "" {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.entry(source).Names++
+}
+
+// SetUnique assigns the number of names that only the data source provided.
+func (t *SourceStatsTracker) SetUnique(source string, unique int) {
+	if source == "" {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.entry(source).Unique = unique
+}
+
+// Stats returns copies of the collected SourceStats, sorted by the data source names.
+func (t *SourceStatsTracker) Stats() []SourceStats {
+	t.Lock()
+	defer t.Unlock()
+
+	stats := make([]SourceStats, 0, len(t.stats))
+	for _, s := range t.stats {
+		stats = append(stats, *s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Source < stats[j].Source })
+	return stats
+}
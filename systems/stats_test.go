@@ -0,0 +1,35 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package systems
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSourceStatsTracker(t *testing.T) {
+	tracker := NewSourceStatsTracker()
+
+	tracker.AddRequest("Crtsh", time.Second, nil)
+	tracker.AddRequest("Crtsh", 2*time.Second, errors.New("failed"))
+	tracker.AddRequest("", time.Second, nil)
+	tracker.AddName("Crtsh")
+	tracker.AddName("Crtsh")
+	tracker.AddName("AlienVault")
+	tracker.SetUnique("Crtsh", 1)
+
+	stats := tracker.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("Stats returned %d entries, expected 2", len(stats))
+	}
+	if stats[0].Source != "AlienVault" || stats[0].Names != 1 || stats[0].Requests != 0 {
+		t.Errorf("Unexpected statistics for AlienVault: %+v", stats[0])
+	}
+
+	c := stats[1]
+	if c.Requests != 2 || c.Errors != 1 || c.Names != 2 || c.Unique != 1 || c.Elapsed != 3*time.Second {
+		t.Errorf("Unexpected statistics for Crtsh: %+v", c)
+	}
+}
@@ -45,3 +45,15 @@ type System interface {
 	// Shutdown will shutdown the System
 	Shutdown() error
 }
+
+// SourceStatsSystem is implemented by the Systems that collect the activity of the data sources.
+type SourceStatsSystem interface {
+	// SourceStats returns the tracker that collects the activity of the data sources
+	SourceStats() *SourceStatsTracker
+}
+
+// BreakerSystem is implemented by the Systems that stop sending requests to failing data sources.
+type BreakerSystem interface {
+	// CircuitBreakers returns the breakers that stop the requests to failing data sources
+	CircuitBreakers() *CircuitBreakers
+}
@@ -4,11 +4,14 @@
 package viz
 
 import (
+	"bufio"
+	"encoding/json"
+	"fmt"
 	"io"
-	"text/template"
 )
 
-const d3Template = `
+// The beginning of the HTML page, up to the nodes of the D3 graph
+const d3Header = `
 <!DOCTYPE html>
 <html lang="en">
 <head>
@@ -40,17 +43,22 @@ const d3Template = `
 
 var graph = {
     nodes: [
-    {{ range .Nodes }}
-        {id: {{.ID }}, num: {{ .Num }}, label: "{{ .Label }}", color: "{{ .Color }}" },
-    {{ end }}
-    ],
+`
+
+// Separates the nodes from the edges within the D3 graph
+const d3Middle = `    ],
     edges: [
-    {{ range .Edges }}
-        {source: {{ .Source }}, target: {{ .Destination }}, label: "{{ .Label }}" },
-    {{ end }}
-    ]
+`
+
+// The node sizes are computed from the edges once the page has been loaded
+const d3Footer = `    ]
 };
 
+graph.edges.forEach(function(e) {
+    graph.nodes[e.source].num++;
+    graph.nodes[e.target].num++;
+});
+
 var graphWidth = window.innerWidth,
     graphHeight = window.innerHeight;
 
@@ -68,7 +76,7 @@ var div = d3.select("body").append("div")
     .style("opacity", 0);
 
 var r = 5,
-    max = {{ .MaxNum }},
+    max = d3.max(graph.nodes, function(n) { return n.num; }) || 1,
     simulation = d3.forceSimulation()
         .nodes(graph.nodes)
         .force("link", d3.forceLink()
@@ -256,70 +264,96 @@ update();
 </html>
 `
 
-type d3Edge struct {
-	Source      int
-	Destination int
-	Label       string
+var d3Colors = map[string]string{
+	"subdomain": "green",
+	"domain":    "red",
+	"address":   "orange",
+	"ptr":       "yellow",
+	"ns":        "cyan",
+	"mx":        "purple",
+	"netblock":  "pink",
+	"as":        "blue",
 }
 
-type d3Node struct {
-	ID    int
-	Num   int
-	Label string
-	Color string
+type d3Writer struct {
+	bufwr   *bufio.Writer
+	events  map[string]int
+	started bool
+	edges   bool
 }
 
-type d3Graph struct {
-	Name   string
-	MaxNum int
-	Nodes  []d3Node
-	Edges  []d3Edge
+// NewD3Writer returns a Writer that streams a HTML file displaying the Amass graph using D3. The events
+// are the enumerations included in the visualization, which color the nodes when there are several.
+func NewD3Writer(output io.Writer, events []string) Writer {
+	return &d3Writer{
+		bufwr:  bufio.NewWriter(output),
+		events: eventIndices(events),
+	}
 }
 
-// WriteD3Data generates a HTML file that displays the Amass graph using D3.
-func WriteD3Data(output io.Writer, nodes []Node, edges []Edge) {
-	colors := map[string]string{
-		"subdomain": "green",
-		"domain":    "red",
-		"address":   "orange",
-		"ptr":       "yellow",
-		"ns":        "cyan",
-		"mx":        "purple",
-		"netblock":  "pink",
-		"as":        "blue",
+func (d *d3Writer) start() {
+	if !d.started {
+		d.started = true
+		d.bufwr.WriteString(d3Header)
+	}
+}
+
+// WriteNode implements the Writer interface.
+func (d *d3Writer) WriteNode(n *Node) error {
+	d.start()
+
+	label := n.Title
+	if n.Source != "" {
+		label += ", Source: " + n.Source
 	}
 
-	graph := &d3Graph{Name: "OWASP Amass - Attack Surface Mapping"}
+	color := d3Colors[n.Type]
+	// Nodes are colored by enumeration when multiple events are included
+	if d.events != nil {
+		color = eventColorNames[d.events[n.Event]%len(eventColorNames)]
+		label += ", Event: " + n.Event
+	}
 
-	for idx, node := range nodes {
-		label := node.Title
-		if node.Source != "" {
-			label += ", Source: " + node.Source
-		}
+	_, err := fmt.Fprintf(d.bufwr, "        {id: %d, num: 0, label: %s, color: %q },\n", n.ID, jsString(label), color)
+	return err
+}
 
-		graph.Nodes = append(graph.Nodes, d3Node{
-			ID:    idx,
-			Label: label,
-			Color: colors[node.Type],
-		})
+// WriteEdge implements the Writer interface.
+func (d *d3Writer) WriteEdge(e *Edge) error {
+	d.start()
+	if !d.edges {
+		d.edges = true
+		d.bufwr.WriteString(d3Middle)
 	}
 
-	for _, edge := range edges {
-		graph.Edges = append(graph.Edges, d3Edge{
-			Source:      edge.From,
-			Destination: edge.To,
-			Label:       edge.Title,
-		})
-		graph.Nodes[edge.From].Num++
-		graph.Nodes[edge.To].Num++
+	_, err := fmt.Fprintf(d.bufwr, "        {source: %d, target: %d, label: %s },\n", e.From, e.To, jsString(e.Title))
+	return err
+}
+
+// Close implements the Writer interface.
+func (d *d3Writer) Close() error {
+	d.start()
+	if !d.edges {
+		d.edges = true
+		d.bufwr.WriteString(d3Middle)
 	}
 
-	for _, node := range graph.Nodes {
-		if node.Num > graph.MaxNum {
-			graph.MaxNum = node.Num
-		}
+	if _, err := d.bufwr.WriteString(d3Footer); err != nil {
+		return err
 	}
+	return d.bufwr.Flush()
+}
 
-	t := template.Must(template.New("graph").Parse(d3Template))
-	t.Execute(output, graph)
+// WriteD3Data generates a HTML file that displays the Amass graph using D3.
+func WriteD3Data(output io.Writer, nodes []Node, edges []Edge) {
+	_ = writeAll(NewD3Writer(output, nodeEvents(nodes)), nodes, edges)
+}
+
+// jsString returns the value as a JavaScript string literal.
+func jsString(value string) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return `""`
+	}
+	return string(b)
 }
@@ -4,78 +4,86 @@
 package viz
 
 import (
+	"bufio"
+	"fmt"
 	"io"
-	"strconv"
-	"text/template"
 )
 
-const dotTemplate = `
-digraph "{{ .Name }}" {
-	size = "7.5,10"; ranksep="2.5 equally"; ratio=auto;
-
-{{ range .Nodes }}
-        node [label="{{ .Label }}",color="{{ .Color }}",type="{{ .Type }}",source="{{ .Source }}"]; n{{ .ID }};
-{{ end }}
-
-{{ range .Edges }}
-        n{{ .Source }} -> n{{ .Destination }} [label="{{ .Label }}"];
-{{ end }}
+var dotColors = map[string]string{
+	"subdomain": "green",
+	"domain":    "red",
+	"address":   "orange",
+	"ptr":       "yellow",
+	"ns":        "cyan",
+	"mx":        "purple",
+	"netblock":  "pink",
+	"as":        "blue",
 }
-`
 
-type dotEdge struct {
-	Source      string
-	Destination string
-	Label       string
+type dotWriter struct {
+	bufwr   *bufio.Writer
+	events  map[string]int
+	started bool
+	edges   bool
 }
 
-type dotNode struct {
-	ID     string
-	Label  string
-	Color  string
-	Type   string
-	Source string
+// NewDOTWriter returns a Writer that streams a DOT file to display the Amass graph. The events
+// are the enumerations included in the visualization, which color the nodes when there are several.
+func NewDOTWriter(output io.Writer, events []string) Writer {
+	return &dotWriter{
+		bufwr:  bufio.NewWriter(output),
+		events: eventIndices(events),
+	}
 }
 
-type dotGraph struct {
-	Name  string
-	Nodes []dotNode
-	Edges []dotEdge
+func (d *dotWriter) start() {
+	if !d.started {
+		d.started = true
+		fmt.Fprint(d.bufwr, "\ndigraph \"OWASP Amass Network Mapping\" {\n")
+		fmt.Fprint(d.bufwr, "\tsize = \"7.5,10\"; ranksep=\"2.5 equally\"; ratio=auto;\n\n")
+	}
 }
 
-// WriteDOTData generates a DOT file to display the Amass graph.
-func WriteDOTData(output io.Writer, nodes []Node, edges []Edge) {
-	colors := map[string]string{
-		"subdomain": "green",
-		"domain":    "red",
-		"address":   "orange",
-		"ptr":       "yellow",
-		"ns":        "cyan",
-		"mx":        "purple",
-		"netblock":  "pink",
-		"as":        "blue",
+// WriteNode implements the Writer interface.
+func (d *dotWriter) WriteNode(n *Node) error {
+	d.start()
+
+	color := dotColors[n.Type]
+	var event string
+	// Merged visualizations are colored by enumeration
+	if d.events != nil {
+		color = eventColorNames[d.events[n.Event]%len(eventColorNames)]
+		event = ",event=\"" + n.Event + "\""
 	}
 
-	graph := &dotGraph{Name: "OWASP Amass Network Mapping"}
+	_, err := fmt.Fprintf(d.bufwr, "        node [label=\"%s\",color=\"%s\",type=\"%s\",source=\"%s\"%s]; n%d;\n",
+		n.Label, color, n.Type, n.Source, event, n.ID+1)
+	return err
+}
 
-	for idx, node := range nodes {
-		graph.Nodes = append(graph.Nodes, dotNode{
-			ID:     strconv.Itoa(idx + 1),
-			Label:  node.Label,
-			Color:  colors[node.Type],
-			Type:   node.Type,
-			Source: node.Source,
-		})
+// WriteEdge implements the Writer interface.
+func (d *dotWriter) WriteEdge(e *Edge) error {
+	d.start()
+	if !d.edges {
+		d.edges = true
+		d.bufwr.WriteString("\n")
 	}
 
-	for _, edge := range edges {
-		graph.Edges = append(graph.Edges, dotEdge{
-			Source:      strconv.Itoa(edge.From + 1),
-			Destination: strconv.Itoa(edge.To + 1),
-			Label:       edge.Title,
-		})
+	_, err := fmt.Fprintf(d.bufwr, "        n%d -> n%d [label=\"%s\"];\n", e.From+1, e.To+1, e.Title)
+	return err
+}
+
+// Close implements the Writer interface.
+func (d *dotWriter) Close() error {
+	d.start()
+
+	if _, err := d.bufwr.WriteString("}\n"); err != nil {
+		return err
 	}
+	return d.bufwr.Flush()
+}
 
-	t := template.Must(template.New("graph").Parse(dotTemplate))
-	t.Execute(output, graph)
+// WriteDOTData generates a DOT file to display the Amass graph.
+func WriteDOTData(output io.Writer, nodes []Node, edges []Edge) {
+	_ = writeAll(NewDOTWriter(output, nodeEvents(nodes)), nodes, edges)
 }
@@ -12,12 +12,15 @@ import (
 )
 
 const (
-	xmlNS    string = "http://www.gephi.org/gexf"
-	xmlNSVIZ string = "http://www.gephi.org/gexf/viz"
+	xmlNS    string = "http://gexf.net/1.3"
+	xmlNSVIZ string = "http://gexf.net/1.3/viz"
 
 	classNode string = "node"
 
-	modeStatic string = "static"
+	// The nodes are present from the start of the first enumeration that discovered them until
+	// the finish of the last one, which Gephi presents using the timeline
+	modeDynamic    string = "dynamic"
+	timeFormatDate string = "dateTime"
 
 	edgeTypeDirected string = "directed"
 )
@@ -39,10 +42,6 @@ type gexfAttributes struct {
 	Attrs []gexfAttribute `xml:"attribute"`
 }
 
-type gexfParent struct {
-	For string `xml:"for,attr"`
-}
-
 type gexfColor struct {
 	R uint8 `xml:"r,attr"`
 	G uint8 `xml:"g,attr"`
@@ -50,21 +49,21 @@ type gexfColor struct {
 }
 
 type gexfNode struct {
-	ID      string          `xml:"id,attr"`
-	Label   string          `xml:"label,attr,omitempty"`
-	Attrs   []gexfAttrValue `xml:"attvalues>attvalue,omitempty"`
-	Parents []gexfParent    `xml:"parents>parent"`
-	Color   *gexfColor      `xml:"viz:color,omitempty"`
+	ID    string          `xml:"id,attr"`
+	Label string          `xml:"label,attr,omitempty"`
+	Start string          `xml:"start,attr,omitempty"`
+	End   string          `xml:"end,attr,omitempty"`
+	Attrs []gexfAttrValue `xml:"attvalues>attvalue,omitempty"`
+	Color *gexfColor      `xml:"viz:color,omitempty"`
 }
 
 type gexfEdge struct {
-	ID     string          `xml:"id,attr"`
-	Label  string          `xml:"label,attr,omitempty"`
-	Type   string          `xml:"type,attr,omitempty"`
-	Source string          `xml:"source,attr"`
-	Target string          `xml:"target,attr"`
-	Weight float64         `xml:"weight,attr,omitempty"`
-	Attrs  []gexfAttrValue `xml:"attvalues>attvalue,omitempty"`
+	ID     string  `xml:"id,attr"`
+	Label  string  `xml:"label,attr,omitempty"`
+	Type   string  `xml:"type,attr,omitempty"`
+	Source string  `xml:"source,attr"`
+	Target string  `xml:"target,attr"`
+	Weight float64 `xml:"weight,attr,omitempty"`
 }
 
 type gexfMeta struct {
@@ -74,22 +73,6 @@ type gexfMeta struct {
 	Desc         string `xml:"description"`
 }
 
-type gexfGraph struct {
-	Mode     string         `xml:"mode,attr,omitempty"`
-	EdgeType string         `xml:"defaultedgetype,attr,omitempty"`
-	Attrs    gexfAttributes `xml:"attributes,omitempty"`
-	Nodes    []gexfNode     `xml:"nodes>node,omitempty"`
-	Edges    []gexfEdge     `xml:"edges>edge,omitempty"`
-}
-
-type gexf struct {
-	XMLName xml.Name
-	Version string    `xml:"version,attr"`
-	Viz     string    `xml:"xmlns:viz,attr"`
-	Meta    gexfMeta  `xml:"meta"`
-	Graph   gexfGraph `xml:"graph"`
-}
-
 var (
 	gexfGreen  = &gexfColor{R: 34, G: 153, B: 84}
 	gexfRed    = &gexfColor{R: 242, G: 44, B: 13}
@@ -99,86 +82,186 @@ var (
 	gexfPurple = &gexfColor{R: 142, G: 68, B: 173}
 	gexfPink   = &gexfColor{R: 243, G: 26, B: 188}
 	gexfBlue   = &gexfColor{R: 26, G: 69, B: 243}
+
+	// Colors assigned to the events when the graph includes multiple enumerations
+	gexfEventColors = []*gexfColor{gexfGreen, gexfRed, gexfOrange,
+		gexfYellow, gexfCyan, gexfPurple, gexfPink, gexfBlue}
 )
 
-// WriteGEXFData generates a GEXF file to display the Amass graph using Gephi.
-func WriteGEXFData(output io.Writer, nodes []Node, edges []Edge) {
+type gexfWriter struct {
+	bufwr   *bufio.Writer
+	enc     *xml.Encoder
+	events  map[string]int
+	started bool
+	edges   bool
+	edgeID  int
+}
+
+// NewGEXFWriter returns a Writer that streams a GEXF file to display the Amass graph using Gephi. The
+// events are the enumerations included in the visualization, which color the nodes when there are several.
+func NewGEXFWriter(output io.Writer, events []string) Writer {
 	bufwr := bufio.NewWriter(output)
 
-	bufwr.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
-	bufwr.Flush()
+	enc := xml.NewEncoder(bufwr)
+	enc.Indent("  ", "    ")
+	return &gexfWriter{
+		bufwr:  bufwr,
+		enc:    enc,
+		events: eventIndices(events),
+	}
+}
+
+func startElement(name string, attrs ...string) xml.StartElement {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
 
-	doc := &gexf{
-		XMLName: xml.Name{
-			Space: xmlNS,
-			Local: "gexf",
-		},
-		Version: "1.3",
-		Viz:     xmlNSVIZ,
-		Meta: gexfMeta{
-			LastModified: time.Now().UTC().Format("2006-01-02"),
-			Creator:      "OWASP Amass - https://github.com/OWASP/Amass",
-			Desc:         "OWASP Amass Network Mapping",
+	for i := 0; i+1 < len(attrs); i += 2 {
+		start.Attr = append(start.Attr, xml.Attr{
+			Name:  xml.Name{Local: attrs[i]},
+			Value: attrs[i+1],
+		})
+	}
+	return start
+}
+
+func (g *gexfWriter) start() error {
+	if g.started {
+		return nil
+	}
+	g.started = true
+
+	g.bufwr.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	if err := g.enc.EncodeToken(startElement("gexf", "xmlns", xmlNS, "version", "1.3", "xmlns:viz", xmlNSVIZ)); err != nil {
+		return err
+	}
+
+	meta := gexfMeta{
+		LastModified: time.Now().UTC().Format("2006-01-02"),
+		Creator:      "OWASP Amass - https://github.com/OWASP/Amass",
+		Desc:         "OWASP Amass Network Mapping",
+	}
+	if err := g.enc.EncodeElement(meta, startElement("meta")); err != nil {
+		return err
+	}
+
+	if err := g.enc.EncodeToken(startElement("graph", "mode", modeDynamic,
+		"timeformat", timeFormatDate, "defaultedgetype", edgeTypeDirected)); err != nil {
+		return err
+	}
+
+	attrs := gexfAttributes{
+		Class: classNode,
+		Attrs: []gexfAttribute{
+			{ID: "0", Title: "Title", Type: "string"},
+			{ID: "1", Title: "Source", Type: "string"},
+			{ID: "2", Title: "Type", Type: "string"},
+			{ID: "3", Title: "Event", Type: "string"},
 		},
-		Graph: gexfGraph{
-			Mode:     modeStatic,
-			EdgeType: edgeTypeDirected,
-			Attrs: gexfAttributes{
-				Class: classNode,
-				Attrs: []gexfAttribute{
-					{ID: "0", Title: "Title", Type: "string"},
-					{ID: "1", Title: "Source", Type: "string"},
-					{ID: "2", Title: "Type", Type: "string"},
-				},
-			},
+	}
+	if err := g.enc.EncodeElement(attrs, startElement("attributes")); err != nil {
+		return err
+	}
+	return g.enc.EncodeToken(startElement("nodes"))
+}
+
+// startEdges closes the list of nodes before the first edge is written.
+func (g *gexfWriter) startEdges() error {
+	if err := g.start(); err != nil || g.edges {
+		return err
+	}
+	g.edges = true
+
+	if err := g.enc.EncodeToken(startElement("nodes").End()); err != nil {
+		return err
+	}
+	return g.enc.EncodeToken(startElement("edges"))
+}
+
+// WriteNode implements the Writer interface.
+func (g *gexfWriter) WriteNode(n *Node) error {
+	if err := g.start(); err != nil {
+		return err
+	}
+
+	var color *gexfColor
+	switch n.Type {
+	case "subdomain":
+		color = gexfGreen
+	case "domain":
+		color = gexfRed
+	case "address":
+		color = gexfOrange
+	case "ptr":
+		color = gexfYellow
+	case "ns":
+		color = gexfCyan
+	case "mx":
+		color = gexfPurple
+	case "netblock":
+		color = gexfPink
+	case "as":
+		color = gexfBlue
+	}
+	// Use the event colors instead of the node type colors
+	if g.events != nil {
+		color = gexfEventColors[g.events[n.Event]%len(gexfEventColors)]
+	}
+
+	return g.enc.EncodeElement(gexfNode{
+		ID:    strconv.Itoa(n.ID),
+		Label: n.Label,
+		Start: gexfTime(n.FirstSeen),
+		End:   gexfTime(n.LastSeen),
+		Attrs: []gexfAttrValue{
+			{For: "0", Value: n.Title},
+			{For: "1", Value: n.Source},
+			{For: "2", Value: n.Type},
+			{For: "3", Value: n.Event},
 		},
+		Color: color,
+	}, startElement("node"))
+}
+
+// WriteEdge implements the Writer interface.
+func (g *gexfWriter) WriteEdge(e *Edge) error {
+	if err := g.startEdges(); err != nil {
+		return err
 	}
 
-	for idx, n := range nodes {
-		var color *gexfColor
-
-		switch n.Type {
-		case "subdomain":
-			color = gexfGreen
-		case "domain":
-			color = gexfRed
-		case "address":
-			color = gexfOrange
-		case "ptr":
-			color = gexfYellow
-		case "ns":
-			color = gexfCyan
-		case "mx":
-			color = gexfPurple
-		case "netblock":
-			color = gexfPink
-		case "as":
-			color = gexfBlue
-		}
+	id := g.edgeID
+	g.edgeID++
+	return g.enc.EncodeElement(gexfEdge{
+		ID:     strconv.Itoa(id),
+		Label:  e.Title,
+		Source: strconv.Itoa(e.From),
+		Target: strconv.Itoa(e.To),
+	}, startElement("edge"))
+}
 
-		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{
-			ID:    strconv.Itoa(idx),
-			Label: n.Label,
-			Attrs: []gexfAttrValue{
-				{For: "0", Value: n.Title},
-				{For: "1", Value: n.Source},
-				{For: "2", Value: n.Type},
-			},
-			Color: color,
-		})
+func gexfTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
 	}
+	return t.UTC().Format(time.RFC3339)
+}
 
-	for idx, e := range edges {
-		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
-			ID:     strconv.Itoa(idx),
-			Label:  e.Label,
-			Source: strconv.Itoa(e.From),
-			Target: strconv.Itoa(e.To),
-		})
+// Close implements the Writer interface.
+func (g *gexfWriter) Close() error {
+	if err := g.startEdges(); err != nil {
+		return err
 	}
 
-	enc := xml.NewEncoder(bufwr)
-	enc.Indent("  ", "    ")
-	enc.Encode(doc)
-	bufwr.Flush()
+	for _, name := range []string{"edges", "graph", "gexf"} {
+		if err := g.enc.EncodeToken(startElement(name).End()); err != nil {
+			return err
+		}
+	}
+	if err := g.enc.Flush(); err != nil {
+		return err
+	}
+	return g.bufwr.Flush()
+}
+
+// WriteGEXFData generates a GEXF file to display the Amass graph using Gephi.
+func WriteGEXFData(output io.Writer, nodes []Node, edges []Edge) {
+	_ = writeAll(NewGEXFWriter(output, nodeEvents(nodes)), nodes, edges)
 }
@@ -4,7 +4,9 @@
 package viz
 
 import (
+	"bufio"
 	"encoding/json"
+	"fmt"
 	"io"
 	"strconv"
 	"time"
@@ -29,59 +31,134 @@ type graphistryNodes struct {
 	Color  int    `json:"pointColor"`
 	Type   string `json:"type"`
 	Source string `json:"source"`
+	Event  string `json:"event"`
 }
 
-type graphistryREST struct {
-	Name     string             `json:"name"`
-	Type     string             `json:"type"`
-	Bindings graphistryBindings `json:"bindings"`
-	Edges    []graphistryEdges  `json:"graph"`
-	Nodes    []graphistryNodes  `json:"labels"`
+type graphistryWriter struct {
+	bufwr   *bufio.Writer
+	events  map[string]int
+	started bool
+	edges   bool
+	count   int
 }
 
-// WriteGraphistryData generates a JSON file to display the Amass graph using Graphistry.
-func WriteGraphistryData(output io.Writer, nodes []Node, edges []Edge) {
-	colors := map[string]int{
-		"subdomain": 3,
-		"domain":    5,
-		"address":   7,
-		"ptr":       10,
-		"ns":        0,
-		"mx":        9,
-		"netblock":  4,
-		"as":        1,
+var graphistryColors = map[string]int{
+	"subdomain": 3,
+	"domain":    5,
+	"address":   7,
+	"ptr":       10,
+	"ns":        0,
+	"mx":        9,
+	"netblock":  4,
+	"as":        1,
+}
+
+// NewGraphistryWriter returns a Writer that streams a JSON file to display the Amass graph using Graphistry.
+// The events are the enumerations included in the visualization, which color the nodes when there are several.
+func NewGraphistryWriter(output io.Writer, events []string) Writer {
+	return &graphistryWriter{
+		bufwr:  bufio.NewWriter(output),
+		events: eventIndices(events),
 	}
-	name := "OWASP_Amass_" + time.Now().Format("Jan_2_2006_15_04_05")
-	restJSON := &graphistryREST{
-		Name: name,
-		Type: "edgelist",
-		Bindings: graphistryBindings{
-			SourceField:      "src",
-			DestinationField: "dst",
-			IDField:          "node",
-		},
+}
+
+func (g *graphistryWriter) start() error {
+	if g.started {
+		return nil
 	}
+	g.started = true
 
-	for idx, node := range nodes {
-		restJSON.Nodes = append(restJSON.Nodes, graphistryNodes{
-			NodeID: strconv.Itoa(idx),
-			Label:  node.Label,
-			Title:  node.Title,
-			Color:  colors[node.Type],
-			Type:   node.Type,
-			Source: node.Source,
-		})
+	name, _ := json.Marshal("OWASP_Amass_" + time.Now().Format("Jan_2_2006_15_04_05"))
+	bindings, err := json.Marshal(&graphistryBindings{
+		SourceField:      "src",
+		DestinationField: "dst",
+		IDField:          "node",
+	})
+	if err != nil {
+		return err
 	}
 
-	for _, edge := range edges {
-		restJSON.Edges = append(restJSON.Edges, graphistryEdges{
-			Source:      strconv.Itoa(edge.From),
-			Destination: strconv.Itoa(edge.To),
-			Title:       edge.Title,
-		})
+	_, err = fmt.Fprintf(g.bufwr, "{\n  \"name\": %s,\n  \"type\": \"edgelist\",\n  \"bindings\": %s,\n  \"labels\": [", name, bindings)
+	return err
+}
+
+// startEdges closes the list of nodes before the first edge is written.
+func (g *graphistryWriter) startEdges() error {
+	if err := g.start(); err != nil || g.edges {
+		return err
 	}
+	g.edges = true
+	g.count = 0
 
-	enc := json.NewEncoder(output)
-	enc.SetIndent("", "  ")
-	enc.Encode(restJSON)
+	_, err := g.bufwr.WriteString("\n  ],\n  \"graph\": [")
+	return err
+}
+
+func (g *graphistryWriter) writeElement(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	sep := ","
+	if g.count == 0 {
+		sep = ""
+	}
+	g.count++
+
+	_, err = fmt.Fprintf(g.bufwr, "%s\n    %s", sep, b)
+	return err
+}
+
+// WriteNode implements the Writer interface.
+func (g *graphistryWriter) WriteNode(n *Node) error {
+	if err := g.start(); err != nil {
+		return err
+	}
+
+	color := graphistryColors[n.Type]
+	// The default Graphistry palette provides twelve categorical colors
+	if g.events != nil {
+		color = g.events[n.Event] % 12
+	}
+
+	return g.writeElement(&graphistryNodes{
+		NodeID: strconv.Itoa(n.ID),
+		Label:  n.Label,
+		Title:  n.Title,
+		Color:  color,
+		Type:   n.Type,
+		Source: n.Source,
+		Event:  n.Event,
+	})
+}
+
+// WriteEdge implements the Writer interface.
+func (g *graphistryWriter) WriteEdge(e *Edge) error {
+	if err := g.startEdges(); err != nil {
+		return err
+	}
+
+	return g.writeElement(&graphistryEdges{
+		Source:      strconv.Itoa(e.From),
+		Destination: strconv.Itoa(e.To),
+		Title:       e.Title,
+	})
+}
+
+// Close implements the Writer interface.
+func (g *graphistryWriter) Close() error {
+	if err := g.startEdges(); err != nil {
+		return err
+	}
+
+	if _, err := g.bufwr.WriteString("\n  ]\n}\n"); err != nil {
+		return err
+	}
+	return g.bufwr.Flush()
+}
+
+// WriteGraphistryData generates a JSON file to display the Amass graph using Graphistry.
+func WriteGraphistryData(output io.Writer, nodes []Node, edges []Edge) {
+	_ = writeAll(NewGraphistryWriter(output, nodeEvents(nodes)), nodes, edges)
 }
@@ -0,0 +1,181 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package viz
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+const graphMLNS string = "http://graphml.graphdrawing.org/xmlns"
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+var graphMLKeys = []graphMLKey{
+	{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+	{ID: "type", For: "node", AttrName: "type", AttrType: "string"},
+	{ID: "title", For: "node", AttrName: "title", AttrType: "string"},
+	{ID: "source", For: "node", AttrName: "source", AttrType: "string"},
+	{ID: "event", For: "node", AttrName: "event", AttrType: "string"},
+	{ID: "relation", For: "edge", AttrName: "relation", AttrType: "string"},
+}
+
+type graphMLWriter struct {
+	bufwr   *bufio.Writer
+	enc     *xml.Encoder
+	started bool
+	edgeID  int
+	sources map[string]string
+}
+
+// NewGraphMLWriter returns a Writer that streams a GraphML file that yEd, Gephi and other tools can import.
+// The data sources are included as nodes of the source type, with edges to the nodes they discovered.
+func NewGraphMLWriter(output io.Writer) Writer {
+	bufwr := bufio.NewWriter(output)
+
+	enc := xml.NewEncoder(bufwr)
+	enc.Indent("", "  ")
+	return &graphMLWriter{
+		bufwr:   bufwr,
+		enc:     enc,
+		sources: make(map[string]string),
+	}
+}
+
+func (g *graphMLWriter) start() error {
+	if g.started {
+		return nil
+	}
+	g.started = true
+
+	if _, err := g.bufwr.WriteString(xml.Header); err != nil {
+		return err
+	}
+	if err := g.enc.EncodeToken(startElement("graphml", "xmlns", graphMLNS)); err != nil {
+		return err
+	}
+	for _, key := range graphMLKeys {
+		if err := g.enc.EncodeElement(key, startElement("key")); err != nil {
+			return err
+		}
+	}
+	return g.enc.EncodeToken(startElement("graph", "id", "OWASP Amass Network Mapping", "edgedefault", "directed"))
+}
+
+func (g *graphMLWriter) writeEdge(from, to, relation string) error {
+	id := "e" + strconv.Itoa(g.edgeID)
+	g.edgeID++
+
+	return g.enc.EncodeElement(graphMLEdge{
+		ID:     id,
+		Source: from,
+		Target: to,
+		Data:   []graphMLData{{Key: "relation", Value: relation}},
+	}, startElement("edge"))
+}
+
+// WriteNode implements the Writer interface.
+func (g *graphMLWriter) WriteNode(n *Node) error {
+	if err := g.start(); err != nil {
+		return err
+	}
+
+	id := "n" + strconv.Itoa(n.ID)
+	if err := g.enc.EncodeElement(graphMLNode{
+		ID: id,
+		Data: []graphMLData{
+			{Key: "label", Value: n.Label},
+			{Key: "type", Value: n.Type},
+			{Key: "title", Value: n.Title},
+			{Key: "source", Value: n.Source},
+			{Key: "event", Value: n.Event},
+		},
+	}, startElement("node")); err != nil {
+		return err
+	}
+
+	if n.Source == "" {
+		return nil
+	}
+	// Each data source becomes a node with edges to the findings it provided
+	src, found := g.sources[n.Source]
+	if !found {
+		src = "s" + strconv.Itoa(len(g.sources))
+		g.sources[n.Source] = src
+
+		if err := g.enc.EncodeElement(graphMLNode{
+			ID: src,
+			Data: []graphMLData{
+				{Key: "label", Value: n.Source},
+				{Key: "type", Value: "source"},
+				{Key: "title", Value: "source: " + n.Source},
+			},
+		}, startElement("node")); err != nil {
+			return err
+		}
+	}
+	return g.writeEdge(src, id, "discovered")
+}
+
+// WriteEdge implements the Writer interface.
+func (g *graphMLWriter) WriteEdge(e *Edge) error {
+	if err := g.start(); err != nil {
+		return err
+	}
+
+	label := e.Label
+	if label == "" {
+		label = e.Title
+	}
+	return g.writeEdge("n"+strconv.Itoa(e.From), "n"+strconv.Itoa(e.To), label)
+}
+
+// Close implements the Writer interface.
+func (g *graphMLWriter) Close() error {
+	if err := g.start(); err != nil {
+		return err
+	}
+
+	for _, name := range []string{"graph", "graphml"} {
+		if err := g.enc.EncodeToken(startElement(name).End()); err != nil {
+			return err
+		}
+	}
+	if err := g.enc.Flush(); err != nil {
+		return err
+	}
+	if _, err := g.bufwr.WriteString("\n"); err != nil {
+		return err
+	}
+	return g.bufwr.Flush()
+}
+
+// WriteGraphMLData generates a GraphML file that yEd, Gephi and other tools can import.
+func WriteGraphMLData(output io.Writer, nodes []Node, edges []Edge) error {
+	return writeAll(NewGraphMLWriter(output), nodes, edges)
+}
@@ -0,0 +1,609 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package viz
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// The beginning of the interactive HTML page, up to the script holding the graph
+const htmlHeader = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="utf-8">
+    <title>OWASP Amass Network Mapping</title>
+    <script src="https://d3js.org/d3.v4.min.js"></script>
+    <style>
+        html, body {
+            margin: 0;
+            height: 100%;
+            overflow: hidden;
+            font-family: 'Open Sans', sans-serif;
+            font-size: 13px;
+        }
+        canvas#graph {
+            display: block;
+        }
+        div.panel {
+            position: absolute;
+            padding: 10px;
+            color: #000;
+            background-color: #fff;
+            border: 1px solid #999;
+            border-radius: 2px;
+            opacity: 0.9;
+            overflow-y: auto;
+        }
+        div#controls {
+            top: 10px;
+            left: 10px;
+            max-height: 80%;
+        }
+        div#details {
+            top: 10px;
+            right: 10px;
+            max-width: 400px;
+            max-height: 80%;
+            display: none;
+        }
+        div#tooltip {
+            position: absolute;
+            padding: 5px;
+            background-color: #fff;
+            border: 1px solid #999;
+            border-radius: 2px;
+            pointer-events: none;
+            display: none;
+        }
+        input#search {
+            width: 240px;
+        }
+        span.swatch {
+            display: inline-block;
+            width: 10px;
+            height: 10px;
+            margin-right: 4px;
+            border: 1px solid #333;
+        }
+        div.neighbor {
+            cursor: pointer;
+            color: #06c;
+        }
+        h4 {
+            margin: 10px 0 4px 0;
+        }
+    </style>
+</head>
+<body>
+    <canvas id="graph"></canvas>
+    <div id="controls" class="panel">
+        <input id="search" type="text" placeholder="Search the names and addresses">
+        <div id="matches">Press enter to visit the matching nodes</div>
+        <h4>Node Types</h4>
+        <div id="types"></div>
+        <div id="eventFilters">
+            <h4>Enumerations</h4>
+            <div id="events"></div>
+        </div>
+        <h4>View</h4>
+        <button id="reset">Reset the view</button>
+    </div>
+    <div id="details" class="panel"></div>
+    <div id="tooltip"></div>
+
+<script>
+/* global d3 */
+
+`
+
+// The graph is drawn on a canvas, which remains responsive for graphs with many nodes
+const htmlFooter = `    ]
+};
+
+// D3.js is loaded from d3js.org, so the graph cannot be drawn without network access
+if (typeof d3 === "undefined") {
+    document.getElementById("controls").textContent =
+        "Failed to load D3.js from d3js.org, which is required for drawing the graph";
+    throw new Error("D3.js is not available");
+}
+
+var canvas = document.getElementById("graph"),
+    ctx = canvas.getContext("2d"),
+    width = window.innerWidth,
+    height = window.innerHeight,
+    transform = d3.zoomIdentity,
+    hiddenTypes = {},
+    hiddenEvents = {},
+    query = "",
+    matches = [],
+    matchIndex = -1,
+    selected = null,
+    hover = null,
+    shown = {nodes: [], links: []};
+
+var byID = {};
+graph.nodes.forEach(function(n) {
+    n.num = 0;
+    n.neighbors = [];
+    byID[n.id] = n;
+});
+graph.edges = graph.edges.filter(function(e) {
+    return byID[e.source] && byID[e.target];
+});
+graph.edges.forEach(function(e) {
+    var s = byID[e.source], t = byID[e.target];
+
+    s.num++;
+    t.num++;
+    s.neighbors.push(t);
+    t.neighbors.push(s);
+});
+
+var max = d3.max(graph.nodes, function(n) { return n.num; }) || 1;
+
+function radius(n) {
+    return 4 + (10 * n.num / max);
+}
+
+function endpoint(v) {
+    return typeof v === "object" ? v : byID[v];
+}
+
+function visible(n) {
+    return !hiddenTypes[n.type] && !hiddenEvents[n.event];
+}
+
+function dimmed(n) {
+    if (query !== "") {
+        return !n.matched;
+    }
+    if (selected) {
+        return n !== selected && selected.neighbors.indexOf(n) === -1;
+    }
+    return false;
+}
+
+var simulation = d3.forceSimulation()
+    .force("link", d3.forceLink().id(function(d) { return d.id; }).distance(40))
+    .force("charge", d3.forceManyBody().strength(-60).distanceMax(600))
+    .force("collide", d3.forceCollide().radius(function(n) { return radius(n) + 1; }))
+    .force("center", d3.forceCenter(width / 2, height / 2))
+    .on("tick", draw);
+
+var zoom = d3.zoom()
+    .scaleExtent([1 / 20, 10])
+    .on("zoom", function() {
+        transform = d3.event.transform;
+        draw();
+    });
+
+d3.select(canvas)
+    .call(d3.drag()
+        .container(canvas)
+        .subject(dragsubject)
+        .on("start", dragstarted)
+        .on("drag", dragged)
+        .on("end", dragended))
+    .call(zoom)
+    .on("dblclick.zoom", null)
+    .on("mousemove", mousemoved)
+    .on("mouseleave", function() {
+        hover = null;
+        tooltip();
+    })
+    .on("click", function() {
+        var p = d3.mouse(this);
+
+        select(findNode(p[0], p[1]) || null);
+    });
+
+function resize() {
+    width = window.innerWidth;
+    height = window.innerHeight;
+    canvas.width = width;
+    canvas.height = height;
+    simulation.force("center", d3.forceCenter(width / 2, height / 2));
+    draw();
+}
+window.addEventListener("resize", resize);
+
+function applyFilters() {
+    shown.nodes = graph.nodes.filter(visible);
+    shown.links = graph.edges.filter(function(e) {
+        return visible(endpoint(e.source)) && visible(endpoint(e.target));
+    });
+
+    simulation.nodes(shown.nodes);
+    simulation.force("link").links(shown.links);
+    simulation.alpha(0.3).restart();
+
+    if (selected && !visible(selected)) {
+        select(null);
+    }
+    search(query);
+}
+
+function draw() {
+    ctx.save();
+    ctx.clearRect(0, 0, width, height);
+    ctx.translate(transform.x, transform.y);
+    ctx.scale(transform.k, transform.k);
+
+    var labels = transform.k >= 2;
+
+    ctx.lineWidth = 1 / transform.k;
+    shown.links.forEach(function(e) {
+        var focus = selected && (e.source === selected || e.target === selected);
+
+        ctx.globalAlpha = (dimmed(e.source) || dimmed(e.target)) && !focus ? 0.15 : 1;
+        ctx.beginPath();
+        ctx.moveTo(e.source.x, e.source.y);
+        ctx.lineTo(e.target.x, e.target.y);
+        ctx.strokeStyle = focus ? "#333" : "#aaa";
+        ctx.stroke();
+
+        if (labels) {
+            drawEdgeLabel(e);
+        }
+    });
+
+    shown.nodes.forEach(function(n) {
+        var emphasized = n.matched || n === selected;
+
+        ctx.globalAlpha = dimmed(n) ? 0.15 : 1;
+        ctx.beginPath();
+        ctx.arc(n.x, n.y, radius(n), 0, 2 * Math.PI);
+        ctx.fillStyle = n.color;
+        ctx.fill();
+        ctx.lineWidth = (emphasized ? 3 : 1) / transform.k;
+        ctx.strokeStyle = emphasized ? "#000" : "#333";
+        ctx.stroke();
+
+        if (labels || emphasized) {
+            ctx.fillStyle = "#000";
+            ctx.font = (12 / transform.k) + "px sans-serif";
+            ctx.textAlign = "left";
+            ctx.fillText(n.label, n.x + radius(n) + 2 / transform.k, n.y + 4 / transform.k);
+        }
+    });
+
+    ctx.globalAlpha = 1;
+    ctx.restore();
+}
+
+function drawEdgeLabel(e) {
+    var dx = e.target.x - e.source.x,
+        dy = e.target.y - e.source.y;
+
+    ctx.save();
+    ctx.translate(e.source.x + dx / 2, e.source.y + dy / 2);
+    ctx.rotate(dx < 0 ? Math.atan2(dy, dx) - Math.PI : Math.atan2(dy, dx));
+    ctx.fillStyle = "#888";
+    ctx.font = (10 / transform.k) + "px sans-serif";
+    ctx.textAlign = "center";
+    ctx.fillText(e.label, 0, -2 / transform.k);
+    ctx.restore();
+}
+
+function findNode(x, y) {
+    var i, n, dx, dy, r,
+        gx = transform.invertX(x),
+        gy = transform.invertY(y);
+
+    for (i = shown.nodes.length - 1; i >= 0; --i) {
+        n = shown.nodes[i];
+        dx = gx - n.x;
+        dy = gy - n.y;
+        r = radius(n) + 2 / transform.k;
+
+        if (dx * dx + dy * dy < r * r) {
+            return n;
+        }
+    }
+}
+
+function mousemoved() {
+    var p = d3.mouse(this);
+
+    hover = findNode(p[0], p[1]);
+    tooltip(p);
+}
+
+function tooltip(p) {
+    var tip = d3.select("#tooltip");
+
+    if (!hover) {
+        tip.style("display", "none");
+        return;
+    }
+    tip.style("display", "block")
+        .style("left", (p[0] + 10) + "px")
+        .style("top", (p[1] + 10) + "px")
+        .text(hover.title);
+}
+
+function dragsubject() {
+    var n = findNode(d3.event.x, d3.event.y);
+
+    if (!n) {
+        return null;
+    }
+    return {node: n, x: transform.applyX(n.x), y: transform.applyY(n.y)};
+}
+
+function dragstarted() {
+    var n = d3.event.subject.node;
+
+    if (!d3.event.active) simulation.alphaTarget(0.3).restart();
+    n.fx = n.x;
+    n.fy = n.y;
+}
+
+function dragged() {
+    var n = d3.event.subject.node;
+
+    n.fx = transform.invertX(d3.event.x);
+    n.fy = transform.invertY(d3.event.y);
+}
+
+function dragended() {
+    var n = d3.event.subject.node;
+
+    if (!d3.event.active) simulation.alphaTarget(0);
+    n.fx = null;
+    n.fy = null;
+}
+
+function select(n) {
+    var details = d3.select("#details");
+
+    selected = n;
+    details.selectAll("*").remove();
+    if (!n) {
+        details.style("display", "none");
+        draw();
+        return;
+    }
+
+    details.style("display", "block");
+    details.append("h4").text(n.label);
+    details.append("div").text("Type: " + n.type);
+    if (n.source) {
+        details.append("div").text("Source: " + n.source);
+    }
+    if (n.title !== n.type + ": " + n.label) {
+        details.append("div").text(n.title);
+    }
+    if (graph.multipleEvents) {
+        details.append("div").text("Event: " + n.event);
+    }
+
+    details.append("h4").text("Connected Nodes (" + n.neighbors.length + ")");
+    n.neighbors.slice().sort(function(a, b) {
+        return a.label < b.label ? -1 : a.label > b.label ? 1 : 0;
+    }).forEach(function(m) {
+        details.append("div")
+            .attr("class", visible(m) ? "neighbor" : null)
+            .text(m.type + ": " + m.label)
+            .on("click", function() {
+                if (visible(m)) {
+                    focus(m);
+                }
+            });
+    });
+    draw();
+}
+
+function focus(n) {
+    var k = Math.max(transform.k, 2);
+
+    select(n);
+    d3.select(canvas).transition().duration(750).call(zoom.transform,
+        d3.zoomIdentity.translate(width / 2, height / 2).scale(k).translate(-n.x, -n.y));
+}
+
+function search(value) {
+    query = value.trim().toLowerCase();
+    matches = [];
+    matchIndex = -1;
+
+    graph.nodes.forEach(function(n) {
+        n.matched = query !== "" && visible(n) &&
+            (n.label.toLowerCase().indexOf(query) !== -1 || n.title.toLowerCase().indexOf(query) !== -1);
+        if (n.matched) {
+            matches.push(n);
+        }
+    });
+
+    d3.select("#matches").text(query === "" ?
+        "Press enter to visit the matching nodes" : matches.length + " matching nodes");
+    draw();
+}
+
+d3.select("#search")
+    .on("input", function() {
+        search(this.value);
+    })
+    .on("keydown", function() {
+        if (d3.event.keyCode !== 13 || matches.length === 0) {
+            return;
+        }
+
+        matchIndex = (matchIndex + 1) % matches.length;
+        focus(matches[matchIndex]);
+        d3.select("#matches").text((matchIndex + 1) + " of " + matches.length + " matching nodes");
+    });
+
+d3.select("#reset").on("click", function() {
+    document.getElementById("search").value = "";
+    search("");
+    select(null);
+    d3.select(canvas).transition().duration(750).call(zoom.transform, d3.zoomIdentity);
+});
+
+// Adds a checkbox for each distinct value of the node field, which hides the nodes when cleared
+function addFilters(container, field, hidden, swatches) {
+    var counts = {}, colors = {};
+
+    graph.nodes.forEach(function(n) {
+        counts[n[field]] = (counts[n[field]] || 0) + 1;
+        colors[n[field]] = colors[n[field]] || n.color;
+    });
+
+    Object.keys(counts).sort().forEach(function(value) {
+        var label = d3.select(container).append("div").append("label");
+
+        label.append("input")
+            .attr("type", "checkbox")
+            .property("checked", true)
+            .on("change", function() {
+                hidden[value] = !this.checked;
+                applyFilters();
+            });
+        if (swatches) {
+            label.append("span")
+                .attr("class", "swatch")
+                .style("background-color", colors[value]);
+        }
+        label.append("span").text(value + " (" + counts[value] + ")");
+    });
+}
+
+addFilters("#types", "type", hiddenTypes, !graph.multipleEvents);
+if (graph.multipleEvents) {
+    addFilters("#events", "event", hiddenEvents, true);
+} else {
+    d3.select("#eventFilters").style("display", "none");
+}
+
+resize();
+applyFilters();
+
+</script>
+</body>
+</html>
+`
+
+type htmlNode struct {
+	ID     int    `json:"id"`
+	Type   string `json:"type"`
+	Label  string `json:"label"`
+	Title  string `json:"title"`
+	Source string `json:"source"`
+	Event  string `json:"event"`
+	Color  string `json:"color"`
+}
+
+type htmlEdge struct {
+	Source int    `json:"source"`
+	Target int    `json:"target"`
+	Label  string `json:"label"`
+}
+
+type htmlWriter struct {
+	bufwr   *bufio.Writer
+	events  map[string]int
+	started bool
+	edges   bool
+}
+
+// NewHTMLWriter returns a Writer that streams a single HTML file for exploring the Amass graph, with a
+// search box, node type filters and zooming. The page loads D3.js from d3js.org when opened. The events are
+// the enumerations included in the visualization, which color the nodes and can be filtered when there are several.
+func NewHTMLWriter(output io.Writer, events []string) Writer {
+	return &htmlWriter{
+		bufwr:  bufio.NewWriter(output),
+		events: eventIndices(events),
+	}
+}
+
+func (h *htmlWriter) start() error {
+	if h.started {
+		return nil
+	}
+	h.started = true
+
+	if _, err := h.bufwr.WriteString(htmlHeader); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(h.bufwr, "var graph = {\n    multipleEvents: %t,\n    nodes: [\n", h.events != nil)
+	return err
+}
+
+// startEdges closes the list of nodes before the first edge is written.
+func (h *htmlWriter) startEdges() error {
+	if err := h.start(); err != nil || h.edges {
+		return err
+	}
+	h.edges = true
+
+	_, err := h.bufwr.WriteString("    ],\n    edges: [\n")
+	return err
+}
+
+// The JSON encoding escapes the characters that would end the script element early
+func (h *htmlWriter) writeElement(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(h.bufwr, "        %s,\n", b)
+	return err
+}
+
+// WriteNode implements the Writer interface.
+func (h *htmlWriter) WriteNode(n *Node) error {
+	if err := h.start(); err != nil {
+		return err
+	}
+
+	color := d3Colors[n.Type]
+	if h.events != nil {
+		color = eventColorNames[h.events[n.Event]%len(eventColorNames)]
+	}
+
+	return h.writeElement(&htmlNode{
+		ID:     n.ID,
+		Type:   n.Type,
+		Label:  n.Label,
+		Title:  n.Title,
+		Source: n.Source,
+		Event:  n.Event,
+		Color:  color,
+	})
+}
+
+// WriteEdge implements the Writer interface.
+func (h *htmlWriter) WriteEdge(e *Edge) error {
+	if err := h.startEdges(); err != nil {
+		return err
+	}
+
+	return h.writeElement(&htmlEdge{
+		Source: e.From,
+		Target: e.To,
+		Label:  e.Title,
+	})
+}
+
+// Close implements the Writer interface.
+func (h *htmlWriter) Close() error {
+	if err := h.startEdges(); err != nil {
+		return err
+	}
+
+	if _, err := h.bufwr.WriteString(htmlFooter); err != nil {
+		return err
+	}
+	return h.bufwr.Flush()
+}
+
+// WriteHTMLData generates a HTML file for exploring the Amass graph interactively, which loads D3.js when opened.
+func WriteHTMLData(output io.Writer, nodes []Node, edges []Edge) {
+	_ = writeAll(NewHTMLWriter(output, nodeEvents(nodes)), nodes, edges)
+}
@@ -4,147 +4,320 @@
 package viz
 
 import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"net"
+	"strconv"
 	"strings"
+	"time"
 
 	amassnet "github.com/OWASP/Amass/v3/net"
 )
 
-// WriteMaltegoData converts the Amass graph nodes and edges into a
-// structured table format (CSV) that can be input by Maltego.
-func WriteMaltegoData(output io.Writer, nodes []Node, edges []Edge) {
-	filter := make(map[int]struct{})
-	types := []string{
-		"maltego.Domain",
-		"maltego.DNSName",
-		"maltego.NSRecord",
-		"maltego.MXRecord",
-		"maltego.IPv4Address",
-		"maltego.Netblock",
-		"maltego.AS",
-		"maltego.Company",
-		"maltego.DNSName",
-	}
-	// Print the column types in the first row
-	fmt.Fprintln(output, strings.Join(types, ","))
-	// Start the graph tranersal from the autonomous systems
-	for idx, node := range nodes {
-		if node.Type == "as" {
-			traverseTree(output, idx, nodes, edges, filter)
+const (
+	maltegoNS  string = "http://maltego.paterva.com/xml/mtgx"
+	maltegoKey string = "MaltegoEntity"
+	linkKey    string = "MaltegoLink"
+
+	// The type of the links, which are labeled with the DNS record type or relationship
+	maltegoLinkType string = "maltego.link.manual-link"
+)
+
+// The Maltego entity types, and the properties holding their values, for the Amass node types
+var maltegoEntities = map[string]maltegoEntityType{
+	"domain":    {Type: "maltego.Domain", Property: "fqdn", DisplayName: "Domain Name"},
+	"subdomain": {Type: "maltego.DNSName", Property: "fqdn", DisplayName: "DNS Name"},
+	"ptr":       {Type: "maltego.DNSName", Property: "fqdn", DisplayName: "DNS Name"},
+	"ns":        {Type: "maltego.NSRecord", Property: "fqdn", DisplayName: "NS Record"},
+	"mx":        {Type: "maltego.MXRecord", Property: "fqdn", DisplayName: "MX Record"},
+	"address":   {Type: "maltego.IPv4Address", Property: "ipv4-address", DisplayName: "IP Address"},
+	"address6":  {Type: "maltego.IPv6Address", Property: "ipv6-address", DisplayName: "IPv6 Address"},
+	"netblock":  {Type: "maltego.Netblock", Property: "ipv4-range", DisplayName: "IP Range"},
+	"as":        {Type: "maltego.AS", Property: "as.number", DisplayName: "AS Number", ValueType: "int"},
+	"company":   {Type: "maltego.Company", Property: "title", DisplayName: "Name"},
+}
+
+type maltegoEntityType struct {
+	Type        string
+	Property    string
+	DisplayName string
+	ValueType   string
+}
+
+type maltegoProperty struct {
+	Name        string `xml:"name,attr"`
+	DisplayName string `xml:"displayName,attr"`
+	Type        string `xml:"type,attr"`
+	Hidden      bool   `xml:"hidden,attr"`
+	Nullable    bool   `xml:"nullable,attr"`
+	ReadOnly    bool   `xml:"readonly,attr"`
+	Value       string `xml:"mtg:Value"`
+}
+
+type maltegoEntity struct {
+	NS         string            `xml:"xmlns:mtg,attr"`
+	Type       string            `xml:"type,attr"`
+	Properties []maltegoProperty `xml:"mtg:Properties>mtg:Property"`
+}
+
+type maltegoData struct {
+	Key    string         `xml:"key,attr"`
+	Entity *maltegoEntity `xml:"mtg:MaltegoEntity,omitempty"`
+	Link   *maltegoEntity `xml:"mtg:MaltegoLink,omitempty"`
+}
+
+type maltegoNode struct {
+	ID   string      `xml:"id,attr"`
+	Data maltegoData `xml:"data"`
+}
+
+type maltegoEdge struct {
+	ID     string      `xml:"id,attr"`
+	Source string      `xml:"source,attr"`
+	Target string      `xml:"target,attr"`
+	Data   maltegoData `xml:"data"`
+}
+
+type maltegoWriter struct {
+	bufwr   *bufio.Writer
+	zipwr   *zip.Writer
+	enc     *xml.Encoder
+	started bool
+	edges   bool
+	edgeID  int
+	// The autonomous systems linked to the companies announcing them once the edges are written
+	companies []int
+}
+
+// NewMaltegoWriter returns a Writer that streams a Maltego graph (MTGX) file, which can be opened by Maltego.
+// The nodes are provided as the Maltego entity types used by the transforms, such as maltego.DNSName and
+// maltego.Netblock, along with properties for the data sources and the enumerations that discovered them.
+func NewMaltegoWriter(output io.Writer) Writer {
+	bufwr := bufio.NewWriter(output)
+
+	return &maltegoWriter{
+		bufwr: bufwr,
+		zipwr: zip.NewWriter(bufwr),
+	}
+}
+
+func (m *maltegoWriter) start() error {
+	if m.started {
+		return nil
+	}
+	m.started = true
+
+	vw, err := m.zipwr.Create("version.properties")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(vw, "maltego.graph.version=1.2\n"); err != nil {
+		return err
+	}
+
+	gw, err := m.zipwr.Create("Graphs/Graph1.graphml")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(gw, xml.Header); err != nil {
+		return err
+	}
+
+	m.enc = xml.NewEncoder(gw)
+	m.enc.Indent("", "  ")
+	if err := m.enc.EncodeToken(startElement("graphml", "xmlns", graphMLNS)); err != nil {
+		return err
+	}
+	for _, key := range []xml.StartElement{
+		startElement("key", "attr.name", maltegoKey, "for", "node", "id", "d0"),
+		startElement("key", "attr.name", linkKey, "for", "edge", "id", "d1"),
+	} {
+		if err := m.enc.EncodeToken(key); err != nil {
+			return err
+		}
+		if err := m.enc.EncodeToken(key.End()); err != nil {
+			return err
 		}
 	}
+	return m.enc.EncodeToken(startElement("graph", "edgedefault", edgeTypeDirected, "id", "G"))
 }
 
-func checkFilter(id int, filter map[int]struct{}) bool {
-	if _, ok := filter[id]; ok {
-		return true
+// startEdges links the autonomous systems to their companies before the first edge is written.
+func (m *maltegoWriter) startEdges() error {
+	if err := m.start(); err != nil || m.edges {
+		return err
 	}
-	filter[id] = struct{}{}
-	return false
+	m.edges = true
+
+	for _, id := range m.companies {
+		if err := m.writeLink(maltegoNodeID(id), maltegoCompanyID(id), "announced_by"); err != nil {
+			return err
+		}
+	}
+	m.companies = nil
+	return nil
 }
 
-func typeToIndex(t string) int {
-	var idx int
+// WriteNode implements the Writer interface.
+func (m *maltegoWriter) WriteNode(n *Node) error {
+	if err := m.start(); err != nil {
+		return err
+	}
 
-	switch t {
-	case "domain":
-		idx = 0
-	case "subdomain":
-		idx = 1
-	case "ptr":
-		idx = 8
-	case "cname":
-		idx = 8
+	ntype, value := n.Type, n.Label
+	switch n.Type {
 	case "address":
-		idx = 4
-	case "ns":
-		idx = 2
-	case "mx":
-		idx = 3
+		if ip := net.ParseIP(n.Label); ip != nil && ip.To4() == nil {
+			ntype = "address6"
+		}
 	case "netblock":
-		idx = 5
-	case "as":
-		idx = 6
-	case "company":
-		idx = 7
+		value = cidrToMaltegoNetblock(n.Label)
 	}
-	return idx
-}
 
-func writeMaltegoTableLine(out io.Writer, data1, type1, data2, type2 string) {
-	row := []string{"", "", "", "", "", "", "", "", ""}
+	et, found := maltegoEntities[ntype]
+	if !found {
+		return nil
+	}
 
-	idx1 := typeToIndex(type1)
-	row[idx1] = data1
-	if type1 == "netblock" {
-		row[idx1] = cidrToMaltegoNetblock(data1)
+	props := []maltegoProperty{maltegoProp(et.Property, et.DisplayName, et.ValueType, value)}
+	if n.Type == "netblock" {
+		props = append(props, maltegoProp("amass.cidr", "CIDR", "", n.Label))
 	}
-	idx2 := typeToIndex(type2)
-	row[idx2] = data2
-	if type2 == "netblock" {
-		row[idx2] = cidrToMaltegoNetblock(data2)
+	if n.Source != "" {
+		props = append(props, maltegoProp("amass.source", "Data Source", "", n.Source))
+	}
+	if n.SourceTag != "" {
+		props = append(props, maltegoProp("amass.source.tag", "Data Source Type", "", n.SourceTag))
+	}
+	if n.Event != "" {
+		props = append(props, maltegoProp("amass.event", "Enumeration", "", n.Event))
+	}
+	if !n.FirstSeen.IsZero() {
+		props = append(props, maltegoProp("amass.first.seen", "First Seen", "", n.FirstSeen.UTC().Format(time.RFC3339)))
+	}
+	if !n.LastSeen.IsZero() {
+		props = append(props, maltegoProp("amass.last.seen", "Last Seen", "", n.LastSeen.UTC().Format(time.RFC3339)))
+	}
+
+	if err := m.writeEntity(maltegoNodeID(n.ID), et.Type, props); err != nil {
+		return err
 	}
-	fmt.Fprintln(out, strings.Join(row, ","))
-}
 
-func traverseTree(out io.Writer, id int, nodes []Node, edges []Edge, filter map[int]struct{}) {
-	d1 := nodes[id].Label
-	t1 := nodes[id].Type
+	// The company announcing the autonomous system is provided as a separate entity
+	if desc := asDescription(n); n.Type == "as" && desc != "" {
+		company := maltegoEntities["company"]
 
-	var from bool
-	if t1 == "netblock" || t1 == "as" {
-		from = true
+		if err := m.writeEntity(maltegoCompanyID(n.ID), company.Type, []maltegoProperty{
+			maltegoProp(company.Property, company.DisplayName, "", desc),
+		}); err != nil {
+			return err
+		}
+		m.companies = append(m.companies, n.ID)
 	}
+	return nil
+}
 
-	if checkFilter(id, filter) {
-		return
+// WriteEdge implements the Writer interface.
+func (m *maltegoWriter) WriteEdge(e *Edge) error {
+	if err := m.startEdges(); err != nil {
+		return err
 	}
-	// Print the line containing the AS company
-	if t1 == "as" {
-		parts := strings.Split(nodes[id].Title, ":")
-		company := strings.Replace(strings.TrimSpace(parts[2]), ",", "", -1)
-		writeMaltegoTableLine(out, d1, t1, company, "company")
+
+	return m.writeLink(maltegoNodeID(e.From), maltegoNodeID(e.To), e.Title)
+}
+
+// Close implements the Writer interface.
+func (m *maltegoWriter) Close() error {
+	if err := m.startEdges(); err != nil {
+		return err
 	}
 
-	for _, edge := range edges {
-		subFrom := from
-		n, found := selectNextEdge(id, from, edge)
-		if !found && (t1 == "subdomain" || t1 == "domain") {
-			subFrom = true
-			n, found = selectNextEdge(id, subFrom, edge)
+	for _, name := range []string{"graph", "graphml"} {
+		if err := m.enc.EncodeToken(startElement(name).End()); err != nil {
+			return err
 		}
-		if !found {
-			continue
-		}
-		d2 := nodes[n].Label
-		t2 := nodes[n].Type
-		// Need to properly handle CNAME records
-		if strings.Contains(edge.Title, "cname") {
-			if subFrom {
-				writeMaltegoTableLine(out, d1, "cname", d2, t2)
-			} else {
-				writeMaltegoTableLine(out, d1, t1, d2, "cname")
-			}
-		} else {
-			writeMaltegoTableLine(out, d1, t1, d2, t2)
-		}
-		traverseTree(out, n, nodes, edges, filter)
 	}
+	if err := m.enc.Flush(); err != nil {
+		return err
+	}
+	if err := m.zipwr.Close(); err != nil {
+		return err
+	}
+	return m.bufwr.Flush()
 }
 
-func selectNextEdge(id int, from bool, edge Edge) (int, bool) {
-	if from {
-		if edge.From == id {
-			return edge.To, true
-		}
-	} else {
-		if edge.To == id {
-			return edge.From, true
-		}
+func (m *maltegoWriter) writeEntity(id, etype string, props []maltegoProperty) error {
+	return m.enc.EncodeElement(maltegoNode{
+		ID: id,
+		Data: maltegoData{
+			Key: "d0",
+			Entity: &maltegoEntity{
+				NS:         maltegoNS,
+				Type:       etype,
+				Properties: props,
+			},
+		},
+	}, startElement("node"))
+}
+
+func (m *maltegoWriter) writeLink(from, to, label string) error {
+	id := m.edgeID
+	m.edgeID++
+
+	return m.enc.EncodeElement(maltegoEdge{
+		ID:     "e" + strconv.Itoa(id),
+		Source: from,
+		Target: to,
+		Data: maltegoData{
+			Key: "d1",
+			Link: &maltegoEntity{
+				NS:   maltegoNS,
+				Type: maltegoLinkType,
+				Properties: []maltegoProperty{
+					maltegoProp("maltego.link.manual.type", "Label", "", label),
+				},
+			},
+		},
+	}, startElement("edge"))
+}
+
+func maltegoProp(name, display, vtype, value string) maltegoProperty {
+	if vtype == "" {
+		vtype = "string"
+	}
+
+	return maltegoProperty{
+		Name:        name,
+		DisplayName: display,
+		Type:        vtype,
+		Nullable:    true,
+		Value:       value,
 	}
-	return 0, false
+}
+
+func maltegoNodeID(id int) string {
+	return "n" + strconv.Itoa(id)
+}
+
+func maltegoCompanyID(id int) string {
+	return "n" + strconv.Itoa(id) + "-company"
+}
+
+// asDescription returns the description of the autonomous system from the title of the node.
+func asDescription(n *Node) string {
+	parts := strings.SplitN(n.Title, "Desc:", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+	return strings.TrimSpace(parts[1])
+}
+
+// WriteMaltegoData generates a Maltego graph (MTGX) file from the Amass graph nodes and edges.
+func WriteMaltegoData(output io.Writer, nodes []Node, edges []Edge) {
+	_ = writeAll(NewMaltegoWriter(output), nodes, edges)
 }
 
 func cidrToMaltegoNetblock(cidr string) string {
@@ -154,5 +327,5 @@ func cidrToMaltegoNetblock(cidr string) string {
 	}
 
 	ip1, ip2 := amassnet.FirstLast(ipnet)
-	return ip1.String() + "-" + ip2.String()
+	return fmt.Sprintf("%s-%s", ip1, ip2)
 }
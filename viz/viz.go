@@ -3,6 +3,8 @@
 
 package viz
 
+import "time"
+
 // Edge represents an Amass graph edge throughout the viz package.
 type Edge struct {
 	From, To int
@@ -12,10 +14,118 @@ type Edge struct {
 
 // Node represents an Amass graph node throughout the viz package.
 type Node struct {
-	ID         int
-	Type       string
-	Label      string
-	Title      string
-	Source     string
+	ID     int
+	Type   string
+	Label  string
+	Title  string
+	Source string
+	// The tag of the data source, such as cert for names found in certificates
+	SourceTag  string
 	ActualType string
+	// The UUID of the enumeration that this node has been attributed to
+	Event string
+	// The earliest start and the latest finish of the included enumerations that discovered
+	// this node, which are zero when the enumerations have no dates
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// Color names assigned to the events when the visualization includes multiple enumerations.
+var eventColorNames = []string{
+	"green", "red", "orange", "yellow", "cyan", "purple", "pink", "blue", "brown", "gray",
+}
+
+// Writer streams a visualization to a file format. All the nodes are written before
+// the edges, and Close completes the output once the last edge has been written.
+type Writer interface {
+	WriteNode(n *Node) error
+	WriteEdge(e *Edge) error
+	Close() error
+}
+
+type multiWriter struct {
+	writers []Writer
+}
+
+// NewMultiWriter returns a Writer that duplicates the nodes and edges to all the writers provided.
+func NewMultiWriter(writers ...Writer) Writer {
+	return &multiWriter{writers: writers}
+}
+
+// WriteNode implements the Writer interface.
+func (m *multiWriter) WriteNode(n *Node) error {
+	for _, w := range m.writers {
+		if err := w.WriteNode(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteEdge implements the Writer interface.
+func (m *multiWriter) WriteEdge(e *Edge) error {
+	for _, w := range m.writers {
+		if err := w.WriteEdge(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements the Writer interface.
+func (m *multiWriter) Close() error {
+	var err error
+
+	for _, w := range m.writers {
+		if e := w.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// writeAll sends the nodes and edges already held in memory to the writer.
+func writeAll(w Writer, nodes []Node, edges []Edge) error {
+	for i := range nodes {
+		if err := w.WriteNode(&nodes[i]); err != nil {
+			return err
+		}
+	}
+	for i := range edges {
+		if err := w.WriteEdge(&edges[i]); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+// nodeEvents returns the distinct events in order of appearance within the nodes.
+func nodeEvents(nodes []Node) []string {
+	var events []string
+	seen := make(map[string]struct{})
+
+	for _, n := range nodes {
+		if _, found := seen[n.Event]; !found {
+			seen[n.Event] = struct{}{}
+			events = append(events, n.Event)
+		}
+	}
+	return events
+}
+
+// eventIndices returns the position of each event within the list. A nil map
+// is returned when the visualization only includes a single event.
+func eventIndices(events []string) map[string]int {
+	indices := make(map[string]int)
+
+	for _, e := range events {
+		if _, found := indices[e]; !found {
+			indices[e] = len(indices)
+		}
+	}
+
+	if len(indices) < 2 {
+		return nil
+	}
+	return indices
 }
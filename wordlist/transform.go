@@ -0,0 +1,110 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package wordlist
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Transforms selects the variations of the wordlist entries that are tried as labels, since
+// organizations join the words of their host names using different styles.
+type Transforms struct {
+	// Separators join the words of each entry, such as "-", "_" or the empty string
+	Separators []string
+
+	// Will entries such as "devApi" be split into words at the changes of case?
+	SplitCamelCase bool
+
+	// Years appended to the variations of each entry, directly and after each separator
+	Years []int
+}
+
+// Empty returns true when the transforms do not change the wordlist.
+func (t *Transforms) Empty() bool {
+	return t == nil || (len(t.Separators) == 0 && !t.SplitCamelCase && len(t.Years) == 0)
+}
+
+// Apply returns the variations of the words, without duplicates and in the order of the words.
+// The words are returned unchanged when the transforms are empty.
+func (t *Transforms) Apply(words []string) []string {
+	if t.Empty() {
+		return words
+	}
+
+	var results []string
+	seen := make(map[string]struct{})
+	add := func(label string) {
+		if label == "" {
+			return
+		}
+		if _, found := seen[label]; !found {
+			seen[label] = struct{}{}
+			results = append(results, label)
+		}
+	}
+
+	for _, word := range words {
+		for _, v := range t.variations(word) {
+			add(v)
+
+			for _, year := range t.Years {
+				y := strconv.Itoa(year)
+
+				add(v + y)
+				for _, sep := range t.Separators {
+					if sep != "" {
+						add(v + sep + y)
+					}
+				}
+			}
+		}
+	}
+	return results
+}
+
+// variations joins the words of the entry using each of the separators.
+func (t *Transforms) variations(word string) []string {
+	parts := t.split(word)
+	if len(parts) == 0 {
+		return nil
+	} else if len(parts) == 1 || len(t.Separators) == 0 {
+		return []string{strings.ToLower(strings.TrimSpace(word))}
+	}
+
+	var results []string
+	for _, sep := range t.Separators {
+		results = append(results, strings.Join(parts, sep))
+	}
+	return results
+}
+
+// split returns the lowercase words of the entry, separated by the characters that cannot be
+// part of a word, and by the changes of case when camel case is split.
+func (t *Transforms) split(word string) []string {
+	var parts []string
+	var cur []rune
+
+	flush := func() {
+		if len(cur) > 0 {
+			parts = append(parts, strings.ToLower(string(cur)))
+			cur = nil
+		}
+	}
+
+	runes := []rune(strings.TrimSpace(word))
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if t.SplitCamelCase && i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1]) {
+			flush()
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return parts
+}
@@ -0,0 +1,47 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package wordlist
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTransforms(t *testing.T) {
+	words := []string{"dev-api", "devApi", "mail", "Staging_Web"}
+
+	tests := []struct {
+		name       string
+		transforms *Transforms
+		expected   []string
+	}{
+		{"Test 1: No transforms", nil, words},
+		{
+			"Test 2: Separators",
+			&Transforms{Separators: []string{"-", "_", ""}},
+			[]string{"dev-api", "dev_api", "devapi", "mail", "staging-web", "staging_web", "stagingweb"},
+		},
+		{
+			"Test 3: Camel case",
+			&Transforms{Separators: []string{"-"}, SplitCamelCase: true},
+			[]string{"dev-api", "mail", "staging-web"},
+		},
+		{
+			"Test 4: Years",
+			&Transforms{Separators: []string{"-"}, Years: []int{2021}},
+			[]string{"dev-api", "dev-api2021", "dev-api-2021", "devapi", "devapi2021", "devapi-2021",
+				"mail", "mail2021", "mail-2021", "staging-web", "staging-web2021", "staging-web-2021"},
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.transforms.Apply(words); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+
+	if !(&Transforms{}).Empty() || (&Transforms{Years: []int{2020}}).Empty() {
+		t.Errorf("Empty did not identify the transforms that change the wordlist")
+	}
+}